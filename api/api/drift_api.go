@@ -0,0 +1,135 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// DriftController controls the drift monitoring API.
+type DriftController struct {
+	*AppContext
+}
+
+// SaveDriftReference registers the reference distributions of the features and the predictions of a model version.
+func (c *DriftController) SaveDriftReference(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	model, version, resp := c.findModelVersion(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	reference, ok := body.(*models.DriftReference)
+	if !ok {
+		return BadRequest("Unable to parse body as drift reference")
+	}
+
+	saved, err := c.DriftService.SaveReference(ctx, model, version, reference)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed saving drift reference of model %d version %d: %v", model.ID, version.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while saving drift reference of model %d version %d", model.ID, version.ID))
+	}
+
+	return Ok(saved)
+}
+
+// GetDriftReference returns the reference distributions of a model version.
+func (c *DriftController) GetDriftReference(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	model, version, resp := c.findModelVersion(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	reference, err := c.DriftService.GetReference(ctx, model.ID, version.ID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Drift reference of model %d version %d not found", model.ID, version.ID))
+		}
+		log.Errorf("failed getting drift reference of model %d version %d: %v", model.ID, version.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while getting drift reference of model %d version %d", model.ID, version.ID))
+	}
+
+	return Ok(reference)
+}
+
+// ListDriftResults lists the drift of a model version computed since the given time, or the latest one.
+func (c *DriftController) ListDriftResults(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	var since time.Time
+	if vars["since"] != "" {
+		parsed, err := time.Parse(time.RFC3339, vars["since"])
+		if err != nil {
+			return BadRequest(fmt.Sprintf("Invalid since %s, it must be in RFC3339 format", vars["since"]))
+		}
+		since = parsed
+	}
+
+	model, version, resp := c.findModelVersion(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	results, err := c.DriftService.ListResults(ctx, model.ID, version.ID, since)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Drift reference of model %d version %d not found", model.ID, version.ID))
+		}
+		log.Errorf("failed listing drift of model %d version %d: %v", model.ID, version.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while listing drift of model %d version %d", model.ID, version.ID))
+	}
+
+	return Ok(results)
+}
+
+func (c *DriftController) findModelVersion(r *http.Request, vars map[string]string) (*models.Model, *models.Version, *Response) {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+
+	model, err := c.ModelsService.FindByID(ctx, modelID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil, NotFound(fmt.Sprintf("Model with given `model_id: %d` not found", modelID))
+		}
+		return nil, nil, InternalServerError(fmt.Sprintf("Error getting model with given `model_id: %d`", modelID))
+	}
+
+	version, err := c.VersionsService.FindByID(ctx, modelID, versionID, c.MonitoringConfig)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil, NotFound(fmt.Sprintf("Version with given `version_id: %d` not found", versionID))
+		}
+		return nil, nil, InternalServerError(fmt.Sprintf("Error getting version with given `version_id: %d`", versionID))
+	}
+
+	return model, version, nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestSaveDriftReference(t *testing.T) {
+	model := &models.Model{ID: 1, Name: "model"}
+	version := &models.Version{ID: 2, ModelID: 1}
+	reference := &models.DriftReference{
+		Dataset: "gs://bucket/training.parquet",
+		Features: models.DriftFeatures{
+			{Name: "age", Source: models.DriftFeatureSourceFeature, Kind: models.DriftFeatureNumeric, Quantiles: []float64{0, 10, 20}},
+		},
+	}
+
+	testCases := []struct {
+		desc              string
+		errFindingVersion error
+		body              interface{}
+		errSaving         error
+		expected          *Response
+	}{
+		{
+			desc: "Should save drift reference",
+			body: reference,
+			expected: &Response{
+				code: http.StatusOK,
+				data: reference,
+			},
+		},
+		{
+			desc:              "Should return not found if version doesn't exist",
+			errFindingVersion: gorm.ErrRecordNotFound,
+			body:              reference,
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Version with given `version_id: 2` not found"},
+			},
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			body: &models.Model{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as drift reference"},
+			},
+		},
+		{
+			desc:      "Should return bad request if reference is invalid",
+			body:      reference,
+			errSaving: merror.NewInvalidInputError("invalid drift reference: at least one feature is required"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: invalid drift reference: at least one feature is required"},
+			},
+		},
+		{
+			desc:      "Should return internal server error if saving failed",
+			body:      reference,
+			errSaving: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while saving drift reference of model 1 version 2"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelsService := &mocks.ModelsService{}
+			modelsService.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+
+			versionsService := &mocks.VersionsService{}
+			versionsService.On("FindByID", mock.Anything, models.ID(1), models.ID(2), mock.Anything).Return(version, tC.errFindingVersion)
+
+			driftService := &mocks.DriftService{}
+			driftService.On("SaveReference", mock.Anything, model, version, reference).Return(reference, tC.errSaving)
+
+			ctl := &DriftController{
+				AppContext: &AppContext{
+					ModelsService:   modelsService,
+					VersionsService: versionsService,
+					DriftService:    driftService,
+				},
+			}
+			resp := ctl.SaveDriftReference(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestGetDriftReference(t *testing.T) {
+	model := &models.Model{ID: 1, Name: "model"}
+	version := &models.Version{ID: 2, ModelID: 1}
+	reference := &models.DriftReference{ID: 1, ModelID: 1, VersionID: 2}
+
+	testCases := []struct {
+		desc       string
+		errGetting error
+		expected   *Response
+	}{
+		{
+			desc: "Should return drift reference",
+			expected: &Response{
+				code: http.StatusOK,
+				data: reference,
+			},
+		},
+		{
+			desc:       "Should return not found if version has no reference",
+			errGetting: gorm.ErrRecordNotFound,
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Drift reference of model 1 version 2 not found"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelsService := &mocks.ModelsService{}
+			modelsService.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+
+			versionsService := &mocks.VersionsService{}
+			versionsService.On("FindByID", mock.Anything, models.ID(1), models.ID(2), mock.Anything).Return(version, nil)
+
+			driftService := &mocks.DriftService{}
+			driftService.On("GetReference", mock.Anything, models.ID(1), models.ID(2)).Return(reference, tC.errGetting)
+
+			ctl := &DriftController{
+				AppContext: &AppContext{
+					ModelsService:   modelsService,
+					VersionsService: versionsService,
+					DriftService:    driftService,
+				},
+			}
+			resp := ctl.GetDriftReference(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2"}, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestListDriftResults(t *testing.T) {
+	model := &models.Model{ID: 1, Name: "model"}
+	version := &models.Version{ID: 2, ModelID: 1}
+	psi := 0.3
+	results := []*models.DriftResult{{Feature: "age", Source: models.DriftFeatureSourceFeature, Observations: 100, PSI: &psi}}
+
+	testCases := []struct {
+		desc          string
+		since         string
+		expectedSince time.Time
+		expected      *Response
+	}{
+		{
+			desc: "Should return latest drift",
+			expected: &Response{
+				code: http.StatusOK,
+				data: results,
+			},
+		},
+		{
+			desc:          "Should return drift since the given time",
+			since:         "2023-01-02T00:00:00Z",
+			expectedSince: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			expected: &Response{
+				code: http.StatusOK,
+				data: results,
+			},
+		},
+		{
+			desc:  "Should return bad request if since is invalid",
+			since: "yesterday",
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Invalid since yesterday, it must be in RFC3339 format"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelsService := &mocks.ModelsService{}
+			modelsService.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+
+			versionsService := &mocks.VersionsService{}
+			versionsService.On("FindByID", mock.Anything, models.ID(1), models.ID(2), mock.Anything).Return(version, nil)
+
+			driftService := &mocks.DriftService{}
+			driftService.On("ListResults", mock.Anything, models.ID(1), models.ID(2), tC.expectedSince).Return(results, nil)
+
+			ctl := &DriftController{
+				AppContext: &AppContext{
+					ModelsService:   modelsService,
+					VersionsService: versionsService,
+					DriftService:    driftService,
+				},
+			}
+			resp := ctl.ListDriftResults(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2", "since": tC.since}, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	prommodel "github.com/prometheus/common/model"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// GroundTruthController controls the ground-truth labels and online performance API.
+type GroundTruthController struct {
+	*AppContext
+}
+
+// SubmitLabels stores the ground-truth labels of the predictions of a model.
+func (c *GroundTruthController) SubmitLabels(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	if _, err := c.ModelsService.FindByID(ctx, modelID); err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Model with given `model_id: %d` not found", modelID))
+		}
+		return InternalServerError(fmt.Sprintf("Error getting model with given `model_id: %d`", modelID))
+	}
+
+	labels, ok := body.(*models.PredictionLabels)
+	if !ok {
+		return BadRequest("Unable to parse body as prediction labels")
+	}
+
+	if err := c.GroundTruthService.SaveLabels(ctx, labels.Labels); err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed saving labels of model %d: %v", modelID, err)
+		return InternalServerError(fmt.Sprintf("Error while saving labels of model %d", modelID))
+	}
+
+	return Ok(labels)
+}
+
+// GetModelEndpointPerformance returns the online performance of the model versions serving a model endpoint over a sliding window.
+func (c *GroundTruthController) GetModelEndpointPerformance(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	var window time.Duration
+	if vars["window"] != "" {
+		parsed, err := prommodel.ParseDuration(vars["window"])
+		if err != nil || parsed <= 0 {
+			return BadRequest(fmt.Sprintf("Invalid window %s", vars["window"]))
+		}
+		window = time.Duration(parsed)
+	} else if len(c.GroundTruthConfig.Windows) > 0 {
+		// the first configured window is the default one
+		window = c.GroundTruthConfig.Windows[0]
+	} else {
+		return BadRequest("Window is required")
+	}
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	model, err := c.ModelsService.FindByID(ctx, modelID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Model with given `model_id: %d` not found", modelID))
+		}
+		return InternalServerError(fmt.Sprintf("Error getting model with given `model_id: %d`", modelID))
+	}
+
+	modelEndpointID, _ := models.ParseID(vars["model_endpoint_id"])
+	modelEndpoint, err := c.ModelEndpointsService.FindByID(ctx, modelEndpointID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Model endpoint with id %s not found", modelEndpointID))
+		}
+		return InternalServerError(fmt.Sprintf("Error while getting model endpoint with id %s", modelEndpointID))
+	}
+	if modelEndpoint.ModelID != modelID {
+		return NotFound(fmt.Sprintf("Model endpoint with id %s not found", modelEndpointID))
+	}
+
+	performances, err := c.GroundTruthService.GetPerformance(ctx, model, servedVersionIDs(modelEndpoint), window)
+	if err != nil {
+		log.Errorf("failed computing online performance of model endpoint %s: %v", modelEndpointID, err)
+		return InternalServerError(fmt.Sprintf("Error while computing online performance of model endpoint %s", modelEndpointID))
+	}
+
+	return Ok(performances)
+}
+
+// servedVersionIDs returns the ids of the model versions the traffic of the model endpoint is routed or mirrored to
+func servedVersionIDs(modelEndpoint *models.ModelEndpoint) []models.ID {
+	versionIDs := make([]models.ID, 0)
+	if modelEndpoint.Rule == nil {
+		return versionIDs
+	}
+
+	seen := map[models.ID]bool{}
+	add := func(versionEndpoint *models.VersionEndpoint) {
+		if versionEndpoint == nil || seen[versionEndpoint.VersionID] {
+			return
+		}
+		seen[versionEndpoint.VersionID] = true
+		versionIDs = append(versionIDs, versionEndpoint.VersionID)
+	}
+	for _, destination := range modelEndpoint.Rule.Destination {
+		add(destination.VersionEndpoint)
+	}
+	add(modelEndpoint.Rule.Mirror)
+	return versionIDs
+}
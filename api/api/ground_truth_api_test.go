@@ -0,0 +1,237 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestSubmitLabels(t *testing.T) {
+	labels := &models.PredictionLabels{
+		Labels: []*models.PredictionLabel{{PredictionID: "prediction-1", RowID: "1", Value: 1}},
+	}
+
+	testCases := []struct {
+		desc            string
+		errFindingModel error
+		body            interface{}
+		errSavingLabels error
+		expected        *Response
+	}{
+		{
+			desc: "Should save labels",
+			body: labels,
+			expected: &Response{
+				code: http.StatusOK,
+				data: labels,
+			},
+		},
+		{
+			desc:            "Should return not found if model doesn't exist",
+			errFindingModel: gorm.ErrRecordNotFound,
+			body:            labels,
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Model with given `model_id: 1` not found"},
+			},
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			body: &models.Model{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as prediction labels"},
+			},
+		},
+		{
+			desc:            "Should return bad request if a label is invalid",
+			body:            labels,
+			errSavingLabels: merror.NewInvalidInputError("invalid label 0: prediction_id is required"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: invalid label 0: prediction_id is required"},
+			},
+		},
+		{
+			desc:            "Should return internal server error if saving failed",
+			body:            labels,
+			errSavingLabels: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while saving labels of model 1"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelsService := &mocks.ModelsService{}
+			modelsService.On("FindByID", mock.Anything, models.ID(1)).Return(&models.Model{ID: 1, Name: "model"}, tC.errFindingModel)
+
+			groundTruthService := &mocks.GroundTruthService{}
+			groundTruthService.On("SaveLabels", mock.Anything, labels.Labels).Return(tC.errSavingLabels)
+
+			ctl := &GroundTruthController{
+				AppContext: &AppContext{
+					ModelsService:      modelsService,
+					GroundTruthService: groundTruthService,
+				},
+			}
+			resp := ctl.SubmitLabels(&http.Request{}, map[string]string{"model_id": "1"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestGetModelEndpointPerformance(t *testing.T) {
+	model := &models.Model{ID: 1, Name: "model"}
+	modelEndpoint := &models.ModelEndpoint{
+		ID:      1,
+		ModelID: 1,
+		Rule: &models.ModelEndpointRule{
+			Destination: []*models.ModelEndpointRuleDestination{
+				{VersionEndpoint: &models.VersionEndpoint{VersionID: 2}, Weight: 80},
+				{VersionEndpoint: &models.VersionEndpoint{VersionID: 3}, Weight: 20},
+			},
+			Mirror: &models.VersionEndpoint{VersionID: 4},
+		},
+	}
+	accuracy := 0.9
+	performances := []*models.OnlinePerformance{
+		{VersionID: 2, Window: "1h", Predictions: 10, LabeledPredictions: 10, Accuracy: &accuracy},
+		{VersionID: 3, Window: "1h"},
+		{VersionID: 4, Window: "1h"},
+	}
+
+	testCases := []struct {
+		desc                    string
+		vars                    map[string]string
+		modelEndpoint           *models.ModelEndpoint
+		errFindingModelEndpoint error
+		windows                 []time.Duration
+		window                  time.Duration
+		errGettingPerformance   error
+		expected                *Response
+	}{
+		{
+			desc:          "Should return the performance over the default window",
+			vars:          map[string]string{"model_id": "1", "model_endpoint_id": "1"},
+			modelEndpoint: modelEndpoint,
+			window:        time.Hour,
+			expected: &Response{
+				code: http.StatusOK,
+				data: performances,
+			},
+		},
+		{
+			desc:          "Should return the performance over the given window",
+			vars:          map[string]string{"model_id": "1", "model_endpoint_id": "1", "window": "1d"},
+			modelEndpoint: modelEndpoint,
+			window:        24 * time.Hour,
+			expected: &Response{
+				code: http.StatusOK,
+				data: performances,
+			},
+		},
+		{
+			desc: "Should return bad request if the window is invalid",
+			vars: map[string]string{"model_id": "1", "model_endpoint_id": "1", "window": "one hour"},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Invalid window one hour"},
+			},
+		},
+		{
+			desc:    "Should return bad request if the window isn't given and there's no default window",
+			vars:    map[string]string{"model_id": "1", "model_endpoint_id": "1"},
+			windows: []time.Duration{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Window is required"},
+			},
+		},
+		{
+			desc:                    "Should return not found if model endpoint doesn't exist",
+			vars:                    map[string]string{"model_id": "1", "model_endpoint_id": "1"},
+			errFindingModelEndpoint: gorm.ErrRecordNotFound,
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Model endpoint with id 1 not found"},
+			},
+		},
+		{
+			desc:          "Should return not found if model endpoint belongs to another model",
+			vars:          map[string]string{"model_id": "1", "model_endpoint_id": "1"},
+			modelEndpoint: &models.ModelEndpoint{ID: 1, ModelID: 2},
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Model endpoint with id 1 not found"},
+			},
+		},
+		{
+			desc:                  "Should return internal server error if the performance can't be computed",
+			vars:                  map[string]string{"model_id": "1", "model_endpoint_id": "1"},
+			modelEndpoint:         modelEndpoint,
+			window:                time.Hour,
+			errGettingPerformance: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while computing online performance of model endpoint 1"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelsService := &mocks.ModelsService{}
+			modelsService.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+
+			modelEndpointsService := &mocks.ModelEndpointsService{}
+			modelEndpointsService.On("FindByID", mock.Anything, models.ID(1)).Return(tC.modelEndpoint, tC.errFindingModelEndpoint)
+
+			groundTruthService := &mocks.GroundTruthService{}
+			groundTruthService.On("GetPerformance", mock.Anything, model, []models.ID{2, 3, 4}, tC.window).Return(performances, tC.errGettingPerformance)
+
+			windows := []time.Duration{time.Hour, 24 * time.Hour}
+			if tC.windows != nil {
+				windows = tC.windows
+			}
+
+			ctl := &GroundTruthController{
+				AppContext: &AppContext{
+					ModelsService:         modelsService,
+					ModelEndpointsService: modelEndpointsService,
+					GroundTruthService:    groundTruthService,
+					GroundTruthConfig: config.GroundTruthConfig{
+						GroundTruthEnabled: true,
+						Windows:            windows,
+					},
+				},
+			}
+			resp := ctl.GetModelEndpointPerformance(&http.Request{}, tC.vars, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/service"
+)
+
+// ImageBuildController controls the docker image builds of pyfunc model versions.
+type ImageBuildController struct {
+	*AppContext
+}
+
+// StartBuild starts building the docker image of a model version ahead of its deployment.
+func (c *ImageBuildController) StartBuild(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+
+	model, version, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			return InternalServerError(err.Error())
+		}
+		return NotFound(err.Error())
+	}
+
+	if model.Type != models.ModelTypePyFunc {
+		return BadRequest(fmt.Sprintf("Image build is only supported by %s model, model %s is %s model", models.ModelTypePyFunc, model.Name, model.Type))
+	}
+
+	// the image is built with the build config of the environment the version is going to be deployed to
+	environmentName := r.URL.Query().Get("environment_name")
+	if environmentName == "" {
+		env, err := c.EnvironmentService.GetDefaultEnvironment()
+		if err != nil {
+			return InternalServerError("Default environment not found, please specify one")
+		}
+		environmentName = env.Name
+	} else if _, err := c.EnvironmentService.GetEnvironment(environmentName); err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			log.Errorf("Unable to find the specified environment: %s. Err: %s", environmentName, err)
+			return InternalServerError(fmt.Sprintf("Unable to find the specified environment: %s", environmentName))
+		}
+		return NotFound(fmt.Sprintf("Environment not found: %s", environmentName))
+	}
+
+	build, err := c.ImageBuilder.StartBuild(ctx, model.Project, model, version, environmentName)
+	if err != nil {
+		log.Errorf("failed starting image build of model %s version %s: %v", model.Name, version.ID, err)
+		return InternalServerError(fmt.Sprintf("Failed starting image build: %s", err))
+	}
+
+	return Created(build)
+}
+
+// GetBuild gets the status of the docker image build of a model version.
+func (c *ImageBuildController) GetBuild(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+
+	model, version, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			return InternalServerError(err.Error())
+		}
+		return NotFound(err.Error())
+	}
+
+	if model.Type != models.ModelTypePyFunc {
+		return BadRequest(fmt.Sprintf("Image build is only supported by %s model, model %s is %s model", models.ModelTypePyFunc, model.Name, model.Type))
+	}
+
+	build, err := c.ImageBuilder.GetBuild(ctx, model.Project, model, version)
+	if err != nil {
+		log.Errorf("failed getting image build of model %s version %s: %v", model.Name, version.ID, err)
+		return InternalServerError(fmt.Sprintf("Failed getting image build: %s", err))
+	}
+
+	return Ok(build)
+}
+
+// ReadBuildLog streams the logs of the kaniko job building the docker image of a model version.
+func (c *ImageBuildController) ReadBuildLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+
+	model, version, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		NotFound(err.Error()).WriteTo(w)
+		return
+	}
+
+	if model.Type != models.ModelTypePyFunc {
+		BadRequest(fmt.Sprintf("Image build is only supported by %s model, model %s is %s model", models.ModelTypePyFunc, model.Name, model.Type)).WriteTo(w)
+		return
+	}
+
+	build, err := c.ImageBuilder.GetBuild(ctx, model.Project, model, version)
+	if err != nil {
+		log.Errorf("failed getting image build of model %s version %s: %v", model.Name, version.ID, err)
+		InternalServerError(fmt.Sprintf("Failed getting image build: %s", err)).WriteTo(w)
+		return
+	}
+
+	// the log options are taken from the query string while the kaniko job is identified by the model version
+	params := r.URL.Query()
+	params.Set("project_name", model.Project.Name)
+	params.Set("model_id", model.ID.String())
+	params.Set("model_name", model.Name)
+	params.Set("version_id", version.ID.String())
+	params.Del("prediction_job_id")
+	params.Set("cluster", build.Cluster)
+	params.Set("namespace", build.Namespace)
+	params.Set("component_type", models.ImageBuilderComponentType)
+
+	var query service.LogQuery
+	if err := decoder.Decode(&query, params); err != nil {
+		log.Errorf("Error while parsing query string %v", err)
+		BadRequest(fmt.Sprintf("Unable to parse query string: %s", err)).WriteTo(w)
+		return
+	}
+
+	c.streamLogs(w, r, &query)
+}
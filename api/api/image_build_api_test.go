@@ -0,0 +1,192 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	imagebuildermock "github.com/caraml-dev/merlin/pkg/imagebuilder/mocks"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestStartImageBuild(t *testing.T) {
+	build := &models.ImageBuild{
+		VersionID: models.ID(1),
+		Status:    models.ImageBuildStatusBuilding,
+		ImageRef:  "ghcr.io/project-model-1:1",
+		JobName:   "project-model-1-1",
+		Namespace: "mlp",
+		Cluster:   "dev",
+	}
+
+	testCases := []struct {
+		desc            string
+		modelType       string
+		environmentName string
+		imageBuilder    func() *imagebuildermock.ImageBuilder
+		expected        *Response
+	}{
+		{
+			desc:      "Should start image build",
+			modelType: models.ModelTypePyFunc,
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("StartBuild", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "id-dev").Return(build, nil)
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: build,
+			},
+		},
+		{
+			desc:            "Should start image build with the build config of the given environment",
+			modelType:       models.ModelTypePyFunc,
+			environmentName: "id-staging",
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("StartBuild", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "id-staging").Return(build, nil)
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: build,
+			},
+		},
+		{
+			desc:            "Should return 404 if environment is not found",
+			modelType:       models.ModelTypePyFunc,
+			environmentName: "id-prod",
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				return &imagebuildermock.ImageBuilder{}
+			},
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Environment not found: id-prod"},
+			},
+		},
+		{
+			desc:      "Should return 400 if model is not pyfunc",
+			modelType: models.ModelTypeSkLearn,
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				return &imagebuildermock.ImageBuilder{}
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Image build is only supported by pyfunc model, model model-1 is sklearn model"},
+			},
+		},
+		{
+			desc:      "Should return 500 if image build can't be started",
+			modelType: models.ModelTypePyFunc,
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("StartBuild", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "id-dev").Return(nil, errors.New("unable to create job"))
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Failed starting image build: unable to create job"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			model := &models.Model{
+				ID:        models.ID(1),
+				Name:      "model-1",
+				ProjectID: models.ID(1),
+				Project:   mlp.Project{Name: "project"},
+				Type:      tC.modelType,
+			}
+			modelSvc := &mocks.ModelsService{}
+			modelSvc.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+			versionSvc := &mocks.VersionsService{}
+			versionSvc.On("FindByID", mock.Anything, models.ID(1), models.ID(1), mock.Anything).Return(&models.Version{
+				ID:      models.ID(1),
+				ModelID: models.ID(1),
+				Model:   model,
+			}, nil)
+
+			envSvc := &mocks.EnvironmentService{}
+			envSvc.On("GetDefaultEnvironment").Return(&models.Environment{Name: "id-dev"}, nil)
+			envSvc.On("GetEnvironment", "id-staging").Return(&models.Environment{Name: "id-staging"}, nil)
+			envSvc.On("GetEnvironment", "id-prod").Return(nil, gorm.ErrRecordNotFound)
+
+			ctl := &ImageBuildController{
+				AppContext: &AppContext{
+					ModelsService:      modelSvc,
+					VersionsService:    versionSvc,
+					EnvironmentService: envSvc,
+					ImageBuilder:       tC.imageBuilder(),
+				},
+			}
+			target := "/models/1/versions/1/image"
+			if tC.environmentName != "" {
+				target += "?environment_name=" + tC.environmentName
+			}
+			resp := ctl.StartBuild(httptest.NewRequest(http.MethodPost, target, nil), map[string]string{"model_id": "1", "version_id": "1"}, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestGetImageBuild(t *testing.T) {
+	build := &models.ImageBuild{
+		VersionID: models.ID(1),
+		Status:    models.ImageBuildStatusNotBuilt,
+		ImageRef:  "ghcr.io/project-model-1:1",
+		JobName:   "project-model-1-1",
+		Namespace: "mlp",
+		Cluster:   "dev",
+	}
+
+	model := &models.Model{
+		ID:        models.ID(1),
+		Name:      "model-1",
+		ProjectID: models.ID(1),
+		Project:   mlp.Project{Name: "project"},
+		Type:      models.ModelTypePyFunc,
+	}
+	modelSvc := &mocks.ModelsService{}
+	modelSvc.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+	versionSvc := &mocks.VersionsService{}
+	versionSvc.On("FindByID", mock.Anything, models.ID(1), models.ID(1), mock.Anything).Return(&models.Version{
+		ID:      models.ID(1),
+		ModelID: models.ID(1),
+		Model:   model,
+	}, nil)
+	imageBuilder := &imagebuildermock.ImageBuilder{}
+	imageBuilder.On("GetBuild", mock.Anything, model.Project, model, mock.Anything).Return(build, nil)
+
+	ctl := &ImageBuildController{
+		AppContext: &AppContext{
+			ModelsService:   modelSvc,
+			VersionsService: versionSvc,
+			ImageBuilder:    imageBuilder,
+		},
+	}
+	resp := ctl.GetBuild(&http.Request{}, map[string]string{"model_id": "1", "version_id": "1"}, nil)
+	assert.Equal(t, &Response{code: http.StatusOK, data: build}, resp)
+}
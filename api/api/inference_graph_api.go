@@ -0,0 +1,195 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// InferenceGraphController controls inference graph API.
+type InferenceGraphController struct {
+	*AppContext
+}
+
+// ListInferenceGraphs lists all inference graphs of a project.
+func (c *InferenceGraphController) ListInferenceGraphs(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	graphs, err := c.InferenceGraphService.ListInferenceGraphs(ctx, projectID)
+	if err != nil {
+		log.Errorf("failed listing inference graphs of project %d: %v", projectID, err)
+		return InternalServerError(fmt.Sprintf("Error while listing inference graphs of project %d", projectID))
+	}
+
+	return Ok(graphs)
+}
+
+// GetInferenceGraph gets an inference graph of a project.
+func (c *InferenceGraphController) GetInferenceGraph(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	graphID, _ := models.ParseID(vars["inference_graph_id"])
+
+	graph, err := c.InferenceGraphService.FindByID(ctx, projectID, graphID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Inference graph with given `inference_graph_id: %d` not found", graphID))
+		}
+		log.Errorf("failed getting inference graph %d: %v", graphID, err)
+		return InternalServerError(fmt.Sprintf("Error while getting inference graph %d", graphID))
+	}
+
+	return Ok(graph)
+}
+
+// CreateInferenceGraph creates a new inference graph in a project and deploys it.
+func (c *InferenceGraphController) CreateInferenceGraph(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	project, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	graph, ok := body.(*models.InferenceGraph)
+	if !ok {
+		return BadRequest("Unable to parse body as inference graph")
+	}
+	graph.ID = 0
+	graph.ProjectID = projectID
+
+	var env *models.Environment
+	if graph.EnvironmentName == "" {
+		// Use default environment if not specified
+		env, err = c.EnvironmentService.GetDefaultEnvironment()
+		if err != nil {
+			return InternalServerError("Default environment not found, please specify one")
+		}
+	} else {
+		env, err = c.EnvironmentService.GetEnvironment(graph.EnvironmentName)
+		if err != nil {
+			if !gorm.IsRecordNotFoundError(err) {
+				log.Errorf("Unable to find the specified environment: %s. Err: %s", graph.EnvironmentName, err)
+				return InternalServerError(fmt.Sprintf("Unable to find the specified environment: %s", graph.EnvironmentName))
+			}
+			return NotFound(fmt.Sprintf("Environment not found: %s", graph.EnvironmentName))
+		}
+	}
+	graph.Environment = env
+	graph.EnvironmentName = env.Name
+
+	graph, err = c.InferenceGraphService.DeployInferenceGraph(ctx, r.Header.Get("User-Email"), project, graph)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed deploying inference graph: %v", err)
+		return InternalServerError(fmt.Sprintf("Unable to deploy inference graph: %s", err.Error()))
+	}
+
+	return Created(graph)
+}
+
+// UpdateInferenceGraph updates the nodes, logger and alert of an existing inference graph and redeploys it.
+func (c *InferenceGraphController) UpdateInferenceGraph(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	graphID, _ := models.ParseID(vars["inference_graph_id"])
+	project, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	newGraph, ok := body.(*models.InferenceGraph)
+	if !ok {
+		return BadRequest("Unable to parse body as inference graph")
+	}
+
+	graph, err := c.InferenceGraphService.FindByID(ctx, projectID, graphID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Inference graph with given `inference_graph_id: %d` not found", graphID))
+		}
+		log.Errorf("failed getting inference graph %d: %v", graphID, err)
+		return InternalServerError(fmt.Sprintf("Error while getting inference graph %d", graphID))
+	}
+
+	if newGraph.Name != "" && newGraph.Name != graph.Name {
+		return BadRequest("Updating inference graph name is not allowed")
+	}
+	if newGraph.EnvironmentName != "" && newGraph.EnvironmentName != graph.EnvironmentName {
+		return BadRequest("Updating inference graph environment is not allowed")
+	}
+	graph.Nodes = newGraph.Nodes
+	graph.Logger = newGraph.Logger
+	graph.Alert = newGraph.Alert
+
+	graph, err = c.InferenceGraphService.DeployInferenceGraph(ctx, r.Header.Get("User-Email"), project, graph)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed deploying inference graph %d: %v", graphID, err)
+		return InternalServerError(fmt.Sprintf("Unable to deploy inference graph: %s", err.Error()))
+	}
+
+	return Ok(graph)
+}
+
+// DeleteInferenceGraph undeploys an inference graph.
+func (c *InferenceGraphController) DeleteInferenceGraph(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	graphID, _ := models.ParseID(vars["inference_graph_id"])
+	project, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	graph, err := c.InferenceGraphService.FindByID(ctx, projectID, graphID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Inference graph with given `inference_graph_id: %d` not found", graphID))
+		}
+		log.Errorf("failed getting inference graph %d: %v", graphID, err)
+		return InternalServerError(fmt.Sprintf("Error while getting inference graph %d", graphID))
+	}
+
+	graph, err = c.InferenceGraphService.UndeployInferenceGraph(ctx, r.Header.Get("User-Email"), project, graph)
+	if err != nil {
+		log.Errorf("failed undeploying inference graph %d: %v", graphID, err)
+		return InternalServerError(fmt.Sprintf("Unable to undeploy inference graph: %s", err.Error()))
+	}
+
+	return Ok(graph)
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/caraml-dev/mlp/api/client"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestCreateInferenceGraph(t *testing.T) {
+	isDefault := true
+	env := &models.Environment{Name: "env1", IsDefault: &isDefault}
+	project := mlp.Project(client.Project{ID: 1, Name: "sample"})
+
+	testCases := []struct {
+		desc        string
+		body        interface{}
+		deployed    *models.InferenceGraph
+		errDeploy   error
+		expected    *Response
+		deployCalls int
+	}{
+		{
+			desc:     "Should deploy graph in default environment",
+			body:     &models.InferenceGraph{Name: "my-graph"},
+			deployed: &models.InferenceGraph{ID: 1, ProjectID: 1, Name: "my-graph", EnvironmentName: "env1", Status: models.EndpointPending},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: &models.InferenceGraph{ID: 1, ProjectID: 1, Name: "my-graph", EnvironmentName: "env1", Status: models.EndpointPending},
+			},
+			deployCalls: 1,
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			body: &models.Model{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as inference graph"},
+			},
+		},
+		{
+			desc:      "Should return bad request if graph is invalid",
+			body:      &models.InferenceGraph{Name: "my-graph"},
+			errDeploy: merror.NewInvalidInputError(`inference graph must have a "root" node`),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: `invalid input: inference graph must have a "root" node`},
+			},
+			deployCalls: 1,
+		},
+		{
+			desc:      "Should return internal server error if deployment fails",
+			body:      &models.InferenceGraph{Name: "my-graph"},
+			errDeploy: errors.New("queue is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Unable to deploy inference graph: queue is down"},
+			},
+			deployCalls: 1,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(project, nil)
+
+			envService := &mocks.EnvironmentService{}
+			envService.On("GetDefaultEnvironment").Return(env, nil)
+
+			graphService := &mocks.InferenceGraphService{}
+			graphService.On("DeployInferenceGraph", mock.Anything, mock.Anything, project, mock.MatchedBy(func(graph *models.InferenceGraph) bool {
+				return graph.ProjectID == models.ID(1) && graph.EnvironmentName == "env1"
+			})).Return(tC.deployed, tC.errDeploy)
+
+			ctl := &InferenceGraphController{
+				AppContext: &AppContext{
+					ProjectsService:       projectService,
+					EnvironmentService:    envService,
+					InferenceGraphService: graphService,
+				},
+			}
+			resp := ctl.CreateInferenceGraph(&http.Request{}, map[string]string{"project_id": "1"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+			graphService.AssertNumberOfCalls(t, "DeployInferenceGraph", tC.deployCalls)
+		})
+	}
+}
+
+func TestUpdateInferenceGraph(t *testing.T) {
+	project := mlp.Project(client.Project{ID: 1, Name: "sample"})
+	existing := func() *models.InferenceGraph {
+		return &models.InferenceGraph{ID: 2, ProjectID: 1, Name: "my-graph", EnvironmentName: "env1", Status: models.EndpointRunning}
+	}
+	nodes := models.InferenceGraphNodes{
+		models.InferenceGraphRootNode: {RouterType: models.InferenceGraphSequence, Steps: []*models.InferenceGraphStep{{NodeName: "other"}}},
+	}
+	logger := &models.LoggerConfig{Enabled: true, Mode: models.LogAll}
+	alert := &models.InferenceGraphAlert{TeamName: "dsp"}
+
+	testCases := []struct {
+		desc     string
+		body     *models.InferenceGraph
+		errFind  error
+		expected *Response
+	}{
+		{
+			desc: "Should redeploy graph with new nodes",
+			body: &models.InferenceGraph{Nodes: nodes},
+			expected: &Response{
+				code: http.StatusOK,
+				data: &models.InferenceGraph{ID: 2, ProjectID: 1, Name: "my-graph", Nodes: nodes, EnvironmentName: "env1", Status: models.EndpointRunning},
+			},
+		},
+		{
+			desc: "Should redeploy graph with new logger and alert",
+			body: &models.InferenceGraph{Nodes: nodes, Logger: logger, Alert: alert},
+			expected: &Response{
+				code: http.StatusOK,
+				data: &models.InferenceGraph{ID: 2, ProjectID: 1, Name: "my-graph", Nodes: nodes, Logger: logger, Alert: alert, EnvironmentName: "env1", Status: models.EndpointRunning},
+			},
+		},
+		{
+			desc:    "Should return not found if graph doesn't exist",
+			body:    &models.InferenceGraph{Nodes: nodes},
+			errFind: gorm.ErrRecordNotFound,
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Inference graph with given `inference_graph_id: 2` not found"},
+			},
+		},
+		{
+			desc: "Should return bad request if environment is changed",
+			body: &models.InferenceGraph{Nodes: nodes, EnvironmentName: "env2"},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Updating inference graph environment is not allowed"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(project, nil)
+
+			graphService := &mocks.InferenceGraphService{}
+			if tC.errFind != nil {
+				graphService.On("FindByID", mock.Anything, models.ID(1), models.ID(2)).Return(nil, tC.errFind)
+			} else {
+				graphService.On("FindByID", mock.Anything, models.ID(1), models.ID(2)).Return(existing(), nil)
+			}
+			graphService.On("DeployInferenceGraph", mock.Anything, mock.Anything, project, mock.Anything).Return(func(_ context.Context, _ string, _ mlp.Project, graph *models.InferenceGraph) *models.InferenceGraph {
+				return graph
+			}, nil)
+
+			ctl := &InferenceGraphController{
+				AppContext: &AppContext{
+					ProjectsService:       projectService,
+					InferenceGraphService: graphService,
+				},
+			}
+			resp := ctl.UpdateInferenceGraph(&http.Request{}, map[string]string{"project_id": "1", "inference_graph_id": "2"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
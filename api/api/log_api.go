@@ -33,8 +33,6 @@ type LogController struct {
 
 // ReadLog parses log requests and fetches logs.
 func (l *LogController) ReadLog(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
 	var query service.LogQuery
 	err := decoder.Decode(&query, r.URL.Query())
 	if err != nil {
@@ -43,6 +41,13 @@ func (l *LogController) ReadLog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	l.streamLogs(w, r, &query)
+}
+
+// streamLogs writes the logs matching the query to the response as an event stream.
+func (c *AppContext) streamLogs(w http.ResponseWriter, r *http.Request, query *service.LogQuery) {
+	ctx := r.Context()
+
 	logLineCh := make(chan string)
 	stopCh := make(chan struct{})
 
@@ -75,7 +80,7 @@ func (l *LogController) ReadLog(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	if err := l.LogService.StreamLogs(ctx, logLineCh, stopCh, &query); err != nil {
+	if err := c.LogService.StreamLogs(ctx, logLineCh, stopCh, query); err != nil {
 		InternalServerError(err.Error()).WriteTo(w)
 		return
 	}
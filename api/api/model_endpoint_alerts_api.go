@@ -15,6 +15,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -22,6 +24,7 @@ import (
 
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
 )
 
 // AlertsController controls alerts API.
@@ -84,27 +87,15 @@ func (c *AlertsController) CreateModelEndpointAlert(r *http.Request, vars map[st
 		return BadRequest("Unable to parse body as model endpoint alert")
 	}
 
-	model, err := c.ModelsService.FindByID(ctx, modelID)
-	if err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			return NotFound(fmt.Sprintf("Model with id %s not found", modelID))
-		}
-		return InternalServerError(fmt.Sprintf("Error while getting model with id %s", modelID))
+	if resp := c.setModelAndEndpoint(ctx, alert, modelID, modelEndpointID); resp != nil {
+		return resp
 	}
 
-	alert.Model = model
-
-	modelEndpoint, err := c.ModelEndpointsService.FindByID(ctx, modelEndpointID)
+	alert, err := c.ModelEndpointAlertService.CreateModelEndpointAlert(user, alert)
 	if err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			return NotFound(fmt.Sprintf("Model endpoint with id %s not found", modelEndpointID))
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
 		}
-		return InternalServerError(fmt.Sprintf("Error while getting model endpoint with id %s", modelEndpointID))
-	}
-	alert.ModelEndpoint = modelEndpoint
-
-	alert, err = c.ModelEndpointAlertService.CreateModelEndpointAlert(user, alert)
-	if err != nil {
 		log.Errorf("CreateModelEndpointAlert: %s", err)
 		return InternalServerError(fmt.Sprintf("Error while creating model endpoint alert for Model %s, Endpoint %s", modelID, modelEndpointID))
 	}
@@ -147,9 +138,62 @@ func (c *AlertsController) UpdateModelEndpointAlert(r *http.Request, vars map[st
 
 	newAlert, err = c.ModelEndpointAlertService.UpdateModelEndpointAlert(user, newAlert)
 	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
 		log.Errorf("UpdateModelEndpointAlert: %s", err)
 		return InternalServerError(fmt.Sprintf("Error while updating model endpoint alert for Model %s, Endpoint %s", modelID, modelEndpointID))
 	}
 
 	return Created(newAlert)
 }
+
+// PreviewModelEndpointAlert returns the Prometheus rules generated for a model endpoint alert without committing them.
+func (c *AlertsController) PreviewModelEndpointAlert(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	modelEndpointID, _ := models.ParseID(vars["model_endpoint_id"])
+
+	alert, ok := body.(*models.ModelEndpointAlert)
+	if !ok {
+		return BadRequest("Unable to parse body as model endpoint alert")
+	}
+
+	if resp := c.setModelAndEndpoint(ctx, alert, modelID, modelEndpointID); resp != nil {
+		return resp
+	}
+
+	preview, err := c.ModelEndpointAlertService.PreviewModelEndpointAlert(alert)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("PreviewModelEndpointAlert: %s", err)
+		return InternalServerError(fmt.Sprintf("Error while generating model endpoint alert for Model %s, Endpoint %s", modelID, modelEndpointID))
+	}
+
+	return Ok(preview)
+}
+
+func (c *AlertsController) setModelAndEndpoint(ctx context.Context, alert *models.ModelEndpointAlert, modelID, modelEndpointID models.ID) *Response {
+	model, err := c.ModelsService.FindByID(ctx, modelID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Model with id %s not found", modelID))
+		}
+		return InternalServerError(fmt.Sprintf("Error while getting model with id %s", modelID))
+	}
+	alert.Model = model
+
+	modelEndpoint, err := c.ModelEndpointsService.FindByID(ctx, modelEndpointID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Model endpoint with id %s not found", modelEndpointID))
+		}
+		return InternalServerError(fmt.Sprintf("Error while getting model endpoint with id %s", modelEndpointID))
+	}
+	alert.ModelEndpoint = modelEndpoint
+
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
 	"github.com/caraml-dev/merlin/service/mocks"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
@@ -593,6 +594,47 @@ func TestCreateModelEndpointAlert(t *testing.T) {
 				data: Error{Message: "Error while creating model endpoint alert for Model 1, Endpoint 1"},
 			},
 		},
+		{
+			desc: "Should return 400 if alert is invalid",
+			vars: map[string]string{
+				"user":              "admin",
+				"model_id":          "1",
+				"model_endpoint_id": "1",
+			},
+			request: &models.ModelEndpointAlert{
+				ModelID:         models.ID(1),
+				ModelEndpointID: models.ID(1),
+				EnvironmentName: "dev",
+				AlertConditions: models.AlertConditions{
+					{
+						Enabled:    true,
+						MetricType: models.AlertConditionTypeCustom,
+						Severity:   models.AlertConditionSeverityCritical,
+						Name:       "Low AUC",
+					},
+				},
+			},
+			modelService: func() *mocks.ModelsService {
+				svc := &mocks.ModelsService{}
+				svc.On("FindByID", mock.Anything, models.ID(1)).Return(&models.Model{ID: models.ID(1), Name: "model-1"}, nil)
+				return svc
+			},
+			modelEndpointService: func() *mocks.ModelEndpointsService {
+				svc := &mocks.ModelEndpointsService{}
+				svc.On("FindByID", mock.Anything, models.ID(1)).Return(&models.ModelEndpoint{ID: models.ID(1), ModelID: models.ID(1)}, nil)
+				return svc
+			},
+			modelEndpointAlertService: func() *mocks.ModelEndpointAlertService {
+				svc := &mocks.ModelEndpointAlertService{}
+				svc.On("CreateModelEndpointAlert", "admin", mock.Anything).
+					Return(nil, merror.NewInvalidInputError("invalid alert condition 0: expr of custom condition is required"))
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: invalid alert condition 0: expr of custom condition is required"},
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -942,3 +984,96 @@ func TestUpdateModelEndpointAlert(t *testing.T) {
 		})
 	}
 }
+
+func TestPreviewModelEndpointAlert(t *testing.T) {
+	alert := &models.ModelEndpointAlert{
+		ModelID:         models.ID(1),
+		ModelEndpointID: models.ID(1),
+		EnvironmentName: "dev",
+		TeamName:        "dsp",
+		SLOs: models.AlertSLOs{
+			{
+				Enabled:   true,
+				Type:      models.AlertSLOTypeAvailability,
+				Objective: 99.9,
+			},
+		},
+	}
+	preview := &models.ModelEndpointAlertPreview{
+		FileName: "alerts/merlin/project-1/model-1_dev.yaml",
+		Content:  "groups: []\n",
+	}
+
+	testCases := []struct {
+		desc               string
+		request            interface{}
+		errFindingEndpoint error
+		errPreviewing      error
+		expected           *Response
+	}{
+		{
+			desc:    "Should return the generated rules",
+			request: alert,
+			expected: &Response{
+				code: http.StatusOK,
+				data: preview,
+			},
+		},
+		{
+			desc:    "Should return 400 if request is invalid",
+			request: &models.ModelEndpoint{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as model endpoint alert"},
+			},
+		},
+		{
+			desc:               "Should return 404 if model endpoint doesn't exist",
+			request:            alert,
+			errFindingEndpoint: gorm.ErrRecordNotFound,
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Model endpoint with id 1 not found"},
+			},
+		},
+		{
+			desc:          "Should return 400 if alert is invalid",
+			request:       alert,
+			errPreviewing: merror.NewInvalidInputError("invalid SLO 0: unknown type throughput"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: invalid SLO 0: unknown type throughput"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelSvc := &mocks.ModelsService{}
+			modelSvc.On("FindByID", mock.Anything, models.ID(1)).Return(&models.Model{
+				ID:      models.ID(1),
+				Name:    "model-1",
+				Project: mlp.Project{Name: "project-1"},
+			}, nil)
+
+			modelEndpointSvc := &mocks.ModelEndpointsService{}
+			modelEndpointSvc.On("FindByID", mock.Anything, models.ID(1)).Return(&models.ModelEndpoint{
+				ID:              models.ID(1),
+				ModelID:         models.ID(1),
+				EnvironmentName: "dev",
+			}, tC.errFindingEndpoint)
+
+			modelEndpointAlertSvc := &mocks.ModelEndpointAlertService{}
+			modelEndpointAlertSvc.On("PreviewModelEndpointAlert", alert).Return(preview, tC.errPreviewing)
+
+			ctl := &AlertsController{
+				AppContext: &AppContext{
+					ModelsService:             modelSvc,
+					ModelEndpointsService:     modelEndpointSvc,
+					ModelEndpointAlertService: modelEndpointAlertSvc,
+				},
+			}
+			resp := ctl.PreviewModelEndpointAlert(&http.Request{}, map[string]string{"model_id": "1", "model_endpoint_id": "1"}, tC.request)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
@@ -90,6 +90,9 @@ func (c *PredictionJobController) List(r *http.Request, vars map[string]string,
 		return InternalServerError("Failed listing prediction job")
 	}
 
+	for _, job := range jobs {
+		job.UpdateCostEstimation()
+	}
 	return Ok(jobs)
 }
 
@@ -120,6 +123,7 @@ func (c *PredictionJobController) Get(r *http.Request, vars map[string]string, _
 		return InternalServerError("Failed reading prediction job")
 	}
 
+	job.UpdateCostEstimation()
 	return Ok(job)
 }
 
@@ -188,6 +192,39 @@ func (c *PredictionJobController) ListContainers(r *http.Request, vars map[strin
 	return Ok(containers)
 }
 
+// ListAttempts method lists all attempts of a prediction job.
+func (c *PredictionJobController) ListAttempts(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+	id, _ := models.ParseID(vars["job_id"])
+
+	model, version, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			return InternalServerError(err.Error())
+		}
+		return NotFound(err.Error())
+	}
+
+	env, err := c.AppContext.EnvironmentService.GetDefaultPredictionJobEnvironment()
+	if err != nil {
+		return InternalServerError("Unable to find default environment, specify environment target for deployment")
+	}
+
+	attempts, err := c.PredictionJobService.ListAttempts(ctx, env, model, version, id)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Prediction job %s not found", id))
+		}
+		log.Errorf("failed to list attempts of prediction job %s for model %s version %s: %v", id, model.Name, version.ID, err)
+		return InternalServerError("Failed listing prediction job attempts")
+	}
+
+	return Ok(attempts)
+}
+
 // ListAllInProject lists all prediction jobs of a project.
 func (c *PredictionJobController) ListAllInProject(r *http.Request, vars map[string]string, body interface{}) *Response {
 	ctx := r.Context()
@@ -212,5 +249,8 @@ func (c *PredictionJobController) ListAllInProject(r *http.Request, vars map[str
 		return InternalServerError("Failed listing prediction job")
 	}
 
+	for _, job := range jobs {
+		job.UpdateCostEstimation()
+	}
 	return Ok(jobs)
 }
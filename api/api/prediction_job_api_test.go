@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	uuid2 "github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -712,6 +713,115 @@ func TestStop(t *testing.T) {
 	}
 }
 
+func TestListAttempts(t *testing.T) {
+	trueBoolean := true
+	model := &models.Model{
+		ID:        models.ID(1),
+		Name:      "model-1",
+		ProjectID: models.ID(1),
+		Type:      "pyfunc",
+	}
+	version := &models.Version{
+		ID:      models.ID(1),
+		ModelID: models.ID(1),
+		Model:   model,
+	}
+	env := &models.Environment{
+		ID:                     models.ID(1),
+		Name:                   "dev",
+		Cluster:                "dev",
+		IsPredictionJobEnabled: true,
+		IsDefaultPredictionJob: &trueBoolean,
+	}
+	attempts := []*models.PredictionJobAttempt{
+		{
+			JobID:                models.ID(1),
+			Attempt:              1,
+			Status:               models.JobFailed,
+			Error:                "ExecutorLostFailure",
+			FailureReason:        models.FailureReasonPreemption,
+			SparkApplicationName: "prediction-job-1",
+			DriverPodName:        "prediction-job-1-driver",
+		},
+		{
+			JobID:                models.ID(1),
+			Attempt:              2,
+			Status:               models.JobRunning,
+			SparkApplicationName: "prediction-job-1-2",
+			DriverPodName:        "prediction-job-1-2-driver",
+		},
+	}
+
+	testCases := []struct {
+		desc                 string
+		predictionJobService func() *mocks.PredictionJobService
+		expected             *Response
+	}{
+		{
+			desc: "Should success list prediction job attempts",
+			predictionJobService: func() *mocks.PredictionJobService {
+				svc := &mocks.PredictionJobService{}
+				svc.On("ListAttempts", mock.Anything, env, model, version, models.ID(1)).Return(attempts, nil)
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusOK,
+				data: attempts,
+			},
+		},
+		{
+			desc: "Should return 404 if prediction job is not found",
+			predictionJobService: func() *mocks.PredictionJobService {
+				svc := &mocks.PredictionJobService{}
+				svc.On("ListAttempts", mock.Anything, env, model, version, models.ID(1)).Return(nil, gorm.ErrRecordNotFound)
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Prediction job 1 not found"},
+			},
+		},
+		{
+			desc: "Should return 500 if error when listing attempts",
+			predictionJobService: func() *mocks.PredictionJobService {
+				svc := &mocks.PredictionJobService{}
+				svc.On("ListAttempts", mock.Anything, env, model, version, models.ID(1)).Return(nil, fmt.Errorf("Connection refused"))
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Failed listing prediction job attempts"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelSvc := &mocks.ModelsService{}
+			modelSvc.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+			versionSvc := &mocks.VersionsService{}
+			versionSvc.On("FindByID", mock.Anything, models.ID(1), models.ID(1), mock.Anything).Return(version, nil)
+			envSvc := &mocks.EnvironmentService{}
+			envSvc.On("GetDefaultPredictionJobEnvironment").Return(env, nil)
+
+			ctl := &PredictionJobController{
+				AppContext: &AppContext{
+					ModelsService:        modelSvc,
+					VersionsService:      versionSvc,
+					PredictionJobService: tC.predictionJobService(),
+					EnvironmentService:   envSvc,
+				},
+			}
+			vars := map[string]string{
+				"model_id":   "1",
+				"version_id": "1",
+				"job_id":     "1",
+			}
+			resp := ctl.ListAttempts(&http.Request{}, vars, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
 func TestListContainers_PredictionJob(t *testing.T) {
 	trueBoolean := true
 	uuid := uuid2.New()
@@ -0,0 +1,250 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// PredictionJobScheduleController controls prediction job schedule API.
+type PredictionJobScheduleController struct {
+	*AppContext
+}
+
+// ListAllInProject lists all prediction job schedules of a project.
+func (c *PredictionJobScheduleController) ListAllInProject(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	schedules, err := c.PredictionJobScheduleService.ListSchedules(ctx, &models.PredictionJobSchedule{ProjectID: projectID})
+	if err != nil {
+		log.Errorf("failed listing prediction job schedules of project %d: %v", projectID, err)
+		return InternalServerError("Failed listing prediction job schedules")
+	}
+	return Ok(schedules)
+}
+
+// List lists all prediction job schedules of a model version.
+func (c *PredictionJobScheduleController) List(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+
+	_, _, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		return NotFound(err.Error())
+	}
+
+	schedules, err := c.PredictionJobScheduleService.ListSchedules(ctx, &models.PredictionJobSchedule{ModelID: modelID, VersionID: versionID})
+	if err != nil {
+		log.Errorf("failed listing prediction job schedules of model %d version %d: %v", modelID, versionID, err)
+		return InternalServerError("Failed listing prediction job schedules")
+	}
+	return Ok(schedules)
+}
+
+// Get gets a prediction job schedule.
+func (c *PredictionJobScheduleController) Get(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	_, _, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+	return Ok(schedule)
+}
+
+// Create creates a prediction job schedule of a model version in the default prediction job environment.
+func (c *PredictionJobScheduleController) Create(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+
+	model, version, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		return NotFound(err.Error())
+	}
+
+	schedule, ok := body.(*models.PredictionJobSchedule)
+	if !ok {
+		return BadRequest("Unable to parse body as prediction job schedule")
+	}
+
+	env, err := c.EnvironmentService.GetDefaultPredictionJobEnvironment()
+	if err != nil {
+		return InternalServerError("Unable to find default environment, specify environment target for deployment")
+	}
+
+	schedule.ID = 0
+	schedule.ProjectID = model.ProjectID
+	schedule.ModelID = model.ID
+	schedule.VersionID = version.ID
+	schedule.EnvironmentName = env.Name
+	schedule.LastRunAt = nil
+
+	return c.saveSchedule(r, schedule, Created)
+}
+
+// Update updates the schedule and job template of a prediction job schedule.
+func (c *PredictionJobScheduleController) Update(r *http.Request, vars map[string]string, body interface{}) *Response {
+	newSchedule, ok := body.(*models.PredictionJobSchedule)
+	if !ok {
+		return BadRequest("Unable to parse body as prediction job schedule")
+	}
+
+	_, _, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	schedule.Name = newSchedule.Name
+	schedule.Schedule = newSchedule.Schedule
+	schedule.Timezone = newSchedule.Timezone
+	schedule.ConcurrencyPolicy = newSchedule.ConcurrencyPolicy
+	schedule.MaxHistory = newSchedule.MaxHistory
+	schedule.Config = newSchedule.Config
+
+	return c.saveSchedule(r, schedule, Ok)
+}
+
+// Delete deletes a prediction job schedule and its run history. Prediction jobs it created are kept.
+func (c *PredictionJobScheduleController) Delete(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	_, _, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	if err := c.PredictionJobScheduleService.DeleteSchedule(r.Context(), schedule); err != nil {
+		log.Errorf("failed deleting prediction job schedule %d: %v", schedule.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while deleting prediction job schedule %d", schedule.ID))
+	}
+	return NoContent()
+}
+
+// Pause pauses a prediction job schedule.
+func (c *PredictionJobScheduleController) Pause(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	_, _, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	paused, err := c.PredictionJobScheduleService.PauseSchedule(r.Context(), schedule)
+	if err != nil {
+		log.Errorf("failed pausing prediction job schedule %d: %v", schedule.ID, err)
+		return InternalServerError("Error while pausing prediction job schedule")
+	}
+	return Ok(paused)
+}
+
+// Resume resumes a paused prediction job schedule.
+func (c *PredictionJobScheduleController) Resume(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	_, _, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	resumed, err := c.PredictionJobScheduleService.ResumeSchedule(r.Context(), schedule)
+	if err != nil {
+		log.Errorf("failed resuming prediction job schedule %d: %v", schedule.ID, err)
+		return InternalServerError("Error while resuming prediction job schedule")
+	}
+	return Ok(resumed)
+}
+
+// Trigger creates a run of a prediction job schedule now, regardless of whether it's paused.
+func (c *PredictionJobScheduleController) Trigger(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	model, version, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	env, err := c.EnvironmentService.GetEnvironment(schedule.EnvironmentName)
+	if err != nil {
+		return InternalServerError(fmt.Sprintf("Unable to find environment %s", schedule.EnvironmentName))
+	}
+
+	run, err := c.PredictionJobScheduleService.TriggerSchedule(ctx, env, model, version, schedule, time.Now().UTC(), true)
+	if err != nil {
+		log.Errorf("failed triggering prediction job schedule %d: %v", schedule.ID, err)
+		return BadRequest(fmt.Sprintf("Failed creating prediction job %s", err))
+	}
+	return Created(run)
+}
+
+// ListRuns lists the run history of a prediction job schedule.
+func (c *PredictionJobScheduleController) ListRuns(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	_, _, schedule, resp := c.findSchedule(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	runs, err := c.PredictionJobScheduleService.ListRuns(r.Context(), schedule)
+	if err != nil {
+		log.Errorf("failed listing runs of prediction job schedule %d: %v", schedule.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while listing runs of prediction job schedule %d", schedule.ID))
+	}
+	return Ok(runs)
+}
+
+func (c *PredictionJobScheduleController) findSchedule(r *http.Request, vars map[string]string) (*models.Model, *models.Version, *models.PredictionJobSchedule, *Response) {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+	scheduleID, _ := models.ParseID(vars["schedule_id"])
+
+	model, version, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		return nil, nil, nil, NotFound(err.Error())
+	}
+
+	schedule, err := c.PredictionJobScheduleService.FindByID(ctx, scheduleID)
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		log.Errorf("failed getting prediction job schedule %d: %v", scheduleID, err)
+		return nil, nil, nil, InternalServerError(fmt.Sprintf("Error while getting prediction job schedule %d", scheduleID))
+	}
+	if err != nil || schedule.ModelID != modelID || schedule.VersionID != versionID {
+		return nil, nil, nil, NotFound(fmt.Sprintf("Prediction job schedule with given `schedule_id: %d` not found", scheduleID))
+	}
+	return model, version, schedule, nil
+}
+
+func (c *PredictionJobScheduleController) saveSchedule(r *http.Request, schedule *models.PredictionJobSchedule, success func(interface{}) *Response) *Response {
+	saved, err := c.PredictionJobScheduleService.SaveSchedule(r.Context(), schedule)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed saving prediction job schedule of model %d version %d: %v", schedule.ModelID, schedule.VersionID, err)
+		return InternalServerError("Error while saving prediction job schedule")
+	}
+	return success(saved)
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func newPredictionJobScheduleController(scheduleService *mocks.PredictionJobScheduleService) *PredictionJobScheduleController {
+	modelService := &mocks.ModelsService{}
+	modelService.On("FindByID", mock.Anything, models.ID(1)).Return(&models.Model{ID: 1, ProjectID: 3, Name: "my-model"}, nil)
+
+	versionService := &mocks.VersionsService{}
+	versionService.On("FindByID", mock.Anything, models.ID(1), models.ID(2), mock.Anything).Return(&models.Version{ID: 2, ModelID: 1}, nil)
+
+	environmentService := &mocks.EnvironmentService{}
+	environmentService.On("GetDefaultPredictionJobEnvironment").Return(&models.Environment{Name: "batch"}, nil)
+	environmentService.On("GetEnvironment", "batch").Return(&models.Environment{Name: "batch"}, nil)
+
+	return &PredictionJobScheduleController{
+		AppContext: &AppContext{
+			ModelsService:                modelService,
+			VersionsService:              versionService,
+			EnvironmentService:           environmentService,
+			PredictionJobScheduleService: scheduleService,
+			MonitoringConfig:             config.MonitoringConfig{},
+		},
+	}
+}
+
+func TestPredictionJobScheduleController_Create(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		body     interface{}
+		errSave  error
+		expected *Response
+	}{
+		{
+			desc: "Should create schedule in the default prediction job environment",
+			body: &models.PredictionJobSchedule{Name: "nightly", Schedule: "0 2 * * *"},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: &models.PredictionJobSchedule{Name: "nightly", Schedule: "0 2 * * *", ProjectID: 3, ModelID: 1, VersionID: 2, EnvironmentName: "batch"},
+			},
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			body: &models.PredictionJob{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as prediction job schedule"},
+			},
+		},
+		{
+			desc:    "Should return bad request if schedule is invalid",
+			body:    &models.PredictionJobSchedule{Name: "nightly", Schedule: "daily"},
+			errSave: merror.NewInvalidInputError("invalid schedule daily"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: invalid schedule daily"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			scheduleService := &mocks.PredictionJobScheduleService{}
+			if tC.errSave != nil {
+				scheduleService.On("SaveSchedule", mock.Anything, mock.Anything).Return(nil, tC.errSave)
+			} else {
+				scheduleService.On("SaveSchedule", mock.Anything, mock.Anything).Return(func(_ context.Context, schedule *models.PredictionJobSchedule) *models.PredictionJobSchedule {
+					return schedule
+				}, nil)
+			}
+
+			ctl := newPredictionJobScheduleController(scheduleService)
+			resp := ctl.Create(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestPredictionJobScheduleController_Get(t *testing.T) {
+	schedule := &models.PredictionJobSchedule{ID: 5, ModelID: 1, VersionID: 2}
+
+	scheduleService := &mocks.PredictionJobScheduleService{}
+	scheduleService.On("FindByID", mock.Anything, models.ID(5)).Return(schedule, nil)
+	scheduleService.On("FindByID", mock.Anything, models.ID(6)).Return(&models.PredictionJobSchedule{ID: 6, ModelID: 1, VersionID: 7}, nil)
+	scheduleService.On("FindByID", mock.Anything, models.ID(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	ctl := newPredictionJobScheduleController(scheduleService)
+
+	resp := ctl.Get(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2", "schedule_id": "5"}, nil)
+	assert.Equal(t, &Response{code: http.StatusOK, data: schedule}, resp)
+
+	// schedule of another version
+	resp = ctl.Get(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2", "schedule_id": "6"}, nil)
+	assert.Equal(t, &Response{code: http.StatusNotFound, data: Error{Message: "Prediction job schedule with given `schedule_id: 6` not found"}}, resp)
+
+	resp = ctl.Get(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2", "schedule_id": "7"}, nil)
+	assert.Equal(t, http.StatusNotFound, resp.code)
+}
+
+func TestPredictionJobScheduleController_Trigger(t *testing.T) {
+	jobID := models.ID(10)
+	schedule := &models.PredictionJobSchedule{ID: 5, ModelID: 1, VersionID: 2, EnvironmentName: "batch", Paused: true}
+	run := &models.PredictionJobScheduleRun{ScheduleID: 5, JobID: &jobID, Manual: true}
+
+	scheduleService := &mocks.PredictionJobScheduleService{}
+	scheduleService.On("FindByID", mock.Anything, models.ID(5)).Return(schedule, nil)
+	scheduleService.On("TriggerSchedule", mock.Anything, &models.Environment{Name: "batch"}, mock.Anything, mock.Anything, schedule, mock.Anything, true).Return(run, nil)
+
+	ctl := newPredictionJobScheduleController(scheduleService)
+
+	resp := ctl.Trigger(&http.Request{}, map[string]string{"model_id": "1", "version_id": "2", "schedule_id": "5"}, nil)
+	assert.Equal(t, &Response{code: http.StatusCreated, data: run}, resp)
+	scheduleService.AssertExpectations(t)
+}
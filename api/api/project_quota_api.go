@@ -0,0 +1,83 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// ProjectQuotaController controls project quota API.
+type ProjectQuotaController struct {
+	*AppContext
+}
+
+// GetProjectQuota returns the quota of a project and its current resource usage.
+func (c *ProjectQuotaController) GetProjectQuota(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	usage, err := c.ProjectQuotaService.GetUsage(ctx, projectID)
+	if err != nil {
+		log.Errorf("failed getting quota usage of project %d: %v", projectID, err)
+		return InternalServerError(fmt.Sprintf("Error while getting quota usage of project %d", projectID))
+	}
+
+	return Ok(usage)
+}
+
+// UpdateProjectQuota creates or updates the quota of a project. Only the configured quota admins are allowed to
+// update it, since the project members could otherwise raise their own quota.
+func (c *ProjectQuotaController) UpdateProjectQuota(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	user := r.Header.Get("User-Email")
+	if !isAdmin(c.ProjectQuotaConfig.Admins, user) {
+		return Forbidden(fmt.Sprintf("%s is not allowed to update project quota", user))
+	}
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	quota, ok := body.(*models.ProjectQuota)
+	if !ok {
+		return BadRequest("Unable to parse body as project quota")
+	}
+	quota.ProjectID = projectID
+
+	quota, err = c.ProjectQuotaService.SaveQuota(ctx, quota)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed saving quota of project %d: %v", projectID, err)
+		return InternalServerError(fmt.Sprintf("Error while saving quota of project %d", projectID))
+	}
+
+	return Ok(quota)
+}
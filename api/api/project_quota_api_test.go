@@ -0,0 +1,189 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/caraml-dev/mlp/api/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestGetProjectQuota(t *testing.T) {
+	maxEndpoints := 10
+	usage := &models.ProjectQuotaUsage{
+		Quota: &models.ProjectQuota{ID: 1, ProjectID: 1, MaxEndpoints: &maxEndpoints},
+		Usage: &models.ProjectResourceUsage{
+			CPU:       resource.MustParse("2"),
+			Memory:    resource.MustParse("4Gi"),
+			Replicas:  2,
+			Endpoints: 1,
+		},
+	}
+
+	testCases := []struct {
+		desc               string
+		errFetchingProject error
+		usage              *models.ProjectQuotaUsage
+		errGettingUsage    error
+		expected           *Response
+	}{
+		{
+			desc:  "Should return quota usage",
+			usage: usage,
+			expected: &Response{
+				code: http.StatusOK,
+				data: usage,
+			},
+		},
+		{
+			desc:               "Should return not found if project doesn't exist",
+			errFetchingProject: errors.New("project not found"),
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Project with given `project_id: 1` not found"},
+			},
+		},
+		{
+			desc:            "Should return internal server error if usage is not available",
+			errGettingUsage: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while getting quota usage of project 1"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(mlp.Project(client.Project{ID: 1, Name: "sample"}), tC.errFetchingProject)
+
+			quotaService := &mocks.ProjectQuotaService{}
+			quotaService.On("GetUsage", mock.Anything, models.ID(1)).Return(tC.usage, tC.errGettingUsage)
+
+			ctl := &ProjectQuotaController{
+				AppContext: &AppContext{
+					ProjectsService:     projectService,
+					ProjectQuotaService: quotaService,
+				},
+			}
+			resp := ctl.GetProjectQuota(&http.Request{}, map[string]string{"project_id": "1"}, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestUpdateProjectQuota(t *testing.T) {
+	maxCPU := &models.ProjectQuota{ID: 1, ProjectID: 1, MaxCPU: "8"}
+
+	testCases := []struct {
+		desc           string
+		user           string
+		body           interface{}
+		savedQuota     *models.ProjectQuota
+		errSavingQuota error
+		expected       *Response
+	}{
+		{
+			desc:       "Should save quota",
+			user:       "admin@caraml.dev",
+			body:       &models.ProjectQuota{MaxCPU: "8"},
+			savedQuota: maxCPU,
+			expected: &Response{
+				code: http.StatusOK,
+				data: maxCPU,
+			},
+		},
+		{
+			desc: "Should return forbidden if user is not a quota admin",
+			user: "member@caraml.dev",
+			body: &models.ProjectQuota{MaxCPU: "8"},
+			expected: &Response{
+				code: http.StatusForbidden,
+				data: Error{Message: "member@caraml.dev is not allowed to update project quota"},
+			},
+		},
+		{
+			desc: "Should return forbidden if user is unknown",
+			body: &models.ProjectQuota{MaxCPU: "8"},
+			expected: &Response{
+				code: http.StatusForbidden,
+				data: Error{Message: " is not allowed to update project quota"},
+			},
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			user: "admin@caraml.dev",
+			body: &models.Model{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as project quota"},
+			},
+		},
+		{
+			desc:           "Should return bad request if quota is invalid",
+			user:           "admin@caraml.dev",
+			body:           &models.ProjectQuota{MaxCPU: "eight"},
+			errSavingQuota: merror.NewInvalidInputError("invalid max cpu: eight"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: invalid max cpu: eight"},
+			},
+		},
+		{
+			desc:           "Should return internal server error if saving failed",
+			user:           "admin@caraml.dev",
+			body:           &models.ProjectQuota{MaxCPU: "8"},
+			errSavingQuota: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while saving quota of project 1"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(mlp.Project(client.Project{ID: 1, Name: "sample"}), nil)
+
+			quotaService := &mocks.ProjectQuotaService{}
+			quotaService.On("SaveQuota", mock.Anything, mock.MatchedBy(func(quota *models.ProjectQuota) bool {
+				return quota.ProjectID == models.ID(1)
+			})).Return(tC.savedQuota, tC.errSavingQuota)
+
+			ctl := &ProjectQuotaController{
+				AppContext: &AppContext{
+					ProjectsService:     projectService,
+					ProjectQuotaService: quotaService,
+					ProjectQuotaConfig:  config.ProjectQuotaConfig{Admins: []string{"admin@caraml.dev"}},
+				},
+			}
+			req := &http.Request{Header: http.Header{}}
+			req.Header.Set("User-Email", tC.user)
+			resp := ctl.UpdateProjectQuota(req, map[string]string{"project_id": "1"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/caraml-dev/merlin/mlflow"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/imagebuilder"
 	internalValidator "github.com/caraml-dev/merlin/pkg/validator"
 	"github.com/caraml-dev/merlin/service"
 )
@@ -50,21 +51,39 @@ type AppContext struct {
 	DB       *gorm.DB
 	Enforcer enforcer.Enforcer
 
-	EnvironmentService        service.EnvironmentService
-	ProjectsService           service.ProjectsService
-	ModelsService             service.ModelsService
-	ModelEndpointsService     service.ModelEndpointsService
-	VersionsService           service.VersionsService
-	EndpointsService          service.EndpointsService
-	LogService                service.LogService
-	PredictionJobService      service.PredictionJobService
-	SecretService             service.SecretService
-	ModelEndpointAlertService service.ModelEndpointAlertService
-	TransformerService        service.TransformerService
+	EnvironmentService           service.EnvironmentService
+	ProjectsService              service.ProjectsService
+	ModelsService                service.ModelsService
+	ModelEndpointsService        service.ModelEndpointsService
+	VersionsService              service.VersionsService
+	EndpointsService             service.EndpointsService
+	LogService                   service.LogService
+	PredictionJobService         service.PredictionJobService
+	PredictionJobScheduleService service.PredictionJobScheduleService
+	SecretService                service.SecretService
+	ModelEndpointAlertService    service.ModelEndpointAlertService
+	TransformerService           service.TransformerService
+	ProjectQuotaService          service.ProjectQuotaService
+	InferenceGraphService        service.InferenceGraphService
+	WebhookService               service.WebhookService
+	TeamService                  service.TeamService
+
+	ImageBuilder              imagebuilder.ImageBuilder
+	PredictionJobImageBuilder imagebuilder.ImageBuilder
+
+	ResourceRecommendationService service.ResourceRecommendationService
+	GroundTruthService            service.GroundTruthService
+	DriftService                  service.DriftService
 
 	AuthorizationEnabled bool
 	AlertEnabled         bool
 	MonitoringConfig     config.MonitoringConfig
+	ProjectQuotaConfig   config.ProjectQuotaConfig
+	TeamRegistryConfig   config.TeamRegistryConfig
+
+	ResourceRecommendationEnabled bool
+	GroundTruthConfig             config.GroundTruthConfig
+	DriftConfig                   config.DriftConfig
 
 	StandardTransformerConfig config.StandardTransformerConfig
 
@@ -148,10 +167,18 @@ func NewRouter(appCtx AppContext) (*mux.Router, error) {
 	versionsController := VersionsController{&appCtx}
 	endpointsController := EndpointsController{&appCtx}
 	predictionJobController := PredictionJobController{&appCtx}
+	predictionJobScheduleController := PredictionJobScheduleController{&appCtx}
 	logController := LogController{&appCtx}
 	secretController := SecretsController{&appCtx}
+	projectQuotaController := ProjectQuotaController{&appCtx}
+	inferenceGraphController := InferenceGraphController{&appCtx}
+	webhookController := WebhookController{&appCtx}
+	teamController := TeamController{&appCtx}
 	alertsController := AlertsController{&appCtx}
 	transformerController := TransformerController{&appCtx}
+	imageBuildController := ImageBuildController{&appCtx}
+	groundTruthController := GroundTruthController{&appCtx}
+	driftController := DriftController{&appCtx}
 
 	routes := []Route{
 		// Environment API
@@ -167,6 +194,25 @@ func NewRouter(appCtx AppContext) (*mux.Router, error) {
 		{http.MethodPatch, "/projects/{project_id:[0-9]+}/secrets/{secret_id}", mlp.Secret{}, secretController.UpdateSecret, "UpdateSecret"},
 		{http.MethodDelete, "/projects/{project_id:[0-9]+}/secrets/{secret_id}", nil, secretController.DeleteSecret, "DeleteSecret"},
 
+		// Project Quota API
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/quota", nil, projectQuotaController.GetProjectQuota, "GetProjectQuota"},
+		{http.MethodPut, "/projects/{project_id:[0-9]+}/quota", models.ProjectQuota{}, projectQuotaController.UpdateProjectQuota, "UpdateProjectQuota"},
+
+		// Inference Graph API
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/inference_graphs", nil, inferenceGraphController.ListInferenceGraphs, "ListInferenceGraphs"},
+		{http.MethodPost, "/projects/{project_id:[0-9]+}/inference_graphs", models.InferenceGraph{}, inferenceGraphController.CreateInferenceGraph, "CreateInferenceGraph"},
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/inference_graphs/{inference_graph_id:[0-9]+}", nil, inferenceGraphController.GetInferenceGraph, "GetInferenceGraph"},
+		{http.MethodPut, "/projects/{project_id:[0-9]+}/inference_graphs/{inference_graph_id:[0-9]+}", models.InferenceGraph{}, inferenceGraphController.UpdateInferenceGraph, "UpdateInferenceGraph"},
+		{http.MethodDelete, "/projects/{project_id:[0-9]+}/inference_graphs/{inference_graph_id:[0-9]+}", nil, inferenceGraphController.DeleteInferenceGraph, "DeleteInferenceGraph"},
+
+		// Webhook API
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/webhooks", nil, webhookController.ListWebhooks, "ListWebhooks"},
+		{http.MethodPost, "/projects/{project_id:[0-9]+}/webhooks", models.Webhook{}, webhookController.CreateWebhook, "CreateWebhook"},
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/webhooks/{webhook_id:[0-9]+}", nil, webhookController.GetWebhook, "GetWebhook"},
+		{http.MethodPut, "/projects/{project_id:[0-9]+}/webhooks/{webhook_id:[0-9]+}", models.Webhook{}, webhookController.UpdateWebhook, "UpdateWebhook"},
+		{http.MethodDelete, "/projects/{project_id:[0-9]+}/webhooks/{webhook_id:[0-9]+}", nil, webhookController.DeleteWebhook, "DeleteWebhook"},
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/webhooks/{webhook_id:[0-9]+}/deliveries", nil, webhookController.ListWebhookDeliveries, "ListWebhookDeliveries"},
+
 		// Model API
 		{http.MethodGet, "/projects/{project_id:[0-9]+}/models/{model_id:[0-9]+}", nil, modelsController.GetModel, "GetModel"},
 		{http.MethodGet, "/projects/{project_id:[0-9]+}/models", nil, modelsController.ListModels, "ListModels"},
@@ -186,6 +232,10 @@ func NewRouter(appCtx AppContext) (*mux.Router, error) {
 		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}", nil, versionsController.GetVersion, "GetVersion"},
 		{http.MethodPatch, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}", models.VersionPatch{}, versionsController.PatchVersion, "PatchVersion"},
 
+		// Image Build API
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/image", nil, imageBuildController.GetBuild, "GetImageBuild"},
+		{http.MethodPost, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/image", nil, imageBuildController.StartBuild, "StartImageBuild"},
+
 		// Version Endpoint API
 		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint", nil, endpointsController.ListEndpoint, "ListEndpoint"},
 		{http.MethodPost, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint", models.VersionEndpoint{}, endpointsController.CreateEndpoint, "CreateEndpoint"},
@@ -196,6 +246,7 @@ func NewRouter(appCtx AppContext) (*mux.Router, error) {
 		{http.MethodPut, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint/{endpoint_id}", models.VersionEndpoint{}, endpointsController.UpdateEndpoint, "UpdateEndpoint"},
 		{http.MethodDelete, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint/{endpoint_id}", nil, endpointsController.DeleteEndpoint, "DeleteEndpoint"},
 		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint/{endpoint_id}/containers", nil, endpointsController.ListContainers, "ListContainers"},
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint/{endpoint_id}/events", nil, endpointsController.ListDeploymentEvents, "ListDeploymentEvents"},
 
 		// Prediction Job API
 		{http.MethodGet, "/projects/{project_id:[0-9]+}/jobs", nil, predictionJobController.ListAllInProject, "ListAllPredictionJobInProject"},
@@ -204,6 +255,19 @@ func NewRouter(appCtx AppContext) (*mux.Router, error) {
 		{http.MethodPut, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/jobs/{job_id:[0-9]+}/stop", nil, predictionJobController.Stop, "StopPredictionJob"},
 		{http.MethodPost, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/jobs", models.PredictionJob{}, predictionJobController.Create, "CreatePredictionJob"},
 		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/jobs/{job_id:[0-9]+}/containers", nil, predictionJobController.ListContainers, "ListJobContainers"},
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/jobs/{job_id:[0-9]+}/attempts", nil, predictionJobController.ListAttempts, "ListPredictionJobAttempts"},
+
+		// Prediction Job Schedule API
+		{http.MethodGet, "/projects/{project_id:[0-9]+}/schedules", nil, predictionJobScheduleController.ListAllInProject, "ListAllPredictionJobScheduleInProject"},
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules", nil, predictionJobScheduleController.List, "ListPredictionJobSchedule"},
+		{http.MethodPost, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules", models.PredictionJobSchedule{}, predictionJobScheduleController.Create, "CreatePredictionJobSchedule"},
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}", nil, predictionJobScheduleController.Get, "GetPredictionJobSchedule"},
+		{http.MethodPut, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}", models.PredictionJobSchedule{}, predictionJobScheduleController.Update, "UpdatePredictionJobSchedule"},
+		{http.MethodDelete, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}", nil, predictionJobScheduleController.Delete, "DeletePredictionJobSchedule"},
+		{http.MethodPut, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}/pause", nil, predictionJobScheduleController.Pause, "PausePredictionJobSchedule"},
+		{http.MethodPut, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}/resume", nil, predictionJobScheduleController.Resume, "ResumePredictionJobSchedule"},
+		{http.MethodPost, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}/trigger", nil, predictionJobScheduleController.Trigger, "TriggerPredictionJobSchedule"},
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/schedules/{schedule_id:[0-9]+}/runs", nil, predictionJobScheduleController.ListRuns, "ListPredictionJobScheduleRuns"},
 
 		// Standard Transformer Simulation API
 		{http.MethodPost, "/standard_transformer/simulate", models.TransformerSimulation{}, transformerController.SimulateTransformer, "SimulateTransformer"},
@@ -217,11 +281,46 @@ func NewRouter(appCtx AppContext) (*mux.Router, error) {
 			{http.MethodGet, "/models/{model_id:[0-9]+}/endpoints/{model_endpoint_id}/alert", nil, alertsController.GetModelEndpointAlert, "GetModelEndpointAlert"},
 			{http.MethodPost, "/models/{model_id:[0-9]+}/endpoints/{model_endpoint_id}/alert", models.ModelEndpointAlert{}, alertsController.CreateModelEndpointAlert, "CreateModelEndpointAlert"},
 			{http.MethodPut, "/models/{model_id:[0-9]+}/endpoints/{model_endpoint_id}/alert", models.ModelEndpointAlert{}, alertsController.UpdateModelEndpointAlert, "UpdateModelEndpointAlert"},
+			{http.MethodPost, "/models/{model_id:[0-9]+}/endpoints/{model_endpoint_id}/alert/preview", models.ModelEndpointAlert{}, alertsController.PreviewModelEndpointAlert, "PreviewModelEndpointAlert"},
+
+			// Team Registry API
+			{http.MethodGet, "/teams", nil, teamController.ListRegisteredTeams, "ListRegisteredTeams"},
+			{http.MethodPost, "/teams", models.Team{}, teamController.CreateTeam, "CreateTeam"},
+			{http.MethodGet, "/teams/{team_id:[0-9]+}", nil, teamController.GetTeam, "GetTeam"},
+			{http.MethodPut, "/teams/{team_id:[0-9]+}", models.Team{}, teamController.UpdateTeam, "UpdateTeam"},
+			{http.MethodDelete, "/teams/{team_id:[0-9]+}", nil, teamController.DeleteTeam, "DeleteTeam"},
+			{http.MethodGet, "/projects/{project_id:[0-9]+}/owner", nil, teamController.GetProjectOwner, "GetProjectOwner"},
+			{http.MethodPut, "/projects/{project_id:[0-9]+}/owner", models.ProjectOwner{}, teamController.UpdateProjectOwner, "UpdateProjectOwner"},
+		}...)
+	}
+
+	if appCtx.ResourceRecommendationEnabled {
+		routes = append(routes, []Route{
+			// Resource Recommendation API
+			{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/endpoint/{endpoint_id}/resource_recommendation", nil, endpointsController.GetResourceRecommendation, "GetResourceRecommendation"},
+		}...)
+	}
+
+	if appCtx.GroundTruthConfig.GroundTruthEnabled {
+		routes = append(routes, []Route{
+			// Ground Truth API
+			{http.MethodPost, "/models/{model_id:[0-9]+}/labels", models.PredictionLabels{}, groundTruthController.SubmitLabels, "SubmitPredictionLabels"},
+			{http.MethodGet, "/models/{model_id:[0-9]+}/endpoints/{model_endpoint_id}/performance", nil, groundTruthController.GetModelEndpointPerformance, "GetModelEndpointPerformance"},
+		}...)
+	}
+
+	if appCtx.DriftConfig.DriftEnabled {
+		routes = append(routes, []Route{
+			// Drift API
+			{http.MethodPut, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/drift/reference", models.DriftReference{}, driftController.SaveDriftReference, "SaveDriftReference"},
+			{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/drift/reference", nil, driftController.GetDriftReference, "GetDriftReference"},
+			{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/drift", nil, driftController.ListDriftResults, "ListDriftResults"},
 		}...)
 	}
 
 	rawRoutes := []RawRoutes{
 		{http.MethodGet, "/logs", http.HandlerFunc(logController.ReadLog), "ReadLogs"},
+		{http.MethodGet, "/models/{model_id:[0-9]+}/versions/{version_id:[0-9]+}/image/logs", http.HandlerFunc(imageBuildController.ReadBuildLog), "ReadImageBuildLogs"},
 	}
 
 	var authzMiddleware *middleware.Authorizer
@@ -0,0 +1,195 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// TeamController controls team registry API.
+type TeamController struct {
+	*AppContext
+}
+
+// ListRegisteredTeams lists all teams of the team registry.
+func (c *TeamController) ListRegisteredTeams(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	teams, err := c.TeamService.ListTeams(r.Context())
+	if err != nil {
+		log.Errorf("failed listing teams: %v", err)
+		return InternalServerError("Error while listing teams")
+	}
+	return Ok(teams)
+}
+
+// GetTeam gets a registered team.
+func (c *TeamController) GetTeam(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	team, resp := c.findTeam(r, vars)
+	if resp != nil {
+		return resp
+	}
+	return Ok(team)
+}
+
+// CreateTeam registers a new team. Only the configured team registry admins are allowed to manage the teams.
+func (c *TeamController) CreateTeam(r *http.Request, vars map[string]string, body interface{}) *Response {
+	if resp := c.checkAdmin(r); resp != nil {
+		return resp
+	}
+
+	team, ok := body.(*models.Team)
+	if !ok {
+		return BadRequest("Unable to parse body as team")
+	}
+
+	team, err := c.TeamService.CreateTeam(r.Context(), team)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed creating team: %v", err)
+		return InternalServerError("Error while creating team")
+	}
+	return Created(team)
+}
+
+// UpdateTeam updates a registered team.
+func (c *TeamController) UpdateTeam(r *http.Request, vars map[string]string, body interface{}) *Response {
+	if resp := c.checkAdmin(r); resp != nil {
+		return resp
+	}
+
+	newTeam, ok := body.(*models.Team)
+	if !ok {
+		return BadRequest("Unable to parse body as team")
+	}
+
+	team, resp := c.findTeam(r, vars)
+	if resp != nil {
+		return resp
+	}
+	newTeam.ID = team.ID
+
+	newTeam, err := c.TeamService.UpdateTeam(r.Context(), newTeam)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed updating team %d: %v", team.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while updating team %d", team.ID))
+	}
+	return Ok(newTeam)
+}
+
+// DeleteTeam deletes a registered team and the ownership of its projects.
+func (c *TeamController) DeleteTeam(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	if resp := c.checkAdmin(r); resp != nil {
+		return resp
+	}
+
+	team, resp := c.findTeam(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	if err := c.TeamService.DeleteTeam(r.Context(), team); err != nil {
+		log.Errorf("failed deleting team %d: %v", team.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while deleting team %d", team.ID))
+	}
+	return Ok(nil)
+}
+
+// GetProjectOwner gets the team owning a project.
+func (c *TeamController) GetProjectOwner(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	owner, err := c.TeamService.GetProjectOwner(ctx, projectID)
+	if err != nil {
+		log.Errorf("failed getting owner of project %d: %v", projectID, err)
+		return InternalServerError(fmt.Sprintf("Error while getting owner of project %d", projectID))
+	}
+	if owner == nil {
+		return NotFound(fmt.Sprintf("Project %d doesn't have an owner", projectID))
+	}
+	return Ok(owner)
+}
+
+// UpdateProjectOwner sets the team owning a project. Only the team registry admins are allowed to set it, since the
+// owner receives the alerts of the project.
+func (c *TeamController) UpdateProjectOwner(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	if resp := c.checkAdmin(r); resp != nil {
+		return resp
+	}
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	owner, ok := body.(*models.ProjectOwner)
+	if !ok {
+		return BadRequest("Unable to parse body as project owner")
+	}
+	owner.ProjectID = projectID
+
+	owner, err = c.TeamService.SetProjectOwner(ctx, owner)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed setting owner of project %d: %v", projectID, err)
+		return InternalServerError(fmt.Sprintf("Error while setting owner of project %d", projectID))
+	}
+	return Ok(owner)
+}
+
+// checkAdmin returns a forbidden response if the user isn't a team registry admin
+func (c *TeamController) checkAdmin(r *http.Request) *Response {
+	user := r.Header.Get("User-Email")
+	if !isAdmin(c.TeamRegistryConfig.Admins, user) {
+		return Forbidden(fmt.Sprintf("%s is not allowed to manage teams", user))
+	}
+	return nil
+}
+
+func (c *TeamController) findTeam(r *http.Request, vars map[string]string) (*models.Team, *Response) {
+	teamID, _ := models.ParseID(vars["team_id"])
+
+	team, err := c.TeamService.GetTeam(r.Context(), teamID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, NotFound(fmt.Sprintf("Team with given `team_id: %d` not found", teamID))
+		}
+		log.Errorf("failed getting team %d: %v", teamID, err)
+		return nil, InternalServerError(fmt.Sprintf("Error while getting team %d", teamID))
+	}
+	return team, nil
+}
@@ -0,0 +1,351 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/caraml-dev/mlp/api/client"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+var teamRegistryConfig = config.TeamRegistryConfig{Admins: []string{"admin@caraml.dev"}}
+
+// newTeamRequest returns a request of the user, or of a team registry admin if the user isn't set
+func newTeamRequest(user string) *http.Request {
+	if user == "" {
+		user = "admin@caraml.dev"
+	}
+	req := &http.Request{Header: http.Header{}}
+	req.Header.Set("User-Email", user)
+	return req
+}
+
+func TestCreateTeam(t *testing.T) {
+	team := &models.Team{ID: 1, Name: "datascience", Source: models.TeamSourceMerlin}
+
+	testCases := []struct {
+		desc          string
+		user          string
+		body          interface{}
+		savedTeam     *models.Team
+		errSavingTeam error
+		expected      *Response
+	}{
+		{
+			desc:      "Should create team",
+			body:      &models.Team{Name: "datascience"},
+			savedTeam: team,
+			expected: &Response{
+				code: http.StatusCreated,
+				data: team,
+			},
+		},
+		{
+			desc: "Should return forbidden if user is not a team registry admin",
+			user: "member@caraml.dev",
+			body: &models.Team{Name: "datascience"},
+			expected: &Response{
+				code: http.StatusForbidden,
+				data: Error{Message: "member@caraml.dev is not allowed to manage teams"},
+			},
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			body: &models.Model{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as team"},
+			},
+		},
+		{
+			desc:          "Should return bad request if team already exists",
+			body:          &models.Team{Name: "datascience"},
+			errSavingTeam: merror.NewInvalidInputError("team datascience already exists"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: team datascience already exists"},
+			},
+		},
+		{
+			desc:          "Should return internal server error if saving failed",
+			body:          &models.Team{Name: "datascience"},
+			errSavingTeam: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while creating team"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teamService := &mocks.TeamService{}
+			teamService.On("CreateTeam", mock.Anything, mock.Anything).Return(tC.savedTeam, tC.errSavingTeam)
+
+			ctl := &TeamController{
+				AppContext: &AppContext{
+					TeamService:        teamService,
+					TeamRegistryConfig: teamRegistryConfig,
+				},
+			}
+			resp := ctl.CreateTeam(newTeamRequest(tC.user), map[string]string{}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestUpdateTeam(t *testing.T) {
+	team := &models.Team{ID: 1, Name: "datascience", Source: models.TeamSourceWarden}
+
+	testCases := []struct {
+		desc          string
+		user          string
+		teamID        string
+		errGetting    error
+		body          interface{}
+		errSavingTeam error
+		expected      *Response
+	}{
+		{
+			desc:   "Should update team",
+			teamID: "1",
+			body:   &models.Team{Name: "datascience", Members: models.TeamMembers{"alice@example.com"}},
+			expected: &Response{
+				code: http.StatusOK,
+				data: &models.Team{ID: 1, Name: "datascience", Members: models.TeamMembers{"alice@example.com"}},
+			},
+		},
+		{
+			desc:   "Should return forbidden if user is not a team registry admin",
+			user:   "member@caraml.dev",
+			teamID: "1",
+			body:   &models.Team{Name: "datascience"},
+			expected: &Response{
+				code: http.StatusForbidden,
+				data: Error{Message: "member@caraml.dev is not allowed to manage teams"},
+			},
+		},
+		{
+			desc:       "Should return not found if team doesn't exist",
+			teamID:     "2",
+			errGetting: gorm.ErrRecordNotFound,
+			body:       &models.Team{Name: "datascience"},
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Team with given `team_id: 2` not found"},
+			},
+		},
+		{
+			desc:          "Should return bad request if team is invalid",
+			teamID:        "1",
+			body:          &models.Team{},
+			errSavingTeam: merror.NewInvalidInputError("team name is required"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: team name is required"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teamService := &mocks.TeamService{}
+			teamService.On("GetTeam", mock.Anything, mock.Anything).Return(team, tC.errGetting)
+			teamService.On("UpdateTeam", mock.Anything, mock.MatchedBy(func(team *models.Team) bool {
+				return team.ID == models.ID(1)
+			})).Return(func(_ context.Context, team *models.Team) *models.Team {
+				if tC.errSavingTeam != nil {
+					return nil
+				}
+				return team
+			}, tC.errSavingTeam)
+
+			ctl := &TeamController{
+				AppContext: &AppContext{
+					TeamService:        teamService,
+					TeamRegistryConfig: teamRegistryConfig,
+				},
+			}
+			resp := ctl.UpdateTeam(newTeamRequest(tC.user), map[string]string{"team_id": tC.teamID}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestDeleteTeam(t *testing.T) {
+	team := &models.Team{ID: 1, Name: "datascience"}
+
+	testCases := []struct {
+		desc     string
+		user     string
+		expected *Response
+	}{
+		{
+			desc: "Should delete team",
+			expected: &Response{
+				code: http.StatusOK,
+			},
+		},
+		{
+			desc: "Should return forbidden if user is not a team registry admin",
+			user: "member@caraml.dev",
+			expected: &Response{
+				code: http.StatusForbidden,
+				data: Error{Message: "member@caraml.dev is not allowed to manage teams"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teamService := &mocks.TeamService{}
+			teamService.On("GetTeam", mock.Anything, models.ID(1)).Return(team, nil)
+			teamService.On("DeleteTeam", mock.Anything, team).Return(nil)
+
+			ctl := &TeamController{
+				AppContext: &AppContext{
+					TeamService:        teamService,
+					TeamRegistryConfig: teamRegistryConfig,
+				},
+			}
+			resp := ctl.DeleteTeam(newTeamRequest(tC.user), map[string]string{"team_id": "1"}, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestGetProjectOwner(t *testing.T) {
+	owner := &models.ProjectOwner{ID: 1, ProjectID: 1, TeamID: 1, Team: &models.Team{ID: 1, Name: "datascience"}}
+
+	testCases := []struct {
+		desc     string
+		owner    *models.ProjectOwner
+		err      error
+		expected *Response
+	}{
+		{
+			desc:  "Should return owner",
+			owner: owner,
+			expected: &Response{
+				code: http.StatusOK,
+				data: owner,
+			},
+		},
+		{
+			desc: "Should return not found if project doesn't have an owner",
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: "Project 1 doesn't have an owner"},
+			},
+		},
+		{
+			desc: "Should return internal server error if owner is not available",
+			err:  errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while getting owner of project 1"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(mlp.Project(client.Project{ID: 1, Name: "sample"}), nil)
+
+			teamService := &mocks.TeamService{}
+			teamService.On("GetProjectOwner", mock.Anything, models.ID(1)).Return(tC.owner, tC.err)
+
+			ctl := &TeamController{
+				AppContext: &AppContext{
+					ProjectsService: projectService,
+					TeamService:     teamService,
+				},
+			}
+			resp := ctl.GetProjectOwner(&http.Request{}, map[string]string{"project_id": "1"}, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestUpdateProjectOwner(t *testing.T) {
+	owner := &models.ProjectOwner{ID: 1, ProjectID: 1, TeamID: 1, Team: &models.Team{ID: 1, Name: "datascience"}}
+
+	testCases := []struct {
+		desc     string
+		user     string
+		body     interface{}
+		owner    *models.ProjectOwner
+		err      error
+		expected *Response
+	}{
+		{
+			desc:  "Should set owner",
+			body:  &models.ProjectOwner{TeamID: 1},
+			owner: owner,
+			expected: &Response{
+				code: http.StatusOK,
+				data: owner,
+			},
+		},
+		{
+			desc: "Should return forbidden if user is not a team registry admin",
+			user: "member@caraml.dev",
+			body: &models.ProjectOwner{TeamID: 1},
+			expected: &Response{
+				code: http.StatusForbidden,
+				data: Error{Message: "member@caraml.dev is not allowed to manage teams"},
+			},
+		},
+		{
+			desc: "Should return bad request if team doesn't exist",
+			body: &models.ProjectOwner{TeamID: 9},
+			err:  merror.NewInvalidInputError("team 9 not found"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: team 9 not found"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(mlp.Project(client.Project{ID: 1, Name: "sample"}), nil)
+
+			teamService := &mocks.TeamService{}
+			teamService.On("SetProjectOwner", mock.Anything, mock.MatchedBy(func(owner *models.ProjectOwner) bool {
+				return owner.ProjectID == models.ID(1)
+			})).Return(tC.owner, tC.err)
+
+			ctl := &TeamController{
+				AppContext: &AppContext{
+					ProjectsService:    projectService,
+					TeamService:        teamService,
+					TeamRegistryConfig: teamRegistryConfig,
+				},
+			}
+			resp := ctl.UpdateProjectOwner(newTeamRequest(tC.user), map[string]string{"project_id": "1"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
@@ -45,3 +45,16 @@ func (c *AppContext) getModelAndVersion(ctx context.Context, modelID models.ID,
 
 	return model, version, nil
 }
+
+// isAdmin returns whether the user is one of the configured admins
+func isAdmin(admins []string, user string) bool {
+	if user == "" {
+		return false
+	}
+	for _, admin := range admins {
+		if admin == user {
+			return true
+		}
+	}
+	return false
+}
@@ -29,10 +29,12 @@ import (
 	"github.com/caraml-dev/merlin/config"
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/prometheus"
 	"github.com/caraml-dev/merlin/pkg/transformer"
 	"github.com/caraml-dev/merlin/pkg/transformer/feast"
 	"github.com/caraml-dev/merlin/pkg/transformer/pipeline"
 	"github.com/caraml-dev/merlin/pkg/transformer/spec"
+	"github.com/caraml-dev/merlin/service"
 )
 
 type EndpointsController struct {
@@ -71,6 +73,10 @@ func (c *EndpointsController) ListEndpoint(r *http.Request, vars map[string]stri
 			})
 		}
 	}
+
+	for k := range endpoints {
+		endpoints[k].UpdateCostEstimation()
+	}
 	return Ok(endpoints)
 }
 
@@ -110,6 +116,7 @@ func (c *EndpointsController) GetEndpoint(r *http.Request, vars map[string]strin
 		})
 	}
 
+	endpoint.UpdateCostEstimation()
 	return Ok(endpoint)
 }
 
@@ -191,6 +198,11 @@ func (c *EndpointsController) CreateEndpoint(r *http.Request, vars map[string]st
 		return BadRequest(fmt.Sprintf("Max deployed endpoint reached. Max: %d Current: %d, undeploy existing endpoint before continuing", config.MaxDeployedVersion, deployedModelVersionCount))
 	}
 
+	// validate logger
+	if err := newEndpoint.Logger.Validate(); err != nil {
+		return BadRequest(err.Error())
+	}
+
 	// validate transformer
 	if newEndpoint.Transformer != nil && newEndpoint.Transformer.Enabled {
 		err := c.validateTransformer(ctx, newEndpoint.Transformer, newEndpoint.Protocol, newEndpoint.Logger)
@@ -265,6 +277,11 @@ func (c *EndpointsController) UpdateEndpoint(r *http.Request, vars map[string]st
 	}
 
 	if newEndpoint.Status == models.EndpointRunning || newEndpoint.Status == models.EndpointServing {
+		// validate logger
+		if err := newEndpoint.Logger.Validate(); err != nil {
+			return BadRequest(err.Error())
+		}
+
 		// validate transformer
 		if newEndpoint.Transformer != nil && newEndpoint.Transformer.Enabled {
 			err := c.validateTransformer(ctx, newEndpoint.Transformer, newEndpoint.Protocol, newEndpoint.Logger)
@@ -388,6 +405,92 @@ func (c *EndpointsController) ListContainers(r *http.Request, vars map[string]st
 	return Ok(endpoint)
 }
 
+// GetResourceRecommendation returns the right-sized resource request of an endpoint based on its historical usage
+func (c *EndpointsController) GetResourceRecommendation(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+	endpointID, _ := uuid.Parse(vars["endpoint_id"])
+
+	_, _, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			return InternalServerError(err.Error())
+		}
+		return NotFound(err.Error())
+	}
+
+	endpoint, err := c.EndpointsService.FindByID(ctx, endpointID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Version endpoint with id %s not found", endpointID))
+		}
+		return InternalServerError(fmt.Sprintf("Error while getting version endpoint with id %s", endpointID))
+	}
+
+	if !endpoint.IsRunning() && !endpoint.IsServing() {
+		return BadRequest(fmt.Sprintf("Version endpoint %s is not running", endpointID))
+	}
+
+	recommendation, err := c.ResourceRecommendationService.RecommendResourceRequest(ctx, endpoint)
+	if err != nil {
+		log.Errorf("Error computing resource recommendation for endpoint %s, reason: %v", endpointID, err)
+		if errors.Is(err, prometheus.ErrNoData) {
+			return NotFound(fmt.Sprintf("Resource usage of version endpoint %s is not available", endpointID))
+		}
+		return InternalServerError(fmt.Sprintf("Error while computing resource recommendation for version endpoint with id %s", endpointID))
+	}
+
+	return Ok(recommendation)
+}
+
+// ListDeploymentEvents returns the deployment timeline of an endpoint from the latest event, with the cursor of the next page in the Next-Cursor header
+func (c *EndpointsController) ListDeploymentEvents(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	modelID, _ := models.ParseID(vars["model_id"])
+	versionID, _ := models.ParseID(vars["version_id"])
+	endpointID, _ := uuid.Parse(vars["endpoint_id"])
+
+	var query service.DeploymentEventQuery
+	if err := decoder.Decode(&query, r.URL.Query()); err != nil {
+		log.Errorf("Error while parsing query string %v", err)
+		return BadRequest(fmt.Sprintf("Unable to parse query string: %s", err))
+	}
+
+	_, _, err := c.getModelAndVersion(ctx, modelID, versionID)
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			return InternalServerError(err.Error())
+		}
+		return NotFound(err.Error())
+	}
+
+	endpoint, err := c.EndpointsService.FindByID(ctx, endpointID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return NotFound(fmt.Sprintf("Version endpoint with id %s not found", endpointID))
+		}
+		return InternalServerError(fmt.Sprintf("Error while getting version endpoint with id %s", endpointID))
+	}
+
+	events, nextCursor, err := c.EndpointsService.ListDeploymentEvents(ctx, endpoint.ID, query)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("Error listing deployment events of endpoint %s, reason: %v", endpointID, err)
+		return InternalServerError(fmt.Sprintf("Error while listing deployment events of version endpoint with id %s", endpointID))
+	}
+
+	responseHeaders := make(map[string]string)
+	if nextCursor != "" {
+		responseHeaders["Next-Cursor"] = nextCursor
+	}
+	return OkWithHeaders(events, responseHeaders)
+}
+
 func validateUpdateRequest(prev *models.VersionEndpoint, new *models.VersionEndpoint) error {
 	if prev.EnvironmentName != new.EnvironmentName {
 		return fmt.Errorf("Updating environment is not allowed, previous: %s, new: %s", prev.EnvironmentName, new.EnvironmentName)
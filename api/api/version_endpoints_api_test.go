@@ -18,15 +18,19 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/caraml-dev/merlin/config"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
 	"github.com/caraml-dev/merlin/pkg/deployment"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/pkg/prometheus"
 	"github.com/caraml-dev/merlin/pkg/protocol"
 	"github.com/caraml-dev/merlin/pkg/transformer"
 	feastmocks "github.com/caraml-dev/merlin/pkg/transformer/feast/mocks"
+	"github.com/caraml-dev/merlin/service"
 	"github.com/caraml-dev/merlin/service/mocks"
 	"github.com/caraml-dev/mlp/api/client"
 	"github.com/feast-dev/feast/sdk/go/protos/feast/core"
@@ -5404,3 +5408,286 @@ func TestDeleteEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceRecommendation(t *testing.T) {
+	uuid := uuid.New()
+	vars := map[string]string{
+		"model_id":    "1",
+		"version_id":  "1",
+		"endpoint_id": uuid.String(),
+	}
+	endpoint := &models.VersionEndpoint{
+		ID:                   uuid,
+		VersionID:            models.ID(1),
+		VersionModelID:       models.ID(1),
+		Status:               models.EndpointRunning,
+		InferenceServiceName: "model-1-1",
+		Namespace:            "sample",
+		EnvironmentName:      "dev",
+		ResourceRequest: &models.ResourceRequest{
+			MinReplica:    1,
+			MaxReplica:    4,
+			CPURequest:    resource.MustParse("1"),
+			MemoryRequest: resource.MustParse("1Gi"),
+		},
+	}
+	recommendation := &models.EndpointResourceRecommendation{
+		LookbackWindow: "1w",
+		Model: &models.ResourceRecommendation{
+			CurrentResourceRequest: endpoint.ResourceRequest,
+			RecommendedResourceRequest: &models.ResourceRequest{
+				MinReplica:    1,
+				MaxReplica:    4,
+				CPURequest:    resource.MustParse("200m"),
+				MemoryRequest: resource.MustParse("300Mi"),
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc                          string
+		endpointService               func() *mocks.EndpointsService
+		resourceRecommendationService func() *mocks.ResourceRecommendationService
+		expected                      *Response
+	}{
+		{
+			desc: "Should success get resource recommendation",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), uuid).Return(endpoint, nil)
+				return svc
+			},
+			resourceRecommendationService: func() *mocks.ResourceRecommendationService {
+				svc := &mocks.ResourceRecommendationService{}
+				svc.On("RecommendResourceRequest", context.Background(), endpoint).Return(recommendation, nil)
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusOK,
+				data: recommendation,
+			},
+		},
+		{
+			desc: "Should return bad request if endpoint is not running",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), uuid).Return(&models.VersionEndpoint{
+					ID:     uuid,
+					Status: models.EndpointTerminated,
+				}, nil)
+				return svc
+			},
+			resourceRecommendationService: func() *mocks.ResourceRecommendationService {
+				return &mocks.ResourceRecommendationService{}
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: fmt.Sprintf("Version endpoint %s is not running", uuid)},
+			},
+		},
+		{
+			desc: "Should return not found if usage data is not available",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), uuid).Return(endpoint, nil)
+				return svc
+			},
+			resourceRecommendationService: func() *mocks.ResourceRecommendationService {
+				svc := &mocks.ResourceRecommendationService{}
+				svc.On("RecommendResourceRequest", context.Background(), endpoint).Return(nil, fmt.Errorf("unable to query cpu usage: %w", prometheus.ErrNoData))
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: fmt.Sprintf("Resource usage of version endpoint %s is not available", uuid)},
+			},
+		},
+		{
+			desc: "Should return internal server error if recommendation failed",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), uuid).Return(endpoint, nil)
+				return svc
+			},
+			resourceRecommendationService: func() *mocks.ResourceRecommendationService {
+				svc := &mocks.ResourceRecommendationService{}
+				svc.On("RecommendResourceRequest", context.Background(), endpoint).Return(nil, fmt.Errorf("connection refused"))
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: fmt.Sprintf("Error while computing resource recommendation for version endpoint with id %s", uuid)},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelSvc := &mocks.ModelsService{}
+			modelSvc.On("FindByID", context.Background(), models.ID(1)).Return(&models.Model{
+				ID:        models.ID(1),
+				Name:      "model-1",
+				ProjectID: models.ID(1),
+				Type:      "pyfunc",
+			}, nil)
+
+			versionSvc := &mocks.VersionsService{}
+			versionSvc.On("FindByID", context.Background(), models.ID(1), models.ID(1), mock.Anything).Return(&models.Version{
+				ID:      models.ID(1),
+				ModelID: models.ID(1),
+			}, nil)
+
+			ctl := &EndpointsController{
+				AppContext: &AppContext{
+					ModelsService:                 modelSvc,
+					VersionsService:               versionSvc,
+					EndpointsService:              tC.endpointService(),
+					ResourceRecommendationService: tC.resourceRecommendationService(),
+				},
+			}
+			resp := ctl.GetResourceRecommendation(&http.Request{}, vars, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestListDeploymentEvents(t *testing.T) {
+	endpointID := uuid.New()
+	vars := map[string]string{
+		"model_id":    "1",
+		"version_id":  "1",
+		"endpoint_id": endpointID.String(),
+	}
+	endpoint := &models.VersionEndpoint{
+		ID:             endpointID,
+		VersionID:      models.ID(1),
+		VersionModelID: models.ID(1),
+		Status:         models.EndpointFailed,
+	}
+	events := []*models.DeploymentEvent{
+		{
+			ID:                models.ID(2),
+			VersionEndpointID: endpointID,
+			Source:            models.DeploymentEventSourceWorker,
+			Type:              models.DeploymentEventTypeWarning,
+			Reason:            "DeploymentFailed",
+			Message:           "timeout creating inference service",
+			FailureClass:      models.FailureClassReadinessTimeout,
+		},
+		{
+			ID:                models.ID(1),
+			VersionEndpointID: endpointID,
+			Source:            models.DeploymentEventSourceKubernetes,
+			Type:              models.DeploymentEventTypeWarning,
+			Reason:            "OOMKilled",
+			Message:           "container kserve-container terminated with exit code 137",
+			Object:            "pod/model-1-1-predictor-abc",
+			FailureClass:      models.FailureClassOOMKilled,
+		},
+	}
+	query := service.DeploymentEventQuery{
+		PaginationQuery: service.PaginationQuery{Limit: 2},
+		Type:            models.DeploymentEventTypeWarning,
+	}
+
+	testCases := []struct {
+		desc            string
+		rawQuery        string
+		endpointService func() *mocks.EndpointsService
+		expected        *Response
+	}{
+		{
+			desc:     "Should return the events with the cursor of the next page",
+			rawQuery: "limit=2&type=warning",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), endpointID).Return(endpoint, nil)
+				svc.On("ListDeploymentEvents", context.Background(), endpointID, query).Return(events, "next-cursor", nil)
+				return svc
+			},
+			expected: &Response{
+				code:    http.StatusOK,
+				data:    events,
+				headers: map[string]string{"Next-Cursor": "next-cursor"},
+			},
+		},
+		{
+			desc:     "Should return bad request for invalid query",
+			rawQuery: "limit=abc",
+			endpointService: func() *mocks.EndpointsService {
+				return &mocks.EndpointsService{}
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse query string: schema: error converting value for \"limit\""},
+			},
+		},
+		{
+			desc:     "Should return bad request for unknown event type",
+			rawQuery: "limit=2&type=warning",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), endpointID).Return(endpoint, nil)
+				svc.On("ListDeploymentEvents", context.Background(), endpointID, query).
+					Return(nil, "", merror.NewInvalidInputError("unknown deployment event type: warning"))
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: unknown deployment event type: warning"},
+			},
+		},
+		{
+			desc:     "Should return not found if endpoint doesn't exist",
+			rawQuery: "",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), endpointID).Return(nil, gorm.ErrRecordNotFound)
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusNotFound,
+				data: Error{Message: fmt.Sprintf("Version endpoint with id %s not found", endpointID)},
+			},
+		},
+		{
+			desc:     "Should return internal server error if listing failed",
+			rawQuery: "limit=2&type=warning",
+			endpointService: func() *mocks.EndpointsService {
+				svc := &mocks.EndpointsService{}
+				svc.On("FindByID", context.Background(), endpointID).Return(endpoint, nil)
+				svc.On("ListDeploymentEvents", context.Background(), endpointID, query).Return(nil, "", fmt.Errorf("connection refused"))
+				return svc
+			},
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: fmt.Sprintf("Error while listing deployment events of version endpoint with id %s", endpointID)},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelSvc := &mocks.ModelsService{}
+			modelSvc.On("FindByID", context.Background(), models.ID(1)).Return(&models.Model{
+				ID:        models.ID(1),
+				Name:      "model-1",
+				ProjectID: models.ID(1),
+			}, nil)
+
+			versionSvc := &mocks.VersionsService{}
+			versionSvc.On("FindByID", context.Background(), models.ID(1), models.ID(1), mock.Anything).Return(&models.Version{
+				ID:      models.ID(1),
+				ModelID: models.ID(1),
+			}, nil)
+
+			ctl := &EndpointsController{
+				AppContext: &AppContext{
+					ModelsService:    modelSvc,
+					VersionsService:  versionSvc,
+					EndpointsService: tC.endpointService(),
+				},
+			}
+			resp := ctl.ListDeploymentEvents(&http.Request{URL: &url.URL{RawQuery: tC.rawQuery}}, vars, nil)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
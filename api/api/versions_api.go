@@ -15,13 +15,16 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/jinzhu/gorm"
 
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/imagebuilder"
 	"github.com/caraml-dev/merlin/service"
 	"github.com/caraml-dev/merlin/utils"
 )
@@ -74,6 +77,14 @@ func (c *VersionsController) PatchVersion(r *http.Request, vars map[string]strin
 		return BadRequest(err.Error())
 	}
 
+	if versionPatch.BuildConfig != nil && v.Model.Type == models.ModelTypePyFunc {
+		buildInputs, err := c.getBuildInputs(v)
+		if err != nil {
+			return BadRequest(fmt.Sprintf("Invalid build config: %s", err))
+		}
+		v.BuildInputs = buildInputs
+	}
+
 	patchedVersion, err := c.VersionsService.Save(ctx, v, c.MonitoringConfig)
 	if err != nil {
 		return InternalServerError(fmt.Sprintf("Error patching model version for given model %s version %s", modelID, versionID))
@@ -128,19 +139,35 @@ func (c *VersionsController) CreateVersion(r *http.Request, vars map[string]stri
 		return NotFound(fmt.Sprintf("Model with given `model_id: %d` not found", modelID))
 	}
 
-	run, err := c.MlflowClient.CreateRun(fmt.Sprintf("%d", model.ExperimentID))
-	if err != nil {
-		return InternalServerError(fmt.Sprintf("Unable to create mlflow run: %s", err.Error()))
-	}
-
 	version := &models.Version{
 		ModelID:       modelID,
-		RunID:         run.Info.RunID,
-		ArtifactURI:   run.Info.ArtifactURI,
 		Labels:        versionPost.Labels,
 		PythonVersion: versionPost.PythonVersion,
 	}
 
+	if versionPost.BuildConfig != nil {
+		if model.Type != models.ModelTypePyFunc {
+			return BadRequest(fmt.Sprintf("Build config is only supported by %s model", models.ModelTypePyFunc))
+		}
+		if err := versionPost.BuildConfig.Validate(); err != nil {
+			return BadRequest(fmt.Sprintf("Invalid build config: %s", err))
+		}
+
+		version.BuildConfig = versionPost.BuildConfig
+		buildInputs, err := c.getBuildInputs(version)
+		if err != nil {
+			return BadRequest(fmt.Sprintf("Invalid build config: %s", err))
+		}
+		version.BuildInputs = buildInputs
+	}
+
+	run, err := c.MlflowClient.CreateRun(fmt.Sprintf("%d", model.ExperimentID))
+	if err != nil {
+		return InternalServerError(fmt.Sprintf("Unable to create mlflow run: %s", err.Error()))
+	}
+	version.RunID = run.Info.RunID
+	version.ArtifactURI = run.Info.ArtifactURI
+
 	version, _ = c.VersionsService.Save(ctx, version, c.MonitoringConfig)
 	return Created(version)
 }
@@ -162,3 +189,30 @@ func validateLabels(labels models.KV) bool {
 	}
 	return true
 }
+
+// getBuildInputs validates the build config of the version against the base images allowed for both model services and
+// prediction jobs. The build inputs of model services are recorded unless the base image is only allowed for prediction jobs.
+func (c *VersionsController) getBuildInputs(version *models.Version) (*models.VersionBuildInputs, error) {
+	buildInputs, err := c.ImageBuilder.GetBuildInputs(version)
+	var notAllowed imagebuilder.ErrBaseImageNotAllowed
+	if err == nil || c.PredictionJobImageBuilder == nil || !errors.As(err, &notAllowed) {
+		return buildInputs, err
+	}
+
+	buildInputs, err = c.PredictionJobImageBuilder.GetBuildInputs(version)
+	var predJobNotAllowed imagebuilder.ErrBaseImageNotAllowed
+	if err == nil || !errors.As(err, &predJobNotAllowed) {
+		return buildInputs, err
+	}
+
+	allowed := make(map[string]bool)
+	for _, name := range append(notAllowed.AllowedBaseImages, predJobNotAllowed.AllowedBaseImages...) {
+		allowed[name] = true
+	}
+	notAllowed.AllowedBaseImages = make([]string, 0, len(allowed))
+	for name := range allowed {
+		notAllowed.AllowedBaseImages = append(notAllowed.AllowedBaseImages, name)
+	}
+	sort.Strings(notAllowed.AllowedBaseImages)
+	return nil, notAllowed
+}
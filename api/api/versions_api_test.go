@@ -15,6 +15,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -25,6 +26,8 @@ import (
 	mlfmocks "github.com/caraml-dev/merlin/mlflow/mocks"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/imagebuilder"
+	imagebuildermock "github.com/caraml-dev/merlin/pkg/imagebuilder/mocks"
 	"github.com/caraml-dev/merlin/service/mocks"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
@@ -1163,3 +1166,176 @@ func TestCreateVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateVersionWithBuildConfig(t *testing.T) {
+	buildConfig := &models.VersionBuildConfig{
+		BaseImage:   "gdal",
+		AptPackages: []string{"libgomp1"},
+		BuildArgs:   map[string]string{"PIP_INDEX_URL": "https://pypi.example.com/simple"},
+	}
+	buildInputs := &models.VersionBuildInputs{
+		BaseImage:       "ghcr.io/caraml-dev/merlin/pyfunc-gdal:1.0.0",
+		DockerfilePath:  "./Dockerfile",
+		BuildContextURI: "gs://bucket/build.tar.gz",
+		AptPackages:     []string{"libgomp1"},
+		BuildArgs:       map[string]string{"PIP_INDEX_URL": "https://pypi.example.com/simple"},
+	}
+
+	testCases := []struct {
+		desc                string
+		modelType           string
+		buildConfig         *models.VersionBuildConfig
+		imageBuilder        func() *imagebuildermock.ImageBuilder
+		predJobImageBuilder func() *imagebuildermock.ImageBuilder
+		expected            *Response
+	}{
+		{
+			desc:        "Should record the build inputs of the version",
+			modelType:   models.ModelTypePyFunc,
+			buildConfig: buildConfig,
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("GetBuildInputs", mock.Anything).Return(buildInputs, nil)
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: &models.Version{
+					ModelID:       models.ID(1),
+					RunID:         "1",
+					ArtifactURI:   "artifact/url/run",
+					PythonVersion: "3.10.*",
+					BuildConfig:   buildConfig,
+					BuildInputs:   buildInputs,
+				},
+			},
+		},
+		{
+			desc:        "Should record the build inputs of a base image only allowed for prediction jobs",
+			modelType:   models.ModelTypePyFunc,
+			buildConfig: buildConfig,
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("GetBuildInputs", mock.Anything).Return(nil, imagebuilder.ErrBaseImageNotAllowed{BaseImage: "gdal", AllowedBaseImages: []string{"cuda"}})
+				return ib
+			},
+			predJobImageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("GetBuildInputs", mock.Anything).Return(buildInputs, nil)
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: &models.Version{
+					ModelID:       models.ID(1),
+					RunID:         "1",
+					ArtifactURI:   "artifact/url/run",
+					PythonVersion: "3.10.*",
+					BuildConfig:   buildConfig,
+					BuildInputs:   buildInputs,
+				},
+			},
+		},
+		{
+			desc:        "Should return 400 if the base image is not allowed",
+			modelType:   models.ModelTypePyFunc,
+			buildConfig: &models.VersionBuildConfig{BaseImage: "tpu"},
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("GetBuildInputs", mock.Anything).Return(nil, imagebuilder.ErrBaseImageNotAllowed{BaseImage: "tpu", AllowedBaseImages: []string{"cuda", "gdal"}})
+				return ib
+			},
+			predJobImageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("GetBuildInputs", mock.Anything).Return(nil, imagebuilder.ErrBaseImageNotAllowed{BaseImage: "tpu", AllowedBaseImages: []string{"gdal", "spark"}})
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Invalid build config: base image tpu is not allowed, allowed base images: [cuda, gdal, spark]"},
+			},
+		},
+		{
+			desc:        "Should return 400 if the python version has no base image",
+			modelType:   models.ModelTypePyFunc,
+			buildConfig: &models.VersionBuildConfig{AptPackages: []string{"libgomp1"}},
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				ib := &imagebuildermock.ImageBuilder{}
+				ib.On("GetBuildInputs", mock.Anything).Return(nil, fmt.Errorf("No matching base image for tag 3.10.*"))
+				return ib
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Invalid build config: No matching base image for tag 3.10.*"},
+			},
+		},
+		{
+			desc:        "Should return 400 if a build arg is reserved",
+			modelType:   models.ModelTypePyFunc,
+			buildConfig: &models.VersionBuildConfig{BuildArgs: map[string]string{"MODEL_URL": "gs://bucket/model"}},
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				return &imagebuildermock.ImageBuilder{}
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Invalid build config: build arg MODEL_URL is set by merlin and can't be overridden"},
+			},
+		},
+		{
+			desc:        "Should return 400 if the model is not pyfunc",
+			modelType:   models.ModelTypeSkLearn,
+			buildConfig: buildConfig,
+			imageBuilder: func() *imagebuildermock.ImageBuilder {
+				return &imagebuildermock.ImageBuilder{}
+			},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Build config is only supported by pyfunc model"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			modelsSvc := &mocks.ModelsService{}
+			modelsSvc.On("FindByID", mock.Anything, models.ID(1)).Return(&models.Model{
+				ID:           models.ID(1),
+				Name:         "model-1",
+				ProjectID:    models.ID(1),
+				ExperimentID: 1,
+				Type:         tC.modelType,
+			}, nil)
+			mlflowClient := &mlfmocks.Client{}
+			mlflowClient.On("CreateRun", "1").Return(&mlflow.Run{
+				Info: mlflow.Info{
+					RunID:       "1",
+					ArtifactURI: "artifact/url/run",
+				},
+			}, nil)
+			versionSvc := &mocks.VersionsService{}
+			versionSvc.On("Save", mock.Anything, mock.Anything, mock.Anything).Return(
+				func(_ context.Context, version *models.Version, _ config.MonitoringConfig) *models.Version {
+					return version
+				}, nil)
+
+			predJobImageBuilder := &imagebuildermock.ImageBuilder{}
+			if tC.predJobImageBuilder != nil {
+				predJobImageBuilder = tC.predJobImageBuilder()
+			}
+
+			ctl := &VersionsController{
+				AppContext: &AppContext{
+					VersionsService:           versionSvc,
+					MlflowClient:              mlflowClient,
+					ModelsService:             modelsSvc,
+					ImageBuilder:              tC.imageBuilder(),
+					PredictionJobImageBuilder: predJobImageBuilder,
+				},
+			}
+			resp := ctl.CreateVersion(&http.Request{}, map[string]string{"model_id": "1"}, &models.VersionPost{
+				PythonVersion: "3.10.*",
+				BuildConfig:   tC.buildConfig,
+			})
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
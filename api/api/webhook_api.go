@@ -0,0 +1,175 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+)
+
+// WebhookController controls webhook API.
+type WebhookController struct {
+	*AppContext
+}
+
+// ListWebhooks lists all webhooks of a project.
+func (c *WebhookController) ListWebhooks(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	webhooks, err := c.WebhookService.ListWebhooks(ctx, projectID)
+	if err != nil {
+		log.Errorf("failed listing webhooks of project %d: %v", projectID, err)
+		return InternalServerError(fmt.Sprintf("Error while listing webhooks of project %d", projectID))
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return Ok(webhooks)
+}
+
+// GetWebhook gets a webhook of a project.
+func (c *WebhookController) GetWebhook(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	webhook, resp := c.findWebhook(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	webhook.Secret = ""
+	return Ok(webhook)
+}
+
+// CreateWebhook creates a new webhook in a project.
+func (c *WebhookController) CreateWebhook(r *http.Request, vars map[string]string, body interface{}) *Response {
+	ctx := r.Context()
+
+	projectID, _ := models.ParseID(vars["project_id"])
+	_, err := c.ProjectsService.GetByID(ctx, int32(projectID))
+	if err != nil {
+		return NotFound(fmt.Sprintf("Project with given `project_id: %d` not found", projectID))
+	}
+
+	webhook, ok := body.(*models.Webhook)
+	if !ok {
+		return BadRequest("Unable to parse body as webhook")
+	}
+	webhook.ID = 0
+	webhook.ProjectID = projectID
+
+	return c.saveWebhook(r, webhook, Created)
+}
+
+// UpdateWebhook updates an existing webhook. The secret is kept if it's not given.
+func (c *WebhookController) UpdateWebhook(r *http.Request, vars map[string]string, body interface{}) *Response {
+	newWebhook, ok := body.(*models.Webhook)
+	if !ok {
+		return BadRequest("Unable to parse body as webhook")
+	}
+
+	webhook, resp := c.findWebhook(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	webhook.Name = newWebhook.Name
+	webhook.URL = newWebhook.URL
+	webhook.EventTypes = newWebhook.EventTypes
+	if newWebhook.Secret != "" {
+		webhook.Secret = newWebhook.Secret
+	}
+
+	return c.saveWebhook(r, webhook, Ok)
+}
+
+// DeleteWebhook deletes a webhook and its delivery log.
+func (c *WebhookController) DeleteWebhook(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	webhook, resp := c.findWebhook(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	if err := c.WebhookService.DeleteWebhook(r.Context(), webhook); err != nil {
+		log.Errorf("failed deleting webhook %d: %v", webhook.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while deleting webhook %d", webhook.ID))
+	}
+	return Ok(nil)
+}
+
+// ListWebhookDeliveries lists the latest deliveries of a webhook.
+func (c *WebhookController) ListWebhookDeliveries(r *http.Request, vars map[string]string, _ interface{}) *Response {
+	webhook, resp := c.findWebhook(r, vars)
+	if resp != nil {
+		return resp
+	}
+
+	limit := 0
+	if vars["limit"] != "" {
+		var err error
+		limit, err = strconv.Atoi(vars["limit"])
+		if err != nil || limit < 0 {
+			return BadRequest(fmt.Sprintf("Invalid limit: %s", vars["limit"]))
+		}
+	}
+
+	deliveries, err := c.WebhookService.ListDeliveries(r.Context(), webhook, limit)
+	if err != nil {
+		log.Errorf("failed listing deliveries of webhook %d: %v", webhook.ID, err)
+		return InternalServerError(fmt.Sprintf("Error while listing deliveries of webhook %d", webhook.ID))
+	}
+	return Ok(deliveries)
+}
+
+func (c *WebhookController) findWebhook(r *http.Request, vars map[string]string) (*models.Webhook, *Response) {
+	projectID, _ := models.ParseID(vars["project_id"])
+	webhookID, _ := models.ParseID(vars["webhook_id"])
+
+	webhook, err := c.WebhookService.FindByID(r.Context(), projectID, webhookID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, NotFound(fmt.Sprintf("Webhook with given `webhook_id: %d` not found", webhookID))
+		}
+		log.Errorf("failed getting webhook %d: %v", webhookID, err)
+		return nil, InternalServerError(fmt.Sprintf("Error while getting webhook %d", webhookID))
+	}
+	return webhook, nil
+}
+
+func (c *WebhookController) saveWebhook(r *http.Request, webhook *models.Webhook, success func(interface{}) *Response) *Response {
+	saved, err := c.WebhookService.SaveWebhook(r.Context(), webhook)
+	if err != nil {
+		if errors.Is(err, merror.InvalidInputError) {
+			return BadRequest(err.Error())
+		}
+		log.Errorf("failed saving webhook of project %d: %v", webhook.ProjectID, err)
+		return InternalServerError("Error while saving webhook")
+	}
+
+	saved.Secret = ""
+	return success(saved)
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/caraml-dev/mlp/api/client"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		body     interface{}
+		errSave  error
+		expected *Response
+	}{
+		{
+			desc: "Should create webhook without returning the secret",
+			body: &models.Webhook{Name: "chat-ops", URL: "https://hooks.example.com", Secret: "secret"},
+			expected: &Response{
+				code: http.StatusCreated,
+				data: &models.Webhook{ProjectID: 1, Name: "chat-ops", URL: "https://hooks.example.com"},
+			},
+		},
+		{
+			desc: "Should return bad request if body is invalid",
+			body: &models.Model{},
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "Unable to parse body as webhook"},
+			},
+		},
+		{
+			desc:    "Should return bad request if webhook is invalid",
+			body:    &models.Webhook{URL: "https://hooks.example.com"},
+			errSave: merror.NewInvalidInputError("webhook name is required"),
+			expected: &Response{
+				code: http.StatusBadRequest,
+				data: Error{Message: "invalid input: webhook name is required"},
+			},
+		},
+		{
+			desc:    "Should return internal server error if webhook can't be saved",
+			body:    &models.Webhook{Name: "chat-ops", URL: "https://hooks.example.com"},
+			errSave: errors.New("db is down"),
+			expected: &Response{
+				code: http.StatusInternalServerError,
+				data: Error{Message: "Error while saving webhook"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			projectService := &mocks.ProjectsService{}
+			projectService.On("GetByID", mock.Anything, int32(1)).Return(mlp.Project(client.Project{ID: 1, Name: "sample"}), nil)
+
+			webhookService := &mocks.WebhookService{}
+			if tC.errSave != nil {
+				webhookService.On("SaveWebhook", mock.Anything, mock.Anything).Return(nil, tC.errSave)
+			} else {
+				webhookService.On("SaveWebhook", mock.Anything, mock.Anything).Return(func(_ context.Context, webhook *models.Webhook) *models.Webhook {
+					return webhook
+				}, nil)
+			}
+
+			ctl := &WebhookController{
+				AppContext: &AppContext{
+					ProjectsService: projectService,
+					WebhookService:  webhookService,
+				},
+			}
+			resp := ctl.CreateWebhook(&http.Request{}, map[string]string{"project_id": "1"}, tC.body)
+			assert.Equal(t, tC.expected, resp)
+		})
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	webhookService := &mocks.WebhookService{}
+	webhookService.On("FindByID", mock.Anything, models.ID(1), models.ID(2)).Return(&models.Webhook{ID: 2, ProjectID: 1, Name: "chat-ops", URL: "https://old.example.com", Secret: "secret"}, nil)
+	webhookService.On("FindByID", mock.Anything, models.ID(1), models.ID(3)).Return(nil, gorm.ErrRecordNotFound)
+	webhookService.On("SaveWebhook", mock.Anything, mock.Anything).Return(func(_ context.Context, webhook *models.Webhook) *models.Webhook {
+		return webhook
+	}, nil)
+
+	ctl := &WebhookController{AppContext: &AppContext{WebhookService: webhookService}}
+
+	body := &models.Webhook{Name: "chat-ops", URL: "https://new.example.com"}
+	resp := ctl.UpdateWebhook(&http.Request{}, map[string]string{"project_id": "1", "webhook_id": "2"}, body)
+	assert.Equal(t, &Response{
+		code: http.StatusOK,
+		data: &models.Webhook{ID: 2, ProjectID: 1, Name: "chat-ops", URL: "https://new.example.com"},
+	}, resp)
+	savedWebhook := webhookService.Calls[1].Arguments[1].(*models.Webhook)
+	assert.Equal(t, "https://new.example.com", savedWebhook.URL)
+
+	resp = ctl.UpdateWebhook(&http.Request{}, map[string]string{"project_id": "1", "webhook_id": "3"}, body)
+	assert.Equal(t, http.StatusNotFound, resp.code)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	webhook := &models.Webhook{ID: 2, ProjectID: 1, Name: "chat-ops"}
+	deliveries := []*models.WebhookDelivery{{ID: 1, WebhookID: 2, Attempts: 1, StatusCode: 200, Success: true}}
+
+	webhookService := &mocks.WebhookService{}
+	webhookService.On("FindByID", mock.Anything, models.ID(1), models.ID(2)).Return(webhook, nil)
+	webhookService.On("ListDeliveries", mock.Anything, webhook, 10).Return(deliveries, nil)
+
+	ctl := &WebhookController{AppContext: &AppContext{WebhookService: webhookService}}
+
+	resp := ctl.ListWebhookDeliveries(&http.Request{}, map[string]string{"project_id": "1", "webhook_id": "2", "limit": "10"}, nil)
+	assert.Equal(t, &Response{code: http.StatusOK, data: deliveries}, resp)
+
+	resp = ctl.ListWebhookDeliveries(&http.Request{}, map[string]string{"project_id": "1", "webhook_id": "2", "limit": "ten"}, nil)
+	assert.Equal(t, &Response{code: http.StatusBadRequest, data: Error{Message: "Invalid limit: ten"}}, resp)
+}
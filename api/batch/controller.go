@@ -18,14 +18,19 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/apis/sparkoperator.k8s.io/v1beta2"
 	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/clientset/versioned"
 	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/informers/externalversions"
 	"github.com/prometheus/client_golang/prometheus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -34,12 +39,16 @@ import (
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/webhook"
 	"github.com/caraml-dev/merlin/storage"
 )
 
 const (
 	resyncPeriod = 5 * time.Second
 	maxRetries   = 3
+	// retryResyncPeriod is how often the pending retries are reloaded from the storage, so that the retries scheduled
+	// before a restart or by another replica are resubmitted
+	retryResyncPeriod = time.Minute
 )
 
 var statusMap = map[v1beta2.ApplicationStateType]models.State{
@@ -90,39 +99,77 @@ type Controller interface {
 }
 
 type controller struct {
+	environmentName  string
 	store            storage.PredictionJobStorage
 	mlpAPIClient     mlp.APIClient
 	sparkClient      versioned.Interface
 	kubeClient       kubernetes.Interface
 	namespaceCreator cluster.NamespaceCreator
 	manifestManager  ManifestManager
+	notifier         webhook.Notifier
+	progressFetcher  ProgressFetcher
 	informer         cache.SharedIndexInformer
+	jobInformer      cache.SharedIndexInformer
 	queue            workqueue.RateLimitingInterface
 
+	// progressPolling holds the keys of the spark applications whose progress is being polled, so that each
+	// is polled by a single chain of progressKey
+	progressPolling map[string]struct{}
+	progressMu      sync.Mutex
+
 	cluster.ContainerFetcher
 }
 
-func NewController(store storage.PredictionJobStorage, mlpAPIClient mlp.APIClient, sparkClient versioned.Interface, kubeClient kubernetes.Interface, manifestManager ManifestManager, envMetaData cluster.Metadata) Controller {
+// retryKey is queued to resubmit a failed prediction job after the backoff of its retry policy
+type retryKey struct {
+	namespace string
+	jobID     models.ID
+	// attempt is the failed attempt, so that it's only retried once
+	attempt int
+}
+
+// progressKey is queued periodically to collect the progress of the spark application with the given key
+type progressKey string
+
+// kubeJobKey is queued when the status of the kubernetes job of a lightweight or standard transformer prediction job changes
+type kubeJobKey string
+
+func NewController(store storage.PredictionJobStorage, mlpAPIClient mlp.APIClient, sparkClient versioned.Interface, kubeClient kubernetes.Interface, manifestManager ManifestManager, environmentName string, envMetaData cluster.Metadata, notifier webhook.Notifier) Controller {
 	informerFactory := externalversions.NewSharedInformerFactory(sparkClient, resyncPeriod)
 	informer := informerFactory.Sparkoperator().V1beta2().SparkApplications().Informer()
+	// only watch kubernetes jobs created for prediction jobs
+	kubeInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = labelPredictionJobID
+	}))
+	jobInformer := kubeInformerFactory.Batch().V1().Jobs().Informer()
 	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
 
 	controller := &controller{
+		environmentName:  environmentName,
 		store:            store,
 		mlpAPIClient:     mlpAPIClient,
 		sparkClient:      sparkClient,
 		kubeClient:       kubeClient,
 		manifestManager:  manifestManager,
+		notifier:         notifier,
+		progressFetcher:  NewProgressFetcher(kubeClient),
 		namespaceCreator: cluster.NewNamespaceCreator(kubeClient.CoreV1(), time.Second*5),
 		informer:         informer,
+		jobInformer:      jobInformer,
 		queue:            queue,
+		progressPolling:  map[string]struct{}{},
 
 		ContainerFetcher: cluster.NewContainerFetcher(kubeClient.CoreV1(), envMetaData),
 	}
 
 	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.onAdd,
 		UpdateFunc: controller.onUpdate,
 	})
+	jobInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.onJobAdd,
+		UpdateFunc: controller.onJobUpdate,
+	})
 	return controller
 }
 
@@ -140,11 +187,6 @@ func (c *controller) Submit(ctx context.Context, predictionJob *models.Predictio
 		return fmt.Errorf("failed creating namespace %s: %w", namespace, err)
 	}
 
-	driverServiceAccount, err := c.manifestManager.CreateDriverAuthorization(ctx, namespace)
-	if err != nil {
-		return fmt.Errorf("failed creating spark driver authorization in namespace %s: %w", namespace, err)
-	}
-
 	secret, err := c.mlpAPIClient.GetPlainSecretByNameAndProjectID(ctx, predictionJob.Config.ServiceAccountName, int32(predictionJob.ProjectID))
 	if err != nil {
 		return fmt.Errorf("service account %s is not found within %s project: %w", predictionJob.Config.ServiceAccountName, namespace, err)
@@ -160,34 +202,79 @@ func (c *controller) Submit(ctx context.Context, predictionJob *models.Predictio
 		return fmt.Errorf("failed creating job specification configmap for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
 	}
 
-	sparkResource, err := CreateSparkApplicationResource(predictionJob)
-	if err != nil {
-		return fmt.Errorf("failed creating spark application resource for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
-	}
-
-	sparkResource.Spec.Driver.ServiceAccount = &driverServiceAccount
-	_, err = c.sparkClient.SparkoperatorV1beta2().SparkApplications(namespace).Create(ctx, sparkResource, metav1.CreateOptions{})
+	err = c.submitAttempt(ctx, predictionJob, namespace)
 	if err != nil {
-		return fmt.Errorf("failed submitting spark application to spark controller for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+		return err
 	}
 
 	return c.store.Save(predictionJob)
 }
 
+// submitAttempt submits the spark application or kubernetes job running the current attempt of the prediction job
+func (c *controller) submitAttempt(ctx context.Context, predictionJob *models.PredictionJob, namespace string) error {
+	switch {
+	case predictionJob.IsLightweight():
+		return c.submitLightweightJob(ctx, predictionJob, namespace)
+	case predictionJob.IsStandardTransformer():
+		return c.submitStandardTransformerJob(ctx, predictionJob, namespace)
+	default:
+		return c.submitSparkApplication(ctx, predictionJob, namespace)
+	}
+}
+
 func (c *controller) Run(stopCh <-chan struct{}) {
 	defer c.queue.ShutDown()
 
 	go c.informer.Run(stopCh)
+	go c.jobInformer.Run(stopCh)
 
 	if !cache.WaitForCacheSync(stopCh, c.hasSynced) {
 		log.Errorf("timed out while waiting for cache to sync")
 		return
 	}
 
+	go wait.Until(c.reconcileRetries, retryResyncPeriod, stopCh)
 	wait.Until(c.runWorker, time.Second, stopCh)
 }
 
+// reconcileRetries queues the retries of the pending prediction jobs in the environment. The retries are persisted
+// as the next retry time of the prediction job, since the queued ones are lost on restart.
+func (c *controller) reconcileRetries() {
+	predictionJobs, err := c.store.ListPendingRetries(c.environmentName)
+	if err != nil {
+		log.Errorf("unable to list pending retries of prediction jobs in environment %s: %v", c.environmentName, err)
+		return
+	}
+
+	for _, predictionJob := range predictionJobs {
+		attempts, err := c.store.ListAttempts(predictionJob.ID)
+		if err != nil {
+			log.Errorf("unable to list attempts of prediction job %s: %v", predictionJob.ID, err)
+			continue
+		}
+
+		namespace := ""
+		for _, attempt := range attempts {
+			if attempt.Attempt == predictionJob.Attempt {
+				namespace = attempt.Namespace
+			}
+		}
+		if namespace == "" {
+			log.Warnf("unable to find namespace of attempt %d of prediction job %s", predictionJob.Attempt, predictionJob.ID)
+			continue
+		}
+
+		// the queue ignores the retries already waiting in it
+		key := retryKey{namespace: namespace, jobID: predictionJob.ID, attempt: predictionJob.Attempt}
+		c.queue.AddAfter(key, time.Until(*predictionJob.NextRetryAt))
+	}
+}
+
 func (c *controller) Stop(ctx context.Context, predictionJob *models.PredictionJob, namespace string) error {
+	if predictionJob.IsKubernetesJob() {
+		return c.stopKubernetesJob(ctx, predictionJob, namespace)
+	}
+
 	sparkResources, _ := c.sparkClient.SparkoperatorV1beta2().SparkApplications(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", labelPredictionJobID, predictionJob.ID.String()),
 	})
@@ -205,6 +292,7 @@ func (c *controller) Stop(ctx context.Context, predictionJob *models.PredictionJ
 
 	c.cleanup(ctx, predictionJob, namespace)
 	predictionJob.Status = models.JobTerminated
+	predictionJob.NextRetryAt = nil
 
 	return c.store.Save(predictionJob)
 }
@@ -223,7 +311,7 @@ func (c *controller) cleanup(ctx context.Context, job *models.PredictionJob, nam
 
 // hasSynced is required for the cache.Controller interface.
 func (c *controller) hasSynced() bool {
-	return c.informer.HasSynced()
+	return c.informer.HasSynced() && c.jobInformer.HasSynced()
 }
 
 func (c *controller) runWorker() {
@@ -240,7 +328,18 @@ func (c *controller) processNextItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	err := c.syncStatus(context.Background(), key.(string))
+
+	var err error
+	switch k := key.(type) {
+	case string:
+		err = c.syncStatus(context.Background(), k)
+	case retryKey:
+		err = c.resubmit(context.Background(), k)
+	case progressKey:
+		err = c.updateProgress(context.Background(), string(k))
+	case kubeJobKey:
+		err = c.syncJobStatus(context.Background(), string(k))
+	}
 	if err == nil {
 		// No error, reset the ratelimit counters
 		c.queue.Forget(key)
@@ -276,26 +375,492 @@ func (c *controller) syncStatus(ctx context.Context, key string) error {
 		return fmt.Errorf("unable to find prediction job with id %s %w", predictionJobID, err)
 	}
 
-	if predictionJob.Status != models.JobTerminated {
-		predictionJob.Status = statusMap[sparkApp.Status.AppState.State]
-		predictionJob.Error = sparkApp.Status.AppState.ErrorMessage
+	if sparkApp.Name != SparkApplicationName(predictionJob) {
+		// the spark application belongs to a previous attempt of the prediction job
+		return nil
 	}
+	if predictionJob.Status.IsTerminal() || predictionJob.NextRetryAt != nil {
+		// the attempt is already finished, e.g. the spark application is listed again after a restart
+		return nil
+	}
+
+	previousStatus := predictionJob.Status
+	predictionJob.Status = statusMap[sparkApp.Status.AppState.State]
+	predictionJob.Error = sparkApp.Status.AppState.ErrorMessage
+
+	attempt := newAttempt(predictionJob, sparkApp)
+	if (predictionJob.Status == models.JobCompleted || predictionJob.Status == models.JobFailed) && predictionJob.Config.QualityChecks != nil {
+		c.applyQuality(ctx, predictionJob, attempt, sparkApp)
+	}
+
+	retry, backoff := applyRetryPolicy(predictionJob, attempt, sparkApp.Namespace)
 
-	if predictionJob.Status.IsTerminal() {
+	if predictionJob.Status != models.JobRunning {
+		deleteProgressMetrics(sparkApp.Namespace, predictionJob)
+		if predictionJob.Progress != nil {
+			predictionJob.Progress.ActiveExecutors = 0
+		}
+	}
+
+	if retry == nil && predictionJob.Status.IsTerminal() {
 		c.cleanup(ctx, predictionJob, sparkApp.Namespace)
 		modelName := getModelName(predictionJob.Name)
 		BatchCounter.WithLabelValues(sparkApp.Namespace, modelName, string(predictionJob.Status)).Inc()
 	}
 
+	if err := c.store.Save(predictionJob); err != nil {
+		return err
+	}
+
+	if err := c.store.SaveAttempt(attempt); err != nil {
+		return fmt.Errorf("unable to save attempt %d of prediction job %s: %w", attempt.Attempt, predictionJob.ID, err)
+	}
+
+	if retry != nil {
+		c.queue.AddAfter(*retry, backoff)
+	}
+
+	if predictionJob.Status == models.JobRunning {
+		c.startProgress(key)
+	}
+
+	c.notifyStatusChanged(ctx, predictionJob, previousStatus)
+	return nil
+}
+
+// applyRetryPolicy classifies the failure of the attempt and marks the prediction job as pending if its retry policy
+// allows another attempt. It returns the retry to queue after the backoff, nil if the prediction job isn't retried.
+func applyRetryPolicy(predictionJob *models.PredictionJob, attempt *models.PredictionJobAttempt, namespace string) (*retryKey, time.Duration) {
+	if predictionJob.Status != models.JobFailed && predictionJob.Status != models.JobFailedSubmission {
+		return nil, 0
+	}
+
+	if attempt.FailureReason == "" {
+		attempt.FailureReason = models.ClassifyFailure(predictionJob.Status, predictionJob.Error)
+	}
+
+	retryPolicy := predictionJob.Config.RetryPolicy
+	if retryPolicy == nil || !retryPolicy.ShouldRetry(attempt.Attempt, attempt.FailureReason) {
+		return nil, 0
+	}
+
+	backoff := retryPolicy.BackoffAfter(attempt.Attempt)
+	nextRetryAt := time.Now().Add(backoff)
+	predictionJob.NextRetryAt = &nextRetryAt
+	predictionJob.Status = models.JobPending
+	predictionJob.Error = fmt.Sprintf("attempt %d failed with %s, retrying in %s: %s", attempt.Attempt, attempt.FailureReason, backoff, attempt.Error)
+	return &retryKey{namespace: namespace, jobID: predictionJob.ID, attempt: attempt.Attempt}, backoff
+}
+
+// resubmit submits the next attempt of a failed prediction job. The secret and job spec of the prediction job
+// are kept from the first submission.
+func (c *controller) resubmit(ctx context.Context, key retryKey) error {
+	predictionJob, err := c.store.Get(key.jobID)
+	if err != nil {
+		return fmt.Errorf("unable to find prediction job with id %s %w", key.jobID, err)
+	}
+
+	if predictionJob.Status != models.JobPending || predictionJob.Attempt != key.attempt {
+		// the prediction job was stopped or already resubmitted while waiting for the retry
+		return nil
+	}
+
+	previousStatus := predictionJob.Status
+	predictionJob.Attempt++
+	predictionJob.Error = ""
+	predictionJob.NextRetryAt = nil
+	// the quality checks are evaluated again on the output of the new attempt
+	predictionJob.Quality = nil
+	predictionJob.QualityStatus = ""
+
+	err = c.submitAttempt(ctx, predictionJob, key.namespace)
+	if err != nil {
+		predictionJob.Status = models.JobFailedSubmission
+		predictionJob.Error = err.Error()
+		c.cleanup(ctx, predictionJob, key.namespace)
+	}
+
+	if err := c.store.Save(predictionJob); err != nil {
+		return err
+	}
+
+	attempt := newAttempt(predictionJob, nil)
+	attempt.Namespace = key.namespace
+	if predictionJob.Status == models.JobFailedSubmission {
+		attempt.FailureReason = models.FailureReasonSubmission
+	}
+	if err := c.store.SaveAttempt(attempt); err != nil {
+		return fmt.Errorf("unable to save attempt %d of prediction job %s: %w", attempt.Attempt, predictionJob.ID, err)
+	}
+
+	c.notifyStatusChanged(ctx, predictionJob, previousStatus)
+	return nil
+}
+
+// startProgress schedules the progress updates of a running spark application unless they're already scheduled
+func (c *controller) startProgress(key string) {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+
+	if _, ok := c.progressPolling[key]; ok {
+		return
+	}
+	c.progressPolling[key] = struct{}{}
+	c.queue.AddAfter(progressKey(key), progressInterval)
+}
+
+// updateProgress saves the progress of a running spark application and schedules the next update until it stops running.
+// The updates also stop on error, they're started again by the next resync of the spark application.
+func (c *controller) updateProgress(ctx context.Context, key string) error {
+	polling := false
+	defer func() {
+		c.progressMu.Lock()
+		defer c.progressMu.Unlock()
+
+		if polling {
+			// the next update is scheduled even if this one fails, as the spark UI might be temporarily unavailable
+			c.queue.AddAfter(progressKey(key), progressInterval)
+		} else {
+			delete(c.progressPolling, key)
+		}
+	}()
+
+	obj, exists, err := c.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		log.Warnf("error fetching object with key %s from store: %v", key, err)
+		return nil
+	}
+	if !exists {
+		return nil
+	}
+
+	sparkApp, ok := obj.(*v1beta2.SparkApplication)
+	if !ok {
+		log.Warnf("not spark application %v", obj)
+		return nil
+	}
+	predictionJobID, err := models.ParseID(sparkApp.Labels[labelPredictionJobID])
+	if err != nil {
+		log.Warnf("unable to parse prediction job id: %v", err)
+		return nil
+	}
+	predictionJob, err := c.store.Get(predictionJobID)
+	if err != nil {
+		log.Warnf("unable to find prediction job with id %s %v", predictionJobID, err)
+		return nil
+	}
+
+	if predictionJob.Status != models.JobRunning || sparkApp.Name != SparkApplicationName(predictionJob) {
+		return nil
+	}
+	polling = true
+
+	progress, err := c.progressFetcher.FetchProgress(ctx, sparkApp)
+	if err != nil {
+		log.Warnf("failed fetching progress of prediction job %s: %v", predictionJob.ID, err)
+		return nil
+	}
+
+	progress.UpdatedAt = time.Now()
+	if err := c.store.SaveProgress(predictionJob.ID, progress); err != nil {
+		log.Warnf("failed saving progress of prediction job %s: %v", predictionJob.ID, err)
+		return nil
+	}
+
+	predictionJob.Progress = progress
+	recordProgressMetrics(sparkApp.Namespace, predictionJob)
+	return nil
+}
+
+func (c *controller) submitSparkApplication(ctx context.Context, predictionJob *models.PredictionJob, namespace string) error {
+	driverServiceAccount, err := c.manifestManager.CreateDriverAuthorization(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed creating spark driver authorization in namespace %s: %w", namespace, err)
+	}
+
+	c.setReferenceQuantiles(predictionJob)
+	sparkResource, err := CreateSparkApplicationResource(predictionJob)
+	if err != nil {
+		return fmt.Errorf("failed creating spark application resource for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+	}
+
+	sparkResource.Spec.Driver.ServiceAccount = &driverServiceAccount
+	_, err = c.sparkClient.SparkoperatorV1beta2().SparkApplications(namespace).Create(ctx, sparkResource, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed submitting spark application to spark controller for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+	}
+	return nil
+}
+
+func (c *controller) submitLightweightJob(ctx context.Context, predictionJob *models.PredictionJob, namespace string) error {
+	jobResource, err := CreateLightweightJobResource(predictionJob)
+	if err != nil {
+		return fmt.Errorf("failed creating kubernetes job resource for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+	}
+
+	_, err = c.kubeClient.BatchV1().Jobs(namespace).Create(ctx, jobResource, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed submitting kubernetes job for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+	}
+	return nil
+}
+
+// submitStandardTransformerJob submits the kubernetes job replaying the source of the prediction job through the
+// standard transformer currently running for its version endpoint
+func (c *controller) submitStandardTransformerJob(ctx context.Context, predictionJob *models.PredictionJob, namespace string) error {
+	inferenceServiceName := predictionJob.Config.StandardTransformer.InferenceServiceName
+	pods, err := c.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: StandardTransformerPodLabelSelector(inferenceServiceName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed listing standard transformer pods of %s in namespace %s: %w", inferenceServiceName, namespace, err)
+	}
+
+	transformer, ok := findStandardTransformerContainer(pods.Items)
+	if !ok {
+		return fmt.Errorf("running standard transformer of %s is not found in namespace %s", inferenceServiceName, namespace)
+	}
+
+	jobResource, err := CreateStandardTransformerJobResource(predictionJob, transformer)
+	if err != nil {
+		return fmt.Errorf("failed creating kubernetes job resource for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+	}
+
+	_, err = c.kubeClient.BatchV1().Jobs(namespace).Create(ctx, jobResource, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed submitting kubernetes job for job %s in namespace %s: %w", predictionJob.Name, namespace, err)
+	}
+	return nil
+}
+
+func (c *controller) stopKubernetesJob(ctx context.Context, predictionJob *models.PredictionJob, namespace string) error {
+	propagationPolicy := metav1.DeletePropagationBackground
+	kubeJobName := SparkApplicationName(predictionJob)
+	err := c.kubeClient.BatchV1().Jobs(namespace).Delete(ctx, kubeJobName, metav1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	})
+	if err != nil && !(kerrors.IsNotFound(err) && predictionJob.NextRetryAt != nil) {
+		// the kubernetes job of a failed attempt waiting for its retry might be deleted already
+		return fmt.Errorf("failed to delete kubernetes job %s in namespace %s: %w", kubeJobName, namespace, err)
+	}
+
+	c.cleanup(ctx, predictionJob, namespace)
+	predictionJob.Status = models.JobTerminated
+	predictionJob.NextRetryAt = nil
+
 	return c.store.Save(predictionJob)
 }
 
+// syncJobStatus updates the status of a prediction job running as kubernetes job, records its attempt and resubmits it
+// according to its retry policy if it failed
+func (c *controller) syncJobStatus(ctx context.Context, key string) error {
+	obj, exists, err := c.jobInformer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("error fetching object with key %s from store: %w", key, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	kubeJob, ok := obj.(*batchv1.Job)
+	if !ok {
+		return fmt.Errorf("not kubernetes job %v", obj)
+	}
+	predictionJobID, err := models.ParseID(kubeJob.Labels[labelPredictionJobID])
+	if err != nil {
+		return fmt.Errorf("unable to parse prediction job id: %w", err)
+	}
+	predictionJob, err := c.store.Get(predictionJobID)
+	if err != nil {
+		return fmt.Errorf("unable to find prediction job with id %s %w", predictionJobID, err)
+	}
+
+	if kubeJob.Name != SparkApplicationName(predictionJob) {
+		// the kubernetes job belongs to a previous attempt of the prediction job
+		return nil
+	}
+	if predictionJob.Status.IsTerminal() || predictionJob.NextRetryAt != nil {
+		// the attempt is already finished, e.g. the kubernetes job is listed again after a restart
+		return nil
+	}
+
+	previousStatus := predictionJob.Status
+	predictionJob.Status, predictionJob.Error = kubeJobStatus(kubeJob)
+
+	attempt := newKubeJobAttempt(predictionJob, kubeJob)
+	if predictionJob.Status == models.JobFailed {
+		if podName, reason := c.kubeJobFailure(ctx, kubeJob); podName != "" {
+			attempt.DriverPodName = podName
+			predictionJob.Error = fmt.Sprintf("%s: %s", predictionJob.Error, reason)
+			attempt.Error = predictionJob.Error
+		}
+	}
+
+	retry, backoff := applyRetryPolicy(predictionJob, attempt, kubeJob.Namespace)
+	if retry == nil && predictionJob.Status.IsTerminal() {
+		c.cleanup(ctx, predictionJob, kubeJob.Namespace)
+		modelName := getModelName(predictionJob.Name)
+		BatchCounter.WithLabelValues(kubeJob.Namespace, modelName, string(predictionJob.Status)).Inc()
+	}
+
+	if err := c.store.Save(predictionJob); err != nil {
+		return err
+	}
+
+	if err := c.store.SaveAttempt(attempt); err != nil {
+		return fmt.Errorf("unable to save attempt %d of prediction job %s: %w", attempt.Attempt, predictionJob.ID, err)
+	}
+
+	if retry != nil {
+		c.queue.AddAfter(*retry, backoff)
+	}
+
+	c.notifyStatusChanged(ctx, predictionJob, previousStatus)
+	return nil
+}
+
+// kubeJobFailure returns the name of a failed pod of the kubernetes job and why it failed, e.g. evicted or OOMKilled,
+// so that the failure can be classified. The name is empty if there's no failed pod.
+func (c *controller) kubeJobFailure(ctx context.Context, kubeJob *batchv1.Job) (string, string) {
+	pods, err := c.kubeClient.CoreV1().Pods(kubeJob.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("job-name=%s", kubeJob.Name),
+	})
+	if err != nil {
+		log.Warnf("failed listing pods of kubernetes job %s in namespace %s: %v", kubeJob.Name, kubeJob.Namespace, err)
+		return "", ""
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+
+		reasons := []string{}
+		if pod.Status.Reason != "" {
+			reasons = append(reasons, pod.Status.Reason)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Reason != "" {
+				reasons = append(reasons, status.State.Terminated.Reason)
+			}
+		}
+		if pod.Status.Message != "" {
+			reasons = append(reasons, pod.Status.Message)
+		}
+		return pod.Name, fmt.Sprintf("pod %s failed: %s", pod.Name, strings.Join(reasons, ", "))
+	}
+	return "", ""
+}
+
+func (c *controller) notifyStatusChanged(ctx context.Context, predictionJob *models.PredictionJob, previousStatus models.State) {
+	if c.notifier == nil || predictionJob.Status == previousStatus {
+		return
+	}
+
+	c.notifier.Notify(ctx, predictionJob.ProjectID, models.WebhookEventPredictionJobStatusChanged, predictionJob.ID.String(), models.PredictionJobEventData{
+		JobID:           predictionJob.ID,
+		Name:            predictionJob.Name,
+		ModelID:         predictionJob.VersionModelID,
+		VersionID:       predictionJob.VersionID,
+		EnvironmentName: predictionJob.EnvironmentName,
+		PreviousStatus:  previousStatus,
+		Status:          predictionJob.Status,
+		Error:           predictionJob.Error,
+	})
+}
+
+// newAttempt returns the record of the current attempt of the prediction job, filled from its spark application if any
+func newAttempt(predictionJob *models.PredictionJob, sparkApp *v1beta2.SparkApplication) *models.PredictionJobAttempt {
+	attemptNumber := predictionJob.Attempt
+	if attemptNumber < 1 {
+		attemptNumber = 1
+	}
+
+	attempt := &models.PredictionJobAttempt{
+		JobID:                predictionJob.ID,
+		Attempt:              attemptNumber,
+		Status:               predictionJob.Status,
+		Error:                predictionJob.Error,
+		SparkApplicationName: SparkApplicationName(predictionJob),
+	}
+	if sparkApp == nil {
+		return attempt
+	}
+
+	attempt.Status = statusMap[sparkApp.Status.AppState.State]
+	attempt.Error = sparkApp.Status.AppState.ErrorMessage
+	attempt.Namespace = sparkApp.Namespace
+	attempt.DriverPodName = sparkApp.Status.DriverInfo.PodName
+	if !sparkApp.Status.LastSubmissionAttemptTime.IsZero() {
+		startedAt := sparkApp.Status.LastSubmissionAttemptTime.Time
+		attempt.StartedAt = &startedAt
+	}
+	if !sparkApp.Status.TerminationTime.IsZero() {
+		finishedAt := sparkApp.Status.TerminationTime.Time
+		attempt.FinishedAt = &finishedAt
+	}
+	return attempt
+}
+
+// newKubeJobAttempt returns the record of the current attempt of a prediction job running as kubernetes job
+func newKubeJobAttempt(predictionJob *models.PredictionJob, kubeJob *batchv1.Job) *models.PredictionJobAttempt {
+	attempt := newAttempt(predictionJob, nil)
+	attempt.Namespace = kubeJob.Namespace
+	if kubeJob.Status.StartTime != nil {
+		startedAt := kubeJob.Status.StartTime.Time
+		attempt.StartedAt = &startedAt
+	}
+	if kubeJob.Status.CompletionTime != nil {
+		finishedAt := kubeJob.Status.CompletionTime.Time
+		attempt.FinishedAt = &finishedAt
+	}
+	for _, condition := range kubeJob.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			finishedAt := condition.LastTransitionTime.Time
+			attempt.FinishedAt = &finishedAt
+		}
+	}
+	return attempt
+}
+
+// onAdd starts the progress updates of the spark applications already running when the controller starts
+func (c *controller) onAdd(obj interface{}) {
+	app, _ := obj.(*v1beta2.SparkApplication)
+	if app != nil && statusMap[app.Status.AppState.State] == models.JobRunning {
+		key, _ := cache.MetaNamespaceKeyFunc(app)
+		c.startProgress(key)
+	}
+}
+
 func (c *controller) onUpdate(old, new interface{}) {
 	oldApp, _ := old.(*v1beta2.SparkApplication)
 	newApp, _ := new.(*v1beta2.SparkApplication)
+	key, _ := cache.MetaNamespaceKeyFunc(newApp)
 	if oldApp.Status.AppState.State != newApp.Status.AppState.State {
-		key, _ := cache.MetaNamespaceKeyFunc(newApp)
 		c.queue.AddRateLimited(key)
+	} else if statusMap[newApp.Status.AppState.State] == models.JobRunning {
+		// resync, restart the progress updates if they stopped
+		c.startProgress(key)
+	}
+}
+
+// onJobAdd reconciles the status of the kubernetes jobs listed when the controller starts, as they might have changed
+// while it wasn't running
+func (c *controller) onJobAdd(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err == nil {
+		c.queue.AddRateLimited(kubeJobKey(key))
+	}
+}
+
+func (c *controller) onJobUpdate(old, new interface{}) {
+	oldJob, _ := old.(*batchv1.Job)
+	newJob, _ := new.(*batchv1.Job)
+	oldStatus, _ := kubeJobStatus(oldJob)
+	newStatus, _ := kubeJobStatus(newJob)
+	if oldStatus != newStatus {
+		key, _ := cache.MetaNamespaceKeyFunc(newJob)
+		c.queue.AddRateLimited(kubeJobKey(key))
 	}
 }
 
@@ -25,6 +25,7 @@ import (
 	sparkOpFake "github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/clientset/versioned/fake"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,6 +42,7 @@ import (
 	"github.com/caraml-dev/merlin/mlp"
 	mlpMock "github.com/caraml-dev/merlin/mlp/mocks"
 	"github.com/caraml-dev/merlin/models"
+	webhookMock "github.com/caraml-dev/merlin/pkg/webhook/mocks"
 	"github.com/caraml-dev/merlin/storage/mocks"
 )
 
@@ -318,7 +320,7 @@ func TestSubmit(t *testing.T) {
 			mockKubeClient := &fake2.Clientset{}
 			mockManifestManager := &batchMock.ManifestManager{}
 			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
-			ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, clusterMetadata)
+			ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil)
 
 			mockKubeClient.PrependReactor("get", "namespaces", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
 				return true, nil, kerrors.NewNotFound(schema.GroupResource{}, action.(ktesting.GetAction).GetName())
@@ -384,7 +386,7 @@ func TestCleanupAfterSubmitFailed(t *testing.T) {
 	mockKubeClient := &fake2.Clientset{}
 	mockManifestManager := &batchMock.ManifestManager{}
 	clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
-	ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, clusterMetadata)
+	ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil)
 
 	mockManifestManager.On("DeleteSecret", context.Background(), jobName, defaultNamespace).Return(nil)
 	mockManifestManager.On("DeleteJobSpec", context.Background(), jobName, defaultNamespace).Return(nil)
@@ -398,8 +400,10 @@ func TestOnUpdate(t *testing.T) {
 	sparkApp.Namespace = defaultNamespace
 
 	mockStorage := &mocks.PredictionJobStorage{}
+	mockStorage.On("ListPendingRetries", "env1").Return(nil, nil).Maybe()
 	mockStorage.On("Save", predictionJob).Return(nil)
 	mockStorage.On("Get", predictionJob.ID).Return(predictionJob, nil)
+	mockStorage.On("SaveAttempt", mock.Anything).Return(nil)
 
 	mockMlpAPIClient := &mlpMock.APIClient{}
 	mockMlpAPIClient.On("GetPlainSecretByNameAndProjectID", context.Background(), secret.Name, int32(1)).Return(secret, nil)
@@ -408,7 +412,7 @@ func TestOnUpdate(t *testing.T) {
 	mockKubeClient := &fake2.Clientset{}
 	mockManifestManager := &batchMock.ManifestManager{}
 	clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
-	ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, clusterMetadata).(*controller)
+	ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil).(*controller)
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go ctl.Run(stopCh)
@@ -446,8 +450,8 @@ func TestOnUpdate(t *testing.T) {
 	// wait until the state sync happen in the background
 	time.Sleep(100 * time.Millisecond)
 	mockStorage.AssertExpectations(t)
-	call := mockStorage.Calls[1]
-	assert.Equal(t, models.JobCompleted, call.Arguments[0].(*models.PredictionJob).Status)
+	saves := storageCalls(mockStorage, "Save")
+	assert.Equal(t, models.JobCompleted, saves[len(saves)-1].Arguments[0].(*models.PredictionJob).Status)
 }
 
 func TestUpdateStatus(t *testing.T) {
@@ -472,18 +476,24 @@ func TestUpdateStatus(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			predictionJob.Status = models.JobPending
 			mockStorage := &mocks.PredictionJobStorage{}
+			mockStorage.On("ListPendingRetries", "env1").Return(nil, nil).Maybe()
 			mockStorage.On("Save", predictionJob).Return(nil)
 			mockStorage.On("Get", predictionJob.ID).Return(predictionJob, nil)
+			mockStorage.On("SaveAttempt", mock.Anything).Return(nil)
 
 			mockMlpAPIClient := &mlpMock.APIClient{}
 			mockMlpAPIClient.On("GetPlainSecretByNameAndProjectID", context.Background(), secret.Name, int32(1)).Return(secret, nil)
 
+			mockNotifier := &webhookMock.Notifier{}
+			mockNotifier.On("Notify", mock.Anything, predictionJob.ProjectID, models.WebhookEventPredictionJobStatusChanged, predictionJob.ID.String(), mock.Anything).Return()
+
 			mockSparkClient := &sparkOpFake.Clientset{}
 			mockKubeClient := &fake2.Clientset{}
 			mockManifestManager := &batchMock.ManifestManager{}
 			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
-			ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, clusterMetadata).(*controller)
+			ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, mockNotifier).(*controller)
 			stopCh := make(chan struct{})
 			defer close(stopCh)
 			go ctl.Run(stopCh)
@@ -503,9 +513,145 @@ func TestUpdateStatus(t *testing.T) {
 			// wait until the state sync happen in the background
 			time.Sleep(500 * time.Millisecond)
 			mockStorage.AssertExpectations(t)
-			call := mockStorage.Calls[1]
-			assert.Equal(t, test.wantState, call.Arguments[0].(*models.PredictionJob).Status)
+			saves := storageCalls(mockStorage, "Save")
+			assert.Equal(t, test.wantState, saves[len(saves)-1].Arguments[0].(*models.PredictionJob).Status)
+			mockManifestManager.AssertExpectations(t)
+
+			if test.wantState == models.JobPending {
+				mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				mockNotifier.AssertNumberOfCalls(t, "Notify", 1)
+				eventData := mockNotifier.Calls[0].Arguments[4].(models.PredictionJobEventData)
+				assert.Equal(t, models.JobPending, eventData.PreviousStatus)
+				assert.Equal(t, test.wantState, eventData.Status)
+			}
+
+			if test.wantState.IsTerminal() {
+				// the finished job is ignored when its spark application is synced again
+				ctl.onUpdate(sparkApp, sparkAppNew)
+				time.Sleep(500 * time.Millisecond)
+				assert.Len(t, storageCalls(mockStorage, "Save"), len(saves))
+				mockNotifier.AssertNumberOfCalls(t, "Notify", 1)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name              string
+		errorMessage      string
+		wantFailureReason models.FailureReason
+		wantRetry         bool
+	}{
+		{
+			name:              "preempted executor is retried",
+			errorMessage:      "ExecutorLostFailure (executor 1 exited caused by one of the running tasks) Reason: Node is shutting down",
+			wantFailureReason: models.FailureReasonPreemption,
+			wantRetry:         true,
+		},
+		{
+			name:              "application error is not retried",
+			errorMessage:      "driver container failed with ExitCode: 1, Reason: Error",
+			wantFailureReason: models.FailureReasonApplication,
+			wantRetry:         false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := *predictionJob.Config
+			config.RetryPolicy = &models.PredictionJobRetryPolicy{MaxAttempts: 2, Backoff: "10ms"}
+			job := *predictionJob
+			job.Status = models.JobRunning
+			job.Attempt = 1
+			job.Config = &config
+			job.QualityStatus = models.QualityStatusPassed
+			job.Quality = &models.PredictionJobQuality{}
+
+			failedApp, _ := CreateSparkApplicationResource(&job)
+			failedApp.Namespace = defaultNamespace
+			failedApp.Status.AppState.State = v1beta2.RunningState
+			assert.Equal(t, v1beta2.Never, failedApp.Spec.RestartPolicy.Type)
+
+			mockStorage := &mocks.PredictionJobStorage{}
+			mockStorage.On("ListPendingRetries", "env1").Return(nil, nil).Maybe()
+			mockStorage.On("Get", job.ID).Return(&job, nil)
+			mockStorage.On("Save", &job).Return(nil)
+			mockStorage.On("SaveAttempt", mock.Anything).Return(nil)
+
+			mockSparkClient := &sparkOpFake.Clientset{}
+			var resubmitted *v1beta2.SparkApplication
+			mockSparkClient.PrependReactor("create", "sparkapplications", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				resubmitted = action.(ktesting.CreateAction).GetObject().(*v1beta2.SparkApplication)
+				return true, resubmitted, nil
+			})
+
+			mockKubeClient := &fake2.Clientset{}
+			mockManifestManager := &batchMock.ManifestManager{}
+			if test.wantRetry {
+				mockManifestManager.On("CreateDriverAuthorization", context.Background(), defaultNamespace).Return(driverServiceAccountName, nil)
+			} else {
+				mockManifestManager.On("DeleteSecret", context.Background(), jobName, defaultNamespace).Return(nil)
+				mockManifestManager.On("DeleteJobSpec", context.Background(), jobName, defaultNamespace).Return(nil)
+			}
+
+			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+			ctl := NewController(mockStorage, &mlpMock.APIClient{}, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil).(*controller)
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			go ctl.Run(stopCh)
+			time.Sleep(10 * time.Millisecond)
+
+			failedAppNew := failedApp.DeepCopy()
+			failedAppNew.Status.AppState.State = v1beta2.FailedState
+			failedAppNew.Status.AppState.ErrorMessage = test.errorMessage
+			failedAppNew.Status.DriverInfo.PodName = jobName + "-driver"
+
+			_ = ctl.informer.GetIndexer().Add(failedAppNew)
+			ctl.onUpdate(failedApp, failedAppNew)
+
+			// wait until the state sync and the retry happen in the background
+			time.Sleep(500 * time.Millisecond)
 			mockManifestManager.AssertExpectations(t)
+
+			firstAttempt := storageCalls(mockStorage, "SaveAttempt")[0].Arguments[0].(*models.PredictionJobAttempt)
+			assert.Equal(t, 1, firstAttempt.Attempt)
+			assert.Equal(t, models.JobFailed, firstAttempt.Status)
+			assert.Equal(t, test.errorMessage, firstAttempt.Error)
+			assert.Equal(t, test.wantFailureReason, firstAttempt.FailureReason)
+			assert.Equal(t, jobName, firstAttempt.SparkApplicationName)
+			assert.Equal(t, jobName+"-driver", firstAttempt.DriverPodName)
+
+			if !test.wantRetry {
+				assert.Nil(t, resubmitted)
+				assert.Equal(t, models.JobFailed, job.Status)
+				assert.Equal(t, 1, job.Attempt)
+				return
+			}
+
+			assert.NotNil(t, resubmitted)
+			assert.Equal(t, jobName+"-2", resubmitted.Name)
+			assert.Equal(t, driverServiceAccountName, *resubmitted.Spec.Driver.ServiceAccount)
+			assert.Equal(t, models.JobPending, job.Status)
+			assert.Equal(t, 2, job.Attempt)
+			assert.Nil(t, job.NextRetryAt)
+			// the quality of the previous attempt is discarded
+			assert.Nil(t, job.Quality)
+			assert.Equal(t, models.QualityStatus(""), job.QualityStatus)
+
+			secondAttempt := storageCalls(mockStorage, "SaveAttempt")[1].Arguments[0].(*models.PredictionJobAttempt)
+			assert.Equal(t, 2, secondAttempt.Attempt)
+			assert.Equal(t, models.JobPending, secondAttempt.Status)
+			assert.Equal(t, jobName+"-2", secondAttempt.SparkApplicationName)
+			assert.Equal(t, defaultNamespace, secondAttempt.Namespace)
+
+			// events of the spark application of the previous attempt are ignored
+			_ = ctl.informer.GetIndexer().Update(failedAppNew)
+			ctl.onUpdate(failedApp, failedAppNew)
+			time.Sleep(100 * time.Millisecond)
+			assert.Len(t, storageCalls(mockStorage, "Get"), 3)
+			assert.Len(t, storageCalls(mockStorage, "SaveAttempt"), 2)
+			assert.Equal(t, models.JobPending, job.Status)
 		})
 	}
 }
@@ -571,7 +717,7 @@ func TestStop(t *testing.T) {
 			mockKubeClient := &fake2.Clientset{}
 			mockManifestManager := &batchMock.ManifestManager{}
 			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
-			ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, clusterMetadata)
+			ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil)
 
 			mockKubeClient.PrependReactor("get", "namespaces", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
 				return true, nil, kerrors.NewNotFound(schema.GroupResource{}, action.(ktesting.GetAction).GetName())
@@ -600,3 +746,45 @@ func TestStop(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileRetries(t *testing.T) {
+	dueAt := time.Now().Add(-time.Minute)
+	laterAt := time.Now().Add(time.Hour)
+	dueJob := &models.PredictionJob{ID: 1, Status: models.JobPending, Attempt: 1, NextRetryAt: &dueAt}
+	laterJob := &models.PredictionJob{ID: 2, Status: models.JobPending, Attempt: 2, NextRetryAt: &laterAt}
+	unknownJob := &models.PredictionJob{ID: 3, Status: models.JobPending, Attempt: 1, NextRetryAt: &dueAt}
+
+	mockStorage := &mocks.PredictionJobStorage{}
+	mockStorage.On("ListPendingRetries", "env1").Return([]*models.PredictionJob{dueJob, laterJob, unknownJob}, nil)
+	mockStorage.On("ListAttempts", models.ID(1)).Return([]*models.PredictionJobAttempt{{JobID: 1, Attempt: 1, Namespace: defaultNamespace}}, nil)
+	mockStorage.On("ListAttempts", models.ID(2)).Return([]*models.PredictionJobAttempt{
+		{JobID: 2, Attempt: 1, Namespace: "previous"},
+		{JobID: 2, Attempt: 2, Namespace: defaultNamespace},
+	}, nil)
+	mockStorage.On("ListAttempts", models.ID(3)).Return([]*models.PredictionJobAttempt{}, nil)
+
+	clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+	ctl := NewController(mockStorage, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, &fake2.Clientset{}, &batchMock.ManifestManager{}, "env1", clusterMetadata, nil).(*controller)
+
+	ctl.reconcileRetries()
+	// reconciling again doesn't queue the same retries twice
+	ctl.reconcileRetries()
+
+	// the due retry is queued right away while the later one waits for its backoff
+	assert.Equal(t, 1, ctl.queue.Len())
+	item, _ := ctl.queue.Get()
+	assert.Equal(t, retryKey{namespace: defaultNamespace, jobID: 1, attempt: 1}, item)
+	ctl.queue.Done(item)
+	mockStorage.AssertExpectations(t)
+}
+
+// storageCalls returns the calls of a method of the storage mock, since the retries are reconciled in the background
+func storageCalls(store *mocks.PredictionJobStorage, method string) []mock.Call {
+	calls := []mock.Call{}
+	for _, call := range store.Calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+const (
+	// lightweightAppFileName is the entrypoint of lightweight prediction jobs, next to the main app of spark
+	lightweightAppFileName = "lightweight.py"
+	lightweightContainer   = "prediction-job"
+
+	jobSpecVolume        = "job-spec"
+	serviceAccountVolume = "service-account"
+)
+
+// CreateLightweightJobResource creates the kubernetes job running a prediction job in lightweight mode.
+// The job runs a single pod using the driver resource request, and mounts the same secret and job spec as spark.
+func CreateLightweightJobResource(job *models.PredictionJob) (*batchv1.Job, error) {
+	resources, err := createDriverResources(job)
+	if err != nil {
+		return nil, err
+	}
+
+	envVars, err := addEnvVars(job)
+	if err != nil {
+		return nil, err
+	}
+
+	lightweight := job.Config.Lightweight
+	if lightweight == nil {
+		lightweight = &models.LightweightJobConfig{}
+		lightweight.ApplyDefaults()
+	}
+
+	args := []string{
+		"python",
+		path.Join(path.Dir(job.Config.MainAppPath), lightweightAppFileName),
+		"--job-name", job.Name,
+		"--spec-path", jobSpecPath,
+		"--concurrency", strconv.Itoa(lightweight.Concurrency),
+		"--batch-size", strconv.Itoa(lightweight.BatchSize),
+	}
+	if lightweight.EndpointURL != "" {
+		args = append(args, "--endpoint-url", lightweight.EndpointURL, "--endpoint-protocol", string(lightweight.Protocol))
+	}
+
+	return createKubernetesJob(job, []corev1.Container{
+		{
+			Name:         lightweightContainer,
+			Image:        job.Config.ImageRef,
+			Args:         args,
+			Env:          envVars,
+			Resources:    resources,
+			VolumeMounts: jobVolumeMounts(),
+		},
+	}), nil
+}
+
+// createKubernetesJob creates the kubernetes job of a prediction job running the given containers
+func createKubernetesJob(job *models.PredictionJob, containers []corev1.Container) *batchv1.Job {
+	// the job isn't retried by kubernetes, the controller resubmits it according to the retry policy of the
+	// prediction job so that every attempt is recorded
+	backoffLimit := int32(0)
+	ttlSecondsAfterFinished := int32(ttlSecond)
+	labels := createLabel(job)
+
+	return &batchv1.Job{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   SparkApplicationName(job),
+			Labels: labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttlSecondsAfterFinished,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    containers,
+					Volumes: []corev1.Volume{
+						{
+							Name: jobSpecVolume,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: job.Name},
+								},
+							},
+						},
+						{
+							Name: serviceAccountVolume,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: job.Name},
+							},
+						},
+					},
+					NodeSelector: defaultNodeSelector,
+					Tolerations:  []corev1.Toleration{defaultToleration},
+				},
+			},
+		},
+	}
+}
+
+// createDriverResources returns the resource requirements of a container using the driver resource request
+func createDriverResources(job *models.PredictionJob) (corev1.ResourceRequirements, error) {
+	cpuRequest, err := resource.ParseQuantity(job.Config.ResourceRequest.DriverCPURequest)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("invalid driver cpu request: %s", job.Config.ResourceRequest.DriverCPURequest)
+	}
+	memoryRequest, err := resource.ParseQuantity(job.Config.ResourceRequest.DriverMemoryRequest)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("invalid driver memory request: %s", job.Config.ResourceRequest.DriverMemoryRequest)
+	}
+
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    cpuRequest,
+			corev1.ResourceMemory: memoryRequest,
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: memoryRequest,
+		},
+	}, nil
+}
+
+func jobVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      jobSpecVolume,
+			MountPath: jobSpecMount,
+		},
+		{
+			Name:      serviceAccountVolume,
+			MountPath: serviceAccountMount,
+		},
+	}
+}
+
+// kubeJobStatus maps the status of the kubernetes job of a lightweight or standard transformer prediction job
+func kubeJobStatus(kubeJob *batchv1.Job) (models.State, string) {
+	for _, condition := range kubeJob.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return models.JobCompleted, ""
+		case batchv1.JobFailed:
+			return models.JobFailed, condition.Message
+		}
+	}
+
+	if kubeJob.Status.Active > 0 {
+		return models.JobRunning, ""
+	}
+	return models.JobPending, ""
+}
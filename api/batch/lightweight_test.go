@@ -0,0 +1,360 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sparkOpFake "github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	jobspec "github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+
+	batchMock "github.com/caraml-dev/merlin/batch/mocks"
+	"github.com/caraml-dev/merlin/cluster"
+	mlpMock "github.com/caraml-dev/merlin/mlp/mocks"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/protocol"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func newLightweightPredictionJob() *models.PredictionJob {
+	config := *predictionJob.Config
+	config.MainAppPath = mainAppPathInput
+	config.Mode = models.PredictionJobModeLightweight
+	config.Lightweight = &models.LightweightJobConfig{
+		Concurrency: 2,
+		BatchSize:   100,
+	}
+	config.JobConfig = &jobspec.PredictionJob{
+		Version: "v1",
+		Kind:    "PredictionJob",
+		Name:    jobName,
+		Source: &jobspec.PredictionJob_GcsSource{
+			GcsSource: &jobspec.GcsSource{
+				Format: jobspec.FileFormat_CSV,
+				Uri:    "gs://bucket-name/input.csv",
+			},
+		},
+		Model: predictionJob.Config.JobConfig.Model,
+		Sink:  predictionJob.Config.JobConfig.Sink,
+	}
+
+	job := *predictionJob
+	job.Status = models.JobPending
+	job.Config = &config
+	return &job
+}
+
+func TestCreateLightweightJobResource(t *testing.T) {
+	job := newLightweightPredictionJob()
+
+	kubeJob, err := CreateLightweightJobResource(job)
+	assert.NoError(t, err)
+	assert.Equal(t, jobName, kubeJob.Name)
+	assert.Equal(t, job.ID.String(), kubeJob.Labels[labelPredictionJobID])
+	assert.Equal(t, int32(0), *kubeJob.Spec.BackoffLimit)
+
+	podSpec := kubeJob.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Len(t, podSpec.Containers, 1)
+	container := podSpec.Containers[0]
+	assert.Equal(t, imageRef, container.Image)
+	assert.Equal(t, []string{
+		"python", "/merlin-spark-app/lightweight.py",
+		"--job-name", jobName,
+		"--spec-path", jobSpecPath,
+		"--concurrency", "2",
+		"--batch-size", "100",
+	}, container.Args)
+	assert.Equal(t, driverMemory, container.Resources.Limits.Memory().String())
+	assert.Equal(t, jobName, podSpec.Volumes[0].ConfigMap.Name)
+	assert.Equal(t, jobName, podSpec.Volumes[1].Secret.SecretName)
+
+	job.Config.Lightweight.EndpointURL = "my-model-1.my-project.example.com"
+	job.Config.Lightweight.Protocol = protocol.HttpJson
+	job.Config.RetryPolicy = &models.PredictionJobRetryPolicy{MaxAttempts: 3}
+	job.Attempt = 2
+	kubeJob, err = CreateLightweightJobResource(job)
+	assert.NoError(t, err)
+	// the retries are resubmitted by the controller as a new kubernetes job
+	assert.Equal(t, int32(0), *kubeJob.Spec.BackoffLimit)
+	assert.Equal(t, jobName+"-2", kubeJob.Name)
+	assert.Equal(t, jobName, kubeJob.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	assert.Equal(t, []string{"--endpoint-url", "my-model-1.my-project.example.com", "--endpoint-protocol", "HTTP_JSON"}, kubeJob.Spec.Template.Spec.Containers[0].Args[10:])
+}
+
+func TestKubeJobStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    batchv1.JobStatus
+		wantState models.State
+		wantError string
+	}{
+		{
+			name:      "not started",
+			status:    batchv1.JobStatus{},
+			wantState: models.JobPending,
+		},
+		{
+			name:      "active",
+			status:    batchv1.JobStatus{Active: 1},
+			wantState: models.JobRunning,
+		},
+		{
+			name: "complete",
+			status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}},
+			wantState: models.JobCompleted,
+		},
+		{
+			name: "failed",
+			status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+			}},
+			wantState: models.JobFailed,
+			wantError: "Job has reached the specified backoff limit",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state, errorMessage := kubeJobStatus(&batchv1.Job{Status: test.status})
+			assert.Equal(t, test.wantState, state)
+			assert.Equal(t, test.wantError, errorMessage)
+		})
+	}
+}
+
+func TestSubmitLightweightJob(t *testing.T) {
+	job := newLightweightPredictionJob()
+
+	mockStorage := &mocks.PredictionJobStorage{}
+	mockStorage.On("Save", job).Return(nil)
+
+	mockMlpAPIClient := &mlpMock.APIClient{}
+	mockMlpAPIClient.On("GetPlainSecretByNameAndProjectID", context.Background(), secret.Name, int32(1)).Return(secret, nil)
+
+	mockSparkClient := &sparkOpFake.Clientset{}
+	mockKubeClient := &fake2.Clientset{}
+	mockKubeClient.PrependReactor("get", "namespaces", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		}, nil
+	})
+	var submitted *batchv1.Job
+	mockKubeClient.PrependReactor("create", "jobs", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		submitted = action.(ktesting.CreateAction).GetObject().(*batchv1.Job)
+		return true, submitted, nil
+	})
+
+	mockManifestManager := &batchMock.ManifestManager{}
+	mockManifestManager.On("CreateSecret", context.Background(), jobName, defaultNamespace, secret.Data).Return(jobName, nil)
+	mockManifestManager.On("CreateJobSpec", context.Background(), jobName, defaultNamespace, job.Config.JobConfig).Return(jobName, nil)
+
+	clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+	ctl := NewController(mockStorage, mockMlpAPIClient, mockSparkClient, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil)
+
+	err := ctl.Submit(context.Background(), job, defaultNamespace)
+	assert.NoError(t, err)
+
+	expectedJob, _ := CreateLightweightJobResource(job)
+	assert.Equal(t, expectedJob, submitted)
+	// spark application and its driver authorization are not created
+	assert.Len(t, mockSparkClient.Fake.Actions(), 0)
+	mockManifestManager.AssertNotCalled(t, "CreateDriverAuthorization", mock.Anything, mock.Anything)
+	mockManifestManager.AssertExpectations(t)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestUpdateLightweightJobStatus(t *testing.T) {
+	job := newLightweightPredictionJob()
+	kubeJob, _ := CreateLightweightJobResource(job)
+	kubeJob.Namespace = defaultNamespace
+	kubeJob.Status.Active = 1
+
+	mockStorage := &mocks.PredictionJobStorage{}
+	mockStorage.On("ListPendingRetries", "env1").Return(nil, nil).Maybe()
+	mockStorage.On("Get", job.ID).Return(job, nil)
+	mockStorage.On("Save", job).Return(nil)
+	mockStorage.On("SaveAttempt", mock.Anything).Return(nil)
+
+	mockManifestManager := &batchMock.ManifestManager{}
+	mockManifestManager.On("DeleteSecret", context.Background(), jobName, defaultNamespace).Return(nil)
+	mockManifestManager.On("DeleteJobSpec", context.Background(), jobName, defaultNamespace).Return(nil)
+
+	clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+	ctl := NewController(mockStorage, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, &fake2.Clientset{}, mockManifestManager, "env1", clusterMetadata, nil).(*controller)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go ctl.Run(stopCh)
+	time.Sleep(10 * time.Millisecond)
+
+	// unchanged status is not queued
+	ctl.onJobUpdate(kubeJob, kubeJob.DeepCopy())
+	assert.Equal(t, 0, ctl.queue.Len())
+
+	completedJob := kubeJob.DeepCopy()
+	completedJob.Status.Active = 0
+	completedJob.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+	}
+	_ = ctl.jobInformer.GetIndexer().Add(completedJob)
+	ctl.onJobUpdate(kubeJob, completedJob)
+
+	// wait until the state sync happen in the background
+	time.Sleep(100 * time.Millisecond)
+	mockStorage.AssertExpectations(t)
+	mockManifestManager.AssertExpectations(t)
+	assert.Equal(t, models.JobCompleted, job.Status)
+
+	attempt := storageCalls(mockStorage, "SaveAttempt")[0].Arguments[0].(*models.PredictionJobAttempt)
+	assert.Equal(t, 1, attempt.Attempt)
+	assert.Equal(t, models.JobCompleted, attempt.Status)
+	assert.Equal(t, jobName, attempt.SparkApplicationName)
+	assert.Equal(t, defaultNamespace, attempt.Namespace)
+
+	// the completed job is ignored when it's listed again
+	ctl.onJobAdd(completedJob)
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, storageCalls(mockStorage, "Save"), 1)
+}
+
+func TestUpdateLightweightJobStatusWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name              string
+		podStatus         corev1.PodStatus
+		wantFailureReason models.FailureReason
+		wantRetry         bool
+	}{
+		{
+			name:              "evicted pod is retried",
+			podStatus:         corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: memory."},
+			wantFailureReason: models.FailureReasonPreemption,
+			wantRetry:         true,
+		},
+		{
+			name: "out of memory is not retried by default",
+			podStatus: corev1.PodStatus{Phase: corev1.PodFailed, ContainerStatuses: []corev1.ContainerStatus{
+				{Name: lightweightContainer, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}},
+			}},
+			wantFailureReason: models.FailureReasonOutOfMemory,
+			wantRetry:         false,
+		},
+		{
+			name: "application error is not retried",
+			podStatus: corev1.PodStatus{Phase: corev1.PodFailed, ContainerStatuses: []corev1.ContainerStatus{
+				{Name: lightweightContainer, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+			}},
+			wantFailureReason: models.FailureReasonApplication,
+			wantRetry:         false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := newLightweightPredictionJob()
+			job.Status = models.JobRunning
+			job.Attempt = 1
+			job.Config.RetryPolicy = &models.PredictionJobRetryPolicy{MaxAttempts: 2, Backoff: "10ms"}
+
+			failedJob, _ := CreateLightweightJobResource(job)
+			failedJob.Namespace = defaultNamespace
+			failedJob.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+			}
+
+			mockStorage := &mocks.PredictionJobStorage{}
+			mockStorage.On("ListPendingRetries", "env1").Return(nil, nil).Maybe()
+			mockStorage.On("Get", job.ID).Return(job, nil)
+			mockStorage.On("Save", job).Return(nil)
+			mockStorage.On("SaveAttempt", mock.Anything).Return(nil)
+
+			mockKubeClient := &fake2.Clientset{}
+			mockKubeClient.PrependReactor("list", "pods", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				assert.Equal(t, "job-name="+jobName, action.(ktesting.ListAction).GetListRestrictions().Labels.String())
+				return true, &corev1.PodList{Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: jobName + "-abcde", Labels: map[string]string{"job-name": jobName}}, Status: test.podStatus},
+				}}, nil
+			})
+			var resubmitted *batchv1.Job
+			mockKubeClient.PrependReactor("create", "jobs", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				resubmitted = action.(ktesting.CreateAction).GetObject().(*batchv1.Job)
+				return true, resubmitted, nil
+			})
+
+			mockManifestManager := &batchMock.ManifestManager{}
+			if !test.wantRetry {
+				mockManifestManager.On("DeleteSecret", context.Background(), jobName, defaultNamespace).Return(nil)
+				mockManifestManager.On("DeleteJobSpec", context.Background(), jobName, defaultNamespace).Return(nil)
+			}
+
+			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+			ctl := NewController(mockStorage, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil).(*controller)
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			go ctl.Run(stopCh)
+			time.Sleep(10 * time.Millisecond)
+
+			// the failed job is reconciled when it's listed
+			_ = ctl.jobInformer.GetIndexer().Add(failedJob)
+			ctl.onJobAdd(failedJob)
+
+			// wait until the state sync and the retry happen in the background
+			time.Sleep(500 * time.Millisecond)
+			mockManifestManager.AssertExpectations(t)
+
+			firstAttempt := storageCalls(mockStorage, "SaveAttempt")[0].Arguments[0].(*models.PredictionJobAttempt)
+			assert.Equal(t, 1, firstAttempt.Attempt)
+			assert.Equal(t, models.JobFailed, firstAttempt.Status)
+			assert.Equal(t, test.wantFailureReason, firstAttempt.FailureReason)
+			assert.Equal(t, jobName, firstAttempt.SparkApplicationName)
+			assert.Equal(t, jobName+"-abcde", firstAttempt.DriverPodName)
+			assert.Contains(t, firstAttempt.Error, "pod "+jobName+"-abcde failed")
+
+			if !test.wantRetry {
+				assert.Nil(t, resubmitted)
+				assert.Equal(t, models.JobFailed, job.Status)
+				assert.Equal(t, 1, job.Attempt)
+				return
+			}
+
+			assert.NotNil(t, resubmitted)
+			assert.Equal(t, jobName+"-2", resubmitted.Name)
+			assert.Equal(t, models.JobPending, job.Status)
+			assert.Equal(t, 2, job.Attempt)
+			assert.Nil(t, job.NextRetryAt)
+
+			secondAttempt := storageCalls(mockStorage, "SaveAttempt")[1].Arguments[0].(*models.PredictionJobAttempt)
+			assert.Equal(t, 2, secondAttempt.Attempt)
+			assert.Equal(t, jobName+"-2", secondAttempt.SparkApplicationName)
+
+			// events of the kubernetes job of the previous attempt are ignored
+			ctl.onJobAdd(failedJob)
+			time.Sleep(100 * time.Millisecond)
+			assert.Len(t, storageCalls(mockStorage, "SaveAttempt"), 2)
+			assert.Equal(t, models.JobPending, job.Status)
+		})
+	}
+}
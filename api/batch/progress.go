@@ -0,0 +1,233 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/apis/sparkoperator.k8s.io/v1beta2"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+const (
+	// progressInterval is the interval of collecting the progress of running prediction jobs
+	progressInterval = 30 * time.Second
+	// progressTimeout is the timeout of querying the spark UI of a driver
+	progressTimeout = 10 * time.Second
+)
+
+var (
+	progressLabels = []string{"project", "model", "version", "job"}
+
+	BatchTasksTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "batch_tasks_total",
+			Namespace: "merlin_api",
+			Help:      "Number of tasks of the stages submitted so far by a running batch prediction",
+		},
+		progressLabels,
+	)
+	BatchTasksCompleted = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "batch_tasks_completed",
+			Namespace: "merlin_api",
+			Help:      "Number of completed tasks of a running batch prediction",
+		},
+		progressLabels,
+	)
+	BatchProgressPercentage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "batch_progress_percentage",
+			Namespace: "merlin_api",
+			Help:      "Percentage of completed tasks of a running batch prediction",
+		},
+		progressLabels,
+	)
+	BatchRowsRead = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "batch_rows_read",
+			Namespace: "merlin_api",
+			Help:      "Number of rows read by a running batch prediction",
+		},
+		progressLabels,
+	)
+	BatchRowsWritten = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "batch_rows_written",
+			Namespace: "merlin_api",
+			Help:      "Number of rows written by a running batch prediction",
+		},
+		progressLabels,
+	)
+	BatchActiveExecutors = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "batch_active_executors",
+			Namespace: "merlin_api",
+			Help:      "Number of running executors of a running batch prediction",
+		},
+		progressLabels,
+	)
+)
+
+func init() {
+	prometheus.MustRegister(BatchTasksTotal, BatchTasksCompleted, BatchProgressPercentage, BatchRowsRead, BatchRowsWritten, BatchActiveExecutors)
+}
+
+// ProgressFetcher collects the progress of a running spark application
+type ProgressFetcher interface {
+	// FetchProgress returns the progress of the given spark application
+	FetchProgress(ctx context.Context, sparkApp *v1beta2.SparkApplication) (*models.PredictionJobProgress, error)
+}
+
+// sparkStage is a stage returned by the monitoring REST API of the spark UI
+// https://spark.apache.org/docs/latest/monitoring.html#rest-api
+type sparkStage struct {
+	StageID          int    `json:"stageId"`
+	AttemptID        int    `json:"attemptId"`
+	Status           string `json:"status"`
+	NumTasks         int    `json:"numTasks"`
+	NumCompleteTasks int    `json:"numCompleteTasks"`
+	NumFailedTasks   int    `json:"numFailedTasks"`
+	InputRecords     int64  `json:"inputRecords"`
+	OutputRecords    int64  `json:"outputRecords"`
+}
+
+type sparkUIProgressFetcher struct {
+	kubeClient kubernetes.Interface
+}
+
+// NewProgressFetcher creates a ProgressFetcher querying the spark UI of the driver through the kubernetes service proxy,
+// since the driver isn't necessarily reachable from merlin
+func NewProgressFetcher(kubeClient kubernetes.Interface) ProgressFetcher {
+	return &sparkUIProgressFetcher{kubeClient: kubeClient}
+}
+
+func (f *sparkUIProgressFetcher) FetchProgress(ctx context.Context, sparkApp *v1beta2.SparkApplication) (*models.PredictionJobProgress, error) {
+	progress := &models.PredictionJobProgress{
+		ActiveExecutors: countActiveExecutors(sparkApp),
+	}
+
+	driverInfo := sparkApp.Status.DriverInfo
+	if driverInfo.WebUIServiceName == "" || sparkApp.Status.SparkApplicationID == "" {
+		// the spark UI isn't available yet
+		return progress, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, progressTimeout)
+	defer cancel()
+
+	path := fmt.Sprintf("/api/v1/applications/%s/stages", sparkApp.Status.SparkApplicationID)
+	body, err := f.kubeClient.CoreV1().Services(sparkApp.Namespace).
+		ProxyGet("http", driverInfo.WebUIServiceName, strconv.Itoa(int(driverInfo.WebUIPort)), path, nil).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying spark UI of %s in namespace %s: %w", sparkApp.Name, sparkApp.Namespace, err)
+	}
+
+	var stages []sparkStage
+	if err := json.Unmarshal(body, &stages); err != nil {
+		return nil, fmt.Errorf("failed parsing stages of %s in namespace %s: %w", sparkApp.Name, sparkApp.Namespace, err)
+	}
+
+	addStages(progress, stages)
+	return progress, nil
+}
+
+// addStages aggregates the stages of a spark application into the progress. Only the latest attempt of a retried stage
+// is counted. The source is read again by every spark job of the application (e.g. counting it before writing the
+// predictions), so the rows read are the most rows read by a single stage rather than their sum.
+func addStages(progress *models.PredictionJobProgress, stages []sparkStage) {
+	latest := make(map[int]sparkStage, len(stages))
+	for _, stage := range stages {
+		if prev, ok := latest[stage.StageID]; ok && prev.AttemptID > stage.AttemptID {
+			continue
+		}
+		latest[stage.StageID] = stage
+	}
+
+	for _, stage := range latest {
+		progress.StagesTotal++
+		progress.TasksTotal += stage.NumTasks
+		switch stage.Status {
+		case "COMPLETE":
+			progress.StagesCompleted++
+			progress.TasksCompleted += stage.NumCompleteTasks
+		case "SKIPPED":
+			// tasks of a skipped stage are never run as its output is reused from a previous stage
+			progress.StagesCompleted++
+			progress.TasksCompleted += stage.NumTasks
+		default:
+			progress.TasksCompleted += stage.NumCompleteTasks
+		}
+		progress.TasksFailed += stage.NumFailedTasks
+		if stage.InputRecords > progress.RowsRead {
+			progress.RowsRead = stage.InputRecords
+		}
+		progress.RowsWritten += stage.OutputRecords
+	}
+	progress.UpdatePercentage()
+}
+
+func countActiveExecutors(sparkApp *v1beta2.SparkApplication) int {
+	count := 0
+	for _, state := range sparkApp.Status.ExecutorState {
+		if state == v1beta2.ExecutorRunningState {
+			count++
+		}
+	}
+	return count
+}
+
+// recordProgressMetrics exports the progress of a prediction job as prometheus metrics
+func recordProgressMetrics(namespace string, job *models.PredictionJob) {
+	labels := prometheus.Labels{
+		"project": namespace,
+		"model":   getModelName(job.Name),
+		"version": job.VersionID.String(),
+		"job":     job.ID.String(),
+	}
+
+	progress := job.Progress
+	BatchTasksTotal.With(labels).Set(float64(progress.TasksTotal))
+	BatchTasksCompleted.With(labels).Set(float64(progress.TasksCompleted))
+	BatchProgressPercentage.With(labels).Set(progress.Percentage)
+	BatchRowsRead.With(labels).Set(float64(progress.RowsRead))
+	BatchRowsWritten.With(labels).Set(float64(progress.RowsWritten))
+	BatchActiveExecutors.With(labels).Set(float64(progress.ActiveExecutors))
+}
+
+// deleteProgressMetrics removes the progress metrics of a prediction job once it's no longer running
+func deleteProgressMetrics(namespace string, job *models.PredictionJob) {
+	labels := prometheus.Labels{
+		"project": namespace,
+		"model":   getModelName(job.Name),
+		"version": job.VersionID.String(),
+		"job":     job.ID.String(),
+	}
+
+	BatchTasksTotal.Delete(labels)
+	BatchTasksCompleted.Delete(labels)
+	BatchProgressPercentage.Delete(labels)
+	BatchRowsRead.Delete(labels)
+	BatchRowsWritten.Delete(labels)
+	BatchActiveExecutors.Delete(labels)
+}
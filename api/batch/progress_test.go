@@ -0,0 +1,205 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/apis/sparkoperator.k8s.io/v1beta2"
+	sparkOpFake "github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/clientset/versioned/fake"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	ktesting "k8s.io/client-go/testing"
+
+	batchMock "github.com/caraml-dev/merlin/batch/mocks"
+	"github.com/caraml-dev/merlin/cluster"
+	mlpMock "github.com/caraml-dev/merlin/mlp/mocks"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+type fakeResponseWrapper struct {
+	body []byte
+	err  error
+}
+
+func (f *fakeResponseWrapper) DoRaw(context.Context) ([]byte, error) {
+	return f.body, f.err
+}
+
+func (f *fakeResponseWrapper) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.body)), f.err
+}
+
+const stagesResponse = `[
+  {"status": "ACTIVE", "stageId": 3, "numTasks": 200, "numCompleteTasks": 50, "numFailedTasks": 1, "inputRecords": 0, "outputRecords": 5000},
+  {"status": "SKIPPED", "stageId": 2, "numTasks": 100, "numCompleteTasks": 0, "numFailedTasks": 0, "inputRecords": 0, "outputRecords": 0},
+  {"status": "COMPLETE", "stageId": 1, "numTasks": 100, "numCompleteTasks": 100, "numFailedTasks": 0, "inputRecords": 100000, "outputRecords": 0}
+]`
+
+func newRunningSparkApp() *v1beta2.SparkApplication {
+	app := sparkApp.DeepCopy()
+	app.Namespace = defaultNamespace
+	app.Status.AppState.State = v1beta2.RunningState
+	app.Status.SparkApplicationID = "spark-1234"
+	app.Status.DriverInfo.WebUIServiceName = jobName + "-ui-svc"
+	app.Status.DriverInfo.WebUIPort = 4040
+	app.Status.ExecutorState = map[string]v1beta2.ExecutorState{
+		"exec-1": v1beta2.ExecutorRunningState,
+		"exec-2": v1beta2.ExecutorRunningState,
+		"exec-3": v1beta2.ExecutorFailedState,
+	}
+	return app
+}
+
+func TestFetchProgress(t *testing.T) {
+	kubeClient := &fake2.Clientset{}
+	kubeClient.PrependProxyReactor("services", func(action ktesting.Action) (bool, restclient.ResponseWrapper, error) {
+		proxy := action.(ktesting.ProxyGetAction)
+		assert.Equal(t, jobName+"-ui-svc", proxy.GetName())
+		assert.Equal(t, "4040", proxy.GetPort())
+		assert.Equal(t, "/api/v1/applications/spark-1234/stages", proxy.GetPath())
+		return true, &fakeResponseWrapper{body: []byte(stagesResponse)}, nil
+	})
+
+	progress, err := NewProgressFetcher(kubeClient).FetchProgress(context.Background(), newRunningSparkApp())
+	require.NoError(t, err)
+	assert.Equal(t, &models.PredictionJobProgress{
+		StagesTotal:     3,
+		StagesCompleted: 2,
+		TasksTotal:      400,
+		TasksCompleted:  250,
+		TasksFailed:     1,
+		Percentage:      62.5,
+		RowsRead:        100000,
+		RowsWritten:     5000,
+		ActiveExecutors: 2,
+	}, progress)
+}
+
+func TestFetchProgress_UINotAvailable(t *testing.T) {
+	app := newRunningSparkApp()
+	app.Status.DriverInfo.WebUIServiceName = ""
+
+	progress, err := NewProgressFetcher(&fake2.Clientset{}).FetchProgress(context.Background(), app)
+	require.NoError(t, err)
+	assert.Equal(t, &models.PredictionJobProgress{ActiveExecutors: 2}, progress)
+}
+
+func TestUpdateProgress(t *testing.T) {
+	job := *predictionJob
+	job.Status = models.JobRunning
+	job.Attempt = 1
+
+	app := newRunningSparkApp()
+	progress := &models.PredictionJobProgress{
+		StagesTotal:     3,
+		StagesCompleted: 2,
+		TasksTotal:      400,
+		TasksCompleted:  250,
+		Percentage:      62.5,
+		RowsRead:        100000,
+		ActiveExecutors: 2,
+	}
+
+	mockStorage := &mocks.PredictionJobStorage{}
+	mockStorage.On("Get", job.ID).Return(&job, nil)
+	mockStorage.On("SaveProgress", job.ID, progress).Return(nil)
+
+	mockFetcher := &batchMock.ProgressFetcher{}
+	mockFetcher.On("FetchProgress", mock.Anything, app).Return(progress, nil)
+
+	clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+	ctl := NewController(mockStorage, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, &fake2.Clientset{}, &batchMock.ManifestManager{}, "env1", clusterMetadata, nil).(*controller)
+	ctl.progressFetcher = mockFetcher
+	_ = ctl.informer.GetIndexer().Add(app)
+
+	key := defaultNamespace + "/" + app.Name
+	err := ctl.updateProgress(context.Background(), key)
+	require.NoError(t, err)
+
+	mockStorage.AssertExpectations(t)
+	mockFetcher.AssertExpectations(t)
+	assert.False(t, progress.UpdatedAt.IsZero())
+	assert.WithinDuration(t, time.Now(), progress.UpdatedAt, time.Minute)
+
+	labels := prometheus.Labels{"project": defaultNamespace, "model": getModelName(jobName), "version": job.VersionID.String(), "job": job.ID.String()}
+	assert.Equal(t, 250.0, testutil.ToFloat64(BatchTasksCompleted.With(labels)))
+	assert.Equal(t, 62.5, testutil.ToFloat64(BatchProgressPercentage.With(labels)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(BatchActiveExecutors.With(labels)))
+
+	// a job which is no longer running isn't updated
+	job.Status = models.JobCompleted
+	err = ctl.updateProgress(context.Background(), key)
+	require.NoError(t, err)
+	mockFetcher.AssertNumberOfCalls(t, "FetchProgress", 1)
+}
+
+func TestAddStages(t *testing.T) {
+	// the source is read by both the count and the write of the predictions, and stage 2 is retried once
+	stages := []sparkStage{
+		{StageID: 0, Status: "COMPLETE", NumTasks: 10, NumCompleteTasks: 10, InputRecords: 1000},
+		{StageID: 1, Status: "COMPLETE", NumTasks: 1, NumCompleteTasks: 1},
+		{StageID: 2, AttemptID: 0, Status: "FAILED", NumTasks: 10, NumCompleteTasks: 4, NumFailedTasks: 1, InputRecords: 400, OutputRecords: 400},
+		{StageID: 2, AttemptID: 1, Status: "ACTIVE", NumTasks: 10, NumCompleteTasks: 5, InputRecords: 500, OutputRecords: 500},
+	}
+
+	progress := &models.PredictionJobProgress{}
+	addStages(progress, stages)
+	assert.Equal(t, &models.PredictionJobProgress{
+		StagesTotal:     3,
+		StagesCompleted: 2,
+		TasksTotal:      21,
+		TasksCompleted:  16,
+		Percentage:      float64(16) / 21 * 100,
+		RowsRead:        1000,
+		RowsWritten:     500,
+	}, progress)
+}
+
+func TestStartProgress(t *testing.T) {
+	app := newRunningSparkApp()
+	key := defaultNamespace + "/" + app.Name
+
+	ctl := NewController(&mocks.PredictionJobStorage{}, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, &fake2.Clientset{}, &batchMock.ManifestManager{}, "env1", cluster.Metadata{}, nil).(*controller)
+
+	// the initial list and the following resyncs only start a single chain of progress updates
+	ctl.onAdd(app)
+	ctl.onUpdate(app, app)
+	ctl.onUpdate(app, app)
+	assert.Equal(t, map[string]struct{}{key: {}}, ctl.progressPolling)
+
+	// the updates stop once the spark application is gone and are started again by the next resync
+	require.NoError(t, ctl.updateProgress(context.Background(), key))
+	assert.Empty(t, ctl.progressPolling)
+
+	ctl.onUpdate(app, app)
+	assert.Equal(t, map[string]struct{}{key: {}}, ctl.progressPolling)
+
+	completed := app.DeepCopy()
+	completed.Status.AppState.State = v1beta2.CompletedState
+	ctl.progressPolling = map[string]struct{}{}
+	ctl.onAdd(completed)
+	assert.Empty(t, ctl.progressPolling)
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/apis/sparkoperator.k8s.io/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+)
+
+const (
+	// qualityMetricsPath is where the prediction job writes its quality metrics, it's the default termination message
+	// path of kubernetes so that the metrics can be read from the status of the pod once it terminated
+	qualityMetricsPath = corev1.TerminationMessagePathDefault
+	// sparkDriverContainer is the name of the driver container created by the spark operator
+	sparkDriverContainer = "spark-kubernetes-driver"
+)
+
+// fetchQualityMetrics reads the quality metrics reported by the prediction job in the termination message of its container
+func (c *controller) fetchQualityMetrics(ctx context.Context, namespace, podName, containerName string) (*models.PredictionJobQualityMetrics, error) {
+	pod, err := c.kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed getting pod %s in namespace %s: %w", podName, namespace, err)
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName || status.State.Terminated == nil {
+			continue
+		}
+
+		var metrics models.PredictionJobQualityMetrics
+		if err := json.Unmarshal([]byte(status.State.Terminated.Message), &metrics); err != nil {
+			return nil, fmt.Errorf("failed parsing quality metrics of pod %s in namespace %s: %w", podName, namespace, err)
+		}
+		return &metrics, nil
+	}
+	return nil, fmt.Errorf("container %s of pod %s in namespace %s hasn't terminated", containerName, podName, namespace)
+}
+
+// applyQuality evaluates the quality checks of a completed or failed prediction job once, and fails the prediction job
+// and its attempt if its output failed them. A failed prediction job is only evaluated if it reported its quality
+// metrics, i.e. it failed after computing its predictions, e.g. because they failed the checks.
+func (c *controller) applyQuality(ctx context.Context, predictionJob *models.PredictionJob, attempt *models.PredictionJobAttempt, sparkApp *v1beta2.SparkApplication) {
+	if predictionJob.Quality == nil {
+		metrics, err := c.fetchQualityMetrics(ctx, sparkApp.Namespace, sparkApp.Status.DriverInfo.PodName, sparkDriverContainer)
+		if err != nil {
+			if predictionJob.Status != models.JobCompleted {
+				return
+			}
+			log.Warnf("failed fetching quality metrics of prediction job %s: %v", predictionJob.ID, err)
+		}
+		c.evaluateQuality(predictionJob, metrics)
+	}
+
+	predictionJob.ApplyQuality()
+	if predictionJob.Status == models.JobFailed && predictionJob.QualityStatus == models.QualityStatusFailed {
+		attempt.Status = predictionJob.Status
+		attempt.Error = predictionJob.Error
+		attempt.FailureReason = models.FailureReasonDataQuality
+	}
+}
+
+// evaluateQuality runs the quality checks of a prediction job on the metrics reported by its driver
+func (c *controller) evaluateQuality(predictionJob *models.PredictionJob, metrics *models.PredictionJobQualityMetrics) {
+	checks := predictionJob.Config.QualityChecks
+
+	var reference *models.PredictionJobQualityMetrics
+	if checks.Drift != nil {
+		referenceJob, err := c.store.Get(checks.Drift.ReferenceJobID)
+		if err != nil {
+			log.Warnf("failed getting reference job %s of prediction job %s: %v", checks.Drift.ReferenceJobID, predictionJob.ID, err)
+		} else if referenceJob.Quality != nil {
+			reference = referenceJob.Quality.Metrics
+		}
+	}
+
+	results, status := checks.Evaluate(metrics, reference)
+	predictionJob.QualityStatus = status
+	predictionJob.Quality = &models.PredictionJobQuality{
+		Metrics:     metrics,
+		Checks:      results,
+		EvaluatedAt: time.Now(),
+	}
+}
+
+// setReferenceQuantiles passes the quantiles of the reference job of the drift check to the prediction job, so that it
+// checks the drift of its predictions before writing them. The drift is only checked once the prediction job completed
+// if the reference job has no quality metrics yet.
+func (c *controller) setReferenceQuantiles(predictionJob *models.PredictionJob) {
+	checks := predictionJob.Config.QualityChecks
+	if checks == nil || checks.Drift == nil {
+		return
+	}
+
+	checks.Drift.ReferenceQuantiles = nil
+	referenceJob, err := c.store.Get(checks.Drift.ReferenceJobID)
+	if err != nil {
+		log.Warnf("failed getting reference job %s of prediction job %s: %v", checks.Drift.ReferenceJobID, predictionJob.ID, err)
+		return
+	}
+	if referenceJob.Quality != nil && referenceJob.Quality.Metrics != nil {
+		checks.Drift.ReferenceQuantiles = referenceJob.Quality.Metrics.Quantiles
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/apis/sparkoperator.k8s.io/v1beta2"
+	sparkOpFake "github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	batchMock "github.com/caraml-dev/merlin/batch/mocks"
+	"github.com/caraml-dev/merlin/cluster"
+	mlpMock "github.com/caraml-dev/merlin/mlp/mocks"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func driverPod(podName, terminationMessage string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: defaultNamespace},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: sparkDriverContainer,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: terminationMessage},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUpdateStatusWithQualityChecks(t *testing.T) {
+	referenceJobID := models.ID(10)
+	referenceJob := &models.PredictionJob{
+		ID: referenceJobID,
+		Quality: &models.PredictionJobQuality{
+			Metrics: &models.PredictionJobQualityMetrics{Quantiles: []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}},
+		},
+	}
+	maxNullFraction := 0.1
+
+	tests := []struct {
+		name              string
+		action            models.QualityCheckAction
+		appState          v1beta2.ApplicationStateType
+		pod               *corev1.Pod
+		wantStatus        models.State
+		wantQualityStatus models.QualityStatus
+		wantFailureReason models.FailureReason
+	}{
+		{
+			name:              "passed",
+			pod:               driverPod(jobName+"-driver", `{"source_rows":100,"output_rows":100,"null_count":0,"min":0,"max":1,"quantiles":[0,0.1,0.2,0.3,0.4,0.5,0.6,0.7,0.8,0.9,1]}`),
+			wantStatus:        models.JobCompleted,
+			wantQualityStatus: models.QualityStatusPassed,
+		},
+		{
+			name:              "all null predictions fail the prediction job",
+			pod:               driverPod(jobName+"-driver", `{"source_rows":100,"output_rows":100,"null_count":100}`),
+			wantStatus:        models.JobFailed,
+			wantQualityStatus: models.QualityStatusFailed,
+			wantFailureReason: models.FailureReasonDataQuality,
+		},
+		{
+			name:              "all null predictions with warn action",
+			action:            models.QualityCheckActionWarn,
+			pod:               driverPod(jobName+"-driver", `{"source_rows":100,"output_rows":100,"null_count":100}`),
+			wantStatus:        models.JobCompleted,
+			wantQualityStatus: models.QualityStatusWarning,
+		},
+		{
+			name:              "predictions failed the checks before writing the sink",
+			appState:          v1beta2.FailedState,
+			pod:               driverPod(jobName+"-driver", `{"source_rows":100,"output_rows":100,"null_count":100}`),
+			wantStatus:        models.JobFailed,
+			wantQualityStatus: models.QualityStatusFailed,
+			wantFailureReason: models.FailureReasonDataQuality,
+		},
+		{
+			name:              "failed before computing the predictions",
+			appState:          v1beta2.FailedState,
+			pod:               driverPod("another-pod", ""),
+			wantStatus:        models.JobFailed,
+			wantFailureReason: models.FailureReasonApplication,
+		},
+		{
+			name:              "metrics not reported",
+			pod:               driverPod("another-pod", ""),
+			wantStatus:        models.JobFailed,
+			wantQualityStatus: models.QualityStatusFailed,
+			wantFailureReason: models.FailureReasonDataQuality,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := *predictionJob.Config
+			config.QualityChecks = &models.PredictionJobQualityChecks{
+				Action:          tt.action,
+				MaxNullFraction: &maxNullFraction,
+				Drift:           &models.PredictionJobDriftCheck{ReferenceJobID: referenceJobID, MaxPSI: 0.2},
+			}
+			job := *predictionJob
+			job.Status = models.JobRunning
+			job.Config = &config
+
+			completedApp, _ := CreateSparkApplicationResource(&job)
+			assert.Contains(t, completedApp.Spec.Arguments, qualityMetricsPath)
+			completedApp.Namespace = defaultNamespace
+			completedApp.Status.AppState.State = v1beta2.CompletedState
+			if tt.appState != "" {
+				completedApp.Status.AppState.State = tt.appState
+			}
+			completedApp.Status.DriverInfo.PodName = jobName + "-driver"
+
+			mockStorage := &mocks.PredictionJobStorage{}
+			mockStorage.On("Get", job.ID).Return(&job, nil)
+			mockStorage.On("Get", referenceJobID).Return(referenceJob, nil)
+			mockStorage.On("Save", &job).Return(nil)
+			mockStorage.On("SaveAttempt", mock.Anything).Return(nil)
+
+			mockManifestManager := &batchMock.ManifestManager{}
+			mockManifestManager.On("DeleteSecret", context.Background(), jobName, defaultNamespace).Return(nil)
+			mockManifestManager.On("DeleteJobSpec", context.Background(), jobName, defaultNamespace).Return(nil)
+
+			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+			ctl := NewController(mockStorage, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, fake2.NewSimpleClientset(tt.pod), mockManifestManager, "env1", clusterMetadata, nil).(*controller)
+
+			_ = ctl.informer.GetIndexer().Add(completedApp)
+			key, _ := cache.MetaNamespaceKeyFunc(completedApp)
+			err := ctl.syncStatus(context.Background(), key)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, job.Status)
+			assert.Equal(t, tt.wantQualityStatus, job.QualityStatus)
+			assert.Equal(t, tt.wantQualityStatus != "", job.Quality != nil)
+
+			attempt := mockStorage.Calls[len(mockStorage.Calls)-1].Arguments[0].(*models.PredictionJobAttempt)
+			assert.Equal(t, tt.wantStatus, attempt.Status)
+			assert.Equal(t, tt.wantFailureReason, attempt.FailureReason)
+			mockManifestManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetReferenceQuantiles(t *testing.T) {
+	referenceJobID := models.ID(10)
+	quantiles := []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
+
+	config := *predictionJob.Config
+	config.QualityChecks = &models.PredictionJobQualityChecks{
+		Drift: &models.PredictionJobDriftCheck{ReferenceJobID: referenceJobID, MaxPSI: 0.2},
+	}
+	job := *predictionJob
+	job.Config = &config
+
+	mockStorage := &mocks.PredictionJobStorage{}
+	mockStorage.On("Get", referenceJobID).Return(&models.PredictionJob{
+		ID:      referenceJobID,
+		Quality: &models.PredictionJobQuality{Metrics: &models.PredictionJobQualityMetrics{Quantiles: quantiles}},
+	}, nil)
+
+	ctl := NewController(mockStorage, &mlpMock.APIClient{}, &sparkOpFake.Clientset{}, &fake2.Clientset{}, &batchMock.ManifestManager{}, "env1", cluster.Metadata{}, nil).(*controller)
+	ctl.setReferenceQuantiles(&job)
+	assert.Equal(t, quantiles, config.QualityChecks.Drift.ReferenceQuantiles)
+
+	// the checks are passed to the prediction job, so that it runs them before writing the sink
+	sparkApp, err := CreateSparkApplicationResource(&job)
+	assert.NoError(t, err)
+	arguments := sparkApp.Spec.Arguments
+	assert.Equal(t, "--quality-checks", arguments[len(arguments)-2])
+	assert.JSONEq(t, `{"drift":{"reference_job_id":10,"max_psi":0.2,"reference_quantiles":[0,0.1,0.2,0.3,0.4,0.5,0.6,0.7,0.8,0.9,1]}}`, arguments[len(arguments)-1])
+}
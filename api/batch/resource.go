@@ -15,6 +15,7 @@
 package batch
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -70,6 +71,10 @@ var (
 		OnSubmissionFailureRetryInterval: &submissionFailureRetryInterval,
 		OnFailureRetryInterval:           &failureRetryInterval,
 	}
+	// noRetryPolicy is used when the prediction job has its own retry policy, so that the controller resubmits it
+	noRetryPolicy = v1beta2.RestartPolicy{
+		Type: v1beta2.Never,
+	}
 
 	defaultToleration = corev1.Toleration{
 		Key:      "batch-job",
@@ -96,13 +101,21 @@ func CreateSparkApplicationResource(job *models.PredictionJob) (*v1beta2.SparkAp
 
 	return &v1beta2.SparkApplication{
 		ObjectMeta: v1.ObjectMeta{
-			Name:   job.Name,
+			Name:   SparkApplicationName(job),
 			Labels: createLabel(job),
 		},
 		Spec: spec,
 	}, nil
 }
 
+// SparkApplicationName returns the name of the spark application, or kubernetes job, of the current attempt of the prediction job
+func SparkApplicationName(job *models.PredictionJob) string {
+	if job.Attempt <= 1 {
+		return job.Name
+	}
+	return fmt.Sprintf("%s-%d", job.Name, job.Attempt)
+}
+
 func createSpec(job *models.PredictionJob) (v1beta2.SparkApplicationSpec, error) {
 	driverSpec, err := createDriverSpec(job)
 	if err != nil {
@@ -116,25 +129,39 @@ func createSpec(job *models.PredictionJob) (v1beta2.SparkApplicationSpec, error)
 
 	var mainApplicationPath = "local://" + job.Config.MainAppPath
 
+	restartPolicy := defaultRetryPolicy
+	if job.Config.RetryPolicy != nil {
+		restartPolicy = noRetryPolicy
+	}
+
+	arguments := []string{
+		"--job-name",
+		job.Name,
+		"--spec-path",
+		jobSpecPath,
+	}
+	if job.Config.QualityChecks != nil {
+		qualityChecks, err := json.Marshal(job.Config.QualityChecks)
+		if err != nil {
+			return v1beta2.SparkApplicationSpec{}, fmt.Errorf("invalid quality checks: %w", err)
+		}
+		arguments = append(arguments, "--quality-metrics-path", qualityMetricsPath, "--quality-checks", string(qualityChecks))
+	}
+
 	return v1beta2.SparkApplicationSpec{
 		Type:                sparkType,
 		SparkVersion:        sparkVersion,
 		Mode:                sparkMode,
 		Image:               &job.Config.ImageRef,
 		MainApplicationFile: &mainApplicationPath,
-		Arguments: []string{
-			"--job-name",
-			job.Name,
-			"--spec-path",
-			jobSpecPath,
-		},
-		HadoopConf:        defaultHadoopConf,
-		Driver:            driverSpec,
-		Executor:          executorSpec,
-		NodeSelector:      defaultNodeSelector,
-		RestartPolicy:     defaultRetryPolicy,
-		PythonVersion:     &pythonVersion,
-		TimeToLiveSeconds: &ttlSecond,
+		Arguments:           arguments,
+		HadoopConf:          defaultHadoopConf,
+		Driver:              driverSpec,
+		Executor:            executorSpec,
+		NodeSelector:        defaultNodeSelector,
+		RestartPolicy:       restartPolicy,
+		PythonVersion:       &pythonVersion,
+		TimeToLiveSeconds:   &ttlSecond,
 	}, nil
 }
 
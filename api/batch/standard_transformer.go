@@ -0,0 +1,159 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+const (
+	standardTransformerContainer = "transformer"
+	modelContainer               = "model"
+	// modelServerFileName is the server of the model artifact, next to the main app of spark
+	modelServerFileName = "model_server.py"
+	modelServerPort     = 8080
+
+	envBatchInputPath        = "BATCH_INPUT_PATH"
+	envBatchOutputPath       = "BATCH_OUTPUT_PATH"
+	envBatchConcurrency      = "BATCH_CONCURRENCY"
+	envBatchModelReadyURL    = "BATCH_MODEL_READY_URL"
+	envBatchModelShutdownURL = "BATCH_MODEL_SHUTDOWN_URL"
+	envBatchMaxFailedRatio   = "BATCH_MAX_FAILED_RATIO"
+	envPredictorHost         = "CARAML_PREDICTOR_HOST"
+
+	// labels and container name given by kserve to the transformer of an inference service
+	labelInferenceService      = "serving.kserve.io/inferenceservice"
+	labelComponent             = "component"
+	transformerComponent       = "transformer"
+	kserveTransformerContainer = "kserve-container"
+)
+
+// StandardTransformerPodLabelSelector returns the label selector of the transformer pods of an inference service
+func StandardTransformerPodLabelSelector(inferenceServiceName string) string {
+	return fmt.Sprintf("%s=%s,%s=%s", labelInferenceService, inferenceServiceName, labelComponent, transformerComponent)
+}
+
+// CreateStandardTransformerJobResource creates the kubernetes job replaying the source of a prediction job through
+// the standard transformer of its version endpoint. The transformer container is created from the container running
+// online, so that its image and configuration are the same, and calls either the predictor of the version endpoint
+// or a sidecar serving the model artifact.
+func CreateStandardTransformerJobResource(job *models.PredictionJob, transformer corev1.Container) (*batchv1.Job, error) {
+	transformerConfig := job.Config.StandardTransformer
+	if transformerConfig == nil {
+		return nil, fmt.Errorf("standard transformer config of job %s is not found", job.Name)
+	}
+
+	source := job.Config.JobConfig.GetGcsSource()
+	sink := job.Config.JobConfig.GetGcsSink()
+	if source == nil || sink == nil {
+		return nil, fmt.Errorf("standard transformer prediction job %s requires gcs source and sink", job.Name)
+	}
+
+	jobEnvVars, err := addEnvVars(job)
+	if err != nil {
+		return nil, err
+	}
+
+	batchEnvVars := []corev1.EnvVar{
+		{Name: envBatchInputPath, Value: source.Uri},
+		{Name: envBatchOutputPath, Value: sink.Uri},
+		{Name: envBatchConcurrency, Value: strconv.Itoa(transformerConfig.Concurrency)},
+		{Name: envBatchMaxFailedRatio, Value: strconv.FormatFloat(transformerConfig.MaxFailedRatio, 'f', -1, 64)},
+	}
+
+	var containers []corev1.Container
+	if transformerConfig.ModelTarget == models.TransformerModelTargetArtifact {
+		resources, err := createDriverResources(job)
+		if err != nil {
+			return nil, err
+		}
+
+		modelServerURL := fmt.Sprintf("http://localhost:%d", modelServerPort)
+		batchEnvVars = append(batchEnvVars,
+			corev1.EnvVar{Name: envPredictorHost, Value: fmt.Sprintf("localhost:%d", modelServerPort)},
+			corev1.EnvVar{Name: envBatchModelReadyURL, Value: modelServerURL + "/"},
+			corev1.EnvVar{Name: envBatchModelShutdownURL, Value: modelServerURL + "/shutdown"},
+		)
+
+		containers = append(containers, corev1.Container{
+			Name:  modelContainer,
+			Image: job.Config.ImageRef,
+			Args: []string{
+				"python",
+				path.Join(path.Dir(job.Config.MainAppPath), modelServerFileName),
+				"--model-uri", job.Config.JobConfig.GetModel().GetUri(),
+				"--port", strconv.Itoa(modelServerPort),
+			},
+			Env:          jobEnvVars,
+			Resources:    resources,
+			VolumeMounts: jobVolumeMounts(),
+		})
+	}
+
+	envVars := overrideEnvVars(transformer.Env, jobEnvVars)
+	envVars = overrideEnvVars(envVars, batchEnvVars)
+
+	containers = append([]corev1.Container{
+		{
+			Name:         standardTransformerContainer,
+			Image:        transformer.Image,
+			Command:      transformer.Command,
+			Args:         transformer.Args,
+			Env:          envVars,
+			Resources:    transformer.Resources,
+			VolumeMounts: jobVolumeMounts(),
+		},
+	}, containers...)
+
+	return createKubernetesJob(job, containers), nil
+}
+
+// findStandardTransformerContainer returns the standard transformer container of the first running transformer pod
+func findStandardTransformerContainer(pods []corev1.Pod) (corev1.Container, bool) {
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if container.Name == kserveTransformerContainer {
+				return container, true
+			}
+		}
+	}
+	return corev1.Container{}, false
+}
+
+// overrideEnvVars returns the environment variables of base overridden by the environment variables with the same name
+func overrideEnvVars(base []corev1.EnvVar, overrides []corev1.EnvVar) []corev1.EnvVar {
+	overridden := make(map[string]bool, len(overrides))
+	for _, ev := range overrides {
+		overridden[ev.Name] = true
+	}
+
+	envVars := make([]corev1.EnvVar, 0, len(base)+len(overrides))
+	for _, ev := range base {
+		if !overridden[ev.Name] {
+			envVars = append(envVars, ev)
+		}
+	}
+	return append(envVars, overrides...)
+}
@@ -0,0 +1,241 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+
+	sparkOpFake "github.com/GoogleCloudPlatform/spark-on-k8s-operator/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	jobspec "github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+
+	batchMock "github.com/caraml-dev/merlin/batch/mocks"
+	"github.com/caraml-dev/merlin/cluster"
+	mlpMock "github.com/caraml-dev/merlin/mlp/mocks"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/protocol"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+const inferenceServiceName = "my-model-1"
+
+var onlineTransformer = corev1.Container{
+	Name:  kserveTransformerContainer,
+	Image: "ghcr.io/caraml-dev/merlin-transformer:1.0.0",
+	Env: []corev1.EnvVar{
+		{Name: "STANDARD_TRANSFORMER_CONFIG", Value: `{"transformerConfig":{}}`},
+		{Name: envPredictorHost, Value: "my-model-1-predictor.my-project"},
+		{Name: envServiceAccountPathKey, Value: "/var/secret/feast.json"},
+	},
+	Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	},
+	ReadinessProbe: &corev1.Probe{},
+}
+
+func newStandardTransformerPredictionJob(modelTarget models.TransformerModelTarget) *models.PredictionJob {
+	config := *predictionJob.Config
+	config.MainAppPath = mainAppPathInput
+	config.Mode = models.PredictionJobModeStandardTransformer
+	config.StandardTransformer = &models.StandardTransformerJobConfig{
+		ModelTarget:          modelTarget,
+		Concurrency:          2,
+		InferenceServiceName: inferenceServiceName,
+		Protocol:             protocol.HttpJson,
+	}
+	config.JobConfig = &jobspec.PredictionJob{
+		Version: "v1",
+		Kind:    "PredictionJob",
+		Name:    jobName,
+		Source: &jobspec.PredictionJob_GcsSource{
+			GcsSource: &jobspec.GcsSource{Format: jobspec.FileFormat_JSON, Uri: "gs://bucket-name/requests.jsonl"},
+		},
+		Model: predictionJob.Config.JobConfig.Model,
+		Sink: &jobspec.PredictionJob_GcsSink{
+			GcsSink: &jobspec.GcsSink{Format: jobspec.FileFormat_JSON, Uri: "gs://bucket-name/responses.jsonl"},
+		},
+	}
+
+	job := *predictionJob
+	job.Status = models.JobPending
+	job.Config = &config
+	return &job
+}
+
+func envVarValue(envVars []corev1.EnvVar, name string) string {
+	for _, ev := range envVars {
+		if ev.Name == name {
+			return ev.Value
+		}
+	}
+	return ""
+}
+
+func TestCreateStandardTransformerJobResource(t *testing.T) {
+	t.Run("endpoint model target", func(t *testing.T) {
+		job := newStandardTransformerPredictionJob(models.TransformerModelTargetEndpoint)
+
+		kubeJob, err := CreateStandardTransformerJobResource(job, onlineTransformer)
+		assert.NoError(t, err)
+		assert.Equal(t, jobName, kubeJob.Name)
+
+		containers := kubeJob.Spec.Template.Spec.Containers
+		assert.Len(t, containers, 1)
+		transformer := containers[0]
+		assert.Equal(t, standardTransformerContainer, transformer.Name)
+		assert.Equal(t, onlineTransformer.Image, transformer.Image)
+		assert.Equal(t, onlineTransformer.Resources, transformer.Resources)
+		assert.Nil(t, transformer.ReadinessProbe)
+		assert.Equal(t, `{"transformerConfig":{}}`, envVarValue(transformer.Env, "STANDARD_TRANSFORMER_CONFIG"))
+		assert.Equal(t, "my-model-1-predictor.my-project", envVarValue(transformer.Env, envPredictorHost))
+		assert.Equal(t, envServiceAccountPath, envVarValue(transformer.Env, envServiceAccountPathKey))
+		assert.Equal(t, "gs://bucket-name/requests.jsonl", envVarValue(transformer.Env, envBatchInputPath))
+		assert.Equal(t, "gs://bucket-name/responses.jsonl", envVarValue(transformer.Env, envBatchOutputPath))
+		assert.Equal(t, "2", envVarValue(transformer.Env, envBatchConcurrency))
+		assert.Equal(t, "0", envVarValue(transformer.Env, envBatchMaxFailedRatio))
+		assert.Equal(t, "", envVarValue(transformer.Env, envBatchModelShutdownURL))
+
+		job.Config.StandardTransformer.MaxFailedRatio = 0.05
+		kubeJob, err = CreateStandardTransformerJobResource(job, onlineTransformer)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.05", envVarValue(kubeJob.Spec.Template.Spec.Containers[0].Env, envBatchMaxFailedRatio))
+	})
+
+	t.Run("artifact model target", func(t *testing.T) {
+		job := newStandardTransformerPredictionJob(models.TransformerModelTargetArtifact)
+
+		kubeJob, err := CreateStandardTransformerJobResource(job, onlineTransformer)
+		assert.NoError(t, err)
+
+		containers := kubeJob.Spec.Template.Spec.Containers
+		assert.Len(t, containers, 2)
+		transformer, model := containers[0], containers[1]
+		assert.Equal(t, "localhost:8080", envVarValue(transformer.Env, envPredictorHost))
+		assert.Equal(t, "http://localhost:8080/", envVarValue(transformer.Env, envBatchModelReadyURL))
+		assert.Equal(t, "http://localhost:8080/shutdown", envVarValue(transformer.Env, envBatchModelShutdownURL))
+
+		assert.Equal(t, modelContainer, model.Name)
+		assert.Equal(t, imageRef, model.Image)
+		assert.Equal(t, []string{
+			"python", "/merlin-spark-app/model_server.py",
+			"--model-uri", "gs://bucket-name/e2e/artifacts/model",
+			"--port", "8080",
+		}, model.Args)
+		assert.Equal(t, driverMemory, model.Resources.Limits.Memory().String())
+	})
+}
+
+func TestSubmitStandardTransformerJob(t *testing.T) {
+	tests := []struct {
+		name         string
+		pods         []corev1.Pod
+		wantErrorMsg string
+	}{
+		{
+			name: "transformer is running",
+			pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-model-1-transformer-default-00001-deployment-abc",
+						Labels: map[string]string{
+							"serving.kserve.io/inferenceservice": inferenceServiceName,
+							"component":                          "transformer",
+						},
+					},
+					Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "queue-proxy"}, onlineTransformer}},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+			},
+		},
+		{
+			name: "transformer is not running",
+			pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-model-1-transformer-default-00001-deployment-abc",
+						Labels: map[string]string{
+							"serving.kserve.io/inferenceservice": inferenceServiceName,
+							"component":                          "transformer",
+						},
+					},
+					Spec:   corev1.PodSpec{Containers: []corev1.Container{onlineTransformer}},
+					Status: corev1.PodStatus{Phase: corev1.PodPending},
+				},
+			},
+			wantErrorMsg: "running standard transformer of my-model-1 is not found in namespace spark-jobs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newStandardTransformerPredictionJob(models.TransformerModelTargetEndpoint)
+
+			mockStorage := &mocks.PredictionJobStorage{}
+			mockStorage.On("Save", job).Return(nil)
+
+			mockMlpAPIClient := &mlpMock.APIClient{}
+			mockMlpAPIClient.On("GetPlainSecretByNameAndProjectID", context.Background(), secret.Name, int32(1)).Return(secret, nil)
+
+			mockKubeClient := &fake2.Clientset{}
+			mockKubeClient.PrependReactor("get", "namespaces", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace},
+					Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+				}, nil
+			})
+			var labelSelector string
+			mockKubeClient.PrependReactor("list", "pods", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				labelSelector = action.(ktesting.ListAction).GetListRestrictions().Labels.String()
+				return true, &corev1.PodList{Items: tt.pods}, nil
+			})
+			var submitted *batchv1.Job
+			mockKubeClient.PrependReactor("create", "jobs", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				submitted = action.(ktesting.CreateAction).GetObject().(*batchv1.Job)
+				return true, submitted, nil
+			})
+
+			mockManifestManager := &batchMock.ManifestManager{}
+			mockManifestManager.On("CreateSecret", context.Background(), jobName, defaultNamespace, secret.Data).Return(jobName, nil)
+			mockManifestManager.On("CreateJobSpec", context.Background(), jobName, defaultNamespace, job.Config.JobConfig).Return(jobName, nil)
+			mockManifestManager.On("DeleteSecret", context.Background(), jobName, defaultNamespace).Return(nil)
+			mockManifestManager.On("DeleteJobSpec", context.Background(), jobName, defaultNamespace).Return(nil)
+
+			clusterMetadata := cluster.Metadata{GcpProject: "my-gcp", ClusterName: "my-cluster"}
+			ctl := NewController(mockStorage, mockMlpAPIClient, &sparkOpFake.Clientset{}, mockKubeClient, mockManifestManager, "env1", clusterMetadata, nil)
+
+			err := ctl.Submit(context.Background(), job, defaultNamespace)
+			assert.Equal(t, "component=transformer,serving.kserve.io/inferenceservice=my-model-1", labelSelector)
+			if tt.wantErrorMsg != "" {
+				assert.EqualError(t, err, tt.wantErrorMsg)
+				assert.Nil(t, submitted)
+				mockManifestManager.AssertCalled(t, "DeleteSecret", context.Background(), jobName, defaultNamespace)
+				return
+			}
+
+			assert.NoError(t, err)
+			expectedJob, _ := CreateStandardTransformerJobResource(job, onlineTransformer)
+			assert.Equal(t, expectedJob, submitted)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
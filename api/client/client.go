@@ -51,6 +51,10 @@ type APIClient struct {
 
 	EnvironmentApi *EnvironmentApiService
 
+	InferenceGraphApi *InferenceGraphApiService
+
+	WebhookApi *WebhookApiService
+
 	LogApi *LogApiService
 
 	ModelEndpointsApi *ModelEndpointsApiService
@@ -59,12 +63,16 @@ type APIClient struct {
 
 	PredictionJobsApi *PredictionJobsApiService
 
+	PredictionJobSchedulesApi *PredictionJobSchedulesApiService
+
 	ProjectApi *ProjectApiService
 
 	SecretApi *SecretApiService
 
 	StandardTransformerApi *StandardTransformerApiService
 
+	TeamApi *TeamApiService
+
 	VersionApi *VersionApiService
 }
 
@@ -87,13 +95,17 @@ func NewAPIClient(cfg *Configuration) *APIClient {
 	c.AlertApi = (*AlertApiService)(&c.common)
 	c.EndpointApi = (*EndpointApiService)(&c.common)
 	c.EnvironmentApi = (*EnvironmentApiService)(&c.common)
+	c.InferenceGraphApi = (*InferenceGraphApiService)(&c.common)
+	c.WebhookApi = (*WebhookApiService)(&c.common)
 	c.LogApi = (*LogApiService)(&c.common)
 	c.ModelEndpointsApi = (*ModelEndpointsApiService)(&c.common)
 	c.ModelsApi = (*ModelsApiService)(&c.common)
 	c.PredictionJobsApi = (*PredictionJobsApiService)(&c.common)
+	c.PredictionJobSchedulesApi = (*PredictionJobSchedulesApiService)(&c.common)
 	c.ProjectApi = (*ProjectApiService)(&c.common)
 	c.SecretApi = (*SecretApiService)(&c.common)
 	c.StandardTransformerApi = (*StandardTransformerApiService)(&c.common)
+	c.TeamApi = (*TeamApiService)(&c.common)
 	c.VersionApi = (*VersionApiService)(&c.common)
 
 	return c
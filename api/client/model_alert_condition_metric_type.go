@@ -18,4 +18,6 @@ const (
 	ERROR_RATE AlertConditionMetricType = "error_rate"
 	CPU        AlertConditionMetricType = "cpu"
 	MEMORY     AlertConditionMetricType = "memory"
+	DRIFT      AlertConditionMetricType = "drift"
+	CUSTOM     AlertConditionMetricType = "custom"
 )
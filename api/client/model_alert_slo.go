@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type AlertSLO struct {
+	Enabled          bool    `json:"enabled,omitempty"`
+	Type_            string  `json:"type,omitempty"`
+	Objective        float64 `json:"objective,omitempty"`
+	Period           string  `json:"period,omitempty"`
+	LatencyThreshold float64 `json:"latency_threshold,omitempty"`
+}
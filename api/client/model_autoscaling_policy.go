@@ -10,6 +10,7 @@
 package client
 
 type AutoscalingPolicy struct {
-	MetricsType *MetricsType `json:"metrics_type,omitempty"`
-	TargetValue float32      `json:"target_value,omitempty"`
+	MetricsType     *MetricsType `json:"metrics_type,omitempty"`
+	TargetValue     float32      `json:"target_value,omitempty"`
+	PrometheusQuery string       `json:"prometheus_query,omitempty"`
 }
@@ -10,9 +10,14 @@
 package client
 
 type Config struct {
-	JobConfig          *PredictionJobConfig          `json:"job_config,omitempty"`
-	ImageRef           string                        `json:"image_ref,omitempty"`
-	ServiceAccountName string                        `json:"service_account_name,omitempty"`
-	ResourceRequest    *PredictionJobResourceRequest `json:"resource_request,omitempty"`
-	EnvVars            []EnvVar                      `json:"env_vars,omitempty"`
+	JobConfig           *PredictionJobConfig          `json:"job_config,omitempty"`
+	ImageRef            string                        `json:"image_ref,omitempty"`
+	ServiceAccountName  string                        `json:"service_account_name,omitempty"`
+	ResourceRequest     *PredictionJobResourceRequest `json:"resource_request,omitempty"`
+	EnvVars             []EnvVar                      `json:"env_vars,omitempty"`
+	RetryPolicy         *PredictionJobRetryPolicy     `json:"retry_policy,omitempty"`
+	Mode                *PredictionJobMode            `json:"mode,omitempty"`
+	Lightweight         *LightweightJobConfig         `json:"lightweight,omitempty"`
+	StandardTransformer *StandardTransformerJobConfig `json:"standard_transformer,omitempty"`
+	QualityChecks       *PredictionJobQualityChecks   `json:"quality_checks,omitempty"`
 }
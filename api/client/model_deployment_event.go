@@ -0,0 +1,31 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type DeploymentEvent struct {
+	Id                int32  `json:"id,omitempty"`
+	VersionEndpointId string `json:"version_endpoint_id,omitempty"`
+	Source            string `json:"source,omitempty"`
+	Type_             string `json:"type,omitempty"`
+	// Short CamelCase identifier of the event, e.g. ImageBuildStarted or BackOff
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Kubernetes object the event is about, e.g. pod/my-model-1-predictor-abc
+	Object string `json:"object,omitempty"`
+	// Category of the failure, set on warning events
+	FailureClass string    `json:"failure_class,omitempty"`
+	Timestamp    time.Time `json:"timestamp,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type DriftFeature struct {
+	Name       string              `json:"name"`
+	Source     *DriftFeatureSource `json:"source"`
+	Kind       *DriftFeatureKind   `json:"kind"`
+	Quantiles  []float64           `json:"quantiles,omitempty"`
+	Categories map[string]float64  `json:"categories,omitempty"`
+}
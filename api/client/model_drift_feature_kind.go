@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type DriftFeatureKind string
+
+// List of DriftFeatureKind
+const (
+	NUMERIC     DriftFeatureKind = "numeric"
+	CATEGORICAL DriftFeatureKind = "categorical"
+)
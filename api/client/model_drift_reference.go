@@ -0,0 +1,24 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type DriftReference struct {
+	Id        int32          `json:"id,omitempty"`
+	ModelId   int32          `json:"model_id,omitempty"`
+	VersionId int32          `json:"version_id,omitempty"`
+	Dataset   string         `json:"dataset,omitempty"`
+	Features  []DriftFeature `json:"features"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+}
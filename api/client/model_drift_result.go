@@ -0,0 +1,26 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type DriftResult struct {
+	Feature       string              `json:"feature,omitempty"`
+	Source        *DriftFeatureSource `json:"source,omitempty"`
+	Observations  int32               `json:"observations,omitempty"`
+	Psi           float64             `json:"psi,omitempty"`
+	Ks            float64             `json:"ks,omitempty"`
+	JensenShannon float64             `json:"jensen_shannon,omitempty"`
+	WindowStart   time.Time           `json:"window_start,omitempty"`
+	WindowEnd     time.Time           `json:"window_end,omitempty"`
+	CreatedAt     time.Time           `json:"created_at,omitempty"`
+}
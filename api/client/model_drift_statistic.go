@@ -0,0 +1,19 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type DriftStatistic string
+
+// List of DriftStatistic
+const (
+	PSI            DriftStatistic = "psi"
+	KS             DriftStatistic = "ks"
+	JENSEN_SHANNON DriftStatistic = "jensen_shannon"
+)
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type EndpointResourceRecommendation struct {
+	LookbackWindow string                  `json:"lookback_window,omitempty"`
+	Model          *ResourceRecommendation `json:"model,omitempty"`
+	Transformer    *ResourceRecommendation `json:"transformer,omitempty"`
+}
@@ -14,15 +14,17 @@ import (
 )
 
 type Environment struct {
-	Id                                  int32                         `json:"id,omitempty"`
-	Name                                string                        `json:"name"`
-	Cluster                             string                        `json:"cluster,omitempty"`
-	IsDefault                           bool                          `json:"is_default,omitempty"`
-	Region                              string                        `json:"region,omitempty"`
-	GcpProject                          string                        `json:"gcp_project,omitempty"`
-	DefaultResourceRequest              *ResourceRequest              `json:"default_resource_request,omitempty"`
-	DefaultTransformerResourceRequest   *ResourceRequest              `json:"default_transformer_resource_request,omitempty"`
-	DefaultPredictionJobResourceRequest *PredictionJobResourceRequest `json:"default_prediction_job_resource_request,omitempty"`
-	CreatedAt                           time.Time                     `json:"created_at,omitempty"`
-	UpdatedAt                           time.Time                     `json:"updated_at,omitempty"`
+	Id                                  int32                           `json:"id,omitempty"`
+	Name                                string                          `json:"name"`
+	Cluster                             string                          `json:"cluster,omitempty"`
+	IsDefault                           bool                            `json:"is_default,omitempty"`
+	Region                              string                          `json:"region,omitempty"`
+	GcpProject                          string                          `json:"gcp_project,omitempty"`
+	DefaultResourceRequest              *ResourceRequest                `json:"default_resource_request,omitempty"`
+	DefaultTransformerResourceRequest   *ResourceRequest                `json:"default_transformer_resource_request,omitempty"`
+	DefaultPredictionJobResourceRequest *PredictionJobResourceRequest   `json:"default_prediction_job_resource_request,omitempty"`
+	LightweightPredictionJobConfig      *LightweightPredictionJobConfig `json:"lightweight_prediction_job_config,omitempty"`
+	UnitCost                            *UnitCost                       `json:"unit_cost,omitempty"`
+	CreatedAt                           time.Time                       `json:"created_at,omitempty"`
+	UpdatedAt                           time.Time                       `json:"updated_at,omitempty"`
 }
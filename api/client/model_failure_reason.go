@@ -0,0 +1,21 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type FailureReason string
+
+// List of FailureReason
+const (
+	PREEMPTION         FailureReason = "preemption"
+	OUT_OF_MEMORY      FailureReason = "out_of_memory"
+	SUBMISSION_FAILURE FailureReason = "submission_failure"
+	APPLICATION_ERROR  FailureReason = "application_error"
+	DATA_QUALITY       FailureReason = "data_quality"
+)
@@ -0,0 +1,28 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type ImageBuild struct {
+	VersionId    int32     `json:"version_id,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	ImageRef     string    `json:"image_ref,omitempty"`
+	ArtifactHash string    `json:"artifact_hash,omitempty"`
+	Reused       bool      `json:"reused,omitempty"`
+	JobName      string    `json:"job_name,omitempty"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Cluster      string    `json:"cluster,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	StartedAt    time.Time `json:"started_at,omitempty"`
+	CompletedAt  time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type IncrementalSource struct {
+	WatermarkColumn string `json:"watermark_column,omitempty"`
+	StartWatermark  string `json:"start_watermark,omitempty"`
+	EndWatermark    string `json:"end_watermark,omitempty"`
+}
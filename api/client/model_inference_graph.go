@@ -0,0 +1,30 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type InferenceGraph struct {
+	Id              int32                         `json:"id,omitempty"`
+	ProjectId       int32                         `json:"project_id,omitempty"`
+	Name            string                        `json:"name,omitempty"`
+	Nodes           map[string]InferenceGraphNode `json:"nodes,omitempty"`
+	Status          *EndpointStatus               `json:"status,omitempty"`
+	Url             string                        `json:"url,omitempty"`
+	Message         string                        `json:"message,omitempty"`
+	EnvironmentName string                        `json:"environment_name,omitempty"`
+	Environment     *Environment                  `json:"environment,omitempty"`
+	Logger          *LoggerConfig                 `json:"logger,omitempty"`
+	Alert           *InferenceGraphAlert          `json:"alert,omitempty"`
+	CreatedAt       time.Time                     `json:"created_at,omitempty"`
+	UpdatedAt       time.Time                     `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+// Alert of the inference graph, drift conditions are not supported
+type InferenceGraphAlert struct {
+	TeamName        string                        `json:"team_name,omitempty"`
+	AlertConditions []ModelEndpointAlertCondition `json:"alert_conditions,omitempty"`
+	Slos            []AlertSLO                    `json:"slos,omitempty"`
+	// Teams notified of the alerts of a severity, instead of `team_name`
+	Routes map[string]string `json:"routes,omitempty"`
+}
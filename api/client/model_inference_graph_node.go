@@ -0,0 +1,15 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type InferenceGraphNode struct {
+	RouterType string               `json:"router_type,omitempty"`
+	Steps      []InferenceGraphStep `json:"steps,omitempty"`
+}
@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type InferenceGraphStep struct {
+	Name              string `json:"name,omitempty"`
+	VersionEndpointId string `json:"version_endpoint_id,omitempty"`
+	NodeName          string `json:"node_name,omitempty"`
+	Data              string `json:"data,omitempty"`
+	Condition         string `json:"condition,omitempty"`
+}
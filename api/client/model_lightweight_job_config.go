@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type LightweightJobConfig struct {
+	EndpointId  string    `json:"endpoint_id,omitempty"`
+	EndpointUrl string    `json:"endpoint_url,omitempty"`
+	Protocol    *Protocol `json:"protocol,omitempty"`
+	Concurrency int32     `json:"concurrency,omitempty"`
+	BatchSize   int32     `json:"batch_size,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type LightweightPredictionJobConfig struct {
+	Enabled        bool  `json:"enabled,omitempty"`
+	MaxConcurrency int32 `json:"max_concurrency,omitempty"`
+	MaxBatchSize   int32 `json:"max_batch_size,omitempty"`
+}
@@ -10,6 +10,10 @@
 package client
 
 type LoggerConfig struct {
-	Enabled bool        `json:"enabled,omitempty"`
-	Mode    *LoggerMode `json:"mode,omitempty"`
+	Enabled    bool                  `json:"enabled,omitempty"`
+	Mode       *LoggerMode           `json:"mode,omitempty"`
+	Sampling   *LoggerSamplingConfig `json:"sampling,omitempty"`
+	Redactions []LoggerRedaction     `json:"redactions,omitempty"`
+	// Size in bytes above which the logged payloads are truncated
+	MaxPayloadSize int32 `json:"max_payload_size,omitempty"`
 }
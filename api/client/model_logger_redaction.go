@@ -0,0 +1,17 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type LoggerRedaction struct {
+	// JSONPath of the redacted field, e.g. $.customer.phone
+	Path    string `json:"path,omitempty"`
+	Action  string `json:"action,omitempty"`
+	Payload string `json:"payload,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type LoggerSamplingConfig struct {
+	// Fraction of the requests logged, between 0 and 1
+	Rate            float64                `json:"rate,omitempty"`
+	StatusCodeRules []LoggerStatusCodeRule `json:"status_code_rules,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type LoggerStatusCodeRule struct {
+	// Status code, e.g. 429, or class of status codes, e.g. 5xx
+	StatusCode string  `json:"status_code,omitempty"`
+	Rate       float64 `json:"rate,omitempty"`
+}
@@ -17,4 +17,5 @@ const (
 	CPU_UTILIZATION    MetricsType = "cpu_utilization"
 	MEMORY_UTILIZATION MetricsType = "memory_utilization"
 	RPS                MetricsType = "rps"
+	PROMETHEUS_QUERY   MetricsType = "prometheus_query"
 )
@@ -15,4 +15,6 @@ type ModelEndpointAlert struct {
 	EnvironmentName string                        `json:"environment_name,omitempty"`
 	TeamName        string                        `json:"team_name,omitempty"`
 	AlertConditions []ModelEndpointAlertCondition `json:"alert_conditions,omitempty"`
+	Slos            []AlertSLO                    `json:"slos,omitempty"`
+	Routes          map[string]string             `json:"routes,omitempty"`
 }
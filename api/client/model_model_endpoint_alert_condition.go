@@ -10,10 +10,13 @@
 package client
 
 type ModelEndpointAlertCondition struct {
-	Enabled    bool                      `json:"enabled,omitempty"`
-	MetricType *AlertConditionMetricType `json:"metric_type,omitempty"`
-	Severity   *AlertConditionSeverity   `json:"severity,omitempty"`
-	Target     float32                   `json:"target,omitempty"`
-	Percentile float32                   `json:"percentile,omitempty"`
-	Unit       string                    `json:"unit,omitempty"`
+	Enabled        bool                      `json:"enabled,omitempty"`
+	MetricType     *AlertConditionMetricType `json:"metric_type,omitempty"`
+	Severity       *AlertConditionSeverity   `json:"severity,omitempty"`
+	Target         float32                   `json:"target,omitempty"`
+	Percentile     float32                   `json:"percentile,omitempty"`
+	Unit           string                    `json:"unit,omitempty"`
+	DriftStatistic *DriftStatistic           `json:"drift_statistic,omitempty"`
+	Name           string                    `json:"name,omitempty"`
+	Expr           string                    `json:"expr,omitempty"`
 }
@@ -0,0 +1,17 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type NotificationChannel struct {
+	Type_ string `json:"type,omitempty"`
+	// Slack channel, email address, PagerDuty service key or webhook URL
+	Target   string                  `json:"target,omitempty"`
+	Severity *AlertConditionSeverity `json:"severity,omitempty"`
+}
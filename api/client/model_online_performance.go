@@ -0,0 +1,20 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type OnlinePerformance struct {
+	VersionId          int32   `json:"version_id,omitempty"`
+	Window             string  `json:"window,omitempty"`
+	Predictions        int32   `json:"predictions,omitempty"`
+	LabeledPredictions int32   `json:"labeled_predictions,omitempty"`
+	Accuracy           float64 `json:"accuracy,omitempty"`
+	Auc                float64 `json:"auc,omitempty"`
+	Rmse               float64 `json:"rmse,omitempty"`
+}
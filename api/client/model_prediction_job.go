@@ -14,16 +14,22 @@ import (
 )
 
 type PredictionJob struct {
-	Id              int32        `json:"id,omitempty"`
-	Name            string       `json:"name,omitempty"`
-	VersionId       int32        `json:"version_id,omitempty"`
-	ModelId         int32        `json:"model_id,omitempty"`
-	ProjectId       int32        `json:"project_id,omitempty"`
-	EnvironmentName string       `json:"environment_name,omitempty"`
-	Environment     *Environment `json:"environment,omitempty"`
-	Config          *Config      `json:"config,omitempty"`
-	Status          string       `json:"status,omitempty"`
-	Error_          string       `json:"error,omitempty"`
-	CreatedAt       time.Time    `json:"created_at,omitempty"`
-	UpdatedAt       time.Time    `json:"updated_at,omitempty"`
+	Id              int32                  `json:"id,omitempty"`
+	Name            string                 `json:"name,omitempty"`
+	VersionId       int32                  `json:"version_id,omitempty"`
+	ModelId         int32                  `json:"model_id,omitempty"`
+	ProjectId       int32                  `json:"project_id,omitempty"`
+	EnvironmentName string                 `json:"environment_name,omitempty"`
+	Environment     *Environment           `json:"environment,omitempty"`
+	Config          *Config                `json:"config,omitempty"`
+	Status          string                 `json:"status,omitempty"`
+	Error_          string                 `json:"error,omitempty"`
+	Attempt         int32                  `json:"attempt,omitempty"`
+	NextRetryAt     time.Time              `json:"next_retry_at,omitempty"`
+	Progress        *PredictionJobProgress `json:"progress,omitempty"`
+	QualityStatus   string                 `json:"quality_status,omitempty"`
+	Quality         *PredictionJobQuality  `json:"quality,omitempty"`
+	CostEstimation  *CostEstimation        `json:"cost_estimation,omitempty"`
+	CreatedAt       time.Time              `json:"created_at,omitempty"`
+	UpdatedAt       time.Time              `json:"updated_at,omitempty"`
 }
@@ -0,0 +1,30 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type PredictionJobAttempt struct {
+	Id                   int32          `json:"id,omitempty"`
+	JobId                int32          `json:"job_id,omitempty"`
+	Attempt              int32          `json:"attempt,omitempty"`
+	Status               string         `json:"status,omitempty"`
+	Error_               string         `json:"error,omitempty"`
+	FailureReason        *FailureReason `json:"failure_reason,omitempty"`
+	SparkApplicationName string         `json:"spark_application_name,omitempty"`
+	Namespace            string         `json:"namespace,omitempty"`
+	DriverPodName        string         `json:"driver_pod_name,omitempty"`
+	StartedAt            time.Time      `json:"started_at,omitempty"`
+	FinishedAt           time.Time      `json:"finished_at,omitempty"`
+	CreatedAt            time.Time      `json:"created_at,omitempty"`
+	UpdatedAt            time.Time      `json:"updated_at,omitempty"`
+}
@@ -10,7 +10,8 @@
 package client
 
 type PredictionJobConfigBigquerySource struct {
-	Table    string            `json:"table,omitempty"`
-	Features []string          `json:"features,omitempty"`
-	Options  map[string]string `json:"options,omitempty"`
+	Table       string             `json:"table,omitempty"`
+	Features    []string           `json:"features,omitempty"`
+	Options     map[string]string  `json:"options,omitempty"`
+	Incremental *IncrementalSource `json:"incremental,omitempty"`
 }
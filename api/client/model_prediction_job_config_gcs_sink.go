@@ -15,4 +15,5 @@ type PredictionJobConfigGcsSink struct {
 	ResultColumn string            `json:"result_column,omitempty"`
 	SaveMode     *SaveMode         `json:"save_mode,omitempty"`
 	Options      map[string]string `json:"options,omitempty"`
+	Partition    *SinkPartition    `json:"partition,omitempty"`
 }
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type PredictionJobDriftCheck struct {
+	ReferenceJobId     int32     `json:"reference_job_id,omitempty"`
+	MaxPsi             float64   `json:"max_psi,omitempty"`
+	ReferenceQuantiles []float64 `json:"reference_quantiles,omitempty"`
+}
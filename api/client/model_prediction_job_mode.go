@@ -0,0 +1,19 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type PredictionJobMode string
+
+// List of PredictionJobMode
+const (
+	SPARK                PredictionJobMode = "spark"
+	LIGHTWEIGHT          PredictionJobMode = "lightweight"
+	STANDARD_TRANSFORMER PredictionJobMode = "standard_transformer"
+)
@@ -0,0 +1,27 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type PredictionJobProgress struct {
+	StagesTotal     int32     `json:"stages_total,omitempty"`
+	StagesCompleted int32     `json:"stages_completed,omitempty"`
+	TasksTotal      int32     `json:"tasks_total,omitempty"`
+	TasksCompleted  int32     `json:"tasks_completed,omitempty"`
+	TasksFailed     int32     `json:"tasks_failed,omitempty"`
+	Percentage      float64   `json:"percentage,omitempty"`
+	RowsRead        int64     `json:"rows_read,omitempty"`
+	RowsWritten     int64     `json:"rows_written,omitempty"`
+	ActiveExecutors int32     `json:"active_executors,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at,omitempty"`
+}
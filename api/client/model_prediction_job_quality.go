@@ -0,0 +1,20 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type PredictionJobQuality struct {
+	Metrics     *PredictionJobQualityMetrics      `json:"metrics,omitempty"`
+	Checks      []PredictionJobQualityCheckResult `json:"checks,omitempty"`
+	EvaluatedAt time.Time                         `json:"evaluated_at,omitempty"`
+}
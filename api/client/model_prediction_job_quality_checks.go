@@ -0,0 +1,19 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type PredictionJobQualityChecks struct {
+	Action          string                   `json:"action,omitempty"`
+	MinRowRatio     float64                  `json:"min_row_ratio,omitempty"`
+	MaxNullFraction float64                  `json:"max_null_fraction,omitempty"`
+	MinValue        float64                  `json:"min_value,omitempty"`
+	MaxValue        float64                  `json:"max_value,omitempty"`
+	Drift           *PredictionJobDriftCheck `json:"drift,omitempty"`
+}
@@ -0,0 +1,19 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type PredictionJobQualityMetrics struct {
+	SourceRows int64     `json:"source_rows,omitempty"`
+	OutputRows int64     `json:"output_rows,omitempty"`
+	NullCount  int64     `json:"null_count,omitempty"`
+	Min        float64   `json:"min,omitempty"`
+	Max        float64   `json:"max,omitempty"`
+	Quantiles  []float64 `json:"quantiles,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type PredictionJobRetryPolicy struct {
+	MaxAttempts      int32           `json:"max_attempts,omitempty"`
+	Backoff          string          `json:"backoff,omitempty"`
+	RetryableReasons []FailureReason `json:"retryable_reasons,omitempty"`
+}
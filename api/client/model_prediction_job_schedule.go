@@ -0,0 +1,36 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type PredictionJobSchedule struct {
+	Id              int32  `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	ProjectId       int32  `json:"project_id,omitempty"`
+	ModelId         int32  `json:"model_id,omitempty"`
+	VersionId       int32  `json:"version_id,omitempty"`
+	EnvironmentName string `json:"environment_name,omitempty"`
+	// Standard 5-field cron expression
+	Schedule string `json:"schedule,omitempty"`
+	// IANA timezone the schedule is evaluated in, UTC if empty
+	Timezone          string    `json:"timezone,omitempty"`
+	ConcurrencyPolicy string    `json:"concurrency_policy,omitempty"`
+	MaxHistory        int32     `json:"max_history,omitempty"`
+	Paused            bool      `json:"paused,omitempty"`
+	Config            *Config   `json:"config,omitempty"`
+	LastRunAt         time.Time `json:"last_run_at,omitempty"`
+	NextRunAt         time.Time `json:"next_run_at,omitempty"`
+	LastWatermark     time.Time `json:"last_watermark,omitempty"`
+	CreatedAt         time.Time `json:"created_at,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,26 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type PredictionJobScheduleRun struct {
+	Id            int32          `json:"id,omitempty"`
+	ScheduleId    int32          `json:"schedule_id,omitempty"`
+	JobId         int32          `json:"job_id,omitempty"`
+	ScheduledTime time.Time      `json:"scheduled_time,omitempty"`
+	Manual        bool           `json:"manual,omitempty"`
+	Message       string         `json:"message,omitempty"`
+	Job           *PredictionJob `json:"job,omitempty"`
+	CreatedAt     time.Time      `json:"created_at,omitempty"`
+	UpdatedAt     time.Time      `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,21 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type PredictionLabel struct {
+	PredictionId string    `json:"prediction_id"`
+	RowId        string    `json:"row_id,omitempty"`
+	Value        float64   `json:"value"`
+	ObservedAt   time.Time `json:"observed_at,omitempty"`
+}
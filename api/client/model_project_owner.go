@@ -0,0 +1,23 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type ProjectOwner struct {
+	Id        int32     `json:"id,omitempty"`
+	ProjectId int32     `json:"project_id,omitempty"`
+	TeamId    int32     `json:"team_id,omitempty"`
+	Team      *Team     `json:"team,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
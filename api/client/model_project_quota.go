@@ -0,0 +1,26 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type ProjectQuota struct {
+	Id                          int32     `json:"id,omitempty"`
+	ProjectId                   int32     `json:"project_id,omitempty"`
+	MaxCpu                      string    `json:"max_cpu,omitempty"`
+	MaxMemory                   string    `json:"max_memory,omitempty"`
+	MaxReplicas                 int32     `json:"max_replicas,omitempty"`
+	MaxEndpoints                int32     `json:"max_endpoints,omitempty"`
+	MaxConcurrentPredictionJobs int32     `json:"max_concurrent_prediction_jobs,omitempty"`
+	CreatedAt                   time.Time `json:"created_at,omitempty"`
+	UpdatedAt                   time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type ProjectResourceUsage struct {
+	Cpu                      string `json:"cpu,omitempty"`
+	Memory                   string `json:"memory,omitempty"`
+	Replicas                 int32  `json:"replicas,omitempty"`
+	Endpoints                int32  `json:"endpoints,omitempty"`
+	ConcurrentPredictionJobs int32  `json:"concurrent_prediction_jobs,omitempty"`
+}
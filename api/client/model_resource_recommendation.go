@@ -0,0 +1,17 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type ResourceRecommendation struct {
+	CurrentResourceRequest     *ResourceRequest `json:"current_resource_request,omitempty"`
+	RecommendedResourceRequest *ResourceRequest `json:"recommended_resource_request,omitempty"`
+	CurrentCostEstimation      *CostEstimation  `json:"current_cost_estimation,omitempty"`
+	RecommendedCostEstimation  *CostEstimation  `json:"recommended_cost_estimation,omitempty"`
+}
@@ -14,4 +14,5 @@ type ResourceRequest struct {
 	MaxReplica    int32  `json:"max_replica,omitempty"`
 	CpuRequest    string `json:"cpu_request,omitempty"`
 	MemoryRequest string `json:"memory_request,omitempty"`
+	GpuRequest    string `json:"gpu_request,omitempty"`
 }
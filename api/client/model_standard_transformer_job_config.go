@@ -0,0 +1,19 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type StandardTransformerJobConfig struct {
+	EndpointId           string                  `json:"endpoint_id,omitempty"`
+	ModelTarget          *TransformerModelTarget `json:"model_target,omitempty"`
+	Concurrency          int32                   `json:"concurrency,omitempty"`
+	MaxFailedRatio       float64                 `json:"max_failed_ratio,omitempty"`
+	InferenceServiceName string                  `json:"inference_service_name,omitempty"`
+	Protocol             *Protocol               `json:"protocol,omitempty"`
+}
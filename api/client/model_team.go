@@ -0,0 +1,28 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type Team struct {
+	Id int32 `json:"id,omitempty"`
+	// Name of the team, used as the `owner` label of its alerts
+	Name                 string                `json:"name,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	Members              []string              `json:"members,omitempty"`
+	NotificationChannels []NotificationChannel `json:"notification_channels,omitempty"`
+	Escalation           *TeamEscalation       `json:"escalation,omitempty"`
+	// Where the team is managed. Read-only.
+	Source    string    `json:"source,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,17 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type TeamEscalation struct {
+	OnCall string `json:"on_call,omitempty"`
+	Policy string `json:"policy,omitempty"`
+	// Duration after which unacknowledged alerts are escalated, e.g. 15m
+	AckTimeout string `json:"ack_timeout,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type UnitCost struct {
+	CpuMonthlyCost    float64 `json:"cpu_monthly_cost,omitempty"`
+	MemoryMonthlyCost float64 `json:"memory_monthly_cost,omitempty"`
+	GpuMonthlyCost    float64 `json:"gpu_monthly_cost,omitempty"`
+}
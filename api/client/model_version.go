@@ -14,16 +14,18 @@ import (
 )
 
 type Version struct {
-	Id              int32             `json:"id,omitempty"`
-	ModelId         int32             `json:"model_id,omitempty"`
-	MlflowRunId     string            `json:"mlflow_run_id,omitempty"`
-	MlflowUrl       string            `json:"mlflow_url,omitempty"`
-	ArtifactUri     string            `json:"artifact_uri,omitempty"`
-	Endpoints       []VersionEndpoint `json:"endpoints,omitempty"`
-	Properties      *interface{}      `json:"properties,omitempty"`
-	Labels          map[string]string `json:"labels,omitempty"`
-	CustomPredictor *CustomPredictor  `json:"custom_predictor,omitempty"`
-	CreatedAt       time.Time         `json:"created_at,omitempty"`
-	UpdatedAt       time.Time         `json:"updated_at,omitempty"`
-	PythonVersion   string            `json:"python_version,omitempty"`
+	Id              int32               `json:"id,omitempty"`
+	ModelId         int32               `json:"model_id,omitempty"`
+	MlflowRunId     string              `json:"mlflow_run_id,omitempty"`
+	MlflowUrl       string              `json:"mlflow_url,omitempty"`
+	ArtifactUri     string              `json:"artifact_uri,omitempty"`
+	Endpoints       []VersionEndpoint   `json:"endpoints,omitempty"`
+	Properties      *interface{}        `json:"properties,omitempty"`
+	Labels          map[string]string   `json:"labels,omitempty"`
+	CustomPredictor *CustomPredictor    `json:"custom_predictor,omitempty"`
+	CreatedAt       time.Time           `json:"created_at,omitempty"`
+	UpdatedAt       time.Time           `json:"updated_at,omitempty"`
+	PythonVersion   string              `json:"python_version,omitempty"`
+	BuildConfig     *VersionBuildConfig `json:"build_config,omitempty"`
+	BuildInputs     *VersionBuildInputs `json:"build_inputs,omitempty"`
 }
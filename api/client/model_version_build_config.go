@@ -0,0 +1,16 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type VersionBuildConfig struct {
+	BaseImage   string            `json:"base_image,omitempty"`
+	AptPackages []string          `json:"apt_packages,omitempty"`
+	BuildArgs   map[string]string `json:"build_args,omitempty"`
+}
@@ -0,0 +1,18 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+type VersionBuildInputs struct {
+	BaseImage       string            `json:"base_image,omitempty"`
+	DockerfilePath  string            `json:"dockerfile_path,omitempty"`
+	BuildContextUri string            `json:"build_context_uri,omitempty"`
+	AptPackages     []string          `json:"apt_packages,omitempty"`
+	BuildArgs       map[string]string `json:"build_args,omitempty"`
+}
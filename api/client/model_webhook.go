@@ -0,0 +1,25 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type Webhook struct {
+	Id         int32     `json:"id,omitempty"`
+	ProjectId  int32     `json:"project_id,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Url        string    `json:"url,omitempty"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,28 @@
+/*
+ * Merlin
+ *
+ * API Guide for accessing Merlin's model management, deployment, and serving functionalities
+ *
+ * API version: 0.14.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package client
+
+import (
+	"time"
+)
+
+type WebhookDelivery struct {
+	Id         int32     `json:"id,omitempty"`
+	WebhookId  int32     `json:"webhook_id,omitempty"`
+	EventId    string    `json:"event_id,omitempty"`
+	EventType  string    `json:"event_type,omitempty"`
+	Payload    string    `json:"payload,omitempty"`
+	Attempts   int32     `json:"attempts,omitempty"`
+	StatusCode int32     `json:"status_code,omitempty"`
+	Success    bool      `json:"success,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/caraml-dev/merlin/cluster/resource"
+	"github.com/caraml-dev/merlin/log"
+)
+
+// ApplyPrometheusRule creates or updates Prometheus Operator's PrometheusRule containing the given Prometheus rules file.
+func (k *controller) ApplyPrometheusRule(ctx context.Context, namespace, name string, labels map[string]string, rules string) error {
+	if k.dynamicClient == nil {
+		return ErrUnableToApplyPrometheusRule
+	}
+
+	spec, err := resource.CreatePrometheusRuleSpec(name, namespace, labels, rules)
+	if err != nil {
+		log.Errorf("unable to create prometheus rule spec %s %v", name, err)
+		return ErrUnableToApplyPrometheusRule
+	}
+
+	prometheusRules := k.dynamicClient.Resource(resource.PrometheusRuleGVR).Namespace(namespace)
+	existing, err := prometheusRules.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Errorf("unable to check prometheus rule %s %v", name, err)
+			return ErrUnableToApplyPrometheusRule
+		}
+
+		_, err = prometheusRules.Create(ctx, spec, metav1.CreateOptions{})
+	} else {
+		spec.SetResourceVersion(existing.GetResourceVersion())
+		_, err = prometheusRules.Update(ctx, spec, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		log.Errorf("unable to apply prometheus rule %s %v", name, err)
+		return ErrUnableToApplyPrometheusRule
+	}
+	return nil
+}
+
+// ApplyConfigMap creates or updates a ConfigMap, replacing all of its data.
+func (k *controller) ApplyConfigMap(ctx context.Context, namespace, name string, labels map[string]string, data map[string]string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: data,
+	}
+
+	configMaps := k.clusterClient.ConfigMaps(namespace)
+	existing, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Errorf("unable to check config map %s %v", name, err)
+			return ErrUnableToApplyConfigMap
+		}
+
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	} else {
+		configMap.ResourceVersion = existing.ResourceVersion
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		log.Errorf("unable to apply config map %s %v", name, err)
+		return ErrUnableToApplyConfigMap
+	}
+	return nil
+}
+
+// DeletePrometheusRule deletes Prometheus Operator's PrometheusRule, a rule that doesn't exist is ignored.
+func (k *controller) DeletePrometheusRule(ctx context.Context, namespace, name string) error {
+	if k.dynamicClient == nil {
+		return ErrUnableToDeletePrometheusRule
+	}
+
+	err := k.dynamicClient.Resource(resource.PrometheusRuleGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Errorf("unable to delete prometheus rule %s %v", name, err)
+		return ErrUnableToDeletePrometheusRule
+	}
+	return nil
+}
+
+// DeleteConfigMap deletes a ConfigMap, a ConfigMap that doesn't exist is ignored.
+func (k *controller) DeleteConfigMap(ctx context.Context, namespace, name string) error {
+	err := k.clusterClient.ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Errorf("unable to delete config map %s %v", name, err)
+		return ErrUnableToDeleteConfigMap
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/caraml-dev/merlin/cluster/resource"
+	"github.com/caraml-dev/merlin/config"
+)
+
+const testAlertRules = `groups:
+- name: merlin_model-1_env-1
+  rules:
+  - alert: "[merlin] model-1: Throughput below 1 rps"
+    expr: |
+      sum(rate(revision_request_count[1m])) < 1
+    for: 5m
+    labels:
+      owner: team-1
+`
+
+func TestController_ApplyPrometheusRule(t *testing.T) {
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		resource.PrometheusRuleGVR: "PrometheusRuleList",
+	})
+	ctl, _ := newController(nil, fake.NewSimpleClientset().CoreV1(), nil, dynamicClient, config.DeploymentConfig{}, nil, nil)
+
+	labels := map[string]string{"prometheus": "k8s"}
+	err := ctl.ApplyPrometheusRule(context.Background(), "monitoring", "merlin-project-1-model-1-env-1", labels, testAlertRules)
+	assert.NoError(t, err)
+
+	// Applying again updates the existing rule
+	err = ctl.ApplyPrometheusRule(context.Background(), "monitoring", "merlin-project-1-model-1-env-1", labels, testAlertRules)
+	assert.NoError(t, err)
+
+	rule, err := dynamicClient.Resource(resource.PrometheusRuleGVR).Namespace("monitoring").
+		Get(context.Background(), "merlin-project-1-model-1-env-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, labels, rule.GetLabels())
+
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	assert.Len(t, groups, 1)
+	rules, _, _ := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "[merlin] model-1: Throughput below 1 rps", rules[0].(map[string]interface{})["alert"])
+
+	err = ctl.ApplyPrometheusRule(context.Background(), "monitoring", "invalid", labels, "groups: [")
+	assert.Equal(t, ErrUnableToApplyPrometheusRule, err)
+
+	err = ctl.DeletePrometheusRule(context.Background(), "monitoring", "merlin-project-1-model-1-env-1")
+	assert.NoError(t, err)
+	_, err = dynamicClient.Resource(resource.PrometheusRuleGVR).Namespace("monitoring").
+		Get(context.Background(), "merlin-project-1-model-1-env-1", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// Deleting a rule that doesn't exist succeeds
+	err = ctl.DeletePrometheusRule(context.Background(), "monitoring", "merlin-project-1-model-1-env-1")
+	assert.NoError(t, err)
+}
+
+func TestController_ApplyPrometheusRule_NoDynamicClient(t *testing.T) {
+	ctl, _ := newController(nil, fake.NewSimpleClientset().CoreV1(), nil, nil, config.DeploymentConfig{}, nil, nil)
+
+	err := ctl.ApplyPrometheusRule(context.Background(), "monitoring", "merlin-project-1-model-1-env-1", nil, testAlertRules)
+	assert.Equal(t, ErrUnableToApplyPrometheusRule, err)
+}
+
+func TestController_ApplyConfigMap(t *testing.T) {
+	v1Client := fake.NewSimpleClientset().CoreV1()
+	ctl, _ := newController(nil, v1Client, nil, nil, config.DeploymentConfig{}, nil, nil)
+
+	labels := map[string]string{"prometheus-rules": "true"}
+	err := ctl.ApplyConfigMap(context.Background(), "monitoring", "merlin-project-1-model-1-env-1", labels, map[string]string{"model-1_env-1.yaml": "groups: []"})
+	assert.NoError(t, err)
+
+	err = ctl.ApplyConfigMap(context.Background(), "monitoring", "merlin-project-1-model-1-env-1", labels, map[string]string{"model-1_env-1.yaml": testAlertRules})
+	assert.NoError(t, err)
+
+	configMap, err := v1Client.ConfigMaps("monitoring").Get(context.Background(), "merlin-project-1-model-1-env-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, labels, configMap.Labels)
+	assert.Equal(t, map[string]string{"model-1_env-1.yaml": testAlertRules}, configMap.Data)
+
+	err = ctl.DeleteConfigMap(context.Background(), "monitoring", "merlin-project-1-model-1-env-1")
+	assert.NoError(t, err)
+	_, err = v1Client.ConfigMaps("monitoring").Get(context.Background(), "merlin-project-1-model-1-env-1", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	err = ctl.DeleteConfigMap(context.Background(), "monitoring", "merlin-project-1-model-1-env-1")
+	assert.NoError(t, err)
+}
@@ -28,8 +28,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/caraml-dev/merlin/config"
@@ -49,6 +51,15 @@ type Controller interface {
 	DeleteJob(ctx context.Context, namespace, jobName string, deleteOptions metav1.DeleteOptions) error
 	DeleteJobs(ctx context.Context, namespace string, deleteOptions metav1.DeleteOptions, listOptions metav1.ListOptions) error
 
+	ValidateInferenceGraph() error
+	DeployInferenceGraph(ctx context.Context, graph *models.InferenceGraph, namespace string, labels map[string]string, loggerURL string) (string, error)
+	DeleteInferenceGraph(ctx context.Context, namespace, name string) error
+
+	ApplyPrometheusRule(ctx context.Context, namespace, name string, labels map[string]string, rules string) error
+	ApplyConfigMap(ctx context.Context, namespace, name string, labels map[string]string, data map[string]string) error
+	DeletePrometheusRule(ctx context.Context, namespace, name string) error
+	DeleteConfigMap(ctx context.Context, namespace, name string) error
+
 	ContainerFetcher
 }
 
@@ -72,6 +83,7 @@ type controller struct {
 	servingClient              kservev1beta1client.ServingV1beta1Interface
 	clusterClient              corev1client.CoreV1Interface
 	batchClient                batchv1client.BatchV1Interface
+	dynamicClient              dynamic.Interface
 	namespaceCreator           NamespaceCreator
 	deploymentConfig           *config.DeploymentConfig
 	kfServingResourceTemplater *resource.InferenceServiceTemplater
@@ -98,18 +110,24 @@ func NewController(clusterConfig Config, deployConfig config.DeploymentConfig, s
 		return nil, err
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	containerFetcher := NewContainerFetcher(coreV1Client, Metadata{
 		ClusterName: clusterConfig.ClusterName,
 		GcpProject:  clusterConfig.GcpProject,
 	})
 
 	kfServingResourceTemplater := resource.NewInferenceServiceTemplater(standardTransformerConfig)
-	return newController(servingClient, coreV1Client, batchV1Client, deployConfig, containerFetcher, kfServingResourceTemplater)
+	return newController(servingClient, coreV1Client, batchV1Client, dynamicClient, deployConfig, containerFetcher, kfServingResourceTemplater)
 }
 
 func newController(kfservingClient kservev1beta1client.ServingV1beta1Interface,
 	coreV1Client corev1client.CoreV1Interface,
 	batchV1Client batchv1client.BatchV1Interface,
+	dynamicClient dynamic.Interface,
 	deploymentConfig config.DeploymentConfig,
 	containerFetcher ContainerFetcher,
 	templater *resource.InferenceServiceTemplater,
@@ -118,6 +136,7 @@ func newController(kfservingClient kservev1beta1client.ServingV1beta1Interface,
 		servingClient:              kfservingClient,
 		clusterClient:              coreV1Client,
 		batchClient:                batchV1Client,
+		dynamicClient:              dynamicClient,
 		namespaceCreator:           NewNamespaceCreator(coreV1Client, deploymentConfig.NamespaceTimeout),
 		deploymentConfig:           &deploymentConfig,
 		ContainerFetcher:           containerFetcher,
@@ -126,6 +145,7 @@ func newController(kfservingClient kservev1beta1client.ServingV1beta1Interface,
 }
 
 func (k *controller) Deploy(ctx context.Context, modelService *models.Service) (*models.Service, error) {
+	deploymentStartTime := time.Now()
 	if modelService.ResourceRequest != nil {
 		cpuRequest, _ := modelService.ResourceRequest.CPURequest.AsInt64()
 		maxCPU, _ := k.deploymentConfig.MaxCPU.AsInt64()
@@ -141,6 +161,11 @@ func (k *controller) Deploy(ctx context.Context, modelService *models.Service) (
 		}
 	}
 
+	// fail before creating the inference service that KServe would reject
+	if err := resource.ValidateScaledObject(modelService, k.deploymentConfig); err != nil {
+		return nil, err
+	}
+
 	_, err := k.namespaceCreator.CreateNamespace(ctx, modelService.Namespace)
 	if err != nil {
 		log.Errorf("unable to create namespace %s %v", modelService.Namespace, err)
@@ -182,12 +207,28 @@ func (k *controller) Deploy(ctx context.Context, modelService *models.Service) (
 		}
 	}
 
-	s, err = k.waitInferenceServiceReady(s)
+	if err := k.deployScaledObject(ctx, modelService); err != nil {
+		log.Errorf("unable to deploy scaled object %s %v", isvcName, err)
+		if err := k.deleteInferenceService(isvcName, modelService.Namespace); err != nil {
+			log.Warnf("unable to delete inference service %s with error %v", isvcName, err)
+		}
+
+		return nil, ErrUnableToDeployScaledObject
+	}
+
+	s, err = k.waitInferenceServiceReady(modelService, s)
+	k.recordKubernetesEvents(ctx, modelService, deploymentStartTime)
 	if err != nil {
+		// collect the container failures before the pods are deleted
+		k.recordContainerFailures(ctx, modelService)
+
 		// remove created inferenceservice when got error
 		if err := k.deleteInferenceService(isvcName, modelService.Namespace); err != nil {
 			log.Warnf("unable to delete inference service %s with error %v", isvcName, err)
 		}
+		if err := k.deleteScaledObject(ctx, modelService); err != nil {
+			log.Warnf("unable to delete scaled object %s with error %v", isvcName, err)
+		}
 
 		return nil, err
 	}
@@ -213,9 +254,54 @@ func (k *controller) Delete(ctx context.Context, modelService *models.Service) (
 	if err := k.deleteInferenceService(modelService.Name, modelService.Namespace); err != nil {
 		return nil, err
 	}
+	if err := k.deleteScaledObject(ctx, modelService); err != nil {
+		return nil, err
+	}
 	return modelService, nil
 }
 
+// deployScaledObject creates or updates KEDA's ScaledObject of model service using prometheus query autoscaling.
+// Left over ScaledObject is removed if the model service no longer uses it.
+func (k *controller) deployScaledObject(ctx context.Context, modelService *models.Service) error {
+	if !resource.IsUsingScaledObject(modelService) {
+		return k.deleteScaledObject(ctx, modelService)
+	}
+
+	spec, err := k.kfServingResourceTemplater.CreateScaledObjectSpec(modelService, k.deploymentConfig)
+	if err != nil {
+		return err
+	}
+
+	scaledObjects := k.dynamicClient.Resource(resource.ScaledObjectGVR).Namespace(modelService.Namespace)
+	existing, err := scaledObjects.Get(ctx, spec.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to check scaled object: %s", spec.GetName())
+		}
+
+		_, err = scaledObjects.Create(ctx, spec, metav1.CreateOptions{})
+		return errors.Wrapf(err, "unable to create scaled object: %s", spec.GetName())
+	}
+
+	spec.SetResourceVersion(existing.GetResourceVersion())
+	_, err = scaledObjects.Update(ctx, spec, metav1.UpdateOptions{})
+	return errors.Wrapf(err, "unable to update scaled object: %s", spec.GetName())
+}
+
+func (k *controller) deleteScaledObject(ctx context.Context, modelService *models.Service) error {
+	// KEDA is not installed in the cluster if it's not configured
+	if k.deploymentConfig.KedaConfig == nil || k.dynamicClient == nil {
+		return nil
+	}
+
+	name := resource.ScaledObjectName(modelService)
+	err := k.dynamicClient.Resource(resource.ScaledObjectGVR).Namespace(modelService.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if client.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "unable to delete scaled object: %s", name)
+	}
+	return nil
+}
+
 func (k *controller) deleteInferenceService(serviceName string, namespace string) error {
 	gracePeriod := int64(deletionGracePeriodSecond)
 	err := k.servingClient.InferenceServices(namespace).Delete(serviceName, &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
@@ -225,9 +311,11 @@ func (k *controller) deleteInferenceService(serviceName string, namespace string
 	return nil
 }
 
-func (k *controller) waitInferenceServiceReady(service *kservev1beta1.InferenceService) (*kservev1beta1.InferenceService, error) {
+// waitInferenceServiceReady polls the inference service until it is ready, recording its condition changes to the model service
+func (k *controller) waitInferenceServiceReady(modelService *models.Service, service *kservev1beta1.InferenceService) (*kservev1beta1.InferenceService, error) {
 	timeout := time.After(k.deploymentConfig.DeploymentTimeout)
 	ticker := time.NewTicker(time.Second * tickDurationSecond)
+	observedConditions := make(map[apis.ConditionType]string)
 
 	for {
 		select {
@@ -241,6 +329,7 @@ func (k *controller) waitInferenceServiceReady(service *kservev1beta1.InferenceS
 				return nil, ErrUnableToGetInferenceServiceStatus
 			}
 
+			recordConditionChanges(modelService, s, observedConditions)
 			if s.Status.IsReady() {
 				// Inference service is completely ready
 				return s, nil
@@ -29,8 +29,10 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
 	ktesting "k8s.io/client-go/testing"
@@ -38,9 +40,12 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/network"
 
+	clusterresource "github.com/caraml-dev/merlin/cluster/resource"
 	"github.com/caraml-dev/merlin/config"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/autoscaling"
+	"github.com/caraml-dev/merlin/pkg/deployment"
 )
 
 const (
@@ -270,7 +275,7 @@ func TestController_DeployInferenceService_NamespaceCreation(t *testing.T) {
 			}
 
 			containerFetcher := NewContainerFetcher(v1Client, clusterMetadata)
-			ctl, _ := newController(kfClient, v1Client, nil, deployConfig, containerFetcher, nil)
+			ctl, _ := newController(kfClient, v1Client, nil, nil, deployConfig, containerFetcher, nil)
 			iSvc, err := ctl.Deploy(context.Background(), modelSvc)
 
 			if tt.wantError {
@@ -644,7 +649,7 @@ func TestController_DeployInferenceService(t *testing.T) {
 			}
 
 			containerFetcher := NewContainerFetcher(v1Client, clusterMetadata)
-			ctl, _ := newController(kfClient, v1Client, nil, deployConfig, containerFetcher, nil)
+			ctl, _ := newController(kfClient, v1Client, nil, nil, deployConfig, containerFetcher, nil)
 			iSvc, err := ctl.Deploy(context.Background(), tt.modelService)
 
 			if tt.wantError {
@@ -658,6 +663,133 @@ func TestController_DeployInferenceService(t *testing.T) {
 	}
 }
 
+func TestController_DeployInferenceService_ScaledObject(t *testing.T) {
+	model := &models.Model{
+		Name: "my-model",
+	}
+	project := mlp.Project{
+		Name: "my-project",
+	}
+	version := &models.Version{
+		ID: 1,
+	}
+	isvcName := models.CreateInferenceServiceName(model.Name, version.ID.String())
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: project.Name},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+
+	tests := []struct {
+		name              string
+		autoscalingPolicy *autoscaling.AutoscalingPolicy
+		existingObjects   []runtime.Object
+		wantScaledObject  bool
+	}{
+		{
+			name: "create scaled object",
+			autoscalingPolicy: &autoscaling.AutoscalingPolicy{
+				MetricsType:     autoscaling.PrometheusQuery,
+				TargetValue:     100,
+				PrometheusQuery: "sum(queue_depth)",
+			},
+			wantScaledObject: true,
+		},
+		{
+			name: "update scaled object",
+			autoscalingPolicy: &autoscaling.AutoscalingPolicy{
+				MetricsType:     autoscaling.PrometheusQuery,
+				TargetValue:     100,
+				PrometheusQuery: "sum(queue_depth)",
+			},
+			existingObjects:  []runtime.Object{fakeScaledObject(isvcName, project.Name)},
+			wantScaledObject: true,
+		},
+		{
+			name: "delete left over scaled object",
+			autoscalingPolicy: &autoscaling.AutoscalingPolicy{
+				MetricsType: autoscaling.CPUUtilization,
+				TargetValue: 50,
+			},
+			existingObjects:  []runtime.Object{fakeScaledObject(isvcName, project.Name)},
+			wantScaledObject: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kfClient := fakekserve.NewSimpleClientset().ServingV1beta1().(*fakekservev1beta1.FakeServingV1beta1)
+			kfClient.PrependReactor(getMethod, inferenceServiceResource, func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				kfClient.PrependReactor(getMethod, inferenceServiceResource, func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, fakeInferenceService(model.Name, version.ID.String(), project.Name), nil
+				})
+				return true, nil, kerrors.NewNotFound(schema.GroupResource{Group: kfservingGroup, Resource: inferenceServiceResource}, isvcName)
+			})
+			kfClient.PrependReactor(createMethod, inferenceServiceResource, func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, &kservev1beta1.InferenceService{ObjectMeta: metav1.ObjectMeta{Name: isvcName, Namespace: project.Name}}, nil
+			})
+
+			v1Client := fake.NewSimpleClientset().CoreV1()
+			nsClient := v1Client.Namespaces().(*fakecorev1.FakeNamespaces)
+			nsClient.Fake.PrependReactor(getMethod, namespaceResource, func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, namespace, nil
+			})
+
+			dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				clusterresource.ScaledObjectGVR: "ScaledObjectList",
+				revisionGVR:                     "RevisionList",
+				deploymentGVR:                   "DeploymentList",
+				replicaSetGVR:                   "ReplicaSetList",
+			}, tt.existingObjects...)
+
+			deployConfig := config.DeploymentConfig{
+				DeploymentTimeout:            2 * tickDurationSecond * time.Second,
+				NamespaceTimeout:             2 * tickDurationSecond * time.Second,
+				MaxCPU:                       resource.MustParse("8"),
+				MaxMemory:                    resource.MustParse("8Gi"),
+				DefaultModelResourceRequests: &config.ResourceRequests{},
+				KedaConfig: &config.KedaConfig{
+					PrometheusServerAddress: "http://prometheus.monitoring:9090",
+				},
+				KServeVersion: "0.11.0",
+			}
+
+			modelSvc := &models.Service{
+				Name:              isvcName,
+				Namespace:         project.Name,
+				Options:           &models.ModelOption{},
+				DeploymentMode:    deployment.RawDeploymentMode,
+				AutoscalingPolicy: tt.autoscalingPolicy,
+			}
+
+			containerFetcher := NewContainerFetcher(v1Client, clusterMetadata)
+			templater := clusterresource.NewInferenceServiceTemplater(config.StandardTransformerConfig{})
+			ctl, _ := newController(kfClient, v1Client, nil, dynamicClient, deployConfig, containerFetcher, templater)
+			iSvc, err := ctl.Deploy(context.Background(), modelSvc)
+			assert.NoError(t, err)
+			assert.NotNil(t, iSvc)
+
+			scaledObject, err := dynamicClient.Resource(clusterresource.ScaledObjectGVR).Namespace(project.Name).
+				Get(context.Background(), clusterresource.ScaledObjectName(modelSvc), metav1.GetOptions{})
+			if !tt.wantScaledObject {
+				assert.True(t, kerrors.IsNotFound(err))
+				return
+			}
+			assert.NoError(t, err)
+			query, _, _ := unstructured.NestedSlice(scaledObject.Object, "spec", "triggers")
+			assert.Len(t, query, 1)
+		})
+	}
+}
+
+func fakeScaledObject(name, namespace string) *unstructured.Unstructured {
+	scaledObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	scaledObject.SetAPIVersion(clusterresource.ScaledObjectGVR.GroupVersion().String())
+	scaledObject.SetKind("ScaledObject")
+	scaledObject.SetName(fmt.Sprintf("%s-predictor-default", name))
+	scaledObject.SetNamespace(namespace)
+	return scaledObject
+}
+
 func fakeInferenceService(model, version, project string) *kservev1beta1.InferenceService {
 	svcName := models.CreateInferenceServiceName(model, version)
 	status := createServiceReadyStatus(svcName, project, baseUrl)
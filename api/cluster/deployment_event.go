@@ -0,0 +1,184 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+)
+
+const inferenceServiceLabelSelector = "serving.kserve.io/inferenceservice=%s"
+
+var (
+	revisionGVR   = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "revisions"}
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	replicaSetGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+)
+
+// inferenceServiceResources are the resources created for an inference service, besides its pods, which are labeled with its name
+var inferenceServiceResources = []schema.GroupVersionResource{revisionGVR, deploymentGVR, replicaSetGVR}
+
+// pendingContainerReasons are waiting reasons of containers that are still starting up normally
+var pendingContainerReasons = map[string]bool{
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// recordConditionChanges appends an event to the model service for every condition of the inference service
+// whose status, reason or message changed since it was last observed
+func recordConditionChanges(modelService *models.Service, isvc *kservev1beta1.InferenceService, observed map[apis.ConditionType]string) {
+	for _, condition := range isvc.Status.Conditions {
+		state := fmt.Sprintf("%s/%s/%s", condition.Status, condition.Reason, condition.Message)
+		if observed[condition.Type] == state {
+			continue
+		}
+		observed[condition.Type] = state
+
+		message := fmt.Sprintf("%s is %s", condition.Type, condition.Status)
+		if condition.Reason != "" {
+			message = fmt.Sprintf("%s (%s)", message, condition.Reason)
+		}
+		if condition.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, condition.Message)
+		}
+
+		event := models.NewDeploymentEvent(models.DeploymentEventSourceInferenceService, string(condition.Type), message)
+		if condition.IsFalse() {
+			event = models.NewDeploymentFailureEvent(models.DeploymentEventSourceInferenceService, string(condition.Type), message)
+		}
+		event.Object = "inferenceservice/" + isvc.Name
+		if !condition.LastTransitionTime.Inner.IsZero() {
+			event.Timestamp = condition.LastTransitionTime.Inner.Time
+		}
+		modelService.Events = append(modelService.Events, event)
+	}
+}
+
+// recordKubernetesEvents appends the Kubernetes events of the inference service and its revisions, deployments and pods
+// that happened since the deployment started. The events are listed by involved object rather than for the whole namespace.
+func (k *controller) recordKubernetesEvents(ctx context.Context, modelService *models.Service, since time.Time) {
+	selectors := []string{fields.Set{"involvedObject.kind": "InferenceService", "involvedObject.name": modelService.Name}.String()}
+	for _, uid := range k.listInferenceServiceObjects(ctx, modelService) {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.uid", uid).String())
+	}
+
+	since = since.Truncate(time.Second)
+	for _, selector := range selectors {
+		events, err := k.clusterClient.Events(modelService.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+		if err != nil {
+			log.Warnf("unable to list events of inference service %s: %v", modelService.Name, err)
+			continue
+		}
+
+		for _, e := range events.Items {
+			timestamp := e.LastTimestamp.Time
+			if timestamp.IsZero() {
+				timestamp = e.EventTime.Time
+			}
+			if timestamp.Before(since) {
+				continue
+			}
+
+			event := models.NewDeploymentEvent(models.DeploymentEventSourceKubernetes, e.Reason, e.Message)
+			if e.Type == corev1.EventTypeWarning {
+				event = models.NewDeploymentFailureEvent(models.DeploymentEventSourceKubernetes, e.Reason, e.Message)
+			}
+			event.Object = strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name
+			if !timestamp.IsZero() {
+				event.Timestamp = timestamp
+			}
+			modelService.Events = append(modelService.Events, event)
+		}
+	}
+}
+
+// listInferenceServiceObjects returns the UIDs of the pods, revisions, deployments and replica sets of the inference service
+func (k *controller) listInferenceServiceObjects(ctx context.Context, modelService *models.Service) []string {
+	labelSelector := fmt.Sprintf(inferenceServiceLabelSelector, modelService.Name)
+
+	var uids []string
+	pods, err := k.ListPods(ctx, modelService.Namespace, labelSelector)
+	if err != nil {
+		log.Warnf("unable to list pods of inference service %s: %v", modelService.Name, err)
+	} else {
+		for _, pod := range pods.Items {
+			uids = append(uids, string(pod.UID))
+		}
+	}
+
+	if k.dynamicClient == nil {
+		return uids
+	}
+	for _, gvr := range inferenceServiceResources {
+		objects, err := k.dynamicClient.Resource(gvr).Namespace(modelService.Namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			// the revisions don't exist in raw deployment mode if Knative isn't installed
+			if !kerrors.IsNotFound(err) {
+				log.Warnf("unable to list %s of inference service %s: %v", gvr.Resource, modelService.Name, err)
+			}
+			continue
+		}
+		for _, object := range objects.Items {
+			uids = append(uids, string(object.GetUID()))
+		}
+	}
+	return uids
+}
+
+// recordContainerFailures appends an event for every container of the inference service's pods that is stuck waiting
+// or was terminated abnormally, e.g. due to ImagePullBackOff or OOMKilled which are not always reported as events
+func (k *controller) recordContainerFailures(ctx context.Context, modelService *models.Service) {
+	pods, err := k.ListPods(ctx, modelService.Namespace, fmt.Sprintf(inferenceServiceLabelSelector, modelService.Name))
+	if err != nil {
+		log.Warnf("unable to list pods of inference service %s: %v", modelService.Name, err)
+		return
+	}
+
+	for _, pod := range pods.Items {
+		// a new slice is built, the statuses of the pod may be shared with the informer cache
+		statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			var event *models.DeploymentEvent
+			if waiting := status.State.Waiting; waiting != nil && !pendingContainerReasons[waiting.Reason] {
+				message := fmt.Sprintf("container %s is waiting: %s", status.Name, waiting.Message)
+				event = models.NewDeploymentFailureEvent(models.DeploymentEventSourceKubernetes, waiting.Reason, message)
+			}
+
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated != nil && terminated.ExitCode != 0 {
+				message := fmt.Sprintf("container %s terminated with exit code %d", status.Name, terminated.ExitCode)
+				if terminated.Message != "" {
+					message = fmt.Sprintf("%s: %s", message, terminated.Message)
+				}
+				// the termination reason is the cause of the waiting state (e.g. CrashLoopBackOff after OOMKilled)
+				event = models.NewDeploymentFailureEvent(models.DeploymentEventSourceKubernetes, terminated.Reason, message)
+				event.Timestamp = terminated.FinishedAt.Time
+			}
+
+			if event == nil {
+				continue
+			}
+			event.Object = "pod/" + pod.Name
+			if event.Timestamp.IsZero() {
+				event.Timestamp = time.Now()
+			}
+			modelService.Events = append(modelService.Events, event)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestRecordConditionChanges(t *testing.T) {
+	modelService := &models.Service{Name: "model-1", Namespace: "project"}
+	isvc := &kservev1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{Name: "model-1", Namespace: "project"},
+	}
+	observed := make(map[apis.ConditionType]string)
+
+	isvc.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{
+		{Type: "PredictorReady", Status: corev1.ConditionUnknown},
+	}}
+	recordConditionChanges(modelService, isvc, observed)
+	// an unchanged condition is only recorded once
+	recordConditionChanges(modelService, isvc, observed)
+
+	isvc.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{
+		{Type: "PredictorReady", Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded", Message: "revision failed to become ready"},
+	}}
+	recordConditionChanges(modelService, isvc, observed)
+
+	assert.Len(t, modelService.Events, 2)
+	assert.Equal(t, models.DeploymentEventSourceInferenceService, modelService.Events[0].Source)
+	assert.Equal(t, models.DeploymentEventTypeNormal, modelService.Events[0].Type)
+	assert.Equal(t, "PredictorReady is Unknown", modelService.Events[0].Message)
+	assert.Equal(t, "inferenceservice/model-1", modelService.Events[0].Object)
+
+	assert.Equal(t, models.DeploymentEventTypeWarning, modelService.Events[1].Type)
+	assert.Equal(t, "PredictorReady", modelService.Events[1].Reason)
+	assert.Equal(t, "PredictorReady is False (ProgressDeadlineExceeded): revision failed to become ready", modelService.Events[1].Message)
+	assert.Equal(t, models.FailureClassReadinessTimeout, modelService.Events[1].FailureClass)
+}
+
+func TestController_RecordKubernetesEvents(t *testing.T) {
+	since := time.Now()
+	after := metav1.NewTime(since.Add(time.Minute))
+	before := metav1.NewTime(since.Add(-time.Hour))
+
+	labels := map[string]string{"serving.kserve.io/inferenceservice": "model-1"}
+	pod := corev1.ObjectReference{Kind: "Pod", Name: "model-1-predictor-00001-deployment-abc", UID: "pod-uid"}
+	replicaSet := corev1.ObjectReference{Kind: "ReplicaSet", Name: "model-1-predictor-00001-deployment-abc", UID: "replicaset-uid"}
+	events := []corev1.Event{
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-1", Namespace: "project"},
+			InvolvedObject: pod,
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Failed",
+			Message:        "Failed to pull image \"model:latest\": not found",
+			LastTimestamp:  after,
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-2", Namespace: "project"},
+			InvolvedObject: pod,
+			Type:           corev1.EventTypeNormal,
+			Reason:         "Scheduled",
+			Message:        "Successfully assigned pod to node-1",
+			LastTimestamp:  after,
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-3", Namespace: "project"},
+			InvolvedObject: replicaSet,
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message:        "exceeded quota: project-quota",
+			LastTimestamp:  after,
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-4", Namespace: "project"},
+			InvolvedObject: corev1.ObjectReference{Kind: "InferenceService", Name: "model-1"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "InternalError",
+			Message:        "fails to reconcile predictor",
+			LastTimestamp:  after,
+		},
+		// event of a previous deployment
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-5", Namespace: "project"},
+			InvolvedObject: pod,
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			LastTimestamp:  before,
+		},
+		// event of another version of the model
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-6", Namespace: "project"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "model-10-predictor-00001-deployment-abc", UID: "other-pod-uid"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			LastTimestamp:  after,
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: "project", UID: pod.UID, Labels: labels},
+	})
+	// the fake clientset ignores the field selectors
+	var fieldSelectors []string
+	clientset.PrependReactor("list", "events", func(action ktesting.Action) (bool, runtime.Object, error) {
+		selector := action.(ktesting.ListAction).GetListRestrictions().Fields
+		fieldSelectors = append(fieldSelectors, selector.String())
+
+		list := &corev1.EventList{}
+		for _, e := range events {
+			if selector.Matches(fields.Set{"involvedObject.kind": e.InvolvedObject.Kind, "involvedObject.name": e.InvolvedObject.Name, "involvedObject.uid": string(e.InvolvedObject.UID)}) {
+				list.Items = append(list.Items, e)
+			}
+		}
+		return true, list, nil
+	})
+
+	rs := &unstructured.Unstructured{}
+	rs.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"})
+	rs.SetName(replicaSet.Name)
+	rs.SetNamespace("project")
+	rs.SetUID(replicaSet.UID)
+	rs.SetLabels(labels)
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		revisionGVR:   "RevisionList",
+		deploymentGVR: "DeploymentList",
+		replicaSetGVR: "ReplicaSetList",
+	}, rs)
+
+	ctl, _ := newController(nil, clientset.CoreV1(), nil, dynamicClient, config.DeploymentConfig{}, nil, nil)
+
+	modelService := &models.Service{Name: "model-1", Namespace: "project"}
+	ctl.(*controller).recordKubernetesEvents(context.Background(), modelService, since)
+
+	// the events are listed by involved object
+	assert.ElementsMatch(t, []string{
+		"involvedObject.kind=InferenceService,involvedObject.name=model-1",
+		"involvedObject.uid=pod-uid",
+		"involvedObject.uid=replicaset-uid",
+	}, fieldSelectors)
+
+	assert.Len(t, modelService.Events, 4)
+	for _, event := range modelService.Events {
+		assert.Equal(t, models.DeploymentEventSourceKubernetes, event.Source)
+		assert.Equal(t, after.Time.Unix(), event.Timestamp.Unix())
+
+		switch event.Reason {
+		case "Failed":
+			assert.Equal(t, "pod/model-1-predictor-00001-deployment-abc", event.Object)
+			assert.Equal(t, models.DeploymentEventTypeWarning, event.Type)
+			assert.Equal(t, models.FailureClassImagePull, event.FailureClass)
+		case "Scheduled":
+			assert.Equal(t, "pod/model-1-predictor-00001-deployment-abc", event.Object)
+			assert.Equal(t, models.DeploymentEventTypeNormal, event.Type)
+			assert.Empty(t, event.FailureClass)
+		case "FailedCreate":
+			assert.Equal(t, "replicaset/model-1-predictor-00001-deployment-abc", event.Object)
+			assert.Equal(t, models.DeploymentEventTypeWarning, event.Type)
+		case "InternalError":
+			assert.Equal(t, "inferenceservice/model-1", event.Object)
+			assert.Equal(t, models.DeploymentEventTypeWarning, event.Type)
+		default:
+			t.Errorf("unexpected event %s", event.Reason)
+		}
+	}
+}
+
+func TestController_RecordContainerFailures(t *testing.T) {
+	labels := map[string]string{"serving.kserve.io/inferenceservice": "model-1"}
+	objects := []runtime.Object{
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "model-1-predictor-abc", Namespace: "project", Labels: labels},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "kserve-container",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 40s restarting failed container"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						Reason:     "OOMKilled",
+						ExitCode:   137,
+						FinishedAt: metav1.Now(),
+					}},
+				},
+				{
+					Name:  "queue-proxy",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "model-1-transformer-abc", Namespace: "project", Labels: labels},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "transformer",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+				},
+				{
+					Name:  "queue-proxy",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+				},
+			}},
+		},
+	}
+	ctl, _ := newController(nil, fake.NewSimpleClientset(objects...).CoreV1(), nil, nil, config.DeploymentConfig{}, nil, nil)
+
+	modelService := &models.Service{Name: "model-1", Namespace: "project"}
+	ctl.(*controller).recordContainerFailures(context.Background(), modelService)
+
+	assert.Len(t, modelService.Events, 2)
+	failures := make(map[string]*models.DeploymentEvent)
+	for _, event := range modelService.Events {
+		assert.Equal(t, models.DeploymentEventTypeWarning, event.Type)
+		failures[event.Object] = event
+	}
+
+	assert.Equal(t, "OOMKilled", failures["pod/model-1-predictor-abc"].Reason)
+	assert.Equal(t, "container kserve-container terminated with exit code 137", failures["pod/model-1-predictor-abc"].Message)
+	assert.Equal(t, models.FailureClassOOMKilled, failures["pod/model-1-predictor-abc"].FailureClass)
+
+	assert.Equal(t, "ImagePullBackOff", failures["pod/model-1-transformer-abc"].Reason)
+	assert.Equal(t, models.FailureClassImagePull, failures["pod/model-1-transformer-abc"].FailureClass)
+}
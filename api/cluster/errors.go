@@ -26,4 +26,11 @@ var (
 	ErrUnableToCreateInferenceService    = errors.New("error creating inference service")
 	ErrUnableToUpdateInferenceService    = errors.New("error updating inference service")
 	ErrTimeoutCreateInferenceService     = errors.New("timeout creating inference service")
+	ErrUnableToDeployScaledObject        = errors.New("error deploying scaled object")
+	ErrUnableToDeployInferenceGraph      = errors.New("error deploying inference graph")
+	ErrTimeoutCreateInferenceGraph       = errors.New("timeout creating inference graph")
+	ErrUnableToApplyPrometheusRule       = errors.New("error applying prometheus rule")
+	ErrUnableToApplyConfigMap            = errors.New("error applying config map")
+	ErrUnableToDeletePrometheusRule      = errors.New("error deleting prometheus rule")
+	ErrUnableToDeleteConfigMap           = errors.New("error deleting config map")
 )
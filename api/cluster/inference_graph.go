@@ -0,0 +1,75 @@
+package cluster
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/caraml-dev/merlin/cluster/resource"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+)
+
+// ValidateInferenceGraph returns error if the inference graph can't be deployed to the cluster, e.g. its KServe is too old.
+func (k *controller) ValidateInferenceGraph() error {
+	return resource.ValidateInferenceGraph(k.deploymentConfig)
+}
+
+// DeployInferenceGraph creates or updates the inference service running the router of the inference graph and waits
+// until it's ready. The requests and responses of the graph are logged to loggerURL if its logger is enabled.
+// It returns the URL of the deployed inference graph.
+func (k *controller) DeployInferenceGraph(ctx context.Context, graph *models.InferenceGraph, namespace string, labels map[string]string, loggerURL string) (string, error) {
+	if err := k.ValidateInferenceGraph(); err != nil {
+		return "", err
+	}
+
+	_, err := k.namespaceCreator.CreateNamespace(ctx, namespace)
+	if err != nil {
+		log.Errorf("unable to create namespace %s %v", namespace, err)
+		return "", ErrUnableToCreateNamespace
+	}
+
+	spec, err := resource.CreateInferenceGraphSpec(graph, namespace, labels, loggerURL, k.deploymentConfig)
+	if err != nil {
+		log.Errorf("unable to create inference graph spec %s %v", graph.Name, err)
+		return "", ErrUnableToDeployInferenceGraph
+	}
+
+	inferenceServices := k.servingClient.InferenceServices(namespace)
+	s, err := inferenceServices.Get(spec.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Errorf("unable to check inference graph %s %v", spec.Name, err)
+			return "", ErrUnableToDeployInferenceGraph
+		}
+
+		s, err = inferenceServices.Create(spec)
+	} else {
+		spec.ResourceVersion = s.ResourceVersion
+		s, err = inferenceServices.Update(spec)
+	}
+	if err != nil {
+		log.Errorf("unable to deploy inference graph %s %v", spec.Name, err)
+		return "", ErrUnableToDeployInferenceGraph
+	}
+
+	// the condition changes of the router are not recorded as the inference graph has no deployment events
+	s, err = k.waitInferenceServiceReady(&models.Service{Name: spec.Name, Namespace: namespace}, s)
+	if err != nil {
+		if err := k.deleteInferenceService(spec.Name, namespace); err != nil {
+			log.Warnf("unable to delete inference graph %s with error %v", spec.Name, err)
+		}
+		if err == ErrTimeoutCreateInferenceService {
+			return "", ErrTimeoutCreateInferenceGraph
+		}
+		return "", ErrUnableToDeployInferenceGraph
+	}
+	return s.Status.URL.String(), nil
+}
+
+// DeleteInferenceGraph deletes the inference service running the router of the inference graph.
+// It's not an error if the inference graph doesn't exist.
+func (k *controller) DeleteInferenceGraph(ctx context.Context, namespace, name string) error {
+	return k.deleteInferenceService(models.InferenceGraphServiceName(name), namespace)
+}
@@ -41,6 +41,34 @@ func (_m *Controller) OnDeleteMatch(matchers ...interface{}) *Controller_Delete
 	return &Controller_Delete{Call: c}
 }
 
+// ApplyConfigMap provides a mock function with given fields: ctx, namespace, name, labels, data
+func (_m *Controller) ApplyConfigMap(ctx context.Context, namespace string, name string, labels map[string]string, data map[string]string) error {
+	ret := _m.Called(ctx, namespace, name, labels, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, map[string]string, map[string]string) error); ok {
+		r0 = rf(ctx, namespace, name, labels, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ApplyPrometheusRule provides a mock function with given fields: ctx, namespace, name, labels, rules
+func (_m *Controller) ApplyPrometheusRule(ctx context.Context, namespace string, name string, labels map[string]string, rules string) error {
+	ret := _m.Called(ctx, namespace, name, labels, rules)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, map[string]string, string) error); ok {
+		r0 = rf(ctx, namespace, name, labels, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, modelService
 func (_m *Controller) Delete(ctx context.Context, modelService *models.Service) (*models.Service, error) {
 	ret := _m.Called(ctx, modelService)
@@ -82,6 +110,34 @@ func (_m *Controller) OnDeleteJobMatch(matchers ...interface{}) *Controller_Dele
 	return &Controller_DeleteJob{Call: c}
 }
 
+// DeleteConfigMap provides a mock function with given fields: ctx, namespace, name
+func (_m *Controller) DeleteConfigMap(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteInferenceGraph provides a mock function with given fields: ctx, namespace, name
+func (_m *Controller) DeleteInferenceGraph(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteJob provides a mock function with given fields: ctx, namespace, jobName, deleteOptions
 func (_m *Controller) DeleteJob(ctx context.Context, namespace string, jobName string, deleteOptions v1.DeleteOptions) error {
 	ret := _m.Called(ctx, namespace, jobName, deleteOptions)
@@ -146,6 +202,20 @@ func (_m *Controller) OnDeployMatch(matchers ...interface{}) *Controller_Deploy
 	return &Controller_Deploy{Call: c}
 }
 
+// DeletePrometheusRule provides a mock function with given fields: ctx, namespace, name
+func (_m *Controller) DeletePrometheusRule(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Deploy provides a mock function with given fields: ctx, modelService
 func (_m *Controller) Deploy(ctx context.Context, modelService *models.Service) (*models.Service, error) {
 	ret := _m.Called(ctx, modelService)
@@ -187,6 +257,27 @@ func (_m *Controller) OnGetContainersMatch(matchers ...interface{}) *Controller_
 	return &Controller_GetContainers{Call: c}
 }
 
+// DeployInferenceGraph provides a mock function with given fields: ctx, graph, namespace, labels, loggerURL
+func (_m *Controller) DeployInferenceGraph(ctx context.Context, graph *models.InferenceGraph, namespace string, labels map[string]string, loggerURL string) (string, error) {
+	ret := _m.Called(ctx, graph, namespace, labels, loggerURL)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *models.InferenceGraph, string, map[string]string, string) string); ok {
+		r0 = rf(ctx, graph, namespace, labels, loggerURL)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.InferenceGraph, string, map[string]string, string) error); ok {
+		r1 = rf(ctx, graph, namespace, labels, loggerURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetContainers provides a mock function with given fields: ctx, namespace, labelSelector
 func (_m *Controller) GetContainers(ctx context.Context, namespace string, labelSelector string) ([]*models.Container, error) {
 	ret := _m.Called(ctx, namespace, labelSelector)
@@ -332,3 +423,17 @@ func (_m *Controller) StreamPodLogs(ctx context.Context, namespace string, podNa
 
 	return r0, r1
 }
+
+// ValidateInferenceGraph provides a mock function with given fields:
+func (_m *Controller) ValidateInferenceGraph() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
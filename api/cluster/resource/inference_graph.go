@@ -0,0 +1,164 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	kserveconstant "github.com/kserve/kserve/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/protocol"
+)
+
+const (
+	predictPathSuffix = ":predict"
+
+	// inferenceGraphMinKServeVersion is the first KServe version releasing the router running the inference graphs
+	inferenceGraphMinKServeVersion = "0.10.0"
+	defaultInferenceGraphRouter    = "kserve/router"
+)
+
+// ValidateInferenceGraph returns error if inference graphs can't be deployed with the given deployment config
+func ValidateInferenceGraph(config *config.DeploymentConfig) error {
+	if !isVersionAtLeast(config.KServeVersion, inferenceGraphMinKServeVersion) {
+		return fmt.Errorf("inference graph requires KServe %s or newer, but the environment has KServe version %q",
+			inferenceGraphMinKServeVersion, config.KServeVersion)
+	}
+	return nil
+}
+
+// CreateInferenceGraphSpec creates the inference service running KServe's router of the given inference graph.
+// The router is deployed as a regular inference service, instead of KServe's InferenceGraph, so that it has the same
+// request logger and metrics as the model version endpoints. The version endpoints used by the graph's steps must have
+// been resolved beforehand.
+func CreateInferenceGraphSpec(graph *models.InferenceGraph, namespace string, labels map[string]string, loggerURL string, config *config.DeploymentConfig) (*kservev1beta1.InferenceService, error) {
+	if err := ValidateInferenceGraph(config); err != nil {
+		return nil, err
+	}
+
+	graphSpec, err := createInferenceGraphRouterSpec(graph)
+	if err != nil {
+		return nil, err
+	}
+	graphJSON, err := json.Marshal(graphSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceRequests := config.DefaultModelResourceRequests
+	cpuLimit := resourceRequests.CPURequest.DeepCopy()
+	cpuLimit.Add(resourceRequests.CPURequest)
+	memoryLimit := resourceRequests.MemoryRequest.DeepCopy()
+	memoryLimit.Add(resourceRequests.MemoryRequest)
+
+	predictor := kservev1beta1.PredictorSpec{
+		PodSpec: kservev1beta1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  kserveconstant.InferenceServiceContainerName,
+					Image: inferenceGraphRouterImage(config),
+					Args:  []string{"--graph-json", string(graphJSON)},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resourceRequests.CPURequest,
+							corev1.ResourceMemory: resourceRequests.MemoryRequest,
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    cpuLimit,
+							corev1.ResourceMemory: memoryLimit,
+						},
+					},
+				},
+			},
+		},
+		ComponentExtensionSpec: kservev1beta1.ComponentExtensionSpec{
+			MinReplicas: &resourceRequests.MinReplica,
+			MaxReplicas: resourceRequests.MaxReplica,
+		},
+	}
+	if graph.Logger != nil && graph.Logger.Enabled && loggerURL != "" {
+		predictor.Logger = createLoggerSpec(loggerURL, *graph.Logger, protocol.HttpJson)
+	}
+
+	return &kservev1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      graph.ServiceName(),
+			Namespace: namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				kserveconstant.DeploymentMode: string(kserveconstant.Serverless),
+			},
+		},
+		Spec: kservev1beta1.InferenceServiceSpec{
+			Predictor: predictor,
+		},
+	}, nil
+}
+
+// createInferenceGraphRouterSpec creates the graph passed to KServe's router, following the spec of KServe's InferenceGraph
+func createInferenceGraphRouterSpec(graph *models.InferenceGraph) (map[string]interface{}, error) {
+	nodes := map[string]interface{}{}
+	for nodeName, node := range graph.Nodes {
+		steps := []interface{}{}
+		for _, step := range node.Steps {
+			inferenceStep := map[string]interface{}{}
+			if step.Name != "" {
+				inferenceStep["stepName"] = step.Name
+			}
+			if step.NodeName != "" {
+				inferenceStep["nodeName"] = step.NodeName
+			} else {
+				serviceURL, err := versionEndpointPredictURL(step.VersionEndpoint)
+				if err != nil {
+					return nil, err
+				}
+				inferenceStep["serviceUrl"] = serviceURL
+			}
+			if step.Data != "" {
+				inferenceStep["data"] = step.Data
+			}
+			if step.Condition != "" {
+				inferenceStep["condition"] = step.Condition
+			}
+			steps = append(steps, inferenceStep)
+		}
+
+		nodes[nodeName] = map[string]interface{}{
+			"routerType": string(node.RouterType),
+			"steps":      steps,
+		}
+	}
+
+	return map[string]interface{}{
+		"nodes": nodes,
+	}, nil
+}
+
+func inferenceGraphRouterImage(config *config.DeploymentConfig) string {
+	if config.InferenceGraphRouterImage != "" {
+		return config.InferenceGraphRouterImage
+	}
+	return fmt.Sprintf("%s:v%s", defaultInferenceGraphRouter, strings.TrimPrefix(config.KServeVersion, "v"))
+}
+
+func versionEndpointPredictURL(versionEndpoint *models.VersionEndpoint) (string, error) {
+	if versionEndpoint == nil {
+		return "", fmt.Errorf("version endpoint of inference graph step is not resolved")
+	}
+
+	hostname := versionEndpoint.Hostname()
+	if hostname == "" {
+		return "", fmt.Errorf("version endpoint %s doesn't have url", versionEndpoint.ID)
+	}
+
+	path := versionEndpoint.Path()
+	if !strings.HasSuffix(path, predictPathSuffix) {
+		path += predictPathSuffix
+	}
+	return fmt.Sprintf("http://%s%s", hostname, path), nil
+}
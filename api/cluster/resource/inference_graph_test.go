@@ -0,0 +1,119 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	kserveconstant "github.com/kserve/kserve/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestCreateInferenceGraphSpec(t *testing.T) {
+	preprocessID, modelID := uuid.New(), uuid.New()
+	preprocess := &models.VersionEndpoint{ID: preprocessID, URL: "http://preprocess-1.project.models.example.com/v1/models/preprocess-1"}
+	model := &models.VersionEndpoint{ID: modelID, URL: "http://model-2.project.models.example.com/v1/models/model-2:predict"}
+
+	graph := &models.InferenceGraph{
+		Name: "my-graph",
+		Nodes: models.InferenceGraphNodes{
+			models.InferenceGraphRootNode: {
+				RouterType: models.InferenceGraphSequence,
+				Steps: []*models.InferenceGraphStep{
+					{VersionEndpointID: &preprocessID, VersionEndpoint: preprocess},
+					{NodeName: "switch", Data: "$response"},
+				},
+			},
+			"switch": {
+				RouterType: models.InferenceGraphSwitch,
+				Steps: []*models.InferenceGraphStep{
+					{Name: "model", VersionEndpointID: &modelID, VersionEndpoint: model, Condition: "instances.#(segment==\"a\")"},
+				},
+			},
+		},
+	}
+	labels := map[string]string{"app": "my-graph"}
+
+	deploymentConfig := &config.DeploymentConfig{
+		KServeVersion:                "0.10.0",
+		DefaultModelResourceRequests: defaultModelResourceRequests,
+	}
+
+	graphJSON := `{"nodes":{"root":{"routerType":"Sequence","steps":[` +
+		`{"serviceUrl":"http://preprocess-1.project.models.example.com/v1/models/preprocess-1:predict"},` +
+		`{"data":"$response","nodeName":"switch"}]},` +
+		`"switch":{"routerType":"Switch","steps":[` +
+		`{"condition":"instances.#(segment==\"a\")","serviceUrl":"http://model-2.project.models.example.com/v1/models/model-2:predict","stepName":"model"}]}}}`
+
+	expected := &kservev1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-graph-graph",
+			Namespace: "project",
+			Labels:    labels,
+			Annotations: map[string]string{
+				kserveconstant.DeploymentMode: string(kserveconstant.Serverless),
+			},
+		},
+		Spec: kservev1beta1.InferenceServiceSpec{
+			Predictor: kservev1beta1.PredictorSpec{
+				PodSpec: kservev1beta1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:      kserveconstant.InferenceServiceContainerName,
+							Image:     "kserve/router:v0.10.0",
+							Args:      []string{"--graph-json", graphJSON},
+							Resources: expDefaultModelResourceRequests,
+						},
+					},
+				},
+				ComponentExtensionSpec: kservev1beta1.ComponentExtensionSpec{
+					MinReplicas: &defaultModelResourceRequests.MinReplica,
+					MaxReplicas: defaultModelResourceRequests.MaxReplica,
+				},
+			},
+		},
+	}
+
+	spec, err := CreateInferenceGraphSpec(graph, "project", labels, "", deploymentConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, spec)
+
+	graph.Logger = &models.LoggerConfig{Enabled: true, Mode: models.LogAll}
+	spec, err = CreateInferenceGraphSpec(graph, "project", labels, "http://logger.example.com", deploymentConfig)
+	assert.NoError(t, err)
+	assert.NotNil(t, spec.Spec.Predictor.Logger)
+	assert.Equal(t, kservev1beta1.LogAll, spec.Spec.Predictor.Logger.Mode)
+
+	deploymentConfig.InferenceGraphRouterImage = "ghcr.io/example/router:latest"
+	spec, err = CreateInferenceGraphSpec(graph, "project", labels, "", deploymentConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcr.io/example/router:latest", spec.Spec.Predictor.Containers[0].Image)
+
+	graph.Nodes["switch"].Steps[0].VersionEndpoint = nil
+	_, err = CreateInferenceGraphSpec(graph, "project", labels, "", deploymentConfig)
+	assert.Error(t, err)
+}
+
+func TestValidateInferenceGraph(t *testing.T) {
+	tests := []struct {
+		kserveVersion string
+		wantErr       bool
+	}{
+		{kserveVersion: "0.8.0", wantErr: true},
+		{kserveVersion: "0.9.0", wantErr: true},
+		{kserveVersion: "", wantErr: true},
+		{kserveVersion: "0.10.0", wantErr: false},
+		{kserveVersion: "v0.11.2", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kserveVersion, func(t *testing.T) {
+			err := ValidateInferenceGraph(&config.DeploymentConfig{KServeVersion: tt.kserveVersion})
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package resource
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// PrometheusRuleGVR is the group version resource of Prometheus Operator's PrometheusRule
+var PrometheusRuleGVR = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "prometheusrules",
+}
+
+// CreatePrometheusRuleSpec creates Prometheus Operator's PrometheusRule from a Prometheus rules file.
+// The rules file has the same format as the one loaded by Prometheus, i.e. a list of rule groups.
+func CreatePrometheusRuleSpec(name, namespace string, labels map[string]string, rules string) (*unstructured.Unstructured, error) {
+	rulesJSON, err := yaml.YAMLToJSON([]byte(rules))
+	if err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(rulesJSON, &spec); err != nil {
+		return nil, err
+	}
+
+	prometheusRule := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	prometheusRule.SetAPIVersion(PrometheusRuleGVR.GroupVersion().String())
+	prometheusRule.SetKind("PrometheusRule")
+	prometheusRule.SetName(name)
+	prometheusRule.SetNamespace(namespace)
+	prometheusRule.SetLabels(labels)
+
+	return prometheusRule, nil
+}
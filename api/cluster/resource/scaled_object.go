@@ -0,0 +1,151 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/autoscaling"
+	"github.com/caraml-dev/merlin/pkg/deployment"
+	kserveconstant "github.com/kserve/kserve/pkg/constants"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// autoscalerClassExternal instructs KServe to not manage the autoscaler of a raw deployment.
+	// Older KServe only accepts the hpa class and always creates its own HPA, which would conflict with KEDA's.
+	autoscalerClassExternal = "external"
+	// autoscalerClassExternalMinVersion is the first KServe version accepting the external autoscaler class
+	autoscalerClassExternalMinVersion = "0.11.0"
+
+	kedaPrometheusTriggerType = "prometheus"
+)
+
+// ScaledObjectGVR is the group version resource of KEDA's ScaledObject
+var ScaledObjectGVR = schema.GroupVersionResource{
+	Group:    "keda.sh",
+	Version:  "v1alpha1",
+	Resource: "scaledobjects",
+}
+
+// ScaledObjectName returns the name of KEDA's ScaledObject of the given model service
+func ScaledObjectName(modelService *models.Service) string {
+	return kserveconstant.DefaultPredictorServiceName(modelService.Name)
+}
+
+// IsUsingScaledObject returns true if the model service is autoscaled by KEDA's ScaledObject
+func IsUsingScaledObject(modelService *models.Service) bool {
+	return modelService.DeploymentMode == deployment.RawDeploymentMode &&
+		modelService.AutoscalingPolicy != nil &&
+		modelService.AutoscalingPolicy.MetricsType == autoscaling.PrometheusQuery
+}
+
+// ValidateScaledObject returns error if the environment can't autoscale the model service using KEDA's ScaledObject
+func ValidateScaledObject(modelService *models.Service, config *config.DeploymentConfig) error {
+	if !IsUsingScaledObject(modelService) {
+		return nil
+	}
+
+	if config.KedaConfig == nil || config.KedaConfig.PrometheusServerAddress == "" {
+		return fmt.Errorf("%s autoscaling metrics is not supported in this environment", autoscaling.PrometheusQuery)
+	}
+	if !isVersionAtLeast(config.KServeVersion, autoscalerClassExternalMinVersion) {
+		return fmt.Errorf("%s autoscaling metrics requires KServe %s or newer, this environment has KServe %q",
+			autoscaling.PrometheusQuery, autoscalerClassExternalMinVersion, config.KServeVersion)
+	}
+	return nil
+}
+
+// isVersionAtLeast compares the major, minor, and patch of the given version, e.g. v0.11.2, with the minimum version.
+// Unparseable version is considered older.
+func isVersionAtLeast(version, minVersion string) bool {
+	current, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	minimum, _ := parseVersion(minVersion)
+	for i := range current {
+		if current[i] != minimum[i] {
+			return current[i] > minimum[i]
+		}
+	}
+	return true
+}
+
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	// ignore pre-release and build metadata, e.g. 0.11.0-rc1
+	version = strings.SplitN(strings.SplitN(version, "-", 2)[0], "+", 2)[0]
+	parts := strings.Split(version, ".")
+	if version == "" || len(parts) > 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return parsed, false
+		}
+		parsed[i] = number
+	}
+	return parsed, true
+}
+
+// CreateScaledObjectSpec creates KEDA's ScaledObject that scales the predictor deployment of raw deployment
+// based on the prometheus query specified in the autoscaling policy.
+// It returns nil if the model service doesn't use prometheus query autoscaling.
+func (t *InferenceServiceTemplater) CreateScaledObjectSpec(modelService *models.Service, config *config.DeploymentConfig) (*unstructured.Unstructured, error) {
+	if !IsUsingScaledObject(modelService) {
+		return nil, nil
+	}
+
+	if err := ValidateScaledObject(modelService, config); err != nil {
+		return nil, err
+	}
+
+	applyDefaults(modelService, config)
+
+	name := ScaledObjectName(modelService)
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"name":       name,
+		},
+		"minReplicaCount": int64(modelService.ResourceRequest.MinReplica),
+		"maxReplicaCount": int64(modelService.ResourceRequest.MaxReplica),
+		"triggers": []interface{}{
+			map[string]interface{}{
+				"type": kedaPrometheusTriggerType,
+				"metadata": map[string]interface{}{
+					"serverAddress": config.KedaConfig.PrometheusServerAddress,
+					"metricName":    name,
+					"query":         modelService.AutoscalingPolicy.PrometheusQuery,
+					"threshold":     fmt.Sprintf("%g", modelService.AutoscalingPolicy.TargetValue),
+				},
+			},
+		},
+	}
+	if config.KedaConfig.PollingInterval > 0 {
+		spec["pollingInterval"] = int64(config.KedaConfig.PollingInterval)
+	}
+	if config.KedaConfig.CooldownPeriod > 0 {
+		spec["cooldownPeriod"] = int64(config.KedaConfig.CooldownPeriod)
+	}
+
+	scaledObject := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	scaledObject.SetAPIVersion(ScaledObjectGVR.GroupVersion().String())
+	scaledObject.SetKind("ScaledObject")
+	scaledObject.SetName(name)
+	scaledObject.SetNamespace(modelService.Namespace)
+	scaledObject.SetLabels(modelService.Metadata.ToLabel())
+
+	return scaledObject, nil
+}
@@ -0,0 +1,187 @@
+package resource
+
+import (
+	"testing"
+
+	kserveconstant "github.com/kserve/kserve/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/autoscaling"
+	"github.com/caraml-dev/merlin/pkg/deployment"
+)
+
+func TestCreateScaledObjectSpec(t *testing.T) {
+	project := mlp.Project{
+		Name: "project",
+	}
+	model := &models.Model{
+		Name: "model",
+	}
+	version := &models.Version{
+		ID: 1,
+	}
+	modelSvc := func(mode deployment.Mode, policy *autoscaling.AutoscalingPolicy) *models.Service {
+		return &models.Service{
+			Name:         models.CreateInferenceServiceName(model.Name, version.ID.String()),
+			ModelName:    model.Name,
+			ModelVersion: version.ID.String(),
+			Namespace:    project.Name,
+			ResourceRequest: &models.ResourceRequest{
+				MinReplica:    1,
+				MaxReplica:    5,
+				CPURequest:    resource.MustParse("1"),
+				MemoryRequest: resource.MustParse("1Gi"),
+			},
+			DeploymentMode:    mode,
+			AutoscalingPolicy: policy,
+			Metadata: models.Metadata{
+				Team:   "dsp",
+				Stream: "dsp",
+				App:    model.Name,
+			},
+		}
+	}
+	prometheusQueryPolicy := &autoscaling.AutoscalingPolicy{
+		MetricsType:     autoscaling.PrometheusQuery,
+		TargetValue:     100,
+		PrometheusQuery: `sum(kafka_consumergroup_lag{consumergroup="model-1"})`,
+	}
+	kedaConfig := &config.KedaConfig{
+		PrometheusServerAddress: "http://prometheus.monitoring:9090",
+		PollingInterval:         15,
+	}
+
+	tests := []struct {
+		name          string
+		modelService  *models.Service
+		kedaConfig    *config.KedaConfig
+		kserveVersion string
+		wantNil       bool
+		wantErr       bool
+	}{
+		{
+			name:          "raw deployment using prometheus query",
+			modelService:  modelSvc(deployment.RawDeploymentMode, prometheusQueryPolicy),
+			kedaConfig:    kedaConfig,
+			kserveVersion: "v0.11.2",
+		},
+		{
+			name: "raw deployment using cpu",
+			modelService: modelSvc(deployment.RawDeploymentMode, &autoscaling.AutoscalingPolicy{
+				MetricsType: autoscaling.CPUUtilization,
+				TargetValue: 50,
+			}),
+			kedaConfig: kedaConfig,
+			wantNil:    true,
+		},
+		{
+			name:         "serverless",
+			modelService: modelSvc(deployment.ServerlessDeploymentMode, autoscaling.DefaultServerlessAutoscalingPolicy),
+			kedaConfig:   kedaConfig,
+			wantNil:      true,
+		},
+		{
+			name:          "keda is not configured",
+			modelService:  modelSvc(deployment.RawDeploymentMode, prometheusQueryPolicy),
+			kserveVersion: "0.11.0",
+			wantErr:       true,
+		},
+		{
+			name:          "kserve doesn't support external autoscaler class",
+			modelService:  modelSvc(deployment.RawDeploymentMode, prometheusQueryPolicy),
+			kedaConfig:    kedaConfig,
+			kserveVersion: "0.9.0",
+			wantErr:       true,
+		},
+		{
+			name:         "kserve version is not configured",
+			modelService: modelSvc(deployment.RawDeploymentMode, prometheusQueryPolicy),
+			kedaConfig:   kedaConfig,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployConfig := &config.DeploymentConfig{
+				DefaultModelResourceRequests: defaultModelResourceRequests,
+				KedaConfig:                   tt.kedaConfig,
+				KServeVersion:                tt.kserveVersion,
+			}
+
+			tpl := NewInferenceServiceTemplater(standardTransformerConfig)
+			scaledObject, err := tpl.CreateScaledObjectSpec(tt.modelService, deployConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				// KServe must not receive the external autoscaler class it would reject
+				_, err = createAnnotations(tt.modelService, deployConfig)
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.wantNil {
+				assert.Nil(t, scaledObject)
+				return
+			}
+
+			assert.Equal(t, "ScaledObject", scaledObject.GetKind())
+			assert.Equal(t, "model-1-predictor-default", scaledObject.GetName())
+			assert.Equal(t, project.Name, scaledObject.GetNamespace())
+
+			targetName, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "name")
+			assert.Equal(t, "model-1-predictor-default", targetName)
+			minReplica, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "minReplicaCount")
+			assert.Equal(t, int64(1), minReplica)
+			maxReplica, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "maxReplicaCount")
+			assert.Equal(t, int64(5), maxReplica)
+			pollingInterval, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "pollingInterval")
+			assert.Equal(t, int64(15), pollingInterval)
+
+			triggers, _, _ := unstructured.NestedSlice(scaledObject.Object, "spec", "triggers")
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{
+					"type": "prometheus",
+					"metadata": map[string]interface{}{
+						"serverAddress": "http://prometheus.monitoring:9090",
+						"metricName":    "model-1-predictor-default",
+						"query":         `sum(kafka_consumergroup_lag{consumergroup="model-1"})`,
+						"threshold":     "100",
+					},
+				},
+			}, triggers)
+
+			annotations, err := createAnnotations(tt.modelService, deployConfig)
+			assert.NoError(t, err)
+			assert.Equal(t, autoscalerClassExternal, annotations[kserveconstant.AutoscalerClass])
+			assert.NotContains(t, annotations, kserveconstant.AutoscalerMetrics)
+		})
+	}
+}
+
+func TestIsVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"0.11.0", true},
+		{"v0.11.1", true},
+		{"0.12", true},
+		{"1.0.0", true},
+		{"0.11.0-rc1", true},
+		{"0.10.2", false},
+		{"v0.9.0", false},
+		{"0.8", false},
+		{"", false},
+		{"latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, isVersionAtLeast(tt.version, autoscalerClassExternalMinVersion))
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/caraml-dev/merlin/models"
 	"github.com/caraml-dev/merlin/pkg/autoscaling"
 	"github.com/caraml-dev/merlin/pkg/deployment"
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
 	prt "github.com/caraml-dev/merlin/pkg/protocol"
 	transformerpkg "github.com/caraml-dev/merlin/pkg/transformer"
 	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
@@ -278,7 +279,7 @@ func createPredictorSpec(modelService *models.Service, config *config.Deployment
 	var loggerSpec *kservev1beta1.LoggerSpec
 	if modelService.Logger != nil && modelService.Logger.Model != nil && modelService.Logger.Model.Enabled {
 		logger := modelService.Logger
-		loggerSpec = createLoggerSpec(logger.DestinationURL, *logger.Model)
+		loggerSpec = createLoggerSpec(logger.DestinationURL, *logger.Model, modelService.Protocol)
 	}
 
 	predictorSpec.MinReplicas = &(modelService.ResourceRequest.MinReplica)
@@ -310,7 +311,7 @@ func (t *InferenceServiceTemplater) createTransformerSpec(modelService *models.S
 	var loggerSpec *kservev1beta1.LoggerSpec
 	if modelService.Logger != nil && modelService.Logger.Transformer != nil && modelService.Logger.Transformer.Enabled {
 		logger := modelService.Logger
-		loggerSpec = createLoggerSpec(logger.DestinationURL, *logger.Transformer)
+		loggerSpec = createLoggerSpec(logger.DestinationURL, *logger.Transformer, modelService.Protocol)
 	}
 
 	var transformerCommand []string
@@ -419,6 +420,9 @@ func (t *InferenceServiceTemplater) enrichStandardTransformerEnvVars(modelServic
 		addEnvVar = append(addEnvVar, models.EnvVar{Name: transformerpkg.KafkaMaxMessageSizeBytes, Value: fmt.Sprintf("%v", kafkaCfg.MaxMessageSizeBytes)})
 		addEnvVar = append(addEnvVar, models.EnvVar{Name: transformerpkg.KafkaConnectTimeoutMS, Value: fmt.Sprintf("%v", kafkaCfg.ConnectTimeoutMS)})
 		addEnvVar = append(addEnvVar, models.EnvVar{Name: transformerpkg.KafkaSerialization, Value: string(kafkaCfg.SerializationFmt)})
+		if kafkaCfg.SchemaRegistryURL != "" {
+			addEnvVar = append(addEnvVar, models.EnvVar{Name: transformerpkg.KafkaSchemaRegistryURL, Value: kafkaCfg.SchemaRegistryURL})
+		}
 	}
 
 	jaegerCfg := t.standardTransformerConfig.Jaeger
@@ -502,7 +506,14 @@ func createAnnotations(modelService *models.Service, config *config.DeploymentCo
 	annotations[kserveconstant.DeploymentMode] = deployMode
 
 	if modelService.AutoscalingPolicy != nil {
-		if modelService.DeploymentMode == deployment.RawDeploymentMode {
+		if modelService.DeploymentMode == deployment.RawDeploymentMode &&
+			modelService.AutoscalingPolicy.MetricsType == autoscaling.PrometheusQuery {
+			// scaling is handled by KEDA's ScaledObject, prevent KServe from creating its own HPA
+			if err := ValidateScaledObject(modelService, config); err != nil {
+				return nil, err
+			}
+			annotations[kserveconstant.AutoscalerClass] = autoscalerClassExternal
+		} else if modelService.DeploymentMode == deployment.RawDeploymentMode {
 			annotations[kserveconstant.AutoscalerClass] = string(kserveconstant.AutoscalerClassHPA)
 			autoscalingMetrics, err := toKServeAutoscalerMetrics(modelService.AutoscalingPolicy.MetricsType)
 			if err != nil {
@@ -542,8 +553,18 @@ func createContainerPorts(protocolValue protocol.Protocol) []corev1.ContainerPor
 	return containerPorts
 }
 
-func createLoggerSpec(loggerURL string, loggerConfig models.LoggerConfig) *kservev1beta1.LoggerSpec {
+func createLoggerSpec(loggerURL string, loggerConfig models.LoggerConfig, protocolValue protocol.Protocol) *kservev1beta1.LoggerSpec {
 	loggerMode := models.ToKFServingLoggerMode(loggerConfig.Mode)
+
+	// The inference logger reads its options and log policy from the logger URL since it's the only configuration passed by KServe
+	if protocolValue == protocol.UpiV1 {
+		if optionsURL, err := logpolicy.SetURLOption(loggerURL, logpolicy.OptionProtocol, string(protocolValue)); err == nil {
+			loggerURL = optionsURL
+		}
+	}
+	if policyURL, err := logpolicy.AppendToURL(loggerURL, loggerConfig.LogPolicy()); err == nil {
+		loggerURL = policyURL
+	}
 	return &kservev1beta1.LoggerSpec{
 		URL:  &loggerURL,
 		Mode: loggerMode,
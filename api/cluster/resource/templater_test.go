@@ -21,6 +21,7 @@ import (
 
 	"github.com/caraml-dev/merlin/pkg/autoscaling"
 	"github.com/caraml-dev/merlin/pkg/deployment"
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
 	"github.com/caraml-dev/merlin/pkg/protocol"
 	transformerpkg "github.com/caraml-dev/merlin/pkg/transformer"
 
@@ -2266,6 +2267,8 @@ func TestCreateInferenceServiceSpecWithLogger(t *testing.T) {
 	}
 
 	loggerDestinationURL := "http://destination.default"
+	loggerPolicyURL := loggerDestinationURL + logpolicy.URLFragmentPrefix +
+		"eyJzYW1wbGluZyI6eyJzdGF0dXNfY29kZV9ydWxlcyI6W3sic3RhdHVzX2NvZGUiOiI1eHgiLCJyYXRlIjoxfV19LCJyZWRhY3Rpb25zIjpbeyJwYXRoIjoiJC5jdXN0b21lci5waG9uZSIsImFjdGlvbiI6Imhhc2gifV0sIm1heF9wYXlsb2FkX3NpemUiOjEwMjR9"
 	modelSvc := &models.Service{
 		Name:         "model-1",
 		ModelName:    "model",
@@ -2365,6 +2368,72 @@ func TestCreateInferenceServiceSpecWithLogger(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "model logger enabled with log policy",
+			modelSvc: &models.Service{
+				Name:         modelSvc.Name,
+				ModelName:    modelSvc.ModelName,
+				ModelVersion: modelSvc.ModelVersion,
+				Namespace:    project.Name,
+				ArtifactURI:  modelSvc.ArtifactURI,
+				Type:         models.ModelTypeTensorflow,
+				Options:      &models.ModelOption{},
+				Metadata:     modelSvc.Metadata,
+				Logger: &models.Logger{
+					DestinationURL: loggerDestinationURL,
+					Model: &models.LoggerConfig{
+						Enabled:        true,
+						Mode:           models.LogAll,
+						Sampling:       &logpolicy.Sampling{StatusCodeRules: []logpolicy.StatusCodeRule{{StatusCode: "5xx", Rate: 1}}},
+						Redactions:     []logpolicy.Redaction{{Path: "$.customer.phone", Action: logpolicy.RedactionHash}},
+						MaxPayloadSize: 1024,
+					},
+				},
+				Protocol: protocol.HttpJson,
+			},
+			exp: &kservev1beta1.InferenceService{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      modelSvc.Name,
+					Namespace: project.Name,
+					Annotations: map[string]string{
+						knserving.QueueSidecarResourcePercentageAnnotationKey: queueResourcePercentage,
+						kserveconstant.DeploymentMode:                         string(kserveconstant.Serverless),
+					},
+					Labels: map[string]string{
+						"gojek.com/app":          modelSvc.Metadata.App,
+						"gojek.com/component":    models.ComponentModelVersion,
+						"gojek.com/environment":  testEnvironmentName,
+						"gojek.com/orchestrator": testOrchestratorName,
+						"gojek.com/stream":       modelSvc.Metadata.Stream,
+						"gojek.com/team":         modelSvc.Metadata.Team,
+						"sample":                 "true",
+					},
+				},
+				Spec: kservev1beta1.InferenceServiceSpec{
+					Predictor: kservev1beta1.PredictorSpec{
+						Tensorflow: &kservev1beta1.TFServingSpec{
+							PredictorExtensionSpec: kservev1beta1.PredictorExtensionSpec{
+								StorageURI: &storageUri,
+								Container: corev1.Container{
+									Name:          kserveconstant.InferenceServiceContainerName,
+									Resources:     expDefaultModelResourceRequests,
+									LivenessProbe: probeConfig,
+									Env:           []corev1.EnvVar{},
+								},
+							},
+						},
+						ComponentExtensionSpec: kservev1beta1.ComponentExtensionSpec{
+							MinReplicas: &defaultModelResourceRequests.MinReplica,
+							MaxReplicas: defaultModelResourceRequests.MaxReplica,
+							Logger: &kservev1beta1.LoggerSpec{
+								URL:  &loggerPolicyURL,
+								Mode: kservev1beta1.LogAll,
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "model logger enabled with transformer",
 			modelSvc: &models.Service{
@@ -3644,3 +3713,19 @@ func createPyFuncDefaultEnvVarsWithProtocol(svc *models.Service, protocolValue p
 	}
 	return envVars
 }
+
+func TestCreateLoggerSpec(t *testing.T) {
+	loggerURL := "kafka:broker:9092"
+	loggerConfig := models.LoggerConfig{Enabled: true, Mode: models.LogAll, MaxPayloadSize: 1024}
+
+	// the inference logger of UPI components reads their protocol from the logger URL, before the log policy
+	spec := createLoggerSpec(loggerURL, loggerConfig, protocol.UpiV1)
+	withoutPolicy, policy, err := logpolicy.ParseURL(*spec.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, policy.MaxPayloadSize)
+	assert.Equal(t, loggerURL+logpolicy.URLOptionsPrefix+"protocol=UPI_V1", withoutPolicy)
+	assert.Equal(t, kservev1beta1.LogAll, spec.Mode)
+
+	spec = createLoggerSpec(loggerURL, models.LoggerConfig{Enabled: true, Mode: models.LogAll}, protocol.HttpJson)
+	assert.Equal(t, loggerURL, *spec.URL)
+}
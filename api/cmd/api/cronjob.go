@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jinzhu/gorm"
 
+	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/pkg/cronjob"
 	"github.com/caraml-dev/merlin/storage"
 )
@@ -21,6 +23,12 @@ func initCronJob(dependencies deps, db *gorm.DB) error {
 
 	imageBuilderJanitor := dependencies.imageBuilderJanitor
 
+	scheduler := cronjob.NewPredictionJobScheduler(
+		dependencies.apiContext.PredictionJobScheduleService,
+		dependencies.apiContext.ModelsService,
+		dependencies.apiContext.VersionsService,
+		dependencies.apiContext.EnvironmentService)
+
 	c, err := cronjob.New()
 	if err != nil {
 		return err
@@ -34,6 +42,68 @@ func initCronJob(dependencies deps, db *gorm.DB) error {
 	if err != nil {
 		return err
 	}
+	err = c.AddFunc("@every 1m", scheduler.RunDueSchedules)
+	if err != nil {
+		return err
+	}
+
+	if groundTruthService := dependencies.apiContext.GroundTruthService; groundTruthService != nil {
+		err = c.AddFunc("@every 1m", func() {
+			if err := groundTruthService.ExportMetrics(context.Background()); err != nil {
+				log.Errorf("failed exporting online performance metrics: %v", err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		err = c.AddFunc("@hourly", func() {
+			if err := groundTruthService.DeleteExpired(context.Background()); err != nil {
+				log.Errorf("failed deleting expired predictions and labels: %v", err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if driftService := dependencies.apiContext.DriftService; driftService != nil {
+		err = c.AddFunc("@every 1m", func() {
+			if err := driftService.Flush(context.Background()); err != nil {
+				log.Errorf("failed flushing drift counts: %v", err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		err = c.AddFunc(fmt.Sprintf("@every %s", dependencies.apiContext.DriftConfig.Interval), func() {
+			if err := driftService.ComputeDrift(context.Background()); err != nil {
+				log.Errorf("failed computing drift: %v", err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		err = c.AddFunc("@hourly", func() {
+			if err := driftService.DeleteExpired(context.Background()); err != nil {
+				log.Errorf("failed deleting expired drift counts and results: %v", err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if dependencies.wardenSyncInterval > 0 {
+		teamService := dependencies.apiContext.TeamService
+		err = c.AddFunc(fmt.Sprintf("@every %s", dependencies.wardenSyncInterval), func() {
+			if err := teamService.SyncWardenTeams(context.Background()); err != nil {
+				log.Errorf("failed syncing teams from warden: %v", err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
 
 	c.Start()
 
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/pkg/kafka"
+	"github.com/caraml-dev/merlin/pkg/kafka/schemaregistry"
+	"github.com/caraml-dev/merlin/service"
+)
+
+// runDriftConsumer consumes the protobuf prediction logs of the model versions until the context is done
+func runDriftConsumer(ctx context.Context, cfg config.DriftConfig, driftService service.DriftService) error {
+	consumer, err := kafka.NewConsumer(cfg.KafkaBrokers, cfg.KafkaConsumerGroup, log.GetLogger())
+	if err != nil {
+		return fmt.Errorf("unable to create drift consumer: %w", err)
+	}
+
+	err = consumer.Run(ctx, []string{predictionLogTopics}, func(topic string, value []byte) error {
+		payload, err := schemaregistry.ProtobufPayload(value)
+		if err != nil {
+			return err
+		}
+
+		predictionLog, err := parsePredictionLog(payload)
+		if err != nil {
+			return err
+		}
+		return driftService.ObservePredictionLog(ctx, predictionLog)
+	})
+
+	// the counts observed since the last flush are stored before the consumer stops
+	if flushErr := driftService.Flush(context.Background()); flushErr != nil {
+		log.Errorf("failed flushing drift counts: %v", flushErr)
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/pkg/kafka"
+	"github.com/caraml-dev/merlin/pkg/kafka/schemaregistry"
+	"github.com/caraml-dev/merlin/service"
+)
+
+// predictionLogTopics matches the topics the model versions publish their prediction logs to, see models.Service.GetPredictionLogTopic
+const predictionLogTopics = "^caraml-.*-prediction-log$"
+
+// runGroundTruthConsumer consumes the protobuf prediction logs of the model versions and the observation logs of the label topic
+// until the context is done
+func runGroundTruthConsumer(ctx context.Context, cfg config.GroundTruthConfig, groundTruthService service.GroundTruthService) error {
+	consumer, err := kafka.NewConsumer(cfg.KafkaBrokers, cfg.KafkaConsumerGroup, log.GetLogger())
+	if err != nil {
+		return fmt.Errorf("unable to create ground truth consumer: %w", err)
+	}
+
+	topics := []string{predictionLogTopics}
+	if cfg.LabelTopic != "" {
+		topics = append(topics, cfg.LabelTopic)
+	}
+	return consumer.Run(ctx, topics, func(topic string, value []byte) error {
+		payload, err := schemaregistry.ProtobufPayload(value)
+		if err != nil {
+			return err
+		}
+
+		if topic == cfg.LabelTopic {
+			observationLog := &upiv1.ObservationLog{}
+			if err := proto.Unmarshal(payload, observationLog); err != nil {
+				return fmt.Errorf("unable to parse observation log: %w", err)
+			}
+			return groundTruthService.SaveObservationLog(ctx, observationLog)
+		}
+
+		predictionLog, err := parsePredictionLog(payload)
+		if err != nil {
+			return err
+		}
+		return groundTruthService.SavePredictionLog(ctx, predictionLog)
+	})
+}
+
+// validateGroundTruthConfig rejects the ground truth config without a positive window to compute the online performance over
+func validateGroundTruthConfig(cfg config.GroundTruthConfig) error {
+	if len(cfg.Windows) == 0 {
+		return fmt.Errorf("ground truth requires at least one window")
+	}
+	for _, window := range cfg.Windows {
+		if window <= 0 {
+			return fmt.Errorf("ground truth window %s must be positive", window)
+		}
+	}
+	return nil
+}
+
+func parsePredictionLog(payload []byte) (*upiv1.PredictionLog, error) {
+	predictionLog := &upiv1.PredictionLog{}
+	if err := proto.Unmarshal(payload, predictionLog); err != nil {
+		return nil, fmt.Errorf("unable to parse prediction log: %w", err)
+	}
+	return predictionLog, nil
+}
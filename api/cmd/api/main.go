@@ -24,6 +24,8 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	// embed the timezone database for prediction job schedules, the runtime image doesn't ship one
+	_ "time/tzdata"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/gorilla/mux"
@@ -42,7 +44,8 @@ import (
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/mlflow"
 	"github.com/caraml-dev/merlin/models"
-	"github.com/caraml-dev/merlin/pkg/gitlab"
+	"github.com/caraml-dev/merlin/pkg/prometheus"
+	"github.com/caraml-dev/merlin/pkg/webhook"
 	"github.com/caraml-dev/merlin/queue"
 	"github.com/caraml-dev/merlin/queue/work"
 	"github.com/caraml-dev/merlin/service"
@@ -62,6 +65,11 @@ func main() {
 	if err != nil {
 		log.Panicf("Failed initializing config: %v", err)
 	}
+	if cfg.FeatureToggleConfig.GroundTruthConfig.GroundTruthEnabled {
+		if err := validateGroundTruthConfig(cfg.FeatureToggleConfig.GroundTruthConfig); err != nil {
+			log.Panicf("Invalid ground truth config: %v", err)
+		}
+	}
 
 	// Initializing Sentry client
 	cfg.Sentry.Labels = map[string]string{"environment": cfg.Environment}
@@ -89,13 +97,31 @@ func main() {
 
 	dependencies := buildDependencies(ctx, cfg, db, dispatcher)
 
-	registerQueueJob(dispatcher, dependencies.modelDeployment, dependencies.batchDeployment)
+	registerQueueJob(dispatcher, dependencies.modelDeployment, dependencies.batchDeployment, dependencies.graphDeployment)
 	dispatcher.Start()
 
 	if err := initCronJob(dependencies, db); err != nil {
 		log.Panicf("Failed to initialize cron jobs: %s", err)
 	}
 
+	groundTruthConfig := cfg.FeatureToggleConfig.GroundTruthConfig
+	if groundTruthConfig.GroundTruthEnabled && groundTruthConfig.KafkaBrokers != "" {
+		go func() {
+			if err := runGroundTruthConsumer(ctx, groundTruthConfig, dependencies.apiContext.GroundTruthService); err != nil {
+				log.Errorf("Ground truth consumer stopped: %s", err)
+			}
+		}()
+	}
+
+	driftConfig := cfg.FeatureToggleConfig.DriftConfig
+	if driftConfig.DriftEnabled && driftConfig.KafkaBrokers != "" {
+		go func() {
+			if err := runDriftConsumer(ctx, driftConfig, dependencies.apiContext.DriftService); err != nil {
+				log.Errorf("Drift consumer stopped: %s", err)
+			}
+		}()
+	}
+
 	router := mux.NewRouter()
 
 	apiRouter, err := api.NewRouter(dependencies.apiContext)
@@ -155,6 +181,12 @@ func main() {
 	if err := srv.Shutdown(context.Background()); err != nil {
 		log.Errorf("failed to shutdown HTTP server")
 	}
+
+	webhookCtx, cancel := context.WithTimeout(context.Background(), cfg.WebhookConfig.ShutdownTimeout)
+	defer cancel()
+	if err := dependencies.webhookNotifier.Shutdown(webhookCtx); err != nil {
+		log.Errorf("failed to shutdown webhook notifier: %v", err)
+	}
 }
 
 func setupSignalHandler() (stopCh <-chan struct{}) {
@@ -198,9 +230,10 @@ func newPprofRouter() *mux.Router {
 	return r
 }
 
-func registerQueueJob(consumer queue.Consumer, modelServiceDepl *work.ModelServiceDeployment, batchDepl *work.BatchDeployment) {
+func registerQueueJob(consumer queue.Consumer, modelServiceDepl *work.ModelServiceDeployment, batchDepl *work.BatchDeployment, graphDepl *work.InferenceGraphDeployment) {
 	consumer.RegisterJob(service.ModelServiceDeployment, modelServiceDepl.Deploy)
 	consumer.RegisterJob(service.BatchDeployment, batchDepl.Deploy)
+	consumer.RegisterJob(service.InferenceGraphDeployment, graphDepl.Deploy)
 }
 
 func buildDependencies(ctx context.Context, cfg *config.Config, db *gorm.DB, dispatcher *queue.Dispatcher) deps {
@@ -214,13 +247,17 @@ func buildDependencies(ctx context.Context, cfg *config.Config, db *gorm.DB, dis
 	webServiceBuilder, predJobBuilder, imageBuilderJanitor := initImageBuilder(cfg)
 
 	clusterControllers := initClusterControllers(cfg)
-	modelServiceDeployment := initModelServiceDeployment(cfg, webServiceBuilder, clusterControllers, db)
-	versionEndpointService := initVersionEndpointService(cfg, webServiceBuilder, clusterControllers, db, coreClient, dispatcher)
-	modelEndpointService := initModelEndpointService(cfg, db)
-
-	batchControllers := initBatchControllers(cfg, db, mlpAPIClient)
+	webhookNotifier := webhook.NewNotifier(storage.NewWebhookStorage(db), cfg.WebhookConfig)
+	modelServiceDeployment := initModelServiceDeployment(cfg, webServiceBuilder, clusterControllers, db, webhookNotifier)
+	projectQuotaService := initProjectQuotaService(db)
+	versionEndpointService := initVersionEndpointService(cfg, webServiceBuilder, clusterControllers, db, coreClient, dispatcher, projectQuotaService)
+	modelEndpointService := initModelEndpointService(cfg, db, webhookNotifier)
+	inferenceGraphDeployment := initInferenceGraphDeployment(cfg, clusterControllers, db)
+
+	batchControllers := initBatchControllers(cfg, db, mlpAPIClient, webhookNotifier)
 	batchDeployment := initBatchDeployment(cfg, db, batchControllers, predJobBuilder)
-	predictionJobService := initPredictionJobService(cfg, batchControllers, predJobBuilder, db, dispatcher)
+	predictionJobService := initPredictionJobService(cfg, batchControllers, predJobBuilder, db, dispatcher, projectQuotaService)
+	predictionJobScheduleService := initPredictionJobScheduleService(db, predictionJobService)
 	logService := initLogService(cfg)
 	// use "mlp" as product name for enforcer so that same policy can be reused by excalibur
 	authEnforcer, err := enforcer.NewEnforcerBuilder().
@@ -236,44 +273,83 @@ func buildDependencies(ctx context.Context, cfg *config.Config, db *gorm.DB, dis
 	versionsService := service.NewVersionsService(db, mlpAPIClient)
 	environmentService := initEnvironmentService(cfg, db)
 	secretService := service.NewSecretService(mlpAPIClient)
+	webhookService := service.NewWebhookService(storage.NewWebhookStorage(db))
 
-	gitlabConfig := cfg.FeatureToggleConfig.AlertConfig.GitlabConfig
-	gitlabClient, err := gitlab.NewClient(gitlabConfig.BaseURL, gitlabConfig.Token)
-	if err != nil {
-		log.Panicf("unable to initialize GitLab client: %v", err)
-	}
-
+	var wardenClient warden.Client
 	wardenConfig := cfg.FeatureToggleConfig.AlertConfig.WardenConfig
-	wardenClient := warden.NewClient(nil, wardenConfig.APIHost)
+	if wardenConfig.APIHost != "" {
+		wardenClient = warden.NewClient(nil, wardenConfig.APIHost)
+	}
+	teamService := service.NewTeamService(storage.NewTeamStorage(db), wardenClient)
 
 	modelEndpointAlertService := service.NewModelEndpointAlertService(
-		storage.NewAlertStorage(db), gitlabClient, wardenClient,
-		gitlabConfig.DashboardRepository, gitlabConfig.DashboardBranch,
-		gitlabConfig.AlertRepository, gitlabConfig.AlertBranch,
+		storage.NewAlertStorage(db), teamService, initAlertDeliverers(cfg, clusterControllers), wardenClient,
 		cfg.FeatureToggleConfig.MonitoringConfig.MonitoringBaseURL)
+	inferenceGraphService := initInferenceGraphService(clusterControllers, db, dispatcher, modelEndpointAlertService)
 
 	mlflowConfig := cfg.MlflowConfig
 	mlflowClient := mlflow.NewClient(mlflowConfig.TrackingURL)
 	transformerService := service.NewTransformerService(cfg.StandardTransformerConfig)
+
+	var resourceRecommendationService service.ResourceRecommendationService
+	resourceRecommendationConfig := cfg.FeatureToggleConfig.ResourceRecommendationConfig
+	if resourceRecommendationConfig.ResourceRecommendationEnabled {
+		prometheusClient, err := prometheus.NewClient(resourceRecommendationConfig.PrometheusURL)
+		if err != nil {
+			log.Panicf("unable to initialize Prometheus client: %v", err)
+		}
+		resourceRecommendationService = service.NewResourceRecommendationService(prometheusClient, resourceRecommendationConfig)
+	}
+
+	var groundTruthService service.GroundTruthService
+	groundTruthConfig := cfg.FeatureToggleConfig.GroundTruthConfig
+	if groundTruthConfig.GroundTruthEnabled {
+		groundTruthService = service.NewGroundTruthService(storage.NewGroundTruthStorage(db), groundTruthConfig)
+	}
+
+	var driftService service.DriftService
+	driftConfig := cfg.FeatureToggleConfig.DriftConfig
+	if driftConfig.DriftEnabled {
+		driftService = service.NewDriftService(storage.NewDriftStorage(db), driftConfig)
+	}
+
 	apiContext := api.AppContext{
 		DB:       db,
 		Enforcer: authEnforcer,
 
-		EnvironmentService:        environmentService,
-		ProjectsService:           projectsService,
-		ModelsService:             modelsService,
-		ModelEndpointsService:     modelEndpointService,
-		VersionsService:           versionsService,
-		EndpointsService:          versionEndpointService,
-		LogService:                logService,
-		PredictionJobService:      predictionJobService,
-		SecretService:             secretService,
-		ModelEndpointAlertService: modelEndpointAlertService,
-		TransformerService:        transformerService,
+		EnvironmentService:           environmentService,
+		ProjectsService:              projectsService,
+		ModelsService:                modelsService,
+		ModelEndpointsService:        modelEndpointService,
+		VersionsService:              versionsService,
+		EndpointsService:             versionEndpointService,
+		LogService:                   logService,
+		PredictionJobService:         predictionJobService,
+		PredictionJobScheduleService: predictionJobScheduleService,
+		SecretService:                secretService,
+		ModelEndpointAlertService:    modelEndpointAlertService,
+		TransformerService:           transformerService,
+		ProjectQuotaService:          projectQuotaService,
+		InferenceGraphService:        inferenceGraphService,
+		WebhookService:               webhookService,
+		TeamService:                  teamService,
+
+		ImageBuilder:              webServiceBuilder,
+		PredictionJobImageBuilder: predJobBuilder,
+
+		ResourceRecommendationService: resourceRecommendationService,
+		GroundTruthService:            groundTruthService,
+		DriftService:                  driftService,
 
 		AuthorizationEnabled: cfg.AuthorizationConfig.AuthorizationEnabled,
 		AlertEnabled:         cfg.FeatureToggleConfig.AlertConfig.AlertEnabled,
 		MonitoringConfig:     cfg.FeatureToggleConfig.MonitoringConfig,
+		ProjectQuotaConfig:   cfg.ProjectQuotaConfig,
+		TeamRegistryConfig:   cfg.TeamRegistryConfig,
+
+		ResourceRecommendationEnabled: resourceRecommendationConfig.ResourceRecommendationEnabled,
+		GroundTruthConfig:             groundTruthConfig,
+		DriftConfig:                   driftConfig,
 
 		StandardTransformerConfig: cfg.StandardTransformerConfig,
 
@@ -284,6 +360,9 @@ func buildDependencies(ctx context.Context, cfg *config.Config, db *gorm.DB, dis
 		apiContext:          apiContext,
 		modelDeployment:     modelServiceDeployment,
 		batchDeployment:     batchDeployment,
+		graphDeployment:     inferenceGraphDeployment,
 		imageBuilderJanitor: imageBuilderJanitor,
+		webhookNotifier:     webhookNotifier,
+		wardenSyncInterval:  wardenConfig.SyncInterval,
 	}
 }
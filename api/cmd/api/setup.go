@@ -27,7 +27,9 @@ import (
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/gitlab"
 	"github.com/caraml-dev/merlin/pkg/imagebuilder"
+	"github.com/caraml-dev/merlin/pkg/webhook"
 	"github.com/caraml-dev/merlin/queue"
 	"github.com/caraml-dev/merlin/queue/work"
 	"github.com/caraml-dev/merlin/service"
@@ -39,7 +41,11 @@ type deps struct {
 	apiContext          api.AppContext
 	modelDeployment     *work.ModelServiceDeployment
 	batchDeployment     *work.BatchDeployment
+	graphDeployment     *work.InferenceGraphDeployment
 	imageBuilderJanitor *imagebuilder.Janitor
+	webhookNotifier     webhook.Notifier
+	// wardenSyncInterval of syncing Warden's teams into the team registry, 0 if disabled
+	wardenSyncInterval time.Duration
 }
 
 func initDB(cfg config.DatabaseConfig) (*gorm.DB, func()) {
@@ -132,6 +138,24 @@ func initImageBuilder(cfg *config.Config) (webserviceBuilder imagebuilder.ImageB
 		log.Panicf("unable to parse image builder timeout to time.Duration %s", cfg.ImageBuilderConfig.BuildTimeout)
 	}
 
+	webServiceEnvConfigs := make(map[string]imagebuilder.EnvironmentConfig)
+	predJobEnvConfigs := make(map[string]imagebuilder.EnvironmentConfig)
+	for _, env := range cfg.EnvironmentConfigs {
+		if env.ImageBuilderConfig == nil {
+			continue
+		}
+		envConfig := imagebuilder.EnvironmentConfig{
+			KanikoCacheDisabled: env.ImageBuilderConfig.KanikoCacheDisabled,
+			KanikoCacheRepo:     env.ImageBuilderConfig.KanikoCacheRepo,
+			KanikoCacheTTL:      env.ImageBuilderConfig.KanikoCacheTTL,
+		}
+
+		envConfig.AllowedBaseImages = env.ImageBuilderConfig.AllowedBaseImages
+		webServiceEnvConfigs[env.Name] = envConfig
+		envConfig.AllowedBaseImages = env.ImageBuilderConfig.PredictionJobAllowedBaseImages
+		predJobEnvConfigs[env.Name] = envConfig
+	}
+
 	webServiceConfig := imagebuilder.Config{
 		BaseImages:           cfg.ImageBuilderConfig.BaseImages,
 		BuildNamespace:       cfg.ImageBuilderConfig.BuildNamespace,
@@ -146,6 +170,9 @@ func initImageBuilder(cfg *config.Config) (webserviceBuilder imagebuilder.ImageB
 		MaximumRetry:         cfg.ImageBuilderConfig.MaximumRetry,
 		SafeToEvict:          cfg.ImageBuilderConfig.SafeToEvict,
 
+		EnvironmentConfigs:       webServiceEnvConfigs,
+		ReuseImageByArtifactHash: cfg.ImageBuilderConfig.ReuseImageByArtifactHash,
+
 		ClusterName: cfg.ImageBuilderConfig.ClusterName,
 		GcpProject:  cfg.ImageBuilderConfig.GcpProject,
 
@@ -167,6 +194,8 @@ func initImageBuilder(cfg *config.Config) (webserviceBuilder imagebuilder.ImageB
 		MaximumRetry:         cfg.ImageBuilderConfig.MaximumRetry,
 		SafeToEvict:          cfg.ImageBuilderConfig.SafeToEvict,
 
+		EnvironmentConfigs: predJobEnvConfigs,
+
 		ClusterName: cfg.ImageBuilderConfig.ClusterName,
 		GcpProject:  cfg.ImageBuilderConfig.GcpProject,
 
@@ -223,6 +252,24 @@ func initEnvironmentService(cfg *config.Config, db *gorm.DB) service.Environment
 
 		deploymentCfg := config.ParseDeploymentConfig(envCfg, cfg.PyfuncGRPCOptions)
 
+		var unitCost *models.UnitCost
+		if envCfg.UnitCost != nil {
+			unitCost = &models.UnitCost{
+				CPUMonthlyCost:    envCfg.UnitCost.CPUMonthlyCost,
+				MemoryMonthlyCost: envCfg.UnitCost.MemoryMonthlyCost,
+				GPUMonthlyCost:    envCfg.UnitCost.GPUMonthlyCost,
+			}
+		}
+
+		var lightweightPredictionJobConfig *models.LightweightPredictionJobConfig
+		if envCfg.LightweightPredictionJobConfig != nil {
+			lightweightPredictionJobConfig = &models.LightweightPredictionJobConfig{
+				Enabled:        envCfg.LightweightPredictionJobConfig.Enabled,
+				MaxConcurrency: envCfg.LightweightPredictionJobConfig.MaxConcurrency,
+				MaxBatchSize:   envCfg.LightweightPredictionJobConfig.MaxBatchSize,
+			}
+		}
+
 		env, err := envSvc.GetEnvironment(envCfg.Name)
 		if err != nil {
 			if !gorm.IsRecordNotFoundError(err) {
@@ -252,6 +299,9 @@ func initEnvironmentService(cfg *config.Config, db *gorm.DB) service.Environment
 				},
 				IsDefaultPredictionJob: isDefaultPredictionJob,
 				IsPredictionJobEnabled: envCfg.IsPredictionJobEnabled,
+				UnitCost:               unitCost,
+
+				LightweightPredictionJobConfig: lightweightPredictionJobConfig,
 			}
 
 			if envCfg.IsPredictionJobEnabled {
@@ -288,6 +338,8 @@ func initEnvironmentService(cfg *config.Config, db *gorm.DB) service.Environment
 			}
 			env.IsDefaultPredictionJob = isDefaultPredictionJob
 			env.IsPredictionJobEnabled = envCfg.IsPredictionJobEnabled
+			env.UnitCost = unitCost
+			env.LightweightPredictionJobConfig = lightweightPredictionJobConfig
 
 			if envCfg.IsPredictionJobEnabled {
 				env.DefaultPredictionJobResourceRequest = &models.PredictionJobResourceRequest{
@@ -350,7 +402,7 @@ func initEnvironmentService(cfg *config.Config, db *gorm.DB) service.Environment
 	return svc
 }
 
-func initModelEndpointService(cfg *config.Config, db *gorm.DB) service.ModelEndpointsService {
+func initModelEndpointService(cfg *config.Config, db *gorm.DB, notifier webhook.Notifier) service.ModelEndpointsService {
 	istioClients := make(map[string]istio.Client)
 	for _, env := range cfg.EnvironmentConfigs {
 		creds := mlpcluster.NewK8sClusterCreds(env.K8sConfig)
@@ -366,7 +418,7 @@ func initModelEndpointService(cfg *config.Config, db *gorm.DB) service.ModelEndp
 		istioClients[env.Name] = istioClient
 	}
 
-	return service.NewModelEndpointsService(istioClients, storage.NewModelEndpointStorage(db), storage.NewVersionEndpointStorage(db), cfg.Environment)
+	return service.NewModelEndpointsService(istioClients, storage.NewModelEndpointStorage(db), storage.NewVersionEndpointStorage(db), cfg.Environment, notifier)
 }
 
 func initBatchDeployment(cfg *config.Config, db *gorm.DB, controllers map[string]batch.Controller, builder imagebuilder.ImageBuilder) *work.BatchDeployment {
@@ -379,7 +431,7 @@ func initBatchDeployment(cfg *config.Config, db *gorm.DB, controllers map[string
 	}
 }
 
-func initBatchControllers(cfg *config.Config, db *gorm.DB, mlpAPIClient mlp.APIClient) map[string]batch.Controller {
+func initBatchControllers(cfg *config.Config, db *gorm.DB, mlpAPIClient mlp.APIClient, notifier webhook.Notifier) map[string]batch.Controller {
 	controllers := make(map[string]batch.Controller)
 	predictionJobStorage := storage.NewPredictionJobStorage(db)
 	for _, env := range cfg.EnvironmentConfigs {
@@ -406,7 +458,7 @@ func initBatchControllers(cfg *config.Config, db *gorm.DB, mlpAPIClient mlp.APIC
 			GcpProject:  env.GcpProject,
 		}
 
-		ctl := batch.NewController(predictionJobStorage, mlpAPIClient, sparkClient, kubeClient, manifestManager, envMetadata)
+		ctl := batch.NewController(predictionJobStorage, mlpAPIClient, sparkClient, kubeClient, manifestManager, env.Name, envMetadata, notifier)
 		stopCh := make(chan struct{})
 		go ctl.Run(stopCh)
 
@@ -415,18 +467,42 @@ func initBatchControllers(cfg *config.Config, db *gorm.DB, mlpAPIClient mlp.APIC
 	return controllers
 }
 
-func initPredictionJobService(cfg *config.Config, controllers map[string]batch.Controller, builder imagebuilder.ImageBuilder, db *gorm.DB, producer queue.Producer) service.PredictionJobService {
+func initPredictionJobService(cfg *config.Config, controllers map[string]batch.Controller, builder imagebuilder.ImageBuilder, db *gorm.DB, producer queue.Producer, projectQuotaService service.ProjectQuotaService) service.PredictionJobService {
 	predictionJobStorage := storage.NewPredictionJobStorage(db)
-	return service.NewPredictionJobService(controllers, builder, predictionJobStorage, clock.RealClock{}, cfg.Environment, producer)
+	versionEndpointStorage := storage.NewVersionEndpointStorage(db)
+	return service.NewPredictionJobService(controllers, builder, predictionJobStorage, clock.RealClock{}, cfg.Environment, producer, projectQuotaService, versionEndpointStorage)
+}
+
+func initPredictionJobScheduleService(db *gorm.DB, predictionJobService service.PredictionJobService) service.PredictionJobScheduleService {
+	return service.NewPredictionJobScheduleService(storage.NewPredictionJobScheduleStorage(db), storage.NewPredictionJobStorage(db), predictionJobService, clock.RealClock{})
+}
+
+func initProjectQuotaService(db *gorm.DB) service.ProjectQuotaService {
+	return service.NewProjectQuotaService(storage.NewProjectQuotaStorage(db), storage.NewVersionEndpointStorage(db), storage.NewPredictionJobStorage(db))
+}
+
+func initInferenceGraphService(controllers map[string]cluster.Controller, db *gorm.DB, producer queue.Producer, alertService service.ModelEndpointAlertService) service.InferenceGraphService {
+	return service.NewInferenceGraphService(controllers, storage.NewInferenceGraphStorage(db), storage.NewVersionEndpointStorage(db), producer, alertService)
+}
+
+func initInferenceGraphDeployment(cfg *config.Config, controllers map[string]cluster.Controller, db *gorm.DB) *work.InferenceGraphDeployment {
+	return &work.InferenceGraphDeployment{
+		ClusterControllers:     controllers,
+		Storage:                storage.NewInferenceGraphStorage(db),
+		VersionEndpointStorage: storage.NewVersionEndpointStorage(db),
+		LoggerDestinationURL:   cfg.LoggerDestinationURL,
+	}
 }
 
-func initModelServiceDeployment(cfg *config.Config, builder imagebuilder.ImageBuilder, controllers map[string]cluster.Controller, db *gorm.DB) *work.ModelServiceDeployment {
+func initModelServiceDeployment(cfg *config.Config, builder imagebuilder.ImageBuilder, controllers map[string]cluster.Controller, db *gorm.DB, notifier webhook.Notifier) *work.ModelServiceDeployment {
 	return &work.ModelServiceDeployment{
 		ClusterControllers:   controllers,
 		ImageBuilder:         builder,
 		Storage:              storage.NewVersionEndpointStorage(db),
 		DeploymentStorage:    storage.NewDeploymentStorage(db),
+		EventStorage:         storage.NewDeploymentEventStorage(db),
 		LoggerDestinationURL: cfg.LoggerDestinationURL,
+		Notifier:             notifier,
 	}
 }
 
@@ -453,17 +529,60 @@ func initClusterControllers(cfg *config.Config) map[string]cluster.Controller {
 	return controllers
 }
 
-func initVersionEndpointService(cfg *config.Config, builder imagebuilder.ImageBuilder, controllers map[string]cluster.Controller, db *gorm.DB, feastCoreClient core.CoreServiceClient, producer queue.Producer) service.EndpointsService {
+// initAlertDeliverers selects the delivery backend of model endpoint alerts in each environment.
+// Environments without alert config use GitLab, if it's configured.
+func initAlertDeliverers(cfg *config.Config, controllers map[string]cluster.Controller) map[string]service.AlertDeliverer {
+	var gitlabDeliverer service.AlertDeliverer
+	gitlabConfig := cfg.FeatureToggleConfig.AlertConfig.GitlabConfig
+	if gitlabConfig.BaseURL != "" {
+		gitlabClient, err := gitlab.NewClient(gitlabConfig.BaseURL, gitlabConfig.Token)
+		if err != nil {
+			log.Panicf("unable to initialize GitLab client: %v", err)
+		}
+		gitlabDeliverer = service.NewGitlabAlertDeliverer(gitlabClient, gitlabConfig.AlertRepository, gitlabConfig.AlertBranch)
+	}
+
+	deliverers := make(map[string]service.AlertDeliverer)
+	for _, env := range cfg.EnvironmentConfigs {
+		alertConfig := env.AlertConfig
+		if alertConfig == nil {
+			alertConfig = &config.EnvironmentAlertConfig{}
+		}
+
+		switch alertConfig.Backend {
+		case config.AlertDeliveryBackendPrometheusRule:
+			deliverers[env.Name] = service.NewPrometheusRuleAlertDeliverer(controllers[env.Name], alertConfig.Namespace, alertConfig.Labels)
+		case config.AlertDeliveryBackendConfigMap:
+			deliverers[env.Name] = service.NewConfigMapAlertDeliverer(controllers[env.Name], alertConfig.Namespace, alertConfig.Labels)
+		case config.AlertDeliveryBackendDirectory:
+			deliverers[env.Name] = service.NewDirectoryAlertDeliverer(alertConfig.Directory)
+		case config.AlertDeliveryBackendGitlab:
+			if gitlabDeliverer == nil {
+				log.Panicf("environment %s delivers alerts to GitLab but GitLab is not configured", env.Name)
+			}
+			deliverers[env.Name] = gitlabDeliverer
+		default:
+			if gitlabDeliverer != nil {
+				deliverers[env.Name] = gitlabDeliverer
+			}
+		}
+	}
+	return deliverers
+}
+
+func initVersionEndpointService(cfg *config.Config, builder imagebuilder.ImageBuilder, controllers map[string]cluster.Controller, db *gorm.DB, feastCoreClient core.CoreServiceClient, producer queue.Producer, projectQuotaService service.ProjectQuotaService) service.EndpointsService {
 	return service.NewEndpointService(service.EndpointServiceParams{
 		ClusterControllers:        controllers,
 		ImageBuilder:              builder,
 		Storage:                   storage.NewVersionEndpointStorage(db),
 		DeploymentStorage:         storage.NewDeploymentStorage(db),
+		DeploymentEventStorage:    storage.NewDeploymentEventStorage(db),
 		MonitoringConfig:          cfg.FeatureToggleConfig.MonitoringConfig,
 		LoggerDestinationURL:      cfg.LoggerDestinationURL,
 		JobProducer:               producer,
 		FeastCoreClient:           feastCoreClient,
 		StandardTransformerConfig: cfg.StandardTransformerConfig,
+		ProjectQuotaService:       projectQuotaService,
 	})
 }
 
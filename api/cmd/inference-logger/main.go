@@ -9,15 +9,22 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/kelseyhightower/envconfig"
 	nrconfig "github.com/newrelic/newrelic-client-go/v2/pkg/config"
 	nrlog "github.com/newrelic/newrelic-client-go/v2/pkg/logs"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	network "knative.dev/networking/pkg"
 	pkgnet "knative.dev/pkg/network"
 	pkghandler "knative.dev/pkg/network/handlers"
@@ -28,6 +35,9 @@ import (
 
 	"github.com/caraml-dev/merlin/pkg/inference-logger/liveness"
 	merlinlogger "github.com/caraml-dev/merlin/pkg/inference-logger/logger"
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
+	"github.com/caraml-dev/merlin/pkg/kafka/schemaregistry"
+	"github.com/caraml-dev/merlin/pkg/protocol"
 )
 
 var (
@@ -38,6 +48,7 @@ var (
 	logMode          = flag.String("log-mode", string(merlinlogger.LogModeAll), "Whether to log 'request', 'response' or 'all'")
 	inferenceService = flag.String("inference-service", "my-model-1", "The InferenceService name to add as header to log events")
 	namespace        = flag.String("namespace", "my-project", "The namespace to add as header to log events")
+	protocolName     = flag.String("protocol", string(protocol.HttpJson), "Protocol of the component, 'UPI_V1' proxies and logs its gRPC requests too. Overridden by the protocol option of the log-url")
 )
 
 const (
@@ -59,6 +70,14 @@ const (
 
 	// NewRelic client configuration
 	NewRelicLogLevel = "info"
+
+	// Interval at which the file and S3 sinks write the files whose log entries are buffered for too long
+	FileSinkFlushInterval = 10 * time.Second
+	// Region of the S3 bucket if it's not in the log-url, S3-compatible storages usually ignore it
+	DefaultS3Region = "us-east-1"
+
+	// Directory of the buffer where the dead-letter sink writes by default
+	DeadLetterDir = "dead-letter"
 )
 
 type config struct {
@@ -66,6 +85,13 @@ type config struct {
 	ServingReadinessProbe string `split_words:"true" required:"false"`
 
 	UnixSocketPath string `split_words:"true" required:"false" default:"@/kserve/agent.sock"`
+
+	// Optional disk buffer between the workers and the log sink, its directory should be an emptyDir volume
+	BufferDir     string `split_words:"true" required:"false"`
+	BufferMaxSize int64  `split_words:"true" required:"false"`
+	// Log sink of the log entries which can't be delivered, JSONL files in the dead-letter directory of the buffer by default
+	DeadLetterUrl string `split_words:"true" required:"false"`
+	MetricsPort   string `split_words:"true" required:"false" default:"9082"`
 }
 
 func main() {
@@ -96,6 +122,26 @@ func main() {
 		log.Info("Malformed log-url", "URL", *logUrl)
 		os.Exit(-1)
 	}
+
+	// The sampling, redaction and truncation policy is appended to the log-url by Merlin
+	sinkUrl, logPolicy, err := logpolicy.ParseURL(*logUrl)
+	if err != nil {
+		log.Info("Malformed log-url", "URL", *logUrl, "error", err)
+		os.Exit(-1)
+	}
+	// So are the options of the sidecar injected by KServe, which can't be configured by flags
+	sinkUrl, options, err := logpolicy.ParseURLOptions(sinkUrl)
+	if err != nil {
+		log.Info("Malformed log-url", "URL", *logUrl, "error", err)
+		os.Exit(-1)
+	}
+	if options.Has(logpolicy.OptionProtocol) {
+		*protocolName = options.Get(logpolicy.OptionProtocol)
+	}
+	if err := applyOptions(&env, options); err != nil {
+		log.Info("Malformed log-url", "URL", *logUrl, "error", err)
+		os.Exit(-1)
+	}
 	loggingMode := merlinlogger.LogMode(*logMode)
 	switch loggingMode {
 	case merlinlogger.LogModeAll, merlinlogger.LogModeRequestOnly, merlinlogger.LogModeResponseOnly:
@@ -104,28 +150,58 @@ func main() {
 		os.Exit(-1)
 	}
 
+	modelProtocol := protocol.Protocol(*protocolName)
+	switch modelProtocol {
+	case protocol.HttpJson, protocol.UpiV1:
+	default:
+		log.Info("Malformed protocol", "protocol", *protocolName)
+		os.Exit(-1)
+	}
+
 	target := &url.URL{
 		Scheme: "http",
 		Host:   net.JoinHostPort("127.0.0.1", *componentPort),
 	}
 
 	workerConfig := &merlinlogger.WorkerConfig{
-		MinBatchSize: QueueMinBatchSize,
-		MaxBatchSize: QueueMaxBatchSize,
+		MinBatchSize:  QueueMinBatchSize,
+		MaxBatchSize:  QueueMaxBatchSize,
+		FlushInterval: FileSinkFlushInterval,
 	}
 
-	logSink := getLogSink(*logUrl, log)
+	logSink := getLogSink(sinkUrl, log)
+	if env.BufferDir != "" && logSink != nil {
+		logSink, err = createDiskBuffer(env, logSink, log)
+		if err != nil {
+			log.Errorw("Failed to create disk buffer", zap.Error(err))
+			os.Exit(-1)
+		}
+	}
 	dispatcher := createLoggerDispatcher(workerConfig, logSink, log)
 	dispatcher.Start()
 
 	// Create handler chain.
 	// Note: innermost handlers are specified first, ie. the last handler in the chain will be executed first.
-	drainer, mainServer := buildServer(target, dispatcher, loggingMode, probe, log)
+	drainer, mainServer := buildServer(target, dispatcher, loggingMode, logPolicy, probe, log)
+
+	// UPI models are served through gRPC, whose requests are proxied alongside the HTTP ones on the same port
+	var grpcServer *grpc.Server
+	if modelProtocol == protocol.UpiV1 {
+		grpcServer, err = buildGRPCServer(target.Host, dispatcher, loggingMode, logPolicy, log)
+		if err != nil {
+			log.Errorw("Failed to connect to the component", zap.Error(err))
+			os.Exit(-1)
+		}
+	}
 
 	ctx := signals.NewContext()
 	servers := map[string]*http.Server{
 		"main": mainServer,
 	}
+	// The metrics are only those of the disk buffer
+	if env.BufferDir != "" {
+		servers["metrics"] = pkgnet.NewServer(":"+env.MetricsPort, promhttp.Handler())
+	}
 	errCh := make(chan error)
 	listenCh := make(chan struct{})
 	log.Infof("Listening at :%s", *port)
@@ -140,6 +216,10 @@ func main() {
 			// Notify the unix socket setup that the tcp socket for the main server is ready.
 			if s == mainServer {
 				close(listenCh)
+
+				if grpcServer != nil {
+					l = serveGRPC(l, grpcServer, errCh)
+				}
 			}
 
 			// Don't forward ErrServerClosed as that indicates we're already shutting down.
@@ -183,6 +263,10 @@ func main() {
 		log.Info("Received TERM signal, attempting to gracefully shutdown servers.")
 		log.Infof("Sleeping %v to allow K8s propagation of non-ready state", drainSleepDuration)
 		drainer.Drain()
+		if grpcServer != nil {
+			log.Info("Shutting down grpc server")
+			grpcServer.GracefulStop()
+		}
 		dispatcher.Stop()
 
 		for serverName, srv := range servers {
@@ -195,7 +279,7 @@ func main() {
 	}
 }
 
-func buildServer(target *url.URL, dispatcher *merlinlogger.Dispatcher, loggingMode merlinlogger.LogMode, probe func() bool, log *zap.SugaredLogger) (*pkghandler.Drainer, *http.Server) {
+func buildServer(target *url.URL, dispatcher *merlinlogger.Dispatcher, loggingMode merlinlogger.LogMode, logPolicy *logpolicy.Policy, probe func() bool, log *zap.SugaredLogger) (*pkghandler.Drainer, *http.Server) {
 	maxIdleConns := 1000 // TODO: somewhat arbitrary value for CC=0, needs experimental validation.
 
 	httpProxy := httputil.NewSingleHostReverseProxy(target)
@@ -206,7 +290,7 @@ func buildServer(target *url.URL, dispatcher *merlinlogger.Dispatcher, loggingMo
 	httpProxy.FlushInterval = network.FlushInterval
 
 	var composedHandler http.Handler = httpProxy
-	composedHandler = merlinlogger.NewLoggerHandler(dispatcher, loggingMode, composedHandler, log)
+	composedHandler = merlinlogger.NewLoggerHandler(dispatcher, loggingMode, logPolicy, composedHandler, log)
 
 	inner := queue.ForwardedShimHandler(composedHandler)
 	composedHandler = inner
@@ -223,6 +307,82 @@ func buildServer(target *url.URL, dispatcher *merlinlogger.Dispatcher, loggingMo
 	return drainer, mainServer
 }
 
+// buildGRPCServer creates the gRPC server that proxies and logs the UPI requests sent to the component
+func buildGRPCServer(target string, dispatcher *merlinlogger.Dispatcher, loggingMode merlinlogger.LogMode, logPolicy *logpolicy.Policy, log *zap.SugaredLogger) (*grpc.Server, error) {
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(merlinlogger.NewUnaryLoggerInterceptor(dispatcher, loggingMode, logPolicy, log)))
+	upiv1.RegisterUniversalPredictionServiceServer(grpcServer, merlinlogger.NewUPIProxy(upiv1.NewUniversalPredictionServiceClient(conn)))
+	return grpcServer, nil
+}
+
+// serveGRPC serves the gRPC requests of the listener and returns the listener of the remaining HTTP requests
+func serveGRPC(l net.Listener, grpcServer *grpc.Server, errCh chan<- error) net.Listener {
+	m := cmux.New(l)
+	// cmux.HTTP2MatchHeaderFieldSendSettings ensures we can handle any gRPC client.
+	grpcListener := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldPrefixSendSettings("content-type", "application/grpc"))
+	httpListener := m.Match(cmux.Any())
+
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			errCh <- fmt.Errorf("grpc server failed to serve: %w", err)
+		}
+	}()
+	go func() {
+		if err := m.Serve(); err != nil && !errors.Is(err, cmux.ErrListenerClosed) && !errors.Is(err, net.ErrClosed) {
+			errCh <- fmt.Errorf("cmux server failed to serve: %w", err)
+		}
+	}()
+	return httpListener
+}
+
+// applyOptions overrides the config with the options of the log-url, the environment of the injected sidecar can't be set
+func applyOptions(env *config, options url.Values) error {
+	if options.Has(logpolicy.OptionBufferDir) {
+		env.BufferDir = options.Get(logpolicy.OptionBufferDir)
+	}
+	if options.Has(logpolicy.OptionBufferMaxSize) {
+		maxSize, err := strconv.ParseInt(options.Get(logpolicy.OptionBufferMaxSize), 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed %s %s: %w", logpolicy.OptionBufferMaxSize, options.Get(logpolicy.OptionBufferMaxSize), err)
+		}
+		env.BufferMaxSize = maxSize
+	}
+	if options.Has(logpolicy.OptionDeadLetterURL) {
+		env.DeadLetterUrl = options.Get(logpolicy.OptionDeadLetterURL)
+	}
+	if options.Has(logpolicy.OptionMetricsPort) {
+		env.MetricsPort = options.Get(logpolicy.OptionMetricsPort)
+	}
+	return nil
+}
+
+// createDiskBuffer puts a disk buffer in front of the log sink, the log entries which can't be delivered are sent to the dead-letter sink
+func createDiskBuffer(env config, logSink merlinlogger.LogSink, log *zap.SugaredLogger) (merlinlogger.LogSink, error) {
+	var deadLetterSink merlinlogger.LogSink
+	if env.DeadLetterUrl != "" {
+		deadLetterSink = getLogSink(env.DeadLetterUrl, log)
+	} else {
+		projectName := *namespace
+		modelName, modelVersion := getModelNameAndVersion(*inferenceService)
+		objectWriter := merlinlogger.NewLocalObjectWriter(filepath.Join(env.BufferDir, DeadLetterDir))
+
+		var err error
+		deadLetterSink, err = merlinlogger.NewFileSink(log, objectWriter, merlinlogger.FileSinkConfig{}, projectName, modelName, modelVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return merlinlogger.NewDiskBufferedSink(log, logSink, deadLetterSink, merlinlogger.DiskBufferConfig{
+		Dir:     env.BufferDir,
+		MaxSize: env.BufferMaxSize,
+	})
+}
+
 func createLoggerDispatcher(
 	workerConfig *merlinlogger.WorkerConfig,
 	logSink merlinlogger.LogSink,
@@ -301,12 +461,20 @@ func getLogSink(
 
 		return merlinlogger.NewNewRelicSink(log, logClient, serviceName, projectName, modelName, modelVersion)
 	case merlinlogger.Kafka:
+		// kafka:<brokers>?schema_registry_url=<url>
+		brokers, rawQuery, _ := strings.Cut(host, "?")
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			log.Infof("malformed options of log-url %s: %v", host, err)
+			return nil
+		}
+
 		// Initialize the producer
 		var kafkaProducer merlinlogger.KafkaProducer
 		// Create Kafka Producer
-		kafkaProducer, err := kafka.NewProducer(
+		kafkaProducer, err = kafka.NewProducer(
 			&kafka.ConfigMap{
-				"bootstrap.servers": host,
+				"bootstrap.servers": brokers,
 				"message.max.bytes": merlinlogger.MaxMessageBytes,
 				"compression.type":  merlinlogger.CompressionType,
 			},
@@ -323,8 +491,94 @@ func getLogSink(
 			return nil
 		}
 
+		if schemaRegistryURL := query.Get("schema_registry_url"); schemaRegistryURL != "" {
+			client, err := schemaregistry.NewClient(schemaRegistryURL)
+			if err != nil {
+				log.Info(err)
+				return nil
+			}
+			kafkaSink, err := merlinlogger.NewSchemaRegistryKafkaSink(log, kafkaProducer, client, serviceName, projectName, modelName, modelVersion, topicName)
+			if err != nil {
+				log.Info(err)
+				return nil
+			}
+			return kafkaSink
+		}
+
 		return merlinlogger.NewKafkaSink(log, kafkaProducer, serviceName, projectName, modelName, modelVersion, topicName)
+	case merlinlogger.File:
+		// file:<directory>?format=<jsonl|parquet>&max_file_size=<bytes>&max_file_age=<duration>&max_buffer_size=<bytes>
+		dir, _, fileSinkConfig, err := getFileSinkConfig(host)
+		if err != nil {
+			log.Info(err)
+			return nil
+		}
+
+		fileSink, err := merlinlogger.NewFileSink(log, merlinlogger.NewLocalObjectWriter(dir), fileSinkConfig, projectName, modelName, modelVersion)
+		if err != nil {
+			log.Info(err)
+			return nil
+		}
+		return fileSink
+	case merlinlogger.S3:
+		// s3:<bucket>/<prefix>?endpoint=<url>&region=<region>&format=<jsonl|parquet>&max_file_size=<bytes>&max_file_age=<duration>&max_buffer_size=<bytes>
+		path, query, fileSinkConfig, err := getFileSinkConfig(host)
+		if err != nil {
+			log.Info(err)
+			return nil
+		}
+
+		region := query.Get("region")
+		if region == "" {
+			region = DefaultS3Region
+		}
+		s3Client, err := merlinlogger.NewS3Client(query.Get("endpoint"), region)
+		if err != nil {
+			log.Info(err)
+			return nil
+		}
+
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(path, "//"), "/")
+		objectWriter := merlinlogger.NewS3ObjectWriter(s3Client, bucket, strings.Trim(prefix, "/"))
+		fileSink, err := merlinlogger.NewFileSink(log, objectWriter, fileSinkConfig, projectName, modelName, modelVersion)
+		if err != nil {
+			log.Info(err)
+			return nil
+		}
+		return fileSink
 	default:
 		return merlinlogger.NewConsoleSink(log)
 	}
 }
+
+// getFileSinkConfig parses the destination of the file and S3 sinks into its path, options and file sink config
+func getFileSinkConfig(dest string) (string, url.Values, merlinlogger.FileSinkConfig, error) {
+	path, rawQuery, _ := strings.Cut(dest, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", nil, merlinlogger.FileSinkConfig{}, fmt.Errorf("malformed options of log-url %s: %w", dest, err)
+	}
+
+	config := merlinlogger.FileSinkConfig{
+		Format: query.Get("format"),
+	}
+	if maxFileSize := query.Get("max_file_size"); maxFileSize != "" {
+		config.MaxFileSize, err = strconv.ParseInt(maxFileSize, 10, 64)
+		if err != nil {
+			return "", nil, merlinlogger.FileSinkConfig{}, fmt.Errorf("malformed max_file_size %s: %w", maxFileSize, err)
+		}
+	}
+	if maxFileAge := query.Get("max_file_age"); maxFileAge != "" {
+		config.MaxFileAge, err = time.ParseDuration(maxFileAge)
+		if err != nil {
+			return "", nil, merlinlogger.FileSinkConfig{}, fmt.Errorf("malformed max_file_age %s: %w", maxFileAge, err)
+		}
+	}
+	if maxBufferSize := query.Get("max_buffer_size"); maxBufferSize != "" {
+		config.MaxBufferSize, err = strconv.ParseInt(maxBufferSize, 10, 64)
+		if err != nil {
+			return "", nil, merlinlogger.FileSinkConfig{}, fmt.Errorf("malformed max_buffer_size %s: %w", maxBufferSize, err)
+		}
+	}
+	return path, query, config, nil
+}
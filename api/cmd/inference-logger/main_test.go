@@ -2,7 +2,10 @@ package main
 
 import (
 	"testing"
+	"time"
 
+	merlinlogger "github.com/caraml-dev/merlin/pkg/inference-logger/logger"
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,3 +19,103 @@ func TestGetModelVersion(t *testing.T) {
 func TestGetTopicName(t *testing.T) {
 	assert.Equal(t, "merlin-my-project-my-model-inference-log", getTopicName(getServiceName("my-project", "my-model")))
 }
+
+func TestGetFileSinkConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		dest       string
+		wantPath   string
+		wantConfig merlinlogger.FileSinkConfig
+		wantErr    string
+	}{
+		{
+			name:       "path only",
+			dest:       "/var/log/merlin",
+			wantPath:   "/var/log/merlin",
+			wantConfig: merlinlogger.FileSinkConfig{},
+		},
+		{
+			name:     "with options",
+			dest:     "my-bucket/inference-logs?format=parquet&max_file_size=1024&max_file_age=1m&max_buffer_size=8192",
+			wantPath: "my-bucket/inference-logs",
+			wantConfig: merlinlogger.FileSinkConfig{
+				Format:        merlinlogger.FileFormatParquet,
+				MaxFileSize:   1024,
+				MaxFileAge:    time.Minute,
+				MaxBufferSize: 8192,
+			},
+		},
+		{
+			name:    "malformed max_file_size",
+			dest:    "/var/log/merlin?max_file_size=64MB",
+			wantErr: "malformed max_file_size 64MB",
+		},
+		{
+			name:    "malformed max_file_age",
+			dest:    "/var/log/merlin?max_file_age=5",
+			wantErr: "malformed max_file_age 5",
+		},
+		{
+			name:    "malformed max_buffer_size",
+			dest:    "/var/log/merlin?max_buffer_size=1GB",
+			wantErr: "malformed max_buffer_size 1GB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _, config, err := getFileSinkConfig(tt.dest)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.wantConfig, config)
+		})
+	}
+}
+
+func TestApplyOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		logURL  string
+		want    config
+		wantErr string
+	}{
+		{
+			name:   "no options",
+			logURL: "kafka:broker:9092",
+			want:   config{MetricsPort: "9082"},
+		},
+		{
+			name:   "buffer options",
+			logURL: "kafka:broker:9092#merlin-logger-options=buffer_dir=%2Ftmp%2Fbuffer&buffer_max_size=1024&dead_letter_url=gs%3A%2F%2Fmy-bucket%2Fdead-letter&metrics_port=9090&protocol=UPI_V1",
+			want: config{
+				BufferDir:     "/tmp/buffer",
+				BufferMaxSize: 1024,
+				DeadLetterUrl: "gs://my-bucket/dead-letter",
+				MetricsPort:   "9090",
+			},
+		},
+		{
+			name:    "malformed buffer_max_size",
+			logURL:  "kafka:broker:9092#merlin-logger-options=buffer_max_size=1GB",
+			wantErr: "malformed buffer_max_size 1GB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, options, err := logpolicy.ParseURLOptions(tt.logURL)
+			assert.NoError(t, err)
+
+			env := config{MetricsPort: "9082"}
+			err = applyOptions(&env, options)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, env)
+		})
+	}
+}
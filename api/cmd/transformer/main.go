@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"strings"
 
 	metricCollector "github.com/afex/hystrix-go/hystrix/metric_collector"
 	"github.com/gorilla/mux"
@@ -21,6 +24,8 @@ import (
 	"github.com/caraml-dev/merlin/pkg/hystrix"
 	"github.com/caraml-dev/merlin/pkg/kafka"
 	"github.com/caraml-dev/merlin/pkg/protocol"
+	"github.com/caraml-dev/merlin/pkg/transformer/batch"
+	"github.com/caraml-dev/merlin/pkg/transformer/executor"
 	"github.com/caraml-dev/merlin/pkg/transformer/feast"
 	"github.com/caraml-dev/merlin/pkg/transformer/jsonpath"
 	"github.com/caraml-dev/merlin/pkg/transformer/pipeline"
@@ -57,6 +62,8 @@ type AppConfig struct {
 	BigtableOverwriteConfig feast.BigtableOverwriteConfig
 	// Kafka config
 	KafkaConfig kafka.Config
+	// Batch configuration to replay a dataset of request payloads instead of running the server
+	Batch batch.Options
 
 	// By default the value is 0, users should configure this value below the memory requested
 	InitHeapSizeInMB int `envconfig:"INIT_HEAP_SIZE_IN_MB" default:"0"`
@@ -101,6 +108,11 @@ func main() {
 		logger.Fatal("unable to parse feature table metadata", zap.Error(err))
 	}
 
+	if appConfig.Batch.Enabled() {
+		runBatch(appConfig, transformerConfig, featureTableMetadata, logger)
+		return
+	}
+
 	if transformerConfig.TransformerConfig.Feast != nil {
 		// Feast Enricher
 		runFeastEnricherServer(appConfig, transformerConfig, featureTableMetadata, logger)
@@ -143,6 +155,44 @@ func main() {
 	}
 }
 
+// runBatch replays the request payloads of the batch input through the standard transformer instead of serving requests
+func runBatch(appConfig AppConfig, transformerConfig *spec.StandardTransformerConfig, featureTableMetadata []*spec.FeatureTableMetadata, logger *zap.Logger) {
+	feastOpts := feast.OverwriteFeastOptionsConfig(appConfig.Feast, appConfig.RedisOverwriteConfig, appConfig.BigtableOverwriteConfig)
+
+	var modelPredictor executor.ModelPredictor
+	if appConfig.Server.Protocol == protocol.UpiV1 {
+		upiPredictor, err := batch.NewUPIModelPredictor(appConfig.Server.ModelPredictURL, appConfig.Server.ModelTimeout)
+		if err != nil {
+			logger.Fatal("unable to connect to model", zap.Error(err))
+		}
+		modelPredictor = upiPredictor
+	} else {
+		predictURL := fmt.Sprintf("%s/v1/models/%s:predict", appConfig.Server.ModelPredictURL, appConfig.Server.ModelFullName)
+		if !strings.HasPrefix(predictURL, "http://") && !strings.HasPrefix(predictURL, "https://") {
+			predictURL = "http://" + predictURL
+		}
+		modelPredictor = batch.NewHTTPModelPredictor(predictURL, appConfig.Server.ModelTimeout)
+	}
+
+	ctx := context.Background()
+	transformer, err := executor.NewStandardTransformerWithConfig(ctx, transformerConfig,
+		executor.WithFeastOptions(feastOpts),
+		executor.WithFeatureTableMetadata(featureTableMetadata),
+		executor.WithLogger(logger),
+		executor.WithProtocol(appConfig.Server.Protocol),
+		executor.WithModelPredictor(modelPredictor),
+	)
+	if err != nil {
+		logger.Fatal("unable to initialize standard transformer", zap.Error(err))
+	}
+
+	summary, err := batch.NewRunner(transformer, appConfig.Batch, logger).Run(ctx)
+	if err != nil {
+		logger.Fatal("failed replaying request payloads", zap.Error(err), zap.Any("summary", summary))
+	}
+	logger.Info("request payloads replayed", zap.Int("total", summary.Total), zap.Int("failed", summary.Failed))
+}
+
 // TODO: Feast enricher will be deprecated soon all associated functions will be deleted
 func runFeastEnricherServer(appConfig AppConfig, transformerConfig *spec.StandardTransformerConfig, featureTableMetadata []*spec.FeatureTableMetadata, logger *zap.Logger) {
 	feastOpts := feast.OverwriteFeastOptionsConfig(appConfig.Feast, appConfig.RedisOverwriteConfig, appConfig.BigtableOverwriteConfig)
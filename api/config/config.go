@@ -16,6 +16,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -57,6 +58,9 @@ type Config struct {
 	UI                        UIConfig
 	StandardTransformerConfig StandardTransformerConfig
 	MlflowConfig              MlflowConfig
+	WebhookConfig             WebhookConfig
+	ProjectQuotaConfig        ProjectQuotaConfig
+	TeamRegistryConfig        TeamRegistryConfig
 }
 
 // UIConfig stores the configuration for the UI.
@@ -86,13 +90,13 @@ type BaseImageConfigs map[string]BaseImageConfig
 // A struct containing configuration details for each base image type
 type BaseImageConfig struct {
 	// docker image name with path
-	ImageName string `json:"imageName"`
+	ImageName string `json:"imageName" yaml:"image_name"`
 	// Dockerfile Path within the build context
-	DockerfilePath string `json:"dockerfilePath"`
+	DockerfilePath string `json:"dockerfilePath" yaml:"dockerfile_path"`
 	// GCS URL Containing build context
-	BuildContextURI string `json:"buildContextURI"`
+	BuildContextURI string `json:"buildContextURI" yaml:"build_context_uri"`
 	// path to main file to run application
-	MainAppPath string `json:"mainAppPath"`
+	MainAppPath string `json:"mainAppPath" yaml:"main_app_path"`
 }
 
 // Decoder to decode the env variable which is a nested map into a list of BaseImageConfig
@@ -203,6 +207,8 @@ type ImageBuilderConfig struct {
 	MaximumRetry  int32                `envconfig:"IMG_BUILDER_MAX_RETRY" default:"3"`
 	K8sConfig     mlpcluster.K8sConfig `envconfig:"IMG_BUILDER_K8S_CONFIG"`
 	SafeToEvict   bool                 `envconfig:"IMG_BUILDER_SAFE_TO_EVICT" default:"false"`
+	// Reuse the image of another version of the model having the same artifact instead of building a new one
+	ReuseImageByArtifactHash bool `envconfig:"IMG_BUILDER_REUSE_IMAGE_BY_ARTIFACT_HASH" default:"true"`
 }
 
 type Tolerations []v1.Toleration
@@ -235,8 +241,11 @@ type AuthorizationConfig struct {
 }
 
 type FeatureToggleConfig struct {
-	MonitoringConfig MonitoringConfig
-	AlertConfig      AlertConfig
+	MonitoringConfig             MonitoringConfig
+	AlertConfig                  AlertConfig
+	ResourceRecommendationConfig ResourceRecommendationConfig
+	GroundTruthConfig            GroundTruthConfig
+	DriftConfig                  DriftConfig
 }
 
 type MonitoringConfig struct {
@@ -251,6 +260,76 @@ type AlertConfig struct {
 	WardenConfig WardenConfig
 }
 
+// ResourceRecommendationConfig stores the configuration for recommending resource request
+// of a version endpoint based on its historical usage
+type ResourceRecommendationConfig struct {
+	ResourceRecommendationEnabled bool   `envconfig:"RESOURCE_RECOMMENDATION_ENABLED" default:"false"`
+	PrometheusURL                 string `envconfig:"RESOURCE_RECOMMENDATION_PROMETHEUS_URL"`
+	// LookbackWindow is the period of historical usage to consider
+	LookbackWindow time.Duration `envconfig:"RESOURCE_RECOMMENDATION_LOOKBACK_WINDOW" default:"168h"`
+	// CPUPercentile is the percentile of CPU usage used as the basis of the CPU recommendation
+	CPUPercentile float64 `envconfig:"RESOURCE_RECOMMENDATION_CPU_PERCENTILE" default:"0.95"`
+	// Headroom is the fraction of the observed usage added on top of the recommendation
+	Headroom float64 `envconfig:"RESOURCE_RECOMMENDATION_HEADROOM" default:"0.2"`
+}
+
+// GroundTruthConfig stores the configuration for joining the logged predictions with their ground-truth labels
+// and computing the online performance of the model versions
+type GroundTruthConfig struct {
+	GroundTruthEnabled bool `envconfig:"GROUND_TRUTH_ENABLED" default:"false"`
+	// KafkaBrokers the prediction logs and the labels are consumed from, the labels can only be submitted through the API if it's not set
+	KafkaBrokers       string `envconfig:"GROUND_TRUTH_KAFKA_BROKERS"`
+	KafkaConsumerGroup string `envconfig:"GROUND_TRUTH_KAFKA_CONSUMER_GROUP" default:"merlin-ground-truth"`
+	// LabelTopic is the topic of the labels, published as UPI observation logs
+	LabelTopic string `envconfig:"GROUND_TRUTH_LABEL_TOPIC"`
+	// Windows are the sliding windows the online performance is computed over
+	Windows []time.Duration `envconfig:"GROUND_TRUTH_WINDOWS" default:"1h,24h"`
+	// Retention is how long the logged predictions and the labels are kept
+	Retention time.Duration `envconfig:"GROUND_TRUTH_RETENTION" default:"168h"`
+}
+
+// DriftConfig stores the configuration for monitoring the drift of the features and the predictions logged by the model versions
+type DriftConfig struct {
+	DriftEnabled bool `envconfig:"DRIFT_ENABLED" default:"false"`
+	// KafkaBrokers the prediction logs are consumed from, the drift can't be computed if it's not set
+	KafkaBrokers       string `envconfig:"DRIFT_KAFKA_BROKERS"`
+	KafkaConsumerGroup string `envconfig:"DRIFT_KAFKA_CONSUMER_GROUP" default:"merlin-drift"`
+	// Window is the period of logged predictions compared with the reference distributions
+	Window time.Duration `envconfig:"DRIFT_WINDOW" default:"24h"`
+	// Interval is how often the drift is computed
+	Interval time.Duration `envconfig:"DRIFT_INTERVAL" default:"1h"`
+	// Retention is how long the counts of the logged values and the drift results are kept
+	Retention time.Duration `envconfig:"DRIFT_RETENTION" default:"720h"`
+}
+
+// WebhookConfig stores the configuration for delivering events to project webhooks
+type WebhookConfig struct {
+	// Timeout of a single delivery attempt
+	Timeout time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"5s"`
+	// MaxRetries is the number of retries after the first failed attempt
+	MaxRetries int `envconfig:"WEBHOOK_MAX_RETRIES" default:"3"`
+	// RetryBackoff is the delay before the first retry, doubled on every subsequent retry
+	RetryBackoff time.Duration `envconfig:"WEBHOOK_RETRY_BACKOFF" default:"1s"`
+	// MaxConcurrentDeliveries is the number of deliveries, including their retries, sent at the same time
+	MaxConcurrentDeliveries int `envconfig:"WEBHOOK_MAX_CONCURRENT_DELIVERIES" default:"10"`
+	// QueueSize is the number of deliveries waiting to be sent, further events are dropped and recorded as failed deliveries
+	QueueSize int `envconfig:"WEBHOOK_QUEUE_SIZE" default:"1000"`
+	// ShutdownTimeout is how long the queued deliveries are waited for on shutdown
+	ShutdownTimeout time.Duration `envconfig:"WEBHOOK_SHUTDOWN_TIMEOUT" default:"30s"`
+}
+
+// ProjectQuotaConfig stores the configuration for managing project quotas
+type ProjectQuotaConfig struct {
+	// Admins are the emails of the users allowed to set project quotas, no one can set them through the API if it's empty
+	Admins []string `envconfig:"PROJECT_QUOTA_ADMINS"`
+}
+
+// TeamRegistryConfig stores the configuration for managing the teams of the team registry
+type TeamRegistryConfig struct {
+	// Admins are the emails of the users allowed to manage the teams and the project owners, no one can manage them through the API if it's empty
+	Admins []string `envconfig:"TEAM_REGISTRY_ADMINS"`
+}
+
 type GitlabConfig struct {
 	BaseURL             string `envconfig:"GITLAB_BASE_URL"`
 	Token               string `envconfig:"GITLAB_TOKEN"`
@@ -260,8 +339,11 @@ type GitlabConfig struct {
 	AlertBranch         string `envconfig:"GITLAB_ALERT_BRANCH" default:"master"`
 }
 
+// WardenConfig configures Warden, the source of alert teams. Merlin's team registry is used instead if APIHost is empty.
 type WardenConfig struct {
 	APIHost string `envconfig:"WARDEN_API_HOST"`
+	// SyncInterval of registering the teams of Warden in the team registry, teams are not synced if 0
+	SyncInterval time.Duration `envconfig:"WARDEN_SYNC_INTERVAL" default:"0s"`
 }
 
 type MlpAPIConfig struct {
@@ -315,6 +397,9 @@ type KafkaConfig struct {
 	MaxMessageSizeBytes int    `envconfig:"KAFKA_MAX_MESSAGE_SIZE_BYTES" default:"1048588"`
 	ConnectTimeoutMS    int    `envconfig:"KAFKA_CONNECT_TIMEOUT_MS" default:"1000"`
 	SerializationFmt    string `envconfig:"KAFKA_SERIALIZATION_FORMAT" default:"protobuf"`
+
+	// SchemaRegistryURL is the URL of the schema registry the prediction log schemas are registered in, it's required by the avro serialization format
+	SchemaRegistryURL string `envconfig:"KAFKA_SCHEMA_REGISTRY_URL"`
 }
 
 // SimulationFeastConfig feast config that aimed to be used only for simulation of standard transformer
@@ -425,5 +510,19 @@ func (cfg *Config) validate() error {
 	if err := json.Unmarshal([]byte(cfg.PyfuncGRPCOptions), &pyfuncGRPCOpts); err != nil {
 		return err
 	}
+
+	driftConfig := cfg.FeatureToggleConfig.DriftConfig
+	if driftConfig.DriftEnabled && (driftConfig.Window <= 0 || driftConfig.Interval <= 0) {
+		return fmt.Errorf("drift requires a positive window and interval")
+	}
+
+	for _, env := range cfg.EnvironmentConfigs {
+		if env.AlertConfig == nil {
+			continue
+		}
+		if err := env.AlertConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid alert config of environment %s: %w", env.Name, err)
+		}
+	}
 	return nil
 }
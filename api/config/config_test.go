@@ -309,3 +309,44 @@ func TestStandardTransformerConfig_ToFeastStorageConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentAlertConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		cfg     EnvironmentAlertConfig
+		wantErr string
+	}{
+		{
+			desc: "default backend",
+			cfg:  EnvironmentAlertConfig{},
+		},
+		{
+			desc: "prometheus rule",
+			cfg:  EnvironmentAlertConfig{Backend: AlertDeliveryBackendPrometheusRule, Namespace: "monitoring"},
+		},
+		{
+			desc: "directory",
+			cfg:  EnvironmentAlertConfig{Backend: AlertDeliveryBackendDirectory, Directory: "/etc/prometheus/rules"},
+		},
+		{
+			desc:    "directory without directory",
+			cfg:     EnvironmentAlertConfig{Backend: AlertDeliveryBackendDirectory},
+			wantErr: "directory alert backend requires a directory",
+		},
+		{
+			desc:    "unknown backend",
+			cfg:     EnvironmentAlertConfig{Backend: "alertmanager"},
+			wantErr: "unknown alert backend: alertmanager",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := tC.cfg.Validate()
+			if tC.wantErr != "" {
+				assert.EqualError(t, err, tC.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -38,6 +38,12 @@ type DeploymentConfig struct {
 	QueueResourcePercentage string
 	// GRPC Options for Pyfunc server
 	PyfuncGRPCOptions string
+	// KEDA configuration, required to autoscale raw deployment using Prometheus query
+	KedaConfig *KedaConfig
+	// Version of KServe installed in the cluster, e.g. 0.11.0
+	KServeVersion string
+	// Image of KServe's router running the inference graphs, defaults to the router of the installed KServe version
+	InferenceGraphRouterImage string
 }
 
 type ResourceRequests struct {
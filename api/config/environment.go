@@ -15,6 +15,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -45,6 +46,32 @@ type EnvironmentConfig struct {
 	DefaultDeploymentConfig    *ResourceRequestConfig              `yaml:"default_deployment_config"`
 	DefaultTransformerConfig   *ResourceRequestConfig              `yaml:"default_transformer_config"`
 	K8sConfig                  *mlpcluster.K8sConfig               `yaml:"k8s_config"`
+	KedaConfig                 *KedaConfig                         `yaml:"keda_config"`
+	// KServeVersion is the version of KServe installed in the cluster, e.g. 0.11.0. Features requiring a newer KServe are disabled if it's not set.
+	KServeVersion string          `yaml:"kserve_version"`
+	UnitCost      *UnitCostConfig `yaml:"unit_cost"`
+	// InferenceGraphRouterImage is the image of KServe's router running the inference graphs, defaults to kserve/router of KServeVersion
+	InferenceGraphRouterImage string `yaml:"inference_graph_router_image"`
+	// LightweightPredictionJobConfig enables prediction jobs running as a plain kubernetes job instead of spark
+	LightweightPredictionJobConfig *LightweightPredictionJobConfig `yaml:"lightweight_prediction_job_config"`
+	// AlertConfig selects where the Prometheus rules of model endpoint alerts in the environment are delivered to
+	AlertConfig *EnvironmentAlertConfig `yaml:"alert_config"`
+	// ImageBuilderConfig configures the docker image builds of the model versions deployed to the environment
+	ImageBuilderConfig *EnvironmentImageBuilderConfig `yaml:"image_builder_config"`
+}
+
+// EnvironmentImageBuilderConfig configures the docker image builds of the model versions deployed to an environment
+type EnvironmentImageBuilderConfig struct {
+	// KanikoCacheDisabled turns off the caching of the image layers by kaniko
+	KanikoCacheDisabled bool `yaml:"kaniko_cache_disabled"`
+	// Repository the image layers are cached in, <image name>/cache if empty
+	KanikoCacheRepo string `yaml:"kaniko_cache_repo"`
+	// Duration the image layers are cached for, kaniko's default of 2 weeks if empty
+	KanikoCacheTTL time.Duration `yaml:"kaniko_cache_ttl"`
+	// Base images, keyed by name, that can be chosen by the build config of the model versions deployed as model services
+	AllowedBaseImages BaseImageConfigs `yaml:"allowed_base_images"`
+	// Base images, keyed by name, that can be chosen by the build config of the model versions run as prediction jobs
+	PredictionJobAllowedBaseImages BaseImageConfigs `yaml:"prediction_job_allowed_base_images"`
 }
 
 type PredictionJobResourceRequestConfig struct {
@@ -62,6 +89,76 @@ type ResourceRequestConfig struct {
 	MemoryRequest string `yaml:"memory_request"`
 }
 
+// KedaConfig configures KEDA which is used to autoscale raw deployment based on Prometheus query
+type KedaConfig struct {
+	// Address of Prometheus server that will be queried by KEDA's prometheus scaler
+	PrometheusServerAddress string `yaml:"prometheus_server_address"`
+	// Interval of KEDA checking the prometheus query, in seconds
+	PollingInterval int32 `yaml:"polling_interval"`
+	// Period to wait after the last trigger reported active before scaling down, in seconds
+	CooldownPeriod int32 `yaml:"cooldown_period"`
+}
+
+// UnitCostConfig is the monthly price of compute resources in the environment, used to estimate deployment's cost
+type UnitCostConfig struct {
+	// Monthly cost of 1 CPU core
+	CPUMonthlyCost float64 `yaml:"cpu_monthly_cost"`
+	// Monthly cost of 1 GiB of memory
+	MemoryMonthlyCost float64 `yaml:"memory_monthly_cost"`
+	// Monthly cost of 1 GPU
+	GPUMonthlyCost float64 `yaml:"gpu_monthly_cost"`
+}
+
+// LightweightPredictionJobConfig limits the prediction jobs running in lightweight mode in the environment
+type LightweightPredictionJobConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Maximum number of batches predicted concurrently by a job, unlimited if 0
+	MaxConcurrency int `yaml:"max_concurrency"`
+	// Maximum number of rows per batch, unlimited if 0
+	MaxBatchSize int `yaml:"max_batch_size"`
+}
+
+// AlertDeliveryBackend is the destination of the Prometheus rules generated for model endpoint alerts
+type AlertDeliveryBackend string
+
+const (
+	// AlertDeliveryBackendGitlab commits the rules file to the GitLab alert repository
+	AlertDeliveryBackendGitlab AlertDeliveryBackend = "gitlab"
+	// AlertDeliveryBackendPrometheusRule applies the rules as Prometheus Operator's PrometheusRule in the environment's cluster
+	AlertDeliveryBackendPrometheusRule AlertDeliveryBackend = "prometheus_rule"
+	// AlertDeliveryBackendConfigMap applies the rules file as a ConfigMap in the environment's cluster
+	AlertDeliveryBackendConfigMap AlertDeliveryBackend = "configmap"
+	// AlertDeliveryBackendDirectory writes the rules file to a local directory
+	AlertDeliveryBackendDirectory AlertDeliveryBackend = "directory"
+)
+
+// EnvironmentAlertConfig configures the delivery of model endpoint alerts in the environment
+type EnvironmentAlertConfig struct {
+	// Backend delivering the rules, gitlab if empty
+	Backend AlertDeliveryBackend `yaml:"backend"`
+	// Namespace of the PrometheusRule or ConfigMap, the model's project namespace if empty
+	Namespace string `yaml:"namespace"`
+	// Labels of the PrometheusRule or ConfigMap, e.g. to be matched by Prometheus' rule selector
+	Labels map[string]string `yaml:"labels"`
+	// Directory the rules files are written to by the directory backend
+	Directory string `yaml:"directory"`
+}
+
+// Validate checks that the backend is known and has the settings it requires
+func (cfg *EnvironmentAlertConfig) Validate() error {
+	switch cfg.Backend {
+	case "", AlertDeliveryBackendGitlab, AlertDeliveryBackendPrometheusRule, AlertDeliveryBackendConfigMap:
+		return nil
+	case AlertDeliveryBackendDirectory:
+		if cfg.Directory == "" {
+			return fmt.Errorf("directory alert backend requires a directory")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown alert backend: %s", cfg.Backend)
+	}
+}
+
 func initEnvironmentConfigs(path string) []EnvironmentConfig {
 	cfgFile, err := os.ReadFile(path)
 	if err != nil {
@@ -91,9 +188,12 @@ func ParseDeploymentConfig(cfg EnvironmentConfig, pyfuncGRPCOptions string) Depl
 			CPURequest:    resource.MustParse(cfg.DefaultTransformerConfig.CPURequest),
 			MemoryRequest: resource.MustParse(cfg.DefaultTransformerConfig.MemoryRequest),
 		},
-		MaxCPU:                  resource.MustParse(cfg.MaxCPU),
-		MaxMemory:               resource.MustParse(cfg.MaxMemory),
-		QueueResourcePercentage: cfg.QueueResourcePercentage,
-		PyfuncGRPCOptions:       pyfuncGRPCOptions,
+		MaxCPU:                    resource.MustParse(cfg.MaxCPU),
+		MaxMemory:                 resource.MustParse(cfg.MaxMemory),
+		QueueResourcePercentage:   cfg.QueueResourcePercentage,
+		PyfuncGRPCOptions:         pyfuncGRPCOptions,
+		KedaConfig:                cfg.KedaConfig,
+		KServeVersion:             cfg.KServeVersion,
+		InferenceGraphRouterImage: cfg.InferenceGraphRouterImage,
 	}
 }
@@ -0,0 +1,137 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const bytesInGi = 1024 * 1024 * 1024
+
+// UnitCost is the monthly price of compute resources in an environment
+type UnitCost struct {
+	// CPUMonthlyCost monthly cost of 1 CPU core
+	CPUMonthlyCost float64 `json:"cpu_monthly_cost"`
+	// MemoryMonthlyCost monthly cost of 1 GiB of memory
+	MemoryMonthlyCost float64 `json:"memory_monthly_cost"`
+	// GPUMonthlyCost monthly cost of 1 GPU
+	GPUMonthlyCost float64 `json:"gpu_monthly_cost"`
+}
+
+func (u UnitCost) Value() (driver.Value, error) {
+	return json.Marshal(u)
+}
+
+func (u *UnitCost) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &u)
+}
+
+// MonthlyCost returns the monthly cost of a single replica requesting the given cpu, memory and gpu
+func (u *UnitCost) MonthlyCost(cpu, memory, gpu resource.Quantity) float64 {
+	cpuCores := float64(cpu.MilliValue()) / 1000
+	memoryGi := float64(memory.Value()) / bytesInGi
+	gpus := float64(gpu.Value())
+	return cpuCores*u.CPUMonthlyCost + memoryGi*u.MemoryMonthlyCost + gpus*u.GPUMonthlyCost
+}
+
+// CostEstimation is the estimated monthly cost of a deployment computed from its resource request and replicas
+type CostEstimation struct {
+	// MinMonthlyCost monthly cost when the deployment is running with its minimum number of replicas
+	MinMonthlyCost float64 `json:"min_monthly_cost"`
+	// MaxMonthlyCost monthly cost when the deployment is running with its maximum number of replicas
+	MaxMonthlyCost float64 `json:"max_monthly_cost"`
+}
+
+// Add adds the other cost estimation into this one
+func (c *CostEstimation) Add(other *CostEstimation) {
+	if other == nil {
+		return
+	}
+	c.MinMonthlyCost += other.MinMonthlyCost
+	c.MaxMonthlyCost += other.MaxMonthlyCost
+}
+
+// round rounds the estimation to 2 decimal places
+func (c *CostEstimation) round() *CostEstimation {
+	c.MinMonthlyCost = math.Round(c.MinMonthlyCost*100) / 100
+	c.MaxMonthlyCost = math.Round(c.MaxMonthlyCost*100) / 100
+	return c
+}
+
+// EstimateCost returns the cost estimation of a deployment using the given resource request.
+// It returns nil if either unit cost or resource request is not available.
+func (u *UnitCost) EstimateCost(resourceRequest *ResourceRequest) *CostEstimation {
+	if u == nil || resourceRequest == nil {
+		return nil
+	}
+
+	replicaCost := u.MonthlyCost(resourceRequest.CPURequest, resourceRequest.MemoryRequest, resourceRequest.GPURequest)
+	estimation := &CostEstimation{
+		MinMonthlyCost: float64(resourceRequest.MinReplica) * replicaCost,
+		MaxMonthlyCost: float64(resourceRequest.MaxReplica) * replicaCost,
+	}
+	return estimation.round()
+}
+
+// EstimatePredictionJobCost returns the cost estimation of a prediction job running with the given resource request
+// It returns nil if either unit cost or resource request is not available.
+func (u *UnitCost) EstimatePredictionJobCost(resourceRequest *PredictionJobResourceRequest) (*CostEstimation, error) {
+	if u == nil || resourceRequest == nil {
+		return nil, nil
+	}
+
+	driverCPU, err := parseOptionalQuantity(resourceRequest.DriverCPURequest)
+	if err != nil {
+		return nil, err
+	}
+	driverMemory, err := parseOptionalQuantity(resourceRequest.DriverMemoryRequest)
+	if err != nil {
+		return nil, err
+	}
+	executorCPU, err := parseOptionalQuantity(resourceRequest.ExecutorCPURequest)
+	if err != nil {
+		return nil, err
+	}
+	executorMemory, err := parseOptionalQuantity(resourceRequest.ExecutorMemoryRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Spark driver and executors of prediction jobs don't request GPU
+	monthlyCost := u.MonthlyCost(driverCPU, driverMemory, resource.Quantity{}) +
+		float64(resourceRequest.ExecutorReplica)*u.MonthlyCost(executorCPU, executorMemory, resource.Quantity{})
+	estimation := &CostEstimation{
+		MinMonthlyCost: monthlyCost,
+		MaxMonthlyCost: monthlyCost,
+	}
+	return estimation.round(), nil
+}
+
+func parseOptionalQuantity(value string) (resource.Quantity, error) {
+	if value == "" {
+		return resource.Quantity{}, nil
+	}
+	return resource.ParseQuantity(value)
+}
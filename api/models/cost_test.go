@@ -0,0 +1,167 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestVersionEndpoint_UpdateCostEstimation(t *testing.T) {
+	unitCost := &UnitCost{
+		CPUMonthlyCost:    20,
+		MemoryMonthlyCost: 2.5,
+	}
+	resourceRequest := &ResourceRequest{
+		MinReplica:    1,
+		MaxReplica:    3,
+		CPURequest:    resource.MustParse("500m"),
+		MemoryRequest: resource.MustParse("2Gi"),
+	}
+
+	tests := []struct {
+		name     string
+		endpoint *VersionEndpoint
+		want     *CostEstimation
+	}{
+		{
+			name: "environment without unit cost",
+			endpoint: &VersionEndpoint{
+				Environment:     &Environment{Name: "dev"},
+				ResourceRequest: resourceRequest,
+			},
+			want: nil,
+		},
+		{
+			name: "model only",
+			endpoint: &VersionEndpoint{
+				Environment:     &Environment{Name: "dev", UnitCost: unitCost},
+				ResourceRequest: resourceRequest,
+			},
+			want: &CostEstimation{MinMonthlyCost: 15, MaxMonthlyCost: 45},
+		},
+		{
+			name: "model with enabled transformer",
+			endpoint: &VersionEndpoint{
+				Environment:     &Environment{Name: "dev", UnitCost: unitCost},
+				ResourceRequest: resourceRequest,
+				Transformer: &Transformer{
+					Enabled: true,
+					ResourceRequest: &ResourceRequest{
+						MinReplica:    1,
+						MaxReplica:    1,
+						CPURequest:    resource.MustParse("100m"),
+						MemoryRequest: resource.MustParse("512Mi"),
+					},
+				},
+			},
+			want: &CostEstimation{MinMonthlyCost: 18.25, MaxMonthlyCost: 48.25},
+		},
+		{
+			name: "model with disabled transformer",
+			endpoint: &VersionEndpoint{
+				Environment:     &Environment{Name: "dev", UnitCost: unitCost},
+				ResourceRequest: resourceRequest,
+				Transformer: &Transformer{
+					Enabled:         false,
+					ResourceRequest: resourceRequest,
+				},
+			},
+			want: &CostEstimation{MinMonthlyCost: 15, MaxMonthlyCost: 45},
+		},
+		{
+			name: "model with gpu",
+			endpoint: &VersionEndpoint{
+				Environment: &Environment{Name: "dev", UnitCost: &UnitCost{
+					CPUMonthlyCost:    20,
+					MemoryMonthlyCost: 2.5,
+					GPUMonthlyCost:    500,
+				}},
+				ResourceRequest: &ResourceRequest{
+					MinReplica:    1,
+					MaxReplica:    2,
+					CPURequest:    resource.MustParse("500m"),
+					MemoryRequest: resource.MustParse("2Gi"),
+					GPURequest:    resource.MustParse("1"),
+				},
+			},
+			want: &CostEstimation{MinMonthlyCost: 515, MaxMonthlyCost: 1030},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.endpoint.UpdateCostEstimation()
+			assert.Equal(t, tt.want, tt.endpoint.CostEstimation)
+		})
+	}
+}
+
+func TestPredictionJob_UpdateCostEstimation(t *testing.T) {
+	unitCost := &UnitCost{
+		CPUMonthlyCost:    20,
+		MemoryMonthlyCost: 2.5,
+	}
+
+	tests := []struct {
+		name string
+		job  *PredictionJob
+		want *CostEstimation
+	}{
+		{
+			name: "driver and executors",
+			job: &PredictionJob{
+				Environment: &Environment{Name: "dev", UnitCost: unitCost},
+				Config: &Config{
+					ResourceRequest: &PredictionJobResourceRequest{
+						DriverCPURequest:      "1",
+						DriverMemoryRequest:   "1Gi",
+						ExecutorReplica:       2,
+						ExecutorCPURequest:    "2",
+						ExecutorMemoryRequest: "4Gi",
+					},
+				},
+			},
+			want: &CostEstimation{MinMonthlyCost: 122.5, MaxMonthlyCost: 122.5},
+		},
+		{
+			name: "invalid quantity",
+			job: &PredictionJob{
+				Environment: &Environment{Name: "dev", UnitCost: unitCost},
+				Config: &Config{
+					ResourceRequest: &PredictionJobResourceRequest{
+						DriverCPURequest: "one",
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "without resource request",
+			job: &PredictionJob{
+				Environment: &Environment{Name: "dev", UnitCost: unitCost},
+				Config:      &Config{},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.job.UpdateCostEstimation()
+			assert.Equal(t, tt.want, tt.job.CostEstimation)
+		})
+	}
+}
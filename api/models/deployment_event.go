@@ -0,0 +1,122 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeploymentEventSource is the component that emitted a deployment event
+type DeploymentEventSource string
+
+const (
+	// DeploymentEventSourceWorker events are emitted by the deployment worker, e.g. deployment start, retries and result
+	DeploymentEventSourceWorker DeploymentEventSource = "worker"
+	// DeploymentEventSourceImageBuilder events are the steps of building the model's image
+	DeploymentEventSourceImageBuilder DeploymentEventSource = "image_builder"
+	// DeploymentEventSourceInferenceService events are condition changes of the endpoint's inference service
+	DeploymentEventSourceInferenceService DeploymentEventSource = "inference_service"
+	// DeploymentEventSourceKubernetes events are Kubernetes events and container states of the endpoint's pods
+	DeploymentEventSourceKubernetes DeploymentEventSource = "kubernetes"
+	// DeploymentEventSourceAPI events are deployments rejected by Merlin API before reaching the worker, e.g. due to the project quota
+	DeploymentEventSourceAPI DeploymentEventSource = "api"
+)
+
+// DeploymentEventType is the severity of a deployment event, following Kubernetes event types
+type DeploymentEventType string
+
+const (
+	DeploymentEventTypeNormal  DeploymentEventType = "normal"
+	DeploymentEventTypeWarning DeploymentEventType = "warning"
+)
+
+// FailureClass is the category of a deployment failure, used to point users to the fix
+type FailureClass string
+
+const (
+	FailureClassImagePull        FailureClass = "image_pull"
+	FailureClassImageBuild       FailureClass = "image_build"
+	FailureClassOOMKilled        FailureClass = "oom_killed"
+	FailureClassCrashLoop        FailureClass = "crash_loop"
+	FailureClassReadinessTimeout FailureClass = "readiness_timeout"
+	FailureClassQuota            FailureClass = "quota"
+	FailureClassUnknown          FailureClass = "unknown"
+)
+
+// failureClassPatterns are matched in order against the lowercased reason and message of a failure,
+// hence more specific causes (e.g. OOMKilled) come before their symptoms (e.g. CrashLoopBackOff)
+var failureClassPatterns = []struct {
+	class    FailureClass
+	patterns []string
+}{
+	{FailureClassImagePull, []string{"errimagepull", "imagepullbackoff", "invalidimagename", "errimageneverpull", "failed to pull image"}},
+	{FailureClassOOMKilled, []string{"oomkilled", "out of memory"}},
+	{FailureClassQuota, []string{"exceeded quota", "exceeds project quota", "insufficient cpu", "insufficient memory", "cpu request is too large", "memory request too large"}},
+	{FailureClassCrashLoop, []string{"crashloopbackoff", "back-off restarting failed container"}},
+	{FailureClassReadinessTimeout, []string{"timeout creating inference service", "readiness probe failed", "progressdeadlineexceeded"}},
+}
+
+// ClassifyDeploymentFailure returns the failure class of a failed deployment step given its reason and message
+func ClassifyDeploymentFailure(reason, message string) FailureClass {
+	text := strings.ToLower(reason + " " + message)
+	for _, c := range failureClassPatterns {
+		for _, pattern := range c.patterns {
+			if strings.Contains(text, pattern) {
+				return c.class
+			}
+		}
+	}
+	return FailureClassUnknown
+}
+
+// DeploymentEvent is an entry of the deployment timeline of a version endpoint
+type DeploymentEvent struct {
+	ID                ID                    `json:"id"`
+	VersionEndpointID uuid.UUID             `json:"version_endpoint_id"`
+	Source            DeploymentEventSource `json:"source"`
+	Type              DeploymentEventType   `json:"type"`
+	// Reason is a short CamelCase identifier of the event, e.g. ImageBuildStarted or BackOff
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Object is the Kubernetes object the event is about, e.g. pod/my-model-1-predictor-abc
+	Object string `json:"object,omitempty"`
+	// FailureClass is set on warning events whose cause is recognised
+	FailureClass FailureClass `json:"failure_class,omitempty"`
+	// Timestamp is when the event happened, which can be earlier than when it was recorded
+	Timestamp time.Time `json:"timestamp"`
+	CreatedUpdated
+}
+
+// NewDeploymentEvent creates a normal event happening now
+func NewDeploymentEvent(source DeploymentEventSource, reason, message string) *DeploymentEvent {
+	return &DeploymentEvent{
+		Source:    source,
+		Type:      DeploymentEventTypeNormal,
+		Reason:    reason,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+}
+
+// NewDeploymentFailureEvent creates a warning event happening now, classifying its failure
+func NewDeploymentFailureEvent(source DeploymentEventSource, reason, message string) *DeploymentEvent {
+	event := NewDeploymentEvent(source, reason, message)
+	event.Type = DeploymentEventTypeWarning
+	event.FailureClass = ClassifyDeploymentFailure(reason, message)
+	return event
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDeploymentFailure(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		reason   string
+		message  string
+		expected FailureClass
+	}{
+		{
+			desc:     "Should classify image pull back-off",
+			reason:   "ImagePullBackOff",
+			message:  "Back-off pulling image \"ghcr.io/model:1\"",
+			expected: FailureClassImagePull,
+		},
+		{
+			desc:     "Should classify failed pull event",
+			reason:   "Failed",
+			message:  "Failed to pull image \"ghcr.io/model:1\": manifest unknown",
+			expected: FailureClassImagePull,
+		},
+		{
+			desc:     "Should classify OOMKilled before the crash loop it causes",
+			reason:   "OOMKilled",
+			message:  "container kserve-container terminated with exit code 137, back-off restarting failed container",
+			expected: FailureClassOOMKilled,
+		},
+		{
+			desc:     "Should classify crash loop",
+			reason:   "BackOff",
+			message:  "Back-off restarting failed container",
+			expected: FailureClassCrashLoop,
+		},
+		{
+			desc:     "Should classify readiness timeout",
+			reason:   "DeploymentFailed",
+			message:  "timeout creating inference service",
+			expected: FailureClassReadinessTimeout,
+		},
+		{
+			desc:     "Should classify failed readiness probe",
+			reason:   "Unhealthy",
+			message:  "Readiness probe failed: HTTP probe failed with statuscode: 503",
+			expected: FailureClassReadinessTimeout,
+		},
+		{
+			desc:     "Should classify kubernetes resource quota",
+			reason:   "FailedCreate",
+			message:  "pods \"model-1\" is forbidden: exceeded quota: compute-resources, requested: limits.cpu=8",
+			expected: FailureClassQuota,
+		},
+		{
+			desc:     "Should classify insufficient cluster resources",
+			reason:   "FailedScheduling",
+			message:  "0/3 nodes are available: 3 Insufficient memory.",
+			expected: FailureClassQuota,
+		},
+		{
+			desc:     "Should classify too large resource request",
+			reason:   "DeploymentFailed",
+			message:  "CPU request is too large",
+			expected: FailureClassQuota,
+		},
+		{
+			desc:     "Should be unknown otherwise",
+			reason:   "DeploymentFailed",
+			message:  "error creating inference service",
+			expected: FailureClassUnknown,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, ClassifyDeploymentFailure(tC.reason, tC.message))
+		})
+	}
+}
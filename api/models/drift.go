@@ -0,0 +1,305 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DriftFeatureSource is the table of the prediction log a monitored feature is read from
+type DriftFeatureSource string
+
+const (
+	// DriftFeatureSourceFeature reads the feature from the features table of the prediction log
+	DriftFeatureSourceFeature DriftFeatureSource = "feature"
+	// DriftFeatureSourcePrediction reads the feature from the prediction results table of the prediction log
+	DriftFeatureSourcePrediction DriftFeatureSource = "prediction"
+)
+
+// DriftFeatureKind is how the distribution of a monitored feature is described
+type DriftFeatureKind string
+
+const (
+	// DriftFeatureNumeric is described by evenly spaced quantiles
+	DriftFeatureNumeric DriftFeatureKind = "numeric"
+	// DriftFeatureCategorical is described by the share of each category
+	DriftFeatureCategorical DriftFeatureKind = "categorical"
+)
+
+// DriftStatistic is a measure of the distance between the reference and the observed distributions of a feature
+type DriftStatistic string
+
+const (
+	// DriftStatisticPSI is the population stability index
+	DriftStatisticPSI DriftStatistic = "psi"
+	// DriftStatisticKS is the Kolmogorov-Smirnov statistic, only computed for numeric features
+	DriftStatisticKS DriftStatistic = "ks"
+	// DriftStatisticJS is the Jensen-Shannon divergence, in base 2 so that it's between 0 and 1
+	DriftStatisticJS DriftStatistic = "jensen_shannon"
+)
+
+// DriftOtherCategory is the bin of the categories missing from the reference distribution
+const DriftOtherCategory = "__other__"
+
+// DriftFeature is a feature, or a prediction output, monitored for drift along with its reference distribution
+type DriftFeature struct {
+	Name   string             `json:"name"`
+	Source DriftFeatureSource `json:"source"`
+	Kind   DriftFeatureKind   `json:"kind"`
+	// Quantiles of a numeric feature are evenly spaced, from the minimum to the maximum of the reference dataset
+	Quantiles []float64 `json:"quantiles,omitempty"`
+	// Categories of a categorical feature map each category to its share of the reference dataset
+	Categories map[string]float64 `json:"categories,omitempty"`
+}
+
+// DriftFeatures is the feature schema of a drift reference
+type DriftFeatures []*DriftFeature
+
+func (f DriftFeatures) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+func (f *DriftFeatures) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &f)
+}
+
+// DriftReference is the reference distribution the logged predictions of a model version are compared with
+type DriftReference struct {
+	ID          ID     `json:"id"`
+	ModelID     ID     `json:"model_id"`
+	VersionID   ID     `json:"version_id"`
+	ProjectName string `json:"-"`
+	ModelName   string `json:"-"`
+	// Dataset is the location of the reference dataset the distributions were computed from, e.g. the training dataset of the MLflow run
+	Dataset  string        `json:"dataset,omitempty"`
+	Features DriftFeatures `json:"features"`
+	CreatedUpdated
+}
+
+// DriftBinCount is the number of values of a monitored feature logged in a bin during an hour
+type DriftBinCount struct {
+	ReferenceID ID
+	Source      DriftFeatureSource
+	Feature     string
+	Bin         string
+	Bucket      time.Time
+	Count       int64
+}
+
+// DriftResult is the drift of a monitored feature over a window of logged predictions
+type DriftResult struct {
+	ID           ID                 `json:"-"`
+	ReferenceID  ID                 `json:"-"`
+	Feature      string             `json:"feature"`
+	Source       DriftFeatureSource `json:"source"`
+	Observations int64              `json:"observations"`
+	PSI          *float64           `json:"psi,omitempty" gorm:"column:psi"`
+	KS           *float64           `json:"ks,omitempty" gorm:"column:ks"`
+	JS           *float64           `json:"jensen_shannon,omitempty" gorm:"column:jensen_shannon"`
+	WindowStart  time.Time          `json:"window_start"`
+	WindowEnd    time.Time          `json:"window_end"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// Statistics returns the computed statistics of the result
+func (r *DriftResult) Statistics() map[DriftStatistic]float64 {
+	statistics := map[DriftStatistic]float64{}
+	for statistic, value := range map[DriftStatistic]*float64{DriftStatisticPSI: r.PSI, DriftStatisticKS: r.KS, DriftStatisticJS: r.JS} {
+		if value != nil {
+			statistics[statistic] = *value
+		}
+	}
+	return statistics
+}
+
+// Validate checks the feature schema and the reference distributions
+func (r *DriftReference) Validate() error {
+	if len(r.Features) == 0 {
+		return errors.New("at least one feature is required")
+	}
+
+	names := map[string]bool{}
+	for _, feature := range r.Features {
+		if feature.Name == "" {
+			return errors.New("feature name is required")
+		}
+		key := string(feature.Source) + "/" + feature.Name
+		if names[key] {
+			return fmt.Errorf("feature %s of source %s is a duplicate", feature.Name, feature.Source)
+		}
+		names[key] = true
+
+		if err := feature.validate(); err != nil {
+			return fmt.Errorf("invalid feature %s: %w", feature.Name, err)
+		}
+	}
+	return nil
+}
+
+func (f *DriftFeature) validate() error {
+	switch f.Source {
+	case DriftFeatureSourceFeature, DriftFeatureSourcePrediction:
+	default:
+		return fmt.Errorf("unknown source %s", f.Source)
+	}
+
+	switch f.Kind {
+	case DriftFeatureNumeric:
+		if len(f.Quantiles) < 2 {
+			return errors.New("numeric feature requires at least 2 quantiles")
+		}
+		if !sort.Float64sAreSorted(f.Quantiles) || f.Quantiles[0] == f.Quantiles[len(f.Quantiles)-1] {
+			return errors.New("quantiles must be increasing")
+		}
+	case DriftFeatureCategorical:
+		if len(f.Categories) == 0 {
+			return errors.New("categorical feature requires at least 1 category")
+		}
+		total := 0.0
+		for category, share := range f.Categories {
+			if share < 0 {
+				return fmt.Errorf("share of category %s must not be negative", category)
+			}
+			total += share
+		}
+		if math.Abs(total-1) > 0.01 {
+			return fmt.Errorf("shares of the categories must sum up to 1, got %v", total)
+		}
+	default:
+		return fmt.Errorf("unknown kind %s", f.Kind)
+	}
+	return nil
+}
+
+// Bin returns the bin of a logged value of the feature, a numeric value falls in one of the bins delimited by the inner
+// quantiles of the reference distribution. Null and mistyped values have no bin.
+func (f *DriftFeature) Bin(value interface{}) (string, bool) {
+	switch f.Kind {
+	case DriftFeatureNumeric:
+		number, ok := value.(float64)
+		if !ok {
+			return "", false
+		}
+		edges := f.Quantiles[1 : len(f.Quantiles)-1]
+		return strconv.Itoa(sort.Search(len(edges), func(i int) bool { return number < edges[i] })), true
+	case DriftFeatureCategorical:
+		var category string
+		switch v := value.(type) {
+		case string:
+			category = v
+		case float64:
+			category = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			category = strconv.FormatBool(v)
+		default:
+			return "", false
+		}
+		if _, ok := f.Categories[category]; !ok {
+			category = DriftOtherCategory
+		}
+		return category, true
+	default:
+		return "", false
+	}
+}
+
+// bins returns the ordered bins of the feature along with their share of the reference distribution
+func (f *DriftFeature) bins() ([]string, []float64) {
+	if f.Kind == DriftFeatureNumeric {
+		edges := f.Quantiles[1 : len(f.Quantiles)-1]
+		bins := make([]string, len(edges)+1)
+		shares := make([]float64, len(edges)+1)
+		lower := math.Inf(-1)
+		for i := range bins {
+			upper := math.Inf(1)
+			if i < len(edges) {
+				upper = edges[i]
+			}
+			bins[i] = strconv.Itoa(i)
+			shares[i] = quantileCDF(f.Quantiles, upper) - quantileCDF(f.Quantiles, lower)
+			lower = upper
+		}
+		return bins, shares
+	}
+
+	bins := make([]string, 0, len(f.Categories)+1)
+	for category := range f.Categories {
+		if category != DriftOtherCategory {
+			bins = append(bins, category)
+		}
+	}
+	sort.Strings(bins)
+	bins = append(bins, DriftOtherCategory)
+	shares := make([]float64, len(bins))
+	for i, category := range bins {
+		shares[i] = f.Categories[category]
+	}
+	return bins, shares
+}
+
+// Drift compares the counts of the logged values in each bin with the reference distribution of the feature.
+// The Kolmogorov-Smirnov statistic is the maximum distance between the cumulative distributions at the bin edges.
+func (f *DriftFeature) Drift(counts map[string]int64) *DriftResult {
+	result := &DriftResult{Feature: f.Name, Source: f.Source}
+	for _, count := range counts {
+		result.Observations += count
+	}
+	if result.Observations == 0 {
+		return result
+	}
+
+	bins, expected := f.bins()
+	psi, js, ks := 0.0, 0.0, 0.0
+	cumulativeExpected, cumulativeActual := 0.0, 0.0
+	for i, bin := range bins {
+		actual := float64(counts[bin]) / float64(result.Observations)
+
+		e, a := math.Max(expected[i], psiEpsilon), math.Max(actual, psiEpsilon)
+		psi += (a - e) * math.Log(a/e)
+
+		m := (expected[i] + actual) / 2
+		js += (klTerm(expected[i], m) + klTerm(actual, m)) / 2
+
+		cumulativeExpected += expected[i]
+		cumulativeActual += actual
+		ks = math.Max(ks, math.Abs(cumulativeActual-cumulativeExpected))
+	}
+
+	result.PSI = &psi
+	result.JS = &js
+	if f.Kind == DriftFeatureNumeric {
+		result.KS = &ks
+	}
+	return result
+}
+
+// klTerm is the term of the Kullback-Leibler divergence in base 2 of a bin
+func klTerm(p, q float64) float64 {
+	if p == 0 {
+		return 0
+	}
+	return p * math.Log2(p/q)
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftReference_Validate(t *testing.T) {
+	numeric := &DriftFeature{Name: "age", Source: DriftFeatureSourceFeature, Kind: DriftFeatureNumeric, Quantiles: []float64{0, 10, 20}}
+	categorical := &DriftFeature{Name: "city", Source: DriftFeatureSourceFeature, Kind: DriftFeatureCategorical, Categories: map[string]float64{"a": 0.4, "b": 0.6}}
+
+	tests := []struct {
+		name      string
+		reference *DriftReference
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			reference: &DriftReference{Features: DriftFeatures{numeric, categorical}},
+		},
+		{
+			name:      "same name in another source",
+			reference: &DriftReference{Features: DriftFeatures{numeric, {Name: "age", Source: DriftFeatureSourcePrediction, Kind: DriftFeatureNumeric, Quantiles: []float64{0, 1}}}},
+		},
+		{
+			name:      "no feature",
+			reference: &DriftReference{},
+			wantErr:   "at least one feature is required",
+		},
+		{
+			name:      "duplicate feature",
+			reference: &DriftReference{Features: DriftFeatures{numeric, numeric}},
+			wantErr:   "feature age of source feature is a duplicate",
+		},
+		{
+			name:      "unknown source",
+			reference: &DriftReference{Features: DriftFeatures{{Name: "age", Source: "raw", Kind: DriftFeatureNumeric, Quantiles: []float64{0, 1}}}},
+			wantErr:   "invalid feature age: unknown source raw",
+		},
+		{
+			name:      "decreasing quantiles",
+			reference: &DriftReference{Features: DriftFeatures{{Name: "age", Source: DriftFeatureSourceFeature, Kind: DriftFeatureNumeric, Quantiles: []float64{10, 0}}}},
+			wantErr:   "invalid feature age: quantiles must be increasing",
+		},
+		{
+			name:      "constant quantiles",
+			reference: &DriftReference{Features: DriftFeatures{{Name: "age", Source: DriftFeatureSourceFeature, Kind: DriftFeatureNumeric, Quantiles: []float64{1, 1}}}},
+			wantErr:   "invalid feature age: quantiles must be increasing",
+		},
+		{
+			name:      "shares don't sum up to 1",
+			reference: &DriftReference{Features: DriftFeatures{{Name: "city", Source: DriftFeatureSourceFeature, Kind: DriftFeatureCategorical, Categories: map[string]float64{"a": 0.5}}}},
+			wantErr:   "invalid feature city: shares of the categories must sum up to 1, got 0.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.reference.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDriftFeature_Bin(t *testing.T) {
+	numeric := &DriftFeature{Kind: DriftFeatureNumeric, Quantiles: []float64{0, 10, 20, 30}}
+	categorical := &DriftFeature{Kind: DriftFeatureCategorical, Categories: map[string]float64{"a": 0.5, "1": 0.5}}
+
+	tests := []struct {
+		name    string
+		feature *DriftFeature
+		value   interface{}
+		want    string
+		wantOk  bool
+	}{
+		{"below minimum", numeric, -5.0, "0", true},
+		{"first inner quantile", numeric, 10.0, "1", true},
+		{"above maximum", numeric, 50.0, "2", true},
+		{"numeric null", numeric, nil, "", false},
+		{"numeric string", numeric, "a", "", false},
+		{"category", categorical, "a", "a", true},
+		{"numeric category", categorical, 1.0, "1", true},
+		{"unknown category", categorical, "b", DriftOtherCategory, true},
+		{"categorical null", categorical, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.feature.Bin(tt.value)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDriftFeature_Drift(t *testing.T) {
+	numeric := &DriftFeature{Name: "age", Source: DriftFeatureSourceFeature, Kind: DriftFeatureNumeric, Quantiles: []float64{0, 10, 20, 30, 40}}
+
+	t.Run("same distribution", func(t *testing.T) {
+		result := numeric.Drift(map[string]int64{"0": 25, "1": 25, "2": 25, "3": 25})
+		assert.Equal(t, int64(100), result.Observations)
+		require.NotNil(t, result.PSI)
+		assert.InDelta(t, 0, *result.PSI, 1e-9)
+		assert.InDelta(t, 0, *result.KS, 1e-9)
+		assert.InDelta(t, 0, *result.JS, 1e-9)
+	})
+
+	t.Run("shifted distribution", func(t *testing.T) {
+		result := numeric.Drift(map[string]int64{"2": 50, "3": 50})
+		assert.InDelta(t, 0.5, *result.KS, 1e-9)
+		// the mixture of both distributions is 1/8 in the first two bins and 3/8 in the last two
+		expectedJS := (0.5*math.Log2(0.25/0.125)+0.5*math.Log2(0.25/0.375))/2 + math.Log2(0.5/0.375)/2
+		assert.InDelta(t, expectedJS, *result.JS, 1e-9)
+		expectedPSI := 2*(0.0001-0.25)*math.Log(0.0001/0.25) + 2*(0.5-0.25)*math.Log(0.5/0.25)
+		assert.InDelta(t, expectedPSI, *result.PSI, 1e-9)
+	})
+
+	t.Run("categorical", func(t *testing.T) {
+		categorical := &DriftFeature{Name: "city", Source: DriftFeatureSourceFeature, Kind: DriftFeatureCategorical, Categories: map[string]float64{"a": 0.5, "b": 0.5}}
+		result := categorical.Drift(map[string]int64{"a": 50, DriftOtherCategory: 50})
+		require.NotNil(t, result.JS)
+		assert.InDelta(t, 0.5, *result.JS, 1e-9)
+		assert.Nil(t, result.KS)
+		assert.Equal(t, map[DriftStatistic]float64{DriftStatisticPSI: *result.PSI, DriftStatisticJS: *result.JS}, result.Statistics())
+	})
+
+	t.Run("no observation", func(t *testing.T) {
+		result := numeric.Drift(map[string]int64{})
+		assert.Equal(t, int64(0), result.Observations)
+		assert.Nil(t, result.PSI)
+		assert.Empty(t, result.Statistics())
+	})
+}
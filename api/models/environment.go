@@ -29,5 +29,9 @@ type Environment struct {
 	IsPredictionJobEnabled              bool                          `json:"is_prediction_job_enabled"`
 	IsDefaultPredictionJob              *bool                         `json:"is_default_prediction_job"`
 	DefaultPredictionJobResourceRequest *PredictionJobResourceRequest `json:"default_prediction_job_resource_request"`
+	// LightweightPredictionJobConfig enables and limits lightweight prediction jobs in the environment
+	LightweightPredictionJobConfig *LightweightPredictionJobConfig `json:"lightweight_prediction_job_config,omitempty"`
+
+	UnitCost *UnitCost `json:"unit_cost,omitempty"`
 	CreatedUpdated
 }
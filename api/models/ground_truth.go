@@ -0,0 +1,181 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// binaryClassThreshold is the score above which a prediction of a binary classifier is the positive class
+const binaryClassThreshold = 0.5
+
+// LoggedPrediction is a row of the prediction results of a prediction log, keyed by the prediction id and the row id
+type LoggedPrediction struct {
+	ID           ID     `json:"-"`
+	ProjectName  string `json:"project_name"`
+	ModelName    string `json:"model_name"`
+	ModelVersion string `json:"model_version"`
+	PredictionID string `json:"prediction_id"`
+	RowID        string `json:"row_id"`
+	// Value is the predicted target, e.g. the probability of the positive class of a binary classifier
+	Value            float64   `json:"value"`
+	RequestTimestamp time.Time `json:"request_timestamp"`
+	CreatedUpdated
+}
+
+// PredictionLabel is the observed outcome, i.e. the ground truth, of a row of a prediction
+type PredictionLabel struct {
+	ID           ID     `json:"-"`
+	PredictionID string `json:"prediction_id"`
+	RowID        string `json:"row_id"`
+	// Value is the observed target, e.g. 0 or 1 for a binary classifier
+	Value      float64   `json:"value"`
+	ObservedAt time.Time `json:"observed_at"`
+	CreatedUpdated
+}
+
+// Validate checks that the label is keyed by a prediction id
+func (l *PredictionLabel) Validate() error {
+	if l.PredictionID == "" {
+		return errors.New("prediction_id is required")
+	}
+	if math.IsNaN(l.Value) || math.IsInf(l.Value, 0) {
+		return errors.New("value must be a finite number")
+	}
+	return nil
+}
+
+// PredictionLabels is the body of the label submission
+type PredictionLabels struct {
+	Labels []*PredictionLabel `json:"labels"`
+}
+
+// PredictionOutcome is a logged prediction joined with its label
+type PredictionOutcome struct {
+	Prediction float64
+	Label      float64
+}
+
+// PredictionModelVersion identifies the model version of logged predictions
+type PredictionModelVersion struct {
+	ProjectName  string
+	ModelName    string
+	ModelVersion string
+}
+
+// OnlinePerformance is the performance of a model version over a sliding window, measured against the labels of its predictions
+type OnlinePerformance struct {
+	VersionID ID `json:"version_id"`
+	// Window is the sliding window, e.g. 1h, the predictions are logged in
+	Window string `json:"window"`
+	// Predictions is the number of predictions logged in the window
+	Predictions int `json:"predictions"`
+	// LabeledPredictions is the number of predictions of the window which have a label
+	LabeledPredictions int `json:"labeled_predictions"`
+	// Accuracy and AUC are only computed if the labels are binary, i.e. 0 or 1, AUC also requires both classes
+	Accuracy *float64 `json:"accuracy,omitempty"`
+	AUC      *float64 `json:"auc,omitempty"`
+	RMSE     *float64 `json:"rmse,omitempty"`
+}
+
+// NewOnlinePerformance computes the performance metrics of the outcomes of the predictions logged in the window
+func NewOnlinePerformance(versionID ID, window string, predictions int, outcomes []*PredictionOutcome) *OnlinePerformance {
+	performance := &OnlinePerformance{
+		VersionID:          versionID,
+		Window:             window,
+		Predictions:        predictions,
+		LabeledPredictions: len(outcomes),
+	}
+	if len(outcomes) == 0 {
+		return performance
+	}
+
+	rmse := computeRMSE(outcomes)
+	performance.RMSE = &rmse
+	if isBinary(outcomes) {
+		accuracy := computeAccuracy(outcomes)
+		performance.Accuracy = &accuracy
+		if auc, ok := computeAUC(outcomes); ok {
+			performance.AUC = &auc
+		}
+	}
+	return performance
+}
+
+func isBinary(outcomes []*PredictionOutcome) bool {
+	for _, outcome := range outcomes {
+		if outcome.Label != 0 && outcome.Label != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func computeRMSE(outcomes []*PredictionOutcome) float64 {
+	sum := 0.0
+	for _, outcome := range outcomes {
+		diff := outcome.Prediction - outcome.Label
+		sum += diff * diff
+	}
+	return math.Sqrt(sum / float64(len(outcomes)))
+}
+
+func computeAccuracy(outcomes []*PredictionOutcome) float64 {
+	correct := 0
+	for _, outcome := range outcomes {
+		positive := outcome.Prediction >= binaryClassThreshold
+		if positive == (outcome.Label == 1) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(outcomes))
+}
+
+// computeAUC computes the area under the ROC curve as the Mann-Whitney U statistic, ties are given their average rank.
+// It returns false if the labels don't have both classes.
+func computeAUC(outcomes []*PredictionOutcome) (float64, bool) {
+	sorted := make([]*PredictionOutcome, len(outcomes))
+	copy(sorted, outcomes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Prediction < sorted[j].Prediction
+	})
+
+	positives, positiveRankSum := 0, 0.0
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j].Prediction == sorted[i].Prediction {
+			j++
+		}
+		// ranks are 1-based, the tied predictions i..j-1 share the average of their ranks
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if sorted[k].Label == 1 {
+				positives++
+				positiveRankSum += rank
+			}
+		}
+		i = j
+	}
+
+	negatives := len(sorted) - positives
+	if positives == 0 || negatives == 0 {
+		return 0, false
+	}
+	u := positiveRankSum - float64(positives*(positives+1))/2
+	return u / float64(positives*negatives), true
+}
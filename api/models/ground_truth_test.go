@@ -0,0 +1,110 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOnlinePerformance(t *testing.T) {
+	tests := []struct {
+		name         string
+		outcomes     []*PredictionOutcome
+		wantAccuracy *float64
+		wantAUC      *float64
+		wantRMSE     *float64
+	}{
+		{
+			name:     "no labels",
+			outcomes: nil,
+		},
+		{
+			name: "binary classifier",
+			outcomes: []*PredictionOutcome{
+				{Prediction: 0.9, Label: 1},
+				{Prediction: 0.6, Label: 0},
+				{Prediction: 0.4, Label: 1},
+				{Prediction: 0.1, Label: 0},
+			},
+			// 0.9 and 0.1 are correct
+			wantAccuracy: floatPtr(0.5),
+			// 3 of the 4 positive-negative pairs are ranked correctly
+			wantAUC:  floatPtr(0.75),
+			wantRMSE: floatPtr(math.Sqrt((0.01 + 0.36 + 0.36 + 0.01) / 4)),
+		},
+		{
+			name: "tied predictions",
+			outcomes: []*PredictionOutcome{
+				{Prediction: 0.5, Label: 1},
+				{Prediction: 0.5, Label: 0},
+			},
+			wantAccuracy: floatPtr(0.5),
+			wantAUC:      floatPtr(0.5),
+			wantRMSE:     floatPtr(0.5),
+		},
+		{
+			name: "single class",
+			outcomes: []*PredictionOutcome{
+				{Prediction: 0.8, Label: 1},
+				{Prediction: 0.2, Label: 1},
+			},
+			wantAccuracy: floatPtr(0.5),
+			wantRMSE:     floatPtr(math.Sqrt((0.04 + 0.64) / 2)),
+		},
+		{
+			name: "regression",
+			outcomes: []*PredictionOutcome{
+				{Prediction: 10, Label: 12},
+				{Prediction: 5, Label: 4},
+			},
+			wantRMSE: floatPtr(math.Sqrt(2.5)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			performance := NewOnlinePerformance(ID(1), "1h", 10, tt.outcomes)
+			assert.Equal(t, ID(1), performance.VersionID)
+			assert.Equal(t, "1h", performance.Window)
+			assert.Equal(t, 10, performance.Predictions)
+			assert.Equal(t, len(tt.outcomes), performance.LabeledPredictions)
+			assertFloatPtr(t, tt.wantAccuracy, performance.Accuracy)
+			assertFloatPtr(t, tt.wantAUC, performance.AUC)
+			assertFloatPtr(t, tt.wantRMSE, performance.RMSE)
+		})
+	}
+}
+
+func TestPredictionLabel_Validate(t *testing.T) {
+	assert.NoError(t, (&PredictionLabel{PredictionID: "1", Value: 1}).Validate())
+	assert.EqualError(t, (&PredictionLabel{Value: 1}).Validate(), "prediction_id is required")
+	assert.EqualError(t, (&PredictionLabel{PredictionID: "1", Value: math.NaN()}).Validate(), "value must be a finite number")
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+func assertFloatPtr(t *testing.T, expected, actual *float64) {
+	if expected == nil {
+		assert.Nil(t, actual)
+		return
+	}
+	if assert.NotNil(t, actual) {
+		assert.InDelta(t, *expected, *actual, 1e-9)
+	}
+}
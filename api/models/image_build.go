@@ -0,0 +1,47 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// ImageBuildStatus is the status of the docker image build of a model version
+type ImageBuildStatus string
+
+const (
+	ImageBuildStatusNotBuilt  ImageBuildStatus = "not_built"
+	ImageBuildStatusBuilding  ImageBuildStatus = "building"
+	ImageBuildStatusSucceeded ImageBuildStatus = "succeeded"
+	ImageBuildStatusFailed    ImageBuildStatus = "failed"
+)
+
+// ImageBuild is the docker image build of a pyfunc model version. It's derived from the kaniko job building the image
+// and the images in the docker registry, hence it's not stored.
+type ImageBuild struct {
+	VersionID ID               `json:"version_id"`
+	Status    ImageBuildStatus `json:"status"`
+	// ImageRef is the image of the model version, it's only usable once the build succeeded
+	ImageRef string `json:"image_ref"`
+	// ArtifactHash identifies the artifact and base image the image is built from, empty if it can't be computed
+	ArtifactHash string `json:"artifact_hash,omitempty"`
+	// Reused is true if the image was built for another model version having the same artifact hash
+	Reused bool `json:"reused"`
+	// JobName, Namespace and Cluster locate the kaniko job building the image and its logs
+	JobName     string     `json:"job_name"`
+	Namespace   string     `json:"namespace"`
+	Cluster     string     `json:"cluster"`
+	Message     string     `json:"message,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
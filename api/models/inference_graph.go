@@ -0,0 +1,283 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/pkg/protocol"
+)
+
+const (
+	// InferenceGraphRootNode is the name of the node receiving the request sent to the inference graph
+	InferenceGraphRootNode = "root"
+	// inferenceGraphMessageMaxLength is the length of the message column of inference graph
+	inferenceGraphMessageMaxLength = 2048
+)
+
+var inferenceGraphNameRegex = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// InferenceGraphRouterType determines how a node routes the request to its steps
+type InferenceGraphRouterType string
+
+const (
+	// InferenceGraphSequence calls the steps one after another
+	InferenceGraphSequence InferenceGraphRouterType = "Sequence"
+	// InferenceGraphEnsemble calls all steps in parallel and combines their responses keyed by step name
+	InferenceGraphEnsemble InferenceGraphRouterType = "Ensemble"
+	// InferenceGraphSwitch calls the first step whose condition matches the request
+	InferenceGraphSwitch InferenceGraphRouterType = "Switch"
+)
+
+// InferenceGraph chains or fans out requests across several version endpoints of a project
+type InferenceGraph struct {
+	ID        ID     `json:"id"`
+	ProjectID ID     `json:"project_id"`
+	Name      string `json:"name"`
+	// Nodes of the graph, keyed by node name. The graph must have a node named "root"
+	Nodes           InferenceGraphNodes `json:"nodes" gorm:"nodes"`
+	Status          EndpointStatus      `json:"status"`
+	URL             string              `json:"url" gorm:"url"`
+	Message         string              `json:"message" gorm:"message"`
+	Environment     *Environment        `json:"environment" gorm:"association_foreignkey:Name"`
+	EnvironmentName string              `json:"environment_name"`
+	// Logger logs the requests and responses of the graph, in addition to the loggers of its version endpoints
+	Logger *LoggerConfig `json:"logger,omitempty" gorm:"logger"`
+	// Alert alerts on the throughput, latency and error rate of the graph
+	Alert *InferenceGraphAlert `json:"alert,omitempty" gorm:"alert"`
+	CreatedUpdated
+}
+
+// InferenceGraphAlert is the alert of an inference graph, delivered in the same way as the model endpoint alerts
+type InferenceGraphAlert struct {
+	TeamName        string          `json:"team_name"`
+	AlertConditions AlertConditions `json:"alert_conditions"`
+	SLOs            AlertSLOs       `json:"slos,omitempty"`
+	// Routes are the teams notified of the alerts of a severity instead of TeamName
+	Routes AlertRoutes `json:"routes,omitempty"`
+}
+
+func (a InferenceGraphAlert) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *InferenceGraphAlert) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &a)
+}
+
+// InferenceGraphNodes is the nodes of an inference graph keyed by node name
+type InferenceGraphNodes map[string]*InferenceGraphNode
+
+func (n InferenceGraphNodes) Value() (driver.Value, error) {
+	return json.Marshal(n)
+}
+
+func (n *InferenceGraphNodes) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &n)
+}
+
+// InferenceGraphNode routes the request to its steps according to the router type
+type InferenceGraphNode struct {
+	RouterType InferenceGraphRouterType `json:"router_type"`
+	Steps      []*InferenceGraphStep    `json:"steps"`
+}
+
+// InferenceGraphStep is a single hop of a node, targeting either a version endpoint or another node of the graph
+type InferenceGraphStep struct {
+	Name string `json:"name"`
+	// VersionEndpointID version endpoint called by the step
+	VersionEndpointID *uuid.UUID `json:"version_endpoint_id,omitempty"`
+	// NodeName node of the graph called by the step
+	NodeName string `json:"node_name,omitempty"`
+	// Data payload sent to the step, either "$request" (default) or "$response" of the previous step
+	Data string `json:"data,omitempty"`
+	// Condition GJSON expression evaluated against the request, required by switch node
+	Condition string `json:"condition,omitempty"`
+	// VersionEndpoint version endpoint referred by VersionEndpointID
+	VersionEndpoint *VersionEndpoint `json:"-"`
+}
+
+// Validate checks that the inference graph is well-formed: it has a root node, every step targets exactly one
+// version endpoint or existing node, and the nodes don't form a cycle
+func (g *InferenceGraph) Validate() error {
+	if !inferenceGraphNameRegex.MatchString(g.Name) {
+		return fmt.Errorf("invalid inference graph name %q: must consist of lower case alphanumeric characters or '-'", g.Name)
+	}
+
+	if _, ok := g.Nodes[InferenceGraphRootNode]; !ok {
+		return fmt.Errorf("inference graph must have a %q node", InferenceGraphRootNode)
+	}
+
+	for nodeName, node := range g.Nodes {
+		if node == nil || len(node.Steps) == 0 {
+			return fmt.Errorf("node %s must have at least one step", nodeName)
+		}
+
+		switch node.RouterType {
+		case InferenceGraphSequence, InferenceGraphEnsemble, InferenceGraphSwitch:
+		default:
+			return fmt.Errorf("node %s has unsupported router type: %q", nodeName, node.RouterType)
+		}
+
+		for i, step := range node.Steps {
+			if (step.VersionEndpointID == nil) == (step.NodeName == "") {
+				return fmt.Errorf("step %d of node %s must specify either version_endpoint_id or node_name", i, nodeName)
+			}
+			if step.NodeName != "" {
+				if _, ok := g.Nodes[step.NodeName]; !ok {
+					return fmt.Errorf("step %d of node %s refers to unknown node %s", i, nodeName, step.NodeName)
+				}
+			}
+			if step.Data != "" && step.Data != "$request" && step.Data != "$response" {
+				return fmt.Errorf("step %d of node %s has invalid data %q: must be $request or $response", i, nodeName, step.Data)
+			}
+			if node.RouterType == InferenceGraphSwitch && step.Condition == "" {
+				return fmt.Errorf("step %d of switch node %s must have a condition", i, nodeName)
+			}
+			if node.RouterType == InferenceGraphEnsemble && step.Name == "" {
+				return fmt.Errorf("step %d of ensemble node %s must have a name", i, nodeName)
+			}
+		}
+	}
+
+	if g.Logger != nil {
+		if err := g.Logger.LogPolicy().Validate(); err != nil {
+			return fmt.Errorf("invalid logger config: %w", err)
+		}
+	}
+
+	if g.Alert != nil {
+		for i, alertCondition := range g.Alert.AlertConditions {
+			if alertCondition.MetricType == AlertConditionTypeDrift {
+				return fmt.Errorf("invalid alert condition %d: drift is not supported by inference graph", i)
+			}
+		}
+	}
+
+	return g.checkCycle()
+}
+
+// InferenceGraphServiceName returns the name of the inference service running the router of the inference graph.
+// The suffix keeps it apart from the inference services of the model versions, which are named <model>-<version>.
+func InferenceGraphServiceName(graphName string) string {
+	return fmt.Sprintf("%s-graph", graphName)
+}
+
+// ServiceName returns the name of the inference service running the router of the inference graph
+func (g *InferenceGraph) ServiceName() string {
+	return InferenceGraphServiceName(g.Name)
+}
+
+// SetMessage sets the deployment message of the inference graph, truncated to fit the message column
+func (g *InferenceGraph) SetMessage(message string) {
+	runes := []rune(message)
+	if len(runes) > inferenceGraphMessageMaxLength {
+		message = string(runes[:inferenceGraphMessageMaxLength])
+	}
+	g.Message = message
+}
+
+// ModelEndpointAlert returns the alert of the inference graph as a model endpoint alert of its router,
+// so that its Prometheus rules are generated and delivered in the same way. It returns nil if the graph has no alert.
+func (g *InferenceGraph) ModelEndpointAlert(project mlp.Project) *ModelEndpointAlert {
+	if g.Alert == nil {
+		return nil
+	}
+
+	return &ModelEndpointAlert{
+		Model: &Model{
+			Name:      g.ServiceName(),
+			ProjectID: g.ProjectID,
+			Project:   project,
+		},
+		ModelEndpoint: &ModelEndpoint{
+			Environment: g.Environment,
+			Protocol:    protocol.HttpJson,
+		},
+		EnvironmentName: g.EnvironmentName,
+		TeamName:        g.Alert.TeamName,
+		AlertConditions: g.Alert.AlertConditions,
+		SLOs:            g.Alert.SLOs,
+		Routes:          g.Alert.Routes,
+	}
+}
+
+// VersionEndpointIDs returns all version endpoints used by the inference graph
+func (g *InferenceGraph) VersionEndpointIDs() []uuid.UUID {
+	ids := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, node := range g.Nodes {
+		for _, step := range node.Steps {
+			if step.VersionEndpointID != nil && !seen[*step.VersionEndpointID] {
+				seen[*step.VersionEndpointID] = true
+				ids = append(ids, *step.VersionEndpointID)
+			}
+		}
+	}
+	return ids
+}
+
+func (g *InferenceGraph) checkCycle() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+
+	var visit func(nodeName string) error
+	visit = func(nodeName string) error {
+		switch state[nodeName] {
+		case visiting:
+			return fmt.Errorf("inference graph has a cycle through node %s", nodeName)
+		case visited:
+			return nil
+		}
+
+		state[nodeName] = visiting
+		for _, step := range g.Nodes[nodeName].Steps {
+			if step.NodeName != "" {
+				if err := visit(step.NodeName); err != nil {
+					return err
+				}
+			}
+		}
+		state[nodeName] = visited
+		return nil
+	}
+
+	for nodeName := range g.Nodes {
+		if err := visit(nodeName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
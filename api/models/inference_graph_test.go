@@ -0,0 +1,166 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/mlp"
+)
+
+func TestInferenceGraph_Validate(t *testing.T) {
+	endpointID := uuid.New()
+	endpointStep := func() *InferenceGraphStep {
+		return &InferenceGraphStep{Name: "model", VersionEndpointID: &endpointID}
+	}
+
+	testCases := []struct {
+		desc     string
+		graph    *InferenceGraph
+		errorMsg string
+	}{
+		{
+			desc: "Should succeed for sequence chaining to ensemble node",
+			graph: &InferenceGraph{
+				Name: "my-graph",
+				Nodes: InferenceGraphNodes{
+					InferenceGraphRootNode: {
+						RouterType: InferenceGraphSequence,
+						Steps: []*InferenceGraphStep{
+							endpointStep(),
+							{NodeName: "ensemble", Data: "$response"},
+						},
+					},
+					"ensemble": {
+						RouterType: InferenceGraphEnsemble,
+						Steps:      []*InferenceGraphStep{endpointStep()},
+					},
+				},
+			},
+		},
+		{
+			desc:     "Should fail for invalid name",
+			graph:    &InferenceGraph{Name: "My_Graph"},
+			errorMsg: `invalid inference graph name "My_Graph": must consist of lower case alphanumeric characters or '-'`,
+		},
+		{
+			desc: "Should fail without root node",
+			graph: &InferenceGraph{
+				Name:  "my-graph",
+				Nodes: InferenceGraphNodes{"other": {RouterType: InferenceGraphSequence, Steps: []*InferenceGraphStep{endpointStep()}}},
+			},
+			errorMsg: `inference graph must have a "root" node`,
+		},
+		{
+			desc: "Should fail for unsupported router type",
+			graph: &InferenceGraph{
+				Name:  "my-graph",
+				Nodes: InferenceGraphNodes{InferenceGraphRootNode: {RouterType: "Splitter", Steps: []*InferenceGraphStep{endpointStep()}}},
+			},
+			errorMsg: `node root has unsupported router type: "Splitter"`,
+		},
+		{
+			desc: "Should fail if step targets both endpoint and node",
+			graph: &InferenceGraph{
+				Name: "my-graph",
+				Nodes: InferenceGraphNodes{InferenceGraphRootNode: {
+					RouterType: InferenceGraphSequence,
+					Steps:      []*InferenceGraphStep{{VersionEndpointID: &endpointID, NodeName: InferenceGraphRootNode}},
+				}},
+			},
+			errorMsg: "step 0 of node root must specify either version_endpoint_id or node_name",
+		},
+		{
+			desc: "Should fail if switch step has no condition",
+			graph: &InferenceGraph{
+				Name:  "my-graph",
+				Nodes: InferenceGraphNodes{InferenceGraphRootNode: {RouterType: InferenceGraphSwitch, Steps: []*InferenceGraphStep{endpointStep()}}},
+			},
+			errorMsg: "step 0 of switch node root must have a condition",
+		},
+		{
+			desc: "Should fail if nodes form a cycle",
+			graph: &InferenceGraph{
+				Name: "my-graph",
+				Nodes: InferenceGraphNodes{
+					InferenceGraphRootNode: {RouterType: InferenceGraphSequence, Steps: []*InferenceGraphStep{{NodeName: "next"}}},
+					"next":                 {RouterType: InferenceGraphSequence, Steps: []*InferenceGraphStep{{NodeName: InferenceGraphRootNode}}},
+				},
+			},
+			errorMsg: "inference graph has a cycle through node",
+		},
+		{
+			desc: "Should fail if alert has drift condition",
+			graph: &InferenceGraph{
+				Name:  "my-graph",
+				Nodes: InferenceGraphNodes{InferenceGraphRootNode: {RouterType: InferenceGraphSequence, Steps: []*InferenceGraphStep{endpointStep()}}},
+				Alert: &InferenceGraphAlert{AlertConditions: AlertConditions{{Enabled: true, MetricType: AlertConditionTypeDrift}}},
+			},
+			errorMsg: "invalid alert condition 0: drift is not supported by inference graph",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := tC.graph.Validate()
+			if tC.errorMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tC.errorMsg)
+		})
+	}
+}
+
+func TestInferenceGraph_VersionEndpointIDs(t *testing.T) {
+	id1, id2 := uuid.New(), uuid.New()
+	graph := &InferenceGraph{
+		Nodes: InferenceGraphNodes{
+			InferenceGraphRootNode: {Steps: []*InferenceGraphStep{{VersionEndpointID: &id1}, {NodeName: "other"}}},
+			"other":                {Steps: []*InferenceGraphStep{{VersionEndpointID: &id1}, {VersionEndpointID: &id2}}},
+		},
+	}
+	assert.ElementsMatch(t, []uuid.UUID{id1, id2}, graph.VersionEndpointIDs())
+}
+
+func TestInferenceGraph_SetMessage(t *testing.T) {
+	graph := &InferenceGraph{}
+	graph.SetMessage("failed")
+	assert.Equal(t, "failed", graph.Message)
+
+	graph.SetMessage(strings.Repeat("é", 3000))
+	assert.Equal(t, strings.Repeat("é", 2048), graph.Message)
+}
+
+func TestInferenceGraph_ModelEndpointAlert(t *testing.T) {
+	project := mlp.Project{ID: 1, Name: "project"}
+	env := &Environment{Name: "env1", Cluster: "cluster1"}
+	graph := &InferenceGraph{ProjectID: 1, Name: "my-graph", Environment: env, EnvironmentName: "env1"}
+	assert.Nil(t, graph.ModelEndpointAlert(project))
+
+	conditions := AlertConditions{{Enabled: true, MetricType: AlertConditionTypeThroughput, Severity: AlertConditionSeverityWarning, Target: 1}}
+	graph.Alert = &InferenceGraphAlert{TeamName: "dsp", AlertConditions: conditions}
+
+	alert := graph.ModelEndpointAlert(project)
+	assert.Equal(t, "my-graph-graph", alert.Model.Name)
+	assert.Equal(t, project, alert.Model.Project)
+	assert.Equal(t, env, alert.ModelEndpoint.Environment)
+	assert.Equal(t, "env1", alert.EnvironmentName)
+	assert.Equal(t, "dsp", alert.TeamName)
+	assert.Equal(t, conditions, alert.AlertConditions)
+}
@@ -18,7 +18,9 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
 	"github.com/caraml-dev/merlin/pkg/transformer/spec"
 	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
 )
@@ -74,6 +76,33 @@ func (plc *PredictionLoggerConfig) ToPredictionLogConfig() *spec.PredictionLogCo
 type LoggerConfig struct {
 	Enabled bool       `json:"enabled"`
 	Mode    LoggerMode `json:"mode"`
+
+	// Optional sampling, redaction and truncation of the logged payloads
+	Sampling       *logpolicy.Sampling   `json:"sampling,omitempty"`
+	Redactions     []logpolicy.Redaction `json:"redactions,omitempty"`
+	MaxPayloadSize int                   `json:"max_payload_size,omitempty"`
+}
+
+func (lc LoggerConfig) Value() (driver.Value, error) {
+	return json.Marshal(lc)
+}
+
+func (lc *LoggerConfig) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &lc)
+}
+
+// LogPolicy returns the sampling, redaction and truncation policy of the inference logger
+func (lc *LoggerConfig) LogPolicy() *logpolicy.Policy {
+	return &logpolicy.Policy{
+		Sampling:       lc.Sampling,
+		Redactions:     lc.Redactions,
+		MaxPayloadSize: lc.MaxPayloadSize,
+	}
 }
 
 func (lc *LoggerConfig) SanitizeMode() {
@@ -82,6 +111,25 @@ func (lc *LoggerConfig) SanitizeMode() {
 	}
 }
 
+// Validate checks the log policies of the model and transformer loggers
+func (logger *Logger) Validate() error {
+	if logger == nil {
+		return nil
+	}
+
+	if logger.Model != nil {
+		if err := logger.Model.LogPolicy().Validate(); err != nil {
+			return fmt.Errorf("invalid model logger config: %w", err)
+		}
+	}
+	if logger.Transformer != nil {
+		if err := logger.Transformer.LogPolicy().Validate(); err != nil {
+			return fmt.Errorf("invalid transformer logger config: %w", err)
+		}
+	}
+	return nil
+}
+
 func ToKFServingLoggerMode(mode LoggerMode) kservev1beta1.LoggerType {
 
 	loggerMode := kservev1beta1.LogAll
@@ -36,10 +36,11 @@ const (
 	// orchestratorValue is the value of the orchestrator (which is Merlin)
 	orchestratorValue = "merlin"
 
-	ComponentBatchJob      = "batch-job"
-	ComponentImageBuilder  = "image-builder"
-	ComponentModelEndpoint = "model-endpoint"
-	ComponentModelVersion  = "model-version"
+	ComponentBatchJob       = "batch-job"
+	ComponentImageBuilder   = "image-builder"
+	ComponentInferenceGraph = "inference-graph"
+	ComponentModelEndpoint  = "model-endpoint"
+	ComponentModelVersion   = "model-version"
 )
 
 var reservedKeys = map[string]bool{
@@ -19,10 +19,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/caraml-dev/merlin/pkg/protocol"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/promql"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -31,6 +35,8 @@ import (
 
 const (
 	defaultAlertForDuration = "5m"
+	sloAlertForDuration     = "2m"
+	defaultSLOPeriod        = "28d"
 )
 
 const (
@@ -40,6 +46,14 @@ const (
 	errorRateSliExprGRPCFormat = "(100 * sum(rate(istio_requests_total{cluster_name=\"%s\",destination_service_name=~\"%s.*\",destination_workload_namespace=\"%s\",grpc_response_status!=\"0\",request_protocol=\"grpc\"}[1m])) / sum(rate(istio_requests_total{cluster_name=\"%s\",destination_service_name=~\"%s.*\",destination_workload_namespace=\"%s\",request_protocol=\"grpc\"}[1m])))"
 	cpuSliExprFormat           = "(100 * sum(rate(container_cpu_usage_seconds_total{cluster_name=\"%s\",namespace=\"%s\",pod=~\".*%s.*\",container!~\"|POD\"}[1m])) / sum(kube_pod_container_resource_requests{resource=\"cpu\",cluster_name=\"%s\",namespace=\"%s\",pod=~\".*%s.*\",container!~\"|POD\"}))"
 	memorySliExprFormat        = "(100 * sum(container_memory_usage_bytes{cluster_name=\"%s\",namespace=\"%s\",pod=~\".*%s.*\",container!~\"|POD\"}) / sum(kube_pod_container_resource_requests{resource=\"memory\",cluster_name=\"%s\",namespace=\"%s\",pod=~\".*%s.*\",container!~\"|POD\"}))"
+	driftSliExprFormat         = "max by(version, feature) (merlin_model_feature_drift{project=\"%s\",model=\"%s\",statistic=\"%s\"})"
+)
+
+// Error ratios of the SLOs over a window
+const (
+	availabilityErrorRatioHTTPFormat = "sum(rate(istio_requests_total{cluster_name=\"%[1]s\",destination_service_name=~\"%[2]s.*\",destination_workload_namespace=\"%[3]s\",response_code!=\"200\",request_protocol=\"http\"}[%[4]s])) / sum(rate(istio_requests_total{cluster_name=\"%[1]s\",destination_service_name=~\"%[2]s.*\",destination_workload_namespace=\"%[3]s\",request_protocol=\"http\"}[%[4]s]))"
+	availabilityErrorRatioGRPCFormat = "sum(rate(istio_requests_total{cluster_name=\"%[1]s\",destination_service_name=~\"%[2]s.*\",destination_workload_namespace=\"%[3]s\",grpc_response_status!=\"0\",request_protocol=\"grpc\"}[%[4]s])) / sum(rate(istio_requests_total{cluster_name=\"%[1]s\",destination_service_name=~\"%[2]s.*\",destination_workload_namespace=\"%[3]s\",request_protocol=\"grpc\"}[%[4]s]))"
+	latencyErrorRatioFormat          = "1 - sum(rate(revision_request_latencies_bucket{cluster_name=\"%[1]s\",namespace_name=\"%[2]s\",revision_name=~\".*%[3]s.*\",le=\"%[4]s\"}[%[5]s])) / sum(rate(revision_request_latencies_count{cluster_name=\"%[1]s\",namespace_name=\"%[2]s\",revision_name=~\".*%[3]s.*\"}[%[5]s]))"
 )
 
 const (
@@ -48,8 +62,41 @@ const (
 	errorRateSummary  = "Error rate of %s model in %s is higher than %.2f%%. Current value is {{ $value }}%%."
 	cpuSummary        = "CPU usage of %s model in %s is higher than %.2f%%. Current value is {{ $value }}%%."
 	memorySummary     = "Memory usage of %s model in %s is higher than %.2f%%. Current value is {{ $value }}%%."
+	driftSummary      = "%s drift of {{ $labels.feature }} of %s model version {{ $labels.version }} is higher than %.2f. Current value is {{ $value }}."
+	customSummary     = "%s of %s model in %s is firing. Current value is {{ $value }}."
+	sloSummary        = "%s of %s model in %s is burning the error budget of its %.2f%% SLO over %s too fast. Current error ratio is {{ $value }}."
 )
 
+// burnRateWindow is a pair of windows over which the error budget of an SLO burning too fast is alerted on, see
+// https://sre.google/workbook/alerting-on-slos/#6-multiwindow-multi-burn-rate-alerts
+type burnRateWindow struct {
+	long  time.Duration
+	short time.Duration
+	// budget is the share of the error budget of the SLO period consumed over the long window
+	budget float64
+}
+
+// burnRateWindows are the windows alerted on by severity, the critical alert pages and the warning one is a ticket
+var burnRateWindows = []struct {
+	severity AlertConditionSeverity
+	windows  []burnRateWindow
+}{
+	{
+		severity: AlertConditionSeverityCritical,
+		windows: []burnRateWindow{
+			{long: time.Hour, short: 5 * time.Minute, budget: 0.02},
+			{long: 6 * time.Hour, short: 30 * time.Minute, budget: 0.05},
+		},
+	},
+	{
+		severity: AlertConditionSeverityWarning,
+		windows: []burnRateWindow{
+			{long: 24 * time.Hour, short: 2 * time.Hour, budget: 0.1},
+			{long: 72 * time.Hour, short: 6 * time.Hour, budget: 0.1},
+		},
+	},
+}
+
 type ModelEndpointAlert struct {
 	ID              ID              `json:"-"`
 	ModelID         ID              `json:"model_id"`
@@ -59,9 +106,18 @@ type ModelEndpointAlert struct {
 	EnvironmentName string          `json:"environment_name"`
 	TeamName        string          `json:"team_name"`
 	AlertConditions AlertConditions `json:"alert_conditions"`
+	SLOs            AlertSLOs       `json:"slos" gorm:"column:slos"`
+	// Routes are the teams notified of the alerts of a severity instead of TeamName
+	Routes AlertRoutes `json:"routes,omitempty" gorm:"column:routes"`
 	CreatedUpdated
 }
 
+// ModelEndpointAlertPreview is the Prometheus rules file generated for a model endpoint alert
+type ModelEndpointAlertPreview struct {
+	FileName string `json:"file_name"`
+	Content  string `json:"content"`
+}
+
 func (alert ModelEndpointAlert) Value() (driver.Value, error) {
 	return json.Marshal(alert)
 }
@@ -123,14 +179,10 @@ func (alert ModelEndpointAlert) ToPromAlertSpec(dashboardBaseURL string) (PromAl
 		exprNode.SetString(alert.prometheusExpr(*alertCondition))
 
 		rule := PromAlertRule{
-			Alert: alertNode,
-			Expr:  exprNode,
-			For:   defaultAlertForDuration,
-			Labels: PromAlertRuleLabels{
-				Owner:       alert.TeamName,
-				ServiceName: fmt.Sprintf("merlin_%s_%s_%s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName),
-				Severity:    strings.ToLower(string(alertCondition.Severity)),
-			},
+			Alert:  alertNode,
+			Expr:   exprNode,
+			For:    defaultAlertForDuration,
+			Labels: alert.ruleLabels(alertCondition.Severity),
 			Annotations: PromAlertRuleAnnotations{
 				Summary:   alert.summary(*alertCondition),
 				Playbook:  "TODO",
@@ -140,6 +192,40 @@ func (alert ModelEndpointAlert) ToPromAlertSpec(dashboardBaseURL string) (PromAl
 		rules = append(rules, rule)
 	}
 
+	for _, slo := range alert.SLOs {
+		if !slo.Enabled {
+			continue
+		}
+
+		period, err := slo.period()
+		if err != nil {
+			return PromAlert{}, err
+		}
+		for _, severityWindows := range burnRateWindows {
+			alertNode := yamlv3.Node{
+				Style: yamlv3.DoubleQuotedStyle,
+			}
+			alertNode.SetString(slo.alertName(alert.Model.Name, severityWindows.severity))
+
+			exprNode := yamlv3.Node{
+				Style: yamlv3.LiteralStyle,
+			}
+			exprNode.SetString(alert.burnRateExpr(*slo, period, severityWindows.windows))
+
+			rules = append(rules, PromAlertRule{
+				Alert:  alertNode,
+				Expr:   exprNode,
+				For:    sloAlertForDuration,
+				Labels: alert.ruleLabels(severityWindows.severity),
+				Annotations: PromAlertRuleAnnotations{
+					Summary:   alert.sloSummary(*slo),
+					Playbook:  "TODO",
+					Dashboard: alert.dashboardLink(dashboardBaseURL),
+				},
+			})
+		}
+	}
+
 	spec := PromAlert{
 		Groups: []PromAlertGroup{
 			{
@@ -166,6 +252,41 @@ func (alert ModelEndpointAlert) ToPromAlertSpec(dashboardBaseURL string) (PromAl
 	return spec, nil
 }
 
+// Validate checks the custom conditions, the SLOs and the routes of the alert
+func (alert ModelEndpointAlert) Validate() error {
+	for i, alertCondition := range alert.AlertConditions {
+		if err := alertCondition.validate(); err != nil {
+			return fmt.Errorf("invalid alert condition %d: %w", i, err)
+		}
+	}
+	for i, slo := range alert.SLOs {
+		if err := slo.validate(); err != nil {
+			return fmt.Errorf("invalid SLO %d: %w", i, err)
+		}
+	}
+	for severity, teamName := range alert.Routes {
+		if severity != AlertConditionSeverityWarning && severity != AlertConditionSeverityCritical {
+			return fmt.Errorf("invalid route: unknown severity %s", severity)
+		}
+		if teamName == "" {
+			return fmt.Errorf("invalid route: team of severity %s is required", severity)
+		}
+	}
+	return nil
+}
+
+func (alert ModelEndpointAlert) ruleLabels(severity AlertConditionSeverity) PromAlertRuleLabels {
+	owner := alert.TeamName
+	if teamName, ok := alert.Routes[severity]; ok {
+		owner = teamName
+	}
+	return PromAlertRuleLabels{
+		Owner:       owner,
+		ServiceName: fmt.Sprintf("merlin_%s_%s_%s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName),
+		Severity:    strings.ToLower(string(severity)),
+	}
+}
+
 func (alert ModelEndpointAlert) sliExpr(alertCondition AlertCondition) string {
 	switch alertCondition.MetricType {
 	case AlertConditionTypeThroughput:
@@ -204,6 +325,11 @@ func (alert ModelEndpointAlert) sliExpr(alertCondition AlertCondition) string {
 			alert.ModelEndpoint.Environment.Cluster, alert.Model.Project.Name, alert.Model.Name,
 			alert.ModelEndpoint.Environment.Cluster, alert.Model.Project.Name, alert.Model.Name,
 		)
+	case AlertConditionTypeDrift:
+		return fmt.Sprintf(
+			driftSliExprFormat,
+			alert.Model.Project.Name, alert.Model.Name, alertCondition.driftStatistic(),
+		)
 	default:
 		return ""
 	}
@@ -221,12 +347,18 @@ func (alert ModelEndpointAlert) sliTarget(alertCondition AlertCondition) string
 		return fmt.Sprint(alertCondition.Target)
 	case AlertConditionTypeMemory:
 		return fmt.Sprint(alertCondition.Target)
+	case AlertConditionTypeDrift:
+		return fmt.Sprint(alertCondition.Target)
 	default:
 		return "0"
 	}
 }
 
 func (alert ModelEndpointAlert) prometheusExpr(alertCondition AlertCondition) string {
+	if alertCondition.MetricType == AlertConditionTypeCustom {
+		return alertCondition.Expr
+	}
+
 	operator := ">"
 	if alertCondition.MetricType == AlertConditionTypeThroughput {
 		operator = "<"
@@ -261,11 +393,70 @@ func (alert ModelEndpointAlert) summary(alertCondition AlertCondition) string {
 			memorySummary,
 			alert.Model.Name, alert.EnvironmentName, alertCondition.Target,
 		)
+	case AlertConditionTypeDrift:
+		return fmt.Sprintf(
+			driftSummary,
+			driftStatisticNames[alertCondition.driftStatistic()], alert.Model.Name, alertCondition.Target,
+		)
+	case AlertConditionTypeCustom:
+		return fmt.Sprintf(
+			customSummary,
+			alertCondition.Name, alert.Model.Name, alert.EnvironmentName,
+		)
 	default:
 		return ""
 	}
 }
 
+// errorRatio returns the share of the requests not meeting the SLO over a window
+func (alert ModelEndpointAlert) errorRatio(slo AlertSLO, window time.Duration) string {
+	rangeWindow := prommodel.Duration(window).String()
+	switch slo.Type {
+	case AlertSLOTypeAvailability:
+		format := availabilityErrorRatioHTTPFormat
+		if alert.ModelEndpoint.Protocol == protocol.UpiV1 {
+			format = availabilityErrorRatioGRPCFormat
+		}
+		return fmt.Sprintf(format, alert.ModelEndpoint.Environment.Cluster, alert.Model.Name, alert.Model.Project.Name, rangeWindow)
+	case AlertSLOTypeLatency:
+		return fmt.Sprintf(
+			latencyErrorRatioFormat,
+			alert.ModelEndpoint.Environment.Cluster, alert.Model.Project.Name, alert.Model.Name,
+			strconv.FormatFloat(slo.LatencyThreshold, 'f', -1, 64), rangeWindow,
+		)
+	default:
+		return ""
+	}
+}
+
+// burnRateExpr fires if the error budget of the SLO burns too fast over both the long and the short window of any of the given windows
+func (alert ModelEndpointAlert) burnRateExpr(slo AlertSLO, period time.Duration, windows []burnRateWindow) string {
+	errorBudget := formatFloat((100 - slo.Objective) / 100)
+
+	conditions := make([]string, 0, len(windows))
+	for _, window := range windows {
+		burnRate := formatFloat(window.budget * float64(period) / float64(window.long))
+		conditions = append(conditions, fmt.Sprintf(
+			"((%s) > (%s * %s) and (%s) > (%s * %s))",
+			alert.errorRatio(slo, window.long), burnRate, errorBudget,
+			alert.errorRatio(slo, window.short), burnRate, errorBudget,
+		))
+	}
+	return strings.Join(conditions, " or ")
+}
+
+func (alert ModelEndpointAlert) sloSummary(slo AlertSLO) string {
+	return fmt.Sprintf(
+		sloSummary,
+		sloTypeNames[slo.Type], alert.Model.Name, alert.EnvironmentName, slo.Objective, slo.periodOrDefault(),
+	)
+}
+
+// formatFloat formats a float without the noise of the floating-point arithmetic
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1e9)/1e9, 'f', -1, 64)
+}
+
 func (alert ModelEndpointAlert) dashboardLink(dashboardBaseURL string) string {
 	url, _ := url.Parse(dashboardBaseURL)
 
@@ -285,6 +476,17 @@ func (alert ModelEndpointAlert) dashboardLink(dashboardBaseURL string) string {
 	return url.String()
 }
 
+var sloTypeNames = map[AlertSLOType]string{
+	AlertSLOTypeAvailability: "Availability",
+	AlertSLOTypeLatency:      "Latency",
+}
+
+var driftStatisticNames = map[DriftStatistic]string{
+	DriftStatisticPSI: "PSI",
+	DriftStatisticKS:  "KS",
+	DriftStatisticJS:  "Jensen-Shannon",
+}
+
 type AlertConditions []*AlertCondition
 
 func (ac AlertConditions) Value() (driver.Value, error) {
@@ -307,6 +509,35 @@ type AlertCondition struct {
 	Target     float64                  `json:"target"`
 	Percentile float64                  `json:"percentile"`
 	Unit       string                   `json:"unit"`
+	// DriftStatistic is the statistic a drift condition is evaluated on, psi if empty
+	DriftStatistic DriftStatistic `json:"drift_statistic,omitempty"`
+	// Name of a custom condition
+	Name string `json:"name,omitempty"`
+	// Expr is the PromQL expression of a custom condition, the alert fires when it returns any series
+	Expr string `json:"expr,omitempty"`
+}
+
+func (ac AlertCondition) validate() error {
+	if ac.MetricType != AlertConditionTypeCustom {
+		return nil
+	}
+	if ac.Name == "" {
+		return errors.New("name of custom condition is required")
+	}
+	if ac.Expr == "" {
+		return errors.New("expr of custom condition is required")
+	}
+	if _, err := promql.ParseExpr(ac.Expr); err != nil {
+		return fmt.Errorf("invalid expr: %w", err)
+	}
+	return nil
+}
+
+func (ac AlertCondition) driftStatistic() DriftStatistic {
+	if ac.DriftStatistic == "" {
+		return DriftStatisticPSI
+	}
+	return ac.DriftStatistic
 }
 
 func (ac AlertCondition) alertName(modelName string) string {
@@ -318,6 +549,9 @@ func (ac AlertCondition) alertName(modelName string) string {
 	if ac.MetricType == AlertConditionTypeLatency {
 		name = fmt.Sprintf("[merlin] %s: %.2fp %s %s", modelName, ac.Percentile, metricType, strings.ToLower(string(ac.Severity)))
 	}
+	if ac.MetricType == AlertConditionTypeCustom {
+		name = fmt.Sprintf("[merlin] %s: %s %s", modelName, ac.Name, strings.ToLower(string(ac.Severity)))
+	}
 
 	return name
 }
@@ -330,6 +564,8 @@ const (
 	AlertConditionTypeErrorRate  AlertConditionMetricType = "error_rate"
 	AlertConditionTypeCPU        AlertConditionMetricType = "cpu"
 	AlertConditionTypeMemory     AlertConditionMetricType = "memory"
+	AlertConditionTypeDrift      AlertConditionMetricType = "drift"
+	AlertConditionTypeCustom     AlertConditionMetricType = "custom"
 )
 
 type AlertConditionSeverity string
@@ -338,3 +574,95 @@ const (
 	AlertConditionSeverityWarning  AlertConditionSeverity = "WARNING"
 	AlertConditionSeverityCritical AlertConditionSeverity = "CRITICAL"
 )
+
+type AlertSLOs []*AlertSLO
+
+func (slos AlertSLOs) Value() (driver.Value, error) {
+	return json.Marshal(slos)
+}
+
+func (slos *AlertSLOs) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &slos)
+}
+
+// AlertSLO is a service level objective of a model endpoint, alerted on when its error budget burns too fast
+type AlertSLO struct {
+	Enabled bool         `json:"enabled"`
+	Type    AlertSLOType `json:"type"`
+	// Objective is the percentage of good requests over the period, e.g. 99.9
+	Objective float64 `json:"objective"`
+	// Period of the SLO, 28d if empty
+	Period string `json:"period,omitempty"`
+	// LatencyThreshold is the latency in milliseconds under which a request is good, it must be a bucket boundary of
+	// the request latency histogram
+	LatencyThreshold float64 `json:"latency_threshold,omitempty"`
+}
+
+func (slo AlertSLO) periodOrDefault() string {
+	if slo.Period == "" {
+		return defaultSLOPeriod
+	}
+	return slo.Period
+}
+
+func (slo AlertSLO) period() (time.Duration, error) {
+	period := slo.periodOrDefault()
+	parsed, err := prommodel.ParseDuration(period)
+	if err != nil {
+		return 0, fmt.Errorf("invalid period %s: %w", period, err)
+	}
+	return time.Duration(parsed), nil
+}
+
+func (slo AlertSLO) validate() error {
+	if slo.Type != AlertSLOTypeAvailability && slo.Type != AlertSLOTypeLatency {
+		return fmt.Errorf("unknown type %s", slo.Type)
+	}
+	if slo.Objective <= 0 || slo.Objective >= 100 {
+		return fmt.Errorf("objective must be between 0 and 100 exclusive, got %v", slo.Objective)
+	}
+	period, err := slo.period()
+	if err != nil {
+		return err
+	}
+	// the longest burn rate window must fit in the period
+	if period < 72*time.Hour {
+		return fmt.Errorf("period must be at least 3d, got %s", slo.periodOrDefault())
+	}
+	if slo.Type == AlertSLOTypeLatency && slo.LatencyThreshold <= 0 {
+		return errors.New("latency SLO requires a positive latency threshold")
+	}
+	return nil
+}
+
+func (slo AlertSLO) alertName(modelName string, severity AlertConditionSeverity) string {
+	return fmt.Sprintf("[merlin] %s: %s SLO burn rate %s", modelName, sloTypeNames[slo.Type], strings.ToLower(string(severity)))
+}
+
+type AlertSLOType string
+
+const (
+	AlertSLOTypeAvailability AlertSLOType = "availability"
+	AlertSLOTypeLatency      AlertSLOType = "latency"
+)
+
+// AlertRoutes maps the severities to the teams notified of their alerts
+type AlertRoutes map[AlertConditionSeverity]string
+
+func (routes AlertRoutes) Value() (driver.Value, error) {
+	return json.Marshal(routes)
+}
+
+func (routes *AlertRoutes) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &routes)
+}
@@ -16,8 +16,10 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	yamlv3 "gopkg.in/yaml.v3"
 
 	"github.com/caraml-dev/merlin/mlp"
@@ -426,6 +428,134 @@ func TestModelEndpointAlert_ToPromAlertSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "drift",
+			fields: fields{
+				ModelID: 1,
+				Model: &Model{
+					Name: "model-1",
+					Project: mlp.Project{
+						Name: "project-1",
+					},
+				},
+				ModelEndpointID: ID(1),
+				ModelEndpoint: &ModelEndpoint{
+					ID: ID(1),
+					Environment: &Environment{
+						Cluster: "cluster-1",
+					},
+				},
+				EnvironmentName: "env-1",
+				TeamName:        "team-1",
+				AlertConditions: AlertConditions{
+					&AlertCondition{
+						Enabled:        true,
+						MetricType:     AlertConditionTypeDrift,
+						Severity:       AlertConditionSeverityCritical,
+						Target:         0.2,
+						DriftStatistic: DriftStatisticJS,
+					},
+				},
+			},
+			want: PromAlert{
+				Groups: []PromAlertGroup{
+					{
+						Name: "merlin_project-1_model-1_env-1",
+						Rules: []PromAlertRule{
+							{
+								Alert: yamlv3.Node{
+									Kind:  yamlv3.ScalarNode,
+									Style: yamlv3.DoubleQuotedStyle,
+									Tag:   "!!str",
+									Value: "[merlin] model-1: Drift critical",
+								},
+								Expr: yamlv3.Node{
+									Kind:  yamlv3.ScalarNode,
+									Style: yamlv3.LiteralStyle,
+									Tag:   "!!str",
+									Value: "max by(version, feature) (merlin_model_feature_drift{model=\"model-1\",project=\"project-1\",statistic=\"jensen_shannon\"}) > 0.2",
+								},
+								For: "5m",
+								Labels: PromAlertRuleLabels{
+									Owner:       "team-1",
+									ServiceName: "merlin_project-1_model-1_env-1",
+									Severity:    "critical",
+								},
+								Annotations: PromAlertRuleAnnotations{
+									Summary:   "Jensen-Shannon drift of {{ $labels.feature }} of model-1 model version {{ $labels.version }} is higher than 0.20. Current value is {{ $value }}.",
+									Dashboard: "https://monitoring.dev/graph/d/123456789/merlin-dashboard?var-cluster=cluster-1&var-model=model-1&var-project=project-1",
+									Playbook:  "TODO",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "custom",
+			fields: fields{
+				ModelID: 1,
+				Model: &Model{
+					Name: "model-1",
+					Project: mlp.Project{
+						Name: "project-1",
+					},
+				},
+				ModelEndpointID: ID(1),
+				ModelEndpoint: &ModelEndpoint{
+					ID: ID(1),
+					Environment: &Environment{
+						Cluster: "cluster-1",
+					},
+				},
+				EnvironmentName: "env-1",
+				TeamName:        "team-1",
+				AlertConditions: AlertConditions{
+					&AlertCondition{
+						Enabled:    true,
+						MetricType: AlertConditionTypeCustom,
+						Severity:   AlertConditionSeverityWarning,
+						Name:       "Low score",
+						Expr:       "avg(merlin_model_online_auc{project=\"project-1\",model=\"model-1\"}) < 0.7",
+					},
+				},
+			},
+			want: PromAlert{
+				Groups: []PromAlertGroup{
+					{
+						Name: "merlin_project-1_model-1_env-1",
+						Rules: []PromAlertRule{
+							{
+								Alert: yamlv3.Node{
+									Kind:  yamlv3.ScalarNode,
+									Style: yamlv3.DoubleQuotedStyle,
+									Tag:   "!!str",
+									Value: "[merlin] model-1: Low score warning",
+								},
+								Expr: yamlv3.Node{
+									Kind:  yamlv3.ScalarNode,
+									Style: yamlv3.LiteralStyle,
+									Tag:   "!!str",
+									Value: "avg(merlin_model_online_auc{model=\"model-1\",project=\"project-1\"}) < 0.7",
+								},
+								For: "5m",
+								Labels: PromAlertRuleLabels{
+									Owner:       "team-1",
+									ServiceName: "merlin_project-1_model-1_env-1",
+									Severity:    "warning",
+								},
+								Annotations: PromAlertRuleAnnotations{
+									Summary:   "Low score of model-1 model in env-1 is firing. Current value is {{ $value }}.",
+									Dashboard: "https://monitoring.dev/graph/d/123456789/merlin-dashboard?var-cluster=cluster-1&var-model=model-1&var-project=project-1",
+									Playbook:  "TODO",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -446,3 +576,154 @@ func TestModelEndpointAlert_ToPromAlertSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestModelEndpointAlert_ToPromAlertSpec_SLO(t *testing.T) {
+	alert := ModelEndpointAlert{
+		ModelID: 1,
+		Model: &Model{
+			Name: "model-1",
+			Project: mlp.Project{
+				Name: "project-1",
+			},
+		},
+		ModelEndpointID: ID(1),
+		ModelEndpoint: &ModelEndpoint{
+			ID: ID(1),
+			Environment: &Environment{
+				Cluster: "cluster-1",
+			},
+		},
+		EnvironmentName: "env-1",
+		TeamName:        "team-1",
+		Routes: AlertRoutes{
+			AlertConditionSeverityCritical: "team-1-oncall",
+		},
+		SLOs: AlertSLOs{
+			&AlertSLO{
+				Enabled:   true,
+				Type:      AlertSLOTypeAvailability,
+				Objective: 99.9,
+			},
+			&AlertSLO{
+				Enabled:          false,
+				Type:             AlertSLOTypeLatency,
+				Objective:        99,
+				LatencyThreshold: 100,
+			},
+		},
+	}
+
+	got, err := alert.ToPromAlertSpec(dashboardBaseURL)
+	require.NoError(t, err)
+	require.Len(t, got.Groups, 1)
+	rules := got.Groups[0].Rules
+	require.Len(t, rules, 2)
+
+	critical := rules[0]
+	assert.Equal(t, "[merlin] model-1: Availability SLO burn rate critical", critical.Alert.Value)
+	assert.Equal(t, "2m", critical.For)
+	assert.Equal(t, PromAlertRuleLabels{Owner: "team-1-oncall", ServiceName: "merlin_project-1_model-1_env-1", Severity: "critical"}, critical.Labels)
+	assert.Equal(t, "Availability of model-1 model in env-1 is burning the error budget of its 99.90% SLO over 28d too fast. Current error ratio is {{ $value }}.", critical.Annotations.Summary)
+	for _, fragment := range []string{"[1h]", "[5m]", "[6h]", "[30m]", "(13.44 * 0.001)", "(5.6 * 0.001)", `response_code!="200"`} {
+		assert.Contains(t, critical.Expr.Value, fragment)
+	}
+
+	warning := rules[1]
+	assert.Equal(t, "[merlin] model-1: Availability SLO burn rate warning", warning.Alert.Value)
+	assert.Equal(t, PromAlertRuleLabels{Owner: "team-1", ServiceName: "merlin_project-1_model-1_env-1", Severity: "warning"}, warning.Labels)
+	for _, fragment := range []string{"[1d]", "[2h]", "[3d]", "[6h]", "(2.8 * 0.001)", "(0.933333333 * 0.001)"} {
+		assert.Contains(t, warning.Expr.Value, fragment)
+	}
+}
+
+func TestAlertSLO_period(t *testing.T) {
+	period, err := AlertSLO{}.period()
+	require.NoError(t, err)
+	assert.Equal(t, 28*24*time.Hour, period)
+
+	period, err = AlertSLO{Period: "7d"}.period()
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, period)
+}
+
+func TestModelEndpointAlert_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		alert   ModelEndpointAlert
+		wantErr string
+	}{
+		{
+			name: "valid",
+			alert: ModelEndpointAlert{
+				AlertConditions: AlertConditions{
+					{MetricType: AlertConditionTypeThroughput, Target: 10},
+					{MetricType: AlertConditionTypeCustom, Name: "Low AUC", Expr: "merlin_model_online_auc < 0.7"},
+				},
+				SLOs: AlertSLOs{
+					{Type: AlertSLOTypeAvailability, Objective: 99.9},
+					{Type: AlertSLOTypeLatency, Objective: 99, Period: "7d", LatencyThreshold: 100},
+				},
+				Routes: AlertRoutes{AlertConditionSeverityCritical: "oncall"},
+			},
+		},
+		{
+			name: "custom condition without expr",
+			alert: ModelEndpointAlert{
+				AlertConditions: AlertConditions{{MetricType: AlertConditionTypeCustom, Name: "Low AUC"}},
+			},
+			wantErr: "invalid alert condition 0: expr of custom condition is required",
+		},
+		{
+			name: "custom condition with invalid expr",
+			alert: ModelEndpointAlert{
+				AlertConditions: AlertConditions{{MetricType: AlertConditionTypeCustom, Name: "Low AUC", Expr: "merlin_model_online_auc <"}},
+			},
+			wantErr: "invalid alert condition 0: invalid expr: parse error at char 26: no valid expression found",
+		},
+		{
+			name: "SLO with unknown type",
+			alert: ModelEndpointAlert{
+				SLOs: AlertSLOs{{Type: "throughput", Objective: 99}},
+			},
+			wantErr: "invalid SLO 0: unknown type throughput",
+		},
+		{
+			name: "SLO with invalid objective",
+			alert: ModelEndpointAlert{
+				SLOs: AlertSLOs{{Type: AlertSLOTypeAvailability, Objective: 100}},
+			},
+			wantErr: "invalid SLO 0: objective must be between 0 and 100 exclusive, got 100",
+		},
+		{
+			name: "SLO with short period",
+			alert: ModelEndpointAlert{
+				SLOs: AlertSLOs{{Type: AlertSLOTypeAvailability, Objective: 99, Period: "1d"}},
+			},
+			wantErr: "invalid SLO 0: period must be at least 3d, got 1d",
+		},
+		{
+			name: "latency SLO without threshold",
+			alert: ModelEndpointAlert{
+				SLOs: AlertSLOs{{Type: AlertSLOTypeLatency, Objective: 99}},
+			},
+			wantErr: "invalid SLO 0: latency SLO requires a positive latency threshold",
+		},
+		{
+			name: "route of unknown severity",
+			alert: ModelEndpointAlert{
+				Routes: AlertRoutes{"INFO": "team-1"},
+			},
+			wantErr: "invalid route: unknown severity INFO",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.alert.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
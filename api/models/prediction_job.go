@@ -17,6 +17,7 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -38,9 +39,34 @@ type PredictionJob struct {
 	Config          *Config      `json:"config,omitempty"`
 	Status          State        `json:"status"`
 	Error           string       `json:"error"`
+	// Attempt is the number of the current attempt, starting from 1
+	Attempt int `json:"attempt"`
+	// NextRetryAt is when the failed attempt is resubmitted, nil if no retry is pending
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
+	// Progress is the latest progress snapshot of the running spark application
+	Progress *PredictionJobProgress `json:"progress,omitempty"`
+	// QualityStatus is the outcome of the data quality checks, empty if the prediction job has none or hasn't completed
+	QualityStatus QualityStatus         `json:"quality_status,omitempty"`
+	Quality       *PredictionJobQuality `json:"quality,omitempty"`
+	// CostEstimation estimated monthly cost of running the prediction job
+	CostEstimation *CostEstimation `json:"cost_estimation,omitempty" gorm:"-"`
 	CreatedUpdated
 }
 
+// UpdateCostEstimation computes the estimated monthly cost of the prediction job
+// using the unit cost of the environment where it's running
+func (job *PredictionJob) UpdateCostEstimation() {
+	if job.Environment == nil || job.Environment.UnitCost == nil || job.Config == nil {
+		return
+	}
+
+	estimation, err := job.Environment.UnitCost.EstimatePredictionJobCost(job.Config.ResourceRequest)
+	if err != nil {
+		return
+	}
+	job.CostEstimation = estimation
+}
+
 type Config struct {
 	ServiceAccountName string                        `json:"service_account_name"`
 	JobConfig          *spec.PredictionJob           `json:"job_config"`
@@ -48,6 +74,14 @@ type Config struct {
 	MainAppPath        string                        `json:"main_app_path"`
 	ResourceRequest    *PredictionJobResourceRequest `json:"resource_request"`
 	EnvVars            EnvVars                       `json:"env_vars"`
+	RetryPolicy        *PredictionJobRetryPolicy     `json:"retry_policy,omitempty"`
+	// Mode is the way the prediction job is executed, spark if empty
+	Mode        PredictionJobMode     `json:"mode,omitempty"`
+	Lightweight *LightweightJobConfig `json:"lightweight,omitempty"`
+	// StandardTransformer configures prediction job in standard_transformer mode
+	StandardTransformer *StandardTransformerJobConfig `json:"standard_transformer,omitempty"`
+	// QualityChecks are evaluated on the output of the prediction job before it's marked as completed
+	QualityChecks *PredictionJobQualityChecks `json:"quality_checks,omitempty"`
 }
 
 type PredictionJobResourceRequest struct {
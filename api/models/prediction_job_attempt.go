@@ -0,0 +1,152 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FailureReason classifies why an attempt of a prediction job failed
+type FailureReason string
+
+const (
+	// FailureReasonPreemption the driver or executors were lost because their node was preempted or drained
+	FailureReasonPreemption FailureReason = "preemption"
+	// FailureReasonOutOfMemory the driver or executors were killed for exceeding their memory limit
+	FailureReasonOutOfMemory FailureReason = "out_of_memory"
+	// FailureReasonSubmission the spark application couldn't be submitted
+	FailureReasonSubmission FailureReason = "submission_failure"
+	// FailureReasonApplication the application itself failed, e.g. invalid model or source table
+	FailureReasonApplication FailureReason = "application_error"
+	// FailureReasonDataQuality the application completed but its output failed the data quality checks.
+	// It's never retried since another attempt would produce the same output.
+	FailureReasonDataQuality FailureReason = "data_quality"
+)
+
+const (
+	// DefaultRetryBackoff is the wait before the first retry if the retry policy doesn't set one
+	DefaultRetryBackoff = time.Minute
+	// MaxRetryAttempts is the maximum number of attempts a retry policy can allow
+	MaxRetryAttempts = 10
+)
+
+// DefaultRetryableReasons are retried if the retry policy doesn't list any, as they're transient
+var DefaultRetryableReasons = []FailureReason{FailureReasonPreemption, FailureReasonSubmission}
+
+var failureReasons = map[FailureReason]bool{
+	FailureReasonPreemption:  true,
+	FailureReasonOutOfMemory: true,
+	FailureReasonSubmission:  true,
+	FailureReasonApplication: true,
+}
+
+// preemptionMessages are substrings of spark and kubernetes errors caused by losing a node
+var preemptionMessages = []string{"preempt", "evicted", "node lost", "nodelost", "executorlostfailure", "node is shutting down"}
+
+// PredictionJobRetryPolicy resubmits a failed prediction job
+type PredictionJobRetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int `json:"max_attempts"`
+	// Backoff is the wait before the first retry, e.g. "5m". It doubles on every following retry.
+	Backoff string `json:"backoff,omitempty"`
+	// RetryableReasons are the failure reasons to retry, DefaultRetryableReasons if empty
+	RetryableReasons []FailureReason `json:"retryable_reasons,omitempty"`
+}
+
+// Validate checks the number of attempts, backoff, and failure reasons of the policy
+func (p *PredictionJobRetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 || p.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("max attempts must be between 1 and %d", MaxRetryAttempts)
+	}
+	if p.Backoff != "" {
+		backoff, err := time.ParseDuration(p.Backoff)
+		if err != nil || backoff < 0 {
+			return fmt.Errorf("invalid retry backoff: %s", p.Backoff)
+		}
+	}
+	for _, reason := range p.RetryableReasons {
+		if !failureReasons[reason] {
+			return fmt.Errorf("unknown failure reason: %s", reason)
+		}
+	}
+	return nil
+}
+
+// ShouldRetry returns true if the given attempt failed for a retryable reason and the policy allows another attempt
+func (p *PredictionJobRetryPolicy) ShouldRetry(attempt int, reason FailureReason) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	retryableReasons := p.RetryableReasons
+	if len(retryableReasons) == 0 {
+		retryableReasons = DefaultRetryableReasons
+	}
+	for _, retryable := range retryableReasons {
+		if retryable == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// BackoffAfter returns the wait before retrying the given failed attempt
+func (p *PredictionJobRetryPolicy) BackoffAfter(attempt int) time.Duration {
+	backoff := DefaultRetryBackoff
+	if p.Backoff != "" {
+		backoff, _ = time.ParseDuration(p.Backoff)
+	}
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
+
+// ClassifyFailure returns the failure reason of a failed attempt given its state and error message
+func ClassifyFailure(state State, errorMessage string) FailureReason {
+	if state == JobFailedSubmission {
+		return FailureReasonSubmission
+	}
+
+	message := strings.ToLower(errorMessage)
+	if strings.Contains(message, "oomkilled") || strings.Contains(message, "outofmemory") {
+		return FailureReasonOutOfMemory
+	}
+	for _, preemption := range preemptionMessages {
+		if strings.Contains(message, preemption) {
+			return FailureReasonPreemption
+		}
+	}
+	return FailureReasonApplication
+}
+
+// PredictionJobAttempt is a single submission of a prediction job
+type PredictionJobAttempt struct {
+	ID            ID            `json:"id"`
+	JobID         ID            `json:"job_id"`
+	Attempt       int           `json:"attempt"`
+	Status        State         `json:"status"`
+	Error         string        `json:"error,omitempty"`
+	FailureReason FailureReason `json:"failure_reason,omitempty"`
+	// SparkApplicationName, Namespace, and DriverPodName locate the attempt's resources and driver log
+	SparkApplicationName string     `json:"spark_application_name"`
+	Namespace            string     `json:"namespace"`
+	DriverPodName        string     `json:"driver_pod_name,omitempty"`
+	StartedAt            *time.Time `json:"started_at,omitempty"`
+	FinishedAt           *time.Time `json:"finished_at,omitempty"`
+	CreatedUpdated
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredictionJobRetryPolicy_ShouldRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *PredictionJobRetryPolicy
+		attempt int
+		reason  FailureReason
+		want    bool
+	}{
+		{
+			name:    "default retryable reason",
+			policy:  &PredictionJobRetryPolicy{MaxAttempts: 3},
+			attempt: 1,
+			reason:  FailureReasonPreemption,
+			want:    true,
+		},
+		{
+			name:    "default non retryable reason",
+			policy:  &PredictionJobRetryPolicy{MaxAttempts: 3},
+			attempt: 1,
+			reason:  FailureReasonApplication,
+			want:    false,
+		},
+		{
+			name:    "custom retryable reason",
+			policy:  &PredictionJobRetryPolicy{MaxAttempts: 3, RetryableReasons: []FailureReason{FailureReasonOutOfMemory}},
+			attempt: 2,
+			reason:  FailureReasonOutOfMemory,
+			want:    true,
+		},
+		{
+			name:    "no attempts left",
+			policy:  &PredictionJobRetryPolicy{MaxAttempts: 3},
+			attempt: 3,
+			reason:  FailureReasonPreemption,
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.ShouldRetry(tt.attempt, tt.reason))
+		})
+	}
+}
+
+func TestPredictionJobRetryPolicy_BackoffAfter(t *testing.T) {
+	assert.Equal(t, DefaultRetryBackoff, (&PredictionJobRetryPolicy{MaxAttempts: 3}).BackoffAfter(1))
+
+	policy := &PredictionJobRetryPolicy{MaxAttempts: 4, Backoff: "5m"}
+	assert.Equal(t, 5*time.Minute, policy.BackoffAfter(1))
+	assert.Equal(t, 10*time.Minute, policy.BackoffAfter(2))
+	assert.Equal(t, 20*time.Minute, policy.BackoffAfter(3))
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		state        State
+		errorMessage string
+		want         FailureReason
+	}{
+		{JobFailedSubmission, "failed to run spark-submit", FailureReasonSubmission},
+		{JobFailed, "driver container failed with ExitCode: 137, Reason: OOMKilled", FailureReasonOutOfMemory},
+		{JobFailed, "ExecutorLostFailure (executor 3 exited unrelated to the running tasks)", FailureReasonPreemption},
+		{JobFailed, "driver pod was Evicted", FailureReasonPreemption},
+		{JobFailed, "driver container failed with ExitCode: 1, Reason: Error", FailureReasonApplication},
+	}
+	for _, tt := range tests {
+		t.Run(tt.errorMessage, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyFailure(tt.state, tt.errorMessage))
+		})
+	}
+}
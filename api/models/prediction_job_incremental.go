@@ -0,0 +1,142 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+)
+
+const (
+	// WatermarkLayout is the format of the start and end watermark of an incremental source
+	WatermarkLayout = time.RFC3339
+	// SinkPartitionLayout is the format of the date partition of a partitioned sink
+	SinkPartitionLayout = "2006-01-02"
+)
+
+// IncrementalSource returns the incremental configuration of the BigQuery source, nil if the source reads the full table
+func (c *Config) IncrementalSource() *spec.IncrementalSource {
+	if c == nil {
+		return nil
+	}
+	return c.JobConfig.GetBigquerySource().GetIncremental()
+}
+
+// SinkPartition returns the date partition the sink writes to, nil if the sink isn't partitioned
+func (c *Config) SinkPartition() *spec.SinkPartition {
+	if c == nil || c.JobConfig == nil {
+		return nil
+	}
+	switch sink := c.JobConfig.Sink.(type) {
+	case *spec.PredictionJob_BigquerySink:
+		return sink.BigquerySink.GetPartition()
+	case *spec.PredictionJob_GcsSink:
+		return sink.GcsSink.GetPartition()
+	}
+	return nil
+}
+
+// EndWatermark returns the end watermark of the incremental source, false if the source isn't incremental
+// or the watermark isn't set
+func (c *Config) EndWatermark() (time.Time, bool) {
+	incremental := c.IncrementalSource()
+	if incremental == nil || incremental.EndWatermark == "" {
+		return time.Time{}, false
+	}
+	end, err := time.Parse(WatermarkLayout, incremental.EndWatermark)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+// ApplyWatermark sets the range of rows read by the incremental source to the ones after start, up to end.
+// The whole table up to end is read if start is nil.
+func (c *Config) ApplyWatermark(start *time.Time, end time.Time) {
+	incremental := c.IncrementalSource()
+	if incremental == nil {
+		return
+	}
+	incremental.StartWatermark = ""
+	if start != nil {
+		incremental.StartWatermark = start.UTC().Format(WatermarkLayout)
+	}
+	incremental.EndWatermark = end.UTC().Format(WatermarkLayout)
+}
+
+// ApplyIncrementalDefaults sets the end watermark of the incremental source and the date of the partitioned sink
+// to the given time if they're not set
+func (c *Config) ApplyIncrementalDefaults(t time.Time) {
+	if incremental := c.IncrementalSource(); incremental != nil && incremental.EndWatermark == "" {
+		incremental.EndWatermark = t.UTC().Format(WatermarkLayout)
+	}
+	if partition := c.SinkPartition(); partition != nil && partition.Value == "" {
+		partition.Value = t.Format(SinkPartitionLayout)
+	}
+}
+
+// ValidateIncremental checks the watermark range of the incremental source and the date partition of the sink
+func (c *Config) ValidateIncremental() error {
+	if incremental := c.IncrementalSource(); incremental != nil {
+		if incremental.WatermarkColumn == "" {
+			return errors.New("watermark column of incremental source is required")
+		}
+
+		var start time.Time
+		var err error
+		if incremental.StartWatermark != "" {
+			start, err = time.Parse(WatermarkLayout, incremental.StartWatermark)
+			if err != nil {
+				return fmt.Errorf("invalid start watermark %s: must be an RFC3339 timestamp", incremental.StartWatermark)
+			}
+		}
+		end, err := time.Parse(WatermarkLayout, incremental.EndWatermark)
+		if err != nil {
+			return fmt.Errorf("invalid end watermark %s: must be an RFC3339 timestamp", incremental.EndWatermark)
+		}
+		if incremental.StartWatermark != "" && !start.Before(end) {
+			return fmt.Errorf("start watermark %s must be before end watermark %s", incremental.StartWatermark, incremental.EndWatermark)
+		}
+	}
+
+	if partition := c.SinkPartition(); partition != nil {
+		if partition.Column == "" {
+			return errors.New("partition column of sink is required")
+		}
+		if _, err := time.Parse(SinkPartitionLayout, partition.Value); err != nil {
+			return fmt.Errorf("invalid sink partition %s: must be a date in YYYY-MM-DD format", partition.Value)
+		}
+
+		// the partition is always overwritten so rerunning the job is idempotent
+		switch c.sinkSaveMode() {
+		case spec.SaveMode_APPEND, spec.SaveMode_IGNORE:
+			return fmt.Errorf("partitioned sink always overwrites the partition, save mode %s is not supported", c.sinkSaveMode())
+		}
+	}
+	return nil
+}
+
+func (c *Config) sinkSaveMode() spec.SaveMode {
+	switch sink := c.JobConfig.Sink.(type) {
+	case *spec.PredictionJob_BigquerySink:
+		return sink.BigquerySink.GetSaveMode()
+	case *spec.PredictionJob_GcsSink:
+		return sink.GcsSink.GetSaveMode()
+	}
+	return spec.SaveMode_ERRORIFEXISTS
+}
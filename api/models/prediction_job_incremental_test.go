@@ -0,0 +1,119 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIncrementalConfig(incremental *spec.IncrementalSource, partition *spec.SinkPartition, saveMode spec.SaveMode) *Config {
+	return &Config{
+		JobConfig: &spec.PredictionJob{
+			Source: &spec.PredictionJob_BigquerySource{
+				BigquerySource: &spec.BigQuerySource{Table: "project.dataset.features", Incremental: incremental},
+			},
+			Sink: &spec.PredictionJob_BigquerySink{
+				BigquerySink: &spec.BigQuerySink{Table: "project.dataset.predictions", SaveMode: saveMode, Partition: partition},
+			},
+		},
+	}
+}
+
+func TestConfig_ValidateIncremental(t *testing.T) {
+	tests := []struct {
+		name        string
+		incremental *spec.IncrementalSource
+		partition   *spec.SinkPartition
+		saveMode    spec.SaveMode
+		wantErrMsg  string
+	}{
+		{
+			name: "full table",
+		},
+		{
+			name:        "incremental source and partitioned sink",
+			incremental: &spec.IncrementalSource{WatermarkColumn: "event_timestamp", StartWatermark: "2023-03-01T00:00:00Z", EndWatermark: "2023-03-02T00:00:00Z"},
+			partition:   &spec.SinkPartition{Column: "prediction_date", Value: "2023-03-02"},
+			saveMode:    spec.SaveMode_OVERWRITE,
+		},
+		{
+			name:        "missing watermark column",
+			incremental: &spec.IncrementalSource{EndWatermark: "2023-03-02T00:00:00Z"},
+			wantErrMsg:  "watermark column of incremental source is required",
+		},
+		{
+			name:        "invalid end watermark",
+			incremental: &spec.IncrementalSource{WatermarkColumn: "event_timestamp", EndWatermark: "2023-03-02"},
+			wantErrMsg:  "invalid end watermark 2023-03-02: must be an RFC3339 timestamp",
+		},
+		{
+			name:        "start watermark after end watermark",
+			incremental: &spec.IncrementalSource{WatermarkColumn: "event_timestamp", StartWatermark: "2023-03-02T00:00:00Z", EndWatermark: "2023-03-01T00:00:00Z"},
+			wantErrMsg:  "start watermark 2023-03-02T00:00:00Z must be before end watermark 2023-03-01T00:00:00Z",
+		},
+		{
+			name:       "missing partition column",
+			partition:  &spec.SinkPartition{Value: "2023-03-02"},
+			wantErrMsg: "partition column of sink is required",
+		},
+		{
+			name:       "invalid partition",
+			partition:  &spec.SinkPartition{Column: "prediction_date", Value: "20230302"},
+			wantErrMsg: "invalid sink partition 20230302: must be a date in YYYY-MM-DD format",
+		},
+		{
+			name:       "append to partition",
+			partition:  &spec.SinkPartition{Column: "prediction_date", Value: "2023-03-02"},
+			saveMode:   spec.SaveMode_APPEND,
+			wantErrMsg: "partitioned sink always overwrites the partition, save mode APPEND is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newIncrementalConfig(tt.incremental, tt.partition, tt.saveMode).ValidateIncremental()
+			if tt.wantErrMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestConfig_ApplyWatermark(t *testing.T) {
+	now := time.Date(2023, 3, 2, 2, 0, 0, 0, time.UTC)
+	config := newIncrementalConfig(&spec.IncrementalSource{WatermarkColumn: "event_timestamp"}, &spec.SinkPartition{Column: "prediction_date"}, spec.SaveMode_OVERWRITE)
+
+	config.ApplyWatermark(nil, now)
+	config.ApplyIncrementalDefaults(now)
+	assert.Equal(t, "", config.IncrementalSource().StartWatermark)
+	assert.Equal(t, "2023-03-02T02:00:00Z", config.IncrementalSource().EndWatermark)
+	assert.Equal(t, "2023-03-02", config.SinkPartition().Value)
+
+	end, ok := config.EndWatermark()
+	assert.True(t, ok)
+	assert.True(t, end.Equal(now))
+
+	start := now.Add(-24 * time.Hour)
+	config.ApplyWatermark(&start, now)
+	assert.Equal(t, "2023-03-01T02:00:00Z", config.IncrementalSource().StartWatermark)
+
+	_, ok = (&Config{}).EndWatermark()
+	assert.False(t, ok)
+}
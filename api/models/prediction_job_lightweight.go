@@ -0,0 +1,123 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/pkg/errors"
+
+	"github.com/caraml-dev/merlin/pkg/protocol"
+)
+
+// PredictionJobMode is the way a prediction job is executed
+type PredictionJobMode string
+
+const (
+	// PredictionJobModeSpark runs the prediction job as a spark application
+	PredictionJobModeSpark PredictionJobMode = "spark"
+	// PredictionJobModeLightweight runs the prediction job as a single pod kubernetes job, suitable for small datasets
+	PredictionJobModeLightweight PredictionJobMode = "lightweight"
+)
+
+const (
+	// DefaultLightweightConcurrency is the default number of batches predicted concurrently by a lightweight prediction job
+	DefaultLightweightConcurrency = 4
+	// DefaultLightweightBatchSize is the default number of rows per batch of a lightweight prediction job
+	DefaultLightweightBatchSize = 1000
+)
+
+// LightweightJobConfig configures a prediction job running in lightweight mode
+type LightweightJobConfig struct {
+	// EndpointID is the ID of a running version endpoint of the model version to send the batches to.
+	// If it's empty, the model is loaded in the job.
+	EndpointID string `json:"endpoint_id,omitempty"`
+	// EndpointURL and Protocol are resolved from the version endpoint
+	EndpointURL string            `json:"endpoint_url,omitempty"`
+	Protocol    protocol.Protocol `json:"protocol,omitempty"`
+	// Concurrency is the number of batches predicted concurrently
+	Concurrency int `json:"concurrency,omitempty"`
+	// BatchSize is the number of rows per batch
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// LightweightPredictionJobConfig is the configuration of lightweight prediction jobs in an environment
+type LightweightPredictionJobConfig struct {
+	Enabled        bool `json:"enabled"`
+	MaxConcurrency int  `json:"max_concurrency,omitempty"`
+	MaxBatchSize   int  `json:"max_batch_size,omitempty"`
+}
+
+func (c LightweightPredictionJobConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *LightweightPredictionJobConfig) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &c)
+}
+
+// IsLightweight returns true if the prediction job runs in lightweight mode
+func (job *PredictionJob) IsLightweight() bool {
+	return job.Config != nil && job.Config.Mode == PredictionJobModeLightweight
+}
+
+// ApplyDefaults sets the default concurrency and batch size
+func (c *LightweightJobConfig) ApplyDefaults() {
+	if c.Concurrency == 0 {
+		c.Concurrency = DefaultLightweightConcurrency
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = DefaultLightweightBatchSize
+	}
+}
+
+// Validate checks the lightweight configuration and source of a prediction job against the configuration of the environment
+func (c *LightweightJobConfig) Validate(envConfig *LightweightPredictionJobConfig, jobConfig *spec.PredictionJob) error {
+	if envConfig == nil || !envConfig.Enabled {
+		return errors.New("lightweight prediction job is not enabled in the environment")
+	}
+
+	if c.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", c.Concurrency)
+	}
+	if envConfig.MaxConcurrency > 0 && c.Concurrency > envConfig.MaxConcurrency {
+		return fmt.Errorf("concurrency %d exceeds the maximum of %d", c.Concurrency, envConfig.MaxConcurrency)
+	}
+	if c.BatchSize < 1 {
+		return fmt.Errorf("invalid batch size: %d", c.BatchSize)
+	}
+	if envConfig.MaxBatchSize > 0 && c.BatchSize > envConfig.MaxBatchSize {
+		return fmt.Errorf("batch size %d exceeds the maximum of %d", c.BatchSize, envConfig.MaxBatchSize)
+	}
+
+	if jobConfig != nil && jobConfig.GetBigquerySource() != nil {
+		return errors.New("lightweight prediction job only supports file source")
+	}
+	if source := jobConfig.GetGcsSource(); source != nil {
+		switch source.Format {
+		case spec.FileFormat_CSV, spec.FileFormat_PARQUET, spec.FileFormat_JSON:
+		default:
+			return fmt.Errorf("lightweight prediction job doesn't support %s source", source.Format)
+		}
+	}
+	return nil
+}
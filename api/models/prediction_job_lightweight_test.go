@@ -0,0 +1,74 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLightweightJobConfig_Validate(t *testing.T) {
+	envConfig := &LightweightPredictionJobConfig{Enabled: true, MaxConcurrency: 8, MaxBatchSize: 5000}
+	csvSource := &spec.PredictionJob{
+		Source: &spec.PredictionJob_GcsSource{GcsSource: &spec.GcsSource{Format: spec.FileFormat_CSV}},
+	}
+
+	tests := []struct {
+		name       string
+		config     LightweightJobConfig
+		envConfig  *LightweightPredictionJobConfig
+		jobConfig  *spec.PredictionJob
+		wantErrMsg string
+	}{
+		{
+			name:      "default config",
+			envConfig: envConfig,
+			jobConfig: csvSource,
+		},
+		{
+			name:       "environment without lightweight config",
+			jobConfig:  csvSource,
+			wantErrMsg: "lightweight prediction job is not enabled in the environment",
+		},
+		{
+			name:       "batch size exceeds maximum",
+			config:     LightweightJobConfig{BatchSize: 10000},
+			envConfig:  envConfig,
+			jobConfig:  csvSource,
+			wantErrMsg: "batch size 10000 exceeds the maximum of 5000",
+		},
+		{
+			name:      "avro source",
+			envConfig: envConfig,
+			jobConfig: &spec.PredictionJob{
+				Source: &spec.PredictionJob_GcsSource{GcsSource: &spec.GcsSource{Format: spec.FileFormat_AVRO}},
+			},
+			wantErrMsg: "lightweight prediction job doesn't support AVRO source",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ApplyDefaults()
+			err := tt.config.Validate(tt.envConfig, tt.jobConfig)
+			if tt.wantErrMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+}
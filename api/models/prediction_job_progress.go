@@ -0,0 +1,59 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PredictionJobProgress is a snapshot of the progress of the spark application of a running prediction job
+type PredictionJobProgress struct {
+	StagesTotal     int `json:"stages_total"`
+	StagesCompleted int `json:"stages_completed"`
+	TasksTotal      int `json:"tasks_total"`
+	TasksCompleted  int `json:"tasks_completed"`
+	TasksFailed     int `json:"tasks_failed"`
+	// Percentage is the percentage of completed tasks of the stages submitted so far
+	Percentage      float64   `json:"percentage"`
+	RowsRead        int64     `json:"rows_read"`
+	RowsWritten     int64     `json:"rows_written"`
+	ActiveExecutors int       `json:"active_executors"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// UpdatePercentage computes the percentage of completed tasks
+func (p *PredictionJobProgress) UpdatePercentage() {
+	if p.TasksTotal == 0 {
+		p.Percentage = 0
+		return
+	}
+	p.Percentage = float64(p.TasksCompleted) / float64(p.TasksTotal) * 100
+}
+
+func (p *PredictionJobProgress) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *PredictionJobProgress) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &p)
+}
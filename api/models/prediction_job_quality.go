@@ -0,0 +1,290 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// QualityCheckAction is what happens to a completed prediction job whose output fails a data quality check
+type QualityCheckAction string
+
+const (
+	// QualityCheckActionFail marks the prediction job as failed
+	QualityCheckActionFail QualityCheckAction = "fail"
+	// QualityCheckActionWarn keeps the prediction job completed with a warning quality status
+	QualityCheckActionWarn QualityCheckAction = "warn"
+)
+
+// QualityStatus is the outcome of the data quality checks of a prediction job
+type QualityStatus string
+
+const (
+	QualityStatusPassed  QualityStatus = "passed"
+	QualityStatusWarning QualityStatus = "warning"
+	QualityStatusFailed  QualityStatus = "failed"
+)
+
+const (
+	QualityCheckMetrics      = "metrics"
+	QualityCheckRowRatio     = "row_ratio"
+	QualityCheckNullFraction = "null_fraction"
+	QualityCheckMinValue     = "min_value"
+	QualityCheckMaxValue     = "max_value"
+	QualityCheckDrift        = "drift"
+)
+
+// psiEpsilon is the minimum share of a bin when computing the population stability index, to avoid dividing by zero
+const psiEpsilon = 0.0001
+
+// PredictionJobQualityChecks are evaluated on the output of a prediction job before it's marked as completed
+type PredictionJobQualityChecks struct {
+	// Action is taken when any check fails, fail if empty
+	Action QualityCheckAction `json:"action,omitempty"`
+	// MinRowRatio is the minimum ratio of rows written to the sink over rows read from the source
+	MinRowRatio *float64 `json:"min_row_ratio,omitempty"`
+	// MaxNullFraction is the maximum fraction of null values in the result column
+	MaxNullFraction *float64 `json:"max_null_fraction,omitempty"`
+	// MinValue and MaxValue bound the values of a numeric result column
+	MinValue *float64 `json:"min_value,omitempty"`
+	MaxValue *float64 `json:"max_value,omitempty"`
+	// Drift compares the distribution of the result column with the one of a reference prediction job
+	Drift *PredictionJobDriftCheck `json:"drift,omitempty"`
+}
+
+// PredictionJobDriftCheck bounds the population stability index of the result column against a reference prediction job
+type PredictionJobDriftCheck struct {
+	// ReferenceJobID is a completed prediction job of the same model having quality metrics
+	ReferenceJobID ID `json:"reference_job_id"`
+	// MaxPSI is the maximum population stability index, 0.2 is commonly considered a significant drift
+	MaxPSI float64 `json:"max_psi"`
+	// ReferenceQuantiles are the quantiles of the reference job when the prediction job is submitted, so that the
+	// prediction job can check the drift before writing the sink
+	ReferenceQuantiles []float64 `json:"reference_quantiles,omitempty"`
+}
+
+// PredictionJobQualityMetrics are computed by the prediction job on its predictions before writing them to the sink.
+// OutputRows is updated with the rows found in the sink once they're written, if the sink can tell them apart.
+type PredictionJobQualityMetrics struct {
+	SourceRows int64 `json:"source_rows"`
+	OutputRows int64 `json:"output_rows"`
+	// NullCount is the number of null values in the result column
+	NullCount int64 `json:"null_count"`
+	// Min, Max and Quantiles are only computed for a numeric result column.
+	// Quantiles are evenly spaced, from the minimum to the maximum.
+	Min       *float64  `json:"min,omitempty"`
+	Max       *float64  `json:"max,omitempty"`
+	Quantiles []float64 `json:"quantiles,omitempty"`
+}
+
+// PredictionJobQualityCheckResult is the result of a single data quality check
+type PredictionJobQualityCheckResult struct {
+	Name      string  `json:"name"`
+	Passed    bool    `json:"passed"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Message   string  `json:"message,omitempty"`
+}
+
+// PredictionJobQuality is the result of the data quality checks of a completed prediction job
+type PredictionJobQuality struct {
+	Metrics     *PredictionJobQualityMetrics      `json:"metrics,omitempty"`
+	Checks      []PredictionJobQualityCheckResult `json:"checks"`
+	EvaluatedAt time.Time                         `json:"evaluated_at"`
+}
+
+// Validate checks the thresholds of the quality checks
+func (c *PredictionJobQualityChecks) Validate() error {
+	switch c.Action {
+	case "", QualityCheckActionFail, QualityCheckActionWarn:
+	default:
+		return fmt.Errorf("unknown quality check action: %s", c.Action)
+	}
+	if c.MinRowRatio != nil && (*c.MinRowRatio < 0 || *c.MinRowRatio > 1) {
+		return fmt.Errorf("min row ratio must be between 0 and 1")
+	}
+	if c.MaxNullFraction != nil && (*c.MaxNullFraction < 0 || *c.MaxNullFraction > 1) {
+		return fmt.Errorf("max null fraction must be between 0 and 1")
+	}
+	if c.MinValue != nil && c.MaxValue != nil && *c.MinValue > *c.MaxValue {
+		return fmt.Errorf("min value %v must not be greater than max value %v", *c.MinValue, *c.MaxValue)
+	}
+	if c.Drift != nil {
+		if c.Drift.ReferenceJobID == 0 {
+			return fmt.Errorf("reference job of drift check is required")
+		}
+		if c.Drift.MaxPSI <= 0 {
+			return fmt.Errorf("max psi of drift check must be positive")
+		}
+	}
+	return nil
+}
+
+// Evaluate runs the checks on the metrics of a prediction job, reference is the metrics of the reference prediction job
+// of the drift check. It returns the result of every check and the resulting quality status.
+func (c *PredictionJobQualityChecks) Evaluate(metrics *PredictionJobQualityMetrics, reference *PredictionJobQualityMetrics) ([]PredictionJobQualityCheckResult, QualityStatus) {
+	var results []PredictionJobQualityCheckResult
+	if metrics == nil {
+		results = append(results, PredictionJobQualityCheckResult{Name: QualityCheckMetrics, Message: "quality metrics weren't reported by the prediction job"})
+		return results, c.status(results)
+	}
+
+	if c.MinRowRatio != nil {
+		ratio := 1.0
+		if metrics.SourceRows > 0 {
+			ratio = float64(metrics.OutputRows) / float64(metrics.SourceRows)
+		}
+		results = append(results, PredictionJobQualityCheckResult{Name: QualityCheckRowRatio, Passed: ratio >= *c.MinRowRatio, Value: ratio, Threshold: *c.MinRowRatio})
+	}
+
+	if c.MaxNullFraction != nil {
+		fraction := 0.0
+		if metrics.OutputRows > 0 {
+			fraction = float64(metrics.NullCount) / float64(metrics.OutputRows)
+		}
+		results = append(results, PredictionJobQualityCheckResult{Name: QualityCheckNullFraction, Passed: fraction <= *c.MaxNullFraction, Value: fraction, Threshold: *c.MaxNullFraction})
+	}
+
+	if c.MinValue != nil {
+		results = append(results, checkBound(QualityCheckMinValue, metrics.Min, *c.MinValue, func(value float64) bool { return value >= *c.MinValue }))
+	}
+	if c.MaxValue != nil {
+		results = append(results, checkBound(QualityCheckMaxValue, metrics.Max, *c.MaxValue, func(value float64) bool { return value <= *c.MaxValue }))
+	}
+
+	if c.Drift != nil {
+		results = append(results, c.checkDrift(metrics, reference))
+	}
+	return results, c.status(results)
+}
+
+func checkBound(name string, value *float64, threshold float64, passed func(float64) bool) PredictionJobQualityCheckResult {
+	if value == nil {
+		return PredictionJobQualityCheckResult{Name: name, Threshold: threshold, Message: "result column has no numeric value"}
+	}
+	return PredictionJobQualityCheckResult{Name: name, Passed: passed(*value), Value: *value, Threshold: threshold}
+}
+
+func (c *PredictionJobQualityChecks) checkDrift(metrics *PredictionJobQualityMetrics, reference *PredictionJobQualityMetrics) PredictionJobQualityCheckResult {
+	result := PredictionJobQualityCheckResult{Name: QualityCheckDrift, Threshold: c.Drift.MaxPSI}
+	if len(metrics.Quantiles) < 2 {
+		result.Message = "result column has no numeric value"
+		return result
+	}
+	if reference == nil || len(reference.Quantiles) < 2 {
+		result.Message = fmt.Sprintf("reference job %s has no quality metrics", c.Drift.ReferenceJobID)
+		return result
+	}
+
+	result.Value = PopulationStabilityIndex(reference.Quantiles, metrics.Quantiles)
+	result.Passed = result.Value <= c.Drift.MaxPSI
+	return result
+}
+
+func (c *PredictionJobQualityChecks) status(results []PredictionJobQualityCheckResult) QualityStatus {
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		if c.Action == QualityCheckActionWarn {
+			return QualityStatusWarning
+		}
+		return QualityStatusFailed
+	}
+	return QualityStatusPassed
+}
+
+// PopulationStabilityIndex compares two distributions given by their evenly spaced quantiles.
+// The bins are delimited by the inner quantiles of the reference distribution, and the share of the current distribution
+// in each bin is interpolated linearly between its quantiles.
+func PopulationStabilityIndex(reference []float64, current []float64) float64 {
+	edges := reference[1 : len(reference)-1]
+
+	psi := 0.0
+	lower := math.Inf(-1)
+	for i := 0; i <= len(edges); i++ {
+		upper := math.Inf(1)
+		if i < len(edges) {
+			upper = edges[i]
+		}
+
+		expected := math.Max(quantileCDF(reference, upper)-quantileCDF(reference, lower), psiEpsilon)
+		actual := math.Max(quantileCDF(current, upper)-quantileCDF(current, lower), psiEpsilon)
+		psi += (actual - expected) * math.Log(actual/expected)
+		lower = upper
+	}
+	return psi
+}
+
+// quantileCDF returns the share of a distribution given by its evenly spaced quantiles that is lower or equal to x
+func quantileCDF(quantiles []float64, x float64) float64 {
+	last := len(quantiles) - 1
+	if x < quantiles[0] {
+		return 0
+	}
+	if x >= quantiles[last] {
+		return 1
+	}
+	for i := last - 1; i > 0; i-- {
+		if x >= quantiles[i] {
+			return (float64(i) + (x-quantiles[i])/(quantiles[i+1]-quantiles[i])) / float64(last)
+		}
+	}
+	return (x - quantiles[0]) / (quantiles[1] - quantiles[0]) / float64(last)
+}
+
+// ApplyQuality fails a completed prediction job whose output failed its data quality checks. A prediction job which
+// failed because its predictions failed the checks before being written to the sink gets the same error.
+func (job *PredictionJob) ApplyQuality() {
+	if (job.Status == JobCompleted || job.Status == JobFailed) && job.QualityStatus == QualityStatusFailed && job.Quality != nil {
+		job.Status = JobFailed
+		job.Error = fmt.Sprintf("data quality checks failed: %s", job.Quality.Summary())
+	}
+}
+
+// Summary describes the failed checks of the quality result
+func (q *PredictionJobQuality) Summary() string {
+	var failed []string
+	for _, check := range q.Checks {
+		if check.Passed {
+			continue
+		}
+		if check.Message != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", check.Name, check.Message))
+		} else {
+			failed = append(failed, fmt.Sprintf("%s: %v (threshold %v)", check.Name, check.Value, check.Threshold))
+		}
+	}
+	return strings.Join(failed, ", ")
+}
+
+func (q *PredictionJobQuality) Value() (driver.Value, error) {
+	return json.Marshal(q)
+}
+
+func (q *PredictionJobQuality) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &q)
+}
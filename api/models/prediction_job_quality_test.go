@@ -0,0 +1,197 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+// deciles returns the 11 evenly spaced quantiles of a uniform distribution between min and max
+func deciles(min, max float64) []float64 {
+	quantiles := make([]float64, 11)
+	for i := range quantiles {
+		quantiles[i] = min + (max-min)*float64(i)/10
+	}
+	return quantiles
+}
+
+func TestPredictionJobQualityChecks_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     PredictionJobQualityChecks
+		wantErrMsg string
+	}{
+		{
+			name: "valid",
+			checks: PredictionJobQualityChecks{
+				Action:          QualityCheckActionWarn,
+				MinRowRatio:     float64Ptr(0.99),
+				MaxNullFraction: float64Ptr(0.01),
+				MinValue:        float64Ptr(0),
+				MaxValue:        float64Ptr(1),
+				Drift:           &PredictionJobDriftCheck{ReferenceJobID: 1, MaxPSI: 0.2},
+			},
+		},
+		{
+			name:       "unknown action",
+			checks:     PredictionJobQualityChecks{Action: "ignore"},
+			wantErrMsg: "unknown quality check action: ignore",
+		},
+		{
+			name:       "invalid null fraction",
+			checks:     PredictionJobQualityChecks{MaxNullFraction: float64Ptr(1.5)},
+			wantErrMsg: "max null fraction must be between 0 and 1",
+		},
+		{
+			name:       "invalid value range",
+			checks:     PredictionJobQualityChecks{MinValue: float64Ptr(1), MaxValue: float64Ptr(0)},
+			wantErrMsg: "min value 1 must not be greater than max value 0",
+		},
+		{
+			name:       "missing reference job",
+			checks:     PredictionJobQualityChecks{Drift: &PredictionJobDriftCheck{MaxPSI: 0.2}},
+			wantErrMsg: "reference job of drift check is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.checks.Validate()
+			if tt.wantErrMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestPredictionJobQualityChecks_Evaluate(t *testing.T) {
+	checks := PredictionJobQualityChecks{
+		MinRowRatio:     float64Ptr(0.9),
+		MaxNullFraction: float64Ptr(0.1),
+		MinValue:        float64Ptr(0),
+		MaxValue:        float64Ptr(1),
+		Drift:           &PredictionJobDriftCheck{ReferenceJobID: 1, MaxPSI: 0.2},
+	}
+	reference := &PredictionJobQualityMetrics{Quantiles: deciles(0, 1)}
+
+	tests := []struct {
+		name       string
+		action     QualityCheckAction
+		metrics    *PredictionJobQualityMetrics
+		reference  *PredictionJobQualityMetrics
+		wantFailed []string
+		wantStatus QualityStatus
+	}{
+		{
+			name:       "passed",
+			metrics:    &PredictionJobQualityMetrics{SourceRows: 100, OutputRows: 100, NullCount: 1, Min: float64Ptr(0), Max: float64Ptr(1), Quantiles: deciles(0, 1)},
+			reference:  reference,
+			wantStatus: QualityStatusPassed,
+		},
+		{
+			name:       "all null predictions",
+			metrics:    &PredictionJobQualityMetrics{SourceRows: 100, OutputRows: 100, NullCount: 100},
+			reference:  reference,
+			wantFailed: []string{QualityCheckNullFraction, QualityCheckMinValue, QualityCheckMaxValue, QualityCheckDrift},
+			wantStatus: QualityStatusFailed,
+		},
+		{
+			name:       "constant predictions out of range",
+			action:     QualityCheckActionWarn,
+			metrics:    &PredictionJobQualityMetrics{SourceRows: 100, OutputRows: 80, Min: float64Ptr(2), Max: float64Ptr(2), Quantiles: deciles(2, 2)},
+			reference:  reference,
+			wantFailed: []string{QualityCheckRowRatio, QualityCheckMaxValue, QualityCheckDrift},
+			wantStatus: QualityStatusWarning,
+		},
+		{
+			name:       "reference without metrics",
+			metrics:    &PredictionJobQualityMetrics{SourceRows: 100, OutputRows: 100, Min: float64Ptr(0), Max: float64Ptr(1), Quantiles: deciles(0, 1)},
+			wantFailed: []string{QualityCheckDrift},
+			wantStatus: QualityStatusFailed,
+		},
+		{
+			name:       "metrics not reported",
+			wantFailed: []string{QualityCheckMetrics},
+			wantStatus: QualityStatusFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := checks
+			checks.Action = tt.action
+
+			results, status := checks.Evaluate(tt.metrics, tt.reference)
+
+			var failed []string
+			for _, result := range results {
+				if !result.Passed {
+					failed = append(failed, result.Name)
+				}
+			}
+			assert.Equal(t, tt.wantFailed, failed)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestPopulationStabilityIndex(t *testing.T) {
+	assert.InDelta(t, 0, PopulationStabilityIndex(deciles(0, 1), deciles(0, 1)), 1e-9)
+
+	slightShift := PopulationStabilityIndex(deciles(0, 1), deciles(0.02, 1.02))
+	largeShift := PopulationStabilityIndex(deciles(0, 1), deciles(0.5, 1.5))
+	assert.Less(t, slightShift, 0.1)
+	assert.Greater(t, largeShift, 0.2)
+	assert.Greater(t, PopulationStabilityIndex(deciles(0, 1), deciles(0.5, 0.5)), largeShift)
+}
+
+func TestPredictionJob_ApplyQuality(t *testing.T) {
+	job := &PredictionJob{
+		Status:        JobCompleted,
+		QualityStatus: QualityStatusFailed,
+		Quality: &PredictionJobQuality{
+			Checks: []PredictionJobQualityCheckResult{
+				{Name: QualityCheckNullFraction, Value: 1, Threshold: 0.1},
+				{Name: QualityCheckDrift, Message: "reference job 1 has no quality metrics"},
+				{Name: QualityCheckRowRatio, Passed: true, Value: 1, Threshold: 0.9},
+			},
+		},
+	}
+
+	job.ApplyQuality()
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Equal(t, "data quality checks failed: null_fraction: 1 (threshold 0.1), drift: reference job 1 has no quality metrics", job.Error)
+
+	job.Status = JobCompleted
+	job.Error = ""
+	job.QualityStatus = QualityStatusWarning
+	job.ApplyQuality()
+	assert.Equal(t, JobCompleted, job.Status)
+	assert.Empty(t, job.Error)
+
+	// the prediction job failed since its predictions failed the checks before being written
+	job.Status = JobFailed
+	job.Error = "QualityCheckError: null_fraction"
+	job.QualityStatus = QualityStatusFailed
+	job.ApplyQuality()
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Equal(t, "data quality checks failed: null_fraction: 1 (threshold 0.1), drift: reference job 1 has no quality metrics", job.Error)
+}
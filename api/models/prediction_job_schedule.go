@@ -0,0 +1,268 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/robfig/cron"
+)
+
+// ConcurrencyPolicy specifies how a schedule treats a run while the job of a previous run is still active
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow starts the new job alongside the active ones
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "allow"
+	// ConcurrencyPolicyForbid skips the run if a previous job is still active
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "forbid"
+	// ConcurrencyPolicyReplace stops the active jobs before starting the new one
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "replace"
+)
+
+// DefaultScheduleMaxHistory is the number of runs kept for a schedule if max_history is not set
+const DefaultScheduleMaxHistory = 10
+
+// PredictionJobSchedule creates prediction jobs of a model version on a cron schedule
+type PredictionJobSchedule struct {
+	ID              ID     `json:"id"`
+	Name            string `json:"name"`
+	ProjectID       ID     `json:"project_id"`
+	ModelID         ID     `json:"model_id"`
+	VersionID       ID     `json:"version_id"`
+	EnvironmentName string `json:"environment_name"`
+	// Schedule is a standard 5-field cron expression
+	Schedule string `json:"schedule"`
+	// Timezone the schedule is evaluated in, UTC if empty
+	Timezone          string            `json:"timezone"`
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrency_policy"`
+	// MaxHistory is the number of most recent runs to keep
+	MaxHistory int  `json:"max_history"`
+	Paused     bool `json:"paused"`
+	// Config is the template of the created prediction jobs. String fields of the source and sink
+	// may use {{ ds }}, {{ ds_nodash }}, {{ ts }}, {{ ts_nodash }}, {{ yesterday_ds }} and {{ yesterday_ds_nodash }}
+	Config    *Config    `json:"config"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	NextRunAt *time.Time `json:"next_run_at"`
+	// LastWatermark is the end watermark of the latest completed job with incremental source,
+	// the next job only reads the rows after it
+	LastWatermark *time.Time `json:"last_watermark"`
+	CreatedUpdated
+}
+
+// PredictionJobScheduleRun records a run of a schedule and the prediction job it created
+type PredictionJobScheduleRun struct {
+	ID            ID        `json:"id"`
+	ScheduleID    ID        `json:"schedule_id"`
+	JobID         *ID       `json:"job_id"`
+	ScheduledTime time.Time `json:"scheduled_time"`
+	// Manual is true if the run was triggered through the API instead of the schedule
+	Manual bool `json:"manual"`
+	// Message explains why no job was created, e.g. the run was skipped or the job failed to be submitted
+	Message string         `json:"message,omitempty"`
+	Job     *PredictionJob `json:"job,omitempty" gorm:"-"`
+	CreatedUpdated
+}
+
+// Validate checks the schedule, timezone, concurrency policy and job template
+func (s *PredictionJobSchedule) Validate() error {
+	if s.Name == "" {
+		return errors.New("schedule name is required")
+	}
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("invalid timezone %s: %w", s.Timezone, err)
+	}
+	if _, err := cron.ParseStandard(s.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule %s: %w", s.Schedule, err)
+	}
+	switch s.ConcurrencyPolicy {
+	case ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace:
+	default:
+		return fmt.Errorf("invalid concurrency policy %s", s.ConcurrencyPolicy)
+	}
+	if s.MaxHistory < 0 {
+		return fmt.Errorf("invalid max history %d", s.MaxHistory)
+	}
+	if s.Config == nil || s.Config.JobConfig == nil {
+		return errors.New("job config is required")
+	}
+	if _, err := s.RenderJob(time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PredictionJobSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// NextRunAfter returns the first scheduled time of the schedule after t
+func (s *PredictionJobSchedule) NextRunAfter(t time.Time) (time.Time, error) {
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	schedule, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(t.In(loc)).UTC(), nil
+}
+
+// RenderJob creates the prediction job of a run scheduled at the given time, rendering the templates
+// in the source and sink of the job config. The incremental source reads the rows after the last watermark
+// up to the scheduled time, and the partitioned sink writes to the date of the scheduled time if not set.
+func (s *PredictionJobSchedule) RenderJob(scheduledTime time.Time) (*PredictionJob, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+
+	// deep copy the template so the schedule is never modified
+	raw, err := json.Marshal(s.Config)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+
+	r := newScheduleRenderer(scheduledTime.In(loc))
+	if config.JobConfig != nil {
+		if err := r.renderSource(config.JobConfig); err != nil {
+			return nil, err
+		}
+		if err := r.renderSink(config.JobConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	config.ApplyWatermark(s.LastWatermark, scheduledTime)
+	config.ApplyIncrementalDefaults(scheduledTime.In(loc))
+
+	return &PredictionJob{Config: config}, nil
+}
+
+type scheduleRenderer struct {
+	funcs template.FuncMap
+}
+
+func newScheduleRenderer(t time.Time) *scheduleRenderer {
+	yesterday := t.AddDate(0, 0, -1)
+	value := func(v string) func() string {
+		return func() string { return v }
+	}
+	return &scheduleRenderer{
+		funcs: template.FuncMap{
+			"ds":                  value(t.Format("2006-01-02")),
+			"ds_nodash":           value(t.Format("20060102")),
+			"ts":                  value(t.Format(time.RFC3339)),
+			"ts_nodash":           value(t.Format("20060102T150405")),
+			"yesterday_ds":        value(yesterday.Format("2006-01-02")),
+			"yesterday_ds_nodash": value(yesterday.Format("20060102")),
+		},
+	}
+}
+
+func (r *scheduleRenderer) render(text string) (string, error) {
+	tmpl, err := template.New("").Funcs(r.funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return "", fmt.Errorf("unable to render template %s: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+func (r *scheduleRenderer) renderOptions(options map[string]string) error {
+	for k, v := range options {
+		rendered, err := r.render(v)
+		if err != nil {
+			return err
+		}
+		options[k] = rendered
+	}
+	return nil
+}
+
+func (r *scheduleRenderer) renderSource(job *spec.PredictionJob) (err error) {
+	switch source := job.Source.(type) {
+	case *spec.PredictionJob_BigquerySource:
+		if source.BigquerySource == nil {
+			return nil
+		}
+		if source.BigquerySource.Table, err = r.render(source.BigquerySource.Table); err != nil {
+			return err
+		}
+		return r.renderOptions(source.BigquerySource.Options)
+	case *spec.PredictionJob_GcsSource:
+		if source.GcsSource == nil {
+			return nil
+		}
+		if source.GcsSource.Uri, err = r.render(source.GcsSource.Uri); err != nil {
+			return err
+		}
+		return r.renderOptions(source.GcsSource.Options)
+	}
+	return nil
+}
+
+func (r *scheduleRenderer) renderSink(job *spec.PredictionJob) (err error) {
+	switch sink := job.Sink.(type) {
+	case *spec.PredictionJob_BigquerySink:
+		if sink.BigquerySink == nil {
+			return nil
+		}
+		if sink.BigquerySink.Table, err = r.render(sink.BigquerySink.Table); err != nil {
+			return err
+		}
+		if err := r.renderPartition(sink.BigquerySink.Partition); err != nil {
+			return err
+		}
+		return r.renderOptions(sink.BigquerySink.Options)
+	case *spec.PredictionJob_GcsSink:
+		if sink.GcsSink == nil {
+			return nil
+		}
+		if sink.GcsSink.Uri, err = r.render(sink.GcsSink.Uri); err != nil {
+			return err
+		}
+		if err := r.renderPartition(sink.GcsSink.Partition); err != nil {
+			return err
+		}
+		return r.renderOptions(sink.GcsSink.Options)
+	}
+	return nil
+}
+
+func (r *scheduleRenderer) renderPartition(partition *spec.SinkPartition) (err error) {
+	if partition == nil {
+		return nil
+	}
+	partition.Value, err = r.render(partition.Value)
+	return err
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchedule() *PredictionJobSchedule {
+	return &PredictionJobSchedule{
+		Name:              "nightly",
+		Schedule:          "0 2 * * *",
+		Timezone:          "Asia/Jakarta",
+		ConcurrencyPolicy: ConcurrencyPolicyForbid,
+		Config: &Config{
+			JobConfig: &spec.PredictionJob{
+				Source: &spec.PredictionJob_BigquerySource{
+					BigquerySource: &spec.BigQuerySource{
+						Table:   "project.dataset.features_{{ ds_nodash }}",
+						Options: map[string]string{"filter": "date = '{{ yesterday_ds }}'"},
+					},
+				},
+				Sink: &spec.PredictionJob_GcsSink{
+					GcsSink: &spec.GcsSink{Uri: "gs://bucket/predictions/dt={{ ds }}"},
+				},
+			},
+		},
+	}
+}
+
+func TestPredictionJobSchedule_Validate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		modify   func(s *PredictionJobSchedule)
+		errorMsg string
+	}{
+		{
+			desc:   "Should succeed",
+			modify: func(s *PredictionJobSchedule) {},
+		},
+		{
+			desc:     "Should fail without name",
+			modify:   func(s *PredictionJobSchedule) { s.Name = "" },
+			errorMsg: "schedule name is required",
+		},
+		{
+			desc:     "Should fail for invalid cron",
+			modify:   func(s *PredictionJobSchedule) { s.Schedule = "every night" },
+			errorMsg: "invalid schedule every night",
+		},
+		{
+			desc:     "Should fail for unknown timezone",
+			modify:   func(s *PredictionJobSchedule) { s.Timezone = "Mars/Olympus" },
+			errorMsg: "invalid timezone Mars/Olympus",
+		},
+		{
+			desc:     "Should fail for unknown concurrency policy",
+			modify:   func(s *PredictionJobSchedule) { s.ConcurrencyPolicy = "queue" },
+			errorMsg: "invalid concurrency policy queue",
+		},
+		{
+			desc:     "Should fail for negative max history",
+			modify:   func(s *PredictionJobSchedule) { s.MaxHistory = -1 },
+			errorMsg: "invalid max history -1",
+		},
+		{
+			desc:     "Should fail without job config",
+			modify:   func(s *PredictionJobSchedule) { s.Config = &Config{} },
+			errorMsg: "job config is required",
+		},
+		{
+			desc: "Should fail for unknown template function",
+			modify: func(s *PredictionJobSchedule) {
+				s.Config.JobConfig.GetBigquerySource().Table = "project.dataset.features_{{ execution_date }}"
+			},
+			errorMsg: "invalid template project.dataset.features_{{ execution_date }}",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			schedule := newTestSchedule()
+			tC.modify(schedule)
+
+			err := schedule.Validate()
+			if tC.errorMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tC.errorMsg)
+		})
+	}
+}
+
+func TestPredictionJobSchedule_NextRunAfter(t *testing.T) {
+	schedule := newTestSchedule()
+
+	// 2023-03-01 20:00 UTC is 2023-03-02 03:00 in Jakarta, so the next 02:00 Jakarta is on 2023-03-03
+	next, err := schedule.NextRunAfter(time.Date(2023, 3, 1, 20, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 3, 2, 19, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, time.UTC, next.Location())
+}
+
+func TestPredictionJobSchedule_RenderJob(t *testing.T) {
+	schedule := newTestSchedule()
+
+	// rendered in the schedule's timezone: 2023-03-01 19:00 UTC is 2023-03-02 02:00 in Jakarta
+	job, err := schedule.RenderJob(time.Date(2023, 3, 1, 19, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	source := job.Config.JobConfig.GetBigquerySource()
+	assert.Equal(t, "project.dataset.features_20230302", source.Table)
+	assert.Equal(t, "date = '2023-03-01'", source.Options["filter"])
+	assert.Equal(t, "gs://bucket/predictions/dt=2023-03-02", job.Config.JobConfig.GetGcsSink().Uri)
+
+	// the template itself is untouched
+	assert.Equal(t, "project.dataset.features_{{ ds_nodash }}", schedule.Config.JobConfig.GetBigquerySource().Table)
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+
+	"github.com/caraml-dev/merlin/pkg/protocol"
+)
+
+// PredictionJobModeStandardTransformer replays request payloads through the standard transformer of a version endpoint
+const PredictionJobModeStandardTransformer PredictionJobMode = "standard_transformer"
+
+// TransformerModelTarget is where the standard transformer of the prediction job sends the model requests
+type TransformerModelTarget string
+
+const (
+	// TransformerModelTargetEndpoint sends the model requests to the predictor of the version endpoint
+	TransformerModelTargetEndpoint TransformerModelTarget = "endpoint"
+	// TransformerModelTargetArtifact sends the model requests to the model artifact loaded in the prediction job
+	TransformerModelTargetArtifact TransformerModelTarget = "artifact"
+)
+
+// StandardTransformerJobConfig configures a prediction job replaying request payloads through the standard transformer
+// of a version endpoint, so that the feature enrichment and transformations done online are reproduced offline
+type StandardTransformerJobConfig struct {
+	// EndpointID is the ID of the version endpoint whose standard transformer is replayed
+	EndpointID string `json:"endpoint_id"`
+	// ModelTarget is where the model requests are sent to, endpoint by default
+	ModelTarget TransformerModelTarget `json:"model_target,omitempty"`
+	// Concurrency is the number of request payloads executed concurrently
+	Concurrency int `json:"concurrency,omitempty"`
+	// MaxFailedRatio is the maximum ratio of request payloads whose response is an error, above which the prediction
+	// job fails. The prediction job fails if any request payload fails by default.
+	MaxFailedRatio float64 `json:"max_failed_ratio,omitempty"`
+	// InferenceServiceName and Protocol are resolved from the version endpoint
+	InferenceServiceName string            `json:"inference_service_name,omitempty"`
+	Protocol             protocol.Protocol `json:"protocol,omitempty"`
+}
+
+// IsStandardTransformer returns true if the prediction job replays request payloads through a standard transformer
+func (job *PredictionJob) IsStandardTransformer() bool {
+	return job.Config != nil && job.Config.Mode == PredictionJobModeStandardTransformer
+}
+
+// IsKubernetesJob returns true if the prediction job runs as a kubernetes job instead of a spark application
+func (job *PredictionJob) IsKubernetesJob() bool {
+	return job.IsLightweight() || job.IsStandardTransformer()
+}
+
+// ApplyDefaults sets the default model target and concurrency
+func (c *StandardTransformerJobConfig) ApplyDefaults() {
+	if c.ModelTarget == "" {
+		c.ModelTarget = TransformerModelTargetEndpoint
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = DefaultLightweightConcurrency
+	}
+}
+
+// Validate checks the standard transformer configuration, source and sink of a prediction job against the
+// lightweight prediction job configuration of the environment, since it runs as a lightweight kubernetes job
+func (c *StandardTransformerJobConfig) Validate(envConfig *LightweightPredictionJobConfig, jobConfig *spec.PredictionJob) error {
+	if envConfig == nil || !envConfig.Enabled {
+		return errors.New("lightweight prediction job is not enabled in the environment")
+	}
+
+	if c.EndpointID == "" {
+		return errors.New("endpoint id is required")
+	}
+	if c.ModelTarget != TransformerModelTargetEndpoint && c.ModelTarget != TransformerModelTargetArtifact {
+		return fmt.Errorf("unknown model target: %s", c.ModelTarget)
+	}
+	if c.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", c.Concurrency)
+	}
+	if envConfig.MaxConcurrency > 0 && c.Concurrency > envConfig.MaxConcurrency {
+		return fmt.Errorf("concurrency %d exceeds the maximum of %d", c.Concurrency, envConfig.MaxConcurrency)
+	}
+	if c.MaxFailedRatio < 0 || c.MaxFailedRatio > 1 {
+		return fmt.Errorf("max failed ratio must be between 0 and 1: %g", c.MaxFailedRatio)
+	}
+
+	if source := jobConfig.GetGcsSource(); source == nil || source.Format != spec.FileFormat_JSON {
+		return errors.New("standard transformer prediction job only supports JSON lines source of request payloads")
+	}
+	if sink := jobConfig.GetGcsSink(); sink == nil || sink.Format != spec.FileFormat_JSON {
+		return errors.New("standard transformer prediction job only supports JSON lines sink")
+	}
+	if sink := jobConfig.GetGcsSink(); sink.GetPartition() != nil {
+		return errors.New("standard transformer prediction job doesn't support partitioned sink")
+	}
+	return nil
+}
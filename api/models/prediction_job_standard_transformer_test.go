@@ -0,0 +1,109 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardTransformerJobConfig_Validate(t *testing.T) {
+	envConfig := &LightweightPredictionJobConfig{Enabled: true, MaxConcurrency: 8, MaxBatchSize: 5000}
+	jsonLines := &spec.PredictionJob{
+		Source: &spec.PredictionJob_GcsSource{GcsSource: &spec.GcsSource{Format: spec.FileFormat_JSON}},
+		Sink:   &spec.PredictionJob_GcsSink{GcsSink: &spec.GcsSink{Format: spec.FileFormat_JSON}},
+	}
+
+	tests := []struct {
+		name       string
+		config     StandardTransformerJobConfig
+		envConfig  *LightweightPredictionJobConfig
+		jobConfig  *spec.PredictionJob
+		wantErrMsg string
+	}{
+		{
+			name:      "default config",
+			config:    StandardTransformerJobConfig{EndpointID: "1234"},
+			envConfig: envConfig,
+			jobConfig: jsonLines,
+		},
+		{
+			name:       "environment without lightweight config",
+			config:     StandardTransformerJobConfig{EndpointID: "1234"},
+			jobConfig:  jsonLines,
+			wantErrMsg: "lightweight prediction job is not enabled in the environment",
+		},
+		{
+			name:       "missing endpoint id",
+			envConfig:  envConfig,
+			jobConfig:  jsonLines,
+			wantErrMsg: "endpoint id is required",
+		},
+		{
+			name:       "unknown model target",
+			config:     StandardTransformerJobConfig{EndpointID: "1234", ModelTarget: "pyfunc"},
+			envConfig:  envConfig,
+			jobConfig:  jsonLines,
+			wantErrMsg: "unknown model target: pyfunc",
+		},
+		{
+			name:       "concurrency exceeds maximum",
+			config:     StandardTransformerJobConfig{EndpointID: "1234", Concurrency: 16},
+			envConfig:  envConfig,
+			jobConfig:  jsonLines,
+			wantErrMsg: "concurrency 16 exceeds the maximum of 8",
+		},
+		{
+			name:       "invalid max failed ratio",
+			config:     StandardTransformerJobConfig{EndpointID: "1234", Concurrency: 4, MaxFailedRatio: 1.5},
+			envConfig:  envConfig,
+			jobConfig:  jsonLines,
+			wantErrMsg: "max failed ratio must be between 0 and 1: 1.5",
+		},
+		{
+			name:      "csv source",
+			config:    StandardTransformerJobConfig{EndpointID: "1234"},
+			envConfig: envConfig,
+			jobConfig: &spec.PredictionJob{
+				Source: &spec.PredictionJob_GcsSource{GcsSource: &spec.GcsSource{Format: spec.FileFormat_CSV}},
+				Sink:   jsonLines.Sink,
+			},
+			wantErrMsg: "standard transformer prediction job only supports JSON lines source of request payloads",
+		},
+		{
+			name:      "bigquery sink",
+			config:    StandardTransformerJobConfig{EndpointID: "1234"},
+			envConfig: envConfig,
+			jobConfig: &spec.PredictionJob{
+				Source: jsonLines.Source,
+				Sink:   &spec.PredictionJob_BigquerySink{BigquerySink: &spec.BigQuerySink{Table: "project.dataset.table"}},
+			},
+			wantErrMsg: "standard transformer prediction job only supports JSON lines sink",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ApplyDefaults()
+			err := tt.config.Validate(tt.envConfig, tt.jobConfig)
+			if tt.wantErrMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ProjectQuota limits the total resources that can be used by all deployments in a project.
+// Empty or nil limit means the resource is not limited.
+type ProjectQuota struct {
+	ID        ID `json:"id"`
+	ProjectID ID `json:"project_id" gorm:"unique;not null"`
+	// MaxCPU total CPU request of all active version endpoints in the project
+	MaxCPU string `json:"max_cpu,omitempty"`
+	// MaxMemory total memory request of all active version endpoints in the project
+	MaxMemory string `json:"max_memory,omitempty"`
+	// MaxReplicas total maximum replicas of all active version endpoints in the project
+	MaxReplicas *int `json:"max_replicas,omitempty"`
+	// MaxEndpoints number of active version endpoints in the project
+	MaxEndpoints *int `json:"max_endpoints,omitempty"`
+	// MaxConcurrentPredictionJobs number of pending or running prediction jobs in the project
+	MaxConcurrentPredictionJobs *int `json:"max_concurrent_prediction_jobs,omitempty"`
+	CreatedUpdated
+}
+
+// Validate checks that all limits in the quota are well-formed
+func (q *ProjectQuota) Validate() error {
+	if q.MaxCPU != "" {
+		if _, err := resource.ParseQuantity(q.MaxCPU); err != nil {
+			return fmt.Errorf("invalid max cpu: %s", q.MaxCPU)
+		}
+	}
+	if q.MaxMemory != "" {
+		if _, err := resource.ParseQuantity(q.MaxMemory); err != nil {
+			return fmt.Errorf("invalid max memory: %s", q.MaxMemory)
+		}
+	}
+	for name, limit := range map[string]*int{
+		"max replicas":                   q.MaxReplicas,
+		"max endpoints":                  q.MaxEndpoints,
+		"max concurrent prediction jobs": q.MaxConcurrentPredictionJobs,
+	} {
+		if limit != nil && *limit < 0 {
+			return fmt.Errorf("invalid %s: %d", name, *limit)
+		}
+	}
+	return nil
+}
+
+// CheckEndpointUsage returns error if the given usage exceeds any of the endpoint limits of the quota
+func (q *ProjectQuota) CheckEndpointUsage(usage *ProjectResourceUsage) error {
+	if q.MaxCPU != "" {
+		maxCPU := resource.MustParse(q.MaxCPU)
+		if usage.CPU.Cmp(maxCPU) > 0 {
+			return fmt.Errorf("total cpu request %s exceeds project quota %s", usage.CPU.String(), q.MaxCPU)
+		}
+	}
+	if q.MaxMemory != "" {
+		maxMemory := resource.MustParse(q.MaxMemory)
+		if usage.Memory.Cmp(maxMemory) > 0 {
+			return fmt.Errorf("total memory request %s exceeds project quota %s", usage.Memory.String(), q.MaxMemory)
+		}
+	}
+	if q.MaxReplicas != nil && usage.Replicas > *q.MaxReplicas {
+		return fmt.Errorf("total max replicas %d exceeds project quota %d", usage.Replicas, *q.MaxReplicas)
+	}
+	if q.MaxEndpoints != nil && usage.Endpoints > *q.MaxEndpoints {
+		return fmt.Errorf("number of active endpoints %d exceeds project quota %d", usage.Endpoints, *q.MaxEndpoints)
+	}
+	return nil
+}
+
+// CheckPredictionJobUsage returns error if the given usage exceeds the prediction job limit of the quota
+func (q *ProjectQuota) CheckPredictionJobUsage(usage *ProjectResourceUsage) error {
+	if q.MaxConcurrentPredictionJobs != nil && usage.ConcurrentPredictionJobs > *q.MaxConcurrentPredictionJobs {
+		return fmt.Errorf("number of concurrent prediction jobs %d exceeds project quota %d", usage.ConcurrentPredictionJobs, *q.MaxConcurrentPredictionJobs)
+	}
+	return nil
+}
+
+// ProjectResourceUsage is the resources currently used by a project
+type ProjectResourceUsage struct {
+	// CPU total CPU request of all active version endpoints, assuming they run with maximum replicas
+	CPU resource.Quantity `json:"cpu"`
+	// Memory total memory request of all active version endpoints, assuming they run with maximum replicas
+	Memory resource.Quantity `json:"memory"`
+	// Replicas total maximum replicas of all active version endpoints
+	Replicas int `json:"replicas"`
+	// Endpoints number of active version endpoints
+	Endpoints int `json:"endpoints"`
+	// ConcurrentPredictionJobs number of pending or running prediction jobs
+	ConcurrentPredictionJobs int `json:"concurrent_prediction_jobs"`
+}
+
+// AddEndpoint adds the resources requested by the version endpoint and its transformer into the usage
+func (u *ProjectResourceUsage) AddEndpoint(endpoint *VersionEndpoint) {
+	u.Endpoints++
+	u.addResourceRequest(endpoint.ResourceRequest)
+	if endpoint.Transformer != nil && endpoint.Transformer.Enabled {
+		u.addResourceRequest(endpoint.Transformer.ResourceRequest)
+	}
+}
+
+func (u *ProjectResourceUsage) addResourceRequest(resourceRequest *ResourceRequest) {
+	if resourceRequest == nil {
+		return
+	}
+
+	for i := 0; i < resourceRequest.MaxReplica; i++ {
+		u.CPU.Add(resourceRequest.CPURequest)
+		u.Memory.Add(resourceRequest.MemoryRequest)
+	}
+	u.Replicas += resourceRequest.MaxReplica
+}
+
+// ProjectQuotaUsage reports the current resource usage of a project against its quota
+type ProjectQuotaUsage struct {
+	// Quota quota of the project, nil if the project is not limited
+	Quota *ProjectQuota `json:"quota"`
+	// Usage current resource usage of the project
+	Usage *ProjectResourceUsage `json:"usage"`
+}
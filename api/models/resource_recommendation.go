@@ -0,0 +1,37 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// ResourceRecommendation compares the current resource request of a component with the right-sized one
+type ResourceRecommendation struct {
+	// CurrentResourceRequest resource request currently used by the component
+	CurrentResourceRequest *ResourceRequest `json:"current_resource_request"`
+	// RecommendedResourceRequest resource request suggested from the component's historical usage
+	RecommendedResourceRequest *ResourceRequest `json:"recommended_resource_request"`
+	// CurrentCostEstimation estimated monthly cost using the current resource request
+	CurrentCostEstimation *CostEstimation `json:"current_cost_estimation,omitempty"`
+	// RecommendedCostEstimation estimated monthly cost using the recommended resource request
+	RecommendedCostEstimation *CostEstimation `json:"recommended_cost_estimation,omitempty"`
+}
+
+// EndpointResourceRecommendation contains the resource recommendation of a version endpoint's model and transformer
+type EndpointResourceRecommendation struct {
+	// LookbackWindow period of historical usage used to compute the recommendation
+	LookbackWindow string `json:"lookback_window"`
+	// Model resource recommendation of the model component
+	Model *ResourceRecommendation `json:"model"`
+	// Transformer resource recommendation of the transformer component, only available when transformer is enabled
+	Transformer *ResourceRecommendation `json:"transformer,omitempty"`
+}
@@ -31,6 +31,8 @@ type ResourceRequest struct {
 	CPURequest resource.Quantity `json:"cpu_request"`
 	// Memory request of inference service
 	MemoryRequest resource.Quantity `json:"memory_request"`
+	// Number of GPU of inference service, only used to estimate its cost since GPU isn't requested from the cluster
+	GPURequest resource.Quantity `json:"gpu_request"`
 }
 
 func (r ResourceRequest) Value() (driver.Value, error) {
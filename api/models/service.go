@@ -45,6 +45,8 @@ type Service struct {
 	DeploymentMode    deployment.Mode
 	AutoscalingPolicy *autoscaling.AutoscalingPolicy
 	Protocol          protocol.Protocol
+	// Events are what happened while the service was being deployed, collected by the cluster controller
+	Events []*DeploymentEvent
 }
 
 func NewService(model *Model, version *Version, modelOpt *ModelOption, endpoint *VersionEndpoint) *Service {
@@ -0,0 +1,184 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const maxTeamNameLength = 128
+
+// TeamSource is where a team is managed
+type TeamSource string
+
+const (
+	// TeamSourceMerlin teams are managed through Merlin's team API
+	TeamSourceMerlin TeamSource = "merlin"
+	// TeamSourceWarden teams are synced from Warden
+	TeamSourceWarden TeamSource = "warden"
+)
+
+// NotificationChannelType is the kind of destination alerts of a team are sent to
+type NotificationChannelType string
+
+const (
+	NotificationChannelTypeSlack     NotificationChannelType = "slack"
+	NotificationChannelTypeEmail     NotificationChannelType = "email"
+	NotificationChannelTypePagerDuty NotificationChannelType = "pagerduty"
+	NotificationChannelTypeWebhook   NotificationChannelType = "webhook"
+)
+
+// Team owns models and receives the alerts of their endpoints. Its name is the `owner` label alerts are routed on.
+type Team struct {
+	ID          ID     `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Members are the usernames or emails of the team's members
+	Members TeamMembers `json:"members" gorm:"members"`
+	// NotificationChannels are where the team is notified of its alerts
+	NotificationChannels NotificationChannels `json:"notification_channels" gorm:"notification_channels"`
+	Escalation           TeamEscalation       `json:"escalation" gorm:"escalation"`
+	Source               TeamSource           `json:"source"`
+	CreatedUpdated
+}
+
+// TeamMembers is the list of members of a team
+type TeamMembers []string
+
+func (m TeamMembers) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *TeamMembers) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &m)
+}
+
+// NotificationChannel is a destination of the alerts of a team
+type NotificationChannel struct {
+	Type NotificationChannelType `json:"type"`
+	// Target is the slack channel, email address, PagerDuty service key or webhook URL
+	Target string `json:"target"`
+	// Severity only sends alerts of the given severity to the channel, all alerts if empty
+	Severity AlertConditionSeverity `json:"severity,omitempty"`
+}
+
+// NotificationChannels is the list of notification channels of a team
+type NotificationChannels []*NotificationChannel
+
+func (c NotificationChannels) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *NotificationChannels) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &c)
+}
+
+// TeamEscalation describes how unacknowledged alerts of a team are escalated
+type TeamEscalation struct {
+	// OnCall is the current on-call of the team
+	OnCall string `json:"on_call,omitempty"`
+	// Policy is the name of the escalation policy in the paging system
+	Policy string `json:"policy,omitempty"`
+	// AckTimeout is the duration after which unacknowledged alerts are escalated, e.g. 15m
+	AckTimeout string `json:"ack_timeout,omitempty"`
+}
+
+func (e TeamEscalation) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+func (e *TeamEscalation) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &e)
+}
+
+// Validate checks the team has a name and its members, channels and escalation are well-formed
+func (t *Team) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("team name is required")
+	}
+	if len(t.Name) > maxTeamNameLength {
+		return fmt.Errorf("team name must be at most %d characters", maxTeamNameLength)
+	}
+	for i, member := range t.Members {
+		if strings.TrimSpace(member) == "" {
+			return fmt.Errorf("invalid member %d: member is required", i)
+		}
+	}
+	for i, channel := range t.NotificationChannels {
+		if err := channel.validate(); err != nil {
+			return fmt.Errorf("invalid notification channel %d: %w", i, err)
+		}
+	}
+	if t.Escalation.AckTimeout != "" {
+		if _, err := time.ParseDuration(t.Escalation.AckTimeout); err != nil {
+			return fmt.Errorf("invalid escalation ack timeout: %s", t.Escalation.AckTimeout)
+		}
+	}
+	return nil
+}
+
+func (c *NotificationChannel) validate() error {
+	if c.Target == "" {
+		return errors.New("target is required")
+	}
+	switch c.Type {
+	case NotificationChannelTypeSlack, NotificationChannelTypePagerDuty:
+	case NotificationChannelTypeEmail:
+		if !strings.Contains(c.Target, "@") {
+			return fmt.Errorf("invalid email address: %s", c.Target)
+		}
+	case NotificationChannelTypeWebhook:
+		u, err := url.Parse(c.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url %q: must be an absolute http or https url", c.Target)
+		}
+	default:
+		return fmt.Errorf("unknown type: %s", c.Type)
+	}
+	if c.Severity != "" && c.Severity != AlertConditionSeverityWarning && c.Severity != AlertConditionSeverityCritical {
+		return fmt.Errorf("unknown severity: %s", c.Severity)
+	}
+	return nil
+}
+
+// ProjectOwner is the team owning a project. The owner receives the alerts of the project's models which don't set a team.
+type ProjectOwner struct {
+	ID        ID    `json:"id"`
+	ProjectID ID    `json:"project_id"`
+	TeamID    ID    `json:"team_id"`
+	Team      *Team `json:"team,omitempty"`
+	CreatedUpdated
+}
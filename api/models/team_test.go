@@ -0,0 +1,89 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeam_Validate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		team     *Team
+		errorMsg string
+	}{
+		{
+			desc: "Should succeed with members, channels and escalation",
+			team: &Team{
+				Name:    "datascience",
+				Members: TeamMembers{"alice@example.com"},
+				NotificationChannels: NotificationChannels{
+					{Type: NotificationChannelTypeSlack, Target: "#ds-alerts"},
+					{Type: NotificationChannelTypePagerDuty, Target: "service-key", Severity: AlertConditionSeverityCritical},
+					{Type: NotificationChannelTypeWebhook, Target: "https://hooks.example.com/alerts"},
+				},
+				Escalation: TeamEscalation{OnCall: "alice@example.com", Policy: "ds-primary", AckTimeout: "15m"},
+			},
+		},
+		{
+			desc:     "Should fail without name",
+			team:     &Team{Name: " "},
+			errorMsg: "team name is required",
+		},
+		{
+			desc:     "Should fail for too long name",
+			team:     &Team{Name: strings.Repeat("a", 129)},
+			errorMsg: "team name must be at most 128 characters",
+		},
+		{
+			desc:     "Should fail for empty member",
+			team:     &Team{Name: "datascience", Members: TeamMembers{""}},
+			errorMsg: "invalid member 0: member is required",
+		},
+		{
+			desc:     "Should fail for unknown channel type",
+			team:     &Team{Name: "datascience", NotificationChannels: NotificationChannels{{Type: "sms", Target: "123"}}},
+			errorMsg: "invalid notification channel 0: unknown type: sms",
+		},
+		{
+			desc:     "Should fail for invalid email",
+			team:     &Team{Name: "datascience", NotificationChannels: NotificationChannels{{Type: NotificationChannelTypeEmail, Target: "ds"}}},
+			errorMsg: "invalid notification channel 0: invalid email address: ds",
+		},
+		{
+			desc:     "Should fail for unknown channel severity",
+			team:     &Team{Name: "datascience", NotificationChannels: NotificationChannels{{Type: NotificationChannelTypeSlack, Target: "#ds", Severity: "INFO"}}},
+			errorMsg: "invalid notification channel 0: unknown severity: INFO",
+		},
+		{
+			desc:     "Should fail for invalid ack timeout",
+			team:     &Team{Name: "datascience", Escalation: TeamEscalation{AckTimeout: "15"}},
+			errorMsg: "invalid escalation ack timeout: 15",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := tC.team.Validate()
+			if tC.errorMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.errorMsg)
+		})
+	}
+}
@@ -34,17 +34,23 @@ type Version struct {
 	Labels          KV                 `json:"labels" gorm:"labels"`
 	PythonVersion   string             `json:"python_version" gorm:"python_version"`
 	CustomPredictor *CustomPredictor   `json:"custom_predictor"`
+	// BuildConfig customizes the docker image build of a pyfunc model version
+	BuildConfig *VersionBuildConfig `json:"build_config,omitempty"`
+	// BuildInputs are the effective inputs of the docker image build resolved from BuildConfig
+	BuildInputs *VersionBuildInputs `json:"build_inputs,omitempty"`
 	CreatedUpdated
 }
 
 type VersionPost struct {
-	Labels        KV     `json:"labels" gorm:"labels"`
-	PythonVersion string `json:"python_version" gorm:"python_version"`
+	Labels        KV                  `json:"labels" gorm:"labels"`
+	PythonVersion string              `json:"python_version" gorm:"python_version"`
+	BuildConfig   *VersionBuildConfig `json:"build_config,omitempty"`
 }
 
 type VersionPatch struct {
-	Properties      *KV              `json:"properties,omitempty"`
-	CustomPredictor *CustomPredictor `json:"custom_predictor,omitempty"`
+	Properties      *KV                 `json:"properties,omitempty"`
+	CustomPredictor *CustomPredictor    `json:"custom_predictor,omitempty"`
+	BuildConfig     *VersionBuildConfig `json:"build_config,omitempty"`
 }
 
 type CustomPredictor struct {
@@ -98,6 +104,12 @@ func (v *Version) Patch(patch *VersionPatch) error {
 		}
 		v.CustomPredictor = patch.CustomPredictor
 	}
+	if patch.BuildConfig != nil && v.Model.Type == ModelTypePyFunc {
+		if err := patch.BuildConfig.Validate(); err != nil {
+			return err
+		}
+		v.BuildConfig = patch.BuildConfig
+	}
 	return nil
 }
 
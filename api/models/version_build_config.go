@@ -0,0 +1,98 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	aptPackageRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9+.\-]+(=[A-Za-z0-9.+~:\-]+)?$`)
+	buildArgRegex   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// reservedBuildArgs are set by the image builder and can't be overridden by the version
+	reservedBuildArgs = map[string]bool{
+		"BASE_IMAGE":                     true,
+		"MODEL_URL":                      true,
+		"APT_PACKAGES":                   true,
+		"GOOGLE_APPLICATION_CREDENTIALS": true,
+	}
+)
+
+// VersionBuildConfig customizes the docker image build of a pyfunc model version
+type VersionBuildConfig struct {
+	// BaseImage is the name of one of the base images allowed by the environment, the base image of the python version is used if empty
+	BaseImage string `json:"base_image,omitempty"`
+	// AptPackages are installed in the image before the model's conda environment
+	AptPackages []string          `json:"apt_packages,omitempty"`
+	BuildArgs   map[string]string `json:"build_args,omitempty"`
+}
+
+func (bc *VersionBuildConfig) Validate() error {
+	for _, pkg := range bc.AptPackages {
+		if !aptPackageRegex.MatchString(pkg) {
+			return fmt.Errorf("invalid apt package %q", pkg)
+		}
+	}
+
+	for name := range bc.BuildArgs {
+		if !buildArgRegex.MatchString(name) {
+			return fmt.Errorf("invalid build arg name %q", name)
+		}
+		if reservedBuildArgs[name] {
+			return fmt.Errorf("build arg %s is set by merlin and can't be overridden", name)
+		}
+	}
+	return nil
+}
+
+func (bc VersionBuildConfig) Value() (driver.Value, error) {
+	return json.Marshal(bc)
+}
+
+func (bc *VersionBuildConfig) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &bc)
+}
+
+// VersionBuildInputs are the effective inputs of the docker image build of a pyfunc model version
+type VersionBuildInputs struct {
+	BaseImage       string            `json:"base_image"`
+	DockerfilePath  string            `json:"dockerfile_path"`
+	BuildContextURI string            `json:"build_context_uri"`
+	AptPackages     []string          `json:"apt_packages,omitempty"`
+	BuildArgs       map[string]string `json:"build_args,omitempty"`
+}
+
+func (bi VersionBuildInputs) Value() (driver.Value, error) {
+	return json.Marshal(bi)
+}
+
+func (bi *VersionBuildInputs) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &bi)
+}
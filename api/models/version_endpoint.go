@@ -69,6 +69,8 @@ type VersionEndpoint struct {
 	AutoscalingPolicy *autoscaling.AutoscalingPolicy `json:"autoscaling_policy" gorm:"autoscaling_policy"`
 	// Protocol to be used when deploying the model
 	Protocol protocol.Protocol `json:"protocol" gorm:"protocol"`
+	// CostEstimation estimated monthly cost of the version endpoint, including its transformer
+	CostEstimation *CostEstimation `json:"cost_estimation,omitempty" gorm:"-"`
 	CreatedUpdated
 }
 
@@ -189,3 +191,21 @@ func (ve *VersionEndpoint) UpdateMonitoringURL(baseURL string, params EndpointMo
 
 	ve.MonitoringURL = url.String()
 }
+
+// UpdateCostEstimation computes the estimated monthly cost of the version endpoint and its transformer
+// using the unit cost of the environment where it's deployed
+func (ve *VersionEndpoint) UpdateCostEstimation() {
+	if ve.Environment == nil || ve.Environment.UnitCost == nil {
+		return
+	}
+
+	estimation := ve.Environment.UnitCost.EstimateCost(ve.ResourceRequest)
+	if estimation == nil {
+		return
+	}
+	if ve.Transformer != nil && ve.Transformer.Enabled {
+		estimation.Add(ve.Environment.UnitCost.EstimateCost(ve.Transformer.ResourceRequest))
+	}
+
+	ve.CostEstimation = estimation.round()
+}
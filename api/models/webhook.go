@@ -0,0 +1,182 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventType is the type of event sent to webhooks, following CloudEvents' reverse-DNS naming
+type WebhookEventType string
+
+const (
+	// WebhookEventVersionEndpointStatusChanged is sent when the deployment of a version endpoint finishes
+	WebhookEventVersionEndpointStatusChanged WebhookEventType = "merlin.version_endpoint.status_changed"
+	// WebhookEventModelEndpointUpdated is sent when a model endpoint is created, updated, or undeployed
+	WebhookEventModelEndpointUpdated WebhookEventType = "merlin.model_endpoint.updated"
+	// WebhookEventPredictionJobStatusChanged is sent when a prediction job changes state
+	WebhookEventPredictionJobStatusChanged WebhookEventType = "merlin.prediction_job.status_changed"
+)
+
+var webhookEventTypes = map[WebhookEventType]bool{
+	WebhookEventVersionEndpointStatusChanged: true,
+	WebhookEventModelEndpointUpdated:         true,
+	WebhookEventPredictionJobStatusChanged:   true,
+}
+
+const cloudEventsSpecVersion = "1.0"
+
+// Webhook is a project-level subscription to Merlin events
+type Webhook struct {
+	ID        ID     `json:"id"`
+	ProjectID ID     `json:"project_id"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	// Secret is used to sign the payload, it's never returned by the API
+	Secret string `json:"secret,omitempty"`
+	// EventTypes the webhook subscribes to, all event types if empty
+	EventTypes WebhookEventTypes `json:"event_types" gorm:"event_types"`
+	CreatedUpdated
+}
+
+// WebhookEventTypes is the list of event types subscribed by a webhook
+type WebhookEventTypes []WebhookEventType
+
+func (t WebhookEventTypes) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+func (t *WebhookEventTypes) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &t)
+}
+
+// Validate checks the webhook has a name, an absolute http(s) URL, and only subscribes to known event types
+func (w *Webhook) Validate() error {
+	if w.Name == "" {
+		return errors.New("webhook name is required")
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q: must be an absolute http or https url", w.URL)
+	}
+
+	for _, eventType := range w.EventTypes {
+		if !webhookEventTypes[eventType] {
+			return fmt.Errorf("unsupported event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Subscribes returns true if the webhook should receive events of the given type
+func (w *Webhook) Subscribes(eventType WebhookEventType) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the payload sent to webhooks, in CloudEvents v1.0 structured JSON format
+type WebhookEvent struct {
+	SpecVersion     string           `json:"specversion"`
+	ID              string           `json:"id"`
+	Source          string           `json:"source"`
+	Type            WebhookEventType `json:"type"`
+	Subject         string           `json:"subject,omitempty"`
+	Time            time.Time        `json:"time"`
+	DataContentType string           `json:"datacontenttype"`
+	Data            interface{}      `json:"data"`
+}
+
+// NewWebhookEvent creates a new event of a project. Subject identifies the resource the event is about.
+func NewWebhookEvent(projectID ID, eventType WebhookEventType, subject string, data interface{}) *WebhookEvent {
+	return &WebhookEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              uuid.New().String(),
+		Source:          fmt.Sprintf("/projects/%d", projectID),
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// WebhookDelivery records the result of delivering an event to a webhook
+type WebhookDelivery struct {
+	ID         ID               `json:"id"`
+	WebhookID  ID               `json:"webhook_id"`
+	EventID    string           `json:"event_id"`
+	EventType  WebhookEventType `json:"event_type"`
+	Payload    string           `json:"payload"`
+	Attempts   int              `json:"attempts"`
+	StatusCode int              `json:"status_code"`
+	Success    bool             `json:"success"`
+	Error      string           `json:"error"`
+	CreatedUpdated
+}
+
+// VersionEndpointEventData is the data of WebhookEventVersionEndpointStatusChanged event
+type VersionEndpointEventData struct {
+	EndpointID      uuid.UUID      `json:"endpoint_id"`
+	ModelID         ID             `json:"model_id"`
+	ModelName       string         `json:"model_name"`
+	VersionID       ID             `json:"version_id"`
+	EnvironmentName string         `json:"environment_name"`
+	PreviousStatus  EndpointStatus `json:"previous_status"`
+	Status          EndpointStatus `json:"status"`
+	URL             string         `json:"url,omitempty"`
+	Message         string         `json:"message,omitempty"`
+}
+
+// ModelEndpointEventData is the data of WebhookEventModelEndpointUpdated event
+type ModelEndpointEventData struct {
+	ModelEndpointID ID             `json:"model_endpoint_id"`
+	ModelID         ID             `json:"model_id"`
+	ModelName       string         `json:"model_name"`
+	EnvironmentName string         `json:"environment_name"`
+	Status          EndpointStatus `json:"status"`
+	URL             string         `json:"url,omitempty"`
+}
+
+// PredictionJobEventData is the data of WebhookEventPredictionJobStatusChanged event
+type PredictionJobEventData struct {
+	JobID           ID     `json:"job_id"`
+	Name            string `json:"name"`
+	ModelID         ID     `json:"model_id"`
+	VersionID       ID     `json:"version_id"`
+	EnvironmentName string `json:"environment_name"`
+	PreviousStatus  State  `json:"previous_status"`
+	Status          State  `json:"status"`
+	Error           string `json:"error,omitempty"`
+}
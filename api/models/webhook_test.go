@@ -0,0 +1,68 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Validate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		webhook  *Webhook
+		errorMsg string
+	}{
+		{
+			desc:    "Should succeed for https url with known event types",
+			webhook: &Webhook{Name: "chat-ops", URL: "https://hooks.example.com/merlin", EventTypes: WebhookEventTypes{WebhookEventModelEndpointUpdated}},
+		},
+		{
+			desc:     "Should fail without name",
+			webhook:  &Webhook{URL: "https://hooks.example.com/merlin"},
+			errorMsg: "webhook name is required",
+		},
+		{
+			desc:     "Should fail for relative url",
+			webhook:  &Webhook{Name: "chat-ops", URL: "/merlin"},
+			errorMsg: `invalid webhook url "/merlin": must be an absolute http or https url`,
+		},
+		{
+			desc:     "Should fail for unknown event type",
+			webhook:  &Webhook{Name: "chat-ops", URL: "http://hooks.example.com", EventTypes: WebhookEventTypes{"merlin.model.created"}},
+			errorMsg: "unsupported event type: merlin.model.created",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := tC.webhook.Validate()
+			if tC.errorMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.errorMsg)
+		})
+	}
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	all := &Webhook{}
+	assert.True(t, all.Subscribes(WebhookEventPredictionJobStatusChanged))
+
+	jobOnly := &Webhook{EventTypes: WebhookEventTypes{WebhookEventPredictionJobStatusChanged}}
+	assert.True(t, jobOnly.Subscribes(WebhookEventPredictionJobStatusChanged))
+	assert.False(t, jobOnly.Subscribes(WebhookEventVersionEndpointStatusChanged))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/caraml-dev/merlin/pkg/deployment"
 	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/prometheus/prometheus/promql"
 )
 
 // MetricsType are supported metrics for autoscaling
@@ -21,6 +22,8 @@ const (
 	Concurrency MetricsType = "concurrency"
 	// RPS autoscaling based on throughput (request per seconds)
 	RPS MetricsType = "rps"
+	// PrometheusQuery autoscaling based on the value of an arbitrary Prometheus query (average value per replica)
+	PrometheusQuery MetricsType = "prometheus_query"
 )
 
 var (
@@ -41,6 +44,8 @@ type AutoscalingPolicy struct {
 	MetricsType MetricsType `json:"metrics_type"`
 	// TargetValue specifies the policy value
 	TargetValue float64 `json:"target_value"`
+	// PrometheusQuery is the PromQL expression to be evaluated, only used by prometheus_query metrics type
+	PrometheusQuery string `json:"prometheus_query,omitempty"`
 }
 
 func (r AutoscalingPolicy) Value() (driver.Value, error) {
@@ -58,11 +63,16 @@ func (r *AutoscalingPolicy) Scan(value interface{}) error {
 
 // ValidateAutoscalingPolicy check autoscaling policy is valid and supported by the given deployment mode
 func ValidateAutoscalingPolicy(target *AutoscalingPolicy, mode deployment.Mode) error {
-	// raw deployment only support cpu utilization
-	if mode == deployment.RawDeploymentMode && target.MetricsType != CPUUtilization {
+	// raw deployment only support cpu utilization and prometheus query
+	if mode == deployment.RawDeploymentMode && target.MetricsType != CPUUtilization && target.MetricsType != PrometheusQuery {
 		return merror.NewInvalidInputErrorf("raw_deployment doesn't support %v autoscaling metrics", target)
 	}
 
+	// prometheus query is delivered through an external autoscaler which only target raw deployment
+	if mode != deployment.RawDeploymentMode && target.MetricsType == PrometheusQuery {
+		return merror.NewInvalidInputErrorf("%v autoscaling metrics is only supported by raw_deployment", target.MetricsType)
+	}
+
 	// boundary check for cpu and memory utilization
 	if (target.MetricsType == CPUUtilization || target.MetricsType == MemoryUtilization) &&
 		(target.TargetValue <= 0 || target.TargetValue > 100) {
@@ -75,5 +85,17 @@ func ValidateAutoscalingPolicy(target *AutoscalingPolicy, mode deployment.Mode)
 		return merror.NewInvalidInputErrorf("policy %v is less than or equal to 0", target.MetricsType)
 	}
 
+	if target.MetricsType == PrometheusQuery {
+		if target.PrometheusQuery == "" {
+			return merror.NewInvalidInputErrorf("prometheus_query is required for %v autoscaling metrics", target.MetricsType)
+		}
+		if _, err := promql.ParseExpr(target.PrometheusQuery); err != nil {
+			return merror.NewInvalidInputErrorf("invalid prometheus_query: %v", err)
+		}
+		if target.TargetValue <= 0 {
+			return merror.NewInvalidInputErrorf("policy %v is less than or equal to 0", target.MetricsType)
+		}
+	}
+
 	return nil
 }
@@ -49,6 +49,65 @@ func TestValidateAutoscalingPolicy(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "raw_deployment using prometheus query",
+			args: args{
+				policy: &AutoscalingPolicy{
+					MetricsType:     PrometheusQuery,
+					TargetValue:     100,
+					PrometheusQuery: `sum(kafka_consumergroup_lag{consumergroup="my-model"})`,
+				},
+				mode: deployment.RawDeploymentMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "raw_deployment using prometheus query without query",
+			args: args{
+				policy: &AutoscalingPolicy{
+					MetricsType: PrometheusQuery,
+					TargetValue: 100,
+				},
+				mode: deployment.RawDeploymentMode,
+			},
+			wantErr: true,
+		},
+		{
+			name: "raw_deployment using prometheus query invalid query",
+			args: args{
+				policy: &AutoscalingPolicy{
+					MetricsType:     PrometheusQuery,
+					TargetValue:     100,
+					PrometheusQuery: `sum(kafka_consumergroup_lag{consumergroup="my-model"}`,
+				},
+				mode: deployment.RawDeploymentMode,
+			},
+			wantErr: true,
+		},
+		{
+			name: "raw_deployment using prometheus query invalid value",
+			args: args{
+				policy: &AutoscalingPolicy{
+					MetricsType:     PrometheusQuery,
+					TargetValue:     0,
+					PrometheusQuery: `sum(kafka_consumergroup_lag{consumergroup="my-model"})`,
+				},
+				mode: deployment.RawDeploymentMode,
+			},
+			wantErr: true,
+		},
+		{
+			name: "serverless using prometheus query",
+			args: args{
+				policy: &AutoscalingPolicy{
+					MetricsType:     PrometheusQuery,
+					TargetValue:     100,
+					PrometheusQuery: `sum(kafka_consumergroup_lag{consumergroup="my-model"})`,
+				},
+				mode: deployment.ServerlessDeploymentMode,
+			},
+			wantErr: true,
+		},
 		{
 			name: "serverless using cpu",
 			args: args{
@@ -0,0 +1,98 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/service"
+)
+
+// PredictionJobScheduler creates the prediction jobs of schedules whose run is due
+type PredictionJobScheduler struct {
+	scheduleService    service.PredictionJobScheduleService
+	modelService       service.ModelsService
+	versionService     service.VersionsService
+	environmentService service.EnvironmentService
+}
+
+func NewPredictionJobScheduler(scheduleService service.PredictionJobScheduleService,
+	modelService service.ModelsService,
+	versionService service.VersionsService,
+	environmentService service.EnvironmentService) *PredictionJobScheduler {
+	return &PredictionJobScheduler{
+		scheduleService:    scheduleService,
+		modelService:       modelService,
+		versionService:     versionService,
+		environmentService: environmentService,
+	}
+}
+
+// RunDueSchedules creates a prediction job for every schedule with a due run.
+// Each run is claimed before the job is created, so it's safe to call from multiple API server replicas.
+func (s *PredictionJobScheduler) RunDueSchedules() {
+	ctx := context.Background()
+
+	schedules, err := s.scheduleService.ListDueSchedules(ctx)
+	if err != nil {
+		log.Errorf("unable to list due prediction job schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		if err := s.run(ctx, schedule); err != nil {
+			log.Errorf("unable to run prediction job schedule %s: %v", schedule.Name, err)
+		}
+	}
+}
+
+func (s *PredictionJobScheduler) run(ctx context.Context, schedule *models.PredictionJobSchedule) error {
+	scheduledTime, claimed, err := s.scheduleService.ClaimScheduledRun(ctx, schedule)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	model, err := s.modelService.FindByID(ctx, schedule.ModelID)
+	if err != nil {
+		return fmt.Errorf("unable to find model %d: %w", schedule.ModelID, err)
+	}
+
+	// monitoring url of the version isn't needed to create prediction jobs
+	version, err := s.versionService.FindByID(ctx, schedule.ModelID, schedule.VersionID, config.MonitoringConfig{})
+	if err != nil {
+		return fmt.Errorf("unable to find model version %d: %w", schedule.VersionID, err)
+	}
+
+	env, err := s.environmentService.GetEnvironment(schedule.EnvironmentName)
+	if err != nil {
+		return fmt.Errorf("unable to find environment %s: %w", schedule.EnvironmentName, err)
+	}
+
+	run, err := s.scheduleService.TriggerSchedule(ctx, env, model, version, schedule, scheduledTime, false)
+	if err != nil {
+		return err
+	}
+	if run.JobID != nil {
+		log.Infof("prediction job schedule %s created job %s for run at %s", schedule.Name, *run.JobID, scheduledTime)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/service/mocks"
+)
+
+func TestPredictionJobScheduler_RunDueSchedules(t *testing.T) {
+	scheduledTime := time.Date(2023, 3, 1, 2, 0, 0, 0, time.UTC)
+	model := &models.Model{ID: 1, Name: "my-model"}
+	version := &models.Version{ID: 2, ModelID: 1}
+	env := &models.Environment{Name: "env"}
+	jobID := models.ID(5)
+
+	claimed := &models.PredictionJobSchedule{ID: 1, Name: "claimed", ModelID: 1, VersionID: 2, EnvironmentName: "env"}
+	claimedElsewhere := &models.PredictionJobSchedule{ID: 2, Name: "claimed-elsewhere", ModelID: 1, VersionID: 2, EnvironmentName: "env"}
+	failing := &models.PredictionJobSchedule{ID: 3, Name: "failing", ModelID: 1, VersionID: 2, EnvironmentName: "env"}
+
+	scheduleService := &mocks.PredictionJobScheduleService{}
+	scheduleService.On("ListDueSchedules", mock.Anything).Return([]*models.PredictionJobSchedule{claimed, claimedElsewhere, failing}, nil)
+	scheduleService.On("ClaimScheduledRun", mock.Anything, claimed).Return(scheduledTime, true, nil)
+	scheduleService.On("ClaimScheduledRun", mock.Anything, claimedElsewhere).Return(time.Time{}, false, nil)
+	scheduleService.On("ClaimScheduledRun", mock.Anything, failing).Return(time.Time{}, false, errors.New("db is down"))
+	scheduleService.On("TriggerSchedule", mock.Anything, env, model, version, claimed, scheduledTime, false).
+		Return(&models.PredictionJobScheduleRun{JobID: &jobID}, nil)
+
+	modelService := &mocks.ModelsService{}
+	modelService.On("FindByID", mock.Anything, models.ID(1)).Return(model, nil)
+	versionService := &mocks.VersionsService{}
+	versionService.On("FindByID", mock.Anything, models.ID(1), models.ID(2), config.MonitoringConfig{}).Return(version, nil)
+	environmentService := &mocks.EnvironmentService{}
+	environmentService.On("GetEnvironment", "env").Return(env, nil)
+
+	scheduler := NewPredictionJobScheduler(scheduleService, modelService, versionService, environmentService)
+	scheduler.RunDueSchedules()
+
+	scheduleService.AssertExpectations(t)
+	scheduleService.AssertNumberOfCalls(t, "TriggerSchedule", 1)
+	modelService.AssertNumberOfCalls(t, "FindByID", 1)
+}
+
+func TestPredictionJobScheduler_RunDueSchedulesListError(t *testing.T) {
+	scheduleService := &mocks.PredictionJobScheduleService{}
+	scheduleService.On("ListDueSchedules", context.Background()).Return(nil, errors.New("db is down"))
+
+	scheduler := NewPredictionJobScheduler(scheduleService, nil, nil, nil)
+	scheduler.RunDueSchedules()
+
+	scheduleService.AssertNotCalled(t, "ClaimScheduledRun", mock.Anything, mock.Anything)
+}
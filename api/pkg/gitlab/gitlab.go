@@ -23,6 +23,7 @@ type Client interface {
 	GetFileContent(opt GetFileContentOptions) (string, error)
 	CreateFile(opt CreateFileOptions) error
 	UpdateFile(opt UpdateFileOptions) error
+	DeleteFile(opt DeleteFileOptions) error
 }
 
 type client struct {
@@ -105,3 +106,24 @@ func (c *client) UpdateFile(opt UpdateFileOptions) error {
 	_, _, err := c.git.RepositoryFiles.UpdateFile(opt.Repository, opt.FileName, updateFile)
 	return err
 }
+
+type DeleteFileOptions struct {
+	Repository    string
+	Branch        string
+	FileName      string
+	CommitMessage string
+	AuthorEmail   string
+	AuthorName    string
+}
+
+func (c *client) DeleteFile(opt DeleteFileOptions) error {
+	deleteFile := &gitlab.DeleteFileOptions{
+		Branch:        &opt.Branch,
+		CommitMessage: &opt.CommitMessage,
+		AuthorEmail:   &opt.AuthorEmail,
+		AuthorName:    &opt.AuthorName,
+	}
+
+	_, err := c.git.RepositoryFiles.DeleteFile(opt.Repository, opt.FileName, deleteFile)
+	return err
+}
@@ -87,4 +87,15 @@ func Test_client(t *testing.T) {
 	}
 	err = client.UpdateFile(updateOpt)
 	assert.Nil(t, err)
+
+	deleteOpt := DeleteFileOptions{
+		Repository:    "test",
+		Branch:        "master",
+		FileName:      ".gitignore",
+		CommitMessage: "Delete",
+		AuthorEmail:   "merlin-dev@gojek.com",
+		AuthorName:    "merlin-dev@gojek.com",
+	}
+	err = client.DeleteFile(deleteOpt)
+	assert.Nil(t, err)
 }
@@ -26,6 +26,20 @@ func (_m *Client) CreateFile(opt gitlab.CreateFileOptions) error {
 	return r0
 }
 
+// DeleteFile provides a mock function with given fields: opt
+func (_m *Client) DeleteFile(opt gitlab.DeleteFileOptions) error {
+	ret := _m.Called(opt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(gitlab.DeleteFileOptions) error); ok {
+		r0 = rf(opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetFileContent provides a mock function with given fields: opt
 func (_m *Client) GetFileContent(opt gitlab.GetFileContentOptions) (string, error) {
 	ret := _m.Called(opt)
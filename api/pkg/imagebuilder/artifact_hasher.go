@@ -0,0 +1,88 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagebuilder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+const gcsScheme = "gs://"
+
+// ArtifactHasher computes a digest of the content of a model artifact
+type ArtifactHasher interface {
+	// Hash returns the digest of all the files under the given artifact URI
+	Hash(ctx context.Context, artifactURI string) (string, error)
+}
+
+type gcsArtifactHasher struct{}
+
+// NewGCSArtifactHasher creates an ArtifactHasher of artifacts stored in GCS. The digest is computed from the checksums
+// of the objects kept by GCS, so that the artifact doesn't need to be downloaded.
+func NewGCSArtifactHasher() ArtifactHasher {
+	return &gcsArtifactHasher{}
+}
+
+func (h *gcsArtifactHasher) Hash(ctx context.Context, artifactURI string) (string, error) {
+	if !strings.HasPrefix(artifactURI, gcsScheme) {
+		return "", fmt.Errorf("artifact %s isn't stored in gcs", artifactURI)
+	}
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(artifactURI, gcsScheme), "/")
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed creating gcs client: %w", err)
+	}
+	defer client.Close() //nolint:errcheck
+
+	var checksums []string
+	objects := client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := objects.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed listing artifact %s: %w", artifactURI, err)
+		}
+		// composite objects don't have a md5 hash, but always have a crc32c checksum
+		checksums = append(checksums, fmt.Sprintf("%s %x %d", strings.TrimPrefix(attrs.Name, prefix), attrs.MD5, attrs.CRC32C))
+	}
+	if len(checksums) == 0 {
+		return "", fmt.Errorf("artifact %s is empty", artifactURI)
+	}
+
+	sort.Strings(checksums)
+	return hashStrings(checksums...), nil
+}
+
+// hashStrings returns the hex encoded sha256 digest of the given strings
+func hashStrings(values ...string) string {
+	digest := sha256.New()
+	for _, value := range values {
+		digest.Write([]byte(value))
+		digest.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(digest.Sum(nil))
+}
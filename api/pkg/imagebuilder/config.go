@@ -37,6 +37,10 @@ type Config struct {
 	KanikoImage string
 	// Kaniko kubernetes service account
 	KanikoServiceAccount string
+	// Build config of the model versions deployed to each environment, keyed by environment name
+	EnvironmentConfigs map[string]EnvironmentConfig
+	// Reuse the image of another version of the model built from the same artifact and base image
+	ReuseImageByArtifactHash bool
 	// Kubernetes resource request and limits for kaniko
 	Resources cfg.ResourceRequestsLimits
 	// Tolerations for Jobs Specification
@@ -56,3 +60,14 @@ type Config struct {
 	// Environment (dev, staging or prod)
 	Environment string
 }
+
+// EnvironmentConfig is the build config of the model versions deployed to an environment
+type EnvironmentConfig struct {
+	// Kaniko caches the layers of the images in KanikoCacheRepo, <image name>/cache if empty,
+	// for KanikoCacheTTL, 2 weeks if empty, unless KanikoCacheDisabled
+	KanikoCacheDisabled bool
+	KanikoCacheRepo     string
+	KanikoCacheTTL      time.Duration
+	// Base images, keyed by name, that can be chosen by the build config of a model version
+	AllowedBaseImages cfg.BaseImageConfigs
+}
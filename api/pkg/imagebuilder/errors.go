@@ -16,6 +16,7 @@ package imagebuilder
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -42,3 +43,18 @@ func (e ErrUnableToCreateJobSpec) Error() string {
 func (e ErrUnableToBuildImage) Error() string {
 	return fmt.Sprintf("error building pyfunc image: %s", e.Message)
 }
+
+// ErrBaseImageNotAllowed is returned if the base image chosen by the build config of a model version isn't allowed
+// by the environment, or by any environment if Environment is empty
+type ErrBaseImageNotAllowed struct {
+	BaseImage         string
+	Environment       string
+	AllowedBaseImages []string
+}
+
+func (e ErrBaseImageNotAllowed) Error() string {
+	if e.Environment != "" {
+		return fmt.Sprintf("base image %s is not allowed in environment %s, allowed base images: [%s]", e.BaseImage, e.Environment, strings.Join(e.AllowedBaseImages, ", "))
+	}
+	return fmt.Sprintf("base image %s is not allowed, allowed base images: [%s]", e.BaseImage, strings.Join(e.AllowedBaseImages, ", "))
+}
@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	cfg "github.com/caraml-dev/merlin/config"
 	"github.com/caraml-dev/merlin/log"
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
@@ -44,12 +45,19 @@ import (
 var maxCheckImageRetry uint64 = 2
 
 type ImageBuilder interface {
-	// BuildImage build docker image for the given model version
+	// BuildImage build docker image for the given model version to be deployed to the environment
 	// return docker image ref
-	BuildImage(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) (string, error)
+	BuildImage(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, environmentName string) (string, error)
 	// GetContainers return reference to container used to build the docker image of a model version
 	GetContainers(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) ([]*models.Container, error)
-	GetMainAppPath(version *models.Version) (string, error)
+	GetMainAppPath(version *models.Version, environmentName string) (string, error)
+	// GetBuildInputs resolves the build config of a model version into the inputs of its docker image build,
+	// its base image has to be allowed by one of the environments
+	GetBuildInputs(version *models.Version) (*models.VersionBuildInputs, error)
+	// StartBuild starts building the docker image of a model version to be deployed to the environment without waiting for its completion
+	StartBuild(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, environmentName string) (*models.ImageBuild, error)
+	// GetBuild returns the status of the docker image build of a model version
+	GetBuild(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) (*models.ImageBuild, error)
 }
 
 type nameGenerator interface {
@@ -60,9 +68,10 @@ type nameGenerator interface {
 }
 
 type imageBuilder struct {
-	kubeClient    kubernetes.Interface
-	config        Config
-	nameGenerator nameGenerator
+	kubeClient     kubernetes.Interface
+	config         Config
+	nameGenerator  nameGenerator
+	artifactHasher ArtifactHasher
 }
 
 const (
@@ -72,6 +81,9 @@ const (
 
 	gacEnvKey  = "GOOGLE_APPLICATION_CREDENTIALS"
 	saFilePath = "/secret/kaniko-secret.json"
+
+	// labelImageBuild identifies the kaniko jobs building the image of a model version for any environment
+	labelImageBuild = "image-build"
 )
 
 var (
@@ -80,18 +92,23 @@ var (
 )
 
 func newImageBuilder(kubeClient kubernetes.Interface, config Config, nameGenerator nameGenerator) ImageBuilder {
-	return &imageBuilder{
+	builder := &imageBuilder{
 		kubeClient:    kubeClient,
 		config:        config,
 		nameGenerator: nameGenerator,
 	}
+	if config.ReuseImageByArtifactHash {
+		builder.artifactHasher = NewGCSArtifactHasher()
+	}
+	return builder
 }
 
 // GetMainAppPath Returns the path to run the main.py of batch predictor, as configured via env var
-func (c *imageBuilder) GetMainAppPath(version *models.Version) (string, error) {
-	baseImageTag, ok := c.config.BaseImages[version.PythonVersion]
-	if !ok {
-		return "", fmt.Errorf("No matching base image for tag %s", version.PythonVersion)
+// or by the allowed base images of the environment
+func (c *imageBuilder) GetMainAppPath(version *models.Version, environmentName string) (string, error) {
+	baseImageTag, err := c.getBaseImage(version, environmentName)
+	if err != nil {
+		return "", err
 	}
 
 	if baseImageTag.MainAppPath == "" {
@@ -101,84 +118,322 @@ func (c *imageBuilder) GetMainAppPath(version *models.Version) (string, error) {
 	return baseImageTag.MainAppPath, nil
 }
 
-// BuildImage build a docker image for the given model version
+// GetBuildInputs resolves the build config of a model version into the inputs of its docker image build,
+// its base image has to be allowed by one of the environments
+func (c *imageBuilder) GetBuildInputs(version *models.Version) (*models.VersionBuildInputs, error) {
+	return c.getBuildInputs(version, "")
+}
+
+// getBuildInputs resolves the build inputs of a model version deployed to the environment, or to any environment if it's empty
+func (c *imageBuilder) getBuildInputs(version *models.Version, environmentName string) (*models.VersionBuildInputs, error) {
+	baseImage, err := c.getBaseImage(version, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := &models.VersionBuildInputs{
+		BaseImage:       baseImage.ImageName,
+		DockerfilePath:  baseImage.DockerfilePath,
+		BuildContextURI: baseImage.BuildContextURI,
+	}
+	if version.BuildConfig != nil {
+		if err := version.BuildConfig.Validate(); err != nil {
+			return nil, err
+		}
+		inputs.AptPackages = version.BuildConfig.AptPackages
+		inputs.BuildArgs = version.BuildConfig.BuildArgs
+	}
+	return inputs, nil
+}
+
+// getBaseImage returns the base image chosen by the build config of the version and allowed by the environment,
+// or by any environment if it's empty, or the base image of its python version
+func (c *imageBuilder) getBaseImage(version *models.Version, environmentName string) (cfg.BaseImageConfig, error) {
+	if version.BuildConfig == nil || version.BuildConfig.BaseImage == "" {
+		baseImageTag, ok := c.config.BaseImages[version.PythonVersion]
+		if !ok {
+			return cfg.BaseImageConfig{}, fmt.Errorf("No matching base image for tag %s", version.PythonVersion)
+		}
+		return baseImageTag, nil
+	}
+
+	environmentNames := []string{environmentName}
+	if environmentName == "" {
+		environmentNames = make([]string, 0, len(c.config.EnvironmentConfigs))
+		for name := range c.config.EnvironmentConfigs {
+			environmentNames = append(environmentNames, name)
+		}
+		sort.Strings(environmentNames)
+	}
+
+	notAllowed := ErrBaseImageNotAllowed{BaseImage: version.BuildConfig.BaseImage, Environment: environmentName}
+	for _, name := range environmentNames {
+		allowedBaseImages := c.config.EnvironmentConfigs[name].AllowedBaseImages
+		if baseImage, ok := allowedBaseImages[version.BuildConfig.BaseImage]; ok {
+			return baseImage, nil
+		}
+		for allowed := range allowedBaseImages {
+			notAllowed.AllowedBaseImages = append(notAllowed.AllowedBaseImages, allowed)
+		}
+	}
+	notAllowed.AllowedBaseImages = uniqueSorted(notAllowed.AllowedBaseImages)
+	return cfg.BaseImageConfig{}, notAllowed
+}
+
+// BuildImage build a docker image for the given model version to be deployed to the environment
 // Returns the docker image ref
-func (c *imageBuilder) BuildImage(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) (string, error) {
+func (c *imageBuilder) BuildImage(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, environmentName string) (string, error) {
+	build, err := c.StartBuild(ctx, project, model, version, environmentName)
+	if err != nil {
+		return "", err
+	}
+	if build.Status == models.ImageBuildStatusSucceeded {
+		return build.ImageRef, nil
+	}
+
+	// wait until pod is created successfully
+	err = c.waitJobCompleted(ctx, build.JobName)
+	if err != nil {
+		return "", err
+	}
+
+	return build.ImageRef, nil
+}
+
+// StartBuild starts building a docker image for the given model version without waiting for its completion.
+// The existing image of the model version, or of another version of the model built from the same artifact, is reused.
+// The build config of the environment the version is deployed to, e.g. its kaniko cache, is used to build a new image.
+func (c *imageBuilder) StartBuild(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, environmentName string) (*models.ImageBuild, error) {
+	build := c.newImageBuild(project, model, version)
+	build.JobName = c.builderJobName(project, model, version, environmentName)
+
 	// check for existing image
 	imageName := c.nameGenerator.generateDockerImageName(project, model)
-	imageExists := c.imageExists(imageName, version.ID.String())
+	if c.imageExists(imageName, version.ID.String()) {
+		log.Infof("Image %s already exists. Skipping build.", build.ImageRef)
+		build.Status = models.ImageBuildStatusSucceeded
+		return build, nil
+	}
 
-	imageRef := c.imageRef(project, model, version)
-	if imageExists {
-		log.Infof("Image %s already exists. Skipping build.", imageRef)
-		return imageRef, nil
+	build.ArtifactHash = c.artifactHash(ctx, version, environmentName)
+	if c.reuseImage(imageName, build) {
+		log.Infof("Image %s of the same artifact already exists. Skipping build of version %s.", build.ImageRef, version.ID)
+		// the reused image is tagged with the version so that it's found like an image built for the version
+		if err := c.tagImage(imageName, artifactTag(build.ArtifactHash), version.ID.String()); err != nil {
+			log.Warnf("unable to tag image %s with version %s: %v", build.ImageRef, version.ID, err)
+		} else {
+			build.ImageRef = c.imageRef(project, model, version)
+		}
+		return build, nil
 	}
 
 	// check for existing job
 	jobClient := c.kubeClient.BatchV1().Jobs(c.config.BuildNamespace)
-	job, err := jobClient.Get(ctx, c.nameGenerator.generateBuilderJobName(project, model, version), metav1.GetOptions{})
+	job, err := jobClient.Get(ctx, build.JobName, metav1.GetOptions{})
 	if err != nil {
 		if !kerrors.IsNotFound(err) {
 			log.Errorf("error retrieving job status: %v", err)
-			return "", ErrUnableToGetJobStatus
+			return nil, ErrUnableToGetJobStatus
 		}
 
-		jobSpec, err := c.createKanikoJobSpec(project, model, version)
+		job, err = c.createJob(ctx, project, model, version, build.ArtifactHash, environmentName)
 		if err != nil {
-			log.Errorf("unable to create job spec %s, error: %v", imageRef, err)
-			return "", ErrUnableToCreateJobSpec{
-				Message: err.Error(),
-			}
+			return nil, err
+		}
+	} else if job.Status.Failed != 0 {
+		// job already created before so we have to delete it first if it failed
+		err = jobClient.Delete(ctx, job.Name, metav1.DeleteOptions{})
+		if err != nil {
+			log.Errorf("error deleting job: %v", err)
+			return nil, ErrDeleteFailedJob
 		}
 
-		job, err = jobClient.Create(ctx, jobSpec, metav1.CreateOptions{})
+		job, err = c.createJob(ctx, project, model, version, build.ArtifactHash, environmentName)
 		if err != nil {
-			log.Errorf("unable to build image %s, error: %v", imageRef, err)
-			return "", ErrUnableToBuildImage{
-				Message: err.Error(),
-			}
+			return nil, err
 		}
-	} else {
-		if job.Status.Failed != 0 {
-			// job already created before so we have to delete it first if it failed
-			err = jobClient.Delete(ctx, job.Name, metav1.DeleteOptions{})
-			if err != nil {
-				log.Errorf("error deleting job: %v", err)
-				return "", ErrDeleteFailedJob
-			}
+	}
 
-			jobSpec, err := c.createKanikoJobSpec(project, model, version)
-			if err != nil {
-				log.Errorf("unable to create job spec %s, error: %v", imageRef, err)
-				return "", ErrUnableToCreateJobSpec{
-					Message: err.Error(),
-				}
-			}
+	c.updateBuildFromJob(ctx, build, job)
+	return build, nil
+}
 
-			job, err = jobClient.Create(ctx, jobSpec, metav1.CreateOptions{})
-			if err != nil {
-				log.Errorf("unable to build image %s, error: %v", imageRef, err)
-				return "", ErrUnableToBuildImage{
-					Message: err.Error(),
-				}
+// GetBuild returns the status of the docker image build of the given model version
+func (c *imageBuilder) GetBuild(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) (*models.ImageBuild, error) {
+	build := c.newImageBuild(project, model, version)
+
+	// the version is built by the job of the environment it's deployed to first
+	jobs, err := c.kubeClient.BatchV1().Jobs(c.config.BuildNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", labelImageBuild, build.JobName),
+	})
+	if err != nil {
+		log.Errorf("error retrieving job status: %v", err)
+		return nil, ErrUnableToGetJobStatus
+	}
+	if len(jobs.Items) > 0 {
+		job := jobs.Items[0]
+		for _, item := range jobs.Items[1:] {
+			if item.CreationTimestamp.After(job.CreationTimestamp.Time) {
+				job = item
 			}
 		}
+		build.JobName = job.Name
+		c.updateBuildFromJob(ctx, build, &job)
+		return build, nil
 	}
 
-	// wait until pod is created successfully
-	err = c.waitJobCompleted(ctx, job)
+	// the job is deleted after the retention period, or the image was never built by merlin
+	imageName := c.nameGenerator.generateDockerImageName(project, model)
+	if c.imageExists(imageName, version.ID.String()) {
+		build.Status = models.ImageBuildStatusSucceeded
+		return build, nil
+	}
+
+	// the environment of the build isn't known, its base image is resolved by any environment allowing it
+	build.ArtifactHash = c.artifactHash(ctx, version, "")
+	c.reuseImage(imageName, build)
+	return build, nil
+}
+
+func (c *imageBuilder) newImageBuild(project mlp.Project, model *models.Model, version *models.Version) *models.ImageBuild {
+	return &models.ImageBuild{
+		VersionID: version.ID,
+		Status:    models.ImageBuildStatusNotBuilt,
+		ImageRef:  c.imageRef(project, model, version),
+		JobName:   c.nameGenerator.generateBuilderJobName(project, model, version),
+		Namespace: c.config.BuildNamespace,
+		Cluster:   c.config.ClusterName,
+	}
+}
+
+// builderJobName returns the name of the kaniko job building the image of the version for the environment,
+// the environments build the image with their own build config
+func (c *imageBuilder) builderJobName(project mlp.Project, model *models.Model, version *models.Version, environmentName string) string {
+	jobName := c.nameGenerator.generateBuilderJobName(project, model, version)
+	if environmentName == "" {
+		return jobName
+	}
+	return fmt.Sprintf("%s-%s", jobName, environmentName)
+}
+
+// artifactHash identifies the artifact of the model version and the base image it's built from,
+// it's empty if the image can't be reused
+func (c *imageBuilder) artifactHash(ctx context.Context, version *models.Version, environmentName string) string {
+	if c.artifactHasher == nil {
+		return ""
+	}
+
+	inputs, err := c.getBuildInputs(version, environmentName)
 	if err != nil {
-		return "", err
+		return ""
+	}
+
+	hash, err := c.artifactHasher.Hash(ctx, fmt.Sprintf("%s/model", version.ArtifactURI))
+	if err != nil {
+		log.Warnf("unable to compute hash of artifact %s: %v", version.ArtifactURI, err)
+		return ""
 	}
 
-	return imageRef, nil
+	values := []string{hash, inputs.BaseImage, inputs.BuildContextURI, inputs.DockerfilePath, c.config.ContextSubPath}
+	if len(inputs.AptPackages) > 0 {
+		values = append(values, "apt:"+strings.Join(inputs.AptPackages, " "))
+	}
+	values = append(values, sortedBuildArgs(inputs.BuildArgs)...)
+	return hashStrings(values...)
+}
+
+// uniqueSorted returns the sorted values without duplicates
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	unique := make([]string, 0, len(values))
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// sortedBuildArgs returns the build args as NAME=value sorted by name
+func sortedBuildArgs(buildArgs map[string]string) []string {
+	args := make([]string, 0, len(buildArgs))
+	for name, value := range buildArgs {
+		args = append(args, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(args)
+	return args
+}
+
+// reuseImage uses the image tagged with the artifact hash of the build if it exists
+func (c *imageBuilder) reuseImage(imageName string, build *models.ImageBuild) bool {
+	if build.ArtifactHash == "" || !c.imageExists(imageName, artifactTag(build.ArtifactHash)) {
+		return false
+	}
+
+	build.ImageRef = fmt.Sprintf("%s:%s", imageName, artifactTag(build.ArtifactHash))
+	build.Reused = true
+	build.Status = models.ImageBuildStatusSucceeded
+	return true
+}
+
+// artifactTag is the additional tag of an image, shared by the versions of a model having the same artifact
+func artifactTag(artifactHash string) string {
+	return "artifact-" + artifactHash
+}
+
+func (c *imageBuilder) createJob(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, artifactHash, environmentName string) (*batchv1.Job, error) {
+	imageRef := c.imageRef(project, model, version)
+	jobSpec, err := c.createKanikoJobSpec(project, model, version, artifactHash, environmentName)
+	if err != nil {
+		log.Errorf("unable to create job spec %s, error: %v", imageRef, err)
+		return nil, ErrUnableToCreateJobSpec{
+			Message: err.Error(),
+		}
+	}
+
+	job, err := c.kubeClient.BatchV1().Jobs(c.config.BuildNamespace).Create(ctx, jobSpec, metav1.CreateOptions{})
+	if err != nil {
+		log.Errorf("unable to build image %s, error: %v", imageRef, err)
+		return nil, ErrUnableToBuildImage{
+			Message: err.Error(),
+		}
+	}
+	return job, nil
+}
+
+// updateBuildFromJob sets the status of the build from the kaniko job building the image
+func (c *imageBuilder) updateBuildFromJob(ctx context.Context, build *models.ImageBuild, job *batchv1.Job) {
+	if job.Status.StartTime != nil {
+		startedAt := job.Status.StartTime.Time
+		build.StartedAt = &startedAt
+	}
+	if job.Status.CompletionTime != nil {
+		completedAt := job.Status.CompletionTime.Time
+		build.CompletedAt = &completedAt
+	}
+
+	build.Status = models.ImageBuildStatusBuilding
+	if job.Status.Succeeded > 0 {
+		build.Status = models.ImageBuildStatusSucceeded
+		return
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			build.Status = models.ImageBuildStatusFailed
+			build.Message = c.jobErrorMessage(ctx, job.Name)
+			if build.Message == "" {
+				build.Message = condition.Message
+			}
+			return
+		}
+	}
 }
 
 // GetContainers return container used for building a docker image for the given model version
 func (c *imageBuilder) GetContainers(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) ([]*models.Container, error) {
 	podClient := c.kubeClient.CoreV1().Pods(c.config.BuildNamespace)
 	pods, err := podClient.List(ctx, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("job-name=%s", c.nameGenerator.generateBuilderJobName(project, model, version)),
+		LabelSelector: fmt.Sprintf("%s=%s", labelImageBuild, c.nameGenerator.generateBuilderJobName(project, model, version)),
 		FieldSelector: "status.phase!=Pending",
 	})
 	if err != nil {
@@ -246,17 +501,12 @@ func (c *imageBuilder) imageExists(imageName, imageTag string) bool {
 // https://github.com/google/go-containerregistry/blob/master/cmd/crane/README.md
 // https://github.com/google/go-containerregistry/blob/master/pkg/v1/google/README.md
 func (c *imageBuilder) imageRefExists(imageName, imageTag string) (bool, error) {
-	keychain := authn.DefaultKeychain
-	if strings.Contains(c.config.DockerRegistry, "gcr.io") {
-		keychain = google.Keychain
-	}
-
 	repo, err := name.NewRepository(imageName)
 	if err != nil {
 		return false, fmt.Errorf("unable to parse docker repository %s: %w", imageName, err)
 	}
 
-	tags, err := remote.List(repo, remote.WithAuthFromKeychain(keychain))
+	tags, err := remote.List(repo, remote.WithAuthFromKeychain(c.keychain()))
 	if err != nil {
 		var terr *transport.Error
 		if errors.As(err, &terr) {
@@ -283,21 +533,46 @@ func (c *imageBuilder) imageRefExists(imageName, imageTag string) (bool, error)
 	return false, nil
 }
 
-func (c *imageBuilder) waitJobCompleted(ctx context.Context, job *batchv1.Job) error {
+// tagImage adds the new tag to the image with the given tag
+func (c *imageBuilder) tagImage(imageName, imageTag, newTag string) error {
+	ref, err := name.NewTag(fmt.Sprintf("%s:%s", imageName, imageTag))
+	if err != nil {
+		return fmt.Errorf("unable to parse image %s: %w", imageName, err)
+	}
+	tag, err := name.NewTag(fmt.Sprintf("%s:%s", imageName, newTag))
+	if err != nil {
+		return fmt.Errorf("unable to parse image %s: %w", imageName, err)
+	}
+
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(c.keychain()))
+	if err != nil {
+		return fmt.Errorf("error getting image %s: %w", ref, err)
+	}
+	return remote.Tag(tag, desc, remote.WithAuthFromKeychain(c.keychain()))
+}
+
+// keychain returns the credentials of the docker registry
+func (c *imageBuilder) keychain() authn.Keychain {
+	if strings.Contains(c.config.DockerRegistry, "gcr.io") {
+		return google.Keychain
+	}
+	return authn.DefaultKeychain
+}
+
+func (c *imageBuilder) waitJobCompleted(ctx context.Context, jobName string) error {
 	timeout := time.After(c.config.BuildTimeoutDuration)
 	ticker := time.NewTicker(time.Second * tickDurationSecond)
 	jobClient := c.kubeClient.BatchV1().Jobs(c.config.BuildNamespace)
-	podClient := c.kubeClient.CoreV1().Pods(c.config.BuildNamespace)
 
 	for {
 		select {
 		case <-timeout:
-			log.Errorf("timeout waiting for kaniko job completion %s", job.Name)
+			log.Errorf("timeout waiting for kaniko job completion %s", jobName)
 			return ErrTimeoutBuilImage
 		case <-ticker.C:
-			j, err := jobClient.Get(ctx, job.Name, metav1.GetOptions{})
+			j, err := jobClient.Get(ctx, jobName, metav1.GetOptions{})
 			if err != nil {
-				log.Errorf("unable to get job status for job %s: %v", job.Name, err)
+				log.Errorf("unable to get job status for job %s: %v", jobName, err)
 				return ErrUnableToBuildImage{
 					Message: err.Error(),
 				}
@@ -307,28 +582,37 @@ func (c *imageBuilder) waitJobCompleted(ctx context.Context, job *batchv1.Job) e
 				// successfully created pod
 				return nil
 			} else if j.Status.Failed == 1 {
-				podList, err := podClient.List(ctx, metav1.ListOptions{
-					LabelSelector: fmt.Sprintf("job-name=%s", j.Name),
-				})
-				pods := podList.Items
-				errMessage := ""
-				if err == nil && len(pods) > 0 {
-					sort.Slice(pods, func(i, j int) bool {
-						return pods[j].CreationTimestamp.Unix() > pods[i].CreationTimestamp.Unix()
-					})
-					errMessage = pods[0].Status.ContainerStatuses[0].State.Terminated.Message
-				}
-				log.Errorf("failed building pyfunc image %s: %v", job.Name, j.Status)
+				log.Errorf("failed building pyfunc image %s: %v", jobName, j.Status)
 				return ErrUnableToBuildImage{
-					Message: errMessage,
+					Message: c.jobErrorMessage(ctx, jobName),
 				}
 			}
 		}
 	}
 }
 
-func (c *imageBuilder) createKanikoJobSpec(project mlp.Project, model *models.Model, version *models.Version) (*batchv1.Job, error) {
-	kanikoPodName := c.nameGenerator.generateBuilderJobName(project, model, version)
+// jobErrorMessage returns the termination message of the kaniko container of a failed job
+func (c *imageBuilder) jobErrorMessage(ctx context.Context, jobName string) string {
+	podList, err := c.kubeClient.CoreV1().Pods(c.config.BuildNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("job-name=%s", jobName),
+	})
+	if err != nil || len(podList.Items) == 0 {
+		return ""
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Unix() > pods[i].CreationTimestamp.Unix()
+	})
+	statuses := pods[0].Status.ContainerStatuses
+	if len(statuses) == 0 || statuses[0].State.Terminated == nil {
+		return ""
+	}
+	return statuses[0].State.Terminated.Message
+}
+
+func (c *imageBuilder) createKanikoJobSpec(project mlp.Project, model *models.Model, version *models.Version, artifactHash, environmentName string) (*batchv1.Job, error) {
+	kanikoPodName := c.builderJobName(project, model, version, environmentName)
 	imageRef := c.imageRef(project, model, version)
 
 	metadata := models.Metadata{
@@ -338,6 +622,8 @@ func (c *imageBuilder) createKanikoJobSpec(project mlp.Project, model *models.Mo
 		Team:      project.Team,
 		Labels:    models.MergeProjectVersionLabels(project.Labels, version.Labels),
 	}
+	labels := metadata.ToLabel()
+	labels[labelImageBuild] = c.nameGenerator.generateBuilderJobName(project, model, version)
 
 	annotations := make(map[string]string)
 	if !c.config.SafeToEvict {
@@ -347,20 +633,40 @@ func (c *imageBuilder) createKanikoJobSpec(project mlp.Project, model *models.Mo
 		annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"] = "false"
 	}
 
-	baseImageTag, ok := c.config.BaseImages[version.PythonVersion]
-	if !ok {
-		return nil, fmt.Errorf("No matching base image for tag %s", version.PythonVersion)
+	inputs, err := c.getBuildInputs(version, environmentName)
+	if err != nil {
+		return nil, err
 	}
 
 	kanikoArgs := []string{
-		fmt.Sprintf("--dockerfile=%s", baseImageTag.DockerfilePath),
-		fmt.Sprintf("--context=%s", baseImageTag.BuildContextURI),
+		fmt.Sprintf("--dockerfile=%s", inputs.DockerfilePath),
+		fmt.Sprintf("--context=%s", inputs.BuildContextURI),
 		fmt.Sprintf("--build-arg=MODEL_URL=%s/model", version.ArtifactURI),
-		fmt.Sprintf("--build-arg=BASE_IMAGE=%s", baseImageTag.ImageName),
-		fmt.Sprintf("--destination=%s", imageRef),
-		"--cache=true",
-		"--single-snapshot",
+		fmt.Sprintf("--build-arg=BASE_IMAGE=%s", inputs.BaseImage),
+	}
+	if len(inputs.AptPackages) > 0 {
+		kanikoArgs = append(kanikoArgs, fmt.Sprintf("--build-arg=APT_PACKAGES=%s", strings.Join(inputs.AptPackages, " ")))
+	}
+	for _, buildArg := range sortedBuildArgs(inputs.BuildArgs) {
+		kanikoArgs = append(kanikoArgs, fmt.Sprintf("--build-arg=%s", buildArg))
+	}
+	kanikoArgs = append(kanikoArgs, fmt.Sprintf("--destination=%s", imageRef))
+	if artifactHash != "" {
+		// the image is also tagged with the artifact hash so that it can be reused by other versions of the model
+		kanikoArgs = append(kanikoArgs, fmt.Sprintf("--destination=%s:%s", c.nameGenerator.generateDockerImageName(project, model), artifactTag(artifactHash)))
+	}
+	// the layers are cached unless disabled by the environment, environments without build config use kaniko's default cache
+	envConfig := c.config.EnvironmentConfigs[environmentName]
+	if !envConfig.KanikoCacheDisabled {
+		kanikoArgs = append(kanikoArgs, "--cache=true")
+		if envConfig.KanikoCacheRepo != "" {
+			kanikoArgs = append(kanikoArgs, fmt.Sprintf("--cache-repo=%s", envConfig.KanikoCacheRepo))
+		}
+		if envConfig.KanikoCacheTTL != 0 {
+			kanikoArgs = append(kanikoArgs, fmt.Sprintf("--cache-ttl=%s", envConfig.KanikoCacheTTL))
+		}
 	}
+	kanikoArgs = append(kanikoArgs, "--single-snapshot")
 
 	if c.config.ContextSubPath != "" {
 		kanikoArgs = append(kanikoArgs, fmt.Sprintf("--context-sub-path=%s", c.config.ContextSubPath))
@@ -422,7 +728,7 @@ func (c *imageBuilder) createKanikoJobSpec(project mlp.Project, model *models.Mo
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        kanikoPodName,
 			Namespace:   c.config.BuildNamespace,
-			Labels:      metadata.ToLabel(),
+			Labels:      labels,
 			Annotations: annotations,
 		},
 		Spec: batchv1.JobSpec{
@@ -432,7 +738,7 @@ func (c *imageBuilder) createKanikoJobSpec(project mlp.Project, model *models.Mo
 			ActiveDeadlineSeconds:   &activeDeadlineSeconds,
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      metadata.ToLabel(),
+					Labels:      labels,
 					Annotations: annotations,
 				},
 				Spec: v1.PodSpec{
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -240,7 +241,7 @@ func TestBuildImage(t *testing.T) {
 			existingJob: nil,
 			wantCreateJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -251,6 +252,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -272,6 +274,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -350,7 +353,7 @@ func TestBuildImage(t *testing.T) {
 			existingJob: nil,
 			wantCreateJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -361,6 +364,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/component":    "image-builder",
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -382,6 +386,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/component":    "image-builder",
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -438,7 +443,7 @@ func TestBuildImage(t *testing.T) {
 			existingJob: nil,
 			wantCreateJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -449,6 +454,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -470,6 +476,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -576,7 +583,7 @@ func TestBuildImage(t *testing.T) {
 			existingJob: nil,
 			wantCreateJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -587,6 +594,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -608,6 +616,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -724,7 +733,7 @@ func TestBuildImage(t *testing.T) {
 			existingJob: nil,
 			wantCreateJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -735,6 +744,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -756,6 +766,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -866,7 +877,7 @@ func TestBuildImage(t *testing.T) {
 			},
 			existingJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -877,6 +888,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -898,6 +910,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -975,7 +988,7 @@ func TestBuildImage(t *testing.T) {
 			},
 			existingJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -986,6 +999,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -1007,6 +1021,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -1087,7 +1102,7 @@ func TestBuildImage(t *testing.T) {
 			},
 			existingJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -1098,6 +1113,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -1119,6 +1135,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -1187,7 +1204,7 @@ func TestBuildImage(t *testing.T) {
 			},
 			wantCreateJob: &batchv1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+					Name:      fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 					Namespace: config.BuildNamespace,
 					Labels: map[string]string{
 						"gojek.com/app":          model.Name,
@@ -1198,6 +1215,7 @@ func TestBuildImage(t *testing.T) {
 						"gojek.com/team":         project.Team,
 						"sample":                 "true",
 						"test":                   "true",
+						"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 					},
 					Annotations: map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -1219,6 +1237,7 @@ func TestBuildImage(t *testing.T) {
 								"gojek.com/team":         project.Team,
 								"sample":                 "true",
 								"test":                   "true",
+								"image-build":            fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 							Annotations: map[string]string{
 								"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
@@ -1283,7 +1302,7 @@ func TestBuildImage(t *testing.T) {
 				},
 				Status: batchv1.JobStatus{},
 			},
-			wantDeleteJobName: fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+			wantDeleteJobName: fmt.Sprintf("%s-%s-%s-id-dev", project.Name, model.Name, modelVersion.ID),
 			wantImageRef:      fmt.Sprintf("%s/%s-%s:%s", config.DockerRegistry, project.Name, model.Name, modelVersion.ID),
 			config:            config,
 		},
@@ -1331,7 +1350,7 @@ func TestBuildImage(t *testing.T) {
 			imageBuilderCfg := tt.config
 			c := NewModelServiceImageBuilder(kubeClient, imageBuilderCfg)
 
-			imageRef, err := c.BuildImage(context.Background(), tt.args.project, tt.args.model, tt.args.version)
+			imageRef, err := c.BuildImage(context.Background(), tt.args.project, tt.args.model, tt.args.version, "id-dev")
 			var actions []ktesting.Action
 			assert.NoError(t, err)
 			assert.Equal(t, tt.wantImageRef, imageRef)
@@ -1389,7 +1408,7 @@ func TestGetContainers(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: fmt.Sprintf("%s-%s-%s-1", project.Name, model.Name, modelVersion.ID),
 							Labels: map[string]string{
-								"job-name": fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+								"image-build": fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 						},
 						Spec: v1.PodSpec{
@@ -1404,7 +1423,7 @@ func TestGetContainers(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Name: fmt.Sprintf("%s-%s-%s-2", project.Name, model.Name, modelVersion.ID),
 							Labels: map[string]string{
-								"job-name": fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
+								"image-build": fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID),
 							},
 						},
 						Spec: v1.PodSpec{
@@ -1606,3 +1625,415 @@ func Test_kanikoBuilder_imageExists_noretry(t *testing.T) {
 
 	assert.Equal(t, 1, retryCounter)
 }
+
+type fakeArtifactHasher struct {
+	hash string
+}
+
+func (h *fakeArtifactHasher) Hash(ctx context.Context, artifactURI string) (string, error) {
+	return h.hash, nil
+}
+
+// newFakeRegistry serves the tags of the image, the images of the tags have the same manifest and can be tagged again
+func newFakeRegistry(t *testing.T, imageName string, tags *[]string) *httptest.Server {
+	tagsPath := fmt.Sprintf("/v2/%s/tags/list", imageName)
+	manifestsPath := fmt.Sprintf("/v2/%s/manifests/", imageName)
+	manifest := `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","config":{},"layers":[]}`
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == tagsPath:
+			_, err := w.Write([]byte(fmt.Sprintf(`{"tags":["%s"]}`, strings.Join(*tags, `","`))))
+			assert.NoError(t, err)
+		case strings.HasPrefix(r.URL.Path, manifestsPath) && r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			_, err := w.Write([]byte(manifest))
+			assert.NoError(t, err)
+		case strings.HasPrefix(r.URL.Path, manifestsPath) && r.Method == http.MethodPut:
+			*tags = append(*tags, strings.TrimPrefix(r.URL.Path, manifestsPath))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Fatalf("Unexpected path: %v", r.URL.Path)
+		}
+	}))
+}
+
+func TestStartBuild(t *testing.T) {
+	hasher := &fakeArtifactHasher{hash: "abc"}
+	baseImage := config.BaseImages[modelVersion.PythonVersion]
+	artifactHash := hashStrings("abc", baseImage.ImageName, baseImage.BuildContextURI, baseImage.DockerfilePath, config.ContextSubPath)
+
+	tests := []struct {
+		name         string
+		tags         []string
+		environment  string
+		want         models.ImageBuildStatus
+		wantReused   bool
+		wantImageTag string
+		wantTags     []string
+		wantHash     string
+		wantArgs     []string
+		wantJob      bool
+	}{
+		{
+			name:         "version image already exists",
+			tags:         []string{modelVersion.ID.String()},
+			want:         models.ImageBuildStatusSucceeded,
+			wantImageTag: modelVersion.ID.String(),
+		},
+		{
+			name:         "reuse image built from the same artifact tagging it with the version",
+			tags:         []string{"2", artifactTag(artifactHash)},
+			want:         models.ImageBuildStatusSucceeded,
+			wantReused:   true,
+			wantImageTag: modelVersion.ID.String(),
+			wantTags:     []string{"2", artifactTag(artifactHash), modelVersion.ID.String()},
+			wantHash:     artifactHash,
+		},
+		{
+			name:         "start kaniko job tagging the image with the artifact hash",
+			tags:         []string{},
+			environment:  "id-cached",
+			want:         models.ImageBuildStatusBuilding,
+			wantImageTag: modelVersion.ID.String(),
+			wantHash:     artifactHash,
+			wantArgs:     []string{"--destination=%s:" + artifactTag(artifactHash), "--cache=true", "--cache-repo=ghcr.io/cache", "--cache-ttl=24h0m0s"},
+			wantJob:      true,
+		},
+		{
+			name:         "start kaniko job without layer caching",
+			tags:         []string{},
+			environment:  "id-uncached",
+			want:         models.ImageBuildStatusBuilding,
+			wantImageTag: modelVersion.ID.String(),
+			wantHash:     artifactHash,
+			wantArgs:     []string{"--destination=%s:" + artifactTag(artifactHash)},
+			wantJob:      true,
+		},
+		{
+			name:         "start kaniko job with the default layer caching of environment without build config",
+			tags:         []string{},
+			environment:  "id-dev",
+			want:         models.ImageBuildStatusBuilding,
+			wantImageTag: modelVersion.ID.String(),
+			wantHash:     artifactHash,
+			wantArgs:     []string{"--destination=%s:" + artifactTag(artifactHash), "--cache=true"},
+			wantJob:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageName := fmt.Sprintf("%s-%s", project.Name, model.Name)
+			tags := append([]string{}, tt.tags...)
+			server := newFakeRegistry(t, imageName, &tags)
+			defer server.Close()
+			u, err := url.Parse(server.URL)
+			assert.NoError(t, err)
+
+			buildConfig := config
+			buildConfig.DockerRegistry = u.Host
+			buildConfig.EnvironmentConfigs = map[string]EnvironmentConfig{
+				"id-cached":   {KanikoCacheRepo: "ghcr.io/cache", KanikoCacheTTL: 24 * time.Hour},
+				"id-uncached": {KanikoCacheDisabled: true},
+			}
+
+			kubeClient := fake.NewSimpleClientset()
+			c := &imageBuilder{
+				kubeClient:     kubeClient,
+				config:         buildConfig,
+				nameGenerator:  &modelServiceNameGenerator{dockerRegistry: u.Host},
+				artifactHasher: hasher,
+			}
+
+			build, err := c.StartBuild(context.Background(), project, model, modelVersion, tt.environment)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, build.Status)
+			assert.Equal(t, tt.wantReused, build.Reused)
+			assert.Equal(t, fmt.Sprintf("%s/%s:%s", u.Host, imageName, tt.wantImageTag), build.ImageRef)
+			assert.Equal(t, tt.wantHash, build.ArtifactHash)
+			if tt.wantTags != nil {
+				assert.Equal(t, tt.wantTags, tags)
+			}
+
+			jobs, err := kubeClient.BatchV1().Jobs(testBuildNamespace).List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			if !tt.wantJob {
+				assert.Empty(t, jobs.Items)
+				return
+			}
+
+			assert.Len(t, jobs.Items, 1)
+			// the environments build the version with their own kaniko job
+			jobName := fmt.Sprintf("%s-%s-%s-%s", project.Name, model.Name, modelVersion.ID, tt.environment)
+			assert.Equal(t, jobName, jobs.Items[0].Name)
+			assert.Equal(t, jobName, build.JobName)
+			args := jobs.Items[0].Spec.Template.Spec.Containers[0].Args
+			for _, arg := range tt.wantArgs {
+				if strings.Contains(arg, "%s") {
+					arg = fmt.Sprintf(arg, fmt.Sprintf("%s/%s", u.Host, imageName))
+				}
+				assert.Contains(t, args, arg)
+			}
+			// the cache args are exactly the ones of the environment
+			assert.Equal(t, cacheArgs(tt.wantArgs), cacheArgs(args))
+		})
+	}
+}
+
+func cacheArgs(args []string) []string {
+	var cache []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--cache") {
+			cache = append(cache, arg)
+		}
+	}
+	return cache
+}
+
+func TestGetBuild(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	buildName := fmt.Sprintf("%s-%s-%s", project.Name, model.Name, modelVersion.ID)
+	jobName := buildName + "-id-dev"
+	jobMeta := metav1.ObjectMeta{Name: jobName, Namespace: testBuildNamespace, Labels: map[string]string{labelImageBuild: buildName}}
+
+	tests := []struct {
+		name        string
+		job         *batchv1.Job
+		pod         *v1.Pod
+		want        models.ImageBuildStatus
+		wantMessage string
+		wantJobName string
+	}{
+		{
+			name: "job is running",
+			job: &batchv1.Job{
+				ObjectMeta: jobMeta,
+				Status:     batchv1.JobStatus{Active: 1, StartTime: &startTime},
+			},
+			want:        models.ImageBuildStatusBuilding,
+			wantJobName: jobName,
+		},
+		{
+			name: "job succeeded",
+			job: &batchv1.Job{
+				ObjectMeta: jobMeta,
+				Status:     batchv1.JobStatus{Succeeded: 1, StartTime: &startTime, CompletionTime: &startTime},
+			},
+			want:        models.ImageBuildStatusSucceeded,
+			wantJobName: jobName,
+		},
+		{
+			name: "job failed",
+			job: &batchv1.Job{
+				ObjectMeta: jobMeta,
+				Status: batchv1.JobStatus{
+					Failed:    1,
+					StartTime: &startTime,
+					Conditions: []batchv1.JobCondition{
+						{Type: batchv1.JobFailed, Status: v1.ConditionTrue, Message: "BackoffLimitExceeded"},
+					},
+				},
+			},
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      jobName + "-abcde",
+					Namespace: testBuildNamespace,
+					Labels:    map[string]string{"job-name": jobName},
+				},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{
+						{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Message: "requirements can't be installed"}}},
+					},
+				},
+			},
+			want:        models.ImageBuildStatusFailed,
+			wantMessage: "requirements can't be installed",
+			wantJobName: jobName,
+		},
+		{
+			name:        "image is not built",
+			want:        models.ImageBuildStatusNotBuilt,
+			wantJobName: buildName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageName := fmt.Sprintf("%s-%s", project.Name, model.Name)
+			server := newFakeRegistry(t, imageName, &[]string{})
+			defer server.Close()
+			u, err := url.Parse(server.URL)
+			assert.NoError(t, err)
+
+			objects := []runtime.Object{}
+			if tt.job != nil {
+				objects = append(objects, tt.job)
+			}
+			if tt.pod != nil {
+				objects = append(objects, tt.pod)
+			}
+
+			buildConfig := config
+			buildConfig.DockerRegistry = u.Host
+			c := &imageBuilder{
+				kubeClient:    fake.NewSimpleClientset(objects...),
+				config:        buildConfig,
+				nameGenerator: &modelServiceNameGenerator{dockerRegistry: u.Host},
+			}
+
+			build, err := c.GetBuild(context.Background(), project, model, modelVersion)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, build.Status)
+			assert.Equal(t, tt.wantMessage, build.Message)
+			assert.Equal(t, tt.wantJobName, build.JobName)
+			assert.Equal(t, testBuildNamespace, build.Namespace)
+			assert.Equal(t, buildConfig.ClusterName, build.Cluster)
+			if tt.job != nil {
+				assert.Equal(t, startTime.Time, *build.StartedAt)
+			}
+		})
+	}
+}
+
+func TestGetBuildInputs(t *testing.T) {
+	gdalImage := cfg.BaseImageConfig{
+		ImageName:       "ghcr.io/caraml-dev/merlin/pyfunc-gdal:1.0.0",
+		BuildContextURI: testBuildContextURL,
+		DockerfilePath:  "./Dockerfile",
+	}
+	stagingGdalImage := cfg.BaseImageConfig{
+		ImageName:       "ghcr.io/caraml-dev/merlin/pyfunc-gdal:1.1.0",
+		BuildContextURI: testBuildContextURL,
+		DockerfilePath:  "./Dockerfile",
+	}
+
+	tests := []struct {
+		name        string
+		environment string
+		buildConfig *models.VersionBuildConfig
+		want        *models.VersionBuildInputs
+		wantErr     string
+	}{
+		{
+			name: "base image of the python version",
+			want: &models.VersionBuildInputs{
+				BaseImage:       "gojek/base-image:4",
+				DockerfilePath:  "./Dockerfile",
+				BuildContextURI: testBuildContextURL,
+			},
+		},
+		{
+			name: "allowed base image with apt packages and build args",
+			buildConfig: &models.VersionBuildConfig{
+				BaseImage:   "gdal",
+				AptPackages: []string{"libgomp1", "gdal-bin=3.2.2"},
+				BuildArgs:   map[string]string{"PIP_INDEX_URL": "https://pypi.example.com/simple"},
+			},
+			want: &models.VersionBuildInputs{
+				BaseImage:       gdalImage.ImageName,
+				DockerfilePath:  gdalImage.DockerfilePath,
+				BuildContextURI: gdalImage.BuildContextURI,
+				AptPackages:     []string{"libgomp1", "gdal-bin=3.2.2"},
+				BuildArgs:       map[string]string{"PIP_INDEX_URL": "https://pypi.example.com/simple"},
+			},
+		},
+		{
+			name:        "base image allowed by the environment",
+			environment: "id-staging",
+			buildConfig: &models.VersionBuildConfig{BaseImage: "gdal"},
+			want: &models.VersionBuildInputs{
+				BaseImage:       stagingGdalImage.ImageName,
+				DockerfilePath:  stagingGdalImage.DockerfilePath,
+				BuildContextURI: stagingGdalImage.BuildContextURI,
+			},
+		},
+		{
+			name:        "base image allowed by another environment",
+			environment: "id-dev",
+			buildConfig: &models.VersionBuildConfig{BaseImage: "cuda"},
+			wantErr:     "base image cuda is not allowed in environment id-dev, allowed base images: [gdal]",
+		},
+		{
+			name:        "base image is not allowed",
+			buildConfig: &models.VersionBuildConfig{BaseImage: "tpu"},
+			wantErr:     "base image tpu is not allowed, allowed base images: [cuda, gdal]",
+		},
+		{
+			name:        "invalid apt package",
+			buildConfig: &models.VersionBuildConfig{AptPackages: []string{"libgomp1; rm -rf /"}},
+			wantErr:     `invalid apt package "libgomp1; rm -rf /"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildConfig := config
+			buildConfig.EnvironmentConfigs = map[string]EnvironmentConfig{
+				"id-dev":     {AllowedBaseImages: cfg.BaseImageConfigs{"gdal": gdalImage}},
+				"id-staging": {AllowedBaseImages: cfg.BaseImageConfigs{"gdal": stagingGdalImage, "cuda": gdalImage}},
+			}
+			c := &imageBuilder{config: buildConfig}
+
+			version := *modelVersion
+			version.BuildConfig = tt.buildConfig
+
+			got, err := c.getBuildInputs(&version, tt.environment)
+			if tt.environment == "" {
+				gotInputs, gotErr := c.GetBuildInputs(&version)
+				assert.Equal(t, got, gotInputs)
+				assert.Equal(t, err, gotErr)
+			}
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateKanikoJobSpec_BuildConfig(t *testing.T) {
+	buildConfig := config
+	buildConfig.EnvironmentConfigs = map[string]EnvironmentConfig{
+		"id-dev": {
+			AllowedBaseImages: cfg.BaseImageConfigs{
+				"gdal": cfg.BaseImageConfig{
+					ImageName:       "ghcr.io/caraml-dev/merlin/pyfunc-gdal:1.0.0",
+					BuildContextURI: "gs://bucket/gdal-build.tar.gz",
+					DockerfilePath:  "./gdal.Dockerfile",
+				},
+			},
+		},
+	}
+	c := &imageBuilder{
+		config:        buildConfig,
+		nameGenerator: &modelServiceNameGenerator{dockerRegistry: testDockerRegistry},
+	}
+
+	version := *modelVersion
+	version.BuildConfig = &models.VersionBuildConfig{
+		BaseImage:   "gdal",
+		AptPackages: []string{"libgomp1", "gdal-bin"},
+		BuildArgs: map[string]string{
+			"PIP_INDEX_URL": "https://pypi.example.com/simple",
+			"GDAL_VERSION":  "3.2.2",
+		},
+	}
+
+	job, err := c.createKanikoJobSpec(project, model, &version, "", "id-dev")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"--dockerfile=./gdal.Dockerfile",
+		"--context=gs://bucket/gdal-build.tar.gz",
+		fmt.Sprintf("--build-arg=MODEL_URL=%s/model", testArtifactURI),
+		"--build-arg=BASE_IMAGE=ghcr.io/caraml-dev/merlin/pyfunc-gdal:1.0.0",
+		"--build-arg=APT_PACKAGES=libgomp1 gdal-bin",
+		"--build-arg=GDAL_VERSION=3.2.2",
+		"--build-arg=PIP_INDEX_URL=https://pypi.example.com/simple",
+		fmt.Sprintf("--destination=%s/%s-%s:%s", testDockerRegistry, project.Name, model.Name, version.ID),
+		"--cache=true",
+		"--single-snapshot",
+		"--context-sub-path=python/pyfunc-server",
+		fmt.Sprintf("--build-arg=%s=%s", gacEnvKey, saFilePath),
+	}, job.Spec.Template.Spec.Containers[0].Args)
+}
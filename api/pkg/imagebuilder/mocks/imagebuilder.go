@@ -17,17 +17,40 @@ type ImageBuilder struct {
 	mock.Mock
 }
 
-// BuildImage provides a mock function with given fields: ctx, project, model, version
-func (_m *ImageBuilder) BuildImage(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) (string, error) {
-	ret := _m.Called(ctx, project, model, version)
+// BuildImage provides a mock function with given fields: ctx, project, model, version, environmentName
+func (_m *ImageBuilder) BuildImage(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, environmentName string) (string, error) {
+	ret := _m.Called(ctx, project, model, version, environmentName)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, mlp.Project, *models.Model, *models.Version) string); ok {
-		r0 = rf(ctx, project, model, version)
+	if rf, ok := ret.Get(0).(func(context.Context, mlp.Project, *models.Model, *models.Version, string) string); ok {
+		r0 = rf(ctx, project, model, version, environmentName)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, mlp.Project, *models.Model, *models.Version, string) error); ok {
+		r1 = rf(ctx, project, model, version, environmentName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBuild provides a mock function with given fields: ctx, project, model, version
+func (_m *ImageBuilder) GetBuild(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) (*models.ImageBuild, error) {
+	ret := _m.Called(ctx, project, model, version)
+
+	var r0 *models.ImageBuild
+	if rf, ok := ret.Get(0).(func(context.Context, mlp.Project, *models.Model, *models.Version) *models.ImageBuild); ok {
+		r0 = rf(ctx, project, model, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImageBuild)
+		}
+	}
+
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, mlp.Project, *models.Model, *models.Version) error); ok {
 		r1 = rf(ctx, project, model, version)
@@ -38,6 +61,29 @@ func (_m *ImageBuilder) BuildImage(ctx context.Context, project mlp.Project, mod
 	return r0, r1
 }
 
+// GetBuildInputs provides a mock function with given fields: version
+func (_m *ImageBuilder) GetBuildInputs(version *models.Version) (*models.VersionBuildInputs, error) {
+	ret := _m.Called(version)
+
+	var r0 *models.VersionBuildInputs
+	if rf, ok := ret.Get(0).(func(*models.Version) *models.VersionBuildInputs); ok {
+		r0 = rf(version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.VersionBuildInputs)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*models.Version) error); ok {
+		r1 = rf(version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetContainers provides a mock function with given fields: ctx, project, model, version
 func (_m *ImageBuilder) GetContainers(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version) ([]*models.Container, error) {
 	ret := _m.Called(ctx, project, model, version)
@@ -61,20 +107,43 @@ func (_m *ImageBuilder) GetContainers(ctx context.Context, project mlp.Project,
 	return r0, r1
 }
 
-// GetMainAppPath provides a mock function with given fields: version
-func (_m *ImageBuilder) GetMainAppPath(version *models.Version) (string, error) {
-	ret := _m.Called(version)
+// GetMainAppPath provides a mock function with given fields: version, environmentName
+func (_m *ImageBuilder) GetMainAppPath(version *models.Version, environmentName string) (string, error) {
+	ret := _m.Called(version, environmentName)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*models.Version) string); ok {
-		r0 = rf(version)
+	if rf, ok := ret.Get(0).(func(*models.Version, string) string); ok {
+		r0 = rf(version, environmentName)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*models.Version) error); ok {
-		r1 = rf(version)
+	if rf, ok := ret.Get(1).(func(*models.Version, string) error); ok {
+		r1 = rf(version, environmentName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartBuild provides a mock function with given fields: ctx, project, model, version, environmentName
+func (_m *ImageBuilder) StartBuild(ctx context.Context, project mlp.Project, model *models.Model, version *models.Version, environmentName string) (*models.ImageBuild, error) {
+	ret := _m.Called(ctx, project, model, version, environmentName)
+
+	var r0 *models.ImageBuild
+	if rf, ok := ret.Get(0).(func(context.Context, mlp.Project, *models.Model, *models.Version, string) *models.ImageBuild); ok {
+		r0 = rf(ctx, project, model, version, environmentName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImageBuild)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, mlp.Project, *models.Model, *models.Version, string) error); ok {
+		r1 = rf(ctx, project, model, version, environmentName)
 	} else {
 		r1 = ret.Error(1)
 	}
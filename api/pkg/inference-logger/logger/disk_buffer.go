@@ -0,0 +1,490 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBufferMaxSize is the size of the buffer directory from which the new log entries are dropped
+	DefaultBufferMaxSize = 512 * 1024 * 1024
+	// DefaultBufferMaxSegmentSize is the size from which a segment file is sealed and can be delivered
+	DefaultBufferMaxSegmentSize = 8 * 1024 * 1024
+	// DefaultBufferMaxAttempts is the number of delivery attempts of a batch before its log entries are dead-lettered
+	DefaultBufferMaxAttempts    = 10
+	DefaultBufferInitialBackoff = time.Second
+	DefaultBufferMaxBackoff     = time.Minute
+
+	segmentFileExtension = ".wal"
+)
+
+// ErrBufferFull is returned when the disk buffer reached its maximum size and the log entries are dropped
+var ErrBufferFull = errors.New("disk buffer is full, request logs are dropped")
+
+type DiskBufferConfig struct {
+	// Dir is where the segment files are written, it's expected to be an emptyDir volume so that they survive container restarts
+	Dir string
+	// MaxSize is the size of the segment files and of the other files of Dir, e.g. the default dead-letter directory
+	MaxSize        int64
+	MaxSegmentSize int64
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DiskBufferedSink is a write-ahead buffer between the workers and a log sink. The log entries are appended to
+// segment files which are delivered to the sink in order, with retries and exponential backoff. The log entries
+// which still fail after the maximum number of attempts are sent to the dead-letter sink.
+type DiskBufferedSink struct {
+	logger         *zap.SugaredLogger
+	sink           LogSink
+	deadLetterSink LogSink
+	config         DiskBufferConfig
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	sealed     []*segment
+	active     *segment
+	activeFile *os.File
+	nextSeq    uint64
+	closed     bool
+	// otherSize is the size of the files of the directory which aren't segment files, e.g. the dead-lettered log entries
+	otherSize int64
+
+	// deliveryMu serializes the deliveries of the background goroutine and of Close
+	deliveryMu sync.Mutex
+	stop       chan struct{}
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+type segment struct {
+	path    string
+	size    int64
+	entries int
+}
+
+// segmentRecord is a line of a segment file, i.e. a batch of log entries
+type segmentRecord struct {
+	LogEntries []*LogEntry `json:"log_entries"`
+}
+
+// NewDiskBufferedSink creates the buffer and starts delivering the segment files left by a previous run
+func NewDiskBufferedSink(logger *zap.SugaredLogger, sink LogSink, deadLetterSink LogSink, config DiskBufferConfig) (FlushableLogSink, error) {
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultBufferMaxSize
+	}
+	if config.MaxSegmentSize <= 0 {
+		config.MaxSegmentSize = DefaultBufferMaxSegmentSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultBufferMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultBufferInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultBufferMaxBackoff
+	}
+
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create buffer directory %s: %w", config.Dir, err)
+	}
+
+	b := &DiskBufferedSink{
+		logger:         logger,
+		sink:           sink,
+		deadLetterSink: deadLetterSink,
+		config:         config,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	if err := b.loadSegments(); err != nil {
+		return nil, err
+	}
+	b.refreshOtherSize()
+
+	go b.deliverSegments()
+	return b, nil
+}
+
+// Sink appends the log entries to the active segment file
+func (b *DiskBufferedSink) Sink(logEntries []*LogEntry) error {
+	data, err := json.Marshal(&segmentRecord{LogEntries: logEntries})
+	if err != nil {
+		droppedEntries.WithLabelValues(dropReasonSerialization).Add(float64(len(logEntries)))
+		return fmt.Errorf("unable to serialize log entries: %w", err)
+	}
+	data = append(data, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.size()+int64(len(data)) > b.config.MaxSize {
+		droppedEntries.WithLabelValues(dropReasonBufferFull).Add(float64(len(logEntries)))
+		return ErrBufferFull
+	}
+
+	if b.activeFile == nil {
+		if err := b.openActiveSegment(); err != nil {
+			return err
+		}
+	}
+	if _, err := b.activeFile.Write(data); err != nil {
+		droppedEntries.WithLabelValues(dropReasonBufferFailed).Add(float64(len(logEntries)))
+		return fmt.Errorf("unable to write to segment file %s: %w", b.active.path, err)
+	}
+	b.active.size += int64(len(data))
+	b.active.entries += len(logEntries)
+	bufferedBytes.Add(float64(len(data)))
+	bufferedEntries.Add(float64(len(logEntries)))
+
+	if b.active.size >= b.config.MaxSegmentSize {
+		b.sealActiveSegment()
+	}
+	b.cond.Signal()
+	return nil
+}
+
+// Flush flushes the sink and the dead-letter sink if they buffer log entries
+func (b *DiskBufferedSink) Flush() error {
+	defer b.refreshOtherSize()
+
+	for _, sink := range []LogSink{b.sink, b.deadLetterSink} {
+		if flushable, ok := sink.(FlushableLogSink); ok {
+			if err := flushable.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close stops the background delivery and tries once to deliver the buffered log entries.
+// The segment files which can't be delivered are kept on disk and delivered on the next start.
+// Close can be called several times, e.g. by every worker, each call delivering the log entries sunk since.
+func (b *DiskBufferedSink) Close() error {
+	b.stopOnce.Do(func() {
+		b.mu.Lock()
+		b.closed = true
+		b.cond.Broadcast()
+		b.mu.Unlock()
+
+		close(b.stop)
+		<-b.done
+	})
+
+	b.deliveryMu.Lock()
+	b.mu.Lock()
+	b.sealActiveSegment()
+	segments := append([]*segment{}, b.sealed...)
+	b.mu.Unlock()
+
+	var err error
+	for _, seg := range segments {
+		if !b.deliverSegment(seg, false) {
+			err = fmt.Errorf("unable to deliver segment file %s, it's kept on disk", seg.path)
+			break
+		}
+	}
+	b.deliveryMu.Unlock()
+
+	for _, sink := range []LogSink{b.sink, b.deadLetterSink} {
+		if flushable, ok := sink.(FlushableLogSink); ok {
+			if closeErr := flushable.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}
+	b.refreshOtherSize()
+	return err
+}
+
+func (b *DiskBufferedSink) deliverSegments() {
+	defer close(b.done)
+
+	for {
+		seg, ok := b.waitSegment()
+		if !ok {
+			return
+		}
+
+		b.deliveryMu.Lock()
+		delivered := b.deliverSegment(seg, true)
+		b.deliveryMu.Unlock()
+		if !delivered {
+			return
+		}
+	}
+}
+
+// waitSegment blocks until there's a segment to deliver, it returns false once the buffer is closed
+func (b *DiskBufferedSink) waitSegment() (*segment, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if b.closed {
+			return nil, false
+		}
+		if len(b.sealed) == 0 {
+			b.sealActiveSegment()
+		}
+		if len(b.sealed) > 0 {
+			return b.sealed[0], true
+		}
+		b.cond.Wait()
+	}
+}
+
+// deliverSegment sends the batches of the segment file to the sink and deletes the file.
+// It returns false if the segment must be kept on disk, i.e. a batch couldn't be delivered without retries or the buffer is closed.
+func (b *DiskBufferedSink) deliverSegment(seg *segment, retry bool) bool {
+	file, err := os.Open(seg.path)
+	if err != nil {
+		b.logger.Errorf("unable to open segment file %s: %v", seg.path, err)
+		b.removeSegment(seg)
+		return true
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			record := &segmentRecord{}
+			if jsonErr := json.Unmarshal(line, record); jsonErr != nil {
+				// a partially written batch of a crashed container
+				b.logger.Errorf("corrupted record in segment file %s: %v", seg.path, jsonErr)
+				droppedEntries.WithLabelValues(dropReasonCorrupted).Inc()
+			} else if !b.deliver(record.LogEntries, retry) {
+				_ = file.Close()
+				return false
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	_ = file.Close()
+	b.removeSegment(seg)
+	return true
+}
+
+// deliver sends the log entries to the sink, it returns false if they couldn't be delivered and must be kept on disk
+func (b *DiskBufferedSink) deliver(logEntries []*LogEntry, retry bool) bool {
+	backoff := b.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := b.sink.Sink(logEntries)
+		if err == nil {
+			return true
+		}
+		if !retry {
+			b.logger.Errorf("error delivering %d buffered log entries: %v", len(logEntries), err)
+			return false
+		}
+		if attempt >= b.config.MaxAttempts {
+			b.logger.Errorf("error delivering %d buffered log entries after %d attempts, sending them to the dead-letter sink: %v", len(logEntries), attempt, err)
+			break
+		}
+
+		b.logger.Warnf("error delivering %d buffered log entries, retrying in %v: %v", len(logEntries), backoff, err)
+		retriedEntries.Add(float64(len(logEntries)))
+		select {
+		case <-time.After(backoff):
+		case <-b.stop:
+			return false
+		}
+		backoff *= 2
+		if backoff > b.config.MaxBackoff {
+			backoff = b.config.MaxBackoff
+		}
+	}
+
+	b.deadLetter(logEntries)
+	return true
+}
+
+// deadLetter sends the log entries one by one to isolate the ones failing, which are then sent to the dead-letter sink
+func (b *DiskBufferedSink) deadLetter(logEntries []*LogEntry) {
+	failed := logEntries
+	if len(logEntries) > 1 {
+		failed = make([]*LogEntry, 0)
+		for _, logEntry := range logEntries {
+			if err := b.sink.Sink([]*LogEntry{logEntry}); err != nil {
+				failed = append(failed, logEntry)
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	if b.deadLetterSink == nil {
+		droppedEntries.WithLabelValues(dropReasonDeliveryFailed).Add(float64(len(failed)))
+		return
+	}
+	if err := b.deadLetterSink.Sink(failed); err != nil {
+		b.logger.Errorf("error sending %d log entries to the dead-letter sink: %v", len(failed), err)
+		droppedEntries.WithLabelValues(dropReasonDeliveryFailed).Add(float64(len(failed)))
+		return
+	}
+	deadLetterEntries.Add(float64(len(failed)))
+	b.refreshOtherSize()
+}
+
+// loadSegments registers the segment files left by a previous run as sealed segments
+func (b *DiskBufferedSink) loadSegments() error {
+	paths, err := filepath.Glob(filepath.Join(b.config.Dir, "*"+segmentFileExtension))
+	if err != nil {
+		return err
+	}
+
+	seqs := make([]uint64, 0, len(paths))
+	for _, path := range paths {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), segmentFileExtension), 10, 64)
+		if err != nil {
+			b.logger.Warnf("ignoring unknown file %s in buffer directory", path)
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	for _, seq := range seqs {
+		seg, err := b.loadSegment(b.segmentPath(seq))
+		if err != nil {
+			return err
+		}
+		b.sealed = append(b.sealed, seg)
+		b.nextSeq = seq + 1
+		bufferedBytes.Add(float64(seg.size))
+		bufferedEntries.Add(float64(seg.entries))
+	}
+	if len(b.sealed) > 0 {
+		b.logger.Infof("found %d buffered segment files to deliver", len(b.sealed))
+	}
+	return nil
+}
+
+func (b *DiskBufferedSink) loadSegment(path string) (*segment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open segment file %s: %w", path, err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	seg := &segment{path: path}
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		seg.size += int64(len(line))
+		record := &segmentRecord{}
+		if len(line) > 0 && json.Unmarshal(line, record) == nil {
+			seg.entries += len(record.LogEntries)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return seg, nil
+}
+
+func (b *DiskBufferedSink) openActiveSegment() error {
+	path := b.segmentPath(b.nextSeq)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to create segment file %s: %w", path, err)
+	}
+	b.nextSeq++
+	b.activeFile = file
+	b.active = &segment{path: path}
+	return nil
+}
+
+// sealActiveSegment closes the active segment file so that it can be delivered, it must be called with mu held
+func (b *DiskBufferedSink) sealActiveSegment() {
+	if b.activeFile == nil || b.active.size == 0 {
+		return
+	}
+	if err := b.activeFile.Close(); err != nil {
+		b.logger.Errorf("error closing segment file %s: %v", b.active.path, err)
+	}
+	b.sealed = append(b.sealed, b.active)
+	b.activeFile = nil
+	b.active = nil
+}
+
+func (b *DiskBufferedSink) removeSegment(seg *segment) {
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		b.logger.Errorf("error removing segment file %s: %v", seg.path, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, sealed := range b.sealed {
+		if sealed == seg {
+			b.sealed = append(b.sealed[:i], b.sealed[i+1:]...)
+			break
+		}
+	}
+	bufferedBytes.Sub(float64(seg.size))
+	bufferedEntries.Sub(float64(seg.entries))
+}
+
+// refreshOtherSize computes the size of the files of the directory which aren't segment files,
+// they're written by the dead-letter sink when it writes to the buffer directory
+func (b *DiskBufferedSink) refreshOtherSize() {
+	size := int64(0)
+	err := filepath.Walk(b.config.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the files can be removed while walking, e.g. the temporary files of the dead-letter sink
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || filepath.Dir(path) == filepath.Clean(b.config.Dir) && filepath.Ext(path) == segmentFileExtension {
+			return nil
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		b.logger.Warnf("unable to compute the size of buffer directory %s: %v", b.config.Dir, err)
+		return
+	}
+
+	b.mu.Lock()
+	b.otherSize = size
+	b.mu.Unlock()
+}
+
+// size returns the size of the segment files and of the other files of the directory, it must be called with mu held
+func (b *DiskBufferedSink) size() int64 {
+	size := b.otherSize
+	for _, seg := range b.sealed {
+		size += seg.size
+	}
+	if b.active != nil {
+		size += b.active.size
+	}
+	return size
+}
+
+func (b *DiskBufferedSink) segmentPath(seq uint64) string {
+	return filepath.Join(b.config.Dir, fmt.Sprintf("%020d%s", seq, segmentFileExtension))
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flakySink struct {
+	mu         sync.Mutex
+	logEntries []*LogEntry
+	failures   int
+	poison     string
+}
+
+func (s *flakySink) Sink(logEntries []*LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures != 0 {
+		if s.failures > 0 {
+			s.failures--
+		}
+		return errors.New("broker is unreachable")
+	}
+	for _, logEntry := range logEntries {
+		if logEntry.RequestId == s.poison {
+			return fmt.Errorf("unable to marshal log entry %s", logEntry.RequestId)
+		}
+	}
+	s.logEntries = append(s.logEntries, logEntries...)
+	return nil
+}
+
+func (s *flakySink) requestIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.logEntries))
+	for _, logEntry := range s.logEntries {
+		ids = append(ids, logEntry.RequestId)
+	}
+	return ids
+}
+
+func newBufferLogEntries(ids ...string) []*LogEntry {
+	logEntries := make([]*LogEntry, 0, len(ids))
+	for _, id := range ids {
+		logEntries = append(logEntries, newFileLogEntry(id, time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC)))
+	}
+	return logEntries
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentFileExtension))
+	require.NoError(t, err)
+	return files
+}
+
+func testBufferConfig(dir string) DiskBufferConfig {
+	return DiskBufferConfig{
+		Dir:            dir,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestDiskBufferedSink_DeliversInOrder(t *testing.T) {
+	dir := t.TempDir()
+	sink := &flakySink{}
+	buffer, err := NewDiskBufferedSink(logger, sink, nil, DiskBufferConfig{Dir: dir, MaxSegmentSize: 512})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, buffer.Sink(newBufferLogEntries(fmt.Sprint(i))))
+	}
+
+	assert.Eventually(t, func() bool { return len(sink.requestIDs()) == 10 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, sink.requestIDs())
+	assert.Eventually(t, func() bool { return len(segmentFiles(t, dir)) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, buffer.Close())
+}
+
+func TestDiskBufferedSink_RetriesWithBackoff(t *testing.T) {
+	retried := testutil.ToFloat64(retriedEntries)
+
+	sink := &flakySink{failures: 2}
+	buffer, err := NewDiskBufferedSink(logger, sink, nil, testBufferConfig(t.TempDir()))
+	require.NoError(t, err)
+
+	require.NoError(t, buffer.Sink(newBufferLogEntries("1", "2")))
+	assert.Eventually(t, func() bool { return len(sink.requestIDs()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, retried+4, testutil.ToFloat64(retriedEntries))
+	require.NoError(t, buffer.Close())
+}
+
+func TestDiskBufferedSink_DeadLetter(t *testing.T) {
+	deadLettered := testutil.ToFloat64(deadLetterEntries)
+
+	sink := &flakySink{poison: "2"}
+	deadLetterSink := &flakySink{}
+	buffer, err := NewDiskBufferedSink(logger, sink, deadLetterSink, testBufferConfig(t.TempDir()))
+	require.NoError(t, err)
+
+	require.NoError(t, buffer.Sink(newBufferLogEntries("1", "2", "3")))
+	assert.Eventually(t, func() bool { return len(deadLetterSink.requestIDs()) == 1 }, time.Second, time.Millisecond)
+
+	// the other log entries of the batch are still delivered
+	assert.Equal(t, []string{"1", "3"}, sink.requestIDs())
+	assert.Equal(t, []string{"2"}, deadLetterSink.requestIDs())
+	assert.Equal(t, deadLettered+1, testutil.ToFloat64(deadLetterEntries))
+	require.NoError(t, buffer.Close())
+}
+
+func TestDiskBufferedSink_KeepsUndeliveredSegmentsOnClose(t *testing.T) {
+	dir := t.TempDir()
+	unreachable := &flakySink{failures: -1}
+	buffer, err := NewDiskBufferedSink(logger, unreachable, nil, DiskBufferConfig{Dir: dir, InitialBackoff: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, buffer.Sink(newBufferLogEntries("1", "2")))
+	require.NoError(t, buffer.Sink(newBufferLogEntries("3")))
+	assert.Error(t, buffer.Close())
+	assert.NotEmpty(t, segmentFiles(t, dir))
+
+	// a partially written record of a crashed container is skipped
+	files := segmentFiles(t, dir)
+	f, err := os.OpenFile(files[len(files)-1], os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"log_entries":[{"RequestId":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// the segment files are delivered on the next start
+	sink := &flakySink{}
+	buffer, err = NewDiskBufferedSink(logger, sink, nil, DiskBufferConfig{Dir: dir})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(sink.requestIDs()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"1", "2", "3"}, sink.requestIDs())
+
+	require.NoError(t, buffer.Sink(newBufferLogEntries("4")))
+	require.NoError(t, buffer.Close())
+	assert.Equal(t, []string{"1", "2", "3", "4"}, sink.requestIDs())
+	assert.Empty(t, segmentFiles(t, dir))
+
+	// the sink can still be used and closed by the other workers
+	require.NoError(t, buffer.Sink(newBufferLogEntries("5")))
+	require.NoError(t, buffer.Close())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, sink.requestIDs())
+}
+
+func TestDiskBufferedSink_BufferFull(t *testing.T) {
+	dropped := testutil.ToFloat64(droppedEntries.WithLabelValues(dropReasonBufferFull))
+
+	unreachable := &flakySink{failures: -1}
+	buffer, err := NewDiskBufferedSink(logger, unreachable, nil, DiskBufferConfig{Dir: t.TempDir(), MaxSize: 300, InitialBackoff: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, buffer.Sink(newBufferLogEntries("1")))
+	assert.Equal(t, ErrBufferFull, buffer.Sink(newBufferLogEntries("2", "3")))
+	assert.Equal(t, dropped+2, testutil.ToFloat64(droppedEntries.WithLabelValues(dropReasonBufferFull)))
+	assert.Error(t, buffer.Close())
+}
+
+func TestDiskBufferedSink_BufferFullOfDeadLetters(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dead-letter"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dead-letter", "1.jsonl"), make([]byte, 250), 0o644))
+
+	// the dead-lettered log entries count in the size of the buffer
+	unreachable := &flakySink{failures: -1}
+	buffer, err := NewDiskBufferedSink(logger, unreachable, nil, DiskBufferConfig{Dir: dir, MaxSize: 300, InitialBackoff: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, ErrBufferFull, buffer.Sink(newBufferLogEntries("1")))
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "dead-letter")))
+	require.NoError(t, buffer.Flush())
+	assert.NoError(t, buffer.Sink(newBufferLogEntries("1")))
+	assert.Error(t, buffer.Close())
+}
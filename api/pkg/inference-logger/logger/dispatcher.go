@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 
 	"go.uber.org/zap"
@@ -38,12 +39,23 @@ func (d *Dispatcher) Start() {
 }
 
 func (d *Dispatcher) Submit(logEntry *LogEntry) error {
-	return d.workerQueue.Put(logEntry)
+	err := d.workerQueue.Put(logEntry)
+	if errors.Is(err, ErrFullQueue) {
+		droppedEntries.WithLabelValues(dropReasonQueueFull).Inc()
+	} else if err != nil {
+		droppedEntries.WithLabelValues(dropReasonQueueClosed).Inc()
+	}
+	return err
 }
 
+// Stop closes the queue and waits until the workers have sent the remaining log entries.
 func (d *Dispatcher) Stop() {
 	err := d.workerQueue.Close()
 	if err != nil {
 		fmt.Println(err)
 	}
+
+	for _, worker := range d.workers {
+		worker.Wait()
+	}
 }
@@ -0,0 +1,363 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type FileFormat = string
+
+const (
+	FileFormatJSONL   FileFormat = "jsonl"
+	FileFormatParquet FileFormat = "parquet"
+
+	// DefaultMaxFileSize is the size of the buffered log entries, before compression, from which a file is written
+	DefaultMaxFileSize = 64 * 1024 * 1024
+	// DefaultMaxFileAge is the duration from which buffered log entries are written, even if the file is small
+	DefaultMaxFileAge = 5 * time.Minute
+
+	// partitionLayout partitions the files by hour of the event timestamp
+	partitionLayout = "dt=2006-01-02/hour=15"
+)
+
+const fileLogSchema = `message inference_log {
+	required binary request_id (STRING);
+	required int64 event_timestamp (TIMESTAMP(MILLIS, true));
+	required binary project_name (STRING);
+	required binary model_name (STRING);
+	required binary model_version (STRING);
+	optional binary request_headers (STRING);
+	optional binary request_body (STRING);
+	optional int32 response_status_code;
+	optional binary response_body (STRING);
+}`
+
+type FileSinkConfig struct {
+	Format FileFormat
+	// A file is written once its log entries exceed MaxFileSize bytes or have been buffered for MaxFileAge
+	MaxFileSize int64
+	MaxFileAge  time.Duration
+	// The log entries of the files which can't be written are kept up to MaxBufferSize bytes, 4 files by default,
+	// the oldest log entries are dropped beyond
+	MaxBufferSize int64
+}
+
+// FileSink writes the log entries as compressed JSONL or Parquet files,
+// partitioned by model, version and hour: <project>/<model>/<version>/dt=<date>/hour=<hour>/<file>
+//
+// The log entries are buffered until the file is written. A file which can't be written is kept in the buffer
+// and retried on the next flush, so that log entries are delivered at least once unless the buffer exceeds its
+// maximum size.
+type FileSink struct {
+	logger *zap.SugaredLogger
+	writer ObjectWriter
+	config FileSinkConfig
+
+	projectName  string
+	modelName    string
+	modelVersion string
+
+	mu      sync.Mutex
+	buffers map[string]*fileBuffer
+	now     func() time.Time
+}
+
+type fileBuffer struct {
+	partition string
+	records   []*fileLogRecord
+	size      int64
+	createdAt time.Time
+}
+
+type fileLogRecord struct {
+	RequestID          string            `json:"request_id"`
+	EventTimestamp     time.Time         `json:"event_timestamp"`
+	ProjectName        string            `json:"project_name"`
+	ModelName          string            `json:"model_name"`
+	ModelVersion       string            `json:"model_version"`
+	RequestHeaders     map[string]string `json:"request_headers,omitempty"`
+	RequestBody        string            `json:"request_body,omitempty"`
+	ResponseStatusCode int               `json:"response_status_code,omitempty"`
+	ResponseBody       string            `json:"response_body,omitempty"`
+}
+
+func NewFileSink(
+	logger *zap.SugaredLogger,
+	writer ObjectWriter,
+	config FileSinkConfig,
+	projectName string,
+	modelName string,
+	modelVersion string,
+) (FlushableLogSink, error) {
+	switch config.Format {
+	case "":
+		config.Format = FileFormatJSONL
+	case FileFormatJSONL, FileFormatParquet:
+	default:
+		return nil, fmt.Errorf("unsupported file format %s", config.Format)
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = DefaultMaxFileSize
+	}
+	if config.MaxFileAge <= 0 {
+		config.MaxFileAge = DefaultMaxFileAge
+	}
+	if config.MaxBufferSize <= 0 {
+		config.MaxBufferSize = 4 * config.MaxFileSize
+	}
+
+	return &FileSink{
+		logger:       logger,
+		writer:       writer,
+		config:       config,
+		projectName:  projectName,
+		modelName:    modelName,
+		modelVersion: modelVersion,
+		buffers:      make(map[string]*fileBuffer),
+		now:          time.Now,
+	}, nil
+}
+
+// Sink buffers the log entries and writes the files exceeding the maximum file size.
+// The log entries are accepted once buffered, the files which can't be written are retried on the next flush
+// instead of returning an error, which would make the caller sink the log entries again.
+func (f *FileSink) Sink(rawLogEntries []*LogEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, logEntry := range rawLogEntries {
+		record := f.newLogRecord(logEntry)
+		partition := record.EventTimestamp.UTC().Format(partitionLayout)
+
+		buffer, ok := f.buffers[partition]
+		if !ok {
+			buffer = &fileBuffer{partition: partition, createdAt: f.now()}
+			f.buffers[partition] = buffer
+		}
+		buffer.records = append(buffer.records, record)
+		buffer.size += record.size()
+	}
+
+	_ = f.flush(func(buffer *fileBuffer) bool {
+		return buffer.size >= f.config.MaxFileSize
+	})
+	return nil
+}
+
+// Flush writes the files whose log entries have been buffered for longer than the maximum file age
+func (f *FileSink) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	return f.flush(func(buffer *fileBuffer) bool {
+		return now.Sub(buffer.createdAt) >= f.config.MaxFileAge
+	})
+}
+
+// Close writes all the buffered log entries
+func (f *FileSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.flush(func(buffer *fileBuffer) bool {
+		return true
+	})
+}
+
+func (f *FileSink) flush(shouldWrite func(buffer *fileBuffer) bool) error {
+	partitions := make([]string, 0, len(f.buffers))
+	for partition := range f.buffers {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	var lastErr error
+	for _, partition := range partitions {
+		buffer := f.buffers[partition]
+		if !shouldWrite(buffer) {
+			continue
+		}
+
+		if err := f.write(buffer); err != nil {
+			f.logger.Errorf("unable to write %d log entries of partition %s, retrying on next flush: %v", len(buffer.records), partition, err)
+			lastErr = err
+			continue
+		}
+		delete(f.buffers, partition)
+	}
+
+	f.dropOldest()
+	return lastErr
+}
+
+// dropOldest drops the oldest buffered log entries exceeding the maximum buffer size,
+// which are the ones of the files that can't be written
+func (f *FileSink) dropOldest() {
+	var size int64
+	buffers := make([]*fileBuffer, 0, len(f.buffers))
+	for _, buffer := range f.buffers {
+		size += buffer.size
+		buffers = append(buffers, buffer)
+	}
+	if size <= f.config.MaxBufferSize {
+		return
+	}
+
+	sort.Slice(buffers, func(i, j int) bool {
+		if buffers[i].createdAt.Equal(buffers[j].createdAt) {
+			return buffers[i].partition < buffers[j].partition
+		}
+		return buffers[i].createdAt.Before(buffers[j].createdAt)
+	})
+	for _, buffer := range buffers {
+		dropped := 0
+		for size > f.config.MaxBufferSize && dropped < len(buffer.records) {
+			recordSize := buffer.records[dropped].size()
+			buffer.size -= recordSize
+			size -= recordSize
+			dropped++
+		}
+		if dropped == 0 {
+			break
+		}
+
+		buffer.records = buffer.records[dropped:]
+		if len(buffer.records) == 0 {
+			delete(f.buffers, buffer.partition)
+		}
+		droppedEntries.WithLabelValues(dropReasonBufferFull).Add(float64(dropped))
+		f.logger.Errorf("dropped %d log entries of partition %s exceeding the maximum buffer size of %d bytes", dropped, buffer.partition, f.config.MaxBufferSize)
+	}
+}
+
+func (f *FileSink) write(buffer *fileBuffer) error {
+	var data []byte
+	var err error
+	switch f.config.Format {
+	case FileFormatParquet:
+		data, err = encodeParquet(buffer.records)
+	default:
+		data, err = encodeJSONL(buffer.records)
+	}
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s/%s/%s/%d-%s.%s", f.projectName, f.modelName, f.modelVersion, buffer.partition,
+		f.now().UnixNano(), uuid.New().String()[:8], fileExtension(f.config.Format))
+	return f.writer.Write(context.Background(), key, data)
+}
+
+func (f *FileSink) newLogRecord(logEntry *LogEntry) *fileLogRecord {
+	record := &fileLogRecord{
+		RequestID:    logEntry.RequestId,
+		ProjectName:  f.projectName,
+		ModelName:    f.modelName,
+		ModelVersion: f.modelVersion,
+	}
+	if logEntry.EventTimestamp != nil {
+		record.EventTimestamp = logEntry.EventTimestamp.AsTime()
+	} else {
+		record.EventTimestamp = f.now()
+	}
+	if logEntry.RequestPayload != nil {
+		record.RequestHeaders = logEntry.RequestPayload.Headers
+		record.RequestBody = string(logEntry.RequestPayload.Body)
+	}
+	if logEntry.ResponsePayload != nil {
+		record.ResponseStatusCode = logEntry.ResponsePayload.StatusCode
+		record.ResponseBody = string(logEntry.ResponsePayload.Body)
+	}
+	return record
+}
+
+// size approximates the size of the record before compression
+func (r *fileLogRecord) size() int64 {
+	size := len(r.RequestID) + len(r.ProjectName) + len(r.ModelName) + len(r.ModelVersion) + len(r.RequestBody) + len(r.ResponseBody)
+	for key, value := range r.RequestHeaders {
+		size += len(key) + len(value)
+	}
+	return int64(size)
+}
+
+func fileExtension(format FileFormat) string {
+	if format == FileFormatParquet {
+		return "parquet"
+	}
+	return "jsonl.gz"
+}
+
+func encodeJSONL(records []*fileLogRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, fmt.Errorf("unable to encode log entry %s: %w", record.RequestID, err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("unable to compress log entries: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeParquet(records []*fileLogRecord) ([]byte, error) {
+	schema, err := parquetschema.ParseSchemaDefinition(fileLogSchema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse parquet schema: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fw := goparquet.NewFileWriter(&buf,
+		goparquet.WithSchemaDefinition(schema),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+		goparquet.WithCreator("merlin-inference-logger"),
+	)
+	for _, record := range records {
+		data := map[string]interface{}{
+			"request_id":      []byte(record.RequestID),
+			"event_timestamp": record.EventTimestamp.UnixMilli(),
+			"project_name":    []byte(record.ProjectName),
+			"model_name":      []byte(record.ModelName),
+			"model_version":   []byte(record.ModelVersion),
+		}
+		if record.RequestHeaders != nil {
+			headers, err := json.Marshal(record.RequestHeaders)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encode request headers of log entry %s: %w", record.RequestID, err)
+			}
+			data["request_headers"] = headers
+		}
+		if record.RequestBody != "" {
+			data["request_body"] = []byte(record.RequestBody)
+		}
+		if record.ResponseStatusCode != 0 {
+			data["response_status_code"] = int32(record.ResponseStatusCode)
+		}
+		if record.ResponseBody != "" {
+			data["response_body"] = []byte(record.ResponseBody)
+		}
+
+		if err := fw.AddData(data); err != nil {
+			return nil, fmt.Errorf("unable to encode log entry %s: %w", record.RequestID, err)
+		}
+	}
+	if err := fw.Close(); err != nil {
+		return nil, fmt.Errorf("unable to write parquet file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type memoryObjectWriter struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	err     error
+}
+
+func newMemoryObjectWriter() *memoryObjectWriter {
+	return &memoryObjectWriter{objects: make(map[string][]byte)}
+}
+
+func (w *memoryObjectWriter) Write(_ context.Context, key string, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+	w.objects[key] = data
+	return nil
+}
+
+func (w *memoryObjectWriter) keys() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	keys := make([]string, 0, len(w.objects))
+	for key := range w.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func newFileLogEntry(id string, eventTime time.Time) *LogEntry {
+	return &LogEntry{
+		RequestId:      id,
+		EventTimestamp: timestamppb.New(eventTime),
+		RequestPayload: &RequestPayload{
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    []byte(`{"instances" : [[1,2,3,4]]}`),
+		},
+		ResponsePayload: &ResponsePayload{
+			StatusCode: 200,
+			Body:       []byte(`{"predictions": [2]}`),
+		},
+	}
+}
+
+func readJSONL(t *testing.T, data []byte) []fileLogRecord {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	records := make([]fileLogRecord, 0)
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var record fileLogRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestFileSink_PartitionsByHour(t *testing.T) {
+	writer := newMemoryObjectWriter()
+	sink, err := NewFileSink(logger, writer, FileSinkConfig{}, projectName, modelName, modelVersion)
+	require.NoError(t, err)
+
+	eventTime := time.Date(2023, 5, 1, 13, 59, 0, 0, time.UTC)
+	err = sink.Sink([]*LogEntry{
+		newFileLogEntry("1", eventTime),
+		newFileLogEntry("2", eventTime.Add(time.Minute)),
+		newFileLogEntry("3", eventTime.Add(30*time.Second)),
+	})
+	require.NoError(t, err)
+
+	// the files are only written once they're big or old enough
+	assert.Empty(t, writer.keys())
+
+	require.NoError(t, sink.Close())
+	keys := writer.keys()
+	require.Len(t, keys, 2)
+
+	records := map[string][]fileLogRecord{}
+	for _, key := range keys {
+		assert.True(t, strings.HasSuffix(key, ".jsonl.gz"))
+		partition := key[:strings.LastIndex(key, "/")]
+		records[partition] = readJSONL(t, writer.objects[key])
+	}
+
+	hour13 := records["my-project/my-model/1/dt=2023-05-01/hour=13"]
+	require.Len(t, hour13, 2)
+	assert.Equal(t, "1", hour13[0].RequestID)
+	assert.Equal(t, "3", hour13[1].RequestID)
+	assert.Equal(t, modelName, hour13[0].ModelName)
+	assert.Equal(t, `{"predictions": [2]}`, hour13[0].ResponseBody)
+	assert.Equal(t, 200, hour13[0].ResponseStatusCode)
+
+	hour14 := records["my-project/my-model/1/dt=2023-05-01/hour=14"]
+	require.Len(t, hour14, 1)
+	assert.Equal(t, "2", hour14[0].RequestID)
+}
+
+func TestFileSink_SizeAndTimeBasedFlushing(t *testing.T) {
+	writer := newMemoryObjectWriter()
+	s, err := NewFileSink(logger, writer, FileSinkConfig{MaxFileSize: 150, MaxFileAge: time.Minute}, projectName, modelName, modelVersion)
+	require.NoError(t, err)
+	sink := s.(*FileSink)
+
+	now := time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+
+	// a log entry is ~95 bytes, the file is written on the second one
+	require.NoError(t, sink.Sink([]*LogEntry{newFileLogEntry("1", now)}))
+	assert.Empty(t, writer.keys())
+	require.NoError(t, sink.Sink([]*LogEntry{newFileLogEntry("2", now)}))
+	assert.Len(t, writer.keys(), 1)
+
+	require.NoError(t, sink.Sink([]*LogEntry{newFileLogEntry("3", now)}))
+	require.NoError(t, sink.Flush())
+	assert.Len(t, writer.keys(), 1)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, sink.Flush())
+	assert.Len(t, writer.keys(), 2)
+}
+
+func TestFileSink_RetriesFailedWrites(t *testing.T) {
+	writer := newMemoryObjectWriter()
+	writer.err = errors.New("connection refused")
+	sink, err := NewFileSink(logger, writer, FileSinkConfig{}, projectName, modelName, modelVersion)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Sink([]*LogEntry{newFileLogEntry("1", time.Now())}))
+	assert.EqualError(t, sink.Close(), "connection refused")
+	assert.Empty(t, writer.keys())
+
+	// the log entries are kept until they're written
+	writer.err = nil
+	require.NoError(t, sink.Close())
+	keys := writer.keys()
+	require.Len(t, keys, 1)
+	records := readJSONL(t, writer.objects[keys[0]])
+	require.Len(t, records, 1)
+	assert.Equal(t, "1", records[0].RequestID)
+}
+
+func TestFileSink_BoundsFailedWrites(t *testing.T) {
+	writer := newMemoryObjectWriter()
+	writer.err = errors.New("connection refused")
+	s, err := NewFileSink(logger, writer, FileSinkConfig{MaxFileSize: 150, MaxBufferSize: 300}, projectName, modelName, modelVersion)
+	require.NoError(t, err)
+	sink := s.(*FileSink)
+
+	now := time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+
+	// the log entries are accepted even though their file can't be written, a log entry is ~95 bytes
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, sink.Sink([]*LogEntry{newFileLogEntry(fmt.Sprint(i), now)}))
+	}
+	assert.Empty(t, writer.keys())
+
+	// the oldest log entries exceeding the maximum buffer size are dropped
+	writer.err = nil
+	require.NoError(t, sink.Close())
+	keys := writer.keys()
+	require.Len(t, keys, 1)
+	records := readJSONL(t, writer.objects[keys[0]])
+	require.Len(t, records, 3)
+	assert.Equal(t, "3", records[0].RequestID)
+	assert.Equal(t, "5", records[2].RequestID)
+}
+
+func TestFileSink_Parquet(t *testing.T) {
+	writer := newMemoryObjectWriter()
+	sink, err := NewFileSink(logger, writer, FileSinkConfig{Format: FileFormatParquet}, projectName, modelName, modelVersion)
+	require.NoError(t, err)
+
+	eventTime := time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC)
+	noResponse := newFileLogEntry("2", eventTime)
+	noResponse.ResponsePayload = nil
+	require.NoError(t, sink.Sink([]*LogEntry{newFileLogEntry("1", eventTime), noResponse}))
+	require.NoError(t, sink.Close())
+
+	keys := writer.keys()
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasSuffix(keys[0], ".parquet"))
+
+	fr, err := goparquet.NewFileReader(bytes.NewReader(writer.objects[keys[0]]))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), fr.NumRows())
+
+	row, err := fr.NextRow()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), row["request_id"])
+	assert.Equal(t, eventTime.UnixMilli(), row["event_timestamp"])
+	assert.Equal(t, int32(200), row["response_status_code"])
+	assert.Equal(t, []byte(`{"Content-Type":"application/json"}`), row["request_headers"])
+
+	row, err = fr.NextRow()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), row["request_id"])
+	assert.Nil(t, row["response_body"])
+}
+
+func TestFileSink_UnsupportedFormat(t *testing.T) {
+	_, err := NewFileSink(logger, newMemoryObjectWriter(), FileSinkConfig{Format: "csv"}, projectName, modelName, modelVersion)
+	assert.EqualError(t, err, "unsupported file format csv")
+}
+
+func TestLocalObjectWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewLocalObjectWriter(dir)
+
+	key := "my-project/my-model/1/dt=2023-05-01/hour=13/1-abc.jsonl.gz"
+	require.NoError(t, writer.Write(context.Background(), key, []byte("data")))
+
+	data, err := os.ReadFile(filepath.Join(dir, key))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	_, err = os.Stat(filepath.Join(dir, key+".inprogress"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDispatcherStopWritesBufferedLogEntries(t *testing.T) {
+	writer := newMemoryObjectWriter()
+	sink, err := NewFileSink(logger, writer, FileSinkConfig{}, projectName, modelName, modelVersion)
+	require.NoError(t, err)
+
+	dispatcher := NewDispatcher(2, workQueueSize, &WorkerConfig{MinBatchSize: 1, MaxBatchSize: maxBatchSize, FlushInterval: time.Hour}, logger, sink)
+	dispatcher.Start()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, dispatcher.Submit(newFileLogEntry(fmt.Sprint(i), time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC))))
+	}
+	dispatcher.Stop()
+
+	records := 0
+	for _, key := range writer.keys() {
+		records += len(readJSONL(t, writer.objects[key]))
+	}
+	assert.Equal(t, 10, records)
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"context"
+	"strings"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
+)
+
+// UPIPredictValuesMethod is the only gRPC method whose requests and responses are logged
+const UPIPredictValuesMethod = "/caraml.upi.v1.UniversalPredictionService/PredictValues"
+
+// UPIProxy forwards the UPI requests to the model, along with their metadata
+type UPIProxy struct {
+	upiv1.UnimplementedUniversalPredictionServiceServer
+
+	client upiv1.UniversalPredictionServiceClient
+}
+
+func NewUPIProxy(client upiv1.UniversalPredictionServiceClient) *UPIProxy {
+	return &UPIProxy{
+		client: client,
+	}
+}
+
+func (p *UPIProxy) PredictValues(ctx context.Context, request *upiv1.PredictValuesRequest) (*upiv1.PredictValuesResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	outgoingCtx := metadata.NewOutgoingContext(ctx, md.Copy())
+
+	var header, trailer metadata.MD
+	response, err := p.client.PredictValues(outgoingCtx, request, grpc.Header(&header), grpc.Trailer(&trailer))
+	_ = grpc.SetHeader(ctx, header)
+	_ = grpc.SetTrailer(ctx, trailer)
+	return response, err
+}
+
+// NewUnaryLoggerInterceptor returns the gRPC counterpart of the LoggerHandler, it submits the UPI PredictValues requests and responses to the dispatcher
+func NewUnaryLoggerInterceptor(dispatcher *Dispatcher, logMode LogMode, policy *logpolicy.Policy, logger *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod != UPIPredictValuesMethod {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		md = md.Copy()
+		id := getOrCreateGRPCID(md)
+		logEntry := &LogEntry{
+			RequestId:      id,
+			EventTimestamp: timestamppb.Now(),
+		}
+
+		if logMode == LogModeAll || logMode == LogModeRequestOnly {
+			body, protoBody := marshalProto(req, logger)
+			logEntry.RequestPayload = &RequestPayload{
+				Headers:   formatMetadata(md),
+				Body:      body,
+				ProtoBody: protoBody,
+			}
+		}
+
+		statusCode := codes.Unknown
+		defer func() {
+			if !applyLogPolicy(policy, logEntry, int(statusCode)) {
+				return
+			}
+			if err := dispatcher.Submit(logEntry); err != nil {
+				logger.Errorf("error submitting log entry: %v", err)
+			}
+		}()
+
+		md.Set(MerlinLogIdHeader, id)
+		resp, err := handler(metadata.NewIncomingContext(ctx, md), req)
+		statusCode = status.Code(err)
+
+		if logMode == LogModeAll || logMode == LogModeResponseOnly {
+			logEntry.ResponsePayload = &ResponsePayload{
+				StatusCode: int(statusCode),
+			}
+			if err != nil {
+				logEntry.ResponsePayload.Body = []byte(status.Convert(err).Message())
+			} else {
+				logEntry.ResponsePayload.Body, logEntry.ResponsePayload.ProtoBody = marshalProto(resp, logger)
+			}
+		}
+
+		return resp, err
+	}
+}
+
+func getOrCreateGRPCID(md metadata.MD) string {
+	if ids := md.Get(MerlinLogIdHeader); len(ids) > 0 && ids[0] != "" {
+		return ids[0]
+	}
+	return uuid.New().String()
+}
+
+// marshalProto returns the JSON and protobuf encodings of the message
+func marshalProto(msg interface{}, logger *zap.SugaredLogger) ([]byte, []byte) {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+
+	body, err := protojson.Marshal(protoMsg)
+	if err != nil {
+		logger.Errorf("error marshalling %T to json: %v", msg, err)
+	}
+	protoBody, err := proto.Marshal(protoMsg)
+	if err != nil {
+		logger.Errorf("error marshalling %T: %v", msg, err)
+	}
+	return body, protoBody
+}
+
+func formatMetadata(md metadata.MD) map[string]string {
+	formatted := map[string]string{}
+	for k, v := range md {
+		formatted[k] = strings.Join(v, ",")
+	}
+
+	return formatted
+}
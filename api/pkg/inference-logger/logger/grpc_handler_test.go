@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/caraml-dev/merlin/pkg/inference-logger/mocks"
+	mlogs "github.com/caraml-dev/merlin/pkg/log"
+)
+
+type recordingSink struct {
+	mu         sync.Mutex
+	logEntries []*LogEntry
+}
+
+func (s *recordingSink) Sink(logEntries []*LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logEntries = append(s.logEntries, logEntries...)
+	return nil
+}
+
+type upiModel struct {
+	upiv1.UnimplementedUniversalPredictionServiceServer
+
+	t        *testing.T
+	logID    string
+	response *upiv1.PredictValuesResponse
+	err      error
+}
+
+func (m *upiModel) PredictValues(ctx context.Context, request *upiv1.PredictValuesRequest) (*upiv1.PredictValuesResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	logIDs := md.Get(MerlinLogIdHeader)
+	require.Len(m.t, logIDs, 1)
+	assert.True(m.t, IsValidUUID(logIDs[0]), "metadata is not a valid UUID %s", logIDs[0])
+	if m.logID != "" {
+		assert.Equal(m.t, m.logID, logIDs[0])
+	}
+	assert.Equal(m.t, []string{"my-value"}, md.Get("my-key"))
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("model-key", "model-value"))
+	return m.response, m.err
+}
+
+func startGRPCServer(t *testing.T, server *grpc.Server) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestUnaryLoggerInterceptor(t *testing.T) {
+	request := &upiv1.PredictValuesRequest{
+		TargetName: "probability",
+		PredictionTable: &upiv1.Table{
+			Name: "driver_table",
+			Columns: []*upiv1.Column{
+				{Name: "rating", Type: upiv1.Type_TYPE_DOUBLE},
+			},
+			Rows: []*upiv1.Row{
+				{RowId: "1", Values: []*upiv1.Value{{DoubleValue: 4.5}}},
+			},
+		},
+	}
+	response := &upiv1.PredictValuesResponse{
+		PredictionResultTable: &upiv1.Table{
+			Name: "result",
+			Columns: []*upiv1.Column{
+				{Name: "probability", Type: upiv1.Type_TYPE_DOUBLE},
+			},
+			Rows: []*upiv1.Row{
+				{RowId: "1", Values: []*upiv1.Value{{DoubleValue: 0.2}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		logMode        LogMode
+		logID          string
+		modelErr       error
+		wantStatusCode codes.Code
+		wantRequest    bool
+		wantResponse   bool
+	}{
+		{
+			name:           "nominal case",
+			logMode:        LogModeAll,
+			wantStatusCode: codes.OK,
+			wantRequest:    true,
+			wantResponse:   true,
+		},
+		{
+			name:           "nominal case with existing merlin log id",
+			logMode:        LogModeAll,
+			logID:          "4d93054e-5ad8-4e3c-9d97-a206560fc77b",
+			wantStatusCode: codes.OK,
+			wantRequest:    true,
+			wantResponse:   true,
+		},
+		{
+			name:           "request only",
+			logMode:        LogModeRequestOnly,
+			wantStatusCode: codes.OK,
+			wantRequest:    true,
+		},
+		{
+			name:           "response only",
+			logMode:        LogModeResponseOnly,
+			wantStatusCode: codes.OK,
+			wantResponse:   true,
+		},
+		{
+			name:           "error response",
+			logMode:        LogModeAll,
+			modelErr:       status.Error(codes.InvalidArgument, "invalid prediction table"),
+			wantStatusCode: codes.InvalidArgument,
+			wantRequest:    true,
+			wantResponse:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := grpc.NewServer()
+			upiv1.RegisterUniversalPredictionServiceServer(model, &upiModel{t: t, logID: tt.logID, response: response, err: tt.modelErr})
+			modelConn := startGRPCServer(t, model)
+
+			sink := &recordingSink{}
+			dispatcher := NewDispatcher(1, 100, workerConfig, logger, sink)
+			dispatcher.Start()
+
+			proxy := grpc.NewServer(grpc.UnaryInterceptor(NewUnaryLoggerInterceptor(dispatcher, tt.logMode, nil, logger)))
+			upiv1.RegisterUniversalPredictionServiceServer(proxy, NewUPIProxy(upiv1.NewUniversalPredictionServiceClient(modelConn)))
+			proxyConn := startGRPCServer(t, proxy)
+
+			md := metadata.Pairs("my-key", "my-value")
+			if tt.logID != "" {
+				md.Set(MerlinLogIdHeader, tt.logID)
+			}
+			ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+			var header metadata.MD
+			resp, err := upiv1.NewUniversalPredictionServiceClient(proxyConn).PredictValues(ctx, request, grpc.Header(&header))
+			assert.Equal(t, tt.wantStatusCode, status.Code(err))
+			if tt.modelErr == nil {
+				assert.True(t, proto.Equal(response, resp))
+			}
+			assert.Equal(t, []string{"model-value"}, header.Get("model-key"))
+
+			dispatcher.Stop()
+			require.Len(t, sink.logEntries, 1)
+			logEntry := sink.logEntries[0]
+			assert.True(t, IsValidUUID(logEntry.RequestId))
+			if tt.logID != "" {
+				assert.Equal(t, tt.logID, logEntry.RequestId)
+			}
+
+			if tt.wantRequest {
+				require.NotNil(t, logEntry.RequestPayload)
+				assert.Equal(t, "my-value", logEntry.RequestPayload.Headers["my-key"])
+				assert.Contains(t, string(logEntry.RequestPayload.Body), `"targetName":"probability"`)
+
+				loggedRequest := &upiv1.PredictValuesRequest{}
+				require.NoError(t, proto.Unmarshal(logEntry.RequestPayload.ProtoBody, loggedRequest))
+				assert.True(t, proto.Equal(request, loggedRequest))
+			} else {
+				assert.Nil(t, logEntry.RequestPayload)
+			}
+
+			if tt.wantResponse {
+				require.NotNil(t, logEntry.ResponsePayload)
+				assert.Equal(t, int(tt.wantStatusCode), logEntry.ResponsePayload.StatusCode)
+				if tt.modelErr != nil {
+					assert.Equal(t, "invalid prediction table", string(logEntry.ResponsePayload.Body))
+					assert.Nil(t, logEntry.ResponsePayload.ProtoBody)
+				} else {
+					loggedResponse := &upiv1.PredictValuesResponse{}
+					require.NoError(t, proto.Unmarshal(logEntry.ResponsePayload.ProtoBody, loggedResponse))
+					assert.True(t, proto.Equal(response, loggedResponse))
+				}
+			} else {
+				assert.Nil(t, logEntry.ResponsePayload)
+			}
+		})
+	}
+}
+
+func TestKafkaSink_ProtoBody(t *testing.T) {
+	var message *kafka.Message
+	mockKafkaProducer := &mocks.KafkaProducer{}
+	mockKafkaProducer.On("Produce", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			message = args.Get(0).(*kafka.Message)
+		}).
+		Return(nil)
+	sink := NewKafkaSink(logger, mockKafkaProducer, serviceName, projectName, modelName, modelVersion, topicName)
+
+	// protobuf encoded bodies aren't valid UTF-8 strings
+	requestBody := []byte{0x0a, 0xff, 0xfe}
+	responseBody := []byte{0x12, 0xfd}
+	err := sink.Sink([]*LogEntry{{
+		RequestId:       "1",
+		RequestPayload:  &RequestPayload{Body: []byte(`{}`), ProtoBody: requestBody},
+		ResponsePayload: &ResponsePayload{Body: []byte(`{}`), ProtoBody: responseBody},
+	}})
+	require.NoError(t, err)
+	require.NotNil(t, message)
+
+	logMessage := &mlogs.InferenceLogMessage{}
+	require.NoError(t, proto.Unmarshal(message.Value, logMessage))
+	assert.Equal(t, requestBody, logMessage.Request.ProtoBody)
+	assert.Equal(t, responseBody, logMessage.Response.ProtoBody)
+	assert.Equal(t, "{}", logMessage.Request.Body)
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
 )
 
 const (
@@ -29,14 +31,16 @@ const (
 type LoggerHandler struct {
 	dispatcher *Dispatcher
 	logMode    LogMode
+	policy     *logpolicy.Policy
 	next       http.Handler
 	logger     *zap.SugaredLogger
 }
 
-func NewLoggerHandler(dispatcher *Dispatcher, logMode LogMode, next http.Handler, logger *zap.SugaredLogger) http.Handler {
+func NewLoggerHandler(dispatcher *Dispatcher, logMode LogMode, policy *logpolicy.Policy, next http.Handler, logger *zap.SugaredLogger) http.Handler {
 	return &LoggerHandler{
 		dispatcher: dispatcher,
 		logMode:    logMode,
+		policy:     policy,
 		next:       next,
 		logger:     logger,
 	}
@@ -71,7 +75,11 @@ func (eh *LoggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	statusCode := http.StatusInternalServerError
 	defer func() {
+		if !applyLogPolicy(eh.policy, logEntry, statusCode) {
+			return
+		}
 		if err := eh.dispatcher.Submit(logEntry); err != nil {
 			eh.logger.Errorf("error submitting log entry: %v", err)
 		}
@@ -82,6 +90,7 @@ func (eh *LoggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r.Body = io.NopCloser(bytes.NewBuffer(requestBody))
 	rr := httptest.NewRecorder()
 	eh.next.ServeHTTP(rr, r)
+	statusCode = rr.Code
 
 	copyHeader(w.Header(), rr.Header())
 	respBody, err := io.ReadAll(rr.Body)
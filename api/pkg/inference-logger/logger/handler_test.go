@@ -161,7 +161,7 @@ func Test(t *testing.T) {
 					dispatcher := NewDispatcher(10, 100, workerConfig, logger, NewNewRelicSink(zapLogger, mockNewRelicLogsClient, serviceName, projectName, modelName, modelVersion), NewConsoleSink(logger))
 					dispatcher.Start()
 					httpProxy := httputil.NewSingleHostReverseProxy(targetUri)
-					oh := NewLoggerHandler(dispatcher, LogModeAll, httpProxy, logger)
+					oh := NewLoggerHandler(dispatcher, LogModeAll, nil, httpProxy, logger)
 
 					oh.ServeHTTP(w, r)
 
@@ -195,7 +195,7 @@ func Test(t *testing.T) {
 					dispatcher := NewDispatcher(10, 100, workerConfig, logger, NewKafkaSink(zapLogger, mockKafkaProducer, serviceName, projectName, modelName, modelVersion, topicName), NewConsoleSink(logger))
 					dispatcher.Start()
 					httpProxy := httputil.NewSingleHostReverseProxy(targetUri)
-					oh := NewLoggerHandler(dispatcher, LogModeAll, httpProxy, logger)
+					oh := NewLoggerHandler(dispatcher, LogModeAll, nil, httpProxy, logger)
 
 					oh.ServeHTTP(w, r)
 
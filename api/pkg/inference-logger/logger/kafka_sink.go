@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/caraml-dev/merlin/pkg/kafka/schemaregistry"
 	mlogs "github.com/caraml-dev/merlin/pkg/log"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	sr "github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry/serde"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/proto"
 )
 
 type KafkaProducer interface {
@@ -34,6 +36,10 @@ type KafkaSink struct {
 	projectName  string
 	modelName    string
 	modelVersion string
+
+	// keySerializer and valueSerializer serialize the messages in the Confluent wire format, they're nil if there's no schema registry
+	keySerializer   schemaregistry.Serializer
+	valueSerializer schemaregistry.Serializer
 }
 
 func NewKafkaSink(
@@ -56,6 +62,40 @@ func NewKafkaSink(
 	}
 }
 
+// NewSchemaRegistryKafkaSink creates a KafkaSink which registers the schemas of the keys and values in the schema registry,
+// under the <topic>-key and <topic>-value subjects, and serializes them in the Confluent wire format
+func NewSchemaRegistryKafkaSink(
+	logger *zap.SugaredLogger,
+	producer KafkaProducer,
+	client sr.Client,
+	serviceName string,
+	projectName string,
+	modelName string,
+	modelVersion string,
+	topic string,
+) (LogSink, error) {
+	keySerializer, err := schemaregistry.NewProtobufSerializer(client, serde.KeySerde)
+	if err != nil {
+		return nil, err
+	}
+	valueSerializer, err := schemaregistry.NewProtobufSerializer(client, serde.ValueSerde)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaSink{
+		logger:          logger,
+		producer:        producer,
+		topic:           topic,
+		serviceName:     serviceName,
+		projectName:     projectName,
+		modelName:       modelName,
+		modelVersion:    modelVersion,
+		keySerializer:   keySerializer,
+		valueSerializer: valueSerializer,
+	}, nil
+}
+
 func (k *KafkaSink) Sink(rawLogEntries []*LogEntry) error {
 	// Format log inputs
 	inferenceLogs, err := k.newLogMessages(rawLogEntries)
@@ -140,13 +180,13 @@ func (k *KafkaSink) buildNewKafkaMessage(
 	}
 
 	// Marshal the key
-	keyBytes, err = proto.Marshal(key)
+	keyBytes, err = schemaregistry.SerializeProto(k.keySerializer, k.topic, key)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to marshal kafka key, %w", err)
 	}
 
 	// Marshal the message
-	valueBytes, err = proto.Marshal(merlinLog)
+	valueBytes, err = schemaregistry.SerializeProto(k.valueSerializer, k.topic, merlinLog)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to marshal kafka value, %w", err)
 	}
@@ -161,8 +201,9 @@ func (k *KafkaSink) newLogMessages(logEntries []*LogEntry) ([]*mlogs.InferenceLo
 		request := &mlogs.Request{}
 		if logEntry.RequestPayload != nil {
 			request = &mlogs.Request{
-				Header: logEntry.RequestPayload.Headers,
-				Body:   string(logEntry.RequestPayload.Body),
+				Header:    logEntry.RequestPayload.Headers,
+				Body:      string(logEntry.RequestPayload.Body),
+				ProtoBody: logEntry.RequestPayload.ProtoBody,
 			}
 		}
 
@@ -171,6 +212,7 @@ func (k *KafkaSink) newLogMessages(logEntries []*LogEntry) ([]*mlogs.InferenceLo
 			response = &mlogs.Response{
 				StatusCode: int32(logEntry.ResponsePayload.StatusCode),
 				Body:       string(logEntry.ResponsePayload.Body),
+				ProtoBody:  logEntry.ResponsePayload.ProtoBody,
 			}
 		}
 
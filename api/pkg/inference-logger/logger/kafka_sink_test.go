@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/caraml-dev/merlin/pkg/inference-logger/mocks"
+	mlogs "github.com/caraml-dev/merlin/pkg/log"
+)
+
+func TestSchemaRegistryKafkaSink(t *testing.T) {
+	var message *kafka.Message
+	mockKafkaProducer := &mocks.KafkaProducer{}
+	mockKafkaProducer.On("Produce", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			message = args.Get(0).(*kafka.Message)
+		}).
+		Return(nil)
+
+	client, err := schemaregistry.NewClient(schemaregistry.NewConfig("mock://"))
+	require.NoError(t, err)
+	sink, err := NewSchemaRegistryKafkaSink(logger, mockKafkaProducer, client, serviceName, projectName, modelName, modelVersion, topicName)
+	require.NoError(t, err)
+
+	err = sink.Sink([]*LogEntry{{
+		RequestId:       "1",
+		EventTimestamp:  timestamppb.Now(),
+		RequestPayload:  &RequestPayload{Body: []byte(`{"instances":[1]}`)},
+		ResponsePayload: &ResponsePayload{StatusCode: 200, Body: []byte(`{"predictions":[2]}`)},
+	}})
+	require.NoError(t, err)
+	require.NotNil(t, message)
+
+	for subject, payload := range map[string][]byte{topicName + "-key": message.Key, topicName + "-value": message.Value} {
+		metadata, err := client.GetLatestSchemaMetadata(subject)
+		require.NoError(t, err)
+		assert.Equal(t, "PROTOBUF", metadata.SchemaType)
+
+		// magic byte, schema id and message indexes
+		require.Greater(t, len(payload), 6)
+		assert.Equal(t, byte(0), payload[0])
+		assert.Equal(t, uint32(metadata.ID), binary.BigEndian.Uint32(payload[1:5]))
+	}
+
+	// InferenceLogKey is the first message of inference_log.proto, its message indexes are encoded as a single 0
+	assert.Equal(t, byte(0), message.Key[5])
+	key := &mlogs.InferenceLogKey{}
+	require.NoError(t, proto.Unmarshal(message.Key[6:], key))
+	assert.Equal(t, "1", key.RequestId)
+	assert.Equal(t, modelVersion, key.ModelVersion)
+
+	// InferenceLogMessage is the second one, its message indexes are the zigzag encoded count and index, both 1
+	assert.Equal(t, []byte{2, 2}, message.Value[5:7])
+	logMessage := &mlogs.InferenceLogMessage{}
+	require.NoError(t, proto.Unmarshal(message.Value[7:], logMessage))
+	assert.Equal(t, `{"instances":[1]}`, logMessage.Request.Body)
+	assert.Equal(t, int32(200), logMessage.Response.StatusCode)
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
+)
+
+// applyLogPolicy samples, redacts and truncates the payloads of the log entry, it returns false if the log entry must not be logged
+func applyLogPolicy(policy *logpolicy.Policy, logEntry *LogEntry, statusCode int) bool {
+	if policy.IsEmpty() {
+		return true
+	}
+
+	if !policy.Sample(statusCode) {
+		return false
+	}
+
+	if logEntry.RequestPayload != nil {
+		logEntry.RequestPayload.Body, logEntry.RequestPayload.ProtoBody = applyPayloadPolicy(policy, logpolicy.PayloadRequest, logEntry.RequestPayload.Body, logEntry.RequestPayload.ProtoBody)
+	}
+	if logEntry.ResponsePayload != nil {
+		logEntry.ResponsePayload.Body, logEntry.ResponsePayload.ProtoBody = applyPayloadPolicy(policy, logpolicy.PayloadResponse, logEntry.ResponsePayload.Body, logEntry.ResponsePayload.ProtoBody)
+	}
+	return true
+}
+
+// applyPayloadPolicy redacts and truncates the body. The protobuf encoded body can neither be redacted nor truncated,
+// so it's dropped if the body is modified, leaving only its JSON representation.
+func applyPayloadPolicy(policy *logpolicy.Policy, payload logpolicy.Payload, body []byte, protoBody []byte) ([]byte, []byte) {
+	body, redacted := policy.Redact(body, payload)
+	body, truncated := policy.Truncate(body)
+	if redacted || truncated {
+		return body, nil
+	}
+
+	if _, protoTruncated := policy.Truncate(protoBody); protoTruncated {
+		return body, nil
+	}
+	return body, protoBody
+}
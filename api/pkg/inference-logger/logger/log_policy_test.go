@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/caraml-dev/merlin/pkg/inference-logger/logpolicy"
+)
+
+func TestApplyLogPolicy(t *testing.T) {
+	zero, one := 0.0, 1.0
+	tests := []struct {
+		name         string
+		policy       *logpolicy.Policy
+		statusCode   int
+		wantLogged   bool
+		wantRequest  *RequestPayload
+		wantResponse *ResponsePayload
+	}{
+		{
+			name:         "no policy",
+			policy:       nil,
+			statusCode:   200,
+			wantLogged:   true,
+			wantRequest:  &RequestPayload{Body: []byte(`{"phone":"+6281234567"}`), ProtoBody: []byte{0x0a}},
+			wantResponse: &ResponsePayload{StatusCode: 200, Body: []byte(`{"predictions":[1,2,3]}`), ProtoBody: []byte{0x0b}},
+		},
+		{
+			name:       "sampled out",
+			policy:     &logpolicy.Policy{Sampling: &logpolicy.Sampling{Rate: &zero}},
+			statusCode: 200,
+			wantLogged: false,
+		},
+		{
+			name: "errors are always logged",
+			policy: &logpolicy.Policy{Sampling: &logpolicy.Sampling{
+				Rate:            &zero,
+				StatusCodeRules: []logpolicy.StatusCodeRule{{StatusCode: "5xx", Rate: one}},
+			}},
+			statusCode:   500,
+			wantLogged:   true,
+			wantRequest:  &RequestPayload{Body: []byte(`{"phone":"+6281234567"}`), ProtoBody: []byte{0x0a}},
+			wantResponse: &ResponsePayload{StatusCode: 200, Body: []byte(`{"predictions":[1,2,3]}`), ProtoBody: []byte{0x0b}},
+		},
+		{
+			name: "redacted request and truncated response drop their proto body",
+			policy: &logpolicy.Policy{
+				Redactions:     []logpolicy.Redaction{{Path: "$.phone", Action: logpolicy.RedactionMask, Payload: logpolicy.PayloadRequest}},
+				MaxPayloadSize: 22,
+			},
+			statusCode:   200,
+			wantLogged:   true,
+			wantRequest:  &RequestPayload{Body: []byte(`{"phone":"[REDACTED]"}`)},
+			wantResponse: &ResponsePayload{StatusCode: 200, Body: []byte(`{"predictions":[1,2,3]`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.policy.Validate())
+
+			logEntry := &LogEntry{
+				RequestId:       "1",
+				RequestPayload:  &RequestPayload{Body: []byte(`{"phone":"+6281234567"}`), ProtoBody: []byte{0x0a}},
+				ResponsePayload: &ResponsePayload{StatusCode: 200, Body: []byte(`{"predictions":[1,2,3]}`), ProtoBody: []byte{0x0b}},
+			}
+			logged := applyLogPolicy(tt.policy, logEntry, tt.statusCode)
+			assert.Equal(t, tt.wantLogged, logged)
+			if tt.wantLogged {
+				assert.Equal(t, tt.wantRequest, logEntry.RequestPayload)
+				assert.Equal(t, tt.wantResponse, logEntry.ResponsePayload)
+			}
+		})
+	}
+}
+
+func TestLoggerHandler_LogPolicy(t *testing.T) {
+	zero, one := 0.0, 1.0
+	policy := &logpolicy.Policy{
+		Sampling: &logpolicy.Sampling{
+			Rate:            &zero,
+			StatusCodeRules: []logpolicy.StatusCodeRule{{StatusCode: "4xx", Rate: one}},
+		},
+		Redactions: []logpolicy.Redaction{{Path: "$.customer.phone", Action: logpolicy.RedactionMask}},
+	}
+	require.NoError(t, policy.Validate())
+
+	sink := &recordingSink{}
+	dispatcher := NewDispatcher(1, 100, workerConfig, logger, sink)
+	dispatcher.Start()
+
+	statusCode := http.StatusOK
+	model := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(statusCode)
+		_, _ = rw.Write([]byte(`{"error":"invalid phone"}`))
+	})
+	handler := NewLoggerHandler(dispatcher, LogModeAll, policy, model, logger)
+
+	for _, code := range []int{http.StatusOK, http.StatusBadRequest} {
+		statusCode = code
+		r := httptest.NewRequest("POST", "http://a", bytes.NewReader([]byte(`{"customer":{"phone":"+6281234567"}}`)))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		// the proxied request isn't redacted
+		assert.Equal(t, code, w.Code)
+		assert.Equal(t, `{"error":"invalid phone"}`, w.Body.String())
+	}
+
+	dispatcher.Stop()
+	require.Len(t, sink.logEntries, 1)
+	assert.Equal(t, `{"customer":{"phone":"[REDACTED]"}}`, string(sink.logEntries[0].RequestPayload.Body))
+	assert.Equal(t, http.StatusBadRequest, sink.logEntries[0].ResponsePayload.StatusCode)
+}
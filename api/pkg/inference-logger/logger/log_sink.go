@@ -3,3 +3,12 @@ package logger
 type LogSink interface {
 	Sink(rawLogEntries []*LogEntry) error
 }
+
+// FlushableLogSink is a LogSink buffering the log entries before writing them
+type FlushableLogSink interface {
+	LogSink
+	// Flush writes the log entries which have been buffered for too long
+	Flush() error
+	// Close writes all the buffered log entries
+	Close() error
+}
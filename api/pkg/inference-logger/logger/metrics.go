@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	dropReasonQueueFull      = "queue_full"
+	dropReasonQueueClosed    = "queue_closed"
+	dropReasonBufferFull     = "buffer_full"
+	dropReasonBufferFailed   = "buffer_failed"
+	dropReasonSerialization  = "serialization"
+	dropReasonCorrupted      = "corrupted"
+	dropReasonDeliveryFailed = "delivery_failed"
+)
+
+var bufferedEntries = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name:      "buffered_entries",
+		Namespace: "merlin_inference_logger",
+		Help:      "Number of log entries in the disk buffer",
+	},
+)
+
+var bufferedBytes = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name:      "buffered_bytes",
+		Namespace: "merlin_inference_logger",
+		Help:      "Size of the segment files of the disk buffer",
+	},
+)
+
+var droppedEntries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:      "dropped_entries_total",
+		Namespace: "merlin_inference_logger",
+		Help:      "Number of log entries dropped",
+	},
+	[]string{"reason"},
+)
+
+var retriedEntries = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name:      "retried_entries_total",
+		Namespace: "merlin_inference_logger",
+		Help:      "Number of log entries whose delivery is retried",
+	},
+)
+
+var deadLetterEntries = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name:      "dead_letter_entries_total",
+		Namespace: "merlin_inference_logger",
+		Help:      "Number of log entries sent to the dead-letter sink",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(bufferedEntries, bufferedBytes, droppedEntries, retriedEntries, deadLetterEntries)
+}
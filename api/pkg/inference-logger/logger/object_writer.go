@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ObjectWriter writes the files of the FileSink
+type ObjectWriter interface {
+	Write(ctx context.Context, key string, data []byte) error
+}
+
+type LocalObjectWriter struct {
+	dir string
+}
+
+// NewLocalObjectWriter creates an ObjectWriter writing the files under dir, e.g. a volume mounted in the logger container
+func NewLocalObjectWriter(dir string) ObjectWriter {
+	return &LocalObjectWriter{dir: dir}
+}
+
+func (w *LocalObjectWriter) Write(_ context.Context, key string, data []byte) error {
+	path := filepath.Join(w.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create directory of %s: %w", path, err)
+	}
+
+	// the file is renamed once fully written so that readers never see a partial file
+	tmpPath := path + ".inprogress"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("unable to rename %s: %w", tmpPath, err)
+	}
+	return nil
+}
+
+type S3Client interface {
+	PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error)
+}
+
+type S3ObjectWriter struct {
+	client S3Client
+	bucket string
+	prefix string
+}
+
+// NewS3Client creates a client of an S3-compatible object storage, e.g. MinIO when endpoint is set.
+// The credentials are taken from the default AWS credential chain.
+func NewS3Client(endpoint string, region string) (S3Client, error) {
+	config := &aws.Config{
+		Region: aws.String(region),
+	}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+		// most S3-compatible storages don't support virtual-hosted-style buckets
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create S3 session: %w", err)
+	}
+	return s3.New(sess), nil
+}
+
+// NewS3ObjectWriter creates an ObjectWriter uploading the files to bucket under prefix
+func NewS3ObjectWriter(client S3Client, bucket string, prefix string) ObjectWriter {
+	return &S3ObjectWriter{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+func (w *S3ObjectWriter) Write(ctx context.Context, key string, data []byte) error {
+	if w.prefix != "" {
+		key = w.prefix + "/" + key
+	}
+
+	_, err := w.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to upload s3://%s/%s: %w", w.bucket, key, err)
+	}
+	return nil
+}
@@ -13,11 +13,15 @@ type LogEntry struct {
 type RequestPayload struct {
 	Headers map[string]string
 	Body    []byte
+	// ProtoBody is the protobuf encoded request of the gRPC models, Body is then its JSON representation
+	ProtoBody []byte
 }
 
 type ResponsePayload struct {
 	StatusCode int
 	Body       []byte
+	// ProtoBody is the protobuf encoded response of the gRPC models, Body is then its JSON representation
+	ProtoBody []byte
 }
 
 type LogMode string
@@ -34,6 +38,8 @@ const (
 	Kafka    LoggerSinkKind = "kafka"
 	NewRelic LoggerSinkKind = "newrelic"
 	Console  LoggerSinkKind = "console"
+	File     LoggerSinkKind = "file"
+	S3       LoggerSinkKind = "s3"
 )
 
-var LoggerSinkKinds = []LoggerSinkKind{Kafka, NewRelic, Console}
+var LoggerSinkKinds = []LoggerSinkKind{Kafka, NewRelic, Console, File, S3}
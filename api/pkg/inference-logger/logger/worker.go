@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -9,8 +11,10 @@ type Worker struct {
 	id                         string
 	workerQueue                *BatchQueue
 	minBatchSize, maxBatchSize int
+	flushInterval              time.Duration
 
 	quitChan chan bool
+	doneChan chan struct{}
 
 	logger   *zap.SugaredLogger
 	logSinks []LogSink
@@ -19,17 +23,21 @@ type Worker struct {
 type WorkerConfig struct {
 	MinBatchSize int
 	MaxBatchSize int
+	// FlushInterval is the interval at which the log entries buffered by FlushableLogSink for too long are written
+	FlushInterval time.Duration
 }
 
 // NewWorker creates a worker that listen to work in workerQueue
 func NewWorker(workerQueue *BatchQueue, workerConfig *WorkerConfig, logger *zap.SugaredLogger, logSinks ...LogSink) *Worker {
 	return &Worker{
-		id:           uuid.New().String(),
-		workerQueue:  workerQueue,
-		minBatchSize: workerConfig.MinBatchSize,
-		maxBatchSize: workerConfig.MaxBatchSize,
-		logger:       logger,
-		logSinks:     logSinks,
+		id:            uuid.New().String(),
+		workerQueue:   workerQueue,
+		minBatchSize:  workerConfig.MinBatchSize,
+		maxBatchSize:  workerConfig.MaxBatchSize,
+		flushInterval: workerConfig.FlushInterval,
+		doneChan:      make(chan struct{}),
+		logger:        logger,
+		logSinks:      logSinks,
 	}
 }
 
@@ -37,10 +45,19 @@ func NewWorker(workerQueue *BatchQueue, workerConfig *WorkerConfig, logger *zap.
 //
 // Call Stop to stop the worker from performing further works
 func (w *Worker) Start() {
+	stopFlush := make(chan struct{})
+	if w.flushInterval > 0 {
+		go w.flushPeriodically(stopFlush)
+	}
+
 	go func() {
 		w.logger.Infof("Starting worker: %s\n", w.id)
 		defer func() {
+			close(stopFlush)
+			// write the log entries still buffered by the sinks so that they're not lost on shutdown
+			w.closeSinks()
 			w.logger.Infof("worker %s is stopped, exiting... \n", w.id)
+			close(w.doneChan)
 		}()
 
 		for {
@@ -101,6 +118,41 @@ func (w *Worker) Stop() {
 	}()
 }
 
+// Wait blocks until the worker has stopped and the sinks have written their buffered log entries.
+func (w *Worker) Wait() {
+	<-w.doneChan
+}
+
+func (w *Worker) flushPeriodically(stop chan struct{}) {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, logSink := range w.logSinks {
+				if flushable, ok := logSink.(FlushableLogSink); ok {
+					if err := flushable.Flush(); err != nil {
+						w.logger.Errorf("error flushing log entries: %v", err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (w *Worker) closeSinks() {
+	for _, logSink := range w.logSinks {
+		if flushable, ok := logSink.(FlushableLogSink); ok {
+			if err := flushable.Close(); err != nil {
+				w.logger.Errorf("error writing remaining log entries: %v", err)
+			}
+		}
+	}
+}
+
 func (w *Worker) Send(rawLogEntries []*LogEntry) error {
 	for _, logSink := range w.logSinks {
 		err := logSink.Sink(rawLogEntries)
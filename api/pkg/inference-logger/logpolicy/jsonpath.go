@@ -0,0 +1,120 @@
+package logpolicy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment selects either a field of an object, an element of an array or, if wildcard is set, all of them
+type pathSegment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// jsonPath is the subset of JSONPath that selects fields to redact: $.a.b, $['a'], $.a[0], $.a[*] and $.a.*
+type jsonPath struct {
+	segments []pathSegment
+}
+
+func compileJSONPath(path string) (jsonPath, error) {
+	if !strings.HasPrefix(path, "$") {
+		return jsonPath{}, fmt.Errorf("invalid path %q, it must start with $", path)
+	}
+
+	segments := make([]pathSegment, 0)
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			field := rest[1 : end+1]
+			if field == "" {
+				return jsonPath{}, fmt.Errorf("invalid path %q, empty field name", path)
+			}
+			segments = append(segments, pathSegment{field: field, wildcard: field == "*"})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return jsonPath{}, fmt.Errorf("invalid path %q, missing ]", path)
+			}
+			selector := rest[1:end]
+			switch {
+			case selector == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				segments = append(segments, pathSegment{field: selector[1 : len(selector)-1]})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return jsonPath{}, fmt.Errorf("invalid path %q, %s is not an array index", path, selector)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return jsonPath{}, fmt.Errorf("invalid path %q, unexpected %q", path, rest)
+		}
+	}
+
+	if len(segments) == 0 {
+		return jsonPath{}, fmt.Errorf("invalid path %q, it must select a field", path)
+	}
+	return jsonPath{segments: segments}, nil
+}
+
+// replace replaces the values selected by the path in the document
+func (p jsonPath) replace(document interface{}, replacer func(value interface{}) interface{}) interface{} {
+	if len(p.segments) == 0 {
+		return document
+	}
+	return replaceSegments(document, p.segments, replacer)
+}
+
+func replaceSegments(node interface{}, segments []pathSegment, replacer func(value interface{}) interface{}) interface{} {
+	if len(segments) == 0 {
+		return replacer(node)
+	}
+
+	segment, rest := segments[0], segments[1:]
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if segment.isIndex {
+			return node
+		}
+		for key, child := range value {
+			if segment.wildcard || key == segment.field {
+				value[key] = replaceSegments(child, rest, replacer)
+			}
+		}
+	case []interface{}:
+		if segment.wildcard {
+			for i, child := range value {
+				value[i] = replaceSegments(child, rest, replacer)
+			}
+		} else if segment.isIndex && segment.index < len(value) {
+			value[segment.index] = replaceSegments(value[segment.index], rest, replacer)
+		}
+	}
+	return node
+}
+
+// hash returns the SHA-256 hex digest of the value, strings are hashed as is and other values in their JSON representation
+func hash(value interface{}) string {
+	data, ok := value.(string)
+	if !ok {
+		encoded, _ := json.Marshal(value)
+		data = string(encoded)
+	}
+	digest := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,48 @@
+package logpolicy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URLOptionsPrefix is the prefix of the log-url fragment carrying the options of the inference logger as a query string,
+// e.g. #merlin-logger-options=protocol=UPI_V1. It precedes the fragment of the policy.
+const URLOptionsPrefix = "#merlin-logger-options="
+
+const (
+	// OptionProtocol is the protocol of the logged component, the gRPC requests of UPI_V1 components are logged too
+	OptionProtocol = "protocol"
+	// OptionBufferDir enables the disk buffer in the directory, which should be an emptyDir volume to survive container restarts
+	OptionBufferDir = "buffer_dir"
+	// OptionBufferMaxSize is the size in bytes of the disk buffer, including the dead-letter directory
+	OptionBufferMaxSize = "buffer_max_size"
+	// OptionDeadLetterURL is the log sink of the log entries which can't be delivered, it has to be URL-encoded
+	OptionDeadLetterURL = "dead_letter_url"
+	// OptionMetricsPort is the port of the metrics of the disk buffer
+	OptionMetricsPort = "metrics_port"
+)
+
+// SetURLOption sets an option of the inference logger in the log-url, the policy has to be appended afterwards
+func SetURLOption(logURL, key, value string) (string, error) {
+	sinkURL, options, err := ParseURLOptions(logURL)
+	if err != nil {
+		return "", err
+	}
+	options.Set(key, value)
+	return sinkURL + URLOptionsPrefix + options.Encode(), nil
+}
+
+// ParseURLOptions splits the log-url, without its policy, into the URL of the log sink and the options of the inference logger
+func ParseURLOptions(logURL string) (string, url.Values, error) {
+	idx := strings.LastIndex(logURL, URLOptionsPrefix)
+	if idx < 0 {
+		return logURL, url.Values{}, nil
+	}
+
+	options, err := url.ParseQuery(logURL[idx+len(URLOptionsPrefix):])
+	if err != nil {
+		return "", nil, fmt.Errorf("malformed logger options: %w", err)
+	}
+	return logURL[:idx], options, nil
+}
@@ -0,0 +1,50 @@
+package logpolicy
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetURLOptionAndParseURLOptions(t *testing.T) {
+	policy := &Policy{MaxPayloadSize: 1024}
+
+	for _, logURL := range []string{
+		"kafka:broker-1:9092,broker-2:9092",
+		"s3:my-bucket/logs?format=parquet",
+	} {
+		t.Run(logURL, func(t *testing.T) {
+			withOptions, err := SetURLOption(logURL, OptionProtocol, "UPI_V1")
+			require.NoError(t, err)
+			assert.Equal(t, logURL+"#merlin-logger-options=protocol=UPI_V1", withOptions)
+
+			// the policy is appended after the options
+			withPolicy, err := AppendToURL(withOptions, policy)
+			require.NoError(t, err)
+
+			withoutPolicy, parsedPolicy, err := ParseURL(withPolicy)
+			require.NoError(t, err)
+			assert.Equal(t, 1024, parsedPolicy.MaxPayloadSize)
+
+			sinkURL, options, err := ParseURLOptions(withoutPolicy)
+			require.NoError(t, err)
+			assert.Equal(t, logURL, sinkURL)
+			assert.Equal(t, url.Values{OptionProtocol: {"UPI_V1"}}, options)
+		})
+	}
+
+	// the option replaces the one already in the log-url
+	withOptions, err := SetURLOption("kafka:broker:9092#merlin-logger-options=protocol=HTTP_JSON", OptionProtocol, "UPI_V1")
+	require.NoError(t, err)
+	assert.Equal(t, "kafka:broker:9092#merlin-logger-options=protocol=UPI_V1", withOptions)
+
+	sinkURL, options, err := ParseURLOptions("kafka:broker:9092")
+	require.NoError(t, err)
+	assert.Equal(t, "kafka:broker:9092", sinkURL)
+	assert.Empty(t, options)
+
+	_, _, err = ParseURLOptions("kafka:broker:9092" + URLOptionsPrefix + "protocol=%zz")
+	assert.ErrorContains(t, err, "malformed logger options")
+}
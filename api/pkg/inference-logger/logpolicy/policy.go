@@ -0,0 +1,246 @@
+package logpolicy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// URLFragmentPrefix is the prefix of the log-url fragment carrying the policy to the inference logger,
+// it's the only way to configure the sidecar injected by KServe besides the log mode
+const URLFragmentPrefix = "#merlin-log-policy="
+
+// Payload is the part of the log entry a redaction applies to
+type Payload string
+
+const (
+	PayloadRequest  Payload = "request"
+	PayloadResponse Payload = "response"
+)
+
+// RedactionAction is what is done with the value of a redacted field
+type RedactionAction string
+
+const (
+	// RedactionMask replaces the value with RedactedValue
+	RedactionMask RedactionAction = "mask"
+	// RedactionHash replaces the value with its SHA-256 hex digest, so that it can still be joined on
+	RedactionHash RedactionAction = "hash"
+)
+
+// RedactedValue replaces the masked values
+const RedactedValue = "[REDACTED]"
+
+var statusCodePattern = regexp.MustCompile(`^[1-5]([0-9]|x)([0-9]|x)$`)
+
+// Policy decides which requests are logged and what of their payloads is kept
+type Policy struct {
+	// Sampling of the logged requests, all of them are logged if it's not set
+	Sampling *Sampling `json:"sampling,omitempty"`
+	// Redactions of the JSON fields of the payloads
+	Redactions []Redaction `json:"redactions,omitempty"`
+	// MaxPayloadSize is the size in bytes above which the payloads are truncated, 0 means no limit
+	MaxPayloadSize int `json:"max_payload_size,omitempty"`
+}
+
+// Sampling of the logged requests
+type Sampling struct {
+	// Rate is the fraction of the requests logged, between 0 and 1, all of them are logged if it's not set
+	Rate *float64 `json:"rate,omitempty"`
+	// StatusCodeRules override the rate of the requests whose response has a matching status code, the first matching rule applies
+	StatusCodeRules []StatusCodeRule `json:"status_code_rules,omitempty"`
+}
+
+// StatusCodeRule is the sampling rate of the requests with a given response status code
+type StatusCodeRule struct {
+	// StatusCode is either a status code, e.g. 429, or a class of status codes, e.g. 5xx
+	StatusCode string  `json:"status_code"`
+	Rate       float64 `json:"rate"`
+}
+
+// Redaction of a JSON field of the payloads
+type Redaction struct {
+	// Path of the field, e.g. $.customer.phone or $.instances[*].phone
+	Path   string          `json:"path"`
+	Action RedactionAction `json:"action"`
+	// Payload is either request or response, the redaction applies to both if it's not set
+	Payload Payload `json:"payload,omitempty"`
+
+	compiled jsonPath
+}
+
+// IsEmpty returns true if the policy logs every request in full
+func (p *Policy) IsEmpty() bool {
+	return p == nil || (p.Sampling == nil && len(p.Redactions) == 0 && p.MaxPayloadSize == 0)
+}
+
+// Validate checks the policy and compiles its redaction paths
+func (p *Policy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.Sampling != nil {
+		if p.Sampling.Rate != nil && (*p.Sampling.Rate < 0 || *p.Sampling.Rate > 1) {
+			return fmt.Errorf("sampling rate %v must be between 0 and 1", *p.Sampling.Rate)
+		}
+		for _, rule := range p.Sampling.StatusCodeRules {
+			if !statusCodePattern.MatchString(rule.StatusCode) {
+				return fmt.Errorf("invalid status code %q, it must be a status code like 429 or a class of status codes like 5xx", rule.StatusCode)
+			}
+			if rule.Rate < 0 || rule.Rate > 1 {
+				return fmt.Errorf("sampling rate %v of status code %s must be between 0 and 1", rule.Rate, rule.StatusCode)
+			}
+		}
+	}
+
+	for i := range p.Redactions {
+		redaction := &p.Redactions[i]
+		switch redaction.Action {
+		case RedactionMask, RedactionHash:
+		default:
+			return fmt.Errorf("invalid redaction action %q of %s, it must be either %s or %s", redaction.Action, redaction.Path, RedactionMask, RedactionHash)
+		}
+		switch redaction.Payload {
+		case "", PayloadRequest, PayloadResponse:
+		default:
+			return fmt.Errorf("invalid redaction payload %q of %s, it must be either %s or %s", redaction.Payload, redaction.Path, PayloadRequest, PayloadResponse)
+		}
+
+		compiled, err := compileJSONPath(redaction.Path)
+		if err != nil {
+			return err
+		}
+		redaction.compiled = compiled
+	}
+
+	if p.MaxPayloadSize < 0 {
+		return fmt.Errorf("max payload size %d must not be negative", p.MaxPayloadSize)
+	}
+	return nil
+}
+
+// Sample returns true if the request, whose response has the status code, must be logged
+func (p *Policy) Sample(statusCode int) bool {
+	if p == nil || p.Sampling == nil {
+		return true
+	}
+
+	rate := 1.0
+	if p.Sampling.Rate != nil {
+		rate = *p.Sampling.Rate
+	}
+	code := strconv.Itoa(statusCode)
+	for _, rule := range p.Sampling.StatusCodeRules {
+		if matchStatusCode(rule.StatusCode, code) {
+			rate = rule.Rate
+			break
+		}
+	}
+
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// Redact applies the redactions of the payload to the JSON body, it returns whether the body has been modified.
+// A body which isn't JSON is dropped since its fields can't be redacted.
+func (p *Policy) Redact(body []byte, payload Payload) ([]byte, bool) {
+	if p == nil || len(body) == 0 {
+		return body, false
+	}
+
+	redactions := make([]Redaction, 0, len(p.Redactions))
+	for _, redaction := range p.Redactions {
+		if redaction.Payload == "" || redaction.Payload == payload {
+			redactions = append(redactions, redaction)
+		}
+	}
+	if len(redactions) == 0 {
+		return body, false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, true
+	}
+
+	redacted := false
+	for _, redaction := range redactions {
+		document = redaction.compiled.replace(document, func(value interface{}) interface{} {
+			redacted = true
+			if redaction.Action == RedactionHash {
+				return hash(value)
+			}
+			return RedactedValue
+		})
+	}
+	if !redacted {
+		return body, false
+	}
+
+	redactedBody, err := json.Marshal(document)
+	if err != nil {
+		return nil, true
+	}
+	return redactedBody, true
+}
+
+// Truncate cuts the body to the maximum payload size, it returns whether the body has been truncated
+func (p *Policy) Truncate(body []byte) ([]byte, bool) {
+	if p == nil || p.MaxPayloadSize <= 0 || len(body) <= p.MaxPayloadSize {
+		return body, false
+	}
+	return body[:p.MaxPayloadSize], true
+}
+
+// AppendToURL appends the policy to the log-url of the inference logger
+func AppendToURL(logURL string, policy *Policy) (string, error) {
+	if policy.IsEmpty() {
+		return logURL, nil
+	}
+
+	encoded, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	return logURL + URLFragmentPrefix + base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// ParseURL splits the log-url of the inference logger into the URL of the log sink and the policy, which is nil if there's none
+func ParseURL(logURL string) (string, *Policy, error) {
+	idx := strings.LastIndex(logURL, URLFragmentPrefix)
+	if idx < 0 {
+		return logURL, nil, nil
+	}
+
+	encoded, err := base64.RawURLEncoding.DecodeString(logURL[idx+len(URLFragmentPrefix):])
+	if err != nil {
+		return "", nil, fmt.Errorf("malformed log policy: %w", err)
+	}
+	policy := &Policy{}
+	if err := json.Unmarshal(encoded, policy); err != nil {
+		return "", nil, fmt.Errorf("malformed log policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return "", nil, errors.New("invalid log policy: " + err.Error())
+	}
+	return logURL[:idx], policy, nil
+}
+
+func matchStatusCode(pattern string, code string) bool {
+	if len(pattern) != len(code) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != 'x' && pattern[i] != code[i] {
+			return false
+		}
+	}
+	return true
+}
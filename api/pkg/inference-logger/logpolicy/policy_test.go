@@ -0,0 +1,259 @@
+package logpolicy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rate(r float64) *float64 {
+	return &r
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *Policy
+		wantErr string
+	}{
+		{
+			name:   "nil policy",
+			policy: nil,
+		},
+		{
+			name: "valid policy",
+			policy: &Policy{
+				Sampling: &Sampling{
+					Rate:            rate(0.1),
+					StatusCodeRules: []StatusCodeRule{{StatusCode: "5xx", Rate: 1}, {StatusCode: "429", Rate: 0.5}},
+				},
+				Redactions: []Redaction{
+					{Path: "$.customer.phone", Action: RedactionHash, Payload: PayloadRequest},
+					{Path: "$.instances[*]['email']", Action: RedactionMask},
+				},
+				MaxPayloadSize: 1024,
+			},
+		},
+		{
+			name:    "invalid sampling rate",
+			policy:  &Policy{Sampling: &Sampling{Rate: rate(1.5)}},
+			wantErr: "sampling rate 1.5 must be between 0 and 1",
+		},
+		{
+			name:    "invalid status code",
+			policy:  &Policy{Sampling: &Sampling{StatusCodeRules: []StatusCodeRule{{StatusCode: "error", Rate: 1}}}},
+			wantErr: `invalid status code "error", it must be a status code like 429 or a class of status codes like 5xx`,
+		},
+		{
+			name:    "invalid status code rate",
+			policy:  &Policy{Sampling: &Sampling{StatusCodeRules: []StatusCodeRule{{StatusCode: "5xx", Rate: -1}}}},
+			wantErr: "sampling rate -1 of status code 5xx must be between 0 and 1",
+		},
+		{
+			name:    "invalid redaction action",
+			policy:  &Policy{Redactions: []Redaction{{Path: "$.phone", Action: "encrypt"}}},
+			wantErr: `invalid redaction action "encrypt" of $.phone, it must be either mask or hash`,
+		},
+		{
+			name:    "invalid redaction payload",
+			policy:  &Policy{Redactions: []Redaction{{Path: "$.phone", Action: RedactionMask, Payload: "headers"}}},
+			wantErr: `invalid redaction payload "headers" of $.phone, it must be either request or response`,
+		},
+		{
+			name:    "invalid redaction path",
+			policy:  &Policy{Redactions: []Redaction{{Path: "phone", Action: RedactionMask}}},
+			wantErr: `invalid path "phone", it must start with $`,
+		},
+		{
+			name:    "root redaction path",
+			policy:  &Policy{Redactions: []Redaction{{Path: "$", Action: RedactionMask}}},
+			wantErr: `invalid path "$", it must select a field`,
+		},
+		{
+			name:    "invalid array index",
+			policy:  &Policy{Redactions: []Redaction{{Path: "$.instances[first]", Action: RedactionMask}}},
+			wantErr: `invalid path "$.instances[first]", first is not an array index`,
+		},
+		{
+			name:    "negative max payload size",
+			policy:  &Policy{MaxPayloadSize: -1},
+			wantErr: "max payload size -1 must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicy_Sample(t *testing.T) {
+	policy := &Policy{
+		Sampling: &Sampling{
+			Rate: rate(0),
+			StatusCodeRules: []StatusCodeRule{
+				{StatusCode: "404", Rate: 0},
+				{StatusCode: "4xx", Rate: 1},
+				{StatusCode: "5xx", Rate: 1},
+			},
+		},
+	}
+
+	assert.False(t, policy.Sample(200))
+	assert.False(t, policy.Sample(404))
+	assert.True(t, policy.Sample(400))
+	assert.True(t, policy.Sample(503))
+
+	// gRPC status codes only match the rules of a single digit
+	assert.False(t, policy.Sample(3))
+
+	assert.True(t, (&Policy{Sampling: &Sampling{}}).Sample(200))
+	assert.True(t, (*Policy)(nil).Sample(200))
+
+	sampled := 0
+	halfPolicy := &Policy{Sampling: &Sampling{Rate: rate(0.5)}}
+	for i := 0; i < 1000; i++ {
+		if halfPolicy.Sample(200) {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 500, sampled, 100)
+}
+
+func TestPolicy_Redact(t *testing.T) {
+	tests := []struct {
+		name         string
+		redactions   []Redaction
+		payload      Payload
+		body         string
+		wantBody     string
+		wantRedacted bool
+	}{
+		{
+			name:         "mask nested field",
+			redactions:   []Redaction{{Path: "$.customer.phone", Action: RedactionMask}},
+			payload:      PayloadRequest,
+			body:         `{"customer": {"phone": "+6281234567", "age": 30}}`,
+			wantBody:     `{"customer":{"age":30,"phone":"[REDACTED]"}}`,
+			wantRedacted: true,
+		},
+		{
+			name:         "hash fields of array elements",
+			redactions:   []Redaction{{Path: "$.instances[*].phone", Action: RedactionHash}},
+			payload:      PayloadRequest,
+			body:         `{"instances": [{"phone": "+6281234567"}, {"phone": 6281234567}, {"name": "a"}]}`,
+			wantBody:     `{"instances":[{"phone":"` + hash("+6281234567") + `"},{"phone":"` + hash(json.Number("6281234567")) + `"},{"name":"a"}]}`,
+			wantRedacted: true,
+		},
+		{
+			name:         "mask array element and wildcard field",
+			redactions:   []Redaction{{Path: "$.instances[1]", Action: RedactionMask}, {Path: "$.customer.*", Action: RedactionMask}},
+			payload:      PayloadResponse,
+			body:         `{"instances": [1, 2, 3], "customer": {"name": "a", "email": "b"}}`,
+			wantBody:     `{"customer":{"email":"[REDACTED]","name":"[REDACTED]"},"instances":[1,"[REDACTED]",3]}`,
+			wantRedacted: true,
+		},
+		{
+			name:         "redaction of the other payload",
+			redactions:   []Redaction{{Path: "$.phone", Action: RedactionMask, Payload: PayloadResponse}},
+			payload:      PayloadRequest,
+			body:         `{"phone": "+6281234567"}`,
+			wantBody:     `{"phone": "+6281234567"}`,
+			wantRedacted: false,
+		},
+		{
+			name:         "no matching field",
+			redactions:   []Redaction{{Path: "$.phone", Action: RedactionMask}},
+			payload:      PayloadRequest,
+			body:         `{"instances": [[1, 2, 3]]}`,
+			wantBody:     `{"instances": [[1, 2, 3]]}`,
+			wantRedacted: false,
+		},
+		{
+			name:         "body isn't json",
+			redactions:   []Redaction{{Path: "$.phone", Action: RedactionMask}},
+			payload:      PayloadRequest,
+			body:         `phone=+6281234567`,
+			wantBody:     ``,
+			wantRedacted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &Policy{Redactions: tt.redactions}
+			require.NoError(t, policy.Validate())
+
+			body, redacted := policy.Redact([]byte(tt.body), tt.payload)
+			assert.Equal(t, tt.wantRedacted, redacted)
+			assert.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func TestPolicy_Truncate(t *testing.T) {
+	policy := &Policy{MaxPayloadSize: 4}
+
+	body, truncated := policy.Truncate([]byte("abcdef"))
+	assert.True(t, truncated)
+	assert.Equal(t, []byte("abcd"), body)
+
+	body, truncated = policy.Truncate([]byte("abcd"))
+	assert.False(t, truncated)
+	assert.Equal(t, []byte("abcd"), body)
+
+	body, truncated = (&Policy{}).Truncate([]byte("abcdef"))
+	assert.False(t, truncated)
+	assert.Equal(t, []byte("abcdef"), body)
+}
+
+func TestAppendToURLAndParseURL(t *testing.T) {
+	policy := &Policy{
+		Sampling:       &Sampling{Rate: rate(0.1), StatusCodeRules: []StatusCodeRule{{StatusCode: "5xx", Rate: 1}}},
+		Redactions:     []Redaction{{Path: "$.customer.phone", Action: RedactionHash}},
+		MaxPayloadSize: 1024,
+	}
+
+	for _, logURL := range []string{
+		"kafka:broker-1:9092,broker-2:9092",
+		"newrelic:https://log-api.newrelic.com/log/v1?Api-Key=my-key",
+		"s3:my-bucket/logs?format=parquet",
+	} {
+		t.Run(logURL, func(t *testing.T) {
+			withPolicy, err := AppendToURL(logURL, policy)
+			require.NoError(t, err)
+			assert.NotEqual(t, logURL, withPolicy)
+
+			sinkURL, parsed, err := ParseURL(withPolicy)
+			require.NoError(t, err)
+			assert.Equal(t, logURL, sinkURL)
+			assert.Equal(t, policy.Sampling, parsed.Sampling)
+			assert.Equal(t, policy.MaxPayloadSize, parsed.MaxPayloadSize)
+			require.Len(t, parsed.Redactions, 1)
+			assert.Equal(t, "$.customer.phone", parsed.Redactions[0].Path)
+
+			// the redaction paths are compiled
+			body, redacted := parsed.Redact([]byte(`{"customer":{"phone":"1"}}`), PayloadRequest)
+			assert.True(t, redacted)
+			assert.Equal(t, `{"customer":{"phone":"`+hash("1")+`"}}`, string(body))
+		})
+	}
+
+	withoutPolicy, err := AppendToURL("kafka:broker:9092", &Policy{})
+	require.NoError(t, err)
+	assert.Equal(t, "kafka:broker:9092", withoutPolicy)
+
+	sinkURL, parsed, err := ParseURL("kafka:broker:9092")
+	require.NoError(t, err)
+	assert.Equal(t, "kafka:broker:9092", sinkURL)
+	assert.Nil(t, parsed)
+
+	_, _, err = ParseURL("kafka:broker:9092" + URLFragmentPrefix + "!")
+	assert.ErrorContains(t, err, "malformed log policy")
+}
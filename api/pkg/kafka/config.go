@@ -6,6 +6,8 @@ type SerializationFormat string
 const (
 	Protobuf SerializationFormat = "protobuf"
 	JSON     SerializationFormat = "json"
+	// Avro flattens the tables of the prediction logs into a typed Avro record, it requires a schema registry
+	Avro SerializationFormat = "avro"
 )
 
 // Kafka configuration for publishing prediction log
@@ -16,4 +18,8 @@ type Config struct {
 	MaxMessageSizeBytes int                 `envconfig:"KAFKA_MAX_MESSAGE_SIZE_BYTES" default:"1048588"`
 	ConnectTimeoutMS    int                 `envconfig:"KAFKA_CONNECT_TIMEOUT_MS" default:"1000"`
 	SerializationFmt    SerializationFormat `envconfig:"KAFKA_SERIALIZATION_FORMAT" default:"protobuf"`
+
+	// SchemaRegistryURL is the URL of the schema registry the protobuf and avro schemas are registered in, the values are then
+	// serialized in the Confluent wire format
+	SchemaRegistryURL string `envconfig:"KAFKA_SCHEMA_REGISTRY_URL"`
 }
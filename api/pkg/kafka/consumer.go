@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	kafkalib "github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.uber.org/zap"
+)
+
+// pollTimeout bounds how long the consumer waits for a message before checking whether it has been stopped
+const pollTimeout = time.Second
+
+// MessageHandler handles a message consumed from a topic
+type MessageHandler func(topic string, value []byte) error
+
+// Consumer responsible to consume messages of a consumer group from kafka
+type Consumer struct {
+	consumer consumer
+	logger   *zap.Logger
+}
+
+type consumer interface {
+	SubscribeTopics([]string, kafkalib.RebalanceCb) error
+	ReadMessage(time.Duration) (*kafkalib.Message, error)
+	Close() error
+}
+
+// NewConsumer creates Consumer instance
+func NewConsumer(brokers string, consumerGroup string, logger *zap.Logger) (*Consumer, error) {
+	consumer, err := kafkalib.NewConsumer(&kafkalib.ConfigMap{
+		"bootstrap.servers": brokers,
+		"group.id":          consumerGroup,
+		"auto.offset.reset": "latest",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Consumer{
+		consumer: consumer,
+		logger:   logger,
+	}, nil
+}
+
+// Run subscribes to the topics, which may be regular expressions starting with ^, and handles their messages until the context is done.
+// Messages which fail to be handled are logged and skipped.
+func (kc *Consumer) Run(ctx context.Context, topics []string, handler MessageHandler) error {
+	defer kc.consumer.Close()
+
+	if err := kc.consumer.SubscribeTopics(topics, nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		msg, err := kc.consumer.ReadMessage(pollTimeout)
+		if err != nil {
+			if kafkaErr, ok := err.(kafkalib.Error); ok && kafkaErr.Code() == kafkalib.ErrTimedOut {
+				continue
+			}
+			kc.logger.Warn("failed to consume message", zap.Error(err))
+			continue
+		}
+
+		if err := handler(*msg.TopicPartition.Topic, msg.Value); err != nil {
+			kc.logger.Warn("failed to handle message", zap.Error(err), zap.String("topic", *msg.TopicPartition.Topic), zap.Any("offset", msg.TopicPartition.Offset))
+		}
+	}
+}
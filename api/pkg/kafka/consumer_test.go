@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// mockKafkaConsumer implements the consumer
+type mockKafkaConsumer struct {
+	mock.Mock
+}
+
+func (mc *mockKafkaConsumer) SubscribeTopics(topics []string, rebalanceCb kafka.RebalanceCb) error {
+	ret := mc.Called(topics, rebalanceCb)
+	return ret.Error(0)
+}
+
+func (mc *mockKafkaConsumer) ReadMessage(timeout time.Duration) (*kafka.Message, error) {
+	ret := mc.Called(timeout)
+	var r0 *kafka.Message
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*kafka.Message)
+	}
+	return r0, ret.Error(1)
+}
+
+func (mc *mockKafkaConsumer) Close() error {
+	ret := mc.Called()
+	return ret.Error(0)
+}
+
+func TestConsumer_Run(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	topics := []string{"^caraml-.*-prediction-log$", "labels"}
+	predictionLogTopic := "caraml-project-model-prediction-log"
+	labelTopic := "labels"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockConsumer := &mockKafkaConsumer{}
+	mockConsumer.On("SubscribeTopics", topics, mock.Anything).Return(nil)
+	mockConsumer.On("ReadMessage", pollTimeout).Return(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &predictionLogTopic},
+		Value:          []byte("prediction"),
+	}, nil).Once()
+	mockConsumer.On("ReadMessage", pollTimeout).Return(nil, kafka.NewError(kafka.ErrTimedOut, "timed out", false)).Once()
+	mockConsumer.On("ReadMessage", pollTimeout).Return(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &labelTopic},
+		Value:          []byte("invalid"),
+	}, nil).Once()
+	mockConsumer.On("ReadMessage", pollTimeout).Return(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &labelTopic},
+		Value:          []byte("label"),
+	}, nil).Once()
+	mockConsumer.On("Close").Return(nil)
+
+	var handled []string
+	consumer := &Consumer{consumer: mockConsumer, logger: logger}
+	err := consumer.Run(ctx, topics, func(topic string, value []byte) error {
+		if string(value) == "invalid" {
+			return fmt.Errorf("invalid message")
+		}
+		handled = append(handled, topic+"/"+string(value))
+		if len(handled) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{predictionLogTopic + "/prediction", labelTopic + "/label"}, handled)
+	mockConsumer.AssertExpectations(t)
+}
+
+func TestConsumer_Run_SubscribeError(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	mockConsumer := &mockKafkaConsumer{}
+	mockConsumer.On("SubscribeTopics", []string{"labels"}, mock.Anything).Return(fmt.Errorf("unknown topic"))
+	mockConsumer.On("Close").Return(nil)
+
+	consumer := &Consumer{consumer: mockConsumer, logger: logger}
+	err := consumer.Run(context.Background(), []string{"labels"}, func(topic string, value []byte) error {
+		return nil
+	})
+	assert.EqualError(t, err, "unknown topic")
+	mockConsumer.AssertExpectations(t)
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/caraml-dev/merlin/pkg/kafka/schemaregistry"
 )
 
 // Producer responsible to produce log to kafka
@@ -18,6 +20,9 @@ type Producer struct {
 	producer            producer
 	serializationFormat SerializationFormat
 	logger              *zap.Logger
+
+	// serializer serializes the values in the Confluent wire format if a schema registry is configured
+	serializer schemaregistry.Serializer
 }
 
 type producer interface {
@@ -28,6 +33,11 @@ type producer interface {
 
 // NewProducer creates Producer instance
 func NewProducer(cfg Config, logger *zap.Logger) (*Producer, error) {
+	serializer, err := newSerializer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	producer, err := kafkalib.NewProducer(&kafkalib.ConfigMap{
 		"bootstrap.servers": cfg.Brokers,
 		"message.max.bytes": cfg.MaxMessageSizeBytes,
@@ -50,12 +60,28 @@ func NewProducer(cfg Config, logger *zap.Logger) (*Producer, error) {
 		producer:            producer,
 		serializationFormat: cfg.SerializationFmt,
 		logger:              logger,
+		serializer:          serializer,
 	}
 
 	go kafkaProducer.handleMessageDelivery()
 	return kafkaProducer, nil
 }
 
+func newSerializer(cfg Config) (schemaregistry.Serializer, error) {
+	if cfg.SchemaRegistryURL == "" {
+		if cfg.SerializationFmt == Avro {
+			return nil, fmt.Errorf("%s serialization requires a schema registry", Avro)
+		}
+		return nil, nil
+	}
+
+	client, err := schemaregistry.NewClient(cfg.SchemaRegistryURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating schema registry client")
+	}
+	return schemaregistry.NewSerializer(client, schemaregistry.Format(cfg.SerializationFmt))
+}
+
 // Close a producer instance
 func (kp *Producer) Close() {
 	kp.producer.Close()
@@ -75,7 +101,13 @@ func (kp *Producer) Produce(ctx context.Context, value interface{}) error {
 		if !validProtoType {
 			return fmt.Errorf("can't serialize if type is not proto.Message")
 		}
-		val, err := proto.Marshal(protoVal)
+		val, err := schemaregistry.SerializeProto(kp.serializer, kp.topic, protoVal)
+		if err != nil {
+			return err
+		}
+		msgValue = val
+	} else if kp.serializationFormat == Avro {
+		val, err := kp.serializer.Serialize(kp.topic, value)
 		if err != nil {
 			return err
 		}
@@ -106,6 +138,10 @@ func (k *Producer) handleMessageDelivery() {
 }
 
 func (k *Producer) deserializeMessageValue(value []byte) any {
+	if k.serializer != nil {
+		// the value is in the Confluent wire format, its schema would have to be fetched from the registry
+		return value
+	}
 	if k.serializationFormat == JSON {
 		var output map[string]any
 		err := json.Unmarshal(value, &output)
@@ -2,14 +2,20 @@ package kafka
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"strings"
 	"testing"
 
 	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/caraml-dev/merlin/pkg/kafka/schemaregistry"
 )
 
 // mockKafkaProducer implements the kafkaProducer
@@ -142,3 +148,62 @@ func TestProducer_Produce(t *testing.T) {
 		})
 	}
 }
+
+func TestProducer_ProduceWithSchemaRegistry(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	topic := "topic"
+
+	for _, format := range []SerializationFormat{Protobuf, Avro} {
+		t.Run(string(format), func(t *testing.T) {
+			client, err := schemaregistry.NewClient("mock://")
+			require.NoError(t, err)
+			serializer, err := schemaregistry.NewSerializer(client, schemaregistry.Format(format))
+			require.NoError(t, err)
+
+			var produced *kafka.Message
+			mockProducer := &mockKafkaProducer{}
+			mockProducer.On("Produce", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				produced = args.Get(0).(*kafka.Message)
+			}).Return(nil)
+
+			kp := &Producer{
+				topic:               topic,
+				producer:            mockProducer,
+				serializationFormat: format,
+				logger:              logger,
+				serializer:          serializer,
+			}
+			err = kp.Produce(context.Background(), &upiv1.PredictionLog{
+				PredictionId: "predictionID",
+				ProjectName:  "project",
+				ModelName:    "model",
+				ModelVersion: "version",
+				TargetName:   "target",
+			})
+			require.NoError(t, err)
+
+			metadata, err := client.GetLatestSchemaMetadata(topic + "-value")
+			require.NoError(t, err)
+			assert.Equal(t, strings.ToUpper(string(format)), metadata.SchemaType)
+			require.NotNil(t, produced)
+			assert.Equal(t, byte(0), produced.Value[0])
+			assert.Equal(t, uint32(metadata.ID), binary.BigEndian.Uint32(produced.Value[1:5]))
+		})
+	}
+}
+
+func TestNewSerializer(t *testing.T) {
+	serializer, err := newSerializer(Config{SerializationFmt: Protobuf})
+	assert.NoError(t, err)
+	assert.Nil(t, serializer)
+
+	_, err = newSerializer(Config{SerializationFmt: Avro})
+	assert.EqualError(t, err, "avro serialization requires a schema registry")
+
+	_, err = newSerializer(Config{SerializationFmt: JSON, SchemaRegistryURL: "mock://"})
+	assert.EqualError(t, err, "serialization format json is not supported by the schema registry")
+
+	serializer, err = newSerializer(Config{SerializationFmt: Avro, SchemaRegistryURL: "mock://"})
+	assert.NoError(t, err)
+	assert.NotNil(t, serializer)
+}
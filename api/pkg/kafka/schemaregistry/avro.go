@@ -0,0 +1,306 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry/serde"
+	"github.com/linkedin/goavro/v2"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const rowIDField = "row_id"
+
+var invalidAvroNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// avroTypes maps the column types of the tables of the prediction logs, see converter.TableToStruct, to Avro types
+var avroTypes = map[string]string{
+	"INTEGER": "long",
+	"FLOAT":   "double",
+	"STRING":  "string",
+}
+
+// predictionLogTable is a table of the prediction log flattened into an array of typed rows
+type predictionLogTable struct {
+	field    string
+	rowName  string
+	getTable func(log *upiv1.PredictionLog) *structpb.Struct
+}
+
+var predictionLogTables = []predictionLogTable{
+	{
+		field:   "features",
+		rowName: "FeaturesRow",
+		getTable: func(log *upiv1.PredictionLog) *structpb.Struct {
+			return log.GetInput().GetFeaturesTable()
+		},
+	},
+	{
+		field:   "entities",
+		rowName: "EntitiesRow",
+		getTable: func(log *upiv1.PredictionLog) *structpb.Struct {
+			return log.GetInput().GetEntitiesTable()
+		},
+	},
+	{
+		field:   "raw_features",
+		rowName: "RawFeaturesRow",
+		getTable: func(log *upiv1.PredictionLog) *structpb.Struct {
+			return log.GetInput().GetRawFeatures()
+		},
+	},
+	{
+		field:   "prediction_results",
+		rowName: "PredictionResultsRow",
+		getTable: func(log *upiv1.PredictionLog) *structpb.Struct {
+			return log.GetOutput().GetPredictionResultsTable()
+		},
+	},
+}
+
+type avroRecord struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Fields    []avroField `json:"fields"`
+}
+
+type avroArray struct {
+	Type  string     `json:"type"`
+	Items avroRecord `json:"items"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroColumn struct {
+	name     string
+	avroType string
+}
+
+// flatTable is a table of the prediction log, whose columns are valid Avro names
+type flatTable struct {
+	columns []avroColumn
+	rowIDs  []string
+	data    [][]interface{}
+}
+
+// PredictionLogAvroSerializer serializes the prediction logs into an Avro record whose tables are flattened into arrays of typed rows.
+// The schema of the record is derived from the columns of the tables, so that each model version registers its own version of the
+// <topic>-value subject.
+type PredictionLogAvroSerializer struct {
+	serde.BaseSerializer
+
+	mu     sync.Mutex
+	codecs map[string]*goavro.Codec
+}
+
+// NewPredictionLogAvroSerializer creates a serializer of prediction logs into flattened Avro records
+func NewPredictionLogAvroSerializer(client schemaregistry.Client) (*PredictionLogAvroSerializer, error) {
+	s := &PredictionLogAvroSerializer{
+		codecs: map[string]*goavro.Codec{},
+	}
+	if err := s.ConfigureSerializer(client, serde.ValueSerde, serde.NewSerializerConfig()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Serialize implements Serializer, the message must be a prediction log
+func (s *PredictionLogAvroSerializer) Serialize(topic string, msg interface{}) ([]byte, error) {
+	predictionLog, ok := msg.(*upiv1.PredictionLog)
+	if !ok {
+		return nil, fmt.Errorf("avro serialization target must be a prediction log, got %T", msg)
+	}
+
+	tables := make([]*flatTable, len(predictionLogTables))
+	for i, table := range predictionLogTables {
+		flat, err := flattenTable(table.getTable(predictionLog))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s table: %w", table.field, err)
+		}
+		tables[i] = flat
+	}
+
+	schema, err := predictionLogSchema(predictionLog, tables)
+	if err != nil {
+		return nil, err
+	}
+	codec, err := s.codec(schema)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.GetID(topic, msg, schemaregistry.SchemaInfo{Schema: schema, SchemaType: "AVRO"})
+	if err != nil {
+		return nil, err
+	}
+
+	native := map[string]interface{}{
+		"prediction_id":     predictionLog.GetPredictionId(),
+		"target_name":       predictionLog.GetTargetName(),
+		"project_name":      predictionLog.GetProjectName(),
+		"model_name":        predictionLog.GetModelName(),
+		"model_version":     predictionLog.GetModelVersion(),
+		"request_timestamp": predictionLog.GetRequestTimestamp().AsTime(),
+		"status":            int64(predictionLog.GetOutput().GetStatus()),
+		"message":           predictionLog.GetOutput().GetMessage(),
+	}
+	for i, table := range predictionLogTables {
+		native[table.field] = tables[i].nativeRows()
+	}
+
+	payload, err := codec.BinaryFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize prediction log to avro: %w", err)
+	}
+	return s.WriteBytes(id, payload)
+}
+
+func (s *PredictionLogAvroSerializer) codec(schema string) (*goavro.Codec, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if codec, ok := s.codecs[schema]; ok {
+		return codec, nil
+	}
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %w", err)
+	}
+	s.codecs[schema] = codec
+	return codec, nil
+}
+
+func predictionLogSchema(predictionLog *upiv1.PredictionLog, tables []*flatTable) (string, error) {
+	fields := []avroField{
+		{Name: "prediction_id", Type: "string"},
+		{Name: "target_name", Type: "string"},
+		{Name: "project_name", Type: "string"},
+		{Name: "model_name", Type: "string"},
+		{Name: "model_version", Type: "string"},
+		{Name: "request_timestamp", Type: map[string]string{"type": "long", "logicalType": "timestamp-micros"}},
+		{Name: "status", Type: "long"},
+		{Name: "message", Type: "string"},
+	}
+	for i, table := range predictionLogTables {
+		rowFields := []avroField{{Name: rowIDField, Type: "string"}}
+		for _, column := range tables[i].columns {
+			rowFields = append(rowFields, avroField{
+				Name:    column.name,
+				Type:    []string{"null", column.avroType},
+				Default: json.RawMessage("null"),
+			})
+		}
+		fields = append(fields, avroField{
+			Name: table.field,
+			Type: avroArray{
+				Type:  "array",
+				Items: avroRecord{Type: "record", Name: table.rowName, Fields: rowFields},
+			},
+		})
+	}
+
+	record := avroRecord{
+		Type:      "record",
+		Name:      "PredictionLog",
+		Namespace: avroNamespace("merlin", predictionLog.GetProjectName(), predictionLog.GetModelName()),
+		Fields:    fields,
+	}
+	schema, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	return string(schema), nil
+}
+
+// flattenTable parses a table of the prediction log, see converter.TableToStruct, an absent table has neither columns nor rows
+func flattenTable(table *structpb.Struct) (*flatTable, error) {
+	flat := &flatTable{}
+	if table == nil {
+		return flat, nil
+	}
+
+	fields := table.GetFields()
+	columns := fields["columns"].GetListValue().GetValues()
+	columnTypes := fields["column_types"].GetListValue().GetValues()
+	if len(columns) != len(columnTypes) {
+		return nil, fmt.Errorf("%d columns but %d column types", len(columns), len(columnTypes))
+	}
+
+	names := map[string]bool{rowIDField: true}
+	for i, column := range columns {
+		name := avroName(column.GetStringValue())
+		if names[name] {
+			return nil, fmt.Errorf("column %s is a duplicate once converted into avro field %s", column.GetStringValue(), name)
+		}
+		names[name] = true
+
+		avroType, ok := avroTypes[columnTypes[i].GetStringValue()]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s of column %s", columnTypes[i].GetStringValue(), column.GetStringValue())
+		}
+		flat.columns = append(flat.columns, avroColumn{name: name, avroType: avroType})
+	}
+
+	for _, rowID := range fields["row_ids"].GetListValue().GetValues() {
+		flat.rowIDs = append(flat.rowIDs, rowID.GetStringValue())
+	}
+	for _, row := range fields["data"].GetListValue().GetValues() {
+		values := row.GetListValue().AsSlice()
+		if len(values) != len(flat.columns) {
+			return nil, fmt.Errorf("row has %d values but the table has %d columns", len(values), len(flat.columns))
+		}
+		flat.data = append(flat.data, values)
+	}
+	return flat, nil
+}
+
+func (t *flatTable) nativeRows() []interface{} {
+	rows := make([]interface{}, len(t.data))
+	for i, values := range t.data {
+		row := map[string]interface{}{rowIDField: ""}
+		if i < len(t.rowIDs) {
+			row[rowIDField] = t.rowIDs[i]
+		}
+		for j, column := range t.columns {
+			row[column.name] = nativeValue(values[j], column.avroType)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+func nativeValue(value interface{}, avroType string) interface{} {
+	if value == nil {
+		return nil
+	}
+	if number, ok := value.(float64); ok && avroType == "long" {
+		return goavro.Union(avroType, int64(number))
+	}
+	return goavro.Union(avroType, value)
+}
+
+// avroName converts the name into a valid Avro name, which only contains letters, digits and underscores and doesn't start with a digit
+func avroName(name string) string {
+	name = invalidAvroNameChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func avroNamespace(parts ...string) string {
+	names := make([]string, len(parts))
+	for i, part := range parts {
+		names[i] = avroName(part)
+	}
+	return strings.Join(names, ".")
+}
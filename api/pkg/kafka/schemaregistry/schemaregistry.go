@@ -0,0 +1,87 @@
+// Package schemaregistry serializes the messages produced to Kafka in the Confluent wire format,
+// registering their schemas in a schema registry under the <topic>-key and <topic>-value subjects
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry/serde"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry/serde/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format of the messages serialized with the schema registry
+type Format string
+
+const (
+	// Protobuf serializes the messages as is, their schema is the proto file of the message
+	Protobuf Format = "protobuf"
+	// Avro flattens the tables of the prediction logs into a typed Avro record
+	Avro Format = "avro"
+)
+
+// Serializer serializes the messages produced to a topic
+type Serializer interface {
+	Serialize(topic string, msg interface{}) ([]byte, error)
+}
+
+// NewClient creates a client of the schema registry, a mock:// URL creates an in-memory registry
+func NewClient(url string) (schemaregistry.Client, error) {
+	if url == "" {
+		return nil, fmt.Errorf("schema registry url is not set")
+	}
+	return schemaregistry.NewClient(schemaregistry.NewConfig(url))
+}
+
+// NewProtobufSerializer creates a serializer of protobuf messages for either the keys or the values of the topics
+func NewProtobufSerializer(client schemaregistry.Client, serdeType serde.Type) (Serializer, error) {
+	return protobuf.NewSerializer(client, serdeType, protobuf.NewSerializerConfig())
+}
+
+// NewSerializer creates a serializer of the values of the topics in the given format
+func NewSerializer(client schemaregistry.Client, format Format) (Serializer, error) {
+	switch format {
+	case Protobuf:
+		return NewProtobufSerializer(client, serde.ValueSerde)
+	case Avro:
+		return NewPredictionLogAvroSerializer(client)
+	default:
+		return nil, fmt.Errorf("serialization format %s is not supported by the schema registry", format)
+	}
+}
+
+// SerializeProto serializes the protobuf message with the serializer, or as is if there's none
+func SerializeProto(serializer Serializer, topic string, msg proto.Message) ([]byte, error) {
+	if serializer == nil {
+		return proto.Marshal(msg)
+	}
+	return serializer.Serialize(topic, msg)
+}
+
+// ProtobufPayload returns the protobuf message of a payload serialized in the Confluent wire format, i.e. without its magic byte,
+// schema id and message indexes. A payload which isn't in the wire format is returned as is, as a protobuf message can't start with 0.
+func ProtobufPayload(payload []byte) ([]byte, error) {
+	if len(payload) == 0 || payload[0] != 0 {
+		return payload, nil
+	}
+	if len(payload) < 6 {
+		return nil, fmt.Errorf("payload of %d bytes is too short for the wire format", len(payload))
+	}
+
+	message := payload[5:]
+	count, n := binary.Varint(message)
+	if n <= 0 || count < 0 {
+		return nil, fmt.Errorf("invalid message indexes")
+	}
+	message = message[n:]
+	// a count of 0 is the shorthand of the first message of the proto file
+	for i := int64(0); i < count; i++ {
+		if _, n = binary.Varint(message); n <= 0 {
+			return nil, fmt.Errorf("invalid message indexes")
+		}
+		message = message[n:]
+	}
+	return message, nil
+}
@@ -0,0 +1,217 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/caraml-dev/universal-prediction-interface/pkg/converter"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const topic = "merlin-project-model-prediction-log"
+
+func newTable(t *testing.T, columns []*upiv1.Column, rows ...*upiv1.Row) *structpb.Struct {
+	table, err := converter.TableToStruct(&upiv1.Table{Name: "table", Columns: columns, Rows: rows}, converter.TableSchemaV1)
+	require.NoError(t, err)
+	return table
+}
+
+func newPredictionLog(t *testing.T, modelVersion string, featureColumns []*upiv1.Column, featureRows ...*upiv1.Row) *upiv1.PredictionLog {
+	return &upiv1.PredictionLog{
+		PredictionId:     "prediction-1",
+		TargetName:       "probability",
+		ProjectName:      "project",
+		ModelName:        "model",
+		ModelVersion:     modelVersion,
+		RequestTimestamp: timestamppb.New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Input: &upiv1.ModelInput{
+			FeaturesTable: newTable(t, featureColumns, featureRows...),
+		},
+		Output: &upiv1.ModelOutput{
+			PredictionResultsTable: newTable(t,
+				[]*upiv1.Column{{Name: "probability", Type: upiv1.Type_TYPE_DOUBLE}},
+				&upiv1.Row{RowId: "1", Values: []*upiv1.Value{{DoubleValue: 0.9}}},
+			),
+			Status: 200,
+		},
+		TableSchemaVersion: converter.TableSchemaV1,
+	}
+}
+
+func newClient(t *testing.T) schemaregistry.Client {
+	client, err := NewClient("mock://")
+	require.NoError(t, err)
+	return client
+}
+
+func splitWireFormat(t *testing.T, payload []byte) (int, []byte) {
+	require.Greater(t, len(payload), 5)
+	require.Equal(t, byte(0), payload[0])
+	return int(binary.BigEndian.Uint32(payload[1:5])), payload[5:]
+}
+
+func TestProtobufSerializer(t *testing.T) {
+	client := newClient(t)
+	serializer, err := NewSerializer(client, Protobuf)
+	require.NoError(t, err)
+
+	predictionLog := newPredictionLog(t, "1",
+		[]*upiv1.Column{{Name: "age", Type: upiv1.Type_TYPE_INTEGER}},
+		&upiv1.Row{RowId: "1", Values: []*upiv1.Value{{IntegerValue: 30}}},
+	)
+	payload, err := serializer.Serialize(topic, predictionLog)
+	require.NoError(t, err)
+
+	id, message := splitWireFormat(t, payload)
+	metadata, err := client.GetLatestSchemaMetadata(topic + "-value")
+	require.NoError(t, err)
+	assert.Equal(t, metadata.ID, id)
+	assert.Equal(t, "PROTOBUF", metadata.SchemaType)
+	assert.Contains(t, metadata.Schema, "message PredictionLog")
+
+	// PredictionLog is the first message of its file, whose message indexes are encoded as a single 0
+	require.Equal(t, byte(0), message[0])
+	decoded := &upiv1.PredictionLog{}
+	require.NoError(t, proto.Unmarshal(message[1:], decoded))
+	assert.True(t, proto.Equal(predictionLog, decoded))
+}
+
+func TestPredictionLogAvroSerializer(t *testing.T) {
+	client := newClient(t)
+	serializer, err := NewSerializer(client, Avro)
+	require.NoError(t, err)
+
+	predictionLog := newPredictionLog(t, "1",
+		[]*upiv1.Column{
+			{Name: "age", Type: upiv1.Type_TYPE_INTEGER},
+			{Name: "income.usd", Type: upiv1.Type_TYPE_DOUBLE},
+			{Name: "city", Type: upiv1.Type_TYPE_STRING},
+		},
+		&upiv1.Row{RowId: "1", Values: []*upiv1.Value{{IntegerValue: 30}, {DoubleValue: 1000.5}, {StringValue: "Jakarta"}}},
+		&upiv1.Row{RowId: "2", Values: []*upiv1.Value{{IntegerValue: 40}, {IsNull: true}, {StringValue: "Bandung"}}},
+	)
+	payload, err := serializer.Serialize(topic, predictionLog)
+	require.NoError(t, err)
+
+	id, message := splitWireFormat(t, payload)
+	metadata, err := client.GetLatestSchemaMetadata(topic + "-value")
+	require.NoError(t, err)
+	assert.Equal(t, metadata.ID, id)
+	assert.Equal(t, "AVRO", metadata.SchemaType)
+
+	codec, err := goavro.NewCodec(metadata.Schema)
+	require.NoError(t, err)
+	native, _, err := codec.NativeFromBinary(message)
+	require.NoError(t, err)
+	record := native.(map[string]interface{})
+	assert.Equal(t, "prediction-1", record["prediction_id"])
+	assert.Equal(t, "1", record["model_version"])
+	assert.Equal(t, int64(200), record["status"])
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), record["request_timestamp"].(time.Time).UTC())
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"row_id":     "1",
+			"age":        goavro.Union("long", int64(30)),
+			"income_usd": goavro.Union("double", 1000.5),
+			"city":       goavro.Union("string", "Jakarta"),
+		},
+		map[string]interface{}{
+			"row_id":     "2",
+			"age":        goavro.Union("long", int64(40)),
+			"income_usd": nil,
+			"city":       goavro.Union("string", "Bandung"),
+		},
+	}, record["features"])
+	assert.Equal(t, []interface{}{}, record["entities"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"row_id": "1", "probability": goavro.Union("double", 0.9)},
+	}, record["prediction_results"])
+
+	// A model version with other features registers a new version of the subject
+	_, err = serializer.Serialize(topic, newPredictionLog(t, "2",
+		[]*upiv1.Column{{Name: "age", Type: upiv1.Type_TYPE_INTEGER}},
+		&upiv1.Row{RowId: "1", Values: []*upiv1.Value{{IntegerValue: 30}}},
+	))
+	require.NoError(t, err)
+	versions, err := client.GetAllVersions(topic + "-value")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+}
+
+func TestPredictionLogAvroSerializer_Invalid(t *testing.T) {
+	serializer, err := NewSerializer(newClient(t), Avro)
+	require.NoError(t, err)
+
+	_, err = serializer.Serialize(topic, &upiv1.PredictValuesRequest{})
+	assert.EqualError(t, err, "avro serialization target must be a prediction log, got *upiv1.PredictValuesRequest")
+
+	_, err = serializer.Serialize(topic, newPredictionLog(t, "1",
+		[]*upiv1.Column{{Name: "a-b", Type: upiv1.Type_TYPE_INTEGER}, {Name: "a.b", Type: upiv1.Type_TYPE_INTEGER}},
+	))
+	assert.EqualError(t, err, "invalid features table: column a.b is a duplicate once converted into avro field a_b")
+}
+
+func TestNewSerializer_UnsupportedFormat(t *testing.T) {
+	_, err := NewSerializer(newClient(t), Format("json"))
+	assert.EqualError(t, err, "serialization format json is not supported by the schema registry")
+}
+
+func TestProtobufPayload(t *testing.T) {
+	predictionLog := newPredictionLog(t, "1",
+		[]*upiv1.Column{{Name: "age", Type: upiv1.Type_TYPE_INTEGER}},
+		&upiv1.Row{RowId: "1", Values: []*upiv1.Value{{IntegerValue: 30}}},
+	)
+	raw, err := proto.Marshal(predictionLog)
+	require.NoError(t, err)
+
+	serializer, err := NewSerializer(newClient(t), Protobuf)
+	require.NoError(t, err)
+	serialized, err := serializer.Serialize(topic, predictionLog)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		wantErr string
+	}{
+		{
+			name:    "wire format with the first message",
+			payload: serialized,
+		},
+		{
+			name:    "wire format with message indexes",
+			payload: append([]byte{0, 0, 0, 0, 1, 4, 2, 0}, raw...),
+		},
+		{
+			name:    "raw protobuf",
+			payload: raw,
+		},
+		{
+			name:    "truncated wire format",
+			payload: []byte{0, 0, 0},
+			wantErr: "payload of 3 bytes is too short for the wire format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProtobufPayload(tt.payload)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			// the tables are maps, whose serialization isn't deterministic
+			decoded := &upiv1.PredictionLog{}
+			require.NoError(t, proto.Unmarshal(got, decoded))
+			assert.True(t, proto.Equal(predictionLog, decoded))
+		})
+	}
+}
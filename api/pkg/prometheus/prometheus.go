@@ -0,0 +1,70 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// ErrNoData is returned when the query doesn't return any sample
+var ErrNoData = errors.New("query returned no data")
+
+// Client for querying Prometheus server.
+type Client interface {
+	// QueryScalar evaluates an instant query at the given time and returns the value of its first sample
+	QueryScalar(ctx context.Context, query string, ts time.Time) (float64, error)
+}
+
+type client struct {
+	api promv1.API
+}
+
+// NewClient initializes new Prometheus client.
+func NewClient(address string) (Client, error) {
+	c, err := api.NewClient(api.Config{Address: address})
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{
+		api: promv1.NewAPI(c),
+	}, nil
+}
+
+func (c *client) QueryScalar(ctx context.Context, query string, ts time.Time) (float64, error) {
+	value, _, err := c.api.Query(ctx, query, ts)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := value.(type) {
+	case *model.Scalar:
+		return float64(v.Value), nil
+	case model.Vector:
+		if len(v) == 0 {
+			return 0, ErrNoData
+		}
+		return float64(v[0].Value), nil
+	default:
+		return 0, fmt.Errorf("unsupported query result type: %s", value.Type())
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_QueryScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     float64
+		wantErr  error
+	}{
+		{
+			name:     "vector",
+			response: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1435781451.781,"0.25"]}]}}`,
+			want:     0.25,
+		},
+		{
+			name:     "scalar",
+			response: `{"status":"success","data":{"resultType":"scalar","result":[1435781451.781,"2"]}}`,
+			want:     2,
+		},
+		{
+			name:     "empty vector",
+			response: `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			wantErr:  ErrNoData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v1/query", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, tt.response)
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL)
+			assert.NoError(t, err)
+
+			got, err := client.QueryScalar(context.Background(), "up", time.Now())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package batch
+
+import "time"
+
+// Options configures the standard transformer to replay a dataset of request payloads instead of serving requests
+type Options struct {
+	// InputPath is the JSON lines file of request payloads, either local path or gs://bucket/object
+	InputPath string `envconfig:"BATCH_INPUT_PATH"`
+	// OutputPath is the JSON lines file where the response of every request payload is written in the same order
+	OutputPath string `envconfig:"BATCH_OUTPUT_PATH"`
+	// Concurrency is the number of request payloads executed concurrently
+	Concurrency int `envconfig:"BATCH_CONCURRENCY" default:"4"`
+	// ModelReadyURL is polled until the model returns OK before the replay starts, e.g. when the model runs as sidecar
+	ModelReadyURL string `envconfig:"BATCH_MODEL_READY_URL"`
+	// ModelReadyTimeout is the maximum wait for the model to be ready
+	ModelReadyTimeout time.Duration `envconfig:"BATCH_MODEL_READY_TIMEOUT" default:"10m"`
+	// ModelShutdownURL is called after the replay so that the model sidecar exits and the job can complete
+	ModelShutdownURL string `envconfig:"BATCH_MODEL_SHUTDOWN_URL"`
+	// MaxFailedRatio is the maximum ratio of request payloads whose response is an error before the replay fails,
+	// so by default the replay fails if any request payload fails
+	MaxFailedRatio float64 `envconfig:"BATCH_MAX_FAILED_RATIO" default:"0"`
+}
+
+// Enabled returns true if the transformer runs in batch mode
+func (o Options) Enabled() bool {
+	return o.InputPath != ""
+}
@@ -0,0 +1,107 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/caraml-dev/merlin/pkg/transformer/executor"
+	"github.com/caraml-dev/merlin/pkg/transformer/types"
+)
+
+type httpModelPredictor struct {
+	predictURL string
+	httpClient *http.Client
+}
+
+// NewHTTPModelPredictor creates model predictor calling the model with HTTP_JSON protocol
+func NewHTTPModelPredictor(predictURL string, timeout time.Duration) executor.ModelPredictor {
+	return &httpModelPredictor{
+		predictURL: predictURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *httpModelPredictor) ModelPrediction(ctx context.Context, requestBody types.Payload, requestHeader map[string]string) (types.Payload, map[string]string, error) {
+	payload, err := requestBody.AsOutput()
+	if err != nil {
+		return nil, nil, err
+	}
+	body, ok := payload.OriginalValue().([]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown type of model request %T", payload.OriginalValue())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.predictURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range requestHeader {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("model returned status code %d: %s", res.StatusCode, string(respBody))
+	}
+
+	respHeaders := make(map[string]string, len(res.Header))
+	for k := range res.Header {
+		respHeaders[k] = res.Header.Get(k)
+	}
+	resp, err := types.BytePayload(respBody).AsInput()
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, respHeaders, nil
+}
+
+type upiModelPredictor struct {
+	client  upiv1.UniversalPredictionServiceClient
+	timeout time.Duration
+}
+
+// NewUPIModelPredictor creates model predictor calling the model with UPI_V1 protocol
+func NewUPIModelPredictor(hostPort string, timeout time.Duration) (executor.ModelPredictor, error) {
+	conn, err := grpc.Dial(hostPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &upiModelPredictor{
+		client:  upiv1.NewUniversalPredictionServiceClient(conn),
+		timeout: timeout,
+	}, nil
+}
+
+func (p *upiModelPredictor) ModelPrediction(ctx context.Context, requestBody types.Payload, requestHeader map[string]string) (types.Payload, map[string]string, error) {
+	request, ok := requestBody.OriginalValue().(*upiv1.PredictValuesRequest)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown type of model request %T", requestBody.OriginalValue())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	response, err := p.client.PredictValues(ctx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return (*types.UPIPredictionResponse)(response), requestHeader, nil
+}
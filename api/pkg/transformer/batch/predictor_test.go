@@ -0,0 +1,58 @@
+package batch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/pkg/transformer/types"
+)
+
+func TestHTTPModelPredictor_ModelPrediction(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		response   string
+		want       types.Payload
+		wantErr    string
+	}{
+		{
+			name:       "success",
+			statusCode: http.StatusOK,
+			response:   `{"predictions": [0.5]}`,
+			want:       types.JSONObject{"predictions": []interface{}{0.5}},
+		},
+		{
+			name:       "model error",
+			statusCode: http.StatusInternalServerError,
+			response:   `model failed`,
+			wantErr:    "model returned status code 500: model failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, `{"instances":[[1,2]]}`, string(body))
+				assert.Equal(t, "ID", r.Header.Get("Country-ID"))
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			predictor := NewHTTPModelPredictor(server.URL+"/v1/models/my-model-1:predict", time.Second)
+			got, _, err := predictor.ModelPrediction(context.Background(), types.JSONObject{"instances": []interface{}{[]interface{}{1, 2}}}, map[string]string{"Country-ID": "ID"})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
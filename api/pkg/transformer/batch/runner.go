@@ -0,0 +1,241 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/caraml-dev/merlin/pkg/transformer/executor"
+	"github.com/caraml-dev/merlin/pkg/transformer/types"
+)
+
+const (
+	// maxLineSize is the maximum size of a request payload in the input file
+	maxLineSize = 16 << 20
+
+	modelReadyPollInterval = 5 * time.Second
+)
+
+// Summary is the result of a replay
+type Summary struct {
+	// Total is the number of request payloads
+	Total int
+	// Failed is the number of request payloads whose response is an error
+	Failed int
+}
+
+// FailedRatio returns the ratio of request payloads whose response is an error
+func (s Summary) FailedRatio() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Total)
+}
+
+// Runner replays request payloads through the standard transformer
+type Runner struct {
+	transformer executor.Transformer
+	options     Options
+	logger      *zap.Logger
+}
+
+// NewRunner creates runner replaying request payloads through the given standard transformer
+func NewRunner(transformer executor.Transformer, options Options, logger *zap.Logger) *Runner {
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+	return &Runner{
+		transformer: transformer,
+		options:     options,
+		logger:      logger,
+	}
+}
+
+// Run replays the input file into the output file, waiting for the model before starting and shutting it down at the end.
+// It returns error if the ratio of failed request payloads exceeds MaxFailedRatio, once the output file is written.
+func (r *Runner) Run(ctx context.Context) (Summary, error) {
+	if r.options.ModelShutdownURL != "" {
+		defer r.shutdownModel()
+	}
+
+	if err := r.waitModelReady(ctx); err != nil {
+		return Summary{}, err
+	}
+
+	in, err := openReader(ctx, r.options.InputPath)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := openWriter(ctx, r.options.OutputPath)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary, err := r.Replay(ctx, in, out)
+	if err != nil {
+		out.Close() //nolint:errcheck
+		return summary, err
+	}
+	if err := out.Close(); err != nil {
+		return summary, err
+	}
+
+	if summary.FailedRatio() > r.options.MaxFailedRatio {
+		return summary, fmt.Errorf("%d of %d request payloads failed, exceeding the maximum failed ratio of %g", summary.Failed, summary.Total, r.options.MaxFailedRatio)
+	}
+	return summary, nil
+}
+
+// Replay executes every line of in as a request payload and writes its response as a line of out, in the same order.
+// At most Concurrency request payloads are executed at the same time.
+func (r *Runner) Replay(ctx context.Context, in io.Reader, out io.Writer) (Summary, error) {
+	// the reader stops queueing request payloads once the replay returns, e.g. when writing a response fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// every request payload gets a channel for its response, queued in the input order
+	pending := make(chan chan []byte, r.options.Concurrency)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(pending)
+
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			request := make([]byte, len(line))
+			copy(request, line)
+			response := make(chan []byte, 1)
+			select {
+			case pending <- response:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
+			go func() {
+				response <- r.execute(ctx, request)
+			}()
+		}
+		readErr <- scanner.Err()
+	}()
+
+	summary := Summary{}
+	writer := bufio.NewWriter(out)
+	for response := range pending {
+		line := <-response
+		summary.Total++
+		if isErrorResponse(line) {
+			summary.Failed++
+		}
+		if _, err := writer.Write(append(line, '\n')); err != nil {
+			return summary, err
+		}
+	}
+
+	if err := <-readErr; err != nil {
+		return summary, fmt.Errorf("failed reading request payloads: %w", err)
+	}
+	return summary, writer.Flush()
+}
+
+func (r *Runner) execute(ctx context.Context, request []byte) []byte {
+	var requestBody types.JSONObject
+	if err := json.Unmarshal(request, &requestBody); err != nil {
+		return errorResponse(fmt.Errorf("invalid request payload: %w", err))
+	}
+
+	resp := r.transformer.Execute(ctx, requestBody, map[string]string{})
+	response, err := marshalResponse(resp.Response)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return response
+}
+
+func marshalResponse(payload types.Payload) ([]byte, error) {
+	output, err := payload.AsOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := output.OriginalValue().(type) {
+	case []byte:
+		return value, nil
+	case *upiv1.PredictValuesResponse:
+		return protojson.Marshal(value)
+	case *upiv1.PredictValuesRequest:
+		return protojson.Marshal(value)
+	default:
+		return json.Marshal(value)
+	}
+}
+
+func errorResponse(err error) []byte {
+	response, _ := json.Marshal(types.JSONObject{"error": err.Error()})
+	return response
+}
+
+// isErrorResponse checks whether the response is the error response of the standard transformer, i.e. {"error": "..."}
+func isErrorResponse(response []byte) bool {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(response, &obj); err != nil {
+		return false
+	}
+	_, ok := obj["error"]
+	return ok && len(obj) == 1
+}
+
+func (r *Runner) waitModelReady(ctx context.Context) error {
+	if r.options.ModelReadyURL == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.options.ModelReadyTimeout)
+	defer cancel()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.options.ModelReadyURL, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err == nil {
+			res.Body.Close() //nolint:errcheck
+			if res.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		r.logger.Info("waiting for model to be ready", zap.String("url", r.options.ModelReadyURL))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("model is not ready after %s", r.options.ModelReadyTimeout)
+		case <-time.After(modelReadyPollInterval):
+		}
+	}
+}
+
+func (r *Runner) shutdownModel() {
+	res, err := http.Post(r.options.ModelShutdownURL, "application/json", nil) //nolint:noctx
+	if err != nil {
+		r.logger.Warn("failed shutting down model", zap.Error(err))
+		return
+	}
+	res.Body.Close() //nolint:errcheck
+}
@@ -0,0 +1,196 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+
+	"github.com/caraml-dev/merlin/pkg/protocol"
+	"github.com/caraml-dev/merlin/pkg/transformer/executor"
+	"github.com/caraml-dev/merlin/pkg/transformer/spec"
+	"github.com/caraml-dev/merlin/pkg/transformer/types"
+)
+
+const preprocessConfig = `
+transformerConfig:
+  preprocess:
+    inputs:
+      - tables:
+          - name: entity_table
+            baseTable:
+              fromJson:
+                jsonPath: $.entities[*]
+    outputs:
+      - jsonOutput:
+          jsonTemplate:
+            fields:
+              - fieldName: instances
+                fromTable:
+                  tableName: entity_table
+                  format: SPLIT
+`
+
+func newTransformer(t *testing.T, modelPredictor executor.ModelPredictor) executor.Transformer {
+	jsonBytes, err := yaml.YAMLToJSON([]byte(preprocessConfig))
+	require.NoError(t, err)
+	transformerConfig := &spec.StandardTransformerConfig{}
+	require.NoError(t, protojson.Unmarshal(jsonBytes, transformerConfig))
+
+	logger, _ := zap.NewDevelopment()
+	transformer, err := executor.NewStandardTransformerWithConfig(context.Background(), transformerConfig,
+		executor.WithLogger(logger),
+		executor.WithProtocol(protocol.HttpJson),
+		executor.WithModelPredictor(modelPredictor),
+	)
+	require.NoError(t, err)
+	return transformer
+}
+
+func TestRunner_Replay(t *testing.T) {
+	predictor := executor.NewMockModelPredictor(types.JSONObject{"predictions": []interface{}{1}}, nil, protocol.HttpJson)
+	echoPredictor := executor.NewMockModelPredictor(nil, nil, protocol.HttpJson)
+
+	tests := []struct {
+		name           string
+		modelPredictor executor.ModelPredictor
+		input          string
+		want           string
+		wantSummary    Summary
+	}{
+		{
+			name:           "model response",
+			modelPredictor: predictor,
+			input:          "{\"entities\": [{\"id\": 1}]}\n{\"entities\": [{\"id\": 2}]}\n",
+			want:           "{\"predictions\":[1]}\n{\"predictions\":[1]}\n",
+			wantSummary:    Summary{Total: 2},
+		},
+		{
+			name:           "preprocess output is kept in order",
+			modelPredictor: echoPredictor,
+			input:          "{\"entities\": [{\"id\": 1}]}\n\n{\"entities\": [{\"id\": 2}]}\n{\"entities\": [{\"id\": 3}]}",
+			want: "{\"instances\":{\"columns\":[\"id\"],\"data\":[[1]]}}\n" +
+				"{\"instances\":{\"columns\":[\"id\"],\"data\":[[2]]}}\n" +
+				"{\"instances\":{\"columns\":[\"id\"],\"data\":[[3]]}}\n",
+			wantSummary: Summary{Total: 3},
+		},
+		{
+			name:           "invalid request payload",
+			modelPredictor: predictor,
+			input:          "{\"entities\": [{\"id\": 1}]}\nnot json\n",
+			want:           "{\"predictions\":[1]}\n{\"error\":\"invalid request payload: invalid character 'o' in literal null (expecting 'u')\"}\n",
+			wantSummary:    Summary{Total: 2, Failed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(newTransformer(t, tt.modelPredictor), Options{Concurrency: 2}, zap.NewNop())
+
+			out := &bytes.Buffer{}
+			summary, err := runner.Replay(context.Background(), strings.NewReader(tt.input), out)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSummary, summary)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "input.jsonl")
+	outputPath := filepath.Join(dir, "output.jsonl")
+	require.NoError(t, os.WriteFile(inputPath, []byte("{\"entities\": [{\"id\": 1}]}\n"), 0o600))
+
+	runner := NewRunner(newTransformer(t, executor.NewMockModelPredictor(nil, nil, protocol.HttpJson)), Options{
+		InputPath:   inputPath,
+		OutputPath:  outputPath,
+		Concurrency: 1,
+	}, zap.NewNop())
+
+	summary, err := runner.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Total: 1}, summary)
+
+	output, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"instances\":{\"columns\":[\"id\"],\"data\":[[1]]}}\n", string(output))
+}
+
+func TestRunner_Run_MaxFailedRatio(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "input.jsonl")
+	outputPath := filepath.Join(dir, "output.jsonl")
+	require.NoError(t, os.WriteFile(inputPath, []byte("{\"entities\": [{\"id\": 1}]}\nnot json\n"), 0o600))
+
+	tests := []struct {
+		name           string
+		maxFailedRatio float64
+		wantErr        string
+	}{
+		{
+			name:           "any failure fails by default",
+			maxFailedRatio: 0,
+			wantErr:        "1 of 2 request payloads failed, exceeding the maximum failed ratio of 0",
+		},
+		{
+			name:           "failures up to the maximum ratio",
+			maxFailedRatio: 0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(newTransformer(t, executor.NewMockModelPredictor(nil, nil, protocol.HttpJson)), Options{
+				InputPath:      inputPath,
+				OutputPath:     outputPath,
+				Concurrency:    1,
+				MaxFailedRatio: tt.maxFailedRatio,
+			}, zap.NewNop())
+
+			summary, err := runner.Run(context.Background())
+			assert.Equal(t, Summary{Total: 2, Failed: 1}, summary)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// the responses are written even if the replay fails
+			output, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Len(t, strings.Split(strings.TrimSpace(string(output)), "\n"), 2)
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRunner_Replay_WriteError(t *testing.T) {
+	input := strings.Repeat("{\"entities\": [{\"id\": 1}]}\n", 10000)
+	runner := NewRunner(newTransformer(t, executor.NewMockModelPredictor(nil, nil, protocol.HttpJson)), Options{Concurrency: 1}, zap.NewNop())
+	before := runtime.NumGoroutine()
+
+	// the writer is buffered, so that the error is returned once the buffer is flushed
+	_, err := runner.Replay(context.Background(), strings.NewReader(input), failingWriter{})
+	assert.EqualError(t, err, "disk full")
+
+	// the reader stops instead of blocking on the responses which are never written
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
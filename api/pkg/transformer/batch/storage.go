@@ -0,0 +1,78 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+const gcsScheme = "gs://"
+
+// openReader opens a local file or a GCS object (gs://bucket/object) for reading
+func openReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(path, gcsScheme) {
+		return os.Open(path)
+	}
+
+	object, client, err := gcsObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := object.NewReader(ctx)
+	if err != nil {
+		client.Close() //nolint:errcheck
+		return nil, fmt.Errorf("failed reading %s: %w", path, err)
+	}
+	return &gcsCloser{ReadCloser: reader, client: client}, nil
+}
+
+// openWriter creates a local file or a GCS object (gs://bucket/object) for writing.
+// The GCS object is only written once the writer is closed successfully.
+func openWriter(ctx context.Context, path string) (io.WriteCloser, error) {
+	if !strings.HasPrefix(path, gcsScheme) {
+		return os.Create(path)
+	}
+
+	object, client, err := gcsObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsWriter{Writer: object.NewWriter(ctx), client: client}, nil
+}
+
+func gcsObject(ctx context.Context, path string) (*storage.ObjectHandle, *storage.Client, error) {
+	bucket, object, found := strings.Cut(strings.TrimPrefix(path, gcsScheme), "/")
+	if !found || bucket == "" || object == "" {
+		return nil, nil, fmt.Errorf("invalid gcs path %s", path)
+	}
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed creating gcs client: %w", err)
+	}
+	return client.Bucket(bucket).Object(object), client, nil
+}
+
+type gcsCloser struct {
+	io.ReadCloser
+	client *storage.Client
+}
+
+func (c *gcsCloser) Close() error {
+	defer c.client.Close() //nolint:errcheck
+	return c.ReadCloser.Close()
+}
+
+type gcsWriter struct {
+	*storage.Writer
+	client *storage.Client
+}
+
+func (w *gcsWriter) Close() error {
+	defer w.client.Close() //nolint:errcheck
+	return w.Writer.Close()
+}
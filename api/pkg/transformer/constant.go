@@ -27,6 +27,7 @@ const (
 	KafkaMaxMessageSizeBytes = "KAFKA_MAX_MESSAGE_SIZE_BYTES"
 	KafkaConnectTimeoutMS    = "KAFKA_CONNECT_TIMEOUT_MS"
 	KafkaSerialization       = "KAFKA_SERIALIZATION_FORMAT"
+	KafkaSchemaRegistryURL   = "KAFKA_SCHEMA_REGISTRY_URL"
 
 	PromNamespace = "merlin_transformer"
 )
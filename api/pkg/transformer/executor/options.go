@@ -3,6 +3,7 @@ package executor
 import (
 	prt "github.com/caraml-dev/merlin/pkg/protocol"
 	"github.com/caraml-dev/merlin/pkg/transformer/feast"
+	"github.com/caraml-dev/merlin/pkg/transformer/spec"
 	"go.uber.org/zap"
 )
 
@@ -42,3 +43,10 @@ func WithProtocol(protocol prt.Protocol) TransformerOptions {
 		cfg.protocol = protocol
 	}
 }
+
+// WithFeatureTableMetadata function to update/set the feature table metadata used to initialize feast clients
+func WithFeatureTableMetadata(featureTableMetadata []*spec.FeatureTableMetadata) TransformerOptions {
+	return func(cfg *transformerExecutorConfig) {
+		cfg.featureTableMetadata = featureTableMetadata
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+const (
+	// SignatureHeader contains the hex-encoded HMAC-SHA256 of the request body, keyed by the webhook secret
+	SignatureHeader = "X-Merlin-Signature"
+
+	contentType = "application/cloudevents+json"
+)
+
+// Notifier sends events to the webhooks subscribed to them
+type Notifier interface {
+	// Notify sends the event to all webhooks of the project subscribing to the event type.
+	// Delivery happens in the background, so Notify never blocks the caller on the webhook receivers.
+	Notify(ctx context.Context, projectID models.ID, eventType models.WebhookEventType, subject string, data interface{})
+	// Shutdown stops accepting events and waits until the queued deliveries are done or ctx is done.
+	// The queued deliveries are attempted once more without waiting for their retry backoff.
+	Shutdown(ctx context.Context) error
+}
+
+type pendingDelivery struct {
+	webhook *models.Webhook
+	event   *models.WebhookEvent
+	payload []byte
+}
+
+type notifier struct {
+	storage      storage.WebhookStorage
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+
+	// queue of the deliveries to be sent by a fixed number of workers, bounding the concurrent deliveries
+	queue chan *pendingDelivery
+	// stopping is closed on shutdown to interrupt the retry backoffs
+	stopping chan struct{}
+	// mu guards closed and sending to queue, so that no event is queued after queue is closed
+	mu     sync.RWMutex
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+// NewNotifier creates a Notifier delivering events with retries and recording every delivery.
+// It starts the workers delivering the events, which run until Shutdown is called.
+func NewNotifier(storage storage.WebhookStorage, cfg config.WebhookConfig) Notifier {
+	n := &notifier{
+		storage:      storage,
+		httpClient:   &http.Client{Timeout: cfg.Timeout},
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+		queue:        make(chan *pendingDelivery, cfg.QueueSize),
+		stopping:     make(chan struct{}),
+	}
+
+	workers := cfg.MaxConcurrentDeliveries
+	if workers < 1 {
+		workers = 1
+	}
+	n.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer n.wg.Done()
+			for pending := range n.queue {
+				n.deliver(pending.webhook, pending.event, pending.payload)
+			}
+		}()
+	}
+	return n
+}
+
+func (n *notifier) Notify(ctx context.Context, projectID models.ID, eventType models.WebhookEventType, subject string, data interface{}) {
+	webhooks, err := n.storage.List(projectID)
+	if err != nil {
+		log.Errorf("unable to list webhooks of project %d: %v", projectID, err)
+		return
+	}
+
+	event := models.NewWebhookEvent(projectID, eventType, subject, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("unable to marshal %s event: %v", eventType, err)
+		return
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(eventType) {
+			continue
+		}
+
+		if n.closed {
+			n.drop(webhook, event, payload, "notifier is shut down")
+			continue
+		}
+		select {
+		case n.queue <- &pendingDelivery{webhook: webhook, event: event, payload: payload}:
+		default:
+			n.drop(webhook, event, payload, "delivery queue is full")
+		}
+	}
+}
+
+func (n *notifier) Shutdown(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.stopping)
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("unable to deliver the queued webhook events: %w", ctx.Err())
+	}
+}
+
+// drop records the delivery of an event that is never attempted
+func (n *notifier) drop(webhook *models.Webhook, event *models.WebhookEvent, payload []byte, reason string) {
+	log.Warnf("dropping %s event %s to webhook %s: %s", event.Type, event.ID, webhook.Name, reason)
+
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   string(payload),
+		Error:     reason,
+	}
+	if err := n.storage.SaveDelivery(delivery); err != nil {
+		log.Errorf("unable to save delivery of event %s to webhook %s: %v", event.ID, webhook.Name, err)
+	}
+}
+
+// deliver posts the payload to the webhook, retrying with exponential backoff, and records the delivery.
+// The retries stop once the notifier is shutting down.
+func (n *notifier) deliver(webhook *models.Webhook, event *models.WebhookEvent, payload []byte) {
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   string(payload),
+	}
+
+	backoff := n.retryBackoff
+	for attempt := 0; attempt <= n.maxRetries; attempt++ {
+		if attempt > 0 {
+			if !n.wait(backoff) {
+				break
+			}
+			backoff *= 2
+		}
+
+		delivery.Attempts++
+		statusCode, err := n.post(webhook, payload)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			break
+		}
+		delivery.Error = err.Error()
+	}
+
+	if !delivery.Success {
+		log.Warnf("unable to deliver %s event %s to webhook %s after %d attempts: %s", event.Type, event.ID, webhook.Name, delivery.Attempts, delivery.Error)
+	}
+
+	if err := n.storage.SaveDelivery(delivery); err != nil {
+		log.Errorf("unable to save delivery of event %s to webhook %s: %v", event.ID, webhook.Name, err)
+	}
+}
+
+// wait waits for the backoff before the next retry, it returns false if the notifier is shutting down
+func (n *notifier) wait(backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-n.stopping:
+		return false
+	}
+}
+
+func (n *notifier) post(webhook *models.Webhook, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, payload))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close() //nolint: errcheck
+
+	// drain body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the payload keyed by the secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload) //nolint: errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
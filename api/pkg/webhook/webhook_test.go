@@ -0,0 +1,178 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func TestNotifier_Notify(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		failedResponses  int32
+		eventTypes       models.WebhookEventTypes
+		expectedAttempts int
+		expectedSuccess  bool
+		expectedDelivery bool
+	}{
+		{
+			desc:             "Should deliver event on first attempt",
+			expectedAttempts: 1,
+			expectedSuccess:  true,
+			expectedDelivery: true,
+		},
+		{
+			desc:             "Should retry failed delivery",
+			failedResponses:  2,
+			expectedAttempts: 3,
+			expectedSuccess:  true,
+			expectedDelivery: true,
+		},
+		{
+			desc:             "Should record failed delivery after exhausting retries",
+			failedResponses:  10,
+			expectedAttempts: 3,
+			expectedSuccess:  false,
+			expectedDelivery: true,
+		},
+		{
+			desc:       "Should skip webhook not subscribing to the event type",
+			eventTypes: models.WebhookEventTypes{models.WebhookEventPredictionJobStatusChanged},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var requests int32
+			var received *models.WebhookEvent
+			var signature string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= tC.failedResponses {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, contentType, r.Header.Get("Content-Type"))
+				signature = r.Header.Get(SignatureHeader)
+				assert.Equal(t, "sha256="+Sign("secret", body), signature)
+				received = &models.WebhookEvent{}
+				assert.NoError(t, json.Unmarshal(body, received))
+			}))
+			defer server.Close()
+
+			webhook := &models.Webhook{ID: 1, ProjectID: 1, Name: "chat-ops", URL: server.URL, Secret: "secret", EventTypes: tC.eventTypes}
+
+			webhookStorage := &mocks.WebhookStorage{}
+			webhookStorage.On("List", models.ID(1)).Return([]*models.Webhook{webhook}, nil)
+			saved := make(chan struct{}, 1)
+			webhookStorage.On("SaveDelivery", mock.Anything).Return(nil).Run(func(mock.Arguments) { saved <- struct{}{} })
+
+			n := NewNotifier(webhookStorage, config.WebhookConfig{
+				Timeout:      time.Second,
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+
+				MaxConcurrentDeliveries: 1,
+				QueueSize:               1,
+			})
+
+			data := models.VersionEndpointEventData{ModelName: "model", Status: models.EndpointRunning}
+			n.Notify(context.Background(), models.ID(1), models.WebhookEventVersionEndpointStatusChanged, "endpoint-id", data)
+			if tC.expectedDelivery {
+				// wait for the retries to finish, since shutting down interrupts them
+				<-saved
+			}
+			assert.NoError(t, n.Shutdown(context.Background()))
+
+			if !tC.expectedDelivery {
+				webhookStorage.AssertNotCalled(t, "SaveDelivery", mock.Anything)
+				assert.Equal(t, int32(0), requests)
+				return
+			}
+
+			webhookStorage.AssertCalled(t, "SaveDelivery", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+				return d.WebhookID == webhook.ID && d.Attempts == tC.expectedAttempts && d.Success == tC.expectedSuccess
+			}))
+			if tC.expectedSuccess {
+				assert.Equal(t, "1.0", received.SpecVersion)
+				assert.Equal(t, "/projects/1", received.Source)
+				assert.Equal(t, models.WebhookEventVersionEndpointStatusChanged, received.Type)
+				assert.Equal(t, "endpoint-id", received.Subject)
+			}
+		})
+	}
+}
+
+func TestNotifier_Shutdown(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: 1, ProjectID: 1, Name: "chat-ops", URL: server.URL}
+
+	webhookStorage := &mocks.WebhookStorage{}
+	webhookStorage.On("List", models.ID(1)).Return([]*models.Webhook{webhook}, nil)
+	webhookStorage.On("SaveDelivery", mock.Anything).Return(nil)
+
+	n := NewNotifier(webhookStorage, config.WebhookConfig{
+		Timeout:      time.Second,
+		MaxRetries:   5,
+		RetryBackoff: time.Hour,
+
+		MaxConcurrentDeliveries: 1,
+		QueueSize:               1,
+	})
+
+	data := models.VersionEndpointEventData{ModelName: "model", Status: models.EndpointFailed}
+	for i := 0; i < 3; i++ {
+		n.Notify(context.Background(), models.ID(1), models.WebhookEventVersionEndpointStatusChanged, "endpoint-id", data)
+		// let the worker pick up the first event, so that the second one waits in the queue
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// the retry backoff is interrupted, so shutdown doesn't wait for it
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, n.Shutdown(ctx))
+
+	// the in-flight and the queued events are attempted, and the event exceeding the queue is dropped
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	webhookStorage.AssertNumberOfCalls(t, "SaveDelivery", 3)
+	webhookStorage.AssertCalled(t, "SaveDelivery", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.Attempts == 0 && d.Error == "delivery queue is full"
+	}))
+
+	n.Notify(context.Background(), models.ID(1), models.WebhookEventVersionEndpointStatusChanged, "endpoint-id", data)
+	webhookStorage.AssertCalled(t, "SaveDelivery", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.Attempts == 0 && d.Error == "notifier is shut down"
+	}))
+}
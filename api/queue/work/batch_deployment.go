@@ -78,13 +78,13 @@ func (depl *BatchDeployment) doCreatePredictionJob(ctx context.Context, env *mod
 	project := model.Project
 
 	// build image
-	imageRef, err := depl.ImageBuilder.BuildImage(ctx, project, model, version)
+	imageRef, err := depl.ImageBuilder.BuildImage(ctx, project, model, version, env.Name)
 	if err != nil {
 		return err
 	}
 	job.Config.ImageRef = imageRef
 
-	job.Config.MainAppPath, err = depl.ImageBuilder.GetMainAppPath(version)
+	job.Config.MainAppPath, err = depl.ImageBuilder.GetMainAppPath(version, env.Name)
 	if err != nil {
 		return err
 	}
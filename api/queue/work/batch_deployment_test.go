@@ -117,8 +117,8 @@ func TestBatchDeployment_Deploy(t *testing.T) {
 				ctrl.On("Submit", context.Background(), savedJob, project.Name).Return(nil)
 			},
 			imageBuilderMock: func(imgBuilder *imageBuilderMock.ImageBuilder) {
-				imgBuilder.On("BuildImage", context.Background(), project, model, version).Return(imageRef, nil)
-				imgBuilder.On("GetMainAppPath", version).Return(mainApplicationPath, nil)
+				imgBuilder.On("BuildImage", context.Background(), project, model, version, predJobEnv.Name).Return(imageRef, nil)
+				imgBuilder.On("GetMainAppPath", version, predJobEnv.Name).Return(mainApplicationPath, nil)
 			},
 			mockStorage: func(st *storageMock.PredictionJobStorage) {
 				st.On("Get", savedJob.ID).Return(savedJob, nil)
@@ -129,7 +129,7 @@ func TestBatchDeployment_Deploy(t *testing.T) {
 			deployErr:      fmt.Errorf("failed building image"),
 			controllerMock: func(ctrl *mocks.Controller) {},
 			imageBuilderMock: func(imgBuilder *imageBuilderMock.ImageBuilder) {
-				imgBuilder.On("BuildImage", context.Background(), project, model, version).Return("", fmt.Errorf("failed building image"))
+				imgBuilder.On("BuildImage", context.Background(), project, model, version, predJobEnv.Name).Return("", fmt.Errorf("failed building image"))
 			},
 			mockStorage: func(st *storageMock.PredictionJobStorage) {
 				st.On("Get", savedJob.ID).Return(savedJob, nil)
@@ -141,8 +141,8 @@ func TestBatchDeployment_Deploy(t *testing.T) {
 			deployErr:      fmt.Errorf("failed getting mainAppPath"),
 			controllerMock: func(ctrl *mocks.Controller) {},
 			imageBuilderMock: func(imgBuilder *imageBuilderMock.ImageBuilder) {
-				imgBuilder.On("BuildImage", context.Background(), project, model, version).Return(imageRef, nil)
-				imgBuilder.On("GetMainAppPath", version).Return("", fmt.Errorf("failed getting mainAppPath"))
+				imgBuilder.On("BuildImage", context.Background(), project, model, version, predJobEnv.Name).Return(imageRef, nil)
+				imgBuilder.On("GetMainAppPath", version, predJobEnv.Name).Return("", fmt.Errorf("failed getting mainAppPath"))
 			},
 			mockStorage: func(st *storageMock.PredictionJobStorage) {
 				st.On("Get", savedJob.ID).Return(savedJob, nil)
@@ -156,8 +156,8 @@ func TestBatchDeployment_Deploy(t *testing.T) {
 				ctrl.On("Submit", context.Background(), savedJob, project.Name).Return(fmt.Errorf("failed submit job"))
 			},
 			imageBuilderMock: func(imgBuilder *imageBuilderMock.ImageBuilder) {
-				imgBuilder.On("BuildImage", context.Background(), project, model, version).Return(imageRef, nil)
-				imgBuilder.On("GetMainAppPath", version).Return(mainApplicationPath, nil)
+				imgBuilder.On("BuildImage", context.Background(), project, model, version, predJobEnv.Name).Return(imageRef, nil)
+				imgBuilder.On("GetMainAppPath", version, predJobEnv.Name).Return(mainApplicationPath, nil)
 			},
 			mockStorage: func(st *storageMock.PredictionJobStorage) {
 				st.On("Get", savedJob.ID).Return(savedJob, nil)
@@ -0,0 +1,108 @@
+package work
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/cluster"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/queue"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+type InferenceGraphDeployment struct {
+	ClusterControllers     map[string]cluster.Controller
+	Storage                storage.InferenceGraphStorage
+	VersionEndpointStorage storage.VersionEndpointStorage
+	LoggerDestinationURL   string
+}
+
+type InferenceGraphJob struct {
+	Graph   *models.InferenceGraph
+	Project mlp.Project
+}
+
+func (depl *InferenceGraphDeployment) Deploy(job *queue.Job) error {
+	ctx := context.Background()
+	data := job.Arguments[dataArgKey]
+	byte, _ := json.Marshal(data)
+	var jobArgs InferenceGraphJob
+	if err := json.Unmarshal(byte, &jobArgs); err != nil {
+		return err
+	}
+
+	graphArg := jobArgs.Graph
+	graph, err := depl.Storage.Get(graphArg.ProjectID, graphArg.ID)
+	if gorm.IsRecordNotFoundError(err) {
+		log.Errorf("could not found inference graph with id %d and error: %v", graphArg.ID, err)
+		return err
+	}
+	if err != nil {
+		log.Errorf("could not fetch inference graph with id %d and error: %v", graphArg.ID, err)
+		// If error getting record from db, return err as RetryableError to enable retry
+		return queue.RetryableError{Message: err.Error()}
+	}
+
+	project := jobArgs.Project
+	log.Infof("creating deployment for inference graph %s in project %s", graph.Name, project.Name)
+
+	graph.Status = models.EndpointFailed
+	defer func() {
+		if err := depl.Storage.Save(graph); err != nil {
+			log.Errorf("unable to update inference graph status for %s, reason: %v", graph.Name, err)
+		}
+	}()
+
+	if err := depl.resolveVersionEndpoints(graph); err != nil {
+		graph.SetMessage(err.Error())
+		return err
+	}
+
+	ctl, ok := depl.ClusterControllers[graph.EnvironmentName]
+	if !ok {
+		err := fmt.Errorf("unable to find cluster controller for environment %s", graph.EnvironmentName)
+		graph.SetMessage(err.Error())
+		return err
+	}
+
+	metadata := models.Metadata{
+		App:       graph.Name,
+		Component: models.ComponentInferenceGraph,
+		Labels:    project.Labels,
+		Stream:    project.Stream,
+		Team:      project.Team,
+	}
+	url, err := ctl.DeployInferenceGraph(ctx, graph, project.Name, metadata.ToLabel(), depl.LoggerDestinationURL)
+	if err != nil {
+		log.Errorf("unable to deploy inference graph %s, reason: %v", graph.Name, err)
+		graph.SetMessage(err.Error())
+		return err
+	}
+
+	graph.URL = url
+	graph.SetMessage("")
+	graph.Status = models.EndpointRunning
+	return nil
+}
+
+func (depl *InferenceGraphDeployment) resolveVersionEndpoints(graph *models.InferenceGraph) error {
+	for _, node := range graph.Nodes {
+		for _, step := range node.Steps {
+			if step.VersionEndpointID == nil {
+				continue
+			}
+
+			endpoint, err := depl.VersionEndpointStorage.Get(*step.VersionEndpointID)
+			if err != nil {
+				return fmt.Errorf("unable to find version endpoint %s: %w", step.VersionEndpointID, err)
+			}
+			step.VersionEndpoint = endpoint
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package work
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/cluster"
+	clusterMock "github.com/caraml-dev/merlin/cluster/mocks"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/queue"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func TestInferenceGraphDeployment_Deploy(t *testing.T) {
+	project := mlp.Project{Name: "project", Team: "dsp", Stream: "dsp"}
+	endpointID := uuid.New()
+	endpoint := &models.VersionEndpoint{ID: endpointID, URL: "http://model-1.project.models.example.com/v1/models/model-1"}
+
+	testCases := []struct {
+		desc            string
+		deployErr       error
+		expectedStatus  models.EndpointStatus
+		expectedURL     string
+		expectedMessage string
+	}{
+		{
+			desc:           "Should mark graph as running when deployed",
+			expectedStatus: models.EndpointRunning,
+			expectedURL:    "http://my-graph.project.models.example.com",
+		},
+		{
+			desc:            "Should mark graph as failed when deployment fails",
+			deployErr:       errors.New("timeout creating inference graph"),
+			expectedStatus:  models.EndpointFailed,
+			expectedMessage: "timeout creating inference graph",
+		},
+		{
+			desc:            "Should truncate long deployment error message",
+			deployErr:       errors.New(strings.Repeat("x", 3000)),
+			expectedStatus:  models.EndpointFailed,
+			expectedMessage: strings.Repeat("x", 2048),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			graph := &models.InferenceGraph{
+				ID:        1,
+				ProjectID: 1,
+				Name:      "my-graph",
+				Nodes: models.InferenceGraphNodes{
+					models.InferenceGraphRootNode: {
+						RouterType: models.InferenceGraphSequence,
+						Steps:      []*models.InferenceGraphStep{{VersionEndpointID: &endpointID}},
+					},
+				},
+				Status:          models.EndpointPending,
+				EnvironmentName: "env1",
+			}
+
+			graphStorage := &mocks.InferenceGraphStorage{}
+			graphStorage.On("Get", models.ID(1), models.ID(1)).Return(graph, nil)
+			graphStorage.On("Save", mock.Anything).Return(nil)
+
+			endpointStorage := &mocks.VersionEndpointStorage{}
+			endpointStorage.On("Get", endpointID).Return(endpoint, nil)
+
+			controller := &clusterMock.Controller{}
+			controller.On("DeployInferenceGraph", mock.Anything, mock.MatchedBy(func(g *models.InferenceGraph) bool {
+				return g.Nodes[models.InferenceGraphRootNode].Steps[0].VersionEndpoint == endpoint
+			}), "project", mock.Anything, "http://logger.example.com").Return(tC.expectedURL, tC.deployErr)
+
+			depl := &InferenceGraphDeployment{
+				ClusterControllers:     map[string]cluster.Controller{"env1": controller},
+				Storage:                graphStorage,
+				VersionEndpointStorage: endpointStorage,
+				LoggerDestinationURL:   "http://logger.example.com",
+			}
+
+			job := &queue.Job{
+				Name: "job",
+				Arguments: queue.Arguments{
+					dataArgKey: InferenceGraphJob{Graph: graph, Project: project},
+				},
+			}
+			err := depl.Deploy(job)
+			assert.Equal(t, tC.deployErr, err)
+			assert.Equal(t, tC.expectedStatus, graph.Status)
+			assert.Equal(t, tC.expectedURL, graph.URL)
+			assert.Equal(t, tC.expectedMessage, graph.Message)
+			graphStorage.AssertCalled(t, "Save", graph)
+		})
+	}
+}
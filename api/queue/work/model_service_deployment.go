@@ -10,8 +10,10 @@ import (
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
 	"github.com/caraml-dev/merlin/pkg/imagebuilder"
+	"github.com/caraml-dev/merlin/pkg/webhook"
 	"github.com/caraml-dev/merlin/queue"
 	"github.com/caraml-dev/merlin/storage"
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -36,7 +38,9 @@ type ModelServiceDeployment struct {
 	ImageBuilder         imagebuilder.ImageBuilder
 	Storage              storage.VersionEndpointStorage
 	DeploymentStorage    storage.DeploymentStorage
+	EventStorage         storage.DeploymentEventStorage
 	LoggerDestinationURL string
+	Notifier             webhook.Notifier
 }
 
 type EndpointJob struct {
@@ -64,6 +68,8 @@ func (depl *ModelServiceDeployment) Deploy(job *queue.Job) error {
 	if err != nil {
 		log.Errorf("could not fetch version endpoint with id %s and error: %v", endpointArg.ID, err)
 		// If error getting record from db, return err as RetryableError to enable retry
+		depl.recordEvents(endpointArg.ID, models.NewDeploymentFailureEvent(models.DeploymentEventSourceWorker, "DeploymentRetried",
+			fmt.Sprintf("unable to fetch version endpoint, the deployment will be retried: %v", err)))
 		return queue.RetryableError{Message: err.Error()}
 	}
 
@@ -78,12 +84,22 @@ func (depl *ModelServiceDeployment) Deploy(job *queue.Job) error {
 
 	model.Project = project
 	log.Infof("creating deployment for model %s version %s with endpoint id: %s", model.Name, endpoint.VersionID, endpoint.ID)
+	depl.recordEvents(endpoint.ID, models.NewDeploymentEvent(models.DeploymentEventSourceWorker, "DeploymentStarted",
+		fmt.Sprintf("deploying model %s version %s to environment %s", model.Name, endpoint.VersionID, endpoint.EnvironmentName)))
 
 	// copy endpoint to avoid race condition
+	pendingStatus := endpoint.Status
 	endpoint.Status = models.EndpointFailed
 	defer func() {
 		deploymentCounter.WithLabelValues(model.Project.Name, model.Name, string(endpoint.Status)).Inc()
 
+		if endpoint.Status == models.EndpointFailed {
+			depl.recordEvents(endpoint.ID, models.NewDeploymentFailureEvent(models.DeploymentEventSourceWorker, "DeploymentFailed", endpoint.Message))
+		} else {
+			depl.recordEvents(endpoint.ID, models.NewDeploymentEvent(models.DeploymentEventSourceWorker, "DeploymentSucceeded",
+				fmt.Sprintf("endpoint is %s at %s", endpoint.Status, endpoint.URL)))
+		}
+
 		// record the deployment result
 		if _, err := depl.DeploymentStorage.Save(&models.Deployment{
 			ProjectID:         model.ProjectID,
@@ -99,9 +115,23 @@ func (depl *ModelServiceDeployment) Deploy(job *queue.Job) error {
 		if err := depl.Storage.Save(endpoint); err != nil {
 			log.Errorf("unable to update endpoint status for model: %s, version: %s, reason: %v", model.Name, version.ID, err)
 		}
+
+		if depl.Notifier != nil {
+			depl.Notifier.Notify(ctx, model.ProjectID, models.WebhookEventVersionEndpointStatusChanged, endpoint.ID.String(), models.VersionEndpointEventData{
+				EndpointID:      endpoint.ID,
+				ModelID:         model.ID,
+				ModelName:       model.Name,
+				VersionID:       endpoint.VersionID,
+				EnvironmentName: endpoint.EnvironmentName,
+				PreviousStatus:  pendingStatus,
+				Status:          endpoint.Status,
+				URL:             endpoint.URL,
+				Message:         endpoint.Message,
+			})
+		}
 	}()
 
-	modelOpt, err := depl.generateModelOptions(ctx, model, version)
+	modelOpt, err := depl.generateModelOptions(ctx, endpoint, model, version)
 	if err != nil {
 		endpoint.Message = err.Error()
 		return err
@@ -113,6 +143,7 @@ func (depl *ModelServiceDeployment) Deploy(job *queue.Job) error {
 		return fmt.Errorf("unable to find cluster controller for environment %s", endpoint.EnvironmentName)
 	}
 	svc, err := ctl.Deploy(ctx, modelService)
+	depl.recordEvents(endpoint.ID, modelService.Events...)
 	if err != nil {
 		log.Errorf("unable to deploy version endpoint for model: %s, version: %s, reason: %v", model.Name, version.ID, err)
 		endpoint.Message = err.Error()
@@ -130,17 +161,40 @@ func (depl *ModelServiceDeployment) Deploy(job *queue.Job) error {
 	return nil
 }
 
-func (depl *ModelServiceDeployment) generateModelOptions(ctx context.Context, model *models.Model, version *models.Version) (*models.ModelOption, error) {
+func (depl *ModelServiceDeployment) generateModelOptions(ctx context.Context, endpoint *models.VersionEndpoint, model *models.Model, version *models.Version) (*models.ModelOption, error) {
 	modelOpt := &models.ModelOption{}
 	switch model.Type {
 	case models.ModelTypePyFunc:
-		imageRef, err := depl.ImageBuilder.BuildImage(ctx, model.Project, model, version)
+		depl.recordEvents(endpoint.ID, models.NewDeploymentEvent(models.DeploymentEventSourceImageBuilder, "ImageBuildStarted",
+			fmt.Sprintf("building image of model %s version %s", model.Name, version.ID)))
+		imageRef, err := depl.ImageBuilder.BuildImage(ctx, model.Project, model, version, endpoint.EnvironmentName)
 		if err != nil {
+			event := models.NewDeploymentFailureEvent(models.DeploymentEventSourceImageBuilder, "ImageBuildFailed", err.Error())
+			if event.FailureClass == models.FailureClassUnknown {
+				event.FailureClass = models.FailureClassImageBuild
+			}
+			depl.recordEvents(endpoint.ID, event)
 			return modelOpt, err
 		}
+		depl.recordEvents(endpoint.ID, models.NewDeploymentEvent(models.DeploymentEventSourceImageBuilder, "ImageBuildSucceeded",
+			fmt.Sprintf("image %s is built", imageRef)))
 		modelOpt.PyFuncImageName = imageRef
 	case models.ModelTypeCustom:
 		modelOpt = models.NewCustomModelOption(version)
 	}
 	return modelOpt, nil
 }
+
+// recordEvents saves the events to the deployment timeline of the endpoint, if the event storage is configured
+func (depl *ModelServiceDeployment) recordEvents(endpointID uuid.UUID, events ...*models.DeploymentEvent) {
+	if depl.EventStorage == nil || len(events) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		event.VersionEndpointID = endpointID
+	}
+	if err := depl.EventStorage.Save(events); err != nil {
+		log.Warnf("unable to save deployment events of endpoint %s: %v", endpointID, err)
+	}
+}
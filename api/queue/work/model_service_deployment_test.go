@@ -11,8 +11,10 @@ import (
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
 	imageBuilderMock "github.com/caraml-dev/merlin/pkg/imagebuilder/mocks"
+	webhookMock "github.com/caraml-dev/merlin/pkg/webhook/mocks"
 	"github.com/caraml-dev/merlin/queue"
 	"github.com/caraml-dev/merlin/storage/mocks"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -199,7 +201,7 @@ func TestExecuteDeployment(t *testing.T) {
 			},
 			imageBuilder: func() *imageBuilderMock.ImageBuilder {
 				mockImgBuilder := &imageBuilderMock.ImageBuilder{}
-				mockImgBuilder.On("BuildImage", context.Background(), project, mock.Anything, mock.Anything).
+				mockImgBuilder.On("BuildImage", context.Background(), project, mock.Anything, mock.Anything, env.Name).
 					Return("gojek/mymodel-1:latest", nil)
 				return mockImgBuilder
 			},
@@ -245,7 +247,7 @@ func TestExecuteDeployment(t *testing.T) {
 			},
 			imageBuilder: func() *imageBuilderMock.ImageBuilder {
 				mockImgBuilder := &imageBuilderMock.ImageBuilder{}
-				mockImgBuilder.On("BuildImage", context.Background(), project, mock.Anything, mock.Anything).
+				mockImgBuilder.On("BuildImage", context.Background(), project, mock.Anything, mock.Anything, env.Name).
 					Return("gojek/mymodel-1:latest", nil)
 				return mockImgBuilder
 			},
@@ -323,7 +325,7 @@ func TestExecuteDeployment(t *testing.T) {
 			},
 			imageBuilder: func() *imageBuilderMock.ImageBuilder {
 				mockImgBuilder := &imageBuilderMock.ImageBuilder{}
-				mockImgBuilder.On("BuildImage", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("Failed to build image"))
+				mockImgBuilder.On("BuildImage", context.Background(), mock.Anything, mock.Anything, mock.Anything, env.Name).Return("", errors.New("Failed to build image"))
 				return mockImgBuilder
 			},
 		},
@@ -346,12 +348,15 @@ func TestExecuteDeployment(t *testing.T) {
 					},
 				},
 			}
+			notifier := &webhookMock.Notifier{}
+			notifier.On("Notify", mock.Anything, mock.Anything, models.WebhookEventVersionEndpointStatusChanged, mock.Anything, mock.Anything).Return()
 			svc := &ModelServiceDeployment{
 				ClusterControllers:   controllers,
 				ImageBuilder:         imgBuilder,
 				Storage:              mockStorage,
 				DeploymentStorage:    mockDeploymentStorage,
 				LoggerDestinationURL: loggerDestinationURL,
+				Notifier:             notifier,
 			}
 
 			err := svc.Deploy(job)
@@ -381,6 +386,120 @@ func TestExecuteDeployment(t *testing.T) {
 				assert.Equal(t, url, savedEndpoint.URL)
 				assert.Equal(t, iSvcName, savedEndpoint.InferenceServiceName)
 			}
+
+			notifier.AssertNumberOfCalls(t, "Notify", 1)
+			eventData := notifier.Calls[0].Arguments[4].(models.VersionEndpointEventData)
+			assert.Equal(t, savedEndpoint.Status, eventData.Status)
 		})
 	}
 }
+
+func TestExecuteDeployment_RecordEvents(t *testing.T) {
+	env := &models.Environment{Name: "env1", Cluster: "cluster1"}
+	project := mlp.Project{Name: "project"}
+	model := &models.Model{Name: "model", Project: project, Type: models.ModelTypePyFunc}
+	version := &models.Version{ID: 1}
+	endpoint := &models.VersionEndpoint{
+		ID:              uuid.New(),
+		EnvironmentName: env.Name,
+		VersionID:       version.ID,
+		Status:          models.EndpointPending,
+	}
+
+	deploymentStorage := &mocks.DeploymentStorage{}
+	deploymentStorage.On("Save", mock.Anything).Return(nil, nil)
+
+	endpointStorage := &mocks.VersionEndpointStorage{}
+	endpointStorage.On("Save", mock.Anything).Return(nil)
+	endpointStorage.On("Get", endpoint.ID).Return(&models.VersionEndpoint{
+		ID:              endpoint.ID,
+		Environment:     env,
+		EnvironmentName: env.Name,
+		VersionID:       version.ID,
+	}, nil)
+
+	imgBuilder := &imageBuilderMock.ImageBuilder{}
+	imgBuilder.On("BuildImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("ghcr.io/project-model:1", nil)
+
+	ctrl := &clusterMock.Controller{}
+	ctrl.On("Deploy", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			modelService := args.Get(1).(*models.Service)
+			modelService.Events = append(modelService.Events,
+				models.NewDeploymentFailureEvent(models.DeploymentEventSourceKubernetes, "OOMKilled", "container kserve-container terminated with exit code 137"))
+		}).
+		Return(nil, cluster.ErrTimeoutCreateInferenceService)
+
+	var events []*models.DeploymentEvent
+	eventStorage := &mocks.DeploymentEventStorage{}
+	eventStorage.On("Save", mock.Anything).
+		Run(func(args mock.Arguments) {
+			events = append(events, args.Get(0).([]*models.DeploymentEvent)...)
+		}).
+		Return(nil)
+
+	svc := &ModelServiceDeployment{
+		ClusterControllers: map[string]cluster.Controller{env.Name: ctrl},
+		ImageBuilder:       imgBuilder,
+		Storage:            endpointStorage,
+		DeploymentStorage:  deploymentStorage,
+		EventStorage:       eventStorage,
+	}
+
+	err := svc.Deploy(&queue.Job{
+		Name: "job",
+		Arguments: queue.Arguments{
+			dataArgKey: EndpointJob{
+				Endpoint: endpoint,
+				Version:  version,
+				Model:    model,
+				Project:  project,
+			},
+		},
+	})
+	assert.Equal(t, cluster.ErrTimeoutCreateInferenceService, err)
+
+	var reasons []string
+	for _, event := range events {
+		assert.Equal(t, endpoint.ID, event.VersionEndpointID)
+		reasons = append(reasons, event.Reason)
+	}
+	assert.Equal(t, []string{"DeploymentStarted", "ImageBuildStarted", "ImageBuildSucceeded", "OOMKilled", "DeploymentFailed"}, reasons)
+	assert.Equal(t, models.FailureClassOOMKilled, events[3].FailureClass)
+	assert.Equal(t, models.DeploymentEventTypeWarning, events[4].Type)
+	assert.Equal(t, models.FailureClassReadinessTimeout, events[4].FailureClass)
+}
+
+func TestExecuteDeployment_RecordRetry(t *testing.T) {
+	endpoint := &models.VersionEndpoint{ID: uuid.New(), EnvironmentName: "env1"}
+
+	endpointStorage := &mocks.VersionEndpointStorage{}
+	endpointStorage.On("Get", endpoint.ID).Return(nil, errors.New("connection refused"))
+
+	eventStorage := &mocks.DeploymentEventStorage{}
+	eventStorage.On("Save", mock.Anything).Return(nil)
+
+	svc := &ModelServiceDeployment{
+		Storage:      endpointStorage,
+		EventStorage: eventStorage,
+	}
+
+	err := svc.Deploy(&queue.Job{
+		Name: "job",
+		Arguments: queue.Arguments{
+			dataArgKey: EndpointJob{
+				Endpoint: endpoint,
+				Version:  &models.Version{ID: 1},
+				Model:    &models.Model{Name: "model"},
+			},
+		},
+	})
+	assert.Equal(t, queue.RetryableError{Message: "connection refused"}, err)
+
+	eventStorage.AssertNumberOfCalls(t, "Save", 1)
+	events := eventStorage.Calls[0].Arguments[0].([]*models.DeploymentEvent)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "DeploymentRetried", events[0].Reason)
+	assert.Equal(t, endpoint.ID, events[0].VersionEndpointID)
+	assert.Equal(t, models.DeploymentEventSourceWorker, events[0].Source)
+}
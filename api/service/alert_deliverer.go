@@ -0,0 +1,191 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caraml-dev/merlin/cluster"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/gitlab"
+)
+
+// AlertDeliverer delivers the Prometheus rules file of model endpoint alerts to where Prometheus loads it from.
+type AlertDeliverer interface {
+	CreateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error
+	UpdateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error
+	DeleteAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error
+}
+
+type gitlabAlertDeliverer struct {
+	gitlabClient gitlab.Client
+	repository   string
+	branch       string
+}
+
+// NewGitlabAlertDeliverer initializes an alert deliverer committing the rules file to a GitLab repository.
+func NewGitlabAlertDeliverer(gitlabClient gitlab.Client, repository, branch string) AlertDeliverer {
+	return &gitlabAlertDeliverer{
+		gitlabClient: gitlabClient,
+		repository:   repository,
+		branch:       branch,
+	}
+}
+
+func (d *gitlabAlertDeliverer) CreateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.gitlabClient.CreateFile(gitlab.CreateFileOptions{
+		Repository:    d.repository,
+		Branch:        d.branch,
+		FileName:      rules.FileName,
+		Content:       rules.Content,
+		CommitMessage: fmt.Sprintf("Autogenerated by Merlin: Create alert for %s/%s in %s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName),
+		AuthorEmail:   user,
+		AuthorName:    user,
+	})
+}
+
+func (d *gitlabAlertDeliverer) UpdateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.gitlabClient.UpdateFile(gitlab.UpdateFileOptions{
+		Repository:    d.repository,
+		Branch:        d.branch,
+		FileName:      rules.FileName,
+		Content:       rules.Content,
+		CommitMessage: fmt.Sprintf("Autogenerated by Merlin: Update alert for %s/%s in %s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName),
+		AuthorEmail:   user,
+		AuthorName:    user,
+	})
+}
+
+func (d *gitlabAlertDeliverer) DeleteAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.gitlabClient.DeleteFile(gitlab.DeleteFileOptions{
+		Repository:    d.repository,
+		Branch:        d.branch,
+		FileName:      rules.FileName,
+		CommitMessage: fmt.Sprintf("Autogenerated by Merlin: Delete alert for %s/%s in %s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName),
+		AuthorEmail:   user,
+		AuthorName:    user,
+	})
+}
+
+type prometheusRuleAlertDeliverer struct {
+	controller cluster.Controller
+	namespace  string
+	labels     map[string]string
+}
+
+// NewPrometheusRuleAlertDeliverer initializes an alert deliverer applying the rules as Prometheus Operator's PrometheusRule
+// through the environment's cluster controller. The rule is created in the model's project namespace if namespace is empty.
+func NewPrometheusRuleAlertDeliverer(controller cluster.Controller, namespace string, labels map[string]string) AlertDeliverer {
+	return &prometheusRuleAlertDeliverer{
+		controller: controller,
+		namespace:  namespace,
+		labels:     labels,
+	}
+}
+
+func (d *prometheusRuleAlertDeliverer) CreateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.UpdateAlert(ctx, user, alert, rules)
+}
+
+func (d *prometheusRuleAlertDeliverer) UpdateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	namespace := alertNamespace(d.namespace, alert)
+	return d.controller.ApplyPrometheusRule(ctx, namespace, alertResourceName(alert), d.labels, rules.Content)
+}
+
+func (d *prometheusRuleAlertDeliverer) DeleteAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.controller.DeletePrometheusRule(ctx, alertNamespace(d.namespace, alert), alertResourceName(alert))
+}
+
+type configMapAlertDeliverer struct {
+	controller cluster.Controller
+	namespace  string
+	labels     map[string]string
+}
+
+// NewConfigMapAlertDeliverer initializes an alert deliverer applying the rules file as a ConfigMap through the environment's
+// cluster controller, e.g. to be mounted into Prometheus. The ConfigMap is created in the model's project namespace if namespace is empty.
+func NewConfigMapAlertDeliverer(controller cluster.Controller, namespace string, labels map[string]string) AlertDeliverer {
+	return &configMapAlertDeliverer{
+		controller: controller,
+		namespace:  namespace,
+		labels:     labels,
+	}
+}
+
+func (d *configMapAlertDeliverer) CreateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.UpdateAlert(ctx, user, alert, rules)
+}
+
+func (d *configMapAlertDeliverer) UpdateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	namespace := alertNamespace(d.namespace, alert)
+	data := map[string]string{
+		filepath.Base(rules.FileName): rules.Content,
+	}
+	return d.controller.ApplyConfigMap(ctx, namespace, alertResourceName(alert), d.labels, data)
+}
+
+func (d *configMapAlertDeliverer) DeleteAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.controller.DeleteConfigMap(ctx, alertNamespace(d.namespace, alert), alertResourceName(alert))
+}
+
+type directoryAlertDeliverer struct {
+	directory string
+}
+
+// NewDirectoryAlertDeliverer initializes an alert deliverer writing the rules file under a directory, keeping the file name
+// relative to the directory, e.g. for a volume shared with Prometheus.
+func NewDirectoryAlertDeliverer(directory string) AlertDeliverer {
+	return &directoryAlertDeliverer{
+		directory: directory,
+	}
+}
+
+func (d *directoryAlertDeliverer) CreateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	return d.UpdateAlert(ctx, user, alert, rules)
+}
+
+func (d *directoryAlertDeliverer) UpdateAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	path := filepath.Join(d.directory, filepath.Clean("/"+rules.FileName))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(rules.Content), 0o644)
+}
+
+func (d *directoryAlertDeliverer) DeleteAlert(ctx context.Context, user string, alert *models.ModelEndpointAlert, rules *models.ModelEndpointAlertPreview) error {
+	path := filepath.Join(d.directory, filepath.Clean("/"+rules.FileName))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func alertNamespace(namespace string, alert *models.ModelEndpointAlert) string {
+	if namespace != "" {
+		return namespace
+	}
+	return alert.Model.Project.Name
+}
+
+// alertResourceName returns the name of the kubernetes resource holding the alert's rules,
+// which must be a valid DNS subdomain name.
+func alertResourceName(alert *models.ModelEndpointAlert) string {
+	name := fmt.Sprintf("merlin-%s-%s-%s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName)
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	clusterMock "github.com/caraml-dev/merlin/cluster/mocks"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/gitlab"
+	gitlabmocks "github.com/caraml-dev/merlin/pkg/gitlab/mocks"
+)
+
+func newTestDeliveredAlert() (*models.ModelEndpointAlert, *models.ModelEndpointAlertPreview) {
+	alert := &models.ModelEndpointAlert{
+		Model: &models.Model{
+			Name: "model-1",
+			Project: mlp.Project{
+				Name: "project-1",
+			},
+		},
+		EnvironmentName: "env_1",
+	}
+	rules := &models.ModelEndpointAlertPreview{
+		FileName: "alerts/merlin/project-1/model-1_env_1.yaml",
+		Content:  "groups: []\n",
+	}
+	return alert, rules
+}
+
+func Test_gitlabAlertDeliverer(t *testing.T) {
+	alert, rules := newTestDeliveredAlert()
+
+	gitlabClient := &gitlabmocks.Client{}
+	gitlabClient.On("UpdateFile", gitlab.UpdateFileOptions{
+		Repository:    "merlin/alerts",
+		Branch:        "main",
+		FileName:      rules.FileName,
+		Content:       rules.Content,
+		CommitMessage: "Autogenerated by Merlin: Update alert for project-1/model-1 in env_1",
+		AuthorEmail:   "author-test",
+		AuthorName:    "author-test",
+	}).Return(nil)
+
+	gitlabClient.On("DeleteFile", gitlab.DeleteFileOptions{
+		Repository:    "merlin/alerts",
+		Branch:        "main",
+		FileName:      rules.FileName,
+		CommitMessage: "Autogenerated by Merlin: Delete alert for project-1/model-1 in env_1",
+		AuthorEmail:   "author-test",
+		AuthorName:    "author-test",
+	}).Return(nil)
+
+	deliverer := NewGitlabAlertDeliverer(gitlabClient, "merlin/alerts", "main")
+	err := deliverer.UpdateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+	err = deliverer.DeleteAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+	gitlabClient.AssertExpectations(t)
+}
+
+func Test_prometheusRuleAlertDeliverer(t *testing.T) {
+	alert, rules := newTestDeliveredAlert()
+	labels := map[string]string{"prometheus": "k8s"}
+
+	controller := &clusterMock.Controller{}
+	controller.On("ApplyPrometheusRule", mock.Anything, "project-1", "merlin-project-1-model-1-env-1", labels, rules.Content).Return(nil)
+	controller.On("ApplyPrometheusRule", mock.Anything, "monitoring", "merlin-project-1-model-1-env-1", labels, rules.Content).Return(nil)
+	controller.On("DeletePrometheusRule", mock.Anything, "monitoring", "merlin-project-1-model-1-env-1").Return(nil)
+
+	err := NewPrometheusRuleAlertDeliverer(controller, "", labels).CreateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+
+	err = NewPrometheusRuleAlertDeliverer(controller, "monitoring", labels).UpdateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+
+	err = NewPrometheusRuleAlertDeliverer(controller, "monitoring", labels).DeleteAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+	controller.AssertExpectations(t)
+}
+
+func Test_configMapAlertDeliverer(t *testing.T) {
+	alert, rules := newTestDeliveredAlert()
+
+	controller := &clusterMock.Controller{}
+	controller.On("ApplyConfigMap", mock.Anything, "monitoring", "merlin-project-1-model-1-env-1", map[string]string(nil),
+		map[string]string{"model-1_env_1.yaml": rules.Content}).Return(nil)
+	controller.On("DeleteConfigMap", mock.Anything, "monitoring", "merlin-project-1-model-1-env-1").Return(nil)
+
+	err := NewConfigMapAlertDeliverer(controller, "monitoring", nil).CreateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+
+	err = NewConfigMapAlertDeliverer(controller, "monitoring", nil).DeleteAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+	controller.AssertExpectations(t)
+}
+
+func Test_directoryAlertDeliverer(t *testing.T) {
+	alert, rules := newTestDeliveredAlert()
+	directory := t.TempDir()
+	deliverer := NewDirectoryAlertDeliverer(directory)
+
+	err := deliverer.CreateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+
+	rules.Content = "groups:\n- name: merlin_project-1_model-1_env_1\n"
+	err = deliverer.UpdateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(filepath.Join(directory, rules.FileName))
+	assert.Nil(t, err)
+	assert.Equal(t, rules.Content, string(content))
+
+	err = deliverer.DeleteAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(directory, rules.FileName))
+	assert.True(t, os.IsNotExist(err))
+
+	// Deleting a rules file that doesn't exist succeeds
+	err = deliverer.DeleteAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+
+	// The rules file can't be written outside of the directory
+	rules.FileName = "../outside.yaml"
+	err = deliverer.UpdateAlert(context.Background(), "author-test", alert, rules)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(directory, "outside.yaml"))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,290 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+// driftReferenceRefreshInterval bounds how long a drift reference registered or replaced is ignored by the prediction logs consumer
+const driftReferenceRefreshInterval = time.Minute
+
+var featureDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "merlin",
+	Name:      "model_feature_drift",
+	Help:      "Drift of a feature or a prediction output of a model version from its reference distribution, over the drift window",
+}, []string{"project", "model", "version", "feature", "source", "statistic"})
+
+func init() {
+	prometheus.MustRegister(featureDrift)
+}
+
+// DriftService monitors the drift of the features and the predictions logged by the model versions from their reference distributions
+type DriftService interface {
+	// SaveReference registers the reference distributions of a model version, replacing the existing ones
+	SaveReference(ctx context.Context, model *models.Model, version *models.Version, reference *models.DriftReference) (*models.DriftReference, error)
+	// GetReference returns the reference distributions of a model version
+	GetReference(ctx context.Context, modelID models.ID, versionID models.ID) (*models.DriftReference, error)
+	// ListResults lists the drift of a model version computed since the given time, or the latest one if it's zero
+	ListResults(ctx context.Context, modelID models.ID, versionID models.ID, since time.Time) ([]*models.DriftResult, error)
+	// ObservePredictionLog counts the values of the monitored features of a prediction log, the counts are kept in memory until flushed
+	ObservePredictionLog(ctx context.Context, predictionLog *upiv1.PredictionLog) error
+	// Flush stores the counts of the observed values
+	Flush(ctx context.Context) error
+	// ComputeDrift computes the drift of every model version having a reference over the drift window, and exports it to Prometheus
+	ComputeDrift(ctx context.Context) error
+	// DeleteExpired deletes the counts and the results older than the retention
+	DeleteExpired(ctx context.Context) error
+}
+
+type driftBinKey struct {
+	referenceID models.ID
+	source      models.DriftFeatureSource
+	feature     string
+	bin         string
+	bucket      time.Time
+}
+
+type driftService struct {
+	storage storage.DriftStorage
+	config  config.DriftConfig
+	now     func() time.Time
+
+	mu                    sync.Mutex
+	counts                map[driftBinKey]int64
+	references            map[string]*models.DriftReference
+	referencesRefreshedAt time.Time
+}
+
+// NewDriftService creates a new DriftService
+func NewDriftService(storage storage.DriftStorage, config config.DriftConfig) DriftService {
+	return &driftService{
+		storage: storage,
+		config:  config,
+		now:     time.Now,
+		counts:  map[driftBinKey]int64{},
+	}
+}
+
+func (s *driftService) SaveReference(ctx context.Context, model *models.Model, version *models.Version, reference *models.DriftReference) (*models.DriftReference, error) {
+	if err := reference.Validate(); err != nil {
+		return nil, merror.NewInvalidInputErrorf("invalid drift reference: %s", err)
+	}
+
+	reference.ModelID = model.ID
+	reference.VersionID = version.ID
+	reference.ProjectName = model.Project.Name
+	reference.ModelName = model.Name
+	return s.storage.SaveReference(reference)
+}
+
+func (s *driftService) GetReference(ctx context.Context, modelID models.ID, versionID models.ID) (*models.DriftReference, error) {
+	return s.storage.GetReference(modelID, versionID)
+}
+
+func (s *driftService) ListResults(ctx context.Context, modelID models.ID, versionID models.ID, since time.Time) ([]*models.DriftResult, error) {
+	reference, err := s.storage.GetReference(modelID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.ListResults(reference.ID, since)
+}
+
+func (s *driftService) ObservePredictionLog(ctx context.Context, predictionLog *upiv1.PredictionLog) error {
+	reference, err := s.referenceOf(predictionLog)
+	if err != nil || reference == nil {
+		return err
+	}
+
+	bucket := s.now()
+	if predictionLog.GetRequestTimestamp() != nil {
+		bucket = predictionLog.GetRequestTimestamp().AsTime()
+	}
+	bucket = bucket.UTC().Truncate(time.Hour)
+
+	tables := map[models.DriftFeatureSource]*driftTable{
+		models.DriftFeatureSourceFeature:    newDriftTable(predictionLog.GetInput().GetFeaturesTable()),
+		models.DriftFeatureSourcePrediction: newDriftTable(predictionLog.GetOutput().GetPredictionResultsTable()),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, feature := range reference.Features {
+		table := tables[feature.Source]
+		column, ok := table.columns[feature.Name]
+		if !ok {
+			continue
+		}
+		for _, row := range table.rows {
+			if column >= len(row) {
+				continue
+			}
+			if bin, ok := feature.Bin(row[column]); ok {
+				s.counts[driftBinKey{referenceID: reference.ID, source: feature.Source, feature: feature.Name, bin: bin, bucket: bucket}]++
+			}
+		}
+	}
+	return nil
+}
+
+func (s *driftService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.counts
+	s.counts = map[driftBinKey]int64{}
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	counts := make([]*models.DriftBinCount, 0, len(pending))
+	for key, count := range pending {
+		counts = append(counts, &models.DriftBinCount{
+			ReferenceID: key.referenceID,
+			Source:      key.source,
+			Feature:     key.feature,
+			Bin:         key.bin,
+			Bucket:      key.bucket,
+			Count:       count,
+		})
+	}
+	if err := s.storage.AddCounts(counts); err != nil {
+		// keep the counts to store them on the next flush
+		s.mu.Lock()
+		for key, count := range pending {
+			s.counts[key] += count
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *driftService) ComputeDrift(ctx context.Context) error {
+	if err := s.Flush(ctx); err != nil {
+		return err
+	}
+
+	references, err := s.storage.ListReferences()
+	if err != nil {
+		return err
+	}
+	s.setReferences(references)
+
+	windowEnd := s.now().UTC()
+	// the counts are bucketed by hour
+	windowStart := windowEnd.Add(-s.config.Window).Truncate(time.Hour)
+
+	featureDrift.Reset()
+	for _, reference := range references {
+		counts, err := s.storage.SumCounts(reference.ID, windowStart)
+		if err != nil {
+			return err
+		}
+		binCounts := map[string]map[string]int64{}
+		for _, count := range counts {
+			key := string(count.Source) + "/" + count.Feature
+			if binCounts[key] == nil {
+				binCounts[key] = map[string]int64{}
+			}
+			binCounts[key][count.Bin] += count.Count
+		}
+
+		results := make([]*models.DriftResult, 0, len(reference.Features))
+		for _, feature := range reference.Features {
+			result := feature.Drift(binCounts[string(feature.Source)+"/"+feature.Name])
+			result.ReferenceID = reference.ID
+			result.WindowStart = windowStart
+			result.WindowEnd = windowEnd
+			results = append(results, result)
+
+			for statistic, value := range result.Statistics() {
+				featureDrift.WithLabelValues(reference.ProjectName, reference.ModelName, reference.VersionID.String(), feature.Name, string(feature.Source), string(statistic)).Set(value)
+			}
+		}
+		if err := s.storage.SaveResults(results); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *driftService) DeleteExpired(ctx context.Context) error {
+	return s.storage.DeleteBefore(s.now().UTC().Add(-s.config.Retention))
+}
+
+// referenceOf returns the drift reference of the model version of the prediction log, if any
+func (s *driftService) referenceOf(predictionLog *upiv1.PredictionLog) (*models.DriftReference, error) {
+	s.mu.Lock()
+	stale := s.now().Sub(s.referencesRefreshedAt) > driftReferenceRefreshInterval
+	s.mu.Unlock()
+
+	if stale {
+		references, err := s.storage.ListReferences()
+		if err != nil {
+			return nil, fmt.Errorf("unable to list drift references: %w", err)
+		}
+		s.setReferences(references)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.references[driftReferenceKey(predictionLog.GetProjectName(), predictionLog.GetModelName(), predictionLog.GetModelVersion())], nil
+}
+
+func (s *driftService) setReferences(references []*models.DriftReference) {
+	byModelVersion := make(map[string]*models.DriftReference, len(references))
+	for _, reference := range references {
+		byModelVersion[driftReferenceKey(reference.ProjectName, reference.ModelName, reference.VersionID.String())] = reference
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.references = byModelVersion
+	s.referencesRefreshedAt = s.now()
+}
+
+func driftReferenceKey(projectName, modelName, modelVersion string) string {
+	return projectName + "/" + modelName + "/" + modelVersion
+}
+
+// driftTable is a table of a prediction log in the converter.TableSchemaV1 format, whose rows are converted into Go values
+type driftTable struct {
+	columns map[string]int
+	rows    [][]interface{}
+}
+
+func newDriftTable(table *structpb.Struct) *driftTable {
+	t := &driftTable{columns: map[string]int{}}
+	fields := table.GetFields()
+	for i, column := range fields["columns"].GetListValue().GetValues() {
+		t.columns[column.GetStringValue()] = i
+	}
+	for _, row := range fields["data"].GetListValue().GetValues() {
+		t.rows = append(t.rows, row.GetListValue().AsSlice())
+	}
+	return t
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/caraml-dev/universal-prediction-interface/pkg/converter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+var (
+	driftNow    = time.Date(2023, 1, 2, 12, 30, 0, 0, time.UTC)
+	driftConfig = config.DriftConfig{
+		DriftEnabled: true,
+		Window:       24 * time.Hour,
+		Interval:     time.Hour,
+		Retention:    30 * 24 * time.Hour,
+	}
+	driftReference = &models.DriftReference{
+		ID:          1,
+		ModelID:     1,
+		VersionID:   2,
+		ProjectName: "project",
+		ModelName:   "model",
+		Features: models.DriftFeatures{
+			{Name: "age", Source: models.DriftFeatureSourceFeature, Kind: models.DriftFeatureNumeric, Quantiles: []float64{0, 10, 20}},
+			{Name: "city", Source: models.DriftFeatureSourceFeature, Kind: models.DriftFeatureCategorical, Categories: map[string]float64{"a": 0.5, "b": 0.5}},
+			{Name: "score", Source: models.DriftFeatureSourcePrediction, Kind: models.DriftFeatureNumeric, Quantiles: []float64{0, 0.5, 1}},
+		},
+	}
+)
+
+func newTestDriftService(storage *mocks.DriftStorage) *driftService {
+	return &driftService{
+		storage: storage,
+		config:  driftConfig,
+		now:     func() time.Time { return driftNow },
+		counts:  map[driftBinKey]int64{},
+	}
+}
+
+func newDriftPredictionLog(t *testing.T) *upiv1.PredictionLog {
+	features, err := converter.TableToStruct(&upiv1.Table{
+		Name: "features",
+		Columns: []*upiv1.Column{
+			{Name: "age", Type: upiv1.Type_TYPE_INTEGER},
+			{Name: "city", Type: upiv1.Type_TYPE_STRING},
+		},
+		Rows: []*upiv1.Row{
+			{RowId: "1", Values: []*upiv1.Value{{IntegerValue: 5}, {StringValue: "a"}}},
+			{RowId: "2", Values: []*upiv1.Value{{IntegerValue: 15}, {StringValue: "c"}}},
+			{RowId: "3", Values: []*upiv1.Value{{IsNull: true}, {StringValue: "a"}}},
+		},
+	}, converter.TableSchemaV1)
+	require.NoError(t, err)
+	predictions, err := converter.TableToStruct(&upiv1.Table{
+		Name:    "prediction_results",
+		Columns: []*upiv1.Column{{Name: "score", Type: upiv1.Type_TYPE_DOUBLE}},
+		Rows: []*upiv1.Row{
+			{RowId: "1", Values: []*upiv1.Value{{DoubleValue: 0.9}}},
+			{RowId: "2", Values: []*upiv1.Value{{DoubleValue: 0.8}}},
+			{RowId: "3", Values: []*upiv1.Value{{DoubleValue: 0.1}}},
+		},
+	}, converter.TableSchemaV1)
+	require.NoError(t, err)
+
+	return &upiv1.PredictionLog{
+		PredictionId:       "prediction-1",
+		ProjectName:        "project",
+		ModelName:          "model",
+		ModelVersion:       "2",
+		RequestTimestamp:   timestamppb.New(driftNow),
+		Input:              &upiv1.ModelInput{FeaturesTable: features},
+		Output:             &upiv1.ModelOutput{PredictionResultsTable: predictions},
+		TableSchemaVersion: converter.TableSchemaV1,
+	}
+}
+
+func TestDriftService_SaveReference(t *testing.T) {
+	model := &models.Model{ID: 1, Name: "model", Project: mlp.Project{Name: "project"}}
+	version := &models.Version{ID: 2, ModelID: 1}
+
+	t.Run("valid", func(t *testing.T) {
+		storage := &mocks.DriftStorage{}
+		storage.On("SaveReference", mock.Anything).Return(func(reference *models.DriftReference) *models.DriftReference {
+			return reference
+		}, nil)
+
+		reference := &models.DriftReference{Features: driftReference.Features}
+		saved, err := newTestDriftService(storage).SaveReference(context.Background(), model, version, reference)
+		require.NoError(t, err)
+		assert.Equal(t, models.ID(1), saved.ModelID)
+		assert.Equal(t, models.ID(2), saved.VersionID)
+		assert.Equal(t, "project", saved.ProjectName)
+		assert.Equal(t, "model", saved.ModelName)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		storage := &mocks.DriftStorage{}
+
+		_, err := newTestDriftService(storage).SaveReference(context.Background(), model, version, &models.DriftReference{})
+		assert.EqualError(t, err, "invalid input: invalid drift reference: at least one feature is required")
+		storage.AssertNotCalled(t, "SaveReference", mock.Anything)
+	})
+}
+
+func TestDriftService_ObservePredictionLog(t *testing.T) {
+	bucket := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	storage := &mocks.DriftStorage{}
+	storage.On("ListReferences").Return([]*models.DriftReference{driftReference}, nil).Once()
+	storage.On("AddCounts", mock.Anything).Return(nil).Once()
+
+	svc := newTestDriftService(storage)
+	require.NoError(t, svc.ObservePredictionLog(context.Background(), newDriftPredictionLog(t)))
+
+	unmonitored := newDriftPredictionLog(t)
+	unmonitored.ModelVersion = "3"
+	require.NoError(t, svc.ObservePredictionLog(context.Background(), unmonitored))
+
+	require.NoError(t, svc.Flush(context.Background()))
+	counts := storage.Calls[1].Arguments.Get(0).([]*models.DriftBinCount)
+	got := map[string]int64{}
+	for _, count := range counts {
+		assert.Equal(t, models.ID(1), count.ReferenceID)
+		assert.Equal(t, bucket, count.Bucket)
+		got[string(count.Source)+"/"+count.Feature+"/"+count.Bin] = count.Count
+	}
+	assert.Equal(t, map[string]int64{
+		"feature/age/0":          1,
+		"feature/age/1":          1,
+		"feature/city/a":         2,
+		"feature/city/__other__": 1,
+		"prediction/score/0":     1,
+		"prediction/score/1":     2,
+	}, got)
+
+	// nothing is left to flush
+	require.NoError(t, svc.Flush(context.Background()))
+	storage.AssertExpectations(t)
+}
+
+func TestDriftService_Flush_Error(t *testing.T) {
+	storage := &mocks.DriftStorage{}
+	storage.On("ListReferences").Return([]*models.DriftReference{driftReference}, nil).Once()
+	storage.On("AddCounts", mock.Anything).Return(errors.New("db is down")).Once()
+	storage.On("AddCounts", mock.Anything).Return(nil).Once()
+
+	svc := newTestDriftService(storage)
+	require.NoError(t, svc.ObservePredictionLog(context.Background(), newDriftPredictionLog(t)))
+
+	assert.EqualError(t, svc.Flush(context.Background()), "db is down")
+	// the counts are kept for the next flush
+	require.NoError(t, svc.Flush(context.Background()))
+	assert.Len(t, storage.Calls[2].Arguments.Get(0).([]*models.DriftBinCount), 6)
+	storage.AssertExpectations(t)
+}
+
+func TestDriftService_ComputeDrift(t *testing.T) {
+	windowStart := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	storage := &mocks.DriftStorage{}
+	storage.On("ListReferences").Return([]*models.DriftReference{driftReference}, nil)
+	storage.On("SumCounts", models.ID(1), windowStart).Return([]*models.DriftBinCount{
+		{ReferenceID: 1, Source: models.DriftFeatureSourceFeature, Feature: "age", Bin: "0", Count: 50},
+		{ReferenceID: 1, Source: models.DriftFeatureSourceFeature, Feature: "age", Bin: "1", Count: 50},
+		{ReferenceID: 1, Source: models.DriftFeatureSourcePrediction, Feature: "score", Bin: "1", Count: 100},
+	}, nil)
+	storage.On("SaveResults", mock.Anything).Return(nil)
+
+	require.NoError(t, newTestDriftService(storage).ComputeDrift(context.Background()))
+
+	results := storage.Calls[2].Arguments.Get(0).([]*models.DriftResult)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.Equal(t, models.ID(1), result.ReferenceID)
+		assert.Equal(t, windowStart, result.WindowStart)
+		assert.Equal(t, driftNow, result.WindowEnd)
+	}
+
+	assert.Equal(t, "age", results[0].Feature)
+	assert.Equal(t, int64(100), results[0].Observations)
+	require.NotNil(t, results[0].PSI)
+	assert.InDelta(t, 0, *results[0].PSI, 1e-9)
+	assert.InDelta(t, 0, testutil.ToFloat64(featureDrift.WithLabelValues("project", "model", "2", "age", "feature", "psi")), 1e-9)
+
+	// no value of city was logged over the window
+	assert.Equal(t, "city", results[1].Feature)
+	assert.Equal(t, int64(0), results[1].Observations)
+	assert.Nil(t, results[1].PSI)
+
+	assert.Equal(t, "score", results[2].Feature)
+	require.NotNil(t, results[2].PSI)
+	assert.Greater(t, *results[2].PSI, 1.0)
+	assert.InDelta(t, 0.5, *results[2].KS, 1e-9)
+	assert.InDelta(t, *results[2].PSI, testutil.ToFloat64(featureDrift.WithLabelValues("project", "model", "2", "score", "prediction", "psi")), 1e-9)
+	storage.AssertExpectations(t)
+}
+
+func TestDriftService_DeleteExpired(t *testing.T) {
+	storage := &mocks.DriftStorage{}
+	storage.On("DeleteBefore", time.Date(2022, 12, 3, 12, 30, 0, 0, time.UTC)).Return(nil)
+
+	require.NoError(t, newTestDriftService(storage).DeleteExpired(context.Background()))
+	storage.AssertExpectations(t)
+}
@@ -0,0 +1,323 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/caraml-dev/universal-prediction-interface/pkg/converter"
+	"github.com/prometheus/client_golang/prometheus"
+	prommodel "github.com/prometheus/common/model"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+var onlinePerformanceLabels = []string{"project", "model", "version", "window"}
+
+var (
+	onlineAccuracy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "merlin",
+		Name:      "model_online_accuracy",
+		Help:      "Accuracy of the labeled predictions of a binary classifier logged in the window",
+	}, onlinePerformanceLabels)
+	onlineAUC = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "merlin",
+		Name:      "model_online_auc",
+		Help:      "Area under the ROC curve of the labeled predictions of a binary classifier logged in the window",
+	}, onlinePerformanceLabels)
+	onlineRMSE = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "merlin",
+		Name:      "model_online_rmse",
+		Help:      "Root mean squared error of the labeled predictions logged in the window",
+	}, onlinePerformanceLabels)
+	labeledPredictions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "merlin",
+		Name:      "model_labeled_predictions",
+		Help:      "Number of predictions logged in the window which have a label",
+	}, onlinePerformanceLabels)
+)
+
+func init() {
+	prometheus.MustRegister(onlineAccuracy, onlineAUC, onlineRMSE, labeledPredictions)
+}
+
+// GroundTruthService joins the logged predictions with their ground-truth labels and computes the online performance of the model versions
+type GroundTruthService interface {
+	// SavePredictionLog stores the rows of the prediction results of a prediction log
+	SavePredictionLog(ctx context.Context, predictionLog *upiv1.PredictionLog) error
+	// SaveObservationLog stores an observation log as the label of the row of its prediction
+	SaveObservationLog(ctx context.Context, observationLog *upiv1.ObservationLog) error
+	// SaveLabels stores the labels of the predictions
+	SaveLabels(ctx context.Context, labels []*models.PredictionLabel) error
+	// GetPerformance computes the online performance of the versions of a model over the window
+	GetPerformance(ctx context.Context, model *models.Model, versionIDs []models.ID, window time.Duration) ([]*models.OnlinePerformance, error)
+	// ExportMetrics exports the online performance of the model versions which logged predictions, over each of the configured windows, to Prometheus
+	ExportMetrics(ctx context.Context) error
+	// DeleteExpired deletes the predictions and labels older than the retention
+	DeleteExpired(ctx context.Context) error
+}
+
+type groundTruthService struct {
+	storage storage.GroundTruthStorage
+	config  config.GroundTruthConfig
+	now     func() time.Time
+
+	mu sync.Mutex
+	// exported are the label sets of the last export of the metrics, keyed by their joined values
+	exported map[string]prometheus.Labels
+}
+
+// NewGroundTruthService creates a new GroundTruthService
+func NewGroundTruthService(storage storage.GroundTruthStorage, config config.GroundTruthConfig) GroundTruthService {
+	return &groundTruthService{
+		storage: storage,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+func (s *groundTruthService) SavePredictionLog(ctx context.Context, predictionLog *upiv1.PredictionLog) error {
+	predictions, err := loggedPredictions(predictionLog)
+	if err != nil {
+		return fmt.Errorf("invalid prediction log %s: %w", predictionLog.GetPredictionId(), err)
+	}
+	if len(predictions) == 0 {
+		return nil
+	}
+	return s.storage.SavePredictions(predictions)
+}
+
+func (s *groundTruthService) SaveObservationLog(ctx context.Context, observationLog *upiv1.ObservationLog) error {
+	value, err := observedValue(observationLog)
+	if err != nil {
+		return fmt.Errorf("invalid observation log of prediction %s: %w", observationLog.GetPredictionId(), err)
+	}
+
+	observedAt := s.now()
+	if observationLog.GetObservationTimestamp() != nil {
+		observedAt = observationLog.GetObservationTimestamp().AsTime()
+	}
+	return s.SaveLabels(ctx, []*models.PredictionLabel{{
+		PredictionID: observationLog.GetPredictionId(),
+		RowID:        observationLog.GetRowId(),
+		Value:        value,
+		ObservedAt:   observedAt,
+	}})
+}
+
+func (s *groundTruthService) SaveLabels(ctx context.Context, labels []*models.PredictionLabel) error {
+	for i, label := range labels {
+		if err := label.Validate(); err != nil {
+			return merror.NewInvalidInputErrorf("invalid label %d: %s", i, err)
+		}
+		if label.ObservedAt.IsZero() {
+			label.ObservedAt = s.now()
+		}
+	}
+	return s.storage.SaveLabels(labels)
+}
+
+func (s *groundTruthService) GetPerformance(ctx context.Context, model *models.Model, versionIDs []models.ID, window time.Duration) ([]*models.OnlinePerformance, error) {
+	performances := make([]*models.OnlinePerformance, 0, len(versionIDs))
+	for _, versionID := range versionIDs {
+		performance, err := s.computePerformance(models.PredictionModelVersion{
+			ProjectName:  model.Project.Name,
+			ModelName:    model.Name,
+			ModelVersion: versionID.String(),
+		}, window)
+		if err != nil {
+			return nil, err
+		}
+		performance.VersionID = versionID
+		performances = append(performances, performance)
+	}
+	return performances, nil
+}
+
+func (s *groundTruthService) ExportMetrics(ctx context.Context) error {
+	maxWindow := time.Duration(0)
+	for _, window := range s.config.Windows {
+		if window > maxWindow {
+			maxWindow = window
+		}
+	}
+	modelVersions, err := s.storage.ListModelVersions(s.now().Add(-maxWindow))
+	if err != nil {
+		return err
+	}
+
+	// the performances are computed before any metric is updated, so that the metrics are never partially exported
+	performances := map[string]*models.OnlinePerformance{}
+	exported := map[string]prometheus.Labels{}
+	for _, modelVersion := range modelVersions {
+		for _, window := range s.config.Windows {
+			performance, err := s.computePerformance(*modelVersion, window)
+			if err != nil {
+				return err
+			}
+
+			key := strings.Join([]string{modelVersion.ProjectName, modelVersion.ModelName, modelVersion.ModelVersion, performance.Window}, "/")
+			performances[key] = performance
+			exported[key] = prometheus.Labels{
+				"project": modelVersion.ProjectName,
+				"model":   modelVersion.ModelName,
+				"version": modelVersion.ModelVersion,
+				"window":  performance.Window,
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, labels := range exported {
+		performance := performances[key]
+		labeledPredictions.With(labels).Set(float64(performance.LabeledPredictions))
+		setOrDelete(onlineAccuracy, labels, performance.Accuracy)
+		setOrDelete(onlineAUC, labels, performance.AUC)
+		setOrDelete(onlineRMSE, labels, performance.RMSE)
+	}
+	// model versions which stopped logging predictions are no longer exported
+	for key, labels := range s.exported {
+		if _, ok := exported[key]; !ok {
+			for _, gauge := range []*prometheus.GaugeVec{onlineAccuracy, onlineAUC, onlineRMSE, labeledPredictions} {
+				gauge.Delete(labels)
+			}
+		}
+	}
+	s.exported = exported
+	return nil
+}
+
+// setOrDelete sets the metric of the labels to the value, or deletes it if there's no value
+func setOrDelete(gauge *prometheus.GaugeVec, labels prometheus.Labels, value *float64) {
+	if value == nil {
+		gauge.Delete(labels)
+		return
+	}
+	gauge.With(labels).Set(*value)
+}
+
+func (s *groundTruthService) DeleteExpired(ctx context.Context) error {
+	return s.storage.DeleteBefore(s.now().Add(-s.config.Retention))
+}
+
+func (s *groundTruthService) computePerformance(modelVersion models.PredictionModelVersion, window time.Duration) (*models.OnlinePerformance, error) {
+	since := s.now().Add(-window)
+	predictions, err := s.storage.CountPredictions(modelVersion, since)
+	if err != nil {
+		return nil, err
+	}
+	outcomes, err := s.storage.ListOutcomes(modelVersion, since)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewOnlinePerformance(0, prommodel.Duration(window).String(), predictions, outcomes), nil
+}
+
+// loggedPredictions returns the predicted target of each row of the prediction results table of the prediction log,
+// the target is the column named after the target name of the prediction or, if there's none, the first numeric column
+func loggedPredictions(predictionLog *upiv1.PredictionLog) ([]*models.LoggedPrediction, error) {
+	table := predictionLog.GetOutput().GetPredictionResultsTable()
+	if table == nil {
+		return nil, nil
+	}
+	if predictionLog.GetTableSchemaVersion() != converter.TableSchemaV1 {
+		return nil, fmt.Errorf("unsupported table schema version %d", predictionLog.GetTableSchemaVersion())
+	}
+	if predictionLog.GetPredictionId() == "" {
+		return nil, fmt.Errorf("prediction id is not set")
+	}
+
+	fields := table.GetFields()
+	columns := fields["columns"].GetListValue().GetValues()
+	columnTypes := fields["column_types"].GetListValue().GetValues()
+	target := -1
+	for i, column := range columns {
+		if column.GetStringValue() == predictionLog.GetTargetName() {
+			target = i
+			break
+		}
+	}
+	for i := 0; target < 0 && i < len(columnTypes); i++ {
+		if columnType := columnTypes[i].GetStringValue(); columnType == "FLOAT" || columnType == "INTEGER" {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("prediction results table doesn't have a numeric column")
+	}
+
+	rowIDs := fields["row_ids"].GetListValue().GetValues()
+	predictions := make([]*models.LoggedPrediction, 0)
+	for i, row := range fields["data"].GetListValue().GetValues() {
+		values := row.GetListValue().GetValues()
+		if target >= len(values) {
+			continue
+		}
+		// null predictions can't be compared with their label
+		value, ok := values[target].GetKind().(*structpb.Value_NumberValue)
+		if !ok {
+			continue
+		}
+
+		rowID := ""
+		if i < len(rowIDs) {
+			rowID = rowIDs[i].GetStringValue()
+		}
+		predictions = append(predictions, &models.LoggedPrediction{
+			ProjectName:      predictionLog.GetProjectName(),
+			ModelName:        predictionLog.GetModelName(),
+			ModelVersion:     predictionLog.GetModelVersion(),
+			PredictionID:     predictionLog.GetPredictionId(),
+			RowID:            rowID,
+			Value:            value.NumberValue,
+			RequestTimestamp: predictionLog.GetRequestTimestamp().AsTime(),
+		})
+	}
+	return predictions, nil
+}
+
+// observedValue returns the observed value named after the target name of the observation or, if there's none, the first one
+func observedValue(observationLog *upiv1.ObservationLog) (float64, error) {
+	values := observationLog.GetObservationValues()
+	if len(values) == 0 {
+		return 0, fmt.Errorf("observation values are not set")
+	}
+
+	observed := values[0]
+	for _, value := range values {
+		if value.GetName() == observationLog.GetTargetName() {
+			observed = value
+			break
+		}
+	}
+	switch observed.GetType() {
+	case upiv1.Type_TYPE_DOUBLE:
+		return observed.GetDoubleValue(), nil
+	case upiv1.Type_TYPE_INTEGER:
+		return float64(observed.GetIntegerValue()), nil
+	default:
+		return 0, fmt.Errorf("observation value %s must be numeric", observed.GetName())
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	upiv1 "github.com/caraml-dev/universal-prediction-interface/gen/go/grpc/caraml/upi/v1"
+	"github.com/caraml-dev/universal-prediction-interface/pkg/converter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+var (
+	groundTruthNow    = time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	groundTruthConfig = config.GroundTruthConfig{
+		GroundTruthEnabled: true,
+		Windows:            []time.Duration{time.Hour, 24 * time.Hour},
+		Retention:          7 * 24 * time.Hour,
+	}
+)
+
+func newTestGroundTruthService(storage *mocks.GroundTruthStorage) *groundTruthService {
+	return &groundTruthService{
+		storage: storage,
+		config:  groundTruthConfig,
+		now:     func() time.Time { return groundTruthNow },
+	}
+}
+
+func newPredictionResultsLog(t *testing.T, targetName string, columns []*upiv1.Column, rows ...*upiv1.Row) *upiv1.PredictionLog {
+	table, err := converter.TableToStruct(&upiv1.Table{Name: "prediction_results", Columns: columns, Rows: rows}, converter.TableSchemaV1)
+	require.NoError(t, err)
+	return &upiv1.PredictionLog{
+		PredictionId:       "prediction-1",
+		TargetName:         targetName,
+		ProjectName:        "project",
+		ModelName:          "model",
+		ModelVersion:       "1",
+		RequestTimestamp:   timestamppb.New(groundTruthNow),
+		Output:             &upiv1.ModelOutput{PredictionResultsTable: table},
+		TableSchemaVersion: converter.TableSchemaV1,
+	}
+}
+
+func TestGroundTruthService_SavePredictionLog(t *testing.T) {
+	columns := []*upiv1.Column{
+		{Name: "label", Type: upiv1.Type_TYPE_STRING},
+		{Name: "score", Type: upiv1.Type_TYPE_DOUBLE},
+		{Name: "probability", Type: upiv1.Type_TYPE_DOUBLE},
+	}
+	rows := []*upiv1.Row{
+		{RowId: "1", Values: []*upiv1.Value{{StringValue: "a"}, {DoubleValue: 0.1}, {DoubleValue: 0.9}}},
+		{RowId: "2", Values: []*upiv1.Value{{StringValue: "b"}, {DoubleValue: 0.2}, {IsNull: true}}},
+	}
+
+	tests := []struct {
+		name          string
+		predictionLog *upiv1.PredictionLog
+		want          []*models.LoggedPrediction
+		wantErr       string
+	}{
+		{
+			name:          "target column",
+			predictionLog: newPredictionResultsLog(t, "probability", columns, rows...),
+			want: []*models.LoggedPrediction{
+				{ProjectName: "project", ModelName: "model", ModelVersion: "1", PredictionID: "prediction-1", RowID: "1", Value: 0.9, RequestTimestamp: groundTruthNow},
+			},
+		},
+		{
+			name:          "first numeric column",
+			predictionLog: newPredictionResultsLog(t, "", columns, rows...),
+			want: []*models.LoggedPrediction{
+				{ProjectName: "project", ModelName: "model", ModelVersion: "1", PredictionID: "prediction-1", RowID: "1", Value: 0.1, RequestTimestamp: groundTruthNow},
+				{ProjectName: "project", ModelName: "model", ModelVersion: "1", PredictionID: "prediction-1", RowID: "2", Value: 0.2, RequestTimestamp: groundTruthNow},
+			},
+		},
+		{
+			name: "no prediction results",
+			predictionLog: &upiv1.PredictionLog{
+				PredictionId: "prediction-1",
+				Output:       &upiv1.ModelOutput{},
+			},
+		},
+		{
+			name:          "no numeric column",
+			predictionLog: newPredictionResultsLog(t, "", columns[:1], &upiv1.Row{RowId: "1", Values: []*upiv1.Value{{StringValue: "a"}}}),
+			wantErr:       "invalid prediction log prediction-1: prediction results table doesn't have a numeric column",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := mocks.NewGroundTruthStorage(t)
+			if tt.want != nil {
+				storage.On("SavePredictions", tt.want).Return(nil)
+			}
+
+			err := newTestGroundTruthService(storage).SavePredictionLog(context.Background(), tt.predictionLog)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGroundTruthService_SaveObservationLog(t *testing.T) {
+	observedAt := groundTruthNow.Add(time.Hour)
+	tests := []struct {
+		name           string
+		observationLog *upiv1.ObservationLog
+		want           *models.PredictionLabel
+		wantErr        string
+	}{
+		{
+			name: "target variable",
+			observationLog: &upiv1.ObservationLog{
+				PredictionId: "prediction-1",
+				RowId:        "1",
+				TargetName:   "converted",
+				ObservationValues: []*upiv1.Variable{
+					{Name: "revenue", Type: upiv1.Type_TYPE_DOUBLE, DoubleValue: 100},
+					{Name: "converted", Type: upiv1.Type_TYPE_INTEGER, IntegerValue: 1},
+				},
+				ObservationTimestamp: timestamppb.New(observedAt),
+			},
+			want: &models.PredictionLabel{PredictionID: "prediction-1", RowID: "1", Value: 1, ObservedAt: observedAt},
+		},
+		{
+			name: "first variable without observation timestamp",
+			observationLog: &upiv1.ObservationLog{
+				PredictionId:      "prediction-1",
+				ObservationValues: []*upiv1.Variable{{Name: "revenue", Type: upiv1.Type_TYPE_DOUBLE, DoubleValue: 100}},
+			},
+			want: &models.PredictionLabel{PredictionID: "prediction-1", Value: 100, ObservedAt: groundTruthNow},
+		},
+		{
+			name: "non numeric variable",
+			observationLog: &upiv1.ObservationLog{
+				PredictionId:      "prediction-1",
+				ObservationValues: []*upiv1.Variable{{Name: "label", Type: upiv1.Type_TYPE_STRING, StringValue: "a"}},
+			},
+			wantErr: "invalid observation log of prediction prediction-1: observation value label must be numeric",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := mocks.NewGroundTruthStorage(t)
+			if tt.want != nil {
+				storage.On("SaveLabels", []*models.PredictionLabel{tt.want}).Return(nil)
+			}
+
+			err := newTestGroundTruthService(storage).SaveObservationLog(context.Background(), tt.observationLog)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGroundTruthService_SaveLabels(t *testing.T) {
+	storage := mocks.NewGroundTruthStorage(t)
+	svc := newTestGroundTruthService(storage)
+
+	err := svc.SaveLabels(context.Background(), []*models.PredictionLabel{{PredictionID: "prediction-1", Value: 1}, {Value: 0}})
+	assert.EqualError(t, err, "invalid input: invalid label 1: prediction_id is required")
+
+	storage.On("SaveLabels", []*models.PredictionLabel{{PredictionID: "prediction-1", Value: 1, ObservedAt: groundTruthNow}}).Return(nil)
+	err = svc.SaveLabels(context.Background(), []*models.PredictionLabel{{PredictionID: "prediction-1", Value: 1}})
+	assert.NoError(t, err)
+}
+
+func TestGroundTruthService_GetPerformance(t *testing.T) {
+	model := &models.Model{Name: "model", Project: mlp.Project{Name: "project"}}
+	since := groundTruthNow.Add(-time.Hour)
+
+	storage := mocks.NewGroundTruthStorage(t)
+	version1 := models.PredictionModelVersion{ProjectName: "project", ModelName: "model", ModelVersion: "1"}
+	storage.On("CountPredictions", version1, since).Return(3, nil)
+	storage.On("ListOutcomes", version1, since).Return([]*models.PredictionOutcome{
+		{Prediction: 0.9, Label: 1},
+		{Prediction: 0.2, Label: 0},
+	}, nil)
+	version2 := models.PredictionModelVersion{ProjectName: "project", ModelName: "model", ModelVersion: "2"}
+	storage.On("CountPredictions", version2, since).Return(0, nil)
+	storage.On("ListOutcomes", version2, since).Return([]*models.PredictionOutcome{}, nil)
+
+	performances, err := newTestGroundTruthService(storage).GetPerformance(context.Background(), model, []models.ID{1, 2}, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, performances, 2)
+
+	assert.Equal(t, models.ID(1), performances[0].VersionID)
+	assert.Equal(t, "1h", performances[0].Window)
+	assert.Equal(t, 3, performances[0].Predictions)
+	assert.Equal(t, 2, performances[0].LabeledPredictions)
+	require.NotNil(t, performances[0].Accuracy)
+	assert.Equal(t, 1.0, *performances[0].Accuracy)
+
+	assert.Equal(t, models.ID(2), performances[1].VersionID)
+	assert.Equal(t, 0, performances[1].LabeledPredictions)
+	assert.Nil(t, performances[1].RMSE)
+}
+
+func TestGroundTruthService_ExportMetrics(t *testing.T) {
+	storage := mocks.NewGroundTruthStorage(t)
+	version := &models.PredictionModelVersion{ProjectName: "project", ModelName: "model", ModelVersion: "1"}
+	storage.On("ListModelVersions", groundTruthNow.Add(-24*time.Hour)).Return([]*models.PredictionModelVersion{version}, nil)
+	storage.On("CountPredictions", *version, mock.Anything).Return(2, nil)
+	storage.On("ListOutcomes", *version, groundTruthNow.Add(-time.Hour)).Return([]*models.PredictionOutcome{
+		{Prediction: 1.5, Label: 2},
+	}, nil)
+	storage.On("ListOutcomes", *version, groundTruthNow.Add(-24*time.Hour)).Return([]*models.PredictionOutcome{
+		{Prediction: 1.5, Label: 2},
+		{Prediction: 2.5, Label: 3},
+	}, nil)
+
+	err := newTestGroundTruthService(storage).ExportMetrics(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(labeledPredictions.WithLabelValues("project", "model", "1", "1h")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(labeledPredictions.WithLabelValues("project", "model", "1", "1d")))
+	assert.Equal(t, 0.5, testutil.ToFloat64(onlineRMSE.WithLabelValues("project", "model", "1", "1h")))
+	assert.Equal(t, 0.5, testutil.ToFloat64(onlineRMSE.WithLabelValues("project", "model", "1", "1d")))
+	// the labels aren't binary, hence there's no classification metric
+	assert.Equal(t, 0, testutil.CollectAndCount(onlineAccuracy))
+}
+
+func TestGroundTruthService_ExportMetrics_StaleVersions(t *testing.T) {
+	storage := mocks.NewGroundTruthStorage(t)
+	version := &models.PredictionModelVersion{ProjectName: "project", ModelName: "model", ModelVersion: "2"}
+	storage.On("ListModelVersions", mock.Anything).Return([]*models.PredictionModelVersion{version}, nil).Once()
+	storage.On("CountPredictions", *version, mock.Anything).Return(1, nil)
+	storage.On("ListOutcomes", *version, mock.Anything).Return([]*models.PredictionOutcome{{Prediction: 1, Label: 1}}, nil)
+
+	svc := newTestGroundTruthService(storage)
+	require.NoError(t, svc.ExportMetrics(context.Background()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(onlineAccuracy.WithLabelValues("project", "model", "2", "1h")))
+
+	// the metrics are kept if they can't be computed
+	storage.On("ListModelVersions", mock.Anything).Return(nil, errors.New("db is down")).Once()
+	assert.Error(t, svc.ExportMetrics(context.Background()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(onlineAccuracy.WithLabelValues("project", "model", "2", "1h")))
+
+	// the metrics of the version which stopped logging predictions are deleted
+	storage.On("ListModelVersions", mock.Anything).Return([]*models.PredictionModelVersion{}, nil).Once()
+	require.NoError(t, svc.ExportMetrics(context.Background()))
+	assert.False(t, onlineAccuracy.Delete(prometheus.Labels{"project": "project", "model": "model", "version": "2", "window": "1h"}))
+	assert.False(t, labeledPredictions.Delete(prometheus.Labels{"project": "project", "model": "model", "version": "2", "window": "1d"}))
+}
+
+func TestGroundTruthService_DeleteExpired(t *testing.T) {
+	storage := mocks.NewGroundTruthStorage(t)
+	storage.On("DeleteBefore", groundTruthNow.Add(-7*24*time.Hour)).Return(nil)
+
+	err := newTestGroundTruthService(storage).DeleteExpired(context.Background())
+	assert.NoError(t, err)
+}
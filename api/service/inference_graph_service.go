@@ -0,0 +1,226 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caraml-dev/merlin/cluster"
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/pkg/protocol"
+	"github.com/caraml-dev/merlin/queue"
+	"github.com/caraml-dev/merlin/queue/work"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+const InferenceGraphDeployment = "inference_graph_deployment"
+
+// InferenceGraphService manages inference graphs which chain or fan out requests across version endpoints
+type InferenceGraphService interface {
+	// ListInferenceGraphs list all inference graphs of a project
+	ListInferenceGraphs(ctx context.Context, projectID models.ID) ([]*models.InferenceGraph, error)
+	// FindByID find inference graph of a project given its ID
+	FindByID(ctx context.Context, projectID models.ID, id models.ID) (*models.InferenceGraph, error)
+	// DeployInferenceGraph create or update an inference graph, deliver its alert, and deploy it asynchronously
+	DeployInferenceGraph(ctx context.Context, user string, project mlp.Project, graph *models.InferenceGraph) (*models.InferenceGraph, error)
+	// UndeployInferenceGraph delete the deployed inference graph from the cluster and its delivered alert
+	UndeployInferenceGraph(ctx context.Context, user string, project mlp.Project, graph *models.InferenceGraph) (*models.InferenceGraph, error)
+}
+
+type inferenceGraphService struct {
+	clusterControllers     map[string]cluster.Controller
+	storage                storage.InferenceGraphStorage
+	versionEndpointStorage storage.VersionEndpointStorage
+	jobProducer            queue.Producer
+	alertService           ModelEndpointAlertService
+}
+
+// NewInferenceGraphService creates a new InferenceGraphService
+func NewInferenceGraphService(clusterControllers map[string]cluster.Controller, storage storage.InferenceGraphStorage, versionEndpointStorage storage.VersionEndpointStorage, jobProducer queue.Producer, alertService ModelEndpointAlertService) InferenceGraphService {
+	return &inferenceGraphService{
+		clusterControllers:     clusterControllers,
+		storage:                storage,
+		versionEndpointStorage: versionEndpointStorage,
+		jobProducer:            jobProducer,
+		alertService:           alertService,
+	}
+}
+
+func (s *inferenceGraphService) ListInferenceGraphs(ctx context.Context, projectID models.ID) ([]*models.InferenceGraph, error) {
+	return s.storage.List(projectID)
+}
+
+func (s *inferenceGraphService) FindByID(ctx context.Context, projectID models.ID, id models.ID) (*models.InferenceGraph, error) {
+	return s.storage.Get(projectID, id)
+}
+
+func (s *inferenceGraphService) DeployInferenceGraph(ctx context.Context, user string, project mlp.Project, graph *models.InferenceGraph) (*models.InferenceGraph, error) {
+	if err := graph.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+
+	ctl, ok := s.clusterControllers[graph.EnvironmentName]
+	if !ok {
+		return nil, merror.NewInvalidInputErrorf("unable to find cluster controller for environment %s", graph.EnvironmentName)
+	}
+	if err := ctl.ValidateInferenceGraph(); err != nil {
+		return nil, merror.NewInvalidInputErrorf("unable to deploy inference graph to environment %s: %s", graph.EnvironmentName, err)
+	}
+
+	if err := s.validateVersionEndpoints(graph); err != nil {
+		return nil, err
+	}
+
+	var existing *models.InferenceGraph
+	if graph.ID != 0 {
+		var err error
+		existing, err = s.storage.Get(graph.ProjectID, graph.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.applyAlert(user, project, graph, existing); err != nil {
+		return nil, err
+	}
+
+	graph.Status = models.EndpointPending
+	graph.SetMessage("")
+	if err := s.storage.Save(graph); err != nil {
+		s.rollbackAlert(user, project, graph, existing)
+		return nil, err
+	}
+
+	if err := s.jobProducer.EnqueueJob(&queue.Job{
+		Name: InferenceGraphDeployment,
+		Arguments: queue.Arguments{
+			dataArgKey: work.InferenceGraphJob{
+				Graph:   graph,
+				Project: project,
+			},
+		},
+	}); err != nil {
+		// if error enqueue job, restore the previous alert and mark inference graph status to failed
+		s.rollbackAlert(user, project, graph, existing)
+		graph.Alert = nil
+		if alertDelivered(existing) {
+			graph.Alert = existing.Alert
+		}
+		graph.Status = models.EndpointFailed
+		if err := s.storage.Save(graph); err != nil {
+			log.Errorf("error to update inference graph %s status to failed: %v", graph.Name, err)
+		}
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// applyAlert delivers the alert of the graph, or deletes the delivered alert of the existing graph when the graph
+// no longer has an alert. The rules file of a delivered alert is updated instead of created.
+func (s *inferenceGraphService) applyAlert(user string, project mlp.Project, graph *models.InferenceGraph, existing *models.InferenceGraph) error {
+	delivered := alertDelivered(existing)
+	if graph.Alert == nil {
+		if !delivered || s.alertService == nil {
+			return nil
+		}
+		return s.alertService.DeleteInferenceGraphAlert(user, project, existing)
+	}
+	if s.alertService == nil {
+		return merror.NewInvalidInputErrorf("alerting is not enabled")
+	}
+
+	return s.alertService.ApplyInferenceGraphAlert(user, project, graph, delivered)
+}
+
+// rollbackAlert restores the delivered alert of the existing graph after the graph failed to be deployed,
+// or deletes the alert delivered for a graph that had none.
+func (s *inferenceGraphService) rollbackAlert(user string, project mlp.Project, graph *models.InferenceGraph, existing *models.InferenceGraph) {
+	if s.alertService == nil {
+		return
+	}
+
+	var err error
+	switch {
+	case alertDelivered(existing):
+		err = s.alertService.ApplyInferenceGraphAlert(user, project, existing, graph.Alert != nil)
+	case graph.Alert != nil:
+		err = s.alertService.DeleteInferenceGraphAlert(user, project, graph)
+	}
+	if err != nil {
+		log.Errorf("error to roll back alert of inference graph %s: %v", graph.Name, err)
+	}
+}
+
+// alertDelivered returns whether the alert of the saved graph is delivered. The alert of an undeployed graph is deleted.
+func alertDelivered(graph *models.InferenceGraph) bool {
+	return graph != nil && graph.Alert != nil && graph.Status != models.EndpointTerminated
+}
+
+// validateVersionEndpoints ensures all version endpoints used by the graph are active HTTP endpoints
+// of the same project and environment as the graph
+func (s *inferenceGraphService) validateVersionEndpoints(graph *models.InferenceGraph) error {
+	activeEndpoints, err := s.versionEndpointStorage.ListActiveEndpointsInProject(graph.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	endpoints := map[string]*models.VersionEndpoint{}
+	for _, endpoint := range activeEndpoints {
+		endpoints[endpoint.ID.String()] = endpoint
+	}
+
+	for _, id := range graph.VersionEndpointIDs() {
+		endpoint, ok := endpoints[id.String()]
+		if !ok || (endpoint.Status != models.EndpointRunning && endpoint.Status != models.EndpointServing) {
+			return merror.NewInvalidInputErrorf("version endpoint %s is not running in the project", id)
+		}
+		if endpoint.EnvironmentName != graph.EnvironmentName {
+			return merror.NewInvalidInputErrorf("version endpoint %s is not deployed in environment %s", id, graph.EnvironmentName)
+		}
+		if endpoint.Protocol == protocol.UpiV1 {
+			return merror.NewInvalidInputErrorf("version endpoint %s uses %s protocol which is not supported by inference graph", id, endpoint.Protocol)
+		}
+	}
+	return nil
+}
+
+func (s *inferenceGraphService) UndeployInferenceGraph(ctx context.Context, user string, project mlp.Project, graph *models.InferenceGraph) (*models.InferenceGraph, error) {
+	ctl, ok := s.clusterControllers[graph.EnvironmentName]
+	if !ok {
+		return nil, fmt.Errorf("unable to find cluster controller for environment %s", graph.EnvironmentName)
+	}
+
+	if err := ctl.DeleteInferenceGraph(ctx, project.Name, graph.Name); err != nil {
+		return nil, err
+	}
+
+	if alertDelivered(graph) && s.alertService != nil {
+		if err := s.alertService.DeleteInferenceGraphAlert(user, project, graph); err != nil {
+			return nil, err
+		}
+	}
+
+	graph.Status = models.EndpointTerminated
+	graph.URL = ""
+	if err := s.storage.Save(graph); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
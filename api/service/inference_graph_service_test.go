@@ -0,0 +1,274 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/cluster"
+	clusterMock "github.com/caraml-dev/merlin/cluster/mocks"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/pkg/protocol"
+	queueMock "github.com/caraml-dev/merlin/queue/mocks"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func TestInferenceGraphService_DeployInferenceGraph(t *testing.T) {
+	project := mlp.Project{ID: 1, Name: "project"}
+	endpointID := uuid.New()
+
+	newGraph := func() *models.InferenceGraph {
+		return &models.InferenceGraph{
+			ProjectID: models.ID(1),
+			Name:      "my-graph",
+			Nodes: models.InferenceGraphNodes{
+				models.InferenceGraphRootNode: {
+					RouterType: models.InferenceGraphSequence,
+					Steps:      []*models.InferenceGraphStep{{VersionEndpointID: &endpointID}},
+				},
+			},
+			Environment:     &models.Environment{Name: "env1", Cluster: "cluster1"},
+			EnvironmentName: "env1",
+		}
+	}
+	newGraphWithAlert := func(id models.ID) *models.InferenceGraph {
+		graph := newGraph()
+		graph.ID = id
+		graph.Alert = &models.InferenceGraphAlert{
+			TeamName: "team-1",
+			AlertConditions: models.AlertConditions{
+				&models.AlertCondition{
+					Enabled:    true,
+					MetricType: models.AlertConditionTypeThroughput,
+					Severity:   models.AlertConditionSeverityWarning,
+					Target:     1,
+				},
+			},
+		}
+		return graph
+	}
+	runningEndpoints := []*models.VersionEndpoint{{ID: endpointID, Status: models.EndpointRunning, EnvironmentName: "env1", Protocol: protocol.HttpJson}}
+
+	alertFileName := "alerts/merlin/project/my-graph-graph_env1.yaml"
+
+	testCases := []struct {
+		desc            string
+		graph           *models.InferenceGraph
+		activeEndpoints []*models.VersionEndpoint
+		existingGraph   *models.InferenceGraph
+		alerting        bool
+		errValidate     error
+		errSave         error
+		errEnqueue      error
+		expectedStatus  models.EndpointStatus
+		expectedError   string
+		invalidInput    bool
+		expectedAlert   bool
+	}{
+		{
+			desc:            "Should save graph and enqueue deployment",
+			graph:           newGraph(),
+			activeEndpoints: []*models.VersionEndpoint{{ID: endpointID, Status: models.EndpointRunning, EnvironmentName: "env1", Protocol: protocol.HttpJson}},
+			expectedStatus:  models.EndpointPending,
+		},
+		{
+			desc:            "Should deliver alert of graph",
+			graph:           newGraphWithAlert(0),
+			activeEndpoints: runningEndpoints,
+			alerting:        true,
+			expectedStatus:  models.EndpointPending,
+			expectedAlert:   true,
+		},
+		{
+			desc:            "Should update delivered alert of existing graph",
+			graph:           newGraphWithAlert(1),
+			activeEndpoints: runningEndpoints,
+			existingGraph:   newGraphWithAlert(1),
+			alerting:        true,
+			expectedStatus:  models.EndpointPending,
+			expectedAlert:   true,
+		},
+		{
+			desc: "Should delete delivered alert of existing graph without alert",
+			graph: func() *models.InferenceGraph {
+				graph := newGraph()
+				graph.ID = 1
+				return graph
+			}(),
+			activeEndpoints: runningEndpoints,
+			existingGraph:   newGraphWithAlert(1),
+			alerting:        true,
+			expectedStatus:  models.EndpointPending,
+		},
+		{
+			desc:            "Should delete delivered alert if graph can't be saved",
+			graph:           newGraphWithAlert(0),
+			activeEndpoints: runningEndpoints,
+			alerting:        true,
+			errSave:         errors.New("db is down"),
+			expectedError:   "db is down",
+		},
+		{
+			desc:            "Should restore alert of existing graph if deployment can't be enqueued",
+			graph:           newGraph(),
+			activeEndpoints: runningEndpoints,
+			existingGraph:   newGraphWithAlert(0),
+			alerting:        true,
+			errEnqueue:      errors.New("queue is down"),
+			expectedStatus:  models.EndpointFailed,
+			expectedError:   "queue is down",
+			expectedAlert:   true,
+		},
+		{
+			desc:            "Should fail if graph has alert but alerting is not enabled",
+			graph:           newGraphWithAlert(0),
+			activeEndpoints: runningEndpoints,
+			expectedError:   "alerting is not enabled",
+			invalidInput:    true,
+		},
+		{
+			desc:          "Should fail if KServe of environment doesn't support inference graph",
+			graph:         newGraph(),
+			errValidate:   errors.New("inference graph requires KServe 0.10.0 or newer"),
+			expectedError: "unable to deploy inference graph to environment env1",
+			invalidInput:  true,
+		},
+		{
+			desc:          "Should fail if graph is invalid",
+			graph:         &models.InferenceGraph{Name: "my-graph", EnvironmentName: "env1"},
+			expectedError: `inference graph must have a "root" node`,
+			invalidInput:  true,
+		},
+		{
+			desc:            "Should fail if version endpoint is not running",
+			graph:           newGraph(),
+			activeEndpoints: []*models.VersionEndpoint{{ID: endpointID, Status: models.EndpointPending, EnvironmentName: "env1"}},
+			expectedError:   "is not running in the project",
+			invalidInput:    true,
+		},
+		{
+			desc:            "Should fail if version endpoint is in another environment",
+			graph:           newGraph(),
+			activeEndpoints: []*models.VersionEndpoint{{ID: endpointID, Status: models.EndpointServing, EnvironmentName: "env2"}},
+			expectedError:   "is not deployed in environment env1",
+			invalidInput:    true,
+		},
+		{
+			desc:            "Should fail if version endpoint uses UPI",
+			graph:           newGraph(),
+			activeEndpoints: []*models.VersionEndpoint{{ID: endpointID, Status: models.EndpointRunning, EnvironmentName: "env1", Protocol: protocol.UpiV1}},
+			expectedError:   "protocol which is not supported by inference graph",
+			invalidInput:    true,
+		},
+		{
+			desc:            "Should mark graph as failed if deployment can't be enqueued",
+			graph:           newGraph(),
+			activeEndpoints: []*models.VersionEndpoint{{ID: endpointID, Status: models.EndpointRunning, EnvironmentName: "env1"}},
+			errEnqueue:      errors.New("queue is down"),
+			expectedStatus:  models.EndpointFailed,
+			expectedError:   "queue is down",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			graphStorage := &mocks.InferenceGraphStorage{}
+			graphStorage.On("Save", mock.Anything).Return(tC.errSave)
+			if tC.existingGraph != nil {
+				tC.graph.ID = 1
+				tC.existingGraph.ID = 1
+				graphStorage.On("Get", models.ID(1), models.ID(1)).Return(tC.existingGraph, nil)
+			}
+
+			alertDirectory := t.TempDir()
+			var alertService ModelEndpointAlertService
+			if tC.alerting {
+				alertService = NewModelEndpointAlertService(nil, newTestTeamService("team-1"),
+					map[string]AlertDeliverer{"env1": NewDirectoryAlertDeliverer(alertDirectory)}, nil, "")
+				if tC.existingGraph != nil && tC.existingGraph.Alert != nil {
+					assert.NoError(t, alertService.ApplyInferenceGraphAlert("user@example.com", project, tC.existingGraph, false))
+				}
+			}
+
+			endpointStorage := &mocks.VersionEndpointStorage{}
+			endpointStorage.On("ListActiveEndpointsInProject", models.ID(1)).Return(tC.activeEndpoints, nil)
+
+			producer := &queueMock.Producer{}
+			producer.On("EnqueueJob", mock.Anything).Return(tC.errEnqueue)
+
+			controller := &clusterMock.Controller{}
+			controller.On("ValidateInferenceGraph").Return(tC.errValidate)
+
+			controllers := map[string]cluster.Controller{"env1": controller}
+			svc := NewInferenceGraphService(controllers, graphStorage, endpointStorage, producer, alertService)
+
+			graph, err := svc.DeployInferenceGraph(context.Background(), "user@example.com", project, tC.graph)
+			if tC.alerting {
+				if tC.expectedAlert {
+					assert.FileExists(t, filepath.Join(alertDirectory, alertFileName))
+				} else {
+					assert.NoFileExists(t, filepath.Join(alertDirectory, alertFileName))
+				}
+			}
+			if tC.expectedError != "" {
+				assert.ErrorContains(t, err, tC.expectedError)
+				assert.Equal(t, tC.invalidInput, errors.Is(err, merror.InvalidInputError))
+				if tC.expectedStatus != "" {
+					assert.Equal(t, tC.expectedStatus, tC.graph.Status)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expectedStatus, graph.Status)
+			producer.AssertCalled(t, "EnqueueJob", mock.Anything)
+		})
+	}
+}
+
+func TestInferenceGraphService_UndeployInferenceGraph(t *testing.T) {
+	project := mlp.Project{ID: 1, Name: "project"}
+	graph := &models.InferenceGraph{ID: 1, ProjectID: 1, Name: "my-graph", URL: "http://my-graph.project.example.com", Status: models.EndpointRunning, EnvironmentName: "env1",
+		Alert: &models.InferenceGraphAlert{TeamName: "team-1"}}
+
+	alertDirectory := t.TempDir()
+	alertService := NewModelEndpointAlertService(nil, newTestTeamService("team-1"),
+		map[string]AlertDeliverer{"env1": NewDirectoryAlertDeliverer(alertDirectory)}, nil, "")
+	assert.NoError(t, alertService.ApplyInferenceGraphAlert("user@example.com", project, graph, false))
+
+	controller := &clusterMock.Controller{}
+	controller.On("DeleteInferenceGraph", mock.Anything, "project", "my-graph").Return(nil)
+
+	graphStorage := &mocks.InferenceGraphStorage{}
+	graphStorage.On("Save", mock.Anything).Return(nil)
+
+	svc := NewInferenceGraphService(map[string]cluster.Controller{"env1": controller}, graphStorage, &mocks.VersionEndpointStorage{}, &queueMock.Producer{}, alertService)
+
+	graph, err := svc.UndeployInferenceGraph(context.Background(), "user@example.com", project, graph)
+	assert.NoError(t, err)
+	assert.Equal(t, models.EndpointTerminated, graph.Status)
+	assert.Empty(t, graph.URL)
+	controller.AssertExpectations(t)
+	graphStorage.AssertExpectations(t)
+	assert.NoFileExists(t, filepath.Join(alertDirectory, "alerts/merlin/project/my-graph-graph_env1.yaml"))
+}
@@ -8,6 +8,8 @@ import (
 	models "github.com/caraml-dev/merlin/models"
 	mock "github.com/stretchr/testify/mock"
 
+	service "github.com/caraml-dev/merlin/service"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -106,6 +108,36 @@ func (_m *EndpointsService) ListContainers(ctx context.Context, model *models.Mo
 	return r0, r1
 }
 
+// ListDeploymentEvents provides a mock function with given fields: ctx, endpointUuid, query
+func (_m *EndpointsService) ListDeploymentEvents(ctx context.Context, endpointUuid uuid.UUID, query service.DeploymentEventQuery) ([]*models.DeploymentEvent, string, error) {
+	ret := _m.Called(ctx, endpointUuid, query)
+
+	var r0 []*models.DeploymentEvent
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, service.DeploymentEventQuery) []*models.DeploymentEvent); ok {
+		r0 = rf(ctx, endpointUuid, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.DeploymentEvent)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, service.DeploymentEventQuery) string); ok {
+		r1 = rf(ctx, endpointUuid, query)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, service.DeploymentEventQuery) error); ok {
+		r2 = rf(ctx, endpointUuid, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ListEndpoints provides a mock function with given fields: ctx, model, version
 func (_m *EndpointsService) ListEndpoints(ctx context.Context, model *models.Model, version *models.Version) ([]*models.VersionEndpoint, error) {
 	ret := _m.Called(ctx, model, version)
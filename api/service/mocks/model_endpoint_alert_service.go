@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	mlp "github.com/caraml-dev/merlin/mlp"
 	models "github.com/caraml-dev/merlin/models"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,6 +13,20 @@ type ModelEndpointAlertService struct {
 	mock.Mock
 }
 
+// ApplyInferenceGraphAlert provides a mock function with given fields: user, project, graph, delivered
+func (_m *ModelEndpointAlertService) ApplyInferenceGraphAlert(user string, project mlp.Project, graph *models.InferenceGraph, delivered bool) error {
+	ret := _m.Called(user, project, graph, delivered)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, mlp.Project, *models.InferenceGraph, bool) error); ok {
+		r0 = rf(user, project, graph, delivered)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateModelEndpointAlert provides a mock function with given fields: user, alert
 func (_m *ModelEndpointAlertService) CreateModelEndpointAlert(user string, alert *models.ModelEndpointAlert) (*models.ModelEndpointAlert, error) {
 	ret := _m.Called(user, alert)
@@ -35,6 +50,20 @@ func (_m *ModelEndpointAlertService) CreateModelEndpointAlert(user string, alert
 	return r0, r1
 }
 
+// DeleteInferenceGraphAlert provides a mock function with given fields: user, project, graph
+func (_m *ModelEndpointAlertService) DeleteInferenceGraphAlert(user string, project mlp.Project, graph *models.InferenceGraph) error {
+	ret := _m.Called(user, project, graph)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, mlp.Project, *models.InferenceGraph) error); ok {
+		r0 = rf(user, project, graph)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetModelEndpointAlert provides a mock function with given fields: modelID, modelEndpointID
 func (_m *ModelEndpointAlertService) GetModelEndpointAlert(modelID models.ID, modelEndpointID models.ID) (*models.ModelEndpointAlert, error) {
 	ret := _m.Called(modelID, modelEndpointID)
@@ -104,6 +133,29 @@ func (_m *ModelEndpointAlertService) ListTeams() ([]string, error) {
 	return r0, r1
 }
 
+// PreviewModelEndpointAlert provides a mock function with given fields: alert
+func (_m *ModelEndpointAlertService) PreviewModelEndpointAlert(alert *models.ModelEndpointAlert) (*models.ModelEndpointAlertPreview, error) {
+	ret := _m.Called(alert)
+
+	var r0 *models.ModelEndpointAlertPreview
+	if rf, ok := ret.Get(0).(func(*models.ModelEndpointAlert) *models.ModelEndpointAlertPreview); ok {
+		r0 = rf(alert)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ModelEndpointAlertPreview)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*models.ModelEndpointAlert) error); ok {
+		r1 = rf(alert)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateModelEndpointAlert provides a mock function with given fields: user, alert
 func (_m *ModelEndpointAlertService) UpdateModelEndpointAlert(user string, alert *models.ModelEndpointAlert) (*models.ModelEndpointAlert, error) {
 	ret := _m.Called(user, alert)
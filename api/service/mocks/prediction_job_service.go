@@ -64,6 +64,29 @@ func (_m *PredictionJobService) GetPredictionJob(ctx context.Context, env *model
 	return r0, r1
 }
 
+// ListAttempts provides a mock function with given fields: ctx, env, model, version, id
+func (_m *PredictionJobService) ListAttempts(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, id models.ID) ([]*models.PredictionJobAttempt, error) {
+	ret := _m.Called(ctx, env, model, version, id)
+
+	var r0 []*models.PredictionJobAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Environment, *models.Model, *models.Version, models.ID) []*models.PredictionJobAttempt); ok {
+		r0 = rf(ctx, env, model, version, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PredictionJobAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Environment, *models.Model, *models.Version, models.ID) error); ok {
+		r1 = rf(ctx, env, model, version, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListContainers provides a mock function with given fields: ctx, env, model, version, predictionJob
 func (_m *PredictionJobService) ListContainers(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, predictionJob *models.PredictionJob) ([]*models.Container, error) {
 	ret := _m.Called(ctx, env, model, version, predictionJob)
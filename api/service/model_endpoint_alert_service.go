@@ -15,12 +15,14 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	yamlv3 "gopkg.in/yaml.v3"
 
+	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
-	"github.com/caraml-dev/merlin/pkg/gitlab"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
 	"github.com/caraml-dev/merlin/storage"
 	"github.com/caraml-dev/merlin/warden"
 )
@@ -33,43 +35,59 @@ type ModelEndpointAlertService interface {
 	GetModelEndpointAlert(modelID models.ID, modelEndpointID models.ID) (*models.ModelEndpointAlert, error)
 	CreateModelEndpointAlert(user string, alert *models.ModelEndpointAlert) (*models.ModelEndpointAlert, error)
 	UpdateModelEndpointAlert(user string, alert *models.ModelEndpointAlert) (*models.ModelEndpointAlert, error)
+	// PreviewModelEndpointAlert returns the Prometheus rules file of an alert without committing it
+	PreviewModelEndpointAlert(alert *models.ModelEndpointAlert) (*models.ModelEndpointAlertPreview, error)
+	// ApplyInferenceGraphAlert creates the Prometheus rules file of the alert of an inference graph, or updates it if the
+	// graph's alert has been delivered before
+	ApplyInferenceGraphAlert(user string, project mlp.Project, graph *models.InferenceGraph, delivered bool) error
+	// DeleteInferenceGraphAlert deletes the Prometheus rules file of the delivered alert of an inference graph
+	DeleteInferenceGraphAlert(user string, project mlp.Project, graph *models.InferenceGraph) error
 }
 
 type modelEndpointAlertService struct {
 	alertStorage storage.AlertStorage
-	gitlabClient gitlab.Client
+	teamService  TeamService
+	// wardenClient is nil if Warden is not configured, the teams are then taken from the team registry
 	wardenClient warden.Client
 
-	dashboardRepository string
-	dashboardBranch     string
-	alertRepository     string
-	alertBranch         string
+	// alertDeliverers are keyed by environment name
+	alertDeliverers map[string]AlertDeliverer
 
 	dashboardBaseURL string
 }
 
 // NewModelEndpointAlertService initializes new alert service.
+// Alerts can only be created in environments having an alert deliverer.
 func NewModelEndpointAlertService(
-	alertStorage storage.AlertStorage,
-	gitlabClient gitlab.Client, wardenClient warden.Client,
-	dashboardRepository, dashboardBranch,
-	alertRepository, alertBranch, dashboardBaseURL string) ModelEndpointAlertService {
+	alertStorage storage.AlertStorage, teamService TeamService,
+	alertDeliverers map[string]AlertDeliverer, wardenClient warden.Client,
+	dashboardBaseURL string) ModelEndpointAlertService {
 	return &modelEndpointAlertService{
 		alertStorage: alertStorage,
-		gitlabClient: gitlabClient,
+		teamService:  teamService,
 		wardenClient: wardenClient,
 
-		dashboardRepository: dashboardRepository,
-		dashboardBranch:     dashboardBranch,
-		alertRepository:     alertRepository,
-		alertBranch:         alertBranch,
+		alertDeliverers: alertDeliverers,
 
 		dashboardBaseURL: dashboardBaseURL,
 	}
 }
 
 func (s *modelEndpointAlertService) ListTeams() ([]string, error) {
-	return s.wardenClient.GetAllTeams()
+	if s.wardenClient != nil {
+		return s.wardenClient.GetAllTeams()
+	}
+
+	teams, err := s.teamService.ListTeams(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	teamNames := make([]string, 0, len(teams))
+	for _, team := range teams {
+		teamNames = append(teamNames, team.Name)
+	}
+	return teamNames, nil
 }
 
 func (s *modelEndpointAlertService) ListModelAlerts(modelID models.ID) ([]*models.ModelEndpointAlert, error) {
@@ -81,28 +99,17 @@ func (s *modelEndpointAlertService) GetModelEndpointAlert(modelID models.ID, mod
 }
 
 func (s *modelEndpointAlertService) CreateModelEndpointAlert(user string, alert *models.ModelEndpointAlert) (*models.ModelEndpointAlert, error) {
-	commitMessage := fmt.Sprintf("Autogenerated by Merlin: Create alert for %s/%s in %s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName)
-	alertSpec, err := alert.ToPromAlertSpec(s.dashboardBaseURL)
+	deliverer, err := s.alertDeliverer(alert.EnvironmentName)
 	if err != nil {
 		return nil, err
 	}
 
-	alertFile, err := yamlv3.Marshal(&alertSpec)
+	alertFile, err := s.PreviewModelEndpointAlert(alert)
 	if err != nil {
 		return nil, err
 	}
-	alertFilename := fmt.Sprintf("alerts/merlin/%s/%s_%s.yaml", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName)
 
-	createAlertOpt := gitlab.CreateFileOptions{
-		Repository:    s.alertRepository,
-		Branch:        s.alertBranch,
-		FileName:      alertFilename,
-		Content:       string(alertFile),
-		CommitMessage: commitMessage,
-		AuthorEmail:   user,
-		AuthorName:    user,
-	}
-	if err := s.gitlabClient.CreateFile(createAlertOpt); err != nil {
+	if err := deliverer.CreateAlert(context.Background(), user, alert, alertFile); err != nil {
 		return nil, err
 	}
 
@@ -114,29 +121,17 @@ func (s *modelEndpointAlertService) CreateModelEndpointAlert(user string, alert
 }
 
 func (s *modelEndpointAlertService) UpdateModelEndpointAlert(user string, alert *models.ModelEndpointAlert) (*models.ModelEndpointAlert, error) {
-	commitMessage := fmt.Sprintf("Autogenerated by Merlin: Update alert for %s/%s in %s", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName)
-
-	alertSpec, err := alert.ToPromAlertSpec(s.dashboardBaseURL)
+	deliverer, err := s.alertDeliverer(alert.EnvironmentName)
 	if err != nil {
 		return nil, err
 	}
 
-	alertFile, err := yamlv3.Marshal(&alertSpec)
+	alertFile, err := s.PreviewModelEndpointAlert(alert)
 	if err != nil {
 		return nil, err
 	}
-	alertFilename := fmt.Sprintf("alerts/merlin/%s/%s_%s.yaml", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName)
 
-	updateAlertOpt := gitlab.UpdateFileOptions{
-		Repository:    s.alertRepository,
-		Branch:        s.alertBranch,
-		FileName:      alertFilename,
-		Content:       string(alertFile),
-		CommitMessage: commitMessage,
-		AuthorEmail:   user,
-		AuthorName:    user,
-	}
-	if err := s.gitlabClient.UpdateFile(updateAlertOpt); err != nil {
+	if err := deliverer.UpdateAlert(context.Background(), user, alert, alertFile); err != nil {
 		return nil, err
 	}
 
@@ -146,3 +141,124 @@ func (s *modelEndpointAlertService) UpdateModelEndpointAlert(user string, alert
 
 	return alert, nil
 }
+
+// ApplyInferenceGraphAlert delivers the alert of the inference graph as the alert of its router's inference service.
+// The alert is kept in the inference graph, instead of the alert storage, as the graph doesn't have a model endpoint.
+func (s *modelEndpointAlertService) ApplyInferenceGraphAlert(user string, project mlp.Project, graph *models.InferenceGraph, delivered bool) error {
+	alert := graph.ModelEndpointAlert(project)
+	if alert == nil {
+		return nil
+	}
+
+	deliverer, err := s.alertDeliverer(alert.EnvironmentName)
+	if err != nil {
+		return err
+	}
+
+	alertFile, err := s.PreviewModelEndpointAlert(alert)
+	if err != nil {
+		return err
+	}
+
+	deliver := deliverer.CreateAlert
+	if delivered {
+		deliver = deliverer.UpdateAlert
+	}
+	if err := deliver(context.Background(), user, alert, alertFile); err != nil {
+		return err
+	}
+
+	graph.Alert.TeamName = alert.TeamName
+	return nil
+}
+
+// DeleteInferenceGraphAlert deletes the rules file delivered for the alert of the inference graph.
+func (s *modelEndpointAlertService) DeleteInferenceGraphAlert(user string, project mlp.Project, graph *models.InferenceGraph) error {
+	alert := graph.ModelEndpointAlert(project)
+	if alert == nil {
+		return nil
+	}
+
+	deliverer, err := s.alertDeliverer(alert.EnvironmentName)
+	if err != nil {
+		return err
+	}
+	return deliverer.DeleteAlert(context.Background(), user, alert, &models.ModelEndpointAlertPreview{FileName: alertFileName(alert)})
+}
+
+func (s *modelEndpointAlertService) alertDeliverer(environmentName string) (AlertDeliverer, error) {
+	deliverer, ok := s.alertDeliverers[environmentName]
+	if !ok {
+		return nil, merror.NewInvalidInputErrorf("alerting is not configured in environment %s", environmentName)
+	}
+	return deliverer, nil
+}
+
+func (s *modelEndpointAlertService) PreviewModelEndpointAlert(alert *models.ModelEndpointAlert) (*models.ModelEndpointAlertPreview, error) {
+	if err := alert.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+	if err := s.resolveTeams(alert); err != nil {
+		return nil, err
+	}
+
+	alertSpec, err := alert.ToPromAlertSpec(s.dashboardBaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	alertFile, err := yamlv3.Marshal(&alertSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ModelEndpointAlertPreview{
+		FileName: alertFileName(alert),
+		Content:  string(alertFile),
+	}, nil
+}
+
+// alertFileName returns the path of the Prometheus rules file of the alert
+func alertFileName(alert *models.ModelEndpointAlert) string {
+	return fmt.Sprintf("alerts/merlin/%s/%s_%s.yaml", alert.Model.Project.Name, alert.Model.Name, alert.EnvironmentName)
+}
+
+// resolveTeams defaults the team of the alert to the owner of the model's project. If Warden is not configured,
+// the teams the alerts are routed to must be registered.
+func (s *modelEndpointAlertService) resolveTeams(alert *models.ModelEndpointAlert) error {
+	ctx := context.Background()
+
+	if alert.TeamName == "" {
+		owner, err := s.teamService.GetProjectOwner(ctx, alert.Model.ProjectID)
+		if err != nil {
+			return err
+		}
+		if owner == nil {
+			return merror.NewInvalidInputErrorf("team name is required as project %s doesn't have an owner", alert.Model.Project.Name)
+		}
+		alert.TeamName = owner.Team.Name
+	}
+
+	if s.wardenClient != nil {
+		return nil
+	}
+
+	teamNames, err := s.ListTeams()
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]bool, len(teamNames))
+	for _, teamName := range teamNames {
+		registered[teamName] = true
+	}
+
+	if !registered[alert.TeamName] {
+		return merror.NewInvalidInputErrorf("team %s is not registered", alert.TeamName)
+	}
+	for _, severity := range []models.AlertConditionSeverity{models.AlertConditionSeverityWarning, models.AlertConditionSeverityCritical} {
+		if teamName, ok := alert.Routes[severity]; ok && !registered[teamName] {
+			return merror.NewInvalidInputErrorf("team %s is not registered", teamName)
+		}
+	}
+	return nil
+}
@@ -16,8 +16,10 @@ package service
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -43,6 +45,27 @@ func Test_modelEndpointAlertService_ListTeams(t *testing.T) {
 	assert.Equal(t, "datascience", teams[0])
 }
 
+func newTestTeamService(teamNames ...string) TeamService {
+	teams := []*models.Team{}
+	for i, teamName := range teamNames {
+		teams = append(teams, &models.Team{ID: models.ID(i + 1), Name: teamName})
+	}
+
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("List").Return(teams, nil)
+	return NewTeamService(teamStorage, nil)
+}
+
+func Test_modelEndpointAlertService_ListTeams_Registry(t *testing.T) {
+	svc := modelEndpointAlertService{
+		teamService: newTestTeamService("analytics", "datascience"),
+	}
+
+	teams, err := svc.ListTeams()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"analytics", "datascience"}, teams)
+}
+
 func Test_modelEndpointAlertService_CreateModelEndpointAlert(t *testing.T) {
 	mockGitlabClient := &gitlabmocks.Client{}
 	mockGitlabClient.On("CreateFile", mock.Anything).
@@ -161,10 +184,11 @@ func Test_modelEndpointAlertService_CreateModelEndpointAlert(t *testing.T) {
 	}
 
 	s := &modelEndpointAlertService{
-		alertStorage:     mockAlertStorage,
-		gitlabClient:     mockGitlabClient,
-		alertRepository:  "merlin/alerts",
-		alertBranch:      "main",
+		alertStorage: mockAlertStorage,
+		teamService:  newTestTeamService("team-1"),
+		alertDeliverers: map[string]AlertDeliverer{
+			"env-1": NewGitlabAlertDeliverer(mockGitlabClient, "merlin/alerts", "main"),
+		},
 		dashboardBaseURL: "http://dashboard.dev/",
 	}
 
@@ -172,3 +196,219 @@ func Test_modelEndpointAlertService_CreateModelEndpointAlert(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, alert, savedAlert)
 }
+
+func Test_modelEndpointAlertService_PreviewModelEndpointAlert(t *testing.T) {
+	alert := &models.ModelEndpointAlert{
+		ModelID: 1,
+		Model: &models.Model{
+			Name: "model-1",
+			Project: mlp.Project{
+				Name: "project-1",
+			},
+		},
+		ModelEndpointID: models.ID(1),
+		ModelEndpoint: &models.ModelEndpoint{
+			ID: models.ID(1),
+			Environment: &models.Environment{
+				Cluster: "cluster-1",
+			},
+		},
+		EnvironmentName: "env-1",
+		TeamName:        "team-1",
+		SLOs: models.AlertSLOs{
+			&models.AlertSLO{
+				Enabled:   true,
+				Type:      models.AlertSLOTypeAvailability,
+				Objective: 99.9,
+			},
+		},
+	}
+
+	mockGitlabClient := &gitlabmocks.Client{}
+	s := &modelEndpointAlertService{
+		teamService: newTestTeamService("team-1"),
+		alertDeliverers: map[string]AlertDeliverer{
+			"env-1": NewGitlabAlertDeliverer(mockGitlabClient, "merlin/alerts", "main"),
+		},
+		dashboardBaseURL: "http://dashboard.dev/",
+	}
+
+	preview, err := s.PreviewModelEndpointAlert(alert)
+	assert.Nil(t, err)
+	assert.Equal(t, "alerts/merlin/project-1/model-1_env-1.yaml", preview.FileName)
+	assert.Contains(t, preview.Content, `alert: "[merlin] model-1: Availability SLO burn rate critical"`)
+	assert.Contains(t, preview.Content, `alert: "[merlin] model-1: Availability SLO burn rate warning"`)
+	mockGitlabClient.AssertNotCalled(t, "CreateFile", mock.Anything)
+
+	alert.SLOs[0].Objective = 100
+	_, err = s.PreviewModelEndpointAlert(alert)
+	assert.EqualError(t, err, "invalid input: invalid SLO 0: objective must be between 0 and 100 exclusive, got 100")
+}
+
+func Test_modelEndpointAlertService_UpdateModelEndpointAlert(t *testing.T) {
+	alert := &models.ModelEndpointAlert{
+		ModelID: 1,
+		Model: &models.Model{
+			Name: "model-1",
+			Project: mlp.Project{
+				Name: "project-1",
+			},
+		},
+		ModelEndpointID: models.ID(1),
+		ModelEndpoint: &models.ModelEndpoint{
+			ID: models.ID(1),
+			Environment: &models.Environment{
+				Cluster: "cluster-1",
+			},
+		},
+		EnvironmentName: "env-1",
+		TeamName:        "team-1",
+		AlertConditions: models.AlertConditions{
+			&models.AlertCondition{
+				Enabled:    true,
+				MetricType: models.AlertConditionTypeThroughput,
+				Severity:   models.AlertConditionSeverityWarning,
+				Target:     1,
+			},
+		},
+	}
+
+	mockAlertStorage := &storagemocks.AlertStorage{}
+	mockAlertStorage.On("UpdateModelEndpointAlert", alert).Return(nil)
+
+	directory := t.TempDir()
+	s := &modelEndpointAlertService{
+		alertStorage:     mockAlertStorage,
+		teamService:      newTestTeamService("team-1"),
+		alertDeliverers:  map[string]AlertDeliverer{"env-1": NewDirectoryAlertDeliverer(directory)},
+		dashboardBaseURL: "http://dashboard.dev/",
+	}
+
+	savedAlert, err := s.UpdateModelEndpointAlert("author-test", alert)
+	assert.Nil(t, err)
+	assert.Equal(t, alert, savedAlert)
+	mockAlertStorage.AssertExpectations(t)
+
+	rulesFile, err := os.ReadFile(filepath.Join(directory, "alerts/merlin/project-1/model-1_env-1.yaml"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(rulesFile), `alert: "[merlin] model-1: Throughput warning"`)
+
+	alert.EnvironmentName = "env-2"
+	_, err = s.UpdateModelEndpointAlert("author-test", alert)
+	assert.EqualError(t, err, "invalid input: alerting is not configured in environment env-2")
+	mockAlertStorage.AssertNumberOfCalls(t, "UpdateModelEndpointAlert", 1)
+}
+
+func Test_modelEndpointAlertService_ApplyInferenceGraphAlert(t *testing.T) {
+	project := mlp.Project{ID: 1, Name: "project-1"}
+	graph := &models.InferenceGraph{
+		ProjectID:       models.ID(1),
+		Name:            "graph-1",
+		Environment:     &models.Environment{Name: "env-1", Cluster: "cluster-1"},
+		EnvironmentName: "env-1",
+		Alert: &models.InferenceGraphAlert{
+			AlertConditions: models.AlertConditions{
+				&models.AlertCondition{
+					Enabled:    true,
+					MetricType: models.AlertConditionTypeErrorRate,
+					Severity:   models.AlertConditionSeverityCritical,
+					Target:     5,
+				},
+			},
+		},
+	}
+
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("List").Return([]*models.Team{{ID: 1, Name: "team-1"}}, nil)
+	teamStorage.On("GetProjectOwner", models.ID(1)).Return(&models.ProjectOwner{ProjectID: 1, Team: &models.Team{ID: 1, Name: "team-1"}}, nil)
+
+	directory := t.TempDir()
+	s := &modelEndpointAlertService{
+		teamService:      NewTeamService(teamStorage, nil),
+		alertDeliverers:  map[string]AlertDeliverer{"env-1": NewDirectoryAlertDeliverer(directory)},
+		dashboardBaseURL: "http://dashboard.dev/",
+	}
+
+	err := s.ApplyInferenceGraphAlert("author-test", project, graph, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "team-1", graph.Alert.TeamName)
+
+	rulesFile, err := os.ReadFile(filepath.Join(directory, "alerts/merlin/project-1/graph-1-graph_env-1.yaml"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(rulesFile), `alert: "[merlin] graph-1-graph: Error Rate critical"`)
+
+	err = s.ApplyInferenceGraphAlert("author-test", project, &models.InferenceGraph{Name: "graph-2"}, true)
+	assert.Nil(t, err)
+
+	err = s.DeleteInferenceGraphAlert("author-test", project, graph)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(directory, "alerts/merlin/project-1/graph-1-graph_env-1.yaml"))
+	assert.True(t, os.IsNotExist(err))
+
+	graph.EnvironmentName = "env-2"
+	err = s.ApplyInferenceGraphAlert("author-test", project, graph, true)
+	assert.EqualError(t, err, "invalid input: alerting is not configured in environment env-2")
+}
+
+func Test_modelEndpointAlertService_PreviewModelEndpointAlert_Teams(t *testing.T) {
+	newAlert := func() *models.ModelEndpointAlert {
+		return &models.ModelEndpointAlert{
+			Model: &models.Model{
+				Name:      "model-1",
+				ProjectID: models.ID(1),
+				Project: mlp.Project{
+					Name: "project-1",
+				},
+			},
+			ModelEndpoint: &models.ModelEndpoint{
+				Environment: &models.Environment{
+					Cluster: "cluster-1",
+				},
+			},
+			EnvironmentName: "env-1",
+			AlertConditions: models.AlertConditions{
+				&models.AlertCondition{
+					Enabled:    true,
+					MetricType: models.AlertConditionTypeThroughput,
+					Severity:   models.AlertConditionSeverityWarning,
+					Target:     1,
+				},
+			},
+		}
+	}
+
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("List").Return([]*models.Team{{ID: 1, Name: "datascience"}, {ID: 2, Name: "sre"}}, nil)
+	teamStorage.On("GetProjectOwner", models.ID(1)).Return(&models.ProjectOwner{ProjectID: 1, TeamID: 1, Team: &models.Team{ID: 1, Name: "datascience"}}, nil)
+	teamStorage.On("GetProjectOwner", models.ID(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	s := &modelEndpointAlertService{
+		teamService:      NewTeamService(teamStorage, nil),
+		dashboardBaseURL: "http://dashboard.dev/",
+	}
+
+	// The team defaults to the owner of the project
+	alert := newAlert()
+	preview, err := s.PreviewModelEndpointAlert(alert)
+	assert.Nil(t, err)
+	assert.Equal(t, "datascience", alert.TeamName)
+	assert.Contains(t, preview.Content, "owner: datascience")
+
+	alert = newAlert()
+	alert.Model.ProjectID = models.ID(2)
+	_, err = s.PreviewModelEndpointAlert(alert)
+	assert.EqualError(t, err, "invalid input: team name is required as project project-1 doesn't have an owner")
+
+	alert = newAlert()
+	alert.TeamName = "sre"
+	alert.Routes = models.AlertRoutes{models.AlertConditionSeverityCritical: "oncall"}
+	_, err = s.PreviewModelEndpointAlert(alert)
+	assert.EqualError(t, err, "invalid input: team oncall is not registered")
+
+	// Teams are not checked against the registry if Warden is configured
+	wardenClient := &wardenmocks.Client{}
+	s.wardenClient = wardenClient
+	_, err = s.PreviewModelEndpointAlert(alert)
+	assert.Nil(t, err)
+	wardenClient.AssertNotCalled(t, "GetAllTeams")
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/caraml-dev/merlin/pkg/protocol"
+	"github.com/caraml-dev/merlin/pkg/webhook"
 	"github.com/caraml-dev/merlin/storage"
 	"github.com/pkg/errors"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
@@ -63,8 +64,8 @@ type ModelEndpointsService interface {
 }
 
 // NewModelEndpointsService returns an initialized ModelEndpointsService.
-func NewModelEndpointsService(istioClients map[string]istio.Client, modelEndpointStorage storage.ModelEndpointStorage, versionEndpointStorage storage.VersionEndpointStorage, environment string) ModelEndpointsService {
-	return newModelEndpointsService(istioClients, modelEndpointStorage, versionEndpointStorage, environment)
+func NewModelEndpointsService(istioClients map[string]istio.Client, modelEndpointStorage storage.ModelEndpointStorage, versionEndpointStorage storage.VersionEndpointStorage, environment string, notifier webhook.Notifier) ModelEndpointsService {
+	return newModelEndpointsService(istioClients, modelEndpointStorage, versionEndpointStorage, environment, notifier)
 }
 
 type modelEndpointsService struct {
@@ -72,14 +73,16 @@ type modelEndpointsService struct {
 	modelEndpointStorage   storage.ModelEndpointStorage
 	versionEndpointStorage storage.VersionEndpointStorage
 	environment            string
+	notifier               webhook.Notifier
 }
 
-func newModelEndpointsService(istioClients map[string]istio.Client, modelEndpointStorage storage.ModelEndpointStorage, versionEndpointStorage storage.VersionEndpointStorage, environment string) *modelEndpointsService {
+func newModelEndpointsService(istioClients map[string]istio.Client, modelEndpointStorage storage.ModelEndpointStorage, versionEndpointStorage storage.VersionEndpointStorage, environment string, notifier webhook.Notifier) *modelEndpointsService {
 	return &modelEndpointsService{
 		istioClients:           istioClients,
 		modelEndpointStorage:   modelEndpointStorage,
 		versionEndpointStorage: versionEndpointStorage,
 		environment:            environment,
+		notifier:               notifier,
 	}
 }
 
@@ -138,6 +141,7 @@ func (s *modelEndpointsService) DeployEndpoint(ctx context.Context, model *model
 		return nil, err
 	}
 
+	s.notify(ctx, model, endpoint)
 	return endpoint, nil
 }
 
@@ -182,6 +186,7 @@ func (s *modelEndpointsService) UpdateEndpoint(ctx context.Context, model *model
 		return nil, err
 	}
 
+	s.notify(ctx, model, newEndpoint)
 	return newEndpoint, nil
 }
 
@@ -206,9 +211,26 @@ func (s *modelEndpointsService) UndeployEndpoint(ctx context.Context, model *mod
 		return nil, err
 	}
 
+	s.notify(ctx, model, endpoint)
 	return endpoint, nil
 }
 
+// notify sends the model endpoint update to the project's webhooks
+func (s *modelEndpointsService) notify(ctx context.Context, model *models.Model, endpoint *models.ModelEndpoint) {
+	if s.notifier == nil {
+		return
+	}
+
+	s.notifier.Notify(ctx, model.ProjectID, models.WebhookEventModelEndpointUpdated, endpoint.ID.String(), models.ModelEndpointEventData{
+		ModelEndpointID: endpoint.ID,
+		ModelID:         model.ID,
+		ModelName:       model.Name,
+		EnvironmentName: endpoint.EnvironmentName,
+		Status:          endpoint.Status,
+		URL:             endpoint.URL,
+	})
+}
+
 func (s *modelEndpointsService) createVirtualService(model *models.Model, endpoint *models.ModelEndpoint) (*v1beta1.VirtualService, error) {
 	metadata := models.Metadata{
 		App:       model.Name,
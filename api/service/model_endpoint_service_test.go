@@ -22,6 +22,7 @@ import (
 	istioCliMock "github.com/caraml-dev/merlin/istio/mocks"
 	"github.com/caraml-dev/merlin/models"
 	"github.com/caraml-dev/merlin/pkg/protocol"
+	webhookMock "github.com/caraml-dev/merlin/pkg/webhook/mocks"
 	"github.com/caraml-dev/merlin/storage"
 	storageMock "github.com/caraml-dev/merlin/storage/mocks"
 	"github.com/google/uuid"
@@ -146,7 +147,9 @@ func Test_modelEndpointsService_DeployEndpoint(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := newModelEndpointsService(tt.fields.istioClients, tt.fields.modelEndpointStorage, tt.fields.versionEndpointStorage, tt.fields.environment)
+			notifier := &webhookMock.Notifier{}
+			notifier.On("Notify", mock.Anything, tt.args.model.ProjectID, models.WebhookEventModelEndpointUpdated, mock.Anything, mock.Anything).Return()
+			s := newModelEndpointsService(tt.fields.istioClients, tt.fields.modelEndpointStorage, tt.fields.versionEndpointStorage, tt.fields.environment, notifier)
 
 			tt.mockFunc(s)
 
@@ -156,6 +159,12 @@ func Test_modelEndpointsService_DeployEndpoint(t *testing.T) {
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				notifier.AssertNumberOfCalls(t, "Notify", 1)
+				assert.Equal(t, models.EndpointServing, notifier.Calls[0].Arguments[4].(models.ModelEndpointEventData).Status)
+			}
 		})
 	}
 }
@@ -349,7 +358,7 @@ func Test_modelEndpointsService_UpdateEndpoint(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := newModelEndpointsService(tt.fields.istioClients, tt.fields.modelEndpointStorage, tt.fields.versionEndpointStorage, tt.fields.environment)
+			s := newModelEndpointsService(tt.fields.istioClients, tt.fields.modelEndpointStorage, tt.fields.versionEndpointStorage, tt.fields.environment, nil)
 
 			tt.mockFunc(s)
 
@@ -432,7 +441,7 @@ func Test_modelEndpointsService_UndeployEndpoint(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := newModelEndpointsService(tt.fields.istioClients, tt.fields.modelEndpointStorage, tt.fields.versionEndpointStorage, tt.fields.environment)
+			s := newModelEndpointsService(tt.fields.istioClients, tt.fields.modelEndpointStorage, tt.fields.versionEndpointStorage, tt.fields.environment, nil)
 
 			tt.mockFunc(s)
 
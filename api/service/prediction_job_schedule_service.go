@@ -0,0 +1,269 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	clock2 "k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/caraml-dev/merlin/log"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+// PredictionJobScheduleService manages recurring prediction jobs of model versions
+type PredictionJobScheduleService interface {
+	// ListSchedules list all schedules matching the given query
+	ListSchedules(ctx context.Context, query *models.PredictionJobSchedule) ([]*models.PredictionJobSchedule, error)
+	// FindByID find schedule given its ID
+	FindByID(ctx context.Context, id models.ID) (*models.PredictionJobSchedule, error)
+	// SaveSchedule create or update a schedule and compute its next run
+	SaveSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) (*models.PredictionJobSchedule, error)
+	// DeleteSchedule delete a schedule and its run history, the created prediction jobs are kept
+	DeleteSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) error
+	// PauseSchedule stop creating prediction jobs from a schedule
+	PauseSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) (*models.PredictionJobSchedule, error)
+	// ResumeSchedule resume a paused schedule from its next run after now, runs missed while paused are skipped
+	ResumeSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) (*models.PredictionJobSchedule, error)
+	// ListDueSchedules list all unpaused schedules with a run that is due
+	ListDueSchedules(ctx context.Context) ([]*models.PredictionJobSchedule, error)
+	// ClaimScheduledRun advance the schedule past its due run and return the scheduled time of the run.
+	// It returns false if the run has been claimed by another replica of the API server.
+	ClaimScheduledRun(ctx context.Context, schedule *models.PredictionJobSchedule) (time.Time, bool, error)
+	// TriggerSchedule create a prediction job for the run scheduled at the given time, honoring the concurrency policy
+	TriggerSchedule(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, schedule *models.PredictionJobSchedule, scheduledTime time.Time, manual bool) (*models.PredictionJobScheduleRun, error)
+	// ListRuns list the run history of a schedule, most recent first
+	ListRuns(ctx context.Context, schedule *models.PredictionJobSchedule) ([]*models.PredictionJobScheduleRun, error)
+}
+
+type predictionJobScheduleService struct {
+	storage              storage.PredictionJobScheduleStorage
+	predictionJobStorage storage.PredictionJobStorage
+	predictionJobService PredictionJobService
+	clock                clock2.Clock
+}
+
+// NewPredictionJobScheduleService creates a new PredictionJobScheduleService
+func NewPredictionJobScheduleService(storage storage.PredictionJobScheduleStorage, predictionJobStorage storage.PredictionJobStorage, predictionJobService PredictionJobService, clock clock2.Clock) PredictionJobScheduleService {
+	return &predictionJobScheduleService{
+		storage:              storage,
+		predictionJobStorage: predictionJobStorage,
+		predictionJobService: predictionJobService,
+		clock:                clock,
+	}
+}
+
+func (s *predictionJobScheduleService) ListSchedules(ctx context.Context, query *models.PredictionJobSchedule) ([]*models.PredictionJobSchedule, error) {
+	return s.storage.List(query)
+}
+
+func (s *predictionJobScheduleService) FindByID(ctx context.Context, id models.ID) (*models.PredictionJobSchedule, error) {
+	return s.storage.Get(id)
+}
+
+func (s *predictionJobScheduleService) SaveSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) (*models.PredictionJobSchedule, error) {
+	if schedule.ConcurrencyPolicy == "" {
+		schedule.ConcurrencyPolicy = models.ConcurrencyPolicyForbid
+	}
+	if schedule.MaxHistory == 0 {
+		schedule.MaxHistory = models.DefaultScheduleMaxHistory
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+
+	if err := s.updateNextRun(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Save(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *predictionJobScheduleService) DeleteSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) error {
+	return s.storage.Delete(schedule)
+}
+
+func (s *predictionJobScheduleService) PauseSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) (*models.PredictionJobSchedule, error) {
+	schedule.Paused = true
+	if err := s.storage.Save(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *predictionJobScheduleService) ResumeSchedule(ctx context.Context, schedule *models.PredictionJobSchedule) (*models.PredictionJobSchedule, error) {
+	schedule.Paused = false
+	if err := s.updateNextRun(schedule); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Save(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *predictionJobScheduleService) updateNextRun(schedule *models.PredictionJobSchedule) error {
+	next, err := schedule.NextRunAfter(s.clock.Now())
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = &next
+	return nil
+}
+
+func (s *predictionJobScheduleService) ListDueSchedules(ctx context.Context) ([]*models.PredictionJobSchedule, error) {
+	return s.storage.ListDue(s.clock.Now())
+}
+
+func (s *predictionJobScheduleService) ClaimScheduledRun(ctx context.Context, schedule *models.PredictionJobSchedule) (time.Time, bool, error) {
+	if schedule.NextRunAt == nil {
+		return time.Time{}, false, fmt.Errorf("schedule %s has no next run", schedule.Name)
+	}
+	scheduledTime := *schedule.NextRunAt
+
+	// runs missed while the API server was down are collapsed into the latest one
+	next, err := schedule.NextRunAfter(s.clock.Now())
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	claimed, err := s.storage.Advance(schedule, next)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return scheduledTime, claimed, nil
+}
+
+func (s *predictionJobScheduleService) TriggerSchedule(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, schedule *models.PredictionJobSchedule, scheduledTime time.Time, manual bool) (*models.PredictionJobScheduleRun, error) {
+	run := &models.PredictionJobScheduleRun{
+		ScheduleID:    schedule.ID,
+		ScheduledTime: scheduledTime,
+		Manual:        manual,
+	}
+
+	runs, err := s.listRunsWithJob(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.advanceWatermark(schedule, runs); err != nil {
+		return nil, err
+	}
+
+	activeJobs := listActiveJobs(runs)
+
+	if len(activeJobs) > 0 {
+		switch schedule.ConcurrencyPolicy {
+		case models.ConcurrencyPolicyForbid:
+			run.Message = fmt.Sprintf("skipped because prediction job %s is still %s", activeJobs[0].ID, activeJobs[0].Status)
+			return run, s.saveRun(schedule, run)
+		case models.ConcurrencyPolicyReplace:
+			for _, job := range activeJobs {
+				if _, err := s.predictionJobService.StopPredictionJob(ctx, env, model, version, job.ID); err != nil {
+					log.Warnf("unable to stop prediction job %s replaced by schedule %s: %v", job.ID, schedule.Name, err)
+				}
+			}
+		}
+	}
+
+	job, err := schedule.RenderJob(scheduledTime)
+	if err == nil {
+		job, err = s.predictionJobService.CreatePredictionJob(ctx, env, model, version, job)
+	}
+	if err != nil {
+		run.Message = fmt.Sprintf("failed creating prediction job: %s", err)
+		if saveErr := s.saveRun(schedule, run); saveErr != nil {
+			log.Errorf("unable to save failed run of schedule %s: %v", schedule.Name, saveErr)
+		}
+		return nil, err
+	}
+
+	run.JobID = &job.ID
+	run.Job = job
+	return run, s.saveRun(schedule, run)
+}
+
+// listActiveJobs returns the prediction jobs created by the schedule's runs that haven't finished
+func listActiveJobs(runs []*models.PredictionJobScheduleRun) []*models.PredictionJob {
+	activeJobs := make([]*models.PredictionJob, 0)
+	for _, run := range runs {
+		if run.Job != nil && !run.Job.Status.IsTerminal() {
+			activeJobs = append(activeJobs, run.Job)
+		}
+	}
+	return activeJobs
+}
+
+// advanceWatermark moves the last watermark of the schedule to the latest end watermark of its completed jobs,
+// so the rows of failed jobs are read again by the next job
+func (s *predictionJobScheduleService) advanceWatermark(schedule *models.PredictionJobSchedule, runs []*models.PredictionJobScheduleRun) error {
+	var watermark *time.Time
+	for _, run := range runs {
+		if run.Job == nil || !run.Job.Status.IsSuccessful() {
+			continue
+		}
+		end, ok := run.Job.Config.EndWatermark()
+		if ok && (watermark == nil || end.After(*watermark)) {
+			watermark = &end
+		}
+	}
+
+	if watermark == nil || (schedule.LastWatermark != nil && !watermark.After(*schedule.LastWatermark)) {
+		return nil
+	}
+	return s.storage.AdvanceWatermark(schedule, *watermark)
+}
+
+func (s *predictionJobScheduleService) saveRun(schedule *models.PredictionJobSchedule, run *models.PredictionJobScheduleRun) error {
+	if err := s.storage.SaveRun(run); err != nil {
+		return err
+	}
+	return s.storage.PruneRuns(schedule.ID, schedule.MaxHistory)
+}
+
+func (s *predictionJobScheduleService) ListRuns(ctx context.Context, schedule *models.PredictionJobSchedule) ([]*models.PredictionJobScheduleRun, error) {
+	return s.listRunsWithJob(schedule)
+}
+
+func (s *predictionJobScheduleService) listRunsWithJob(schedule *models.PredictionJobSchedule) ([]*models.PredictionJobScheduleRun, error) {
+	runs, err := s.storage.ListRuns(schedule.ID, schedule.MaxHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		if run.JobID == nil {
+			continue
+		}
+		job, err := s.predictionJobStorage.Get(*run.JobID)
+		if err != nil {
+			if gorm.IsRecordNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		run.Job = job
+	}
+	return runs, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/caraml-dev/merlin/batch/mocks"
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	storageMock "github.com/caraml-dev/merlin/storage/mocks"
+)
+
+var scheduleNow = time.Date(2023, 3, 1, 10, 30, 0, 0, time.UTC)
+
+func newSchedule(policy models.ConcurrencyPolicy) *models.PredictionJobSchedule {
+	return &models.PredictionJobSchedule{
+		ID:                1,
+		Name:              "nightly",
+		ProjectID:         models.ID(project.ID),
+		ModelID:           model.ID,
+		VersionID:         version.ID,
+		EnvironmentName:   predJobEnv.Name,
+		Schedule:          "0 2 * * *",
+		ConcurrencyPolicy: policy,
+		MaxHistory:        3,
+		Config: &models.Config{
+			JobConfig: &spec.PredictionJob{
+				Source: &spec.PredictionJob_BigquerySource{
+					BigquerySource: &spec.BigQuerySource{Table: "project.dataset.features_{{ ds_nodash }}"},
+				},
+			},
+		},
+	}
+}
+
+func newMockPredictionJobScheduleService() (PredictionJobScheduleService, *storageMock.PredictionJobScheduleStorage, *storageMock.PredictionJobStorage, *mocks.Controller) {
+	predictionJobService, controllers, _, predictionJobStorage, producer := newMockPredictionJobService()
+	producer.On("EnqueueJob", mock.Anything).Return(nil)
+
+	scheduleStorage := &storageMock.PredictionJobScheduleStorage{}
+	svc := NewPredictionJobScheduleService(scheduleStorage, predictionJobStorage, predictionJobService, clock.NewFakeClock(scheduleNow))
+	return svc, scheduleStorage, predictionJobStorage, controllers[predJobEnv.Name].(*mocks.Controller)
+}
+
+func TestPredictionJobScheduleService_SaveSchedule(t *testing.T) {
+	svc, scheduleStorage, _, _ := newMockPredictionJobScheduleService()
+	scheduleStorage.On("Save", mock.Anything).Return(nil)
+
+	invalid := newSchedule(models.ConcurrencyPolicyAllow)
+	invalid.Schedule = "daily"
+	_, err := svc.SaveSchedule(context.Background(), invalid)
+	assert.True(t, errors.Is(err, merror.InvalidInputError))
+	scheduleStorage.AssertNotCalled(t, "Save", mock.Anything)
+
+	schedule := newSchedule("")
+	schedule.MaxHistory = 0
+	saved, err := svc.SaveSchedule(context.Background(), schedule)
+	require.NoError(t, err)
+	assert.Equal(t, models.ConcurrencyPolicyForbid, saved.ConcurrencyPolicy)
+	assert.Equal(t, models.DefaultScheduleMaxHistory, saved.MaxHistory)
+	assert.Equal(t, time.Date(2023, 3, 2, 2, 0, 0, 0, time.UTC), *saved.NextRunAt)
+	scheduleStorage.AssertCalled(t, "Save", schedule)
+}
+
+func TestPredictionJobScheduleService_ClaimScheduledRun(t *testing.T) {
+	svc, scheduleStorage, _, _ := newMockPredictionJobScheduleService()
+
+	// the API server was down for two days, only the latest missed run is created
+	due := time.Date(2023, 2, 27, 2, 0, 0, 0, time.UTC)
+	schedule := newSchedule(models.ConcurrencyPolicyForbid)
+	schedule.NextRunAt = &due
+	scheduleStorage.On("Advance", schedule, time.Date(2023, 3, 2, 2, 0, 0, 0, time.UTC)).Return(true, nil).Once()
+	scheduleStorage.On("Advance", schedule, time.Date(2023, 3, 2, 2, 0, 0, 0, time.UTC)).Return(false, nil).Once()
+
+	scheduledTime, claimed, err := svc.ClaimScheduledRun(context.Background(), schedule)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, due, scheduledTime)
+
+	_, claimed, err = svc.ClaimScheduledRun(context.Background(), schedule)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestPredictionJobScheduleService_TriggerSchedule(t *testing.T) {
+	scheduledTime := time.Date(2023, 3, 1, 2, 0, 0, 0, time.UTC)
+	activeJobID := models.ID(10)
+	finishedJobID := models.ID(9)
+
+	testCases := []struct {
+		desc          string
+		policy        models.ConcurrencyPolicy
+		activeJob     bool
+		expectedJob   bool
+		expectedStops int
+		message       string
+	}{
+		{
+			desc:        "Should create job when no job is active",
+			policy:      models.ConcurrencyPolicyForbid,
+			expectedJob: true,
+		},
+		{
+			desc:      "Should skip run when a job is active and concurrency is forbidden",
+			policy:    models.ConcurrencyPolicyForbid,
+			activeJob: true,
+			message:   "skipped because prediction job 10 is still running",
+		},
+		{
+			desc:        "Should create job alongside active job when concurrency is allowed",
+			policy:      models.ConcurrencyPolicyAllow,
+			activeJob:   true,
+			expectedJob: true,
+		},
+		{
+			desc:          "Should stop active job when concurrency policy is replace",
+			policy:        models.ConcurrencyPolicyReplace,
+			activeJob:     true,
+			expectedJob:   true,
+			expectedStops: 1,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			svc, scheduleStorage, predictionJobStorage, controller := newMockPredictionJobScheduleService()
+			schedule := newSchedule(tC.policy)
+
+			runs := []*models.PredictionJobScheduleRun{{ScheduleID: schedule.ID, JobID: &finishedJobID}}
+			predictionJobStorage.On("Get", finishedJobID).Return(&models.PredictionJob{ID: finishedJobID, Status: models.JobCompleted}, nil)
+			if tC.activeJob {
+				runs = append([]*models.PredictionJobScheduleRun{{ScheduleID: schedule.ID, JobID: &activeJobID}}, runs...)
+				predictionJobStorage.On("Get", activeJobID).Return(&models.PredictionJob{ID: activeJobID, Status: models.JobRunning}, nil)
+			}
+			scheduleStorage.On("ListRuns", schedule.ID, schedule.MaxHistory).Return(runs, nil)
+			scheduleStorage.On("SaveRun", mock.Anything).Return(nil)
+			scheduleStorage.On("PruneRuns", schedule.ID, schedule.MaxHistory).Return(nil)
+			predictionJobStorage.On("Save", mock.Anything).Return(nil)
+			controller.On("Stop", mock.Anything, mock.Anything, project.Name).Return(nil)
+
+			run, err := svc.TriggerSchedule(context.Background(), predJobEnv, model, version, schedule, scheduledTime, true)
+			require.NoError(t, err)
+			assert.True(t, run.Manual)
+			assert.Equal(t, scheduledTime, run.ScheduledTime)
+			assert.Equal(t, tC.message, run.Message)
+			if tC.expectedJob {
+				require.NotNil(t, run.Job)
+				assert.Equal(t, "project.dataset.features_20230301", run.Job.Config.JobConfig.GetBigquerySource().Table)
+				predictionJobStorage.AssertCalled(t, "Save", run.Job)
+			} else {
+				assert.Nil(t, run.JobID)
+				predictionJobStorage.AssertNotCalled(t, "Save", mock.Anything)
+			}
+			controller.AssertNumberOfCalls(t, "Stop", tC.expectedStops)
+			scheduleStorage.AssertCalled(t, "SaveRun", run)
+			scheduleStorage.AssertCalled(t, "PruneRuns", schedule.ID, schedule.MaxHistory)
+		})
+	}
+}
+
+func TestPredictionJobScheduleService_TriggerIncrementalSchedule(t *testing.T) {
+	scheduledTime := time.Date(2023, 3, 1, 2, 0, 0, 0, time.UTC)
+	completedJobID := models.ID(8)
+	failedJobID := models.ID(9)
+
+	incrementalJob := func(id models.ID, status models.State, endWatermark string) *models.PredictionJob {
+		return &models.PredictionJob{
+			ID:     id,
+			Status: status,
+			Config: &models.Config{
+				JobConfig: &spec.PredictionJob{
+					Source: &spec.PredictionJob_BigquerySource{
+						BigquerySource: &spec.BigQuerySource{
+							Incremental: &spec.IncrementalSource{WatermarkColumn: "event_timestamp", EndWatermark: endWatermark},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	svc, scheduleStorage, predictionJobStorage, _ := newMockPredictionJobScheduleService()
+	schedule := newSchedule(models.ConcurrencyPolicyForbid)
+	schedule.Config.JobConfig.GetBigquerySource().Incremental = &spec.IncrementalSource{WatermarkColumn: "event_timestamp"}
+	schedule.Config.JobConfig.Sink = &spec.PredictionJob_BigquerySink{
+		BigquerySink: &spec.BigQuerySink{
+			Table:     "project.dataset.predictions",
+			Partition: &spec.SinkPartition{Column: "prediction_date"},
+		},
+	}
+
+	runs := []*models.PredictionJobScheduleRun{
+		{ScheduleID: schedule.ID, JobID: &failedJobID},
+		{ScheduleID: schedule.ID, JobID: &completedJobID},
+	}
+	predictionJobStorage.On("Get", failedJobID).Return(incrementalJob(failedJobID, models.JobFailed, "2023-02-28T02:00:00Z"), nil)
+	predictionJobStorage.On("Get", completedJobID).Return(incrementalJob(completedJobID, models.JobCompleted, "2023-02-27T02:00:00Z"), nil)
+	predictionJobStorage.On("Save", mock.Anything).Return(nil)
+	scheduleStorage.On("ListRuns", schedule.ID, schedule.MaxHistory).Return(runs, nil)
+	scheduleStorage.On("SaveRun", mock.Anything).Return(nil)
+	scheduleStorage.On("PruneRuns", schedule.ID, schedule.MaxHistory).Return(nil)
+
+	lastWatermark := time.Date(2023, 2, 27, 2, 0, 0, 0, time.UTC)
+	scheduleStorage.On("AdvanceWatermark", schedule, lastWatermark).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.PredictionJobSchedule).LastWatermark = &lastWatermark
+	})
+
+	run, err := svc.TriggerSchedule(context.Background(), predJobEnv, model, version, schedule, scheduledTime, false)
+	require.NoError(t, err)
+	require.NotNil(t, run.Job)
+
+	// the rows of the failed job are read again
+	incremental := run.Job.Config.JobConfig.GetBigquerySource().Incremental
+	assert.Equal(t, "2023-02-27T02:00:00Z", incremental.StartWatermark)
+	assert.Equal(t, "2023-03-01T02:00:00Z", incremental.EndWatermark)
+	assert.Equal(t, "2023-03-01", run.Job.Config.JobConfig.GetBigquerySink().Partition.Value)
+	scheduleStorage.AssertExpectations(t)
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	clock2 "k8s.io/apimachinery/pkg/util/clock"
@@ -28,6 +29,7 @@ import (
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
 	"github.com/caraml-dev/merlin/pkg/imagebuilder"
+	"github.com/caraml-dev/merlin/pkg/protocol"
 	"github.com/caraml-dev/merlin/queue"
 	"github.com/caraml-dev/merlin/queue/work"
 	"github.com/caraml-dev/merlin/storage"
@@ -44,6 +46,8 @@ type PredictionJobService interface {
 	ListContainers(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, predictionJob *models.PredictionJob) ([]*models.Container, error)
 	// StopPredictionJob deletes the spark application resource and cleans up the resource
 	StopPredictionJob(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, id models.ID) (*models.PredictionJob, error)
+	// ListAttempts return all attempts of the prediction job with given ID
+	ListAttempts(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, id models.ID) ([]*models.PredictionJobAttempt, error)
 }
 
 // ListPredictionJobQuery represent query string for list prediction job api
@@ -54,6 +58,8 @@ type ListPredictionJobQuery struct {
 	VersionID models.ID    `schema:"version_id"`
 	Status    models.State `schema:"status"`
 	Error     string       `schema:"error"`
+	// QualityStatus filters the prediction jobs by the outcome of their data quality checks
+	QualityStatus models.QualityStatus `schema:"quality_status"`
 }
 
 type predictionJobService struct {
@@ -63,10 +69,12 @@ type predictionJobService struct {
 	clock            clock2.Clock
 	environmentLabel string
 	producer         queue.Producer
+	quotaService     ProjectQuotaService
+	endpointStore    storage.VersionEndpointStorage
 }
 
-func NewPredictionJobService(batchControllers map[string]batch.Controller, imageBuilder imagebuilder.ImageBuilder, store storage.PredictionJobStorage, clock clock2.Clock, environmentLabel string, producer queue.Producer) PredictionJobService {
-	svc := predictionJobService{store: store, imageBuilder: imageBuilder, batchControllers: batchControllers, clock: clock, environmentLabel: environmentLabel, producer: producer}
+func NewPredictionJobService(batchControllers map[string]batch.Controller, imageBuilder imagebuilder.ImageBuilder, store storage.PredictionJobStorage, clock clock2.Clock, environmentLabel string, producer queue.Producer, quotaService ProjectQuotaService, endpointStore storage.VersionEndpointStorage) PredictionJobService {
+	svc := predictionJobService{store: store, imageBuilder: imageBuilder, batchControllers: batchControllers, clock: clock, environmentLabel: environmentLabel, producer: producer, quotaService: quotaService, endpointStore: endpointStore}
 	return &svc
 }
 
@@ -85,6 +93,7 @@ func (p *predictionJobService) ListPredictionJobs(ctx context.Context, project m
 		ProjectID:      models.ID(project.ID),
 		Status:         query.Status,
 		Error:          query.Error,
+		QualityStatus:  query.QualityStatus,
 	}
 
 	return p.store.List(predJobQuery)
@@ -105,6 +114,7 @@ func (p *predictionJobService) CreatePredictionJob(ctx context.Context, env *mod
 		Team:      model.Project.Team,
 	}
 	predictionJob.Status = models.JobPending
+	predictionJob.Attempt = 1
 	predictionJob.VersionModelID = model.ID
 	predictionJob.ProjectID = model.ProjectID
 	predictionJob.VersionID = version.ID
@@ -116,8 +126,24 @@ func (p *predictionJobService) CreatePredictionJob(ctx context.Context, env *mod
 		return nil, err
 	}
 
-	if err := p.store.Save(predictionJob); err != nil {
-		return nil, errors.Wrapf(err, "failed saving prediction job")
+	if err := p.resolveEndpoint(version, predictionJob); err != nil {
+		return nil, err
+	}
+
+	saveJob := func() error {
+		if err := p.store.Save(predictionJob); err != nil {
+			return errors.Wrapf(err, "failed saving prediction job")
+		}
+		return nil
+	}
+	var err error
+	if p.quotaService != nil {
+		err = p.quotaService.CheckPredictionJobQuota(ctx, model.ProjectID, saveJob)
+	} else {
+		err = saveJob()
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	if err := p.producer.EnqueueJob(&queue.Job{
@@ -182,6 +208,68 @@ func (p *predictionJobService) StopPredictionJob(ctx context.Context, env *model
 	return job, ctl.Stop(ctx, job, project.Name)
 }
 
+// ListAttempts return all attempts of the prediction job with given ID
+func (p *predictionJobService) ListAttempts(ctx context.Context, env *models.Environment, model *models.Model, version *models.Version, id models.ID) ([]*models.PredictionJobAttempt, error) {
+	job, err := p.GetPredictionJob(ctx, env, model, version, id)
+	if err != nil {
+		return nil, err
+	}
+	return p.store.ListAttempts(job.ID)
+}
+
+// resolveEndpoint sets the version endpoint details used by a lightweight or standard transformer prediction job
+func (p *predictionJobService) resolveEndpoint(version *models.Version, job *models.PredictionJob) error {
+	switch {
+	case job.IsLightweight() && job.Config.Lightweight.EndpointID != "":
+		endpoint, err := p.getRunningEndpoint(version, job.Config.Lightweight.EndpointID)
+		if err != nil {
+			return err
+		}
+
+		job.Config.Lightweight.EndpointURL = endpoint.URL
+		job.Config.Lightweight.Protocol = endpoint.Protocol
+	case job.IsStandardTransformer():
+		transformerConfig := job.Config.StandardTransformer
+		endpoint, err := p.getRunningEndpoint(version, transformerConfig.EndpointID)
+		if err != nil {
+			return err
+		}
+
+		transformer := endpoint.Transformer
+		if transformer == nil || !transformer.Enabled || transformer.TransformerType != models.StandardTransformerType {
+			return fmt.Errorf("endpoint %s doesn't have standard transformer", endpoint.ID)
+		}
+		// the model artifact is served over HTTP in the prediction job
+		if transformerConfig.ModelTarget == models.TransformerModelTargetArtifact && endpoint.Protocol == protocol.UpiV1 {
+			return fmt.Errorf("model target %s doesn't support %s endpoint", models.TransformerModelTargetArtifact, protocol.UpiV1)
+		}
+
+		transformerConfig.InferenceServiceName = endpoint.InferenceServiceName
+		transformerConfig.Protocol = endpoint.Protocol
+	}
+	return nil
+}
+
+// getRunningEndpoint returns the version endpoint with the given ID if it belongs to the version and is running
+func (p *predictionJobService) getRunningEndpoint(version *models.Version, id string) (*models.VersionEndpoint, error) {
+	endpointID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint id: %s", id)
+	}
+
+	endpoint, err := p.endpointStore.Get(endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %s is not found: %w", endpointID, err)
+	}
+	if endpoint.VersionModelID != version.ModelID || endpoint.VersionID != version.ID {
+		return nil, fmt.Errorf("endpoint %s doesn't belong to model %s version %s", endpointID, version.ModelID, version.ID)
+	}
+	if endpoint.Status != models.EndpointRunning && endpoint.Status != models.EndpointServing {
+		return nil, fmt.Errorf("endpoint %s is not running", endpointID)
+	}
+	return endpoint, nil
+}
+
 func (p *predictionJobService) applyDefaults(env *models.Environment, job *models.PredictionJob) *models.PredictionJob {
 	if job.Config == nil {
 		job.Config = &models.Config{}
@@ -211,6 +299,22 @@ func (p *predictionJobService) applyDefaults(env *models.Environment, job *model
 		job.Config.ResourceRequest.ExecutorReplica = env.DefaultPredictionJobResourceRequest.ExecutorReplica
 	}
 
+	if job.Config.Mode == models.PredictionJobModeLightweight {
+		if job.Config.Lightweight == nil {
+			job.Config.Lightweight = &models.LightweightJobConfig{}
+		}
+		job.Config.Lightweight.ApplyDefaults()
+	}
+
+	if job.Config.Mode == models.PredictionJobModeStandardTransformer {
+		if job.Config.StandardTransformer == nil {
+			job.Config.StandardTransformer = &models.StandardTransformerJobConfig{}
+		}
+		job.Config.StandardTransformer.ApplyDefaults()
+	}
+
+	job.Config.ApplyIncrementalDefaults(p.clock.Now())
+
 	return job
 }
 
@@ -237,5 +341,58 @@ func (p *predictionJobService) validate(model *models.Model, _ *models.Version,
 	if err != nil {
 		return fmt.Errorf("invalid executor memory request: %s", job.Config.ResourceRequest.ExecutorMemoryRequest)
 	}
+	if job.Config.RetryPolicy != nil {
+		if err := job.Config.RetryPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := job.Config.ValidateIncremental(); err != nil {
+		return err
+	}
+	if job.Config.QualityChecks != nil {
+		if err := p.validateQualityChecks(model, job); err != nil {
+			return err
+		}
+	}
+	switch job.Config.Mode {
+	case "", models.PredictionJobModeSpark:
+	case models.PredictionJobModeLightweight:
+		if err := job.Config.Lightweight.Validate(job.Environment.LightweightPredictionJobConfig, job.Config.JobConfig); err != nil {
+			return err
+		}
+	case models.PredictionJobModeStandardTransformer:
+		if err := job.Config.StandardTransformer.Validate(job.Environment.LightweightPredictionJobConfig, job.Config.JobConfig); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown prediction job mode: %s", job.Config.Mode)
+	}
+	return nil
+}
+
+// validateQualityChecks checks the thresholds of the quality checks and the reference job of the drift check,
+// which must be a prediction job of the same model
+func (p *predictionJobService) validateQualityChecks(model *models.Model, job *models.PredictionJob) error {
+	if job.Config.Mode != "" && job.Config.Mode != models.PredictionJobModeSpark {
+		return fmt.Errorf("quality checks are only supported by prediction job in spark mode")
+	}
+
+	checks := job.Config.QualityChecks
+	if err := checks.Validate(); err != nil {
+		return err
+	}
+	if checks.Drift == nil {
+		return nil
+	}
+	// the quantiles of the reference job are only set on submission
+	checks.Drift.ReferenceQuantiles = nil
+
+	reference, err := p.store.Get(checks.Drift.ReferenceJobID)
+	if err != nil {
+		return fmt.Errorf("reference job %s of drift check not found", checks.Drift.ReferenceJobID)
+	}
+	if reference.VersionModelID != model.ID {
+		return fmt.Errorf("reference job %s of drift check doesn't belong to model %s", reference.ID, model.Name)
+	}
 	return nil
 }
@@ -21,6 +21,8 @@ import (
 	"testing"
 	"time"
 
+	jobspec "github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,6 +33,7 @@ import (
 	"github.com/caraml-dev/merlin/mlp"
 	"github.com/caraml-dev/merlin/models"
 	imageBuilderMock "github.com/caraml-dev/merlin/pkg/imagebuilder/mocks"
+	"github.com/caraml-dev/merlin/pkg/protocol"
 	queueMock "github.com/caraml-dev/merlin/queue/mocks"
 	storageMock "github.com/caraml-dev/merlin/storage/mocks"
 )
@@ -108,7 +111,8 @@ var (
 				},
 			},
 		},
-		Status: models.JobPending,
+		Status:  models.JobPending,
+		Attempt: 1,
 	}
 	reqJob = &models.PredictionJob{
 		VersionID:      3,
@@ -260,6 +264,428 @@ func TestInvalidResourceRequest(t *testing.T) {
 	}
 }
 
+func TestInvalidRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		retryPolicy *models.PredictionJobRetryPolicy
+		wantErrMsg  string
+	}{
+		{
+			name:        "no attempts",
+			retryPolicy: &models.PredictionJobRetryPolicy{MaxAttempts: 0},
+			wantErrMsg:  "max attempts must be between 1 and 10",
+		},
+		{
+			name:        "invalid backoff",
+			retryPolicy: &models.PredictionJobRetryPolicy{MaxAttempts: 3, Backoff: "soon"},
+			wantErrMsg:  "invalid retry backoff: soon",
+		},
+		{
+			name:        "unknown failure reason",
+			retryPolicy: &models.PredictionJobRetryPolicy{MaxAttempts: 3, RetryableReasons: []models.FailureReason{"invalid_model"}},
+			wantErrMsg:  "unknown failure reason: invalid_model",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, _, _, _, _ := newMockPredictionJobService()
+			req := &models.PredictionJob{
+				VersionID:      3,
+				VersionModelID: 1,
+				Config: &models.Config{
+					RetryPolicy: test.retryPolicy,
+				},
+			}
+			_, err := svc.CreatePredictionJob(context.Background(), predJobEnv, model, version, req)
+			assert.Error(t, err)
+			assert.Equal(t, test.wantErrMsg, err.Error())
+		})
+	}
+}
+
+func TestCreateIncrementalPredictionJob(t *testing.T) {
+	tests := []struct {
+		name        string
+		incremental *jobspec.IncrementalSource
+		partition   *jobspec.SinkPartition
+		wantErrMsg  string
+	}{
+		{
+			name:        "watermark and partition default to now",
+			incremental: &jobspec.IncrementalSource{WatermarkColumn: "event_timestamp", StartWatermark: "2023-03-01T00:00:00Z"},
+			partition:   &jobspec.SinkPartition{Column: "prediction_date"},
+		},
+		{
+			name:        "missing watermark column",
+			incremental: &jobspec.IncrementalSource{},
+			wantErrMsg:  "watermark column of incremental source is required",
+		},
+		{
+			name:       "invalid partition",
+			partition:  &jobspec.SinkPartition{Column: "prediction_date", Value: "yesterday"},
+			wantErrMsg: "invalid sink partition yesterday: must be a date in YYYY-MM-DD format",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, _, _, mockStorage, mockJobProducer := newMockPredictionJobService()
+			mockStorage.On("Save", mock.Anything).Return(nil)
+			mockJobProducer.On("EnqueueJob", mock.Anything).Return(nil)
+
+			req := &models.PredictionJob{
+				VersionID:      3,
+				VersionModelID: 1,
+				Config: &models.Config{
+					JobConfig: &jobspec.PredictionJob{
+						Source: &jobspec.PredictionJob_BigquerySource{
+							BigquerySource: &jobspec.BigQuerySource{Table: "project.dataset.features", Incremental: test.incremental},
+						},
+						Sink: &jobspec.PredictionJob_BigquerySink{
+							BigquerySink: &jobspec.BigQuerySink{Table: "project.dataset.predictions", Partition: test.partition},
+						},
+					},
+				},
+			}
+			j, err := svc.CreatePredictionJob(context.Background(), predJobEnv, model, version, req)
+			if test.wantErrMsg != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.wantErrMsg, err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, now.UTC().Format(time.RFC3339), j.Config.JobConfig.GetBigquerySource().Incremental.EndWatermark)
+			assert.Equal(t, now.Format("2006-01-02"), j.Config.JobConfig.GetBigquerySink().Partition.Value)
+		})
+	}
+}
+
+func TestCreatePredictionJobWithQualityChecks(t *testing.T) {
+	maxNullFraction := 0.01
+	tests := []struct {
+		name         string
+		mode         models.PredictionJobMode
+		checks       *models.PredictionJobQualityChecks
+		referenceJob *models.PredictionJob
+		wantErrMsg   string
+	}{
+		{
+			name: "drift against a prediction job of the same model",
+			checks: &models.PredictionJobQualityChecks{
+				MaxNullFraction: &maxNullFraction,
+				Drift:           &models.PredictionJobDriftCheck{ReferenceJobID: 5, MaxPSI: 0.2, ReferenceQuantiles: []float64{0, 1}},
+			},
+			referenceJob: &models.PredictionJob{ID: 5, VersionModelID: model.ID},
+		},
+		{
+			name:       "lightweight mode",
+			mode:       models.PredictionJobModeLightweight,
+			checks:     &models.PredictionJobQualityChecks{MaxNullFraction: &maxNullFraction},
+			wantErrMsg: "quality checks are only supported by prediction job in spark mode",
+		},
+		{
+			name:       "invalid threshold",
+			checks:     &models.PredictionJobQualityChecks{Drift: &models.PredictionJobDriftCheck{ReferenceJobID: 5}},
+			wantErrMsg: "max psi of drift check must be positive",
+		},
+		{
+			name:         "reference job of another model",
+			checks:       &models.PredictionJobQualityChecks{Drift: &models.PredictionJobDriftCheck{ReferenceJobID: 5, MaxPSI: 0.2}},
+			referenceJob: &models.PredictionJob{ID: 5, VersionModelID: 2},
+			wantErrMsg:   "reference job 5 of drift check doesn't belong to model my-model",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, _, _, mockStorage, mockJobProducer := newMockPredictionJobService()
+			mockStorage.On("Save", mock.Anything).Return(nil)
+			if test.referenceJob != nil {
+				mockStorage.On("Get", test.referenceJob.ID).Return(test.referenceJob, nil)
+			}
+			mockJobProducer.On("EnqueueJob", mock.Anything).Return(nil)
+
+			req := &models.PredictionJob{
+				VersionID:      3,
+				VersionModelID: 1,
+				Config: &models.Config{
+					JobConfig:     &jobspec.PredictionJob{},
+					Mode:          test.mode,
+					QualityChecks: test.checks,
+				},
+			}
+			j, err := svc.CreatePredictionJob(context.Background(), predJobEnv, model, version, req)
+			if test.wantErrMsg != "" {
+				assert.EqualError(t, err, test.wantErrMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.checks, j.Config.QualityChecks)
+			if j.Config.QualityChecks.Drift != nil {
+				// the quantiles of the reference job are only set on submission
+				assert.Nil(t, j.Config.QualityChecks.Drift.ReferenceQuantiles)
+			}
+		})
+	}
+}
+
+func TestCreateLightweightPredictionJob(t *testing.T) {
+	endpointID := uuid.New()
+	lightweightEnv := *predJobEnv
+	lightweightEnv.LightweightPredictionJobConfig = &models.LightweightPredictionJobConfig{
+		Enabled:        true,
+		MaxConcurrency: 8,
+		MaxBatchSize:   5000,
+	}
+	gcsSource := &jobspec.PredictionJob{
+		Source: &jobspec.PredictionJob_GcsSource{
+			GcsSource: &jobspec.GcsSource{Format: jobspec.FileFormat_CSV, Uri: "gs://bucket-name/input.csv"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		env         *models.Environment
+		jobConfig   *jobspec.PredictionJob
+		lightweight *models.LightweightJobConfig
+		endpoint    *models.VersionEndpoint
+		wantErrMsg  string
+	}{
+		{
+			name:       "disabled in environment",
+			env:        predJobEnv,
+			jobConfig:  gcsSource,
+			wantErrMsg: "lightweight prediction job is not enabled in the environment",
+		},
+		{
+			name: "bigquery source",
+			env:  &lightweightEnv,
+			jobConfig: &jobspec.PredictionJob{
+				Source: &jobspec.PredictionJob_BigquerySource{
+					BigquerySource: &jobspec.BigQuerySource{Table: "project.dataset.table"},
+				},
+			},
+			wantErrMsg: "lightweight prediction job only supports file source",
+		},
+		{
+			name:        "concurrency exceeds environment maximum",
+			env:         &lightweightEnv,
+			jobConfig:   gcsSource,
+			lightweight: &models.LightweightJobConfig{Concurrency: 16},
+			wantErrMsg:  "concurrency 16 exceeds the maximum of 8",
+		},
+		{
+			name:        "endpoint is not running",
+			env:         &lightweightEnv,
+			jobConfig:   gcsSource,
+			lightweight: &models.LightweightJobConfig{EndpointID: endpointID.String()},
+			endpoint: &models.VersionEndpoint{
+				ID:             endpointID,
+				VersionID:      version.ID,
+				VersionModelID: model.ID,
+				Status:         models.EndpointFailed,
+			},
+			wantErrMsg: fmt.Sprintf("endpoint %s is not running", endpointID),
+		},
+		{
+			name:        "endpoint of another version",
+			env:         &lightweightEnv,
+			jobConfig:   gcsSource,
+			lightweight: &models.LightweightJobConfig{EndpointID: endpointID.String()},
+			endpoint: &models.VersionEndpoint{
+				ID:             endpointID,
+				VersionID:      version.ID + 1,
+				VersionModelID: model.ID,
+				Status:         models.EndpointRunning,
+			},
+			wantErrMsg: fmt.Sprintf("endpoint %s doesn't belong to model 1 version 3", endpointID),
+		},
+		{
+			name:        "endpoint is resolved",
+			env:         &lightweightEnv,
+			jobConfig:   gcsSource,
+			lightweight: &models.LightweightJobConfig{EndpointID: endpointID.String()},
+			endpoint: &models.VersionEndpoint{
+				ID:             endpointID,
+				VersionID:      version.ID,
+				VersionModelID: model.ID,
+				Status:         models.EndpointRunning,
+				URL:            "my-model-3.my-project.example.com",
+				Protocol:       protocol.HttpJson,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockStorage := &storageMock.PredictionJobStorage{}
+			mockStorage.On("Save", mock.Anything).Return(nil)
+			mockJobProducer := &queueMock.Producer{}
+			mockJobProducer.On("EnqueueJob", mock.Anything).Return(nil)
+			mockEndpointStorage := &storageMock.VersionEndpointStorage{}
+			if test.endpoint != nil {
+				mockEndpointStorage.On("Get", endpointID).Return(test.endpoint, nil)
+			}
+			svc := NewPredictionJobService(map[string]batch.Controller{}, &imageBuilderMock.ImageBuilder{}, mockStorage, clock.NewFakeClock(now), environmentLabel, mockJobProducer, nil, mockEndpointStorage)
+
+			req := &models.PredictionJob{
+				VersionID:      3,
+				VersionModelID: 1,
+				Config: &models.Config{
+					JobConfig:   test.jobConfig,
+					Mode:        models.PredictionJobModeLightweight,
+					Lightweight: test.lightweight,
+				},
+			}
+			j, err := svc.CreatePredictionJob(context.Background(), test.env, model, version, req)
+			if test.wantErrMsg != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.wantErrMsg, err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.DefaultLightweightConcurrency, j.Config.Lightweight.Concurrency)
+			assert.Equal(t, models.DefaultLightweightBatchSize, j.Config.Lightweight.BatchSize)
+			assert.Equal(t, test.endpoint.URL, j.Config.Lightweight.EndpointURL)
+			assert.Equal(t, test.endpoint.Protocol, j.Config.Lightweight.Protocol)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateStandardTransformerPredictionJob(t *testing.T) {
+	endpointID := uuid.New()
+	lightweightEnv := *predJobEnv
+	lightweightEnv.LightweightPredictionJobConfig = &models.LightweightPredictionJobConfig{
+		Enabled:        true,
+		MaxConcurrency: 8,
+	}
+	jsonLines := &jobspec.PredictionJob{
+		Source: &jobspec.PredictionJob_GcsSource{
+			GcsSource: &jobspec.GcsSource{Format: jobspec.FileFormat_JSON, Uri: "gs://bucket-name/requests.jsonl"},
+		},
+		Sink: &jobspec.PredictionJob_GcsSink{
+			GcsSink: &jobspec.GcsSink{Format: jobspec.FileFormat_JSON, Uri: "gs://bucket-name/responses.jsonl"},
+		},
+	}
+	standardTransformer := &models.Transformer{
+		Enabled:         true,
+		TransformerType: models.StandardTransformerType,
+	}
+
+	tests := []struct {
+		name        string
+		modelTarget models.TransformerModelTarget
+		endpoint    *models.VersionEndpoint
+		wantErrMsg  string
+	}{
+		{
+			name: "endpoint without transformer",
+			endpoint: &models.VersionEndpoint{
+				ID:             endpointID,
+				VersionID:      version.ID,
+				VersionModelID: model.ID,
+				Status:         models.EndpointRunning,
+			},
+			wantErrMsg: fmt.Sprintf("endpoint %s doesn't have standard transformer", endpointID),
+		},
+		{
+			name: "endpoint with custom transformer",
+			endpoint: &models.VersionEndpoint{
+				ID:             endpointID,
+				VersionID:      version.ID,
+				VersionModelID: model.ID,
+				Status:         models.EndpointRunning,
+				Transformer:    &models.Transformer{Enabled: true, TransformerType: models.CustomTransformerType},
+			},
+			wantErrMsg: fmt.Sprintf("endpoint %s doesn't have standard transformer", endpointID),
+		},
+		{
+			name:        "model artifact of UPI endpoint",
+			modelTarget: models.TransformerModelTargetArtifact,
+			endpoint: &models.VersionEndpoint{
+				ID:             endpointID,
+				VersionID:      version.ID,
+				VersionModelID: model.ID,
+				Status:         models.EndpointRunning,
+				Protocol:       protocol.UpiV1,
+				Transformer:    standardTransformer,
+			},
+			wantErrMsg: "model target artifact doesn't support UPI_V1 endpoint",
+		},
+		{
+			name: "endpoint is resolved",
+			endpoint: &models.VersionEndpoint{
+				ID:                   endpointID,
+				VersionID:            version.ID,
+				VersionModelID:       model.ID,
+				Status:               models.EndpointRunning,
+				InferenceServiceName: "my-model-3",
+				Protocol:             protocol.HttpJson,
+				Transformer:          standardTransformer,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockStorage := &storageMock.PredictionJobStorage{}
+			mockStorage.On("Save", mock.Anything).Return(nil)
+			mockJobProducer := &queueMock.Producer{}
+			mockJobProducer.On("EnqueueJob", mock.Anything).Return(nil)
+			mockEndpointStorage := &storageMock.VersionEndpointStorage{}
+			mockEndpointStorage.On("Get", endpointID).Return(test.endpoint, nil)
+			svc := NewPredictionJobService(map[string]batch.Controller{}, &imageBuilderMock.ImageBuilder{}, mockStorage, clock.NewFakeClock(now), environmentLabel, mockJobProducer, nil, mockEndpointStorage)
+
+			req := &models.PredictionJob{
+				VersionID:      3,
+				VersionModelID: 1,
+				Config: &models.Config{
+					JobConfig: jsonLines,
+					Mode:      models.PredictionJobModeStandardTransformer,
+					StandardTransformer: &models.StandardTransformerJobConfig{
+						EndpointID:  endpointID.String(),
+						ModelTarget: test.modelTarget,
+					},
+				},
+			}
+			j, err := svc.CreatePredictionJob(context.Background(), &lightweightEnv, model, version, req)
+			if test.wantErrMsg != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.wantErrMsg, err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.TransformerModelTargetEndpoint, j.Config.StandardTransformer.ModelTarget)
+			assert.Equal(t, models.DefaultLightweightConcurrency, j.Config.StandardTransformer.Concurrency)
+			assert.Equal(t, test.endpoint.InferenceServiceName, j.Config.StandardTransformer.InferenceServiceName)
+			assert.Equal(t, test.endpoint.Protocol, j.Config.StandardTransformer.Protocol)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListPredictionJobAttempts(t *testing.T) {
+	attempts := []*models.PredictionJobAttempt{
+		{
+			JobID:                job.ID,
+			Attempt:              1,
+			Status:               models.JobFailed,
+			Error:                "ExecutorLostFailure",
+			FailureReason:        models.FailureReasonPreemption,
+			SparkApplicationName: job.Name,
+		},
+	}
+	svc, _, _, mockStorage, _ := newMockPredictionJobService()
+	mockStorage.On("Get", job.ID).Return(job, nil)
+	mockStorage.On("ListAttempts", job.ID).Return(attempts, nil)
+
+	a, err := svc.ListAttempts(context.Background(), predJobEnv, model, version, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, attempts, a)
+	mockStorage.AssertExpectations(t)
+}
+
 func TestPredictionJobService_ListContainers(t *testing.T) {
 	project := mlp.Project{ID: 1, Name: "my-project"}
 	model := &models.Model{ID: 1, Name: "model", Type: models.ModelTypeXgboost, Project: project, ProjectID: models.ID(project.ID)}
@@ -364,5 +790,5 @@ func newMockPredictionJobService() (PredictionJobService, map[string]batch.Contr
 	mockImageBuilder := &imageBuilderMock.ImageBuilder{}
 	mockStorage := &storageMock.PredictionJobStorage{}
 	mockClock := clock.NewFakeClock(now)
-	return NewPredictionJobService(mockControllers, mockImageBuilder, mockStorage, mockClock, environmentLabel, mockJobProducer), mockControllers, mockImageBuilder, mockStorage, mockJobProducer
+	return NewPredictionJobService(mockControllers, mockImageBuilder, mockStorage, mockClock, environmentLabel, mockJobProducer, nil, &storageMock.VersionEndpointStorage{}), mockControllers, mockImageBuilder, mockStorage, mockJobProducer
 }
@@ -0,0 +1,166 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+// ProjectQuotaService manages the resource quota of projects and enforces them on deployments
+type ProjectQuotaService interface {
+	// GetQuota return the quota of a project, it returns nil if the project doesn't have any quota
+	GetQuota(ctx context.Context, projectID models.ID) (*models.ProjectQuota, error)
+	// SaveQuota create or update the quota of a project
+	SaveQuota(ctx context.Context, quota *models.ProjectQuota) (*models.ProjectQuota, error)
+	// GetUsage return the current resource usage of a project against its quota
+	GetUsage(ctx context.Context, projectID models.ID) (*models.ProjectQuotaUsage, error)
+	// CheckEndpointQuota return error if deploying the given endpoint would exceed the project quota,
+	// otherwise it calls save while holding the quota lock so that concurrent deployments can't exceed the quota
+	CheckEndpointQuota(ctx context.Context, projectID models.ID, endpoint *models.VersionEndpoint, save func() error) error
+	// CheckPredictionJobQuota return error if starting a new prediction job would exceed the project quota,
+	// otherwise it calls save while holding the quota lock so that concurrent prediction jobs can't exceed the quota
+	CheckPredictionJobQuota(ctx context.Context, projectID models.ID, save func() error) error
+}
+
+type projectQuotaService struct {
+	quotaStorage         storage.ProjectQuotaStorage
+	endpointStorage      storage.VersionEndpointStorage
+	predictionJobStorage storage.PredictionJobStorage
+}
+
+// NewProjectQuotaService creates a new ProjectQuotaService
+func NewProjectQuotaService(quotaStorage storage.ProjectQuotaStorage, endpointStorage storage.VersionEndpointStorage, predictionJobStorage storage.PredictionJobStorage) ProjectQuotaService {
+	return &projectQuotaService{
+		quotaStorage:         quotaStorage,
+		endpointStorage:      endpointStorage,
+		predictionJobStorage: predictionJobStorage,
+	}
+}
+
+func (s *projectQuotaService) GetQuota(ctx context.Context, projectID models.ID) (*models.ProjectQuota, error) {
+	quota, err := s.quotaStorage.Get(projectID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return quota, nil
+}
+
+func (s *projectQuotaService) SaveQuota(ctx context.Context, quota *models.ProjectQuota) (*models.ProjectQuota, error) {
+	if err := quota.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+
+	existing, err := s.GetQuota(ctx, quota.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	quota.ID = 0
+	if existing != nil {
+		quota.ID = existing.ID
+		quota.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.quotaStorage.Save(quota); err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+func (s *projectQuotaService) GetUsage(ctx context.Context, projectID models.ID) (*models.ProjectQuotaUsage, error) {
+	quota, err := s.GetQuota(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.endpointUsage(projectID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	usage.ConcurrentPredictionJobs, err = s.predictionJobStorage.CountActivePredictionJobs(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProjectQuotaUsage{
+		Quota: quota,
+		Usage: usage,
+	}, nil
+}
+
+func (s *projectQuotaService) CheckEndpointQuota(ctx context.Context, projectID models.ID, endpoint *models.VersionEndpoint, save func() error) error {
+	return s.quotaStorage.Lock(projectID, func(quota *models.ProjectQuota) error {
+		if quota == nil {
+			return save()
+		}
+
+		// the endpoint being redeployed is replaced, hence only its new configuration is counted
+		usage, err := s.endpointUsage(projectID, endpoint)
+		if err != nil {
+			return err
+		}
+		usage.AddEndpoint(endpoint)
+
+		if err := quota.CheckEndpointUsage(usage); err != nil {
+			return merror.NewInvalidInputError(err.Error())
+		}
+		return save()
+	})
+}
+
+func (s *projectQuotaService) CheckPredictionJobQuota(ctx context.Context, projectID models.ID, save func() error) error {
+	return s.quotaStorage.Lock(projectID, func(quota *models.ProjectQuota) error {
+		if quota == nil {
+			return save()
+		}
+
+		count, err := s.predictionJobStorage.CountActivePredictionJobs(projectID)
+		if err != nil {
+			return err
+		}
+
+		usage := &models.ProjectResourceUsage{ConcurrentPredictionJobs: count + 1}
+		if err := quota.CheckPredictionJobUsage(usage); err != nil {
+			return merror.NewInvalidInputError(err.Error())
+		}
+		return save()
+	})
+}
+
+// endpointUsage computes the resources used by active endpoints in the project, skipping the excluded endpoint
+func (s *projectQuotaService) endpointUsage(projectID models.ID, excluded *models.VersionEndpoint) (*models.ProjectResourceUsage, error) {
+	endpoints, err := s.endpointStorage.ListActiveEndpointsInProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.ProjectResourceUsage{}
+	for _, endpoint := range endpoints {
+		if excluded != nil && endpoint.ID == excluded.ID {
+			continue
+		}
+		usage.AddEndpoint(endpoint)
+	}
+	return usage, nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func TestProjectQuotaService_GetUsage(t *testing.T) {
+	maxEndpoints := 5
+	quota := &models.ProjectQuota{ID: 1, ProjectID: 1, MaxCPU: "10", MaxEndpoints: &maxEndpoints}
+	endpoints := []*models.VersionEndpoint{
+		{
+			ID: uuid.New(),
+			ResourceRequest: &models.ResourceRequest{
+				MinReplica:    1,
+				MaxReplica:    2,
+				CPURequest:    resource.MustParse("500m"),
+				MemoryRequest: resource.MustParse("1Gi"),
+			},
+			Transformer: &models.Transformer{
+				Enabled: true,
+				ResourceRequest: &models.ResourceRequest{
+					MinReplica:    1,
+					MaxReplica:    1,
+					CPURequest:    resource.MustParse("1"),
+					MemoryRequest: resource.MustParse("512Mi"),
+				},
+			},
+		},
+		{
+			ID: uuid.New(),
+			ResourceRequest: &models.ResourceRequest{
+				MinReplica:    0,
+				MaxReplica:    3,
+				CPURequest:    resource.MustParse("1"),
+				MemoryRequest: resource.MustParse("1Gi"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		quota         *models.ProjectQuota
+		quotaErr      error
+		want          *models.ProjectQuotaUsage
+		expectedError string
+	}{
+		{
+			name:  "project with quota",
+			quota: quota,
+			want: &models.ProjectQuotaUsage{
+				Quota: quota,
+				Usage: &models.ProjectResourceUsage{
+					CPU:                      resource.MustParse("5"),
+					Memory:                   resource.MustParse("5632Mi"),
+					Replicas:                 6,
+					Endpoints:                2,
+					ConcurrentPredictionJobs: 3,
+				},
+			},
+		},
+		{
+			name:     "project without quota",
+			quotaErr: gorm.ErrRecordNotFound,
+			want: &models.ProjectQuotaUsage{
+				Usage: &models.ProjectResourceUsage{
+					CPU:                      resource.MustParse("5"),
+					Memory:                   resource.MustParse("5632Mi"),
+					Replicas:                 6,
+					Endpoints:                2,
+					ConcurrentPredictionJobs: 3,
+				},
+			},
+		},
+		{
+			name:          "storage error",
+			quotaErr:      errors.New("db is down"),
+			expectedError: "db is down",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotaStorage := &mocks.ProjectQuotaStorage{}
+			quotaStorage.On("Get", models.ID(1)).Return(tt.quota, tt.quotaErr)
+			endpointStorage := &mocks.VersionEndpointStorage{}
+			endpointStorage.On("ListActiveEndpointsInProject", models.ID(1)).Return(endpoints, nil)
+			jobStorage := &mocks.PredictionJobStorage{}
+			jobStorage.On("CountActivePredictionJobs", models.ID(1)).Return(3, nil)
+
+			svc := NewProjectQuotaService(quotaStorage, endpointStorage, jobStorage)
+			got, err := svc.GetUsage(context.Background(), models.ID(1))
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Quota, got.Quota)
+			assert.True(t, tt.want.Usage.CPU.Equal(got.Usage.CPU), "cpu: %s", got.Usage.CPU.String())
+			assert.True(t, tt.want.Usage.Memory.Equal(got.Usage.Memory), "memory: %s", got.Usage.Memory.String())
+			assert.Equal(t, tt.want.Usage.Replicas, got.Usage.Replicas)
+			assert.Equal(t, tt.want.Usage.Endpoints, got.Usage.Endpoints)
+			assert.Equal(t, tt.want.Usage.ConcurrentPredictionJobs, got.Usage.ConcurrentPredictionJobs)
+		})
+	}
+}
+
+func TestProjectQuotaService_SaveQuota(t *testing.T) {
+	negative := -1
+	existing := &models.ProjectQuota{ID: 7, ProjectID: 1, MaxCPU: "10"}
+
+	tests := []struct {
+		name          string
+		quota         *models.ProjectQuota
+		existing      *models.ProjectQuota
+		wantID        models.ID
+		expectedError string
+	}{
+		{
+			name:   "create new quota",
+			quota:  &models.ProjectQuota{ID: 100, ProjectID: 1, MaxCPU: "4"},
+			wantID: 0,
+		},
+		{
+			name:     "update existing quota",
+			quota:    &models.ProjectQuota{ProjectID: 1, MaxMemory: "16Gi"},
+			existing: existing,
+			wantID:   7,
+		},
+		{
+			name:          "invalid cpu",
+			quota:         &models.ProjectQuota{ProjectID: 1, MaxCPU: "four"},
+			expectedError: "invalid input: invalid max cpu: four",
+		},
+		{
+			name:          "negative limit",
+			quota:         &models.ProjectQuota{ProjectID: 1, MaxEndpoints: &negative},
+			expectedError: "invalid input: invalid max endpoints: -1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotaStorage := &mocks.ProjectQuotaStorage{}
+			if tt.existing != nil {
+				quotaStorage.On("Get", models.ID(1)).Return(tt.existing, nil)
+			} else {
+				quotaStorage.On("Get", models.ID(1)).Return(nil, gorm.ErrRecordNotFound)
+			}
+			quotaStorage.On("Save", mock.Anything).Return(nil)
+
+			svc := NewProjectQuotaService(quotaStorage, &mocks.VersionEndpointStorage{}, &mocks.PredictionJobStorage{})
+			got, err := svc.SaveQuota(context.Background(), tt.quota)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.True(t, errors.Is(err, merror.InvalidInputError))
+				quotaStorage.AssertNotCalled(t, "Save", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantID, got.ID)
+			quotaStorage.AssertCalled(t, "Save", tt.quota)
+		})
+	}
+}
+
+func TestProjectQuotaService_CheckPredictionJobQuota(t *testing.T) {
+	maxJobs := 2
+
+	tests := []struct {
+		name          string
+		quota         *models.ProjectQuota
+		activeJobs    int
+		expectedError string
+	}{
+		{
+			name:       "no quota",
+			activeJobs: 10,
+		},
+		{
+			name:       "within quota",
+			quota:      &models.ProjectQuota{ProjectID: 1, MaxConcurrentPredictionJobs: &maxJobs},
+			activeJobs: 1,
+		},
+		{
+			name:          "quota exceeded",
+			quota:         &models.ProjectQuota{ProjectID: 1, MaxConcurrentPredictionJobs: &maxJobs},
+			activeJobs:    2,
+			expectedError: "invalid input: number of concurrent prediction jobs 3 exceeds project quota 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotaStorage := &mocks.ProjectQuotaStorage{}
+			quotaStorage.On("Lock", models.ID(1), mock.Anything).Return(func(_ models.ID, fn func(*models.ProjectQuota) error) error {
+				return fn(tt.quota)
+			})
+			jobStorage := &mocks.PredictionJobStorage{}
+			jobStorage.On("CountActivePredictionJobs", models.ID(1)).Return(tt.activeJobs, nil)
+
+			saved := false
+			save := func() error {
+				saved = true
+				return nil
+			}
+
+			svc := NewProjectQuotaService(quotaStorage, &mocks.VersionEndpointStorage{}, jobStorage)
+			err := svc.CheckPredictionJobQuota(context.Background(), models.ID(1), save)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.False(t, saved)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, saved)
+		})
+	}
+}
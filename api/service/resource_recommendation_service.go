@@ -0,0 +1,146 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	kserveconstant "github.com/kserve/kserve/pkg/constants"
+	"github.com/pkg/errors"
+	prommodel "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/prometheus"
+)
+
+const (
+	transformerContainerName = "transformer"
+
+	// cpuUsageExprFormat returns the given percentile of the per-pod cpu usage over the lookback window
+	cpuUsageExprFormat = "max(quantile_over_time(%f, rate(container_cpu_usage_seconds_total{cluster_name=\"%s\",namespace=\"%s\",pod=~\"%s-%s-.*\",container=\"%s\"}[5m])[%s:5m]))"
+	// memoryUsageExprFormat returns the peak per-pod memory working set over the lookback window
+	memoryUsageExprFormat = "max(max_over_time(container_memory_working_set_bytes{cluster_name=\"%s\",namespace=\"%s\",pod=~\"%s-%s-.*\",container=\"%s\"}[%s]))"
+
+	bytesInMi = 1024 * 1024
+)
+
+// ResourceRecommendationService suggests right-sized resource request of a version endpoint from its historical usage
+type ResourceRecommendationService interface {
+	// RecommendResourceRequest returns the recommended resource request of the endpoint's model and transformer
+	RecommendResourceRequest(ctx context.Context, endpoint *models.VersionEndpoint) (*models.EndpointResourceRecommendation, error)
+}
+
+type resourceRecommendationService struct {
+	prometheusClient prometheus.Client
+	config           config.ResourceRecommendationConfig
+}
+
+// NewResourceRecommendationService creates a new ResourceRecommendationService
+func NewResourceRecommendationService(prometheusClient prometheus.Client, config config.ResourceRecommendationConfig) ResourceRecommendationService {
+	return &resourceRecommendationService{
+		prometheusClient: prometheusClient,
+		config:           config,
+	}
+}
+
+func (s *resourceRecommendationService) RecommendResourceRequest(ctx context.Context, endpoint *models.VersionEndpoint) (*models.EndpointResourceRecommendation, error) {
+	now := time.Now()
+
+	modelRecommendation, err := s.recommend(ctx, endpoint, string(kserveconstant.Predictor), kserveconstant.InferenceServiceContainerName, endpoint.ResourceRequest, now)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to compute model resource recommendation")
+	}
+
+	recommendation := &models.EndpointResourceRecommendation{
+		LookbackWindow: prommodel.Duration(s.config.LookbackWindow).String(),
+		Model:          modelRecommendation,
+	}
+
+	if endpoint.Transformer != nil && endpoint.Transformer.Enabled {
+		transformerRecommendation, err := s.recommend(ctx, endpoint, string(kserveconstant.Transformer), transformerContainerName, endpoint.Transformer.ResourceRequest, now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to compute transformer resource recommendation")
+		}
+		recommendation.Transformer = transformerRecommendation
+	}
+
+	return recommendation, nil
+}
+
+func (s *resourceRecommendationService) recommend(ctx context.Context, endpoint *models.VersionEndpoint, component string, container string, current *models.ResourceRequest, ts time.Time) (*models.ResourceRecommendation, error) {
+	if current == nil {
+		return nil, fmt.Errorf("resource request of %s is not available", component)
+	}
+
+	cluster := ""
+	if endpoint.Environment != nil {
+		cluster = endpoint.Environment.Cluster
+	}
+	window := prommodel.Duration(s.config.LookbackWindow).String()
+
+	cpuQuery := fmt.Sprintf(cpuUsageExprFormat, s.config.CPUPercentile, cluster, endpoint.Namespace, endpoint.InferenceServiceName, component, container, window)
+	cpuUsage, err := s.prometheusClient.QueryScalar(ctx, cpuQuery, ts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to query cpu usage")
+	}
+
+	memoryQuery := fmt.Sprintf(memoryUsageExprFormat, cluster, endpoint.Namespace, endpoint.InferenceServiceName, component, container, window)
+	memoryUsage, err := s.prometheusClient.QueryScalar(ctx, memoryQuery, ts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to query memory usage")
+	}
+
+	recommended := &models.ResourceRequest{
+		MinReplica:    current.MinReplica,
+		MaxReplica:    current.MaxReplica,
+		CPURequest:    recommendCPU(cpuUsage, s.config.Headroom),
+		MemoryRequest: recommendMemory(memoryUsage, s.config.Headroom),
+		GPURequest:    current.GPURequest,
+	}
+
+	recommendation := &models.ResourceRecommendation{
+		CurrentResourceRequest:     current,
+		RecommendedResourceRequest: recommended,
+	}
+	if endpoint.Environment != nil && endpoint.Environment.UnitCost != nil {
+		recommendation.CurrentCostEstimation = endpoint.Environment.UnitCost.EstimateCost(current)
+		recommendation.RecommendedCostEstimation = endpoint.Environment.UnitCost.EstimateCost(recommended)
+	}
+
+	return recommendation, nil
+}
+
+// recommendCPU adds headroom to the cpu usage (in cores) and rounds it up to the nearest 10 millicores
+func recommendCPU(usage float64, headroom float64) resource.Quantity {
+	milliCores := math.Ceil(usage*(1+headroom)*100) * 10
+	if milliCores < 10 {
+		milliCores = 10
+	}
+	return *resource.NewMilliQuantity(int64(milliCores), resource.DecimalSI)
+}
+
+// recommendMemory adds headroom to the memory usage (in bytes) and rounds it up to the nearest Mi
+func recommendMemory(usage float64, headroom float64) resource.Quantity {
+	mebibytes := math.Ceil(usage * (1 + headroom) / bytesInMi)
+	if mebibytes < 1 {
+		mebibytes = 1
+	}
+	return *resource.NewQuantity(int64(mebibytes)*bytesInMi, resource.BinarySI)
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/caraml-dev/merlin/config"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/prometheus"
+	"github.com/caraml-dev/merlin/pkg/prometheus/mocks"
+)
+
+func TestResourceRecommendationService_RecommendResourceRequest(t *testing.T) {
+	cfg := config.ResourceRecommendationConfig{
+		ResourceRecommendationEnabled: true,
+		LookbackWindow:                7 * 24 * time.Hour,
+		CPUPercentile:                 0.95,
+		Headroom:                      0.2,
+	}
+
+	env := &models.Environment{
+		Name:    "dev",
+		Cluster: "dev-cluster",
+		UnitCost: &models.UnitCost{
+			CPUMonthlyCost:    10,
+			MemoryMonthlyCost: 1,
+		},
+	}
+
+	currentRequest := &models.ResourceRequest{
+		MinReplica:    1,
+		MaxReplica:    2,
+		CPURequest:    resource.MustParse("2"),
+		MemoryRequest: resource.MustParse("2Gi"),
+	}
+
+	modelCPUQuery := `max(quantile_over_time(0.950000, rate(container_cpu_usage_seconds_total{cluster_name="dev-cluster",namespace="sample",pod=~"model-1-predictor-.*",container="kserve-container"}[5m])[1w:5m]))`
+	modelMemoryQuery := `max(max_over_time(container_memory_working_set_bytes{cluster_name="dev-cluster",namespace="sample",pod=~"model-1-predictor-.*",container="kserve-container"}[1w]))`
+	transformerCPUQuery := `max(quantile_over_time(0.950000, rate(container_cpu_usage_seconds_total{cluster_name="dev-cluster",namespace="sample",pod=~"model-1-transformer-.*",container="transformer"}[5m])[1w:5m]))`
+	transformerMemoryQuery := `max(max_over_time(container_memory_working_set_bytes{cluster_name="dev-cluster",namespace="sample",pod=~"model-1-transformer-.*",container="transformer"}[1w]))`
+
+	tests := []struct {
+		name        string
+		endpoint    *models.VersionEndpoint
+		mockClient  func(client *mocks.Client)
+		want        *models.EndpointResourceRecommendation
+		wantErr     bool
+		expectedErr string
+	}{
+		{
+			name: "model only",
+			endpoint: &models.VersionEndpoint{
+				InferenceServiceName: "model-1",
+				Namespace:            "sample",
+				Environment:          env,
+				ResourceRequest:      currentRequest,
+			},
+			mockClient: func(client *mocks.Client) {
+				client.On("QueryScalar", mock.Anything, modelCPUQuery, mock.Anything).Return(0.4, nil)
+				client.On("QueryScalar", mock.Anything, modelMemoryQuery, mock.Anything).Return(float64(500*1024*1024), nil)
+			},
+			want: &models.EndpointResourceRecommendation{
+				LookbackWindow: "1w",
+				Model: &models.ResourceRecommendation{
+					CurrentResourceRequest: currentRequest,
+					RecommendedResourceRequest: &models.ResourceRequest{
+						MinReplica:    1,
+						MaxReplica:    2,
+						CPURequest:    *resource.NewMilliQuantity(480, resource.DecimalSI),
+						MemoryRequest: *resource.NewQuantity(600*1024*1024, resource.BinarySI),
+					},
+					CurrentCostEstimation:     &models.CostEstimation{MinMonthlyCost: 22, MaxMonthlyCost: 44},
+					RecommendedCostEstimation: &models.CostEstimation{MinMonthlyCost: 5.39, MaxMonthlyCost: 10.77},
+				},
+			},
+		},
+		{
+			name: "model and transformer",
+			endpoint: &models.VersionEndpoint{
+				InferenceServiceName: "model-1",
+				Namespace:            "sample",
+				Environment:          env,
+				ResourceRequest:      currentRequest,
+				Transformer: &models.Transformer{
+					Enabled:         true,
+					ResourceRequest: currentRequest,
+				},
+			},
+			mockClient: func(client *mocks.Client) {
+				client.On("QueryScalar", mock.Anything, modelCPUQuery, mock.Anything).Return(0.4, nil)
+				client.On("QueryScalar", mock.Anything, modelMemoryQuery, mock.Anything).Return(float64(500*1024*1024), nil)
+				client.On("QueryScalar", mock.Anything, transformerCPUQuery, mock.Anything).Return(0.001, nil)
+				client.On("QueryScalar", mock.Anything, transformerMemoryQuery, mock.Anything).Return(float64(100), nil)
+			},
+			want: &models.EndpointResourceRecommendation{
+				LookbackWindow: "1w",
+				Model: &models.ResourceRecommendation{
+					CurrentResourceRequest: currentRequest,
+					RecommendedResourceRequest: &models.ResourceRequest{
+						MinReplica:    1,
+						MaxReplica:    2,
+						CPURequest:    *resource.NewMilliQuantity(480, resource.DecimalSI),
+						MemoryRequest: *resource.NewQuantity(600*1024*1024, resource.BinarySI),
+					},
+					CurrentCostEstimation:     &models.CostEstimation{MinMonthlyCost: 22, MaxMonthlyCost: 44},
+					RecommendedCostEstimation: &models.CostEstimation{MinMonthlyCost: 5.39, MaxMonthlyCost: 10.77},
+				},
+				Transformer: &models.ResourceRecommendation{
+					CurrentResourceRequest: currentRequest,
+					RecommendedResourceRequest: &models.ResourceRequest{
+						MinReplica:    1,
+						MaxReplica:    2,
+						CPURequest:    *resource.NewMilliQuantity(10, resource.DecimalSI),
+						MemoryRequest: *resource.NewQuantity(1024*1024, resource.BinarySI),
+					},
+					CurrentCostEstimation:     &models.CostEstimation{MinMonthlyCost: 22, MaxMonthlyCost: 44},
+					RecommendedCostEstimation: &models.CostEstimation{MinMonthlyCost: 0.1, MaxMonthlyCost: 0.2},
+				},
+			},
+		},
+		{
+			name: "no usage data",
+			endpoint: &models.VersionEndpoint{
+				InferenceServiceName: "model-1",
+				Namespace:            "sample",
+				Environment:          env,
+				ResourceRequest:      currentRequest,
+			},
+			mockClient: func(client *mocks.Client) {
+				client.On("QueryScalar", mock.Anything, modelCPUQuery, mock.Anything).Return(float64(0), prometheus.ErrNoData)
+			},
+			wantErr:     true,
+			expectedErr: "unable to compute model resource recommendation: unable to query cpu usage: query returned no data",
+		},
+		{
+			name: "prometheus error",
+			endpoint: &models.VersionEndpoint{
+				InferenceServiceName: "model-1",
+				Namespace:            "sample",
+				Environment:          env,
+				ResourceRequest:      currentRequest,
+			},
+			mockClient: func(client *mocks.Client) {
+				client.On("QueryScalar", mock.Anything, modelCPUQuery, mock.Anything).Return(0.4, nil)
+				client.On("QueryScalar", mock.Anything, modelMemoryQuery, mock.Anything).Return(float64(0), errors.New("connection refused"))
+			},
+			wantErr:     true,
+			expectedErr: "unable to compute model resource recommendation: unable to query memory usage: connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mocks.NewClient(t)
+			tt.mockClient(client)
+
+			svc := NewResourceRecommendationService(client, cfg)
+			got, err := svc.RecommendResourceRequest(context.Background(), tt.endpoint)
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage"
+	"github.com/caraml-dev/merlin/warden"
+)
+
+// TeamService manages the built-in registry of teams and the teams owning projects
+type TeamService interface {
+	// ListTeams return all registered teams ordered by name
+	ListTeams(ctx context.Context) ([]*models.Team, error)
+	// GetTeam return the team with the given ID
+	GetTeam(ctx context.Context, id models.ID) (*models.Team, error)
+	// CreateTeam register a new team
+	CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
+	// UpdateTeam update a registered team, keeping its source
+	UpdateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
+	// DeleteTeam delete a team and the ownership of its projects
+	DeleteTeam(ctx context.Context, team *models.Team) error
+	// GetProjectOwner return the owner of a project, it returns nil if the project doesn't have any owner
+	GetProjectOwner(ctx context.Context, projectID models.ID) (*models.ProjectOwner, error)
+	// SetProjectOwner create or update the owner of a project
+	SetProjectOwner(ctx context.Context, owner *models.ProjectOwner) (*models.ProjectOwner, error)
+	// SyncWardenTeams register the teams of Warden which are not registered yet, it does nothing if Warden is not configured
+	SyncWardenTeams(ctx context.Context) error
+}
+
+type teamService struct {
+	teamStorage  storage.TeamStorage
+	wardenClient warden.Client
+}
+
+// NewTeamService creates a new TeamService. wardenClient is nil if Warden is not configured.
+func NewTeamService(teamStorage storage.TeamStorage, wardenClient warden.Client) TeamService {
+	return &teamService{
+		teamStorage:  teamStorage,
+		wardenClient: wardenClient,
+	}
+}
+
+func (s *teamService) ListTeams(ctx context.Context) ([]*models.Team, error) {
+	return s.teamStorage.List()
+}
+
+func (s *teamService) GetTeam(ctx context.Context, id models.ID) (*models.Team, error) {
+	return s.teamStorage.Get(id)
+}
+
+func (s *teamService) CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
+	if err := team.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+	if err := s.checkNameAvailable(team); err != nil {
+		return nil, err
+	}
+
+	team.ID = 0
+	team.Source = models.TeamSourceMerlin
+	if err := s.teamStorage.Save(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) UpdateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
+	if err := team.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+
+	existing, err := s.teamStorage.Get(team.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(team); err != nil {
+		return nil, err
+	}
+
+	team.Source = existing.Source
+	team.CreatedAt = existing.CreatedAt
+	if err := s.teamStorage.Save(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) DeleteTeam(ctx context.Context, team *models.Team) error {
+	return s.teamStorage.Delete(team)
+}
+
+func (s *teamService) GetProjectOwner(ctx context.Context, projectID models.ID) (*models.ProjectOwner, error) {
+	owner, err := s.teamStorage.GetProjectOwner(projectID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return owner, nil
+}
+
+func (s *teamService) SetProjectOwner(ctx context.Context, owner *models.ProjectOwner) (*models.ProjectOwner, error) {
+	team, err := s.teamStorage.Get(owner.TeamID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, merror.NewInvalidInputErrorf("team %d not found", owner.TeamID)
+		}
+		return nil, err
+	}
+
+	existing, err := s.GetProjectOwner(ctx, owner.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	owner.ID = 0
+	if existing != nil {
+		owner.ID = existing.ID
+		owner.CreatedAt = existing.CreatedAt
+	}
+
+	owner.Team = nil
+	if err := s.teamStorage.SaveProjectOwner(owner); err != nil {
+		return nil, err
+	}
+	owner.Team = team
+	return owner, nil
+}
+
+func (s *teamService) SyncWardenTeams(ctx context.Context) error {
+	if s.wardenClient == nil {
+		return nil
+	}
+
+	wardenTeams, err := s.wardenClient.GetAllTeams()
+	if err != nil {
+		return err
+	}
+
+	teams, err := s.teamStorage.List()
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		registered[team.Name] = true
+	}
+
+	for _, name := range wardenTeams {
+		if registered[name] {
+			continue
+		}
+		team := &models.Team{
+			Name:                 name,
+			Members:              models.TeamMembers{},
+			NotificationChannels: models.NotificationChannels{},
+			Source:               models.TeamSourceWarden,
+		}
+		if err := s.teamStorage.Save(team); err != nil {
+			return err
+		}
+		registered[name] = true
+	}
+	return nil
+}
+
+// checkNameAvailable returns error if another team is registered with the name of the given team
+func (s *teamService) checkNameAvailable(team *models.Team) error {
+	existing, err := s.teamStorage.GetByName(team.Name)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != team.ID {
+		return merror.NewInvalidInputErrorf("team %s already exists", team.Name)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/models"
+	storagemocks "github.com/caraml-dev/merlin/storage/mocks"
+	wardenmocks "github.com/caraml-dev/merlin/warden/mocks"
+)
+
+func TestTeamService_CreateTeam(t *testing.T) {
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("GetByName", "datascience").Return(nil, gorm.ErrRecordNotFound)
+	teamStorage.On("GetByName", "analytics").Return(&models.Team{ID: 1, Name: "analytics"}, nil)
+	teamStorage.On("Save", mock.Anything).Return(nil)
+
+	svc := NewTeamService(teamStorage, nil)
+
+	team, err := svc.CreateTeam(context.Background(), &models.Team{ID: 5, Name: "datascience", Source: models.TeamSourceWarden})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ID(0), team.ID)
+	assert.Equal(t, models.TeamSourceMerlin, team.Source)
+
+	_, err = svc.CreateTeam(context.Background(), &models.Team{Name: "analytics"})
+	assert.EqualError(t, err, "invalid input: team analytics already exists")
+
+	_, err = svc.CreateTeam(context.Background(), &models.Team{})
+	assert.EqualError(t, err, "invalid input: team name is required")
+	teamStorage.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestTeamService_UpdateTeam(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := &models.Team{ID: 1, Name: "analytics", Source: models.TeamSourceWarden}
+	existing.CreatedAt = createdAt
+
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("Get", models.ID(1)).Return(existing, nil)
+	teamStorage.On("GetByName", "analytics").Return(existing, nil)
+	teamStorage.On("GetByName", "datascience").Return(&models.Team{ID: 2, Name: "datascience"}, nil)
+	teamStorage.On("Save", mock.Anything).Return(nil)
+
+	svc := NewTeamService(teamStorage, nil)
+
+	team, err := svc.UpdateTeam(context.Background(), &models.Team{ID: 1, Name: "analytics", Members: models.TeamMembers{"bob@example.com"}})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TeamSourceWarden, team.Source)
+	assert.Equal(t, createdAt, team.CreatedAt)
+
+	_, err = svc.UpdateTeam(context.Background(), &models.Team{ID: 1, Name: "datascience"})
+	assert.EqualError(t, err, "invalid input: team datascience already exists")
+}
+
+func TestTeamService_SetProjectOwner(t *testing.T) {
+	team := &models.Team{ID: 1, Name: "datascience"}
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := &models.ProjectOwner{ID: 3, ProjectID: 1, TeamID: 2}
+	existing.CreatedAt = createdAt
+
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("Get", models.ID(1)).Return(team, nil)
+	teamStorage.On("Get", models.ID(9)).Return(nil, gorm.ErrRecordNotFound)
+	teamStorage.On("GetProjectOwner", models.ID(1)).Return(existing, nil)
+	teamStorage.On("GetProjectOwner", models.ID(2)).Return(nil, gorm.ErrRecordNotFound)
+	teamStorage.On("SaveProjectOwner", mock.Anything).Return(nil)
+
+	svc := NewTeamService(teamStorage, nil)
+
+	owner, err := svc.SetProjectOwner(context.Background(), &models.ProjectOwner{ProjectID: 1, TeamID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ID(3), owner.ID)
+	assert.Equal(t, createdAt, owner.CreatedAt)
+	assert.Equal(t, team, owner.Team)
+
+	owner, err = svc.SetProjectOwner(context.Background(), &models.ProjectOwner{ProjectID: 2, TeamID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ID(0), owner.ID)
+
+	_, err = svc.SetProjectOwner(context.Background(), &models.ProjectOwner{ProjectID: 2, TeamID: 9})
+	assert.EqualError(t, err, "invalid input: team 9 not found")
+
+	owner, err = svc.GetProjectOwner(context.Background(), models.ID(2))
+	assert.NoError(t, err)
+	assert.Nil(t, owner)
+}
+
+func TestTeamService_SyncWardenTeams(t *testing.T) {
+	wardenClient := &wardenmocks.Client{}
+	wardenClient.On("GetAllTeams").Return([]string{"analytics", "datascience"}, nil)
+
+	teamStorage := &storagemocks.TeamStorage{}
+	teamStorage.On("List").Return([]*models.Team{{ID: 1, Name: "analytics", Source: models.TeamSourceMerlin}}, nil)
+	teamStorage.On("Save", mock.Anything).Return(nil)
+
+	err := NewTeamService(teamStorage, wardenClient).SyncWardenTeams(context.Background())
+	assert.NoError(t, err)
+	teamStorage.AssertNumberOfCalls(t, "Save", 1)
+	saved := teamStorage.Calls[1].Arguments[0].(*models.Team)
+	assert.Equal(t, "datascience", saved.Name)
+	assert.Equal(t, models.TeamSourceWarden, saved.Source)
+
+	// Nothing is synced without Warden
+	err = NewTeamService(teamStorage, nil).SyncWardenTeams(context.Background())
+	assert.NoError(t, err)
+	teamStorage.AssertNumberOfCalls(t, "Save", 1)
+}
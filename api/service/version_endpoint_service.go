@@ -17,6 +17,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/caraml-dev/merlin/cluster"
@@ -25,6 +26,7 @@ import (
 	"github.com/caraml-dev/merlin/models"
 	"github.com/caraml-dev/merlin/pkg/autoscaling"
 	"github.com/caraml-dev/merlin/pkg/deployment"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
 	"github.com/caraml-dev/merlin/pkg/imagebuilder"
 	"github.com/caraml-dev/merlin/pkg/protocol"
 	"github.com/caraml-dev/merlin/pkg/transformer"
@@ -51,6 +53,17 @@ type EndpointsService interface {
 	CountEndpoints(ctx context.Context, environment *models.Environment, model *models.Model) (int, error)
 	// ListContainers list all container associated with an endpoint
 	ListContainers(ctx context.Context, model *models.Model, version *models.Version, endpointUuid uuid.UUID) ([]*models.Container, error)
+	// ListDeploymentEvents list the deployment timeline of an endpoint from the latest event, with the cursor of the next page
+	ListDeploymentEvents(ctx context.Context, endpointUuid uuid.UUID, query DeploymentEventQuery) ([]*models.DeploymentEvent, string, error)
+}
+
+// defaultDeploymentEventLimit is the page size of the deployment timeline if the query doesn't set one
+const defaultDeploymentEventLimit = 100
+
+type DeploymentEventQuery struct {
+	PaginationQuery
+	// Type only lists events of the given type, e.g. warning to only list failures
+	Type models.DeploymentEventType `schema:"type"`
 }
 
 type EndpointServiceParams struct {
@@ -58,12 +71,14 @@ type EndpointServiceParams struct {
 	ImageBuilder              imagebuilder.ImageBuilder
 	Storage                   storage.VersionEndpointStorage
 	DeploymentStorage         storage.DeploymentStorage
+	DeploymentEventStorage    storage.DeploymentEventStorage
 	Environment               string
 	MonitoringConfig          config.MonitoringConfig
 	LoggerDestinationURL      string
 	JobProducer               queue.Producer
 	FeastCoreClient           core.CoreServiceClient
 	StandardTransformerConfig config.StandardTransformerConfig
+	ProjectQuotaService       ProjectQuotaService
 }
 
 type endpointService struct {
@@ -71,12 +86,14 @@ type endpointService struct {
 	imageBuilder              imagebuilder.ImageBuilder
 	storage                   storage.VersionEndpointStorage
 	deploymentStorage         storage.DeploymentStorage
+	deploymentEventStorage    storage.DeploymentEventStorage
 	environment               string
 	monitoringConfig          config.MonitoringConfig
 	loggerDestinationURL      string
 	jobProducer               queue.Producer
 	feastCoreClient           core.CoreServiceClient
 	standardTransformerConfig config.StandardTransformerConfig
+	projectQuotaService       ProjectQuotaService
 }
 
 func NewEndpointService(params EndpointServiceParams) EndpointsService {
@@ -85,12 +102,14 @@ func NewEndpointService(params EndpointServiceParams) EndpointsService {
 		imageBuilder:              params.ImageBuilder,
 		storage:                   params.Storage,
 		deploymentStorage:         params.DeploymentStorage,
+		deploymentEventStorage:    params.DeploymentEventStorage,
 		environment:               params.Environment,
 		monitoringConfig:          params.MonitoringConfig,
 		loggerDestinationURL:      params.LoggerDestinationURL,
 		jobProducer:               params.JobProducer,
 		feastCoreClient:           params.FeastCoreClient,
 		standardTransformerConfig: params.StandardTransformerConfig,
+		projectQuotaService:       params.ProjectQuotaService,
 	}
 }
 
@@ -109,7 +128,7 @@ func (k *endpointService) FindByID(ctx context.Context, endpointUuid uuid.UUID)
 
 func (k *endpointService) DeployEndpoint(ctx context.Context, environment *models.Environment, model *models.Model, version *models.Version, newEndpoint *models.VersionEndpoint) (*models.VersionEndpoint, error) {
 	// get existing endpoint or create a new one with default config
-	endpoint, _ := version.GetEndpointByEnvironmentName(environment.Name)
+	endpoint, redeploy := version.GetEndpointByEnvironmentName(environment.Name)
 	if endpoint == nil {
 		// create endpoint with default configurations
 		endpoint = models.NewVersionEndpoint(environment, model.Project, model, version, k.monitoringConfig, newEndpoint.DeploymentMode)
@@ -124,7 +143,18 @@ func (k *endpointService) DeployEndpoint(ctx context.Context, environment *model
 	// Copy to avoid race condition
 	tobeDeployedEndpoint := *endpoint
 	endpoint.Status = models.EndpointPending
-	err = k.storage.Save(endpoint)
+	saveEndpoint := func() error {
+		return k.storage.Save(endpoint)
+	}
+	if k.projectQuotaService != nil {
+		err = k.projectQuotaService.CheckEndpointQuota(ctx, model.ProjectID, endpoint, saveEndpoint)
+		// only an endpoint being redeployed exists in the database to attach the rejection to
+		if redeploy && errors.Is(err, merror.InvalidInputError) {
+			k.recordEvent(endpoint.ID, models.NewDeploymentFailureEvent(models.DeploymentEventSourceAPI, "QuotaExceeded", err.Error()))
+		}
+	} else {
+		err = saveEndpoint()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +181,18 @@ func (k *endpointService) DeployEndpoint(ctx context.Context, environment *model
 	return endpoint, nil
 }
 
+// recordEvent saves the event to the deployment timeline of the endpoint, if the event storage is configured
+func (k *endpointService) recordEvent(endpointID uuid.UUID, event *models.DeploymentEvent) {
+	if k.deploymentEventStorage == nil {
+		return
+	}
+
+	event.VersionEndpointID = endpointID
+	if err := k.deploymentEventStorage.Save([]*models.DeploymentEvent{event}); err != nil {
+		log.Warnf("unable to save deployment event of endpoint %s: %v", endpointID, err)
+	}
+}
+
 // override left version endpoint with values on the right version endpoint
 func (k *endpointService) override(left *models.VersionEndpoint, right *models.VersionEndpoint, environment *models.Environment) error {
 	// override deployment mode
@@ -305,6 +347,18 @@ func (k *endpointService) ListContainers(ctx context.Context, model *models.Mode
 	return containers, nil
 }
 
+func (k *endpointService) ListDeploymentEvents(ctx context.Context, id uuid.UUID, query DeploymentEventQuery) ([]*models.DeploymentEvent, string, error) {
+	if query.Type != "" && query.Type != models.DeploymentEventTypeNormal && query.Type != models.DeploymentEventTypeWarning {
+		return nil, "", merror.NewInvalidInputErrorf("unknown deployment event type: %s", query.Type)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultDeploymentEventLimit
+	}
+	return k.deploymentEventStorage.List(id, query.Type, limit, query.Cursor)
+}
+
 func (k *endpointService) reconfigureStandardTransformer(standardTransformer *models.Transformer, predictionLogger *models.PredictionLoggerConfig) (*models.Transformer, error) {
 	envVars := standardTransformer.EnvVars
 	envVarsMap := envVars.ToMap()
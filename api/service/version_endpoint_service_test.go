@@ -24,6 +24,7 @@ import (
 
 	"github.com/caraml-dev/merlin/pkg/autoscaling"
 	"github.com/caraml-dev/merlin/pkg/deployment"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
 	"github.com/caraml-dev/merlin/pkg/protocol"
 	"github.com/feast-dev/feast/sdk/go/protos/feast/core"
 	"github.com/feast-dev/feast/sdk/go/protos/feast/types"
@@ -855,6 +856,104 @@ func TestDeployEndpoint(t *testing.T) {
 	}
 }
 
+func TestDeployEndpoint_ProjectQuota(t *testing.T) {
+	env := &models.Environment{
+		Name:    "env1",
+		Cluster: "cluster1",
+		DefaultResourceRequest: &models.ResourceRequest{
+			MinReplica:    1,
+			MaxReplica:    2,
+			CPURequest:    resource.MustParse("1"),
+			MemoryRequest: resource.MustParse("1Gi"),
+		},
+	}
+	model := &models.Model{ID: 1, Name: "model", ProjectID: 1, Project: mlp.Project{ID: 1, Name: "project"}}
+	maxReplicas := 4
+	existingEndpoint := &models.VersionEndpoint{
+		ID:              uuid.New(),
+		ResourceRequest: env.DefaultResourceRequest,
+	}
+
+	redeployedEndpoint := &models.VersionEndpoint{
+		ID:              uuid.New(),
+		EnvironmentName: env.Name,
+		ResourceRequest: env.DefaultResourceRequest,
+	}
+
+	tests := []struct {
+		name          string
+		quota         *models.ProjectQuota
+		endpoints     []*models.VersionEndpoint
+		expectedError string
+		expectedEvent bool
+	}{
+		{
+			name:  "within quota",
+			quota: &models.ProjectQuota{ProjectID: 1, MaxCPU: "4", MaxReplicas: &maxReplicas},
+		},
+		{
+			name:          "cpu quota exceeded",
+			quota:         &models.ProjectQuota{ProjectID: 1, MaxCPU: "3"},
+			expectedError: "invalid input: total cpu request 4 exceeds project quota 3",
+		},
+		{
+			name:          "cpu quota exceeded on redeploy",
+			quota:         &models.ProjectQuota{ProjectID: 1, MaxCPU: "3"},
+			endpoints:     []*models.VersionEndpoint{redeployedEndpoint},
+			expectedError: "invalid input: total cpu request 4 exceeds project quota 3",
+			expectedEvent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := &models.Version{ID: 1, Endpoints: tt.endpoints}
+			mockQueueProducer := &queueMock.Producer{}
+			mockStorage := &mocks.VersionEndpointStorage{}
+			mockEventStorage := &mocks.DeploymentEventStorage{}
+			mockEventStorage.On("Save", mock.Anything).Return(nil)
+			mockQuotaStorage := &mocks.ProjectQuotaStorage{}
+			mockQuotaStorage.On("Lock", models.ID(1), mock.Anything).Return(func(_ models.ID, fn func(*models.ProjectQuota) error) error {
+				return fn(tt.quota)
+			})
+			mockStorage.On("ListActiveEndpointsInProject", models.ID(1)).Return([]*models.VersionEndpoint{existingEndpoint}, nil)
+			if tt.expectedError == "" {
+				mockStorage.On("Save", mock.Anything).Return(nil)
+				mockQueueProducer.On("EnqueueJob", mock.Anything).Return(nil)
+			}
+
+			endpointSvc := NewEndpointService(EndpointServiceParams{
+				ClusterControllers:     map[string]cluster.Controller{env.Name: &clusterMock.Controller{}},
+				Storage:                mockStorage,
+				DeploymentEventStorage: mockEventStorage,
+				Environment:            "dev",
+				JobProducer:            mockQueueProducer,
+				ProjectQuotaService:    NewProjectQuotaService(mockQuotaStorage, mockStorage, &mocks.PredictionJobStorage{}),
+			})
+			_, err := endpointSvc.DeployEndpoint(context.Background(), env, model, version, &models.VersionEndpoint{})
+			if tt.expectedEvent {
+				mockEventStorage.AssertCalled(t, "Save", mock.MatchedBy(func(events []*models.DeploymentEvent) bool {
+					return len(events) == 1 &&
+						events[0].VersionEndpointID == redeployedEndpoint.ID &&
+						events[0].Source == models.DeploymentEventSourceAPI &&
+						events[0].Reason == "QuotaExceeded" &&
+						events[0].FailureClass == models.FailureClassQuota
+				}))
+			} else {
+				mockEventStorage.AssertNotCalled(t, "Save", mock.Anything)
+			}
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.True(t, errors.Is(err, merror.InvalidInputError))
+				mockStorage.AssertNotCalled(t, "Save", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			mockStorage.AssertNumberOfCalls(t, "Save", 1)
+		})
+	}
+}
+
 func TestDeployEndpoint_StandardTransformer(t *testing.T) {
 	env := &models.Environment{
 		Name:       "env1",
@@ -2302,6 +2401,55 @@ func TestListContainers(t *testing.T) {
 	}
 }
 
+func TestListDeploymentEvents(t *testing.T) {
+	endpointID := uuid.New()
+	events := []*models.DeploymentEvent{
+		models.NewDeploymentFailureEvent(models.DeploymentEventSourceKubernetes, "OOMKilled", "container kserve-container terminated with exit code 137"),
+	}
+
+	testCases := []struct {
+		desc          string
+		query         DeploymentEventQuery
+		expectedLimit int
+		errorMsg      string
+	}{
+		{
+			desc:          "Should use the default limit",
+			query:         DeploymentEventQuery{Type: models.DeploymentEventTypeWarning},
+			expectedLimit: defaultDeploymentEventLimit,
+		},
+		{
+			desc:          "Should use the given limit and cursor",
+			query:         DeploymentEventQuery{PaginationQuery: PaginationQuery{Limit: 10, Cursor: "cursor"}, Type: models.DeploymentEventTypeWarning},
+			expectedLimit: 10,
+		},
+		{
+			desc:     "Should fail for unknown type",
+			query:    DeploymentEventQuery{Type: "error"},
+			errorMsg: "invalid input: unknown deployment event type: error",
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			eventStorage := &mocks.DeploymentEventStorage{}
+			eventStorage.On("List", endpointID, models.DeploymentEventTypeWarning, tC.expectedLimit, tC.query.Cursor).Return(events, "next", nil)
+
+			endpointSvc := NewEndpointService(EndpointServiceParams{DeploymentEventStorage: eventStorage})
+			result, nextCursor, err := endpointSvc.ListDeploymentEvents(context.Background(), endpointID, tC.query)
+			if tC.errorMsg != "" {
+				assert.EqualError(t, err, tC.errorMsg)
+				eventStorage.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, events, result)
+			assert.Equal(t, "next", nextCursor)
+		})
+	}
+}
+
 func assertElementMatchFeatureTableMetadata(t *testing.T, expectation []*spec.FeatureTableMetadata, got []*spec.FeatureTableMetadata) {
 	visited := make(map[int]bool)
 	for _, protoMsg := range got {
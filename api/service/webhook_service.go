@@ -0,0 +1,79 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage"
+)
+
+// DefaultWebhookDeliveriesLimit is the number of latest deliveries returned when no limit is given
+const DefaultWebhookDeliveriesLimit = 50
+
+// WebhookService manages the webhook subscriptions of projects and their delivery logs
+type WebhookService interface {
+	// ListWebhooks list all webhooks of a project
+	ListWebhooks(ctx context.Context, projectID models.ID) ([]*models.Webhook, error)
+	// FindByID find webhook of a project given its ID
+	FindByID(ctx context.Context, projectID models.ID, id models.ID) (*models.Webhook, error)
+	// SaveWebhook create or update a webhook
+	SaveWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	// DeleteWebhook delete a webhook and its delivery logs
+	DeleteWebhook(ctx context.Context, webhook *models.Webhook) error
+	// ListDeliveries list the latest deliveries of a webhook, most recent first
+	ListDeliveries(ctx context.Context, webhook *models.Webhook, limit int) ([]*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	storage storage.WebhookStorage
+}
+
+// NewWebhookService creates a new WebhookService
+func NewWebhookService(storage storage.WebhookStorage) WebhookService {
+	return &webhookService{storage: storage}
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context, projectID models.ID) ([]*models.Webhook, error) {
+	return s.storage.List(projectID)
+}
+
+func (s *webhookService) FindByID(ctx context.Context, projectID models.ID, id models.ID) (*models.Webhook, error) {
+	return s.storage.Get(projectID, id)
+}
+
+func (s *webhookService) SaveWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return nil, merror.NewInvalidInputError(err.Error())
+	}
+
+	if err := s.storage.Save(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return s.storage.Delete(webhook)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, webhook *models.Webhook, limit int) ([]*models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = DefaultWebhookDeliveriesLimit
+	}
+	return s.storage.ListDeliveries(webhook.ID, limit)
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/caraml-dev/merlin/models"
+	merror "github.com/caraml-dev/merlin/pkg/errors"
+	"github.com/caraml-dev/merlin/storage/mocks"
+)
+
+func TestWebhookService_SaveWebhook(t *testing.T) {
+	webhookStorage := &mocks.WebhookStorage{}
+	webhookStorage.On("Save", mock.Anything).Return(nil)
+
+	svc := NewWebhookService(webhookStorage)
+
+	_, err := svc.SaveWebhook(context.Background(), &models.Webhook{ProjectID: 1, URL: "https://hooks.example.com"})
+	assert.True(t, errors.Is(err, merror.InvalidInputError))
+	webhookStorage.AssertNotCalled(t, "Save", mock.Anything)
+
+	webhook := &models.Webhook{ProjectID: 1, Name: "chat-ops", URL: "https://hooks.example.com"}
+	saved, err := svc.SaveWebhook(context.Background(), webhook)
+	assert.NoError(t, err)
+	assert.Equal(t, webhook, saved)
+	webhookStorage.AssertCalled(t, "Save", webhook)
+}
+
+func TestWebhookService_ListDeliveries(t *testing.T) {
+	webhook := &models.Webhook{ID: 1}
+
+	webhookStorage := &mocks.WebhookStorage{}
+	webhookStorage.On("ListDeliveries", models.ID(1), DefaultWebhookDeliveriesLimit).Return([]*models.WebhookDelivery{}, nil)
+	webhookStorage.On("ListDeliveries", models.ID(1), 5).Return([]*models.WebhookDelivery{}, nil)
+
+	svc := NewWebhookService(webhookStorage)
+
+	_, err := svc.ListDeliveries(context.Background(), webhook, 0)
+	assert.NoError(t, err)
+	_, err = svc.ListDeliveries(context.Background(), webhook, 5)
+	assert.NoError(t, err)
+	webhookStorage.AssertExpectations(t)
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	paginator "github.com/pilagod/gorm-cursor-paginator"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type DeploymentEventStorage interface {
+	// Save insert the events of a version endpoint's deployment
+	Save(events []*models.DeploymentEvent) error
+	// List list the events of a version endpoint from the latest, optionally of the given type.
+	// If limit is positive, at most limit events after the cursor are returned along with the cursor of the next page.
+	List(endpointID uuid.UUID, eventType models.DeploymentEventType, limit int, cursor string) ([]*models.DeploymentEvent, string, error)
+}
+
+type deploymentEventStorage struct {
+	db *gorm.DB
+}
+
+func NewDeploymentEventStorage(db *gorm.DB) DeploymentEventStorage {
+	return &deploymentEventStorage{db: db}
+}
+
+// Save insert the events of a version endpoint's deployment
+func (s *deploymentEventStorage) Save(events []*models.DeploymentEvent) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		for _, event := range events {
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List list the events of a version endpoint from the latest, optionally of the given type
+func (s *deploymentEventStorage) List(endpointID uuid.UUID, eventType models.DeploymentEventType, limit int, cursor string) (events []*models.DeploymentEvent, nextCursor string, err error) {
+	query := s.db.Where("version_endpoint_id = ?", endpointID)
+	if eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+
+	if limit <= 0 {
+		err = query.Order("timestamp desc, id desc").Find(&events).Error
+		return
+	}
+
+	paginateEngine := paginator.New()
+	paginateEngine.SetKeys("Timestamp", "ID")
+	paginateEngine.SetLimit(limit)
+	paginateEngine.SetAfterCursor(cursor)
+	paginateEngine.SetOrder(paginator.DESC)
+	if err = paginateEngine.Paginate(query, &events).Error; err != nil {
+		return nil, "", err
+	}
+	if paginateEngine.GetNextCursor().After != nil {
+		nextCursor = *paginateEngine.GetNextCursor().After
+	}
+	return
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+	"github.com/caraml-dev/merlin/pkg/deployment"
+)
+
+func TestDeploymentEventStorage(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		eventStorage := NewDeploymentEventStorage(db)
+		isDefaultTrue := true
+
+		p := mlp.Project{
+			Name:              "project",
+			MLFlowTrackingURL: "http://mlflow:5000",
+		}
+		db.Create(&p)
+
+		m := models.Model{
+			ID:           1,
+			ProjectID:    models.ID(p.ID),
+			ExperimentID: 1,
+			Name:         "model",
+			Type:         models.ModelTypePyFunc,
+		}
+		db.Create(&m)
+
+		v := models.Version{
+			ModelID:       m.ID,
+			RunID:         "1",
+			ArtifactURI:   "gcs:/mlp/1/1",
+			PythonVersion: "3.7.*",
+		}
+		db.Create(&v)
+
+		env := models.Environment{
+			Name:      "env1",
+			Cluster:   "k8s",
+			IsDefault: &isDefaultTrue,
+		}
+		db.Create(&env)
+
+		endpoint := models.VersionEndpoint{
+			ID:              uuid.New(),
+			VersionID:       v.ID,
+			VersionModelID:  m.ID,
+			Status:          models.EndpointFailed,
+			EnvironmentName: env.Name,
+			DeploymentMode:  deployment.ServerlessDeploymentMode,
+		}
+		db.Create(&endpoint)
+
+		start := time.Now().Add(-time.Hour)
+		var events []*models.DeploymentEvent
+		for i, reason := range []string{"DeploymentStarted", "ImageBuildStarted", "ImageBuildSucceeded", "BackOff", "DeploymentFailed"} {
+			event := models.NewDeploymentEvent(models.DeploymentEventSourceWorker, reason, "")
+			if reason == "BackOff" || reason == "DeploymentFailed" {
+				event = models.NewDeploymentFailureEvent(models.DeploymentEventSourceWorker, reason, "back-off restarting failed container")
+			}
+			event.VersionEndpointID = endpoint.ID
+			event.Timestamp = start.Add(time.Duration(i) * time.Minute)
+			events = append(events, event)
+		}
+		err := eventStorage.Save(events)
+		assert.NoError(t, err)
+
+		all, nextCursor, err := eventStorage.List(endpoint.ID, "", 0, "")
+		assert.NoError(t, err)
+		assert.Empty(t, nextCursor)
+		assert.Len(t, all, 5)
+		assert.Equal(t, "DeploymentFailed", all[0].Reason)
+		assert.Equal(t, "DeploymentStarted", all[4].Reason)
+
+		warnings, _, err := eventStorage.List(endpoint.ID, models.DeploymentEventTypeWarning, 0, "")
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 2)
+		assert.Equal(t, models.FailureClassCrashLoop, warnings[0].FailureClass)
+
+		firstPage, nextCursor, err := eventStorage.List(endpoint.ID, "", 3, "")
+		assert.NoError(t, err)
+		assert.Len(t, firstPage, 3)
+		assert.NotEmpty(t, nextCursor)
+		assert.Equal(t, "ImageBuildSucceeded", firstPage[2].Reason)
+
+		secondPage, nextCursor, err := eventStorage.List(endpoint.ID, "", 3, nextCursor)
+		assert.NoError(t, err)
+		assert.Len(t, secondPage, 2)
+		assert.Empty(t, nextCursor)
+		assert.Equal(t, "ImageBuildStarted", secondPage[0].Reason)
+		assert.Equal(t, "DeploymentStarted", secondPage[1].Reason)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type DriftStorage interface {
+	// SaveReference insert the drift reference of a model version, or replace it if it has already been registered
+	SaveReference(reference *models.DriftReference) (*models.DriftReference, error)
+	// GetReference get the drift reference of a model version
+	GetReference(modelID models.ID, versionID models.ID) (*models.DriftReference, error)
+	// ListReferences list the drift references of all model versions
+	ListReferences() ([]*models.DriftReference, error)
+	// AddCounts add the counts of the logged values to the counts of their bin
+	AddCounts(counts []*models.DriftBinCount) error
+	// SumCounts sum the counts of each bin of the features of a drift reference since the given time
+	SumCounts(referenceID models.ID, since time.Time) ([]*models.DriftBinCount, error)
+	// SaveResults insert the drift results
+	SaveResults(results []*models.DriftResult) error
+	// ListResults list the drift results of a drift reference whose window ended since the given time, or the latest ones if it's zero
+	ListResults(referenceID models.ID, since time.Time) ([]*models.DriftResult, error)
+	// DeleteBefore delete the counts and the results older than the given time
+	DeleteBefore(before time.Time) error
+}
+
+type driftStorage struct {
+	db *gorm.DB
+}
+
+func NewDriftStorage(db *gorm.DB) DriftStorage {
+	return &driftStorage{db: db}
+}
+
+// SaveReference insert the drift reference of a model version, or replace it if it has already been registered
+func (s *driftStorage) SaveReference(reference *models.DriftReference) (*models.DriftReference, error) {
+	var existing models.DriftReference
+	err := s.db.Where("model_id = ? AND version_id = ?", reference.ModelID, reference.VersionID).First(&existing).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+	if err == nil {
+		reference.ID = existing.ID
+		reference.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.db.Save(reference).Error; err != nil {
+		return nil, err
+	}
+	return reference, nil
+}
+
+// GetReference get the drift reference of a model version
+func (s *driftStorage) GetReference(modelID models.ID, versionID models.ID) (*models.DriftReference, error) {
+	var reference models.DriftReference
+	if err := s.db.Where("model_id = ? AND version_id = ?", modelID, versionID).First(&reference).Error; err != nil {
+		return nil, err
+	}
+	return &reference, nil
+}
+
+// ListReferences list the drift references of all model versions
+func (s *driftStorage) ListReferences() ([]*models.DriftReference, error) {
+	var references []*models.DriftReference
+	err := s.db.Order("id").Find(&references).Error
+	return references, err
+}
+
+// AddCounts add the counts of the logged values to the counts of their bin
+func (s *driftStorage) AddCounts(counts []*models.DriftBinCount) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		for _, count := range counts {
+			err := tx.Exec(`INSERT INTO drift_bin_counts (reference_id, source, feature, bin, bucket, count)
+				VALUES (?, ?, ?, ?, ?, ?)
+				ON CONFLICT (reference_id, source, feature, bin, bucket) DO UPDATE SET count = drift_bin_counts.count + EXCLUDED.count`,
+				count.ReferenceID, count.Source, count.Feature, count.Bin, count.Bucket, count.Count).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SumCounts sum the counts of each bin of the features of a drift reference since the given time
+func (s *driftStorage) SumCounts(referenceID models.ID, since time.Time) ([]*models.DriftBinCount, error) {
+	var counts []*models.DriftBinCount
+	err := s.db.Table("drift_bin_counts").
+		Select("reference_id, source, feature, bin, SUM(count) AS count").
+		Where("reference_id = ? AND bucket >= ?", referenceID, since).
+		Group("reference_id, source, feature, bin").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// SaveResults insert the drift results
+func (s *driftStorage) SaveResults(results []*models.DriftResult) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		for _, result := range results {
+			if err := tx.Create(result).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListResults list the drift results of a drift reference whose window ended since the given time, or the latest ones if it's zero
+func (s *driftStorage) ListResults(referenceID models.ID, since time.Time) ([]*models.DriftResult, error) {
+	query := s.db.Where("reference_id = ?", referenceID)
+	if since.IsZero() {
+		query = query.Where("window_end = (SELECT MAX(window_end) FROM drift_results WHERE reference_id = ?)", referenceID)
+	} else {
+		query = query.Where("window_end >= ?", since)
+	}
+
+	var results []*models.DriftResult
+	err := query.Order("window_end, source, feature").Find(&results).Error
+	return results, err
+}
+
+// DeleteBefore delete the counts and the results older than the given time
+func (s *driftStorage) DeleteBefore(before time.Time) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Where("bucket < ?", before).Delete(&models.DriftBinCount{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("window_end < ?", before).Delete(&models.DriftResult{}).Error
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestDriftStorage(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		driftStorage := NewDriftStorage(db)
+
+		p := mlp.Project{
+			Name:              "project",
+			MLFlowTrackingURL: "http://mlflow:5000",
+		}
+		db.Create(&p)
+
+		m := models.Model{
+			ID:           1,
+			ProjectID:    models.ID(p.ID),
+			ExperimentID: 1,
+			Name:         "model",
+			Type:         models.ModelTypeSkLearn,
+		}
+		db.Create(&m)
+
+		v := models.Version{
+			ModelID:       m.ID,
+			RunID:         "1",
+			ArtifactURI:   "gcs:/mlp/1/1",
+			PythonVersion: "3.7.*",
+		}
+		db.Create(&v)
+
+		_, err := driftStorage.GetReference(m.ID, v.ID)
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+
+		age := &models.DriftFeature{Name: "age", Source: models.DriftFeatureSourceFeature, Kind: models.DriftFeatureNumeric, Quantiles: []float64{0, 10, 20}}
+		reference, err := driftStorage.SaveReference(&models.DriftReference{
+			ModelID:     m.ID,
+			VersionID:   v.ID,
+			ProjectName: "project",
+			ModelName:   "model",
+			Features:    models.DriftFeatures{age},
+		})
+		require.NoError(t, err)
+
+		// registering the reference again replaces it
+		replaced, err := driftStorage.SaveReference(&models.DriftReference{
+			ModelID:     m.ID,
+			VersionID:   v.ID,
+			ProjectName: "project",
+			ModelName:   "model",
+			Dataset:     "gs://bucket/training.csv",
+			Features:    models.DriftFeatures{age},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, reference.ID, replaced.ID)
+
+		got, err := driftStorage.GetReference(m.ID, v.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "gs://bucket/training.csv", got.Dataset)
+		assert.Equal(t, models.DriftFeatures{age}, got.Features)
+
+		references, err := driftStorage.ListReferences()
+		require.NoError(t, err)
+		assert.Len(t, references, 1)
+
+		now := time.Now().UTC().Truncate(time.Hour)
+		newCount := func(bin string, bucket time.Time, count int64) *models.DriftBinCount {
+			return &models.DriftBinCount{ReferenceID: reference.ID, Source: models.DriftFeatureSourceFeature, Feature: "age", Bin: bin, Bucket: bucket, Count: count}
+		}
+		require.NoError(t, driftStorage.AddCounts([]*models.DriftBinCount{newCount("0", now, 2), newCount("1", now, 3), newCount("0", now.Add(-48*time.Hour), 10)}))
+		require.NoError(t, driftStorage.AddCounts([]*models.DriftBinCount{newCount("0", now, 1), newCount("0", now.Add(-time.Hour), 4)}))
+
+		counts, err := driftStorage.SumCounts(reference.ID, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		sums := map[string]int64{}
+		for _, count := range counts {
+			sums[count.Bin] = count.Count
+		}
+		assert.Equal(t, map[string]int64{"0": 7, "1": 3}, sums)
+
+		psi := 0.1
+		newResult := func(windowEnd time.Time) *models.DriftResult {
+			return &models.DriftResult{ReferenceID: reference.ID, Feature: "age", Source: models.DriftFeatureSourceFeature, Observations: 10, PSI: &psi, WindowStart: windowEnd.Add(-24 * time.Hour), WindowEnd: windowEnd}
+		}
+		require.NoError(t, driftStorage.SaveResults([]*models.DriftResult{newResult(now.Add(-48 * time.Hour)), newResult(now.Add(-time.Hour)), newResult(now)}))
+
+		latest, err := driftStorage.ListResults(reference.ID, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, latest, 1)
+		assert.Equal(t, now, latest[0].WindowEnd.UTC())
+		assert.Equal(t, &psi, latest[0].PSI)
+		assert.Nil(t, latest[0].KS)
+
+		results, err := driftStorage.ListResults(reference.ID, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+
+		require.NoError(t, driftStorage.DeleteBefore(now.Add(-24*time.Hour)))
+		counts, err = driftStorage.SumCounts(reference.ID, time.Time{})
+		require.NoError(t, err)
+		assert.Len(t, counts, 2)
+		results, err = driftStorage.ListResults(reference.ID, now.Add(-72*time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+}
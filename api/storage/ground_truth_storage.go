@@ -0,0 +1,123 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type GroundTruthStorage interface {
+	// SavePredictions insert the logged predictions, or update them if they have already been logged
+	SavePredictions(predictions []*models.LoggedPrediction) error
+	// SaveLabels insert the labels of the predictions, or update them if they have already been submitted
+	SaveLabels(labels []*models.PredictionLabel) error
+	// CountPredictions count the predictions of the model version logged since the given time
+	CountPredictions(modelVersion models.PredictionModelVersion, since time.Time) (int, error)
+	// ListOutcomes list the predictions of the model version logged since the given time, joined with their labels
+	ListOutcomes(modelVersion models.PredictionModelVersion, since time.Time) ([]*models.PredictionOutcome, error)
+	// ListModelVersions list the model versions which logged predictions since the given time
+	ListModelVersions(since time.Time) ([]*models.PredictionModelVersion, error)
+	// DeleteBefore delete the predictions logged and the labels observed before the given time
+	DeleteBefore(before time.Time) error
+}
+
+type groundTruthStorage struct {
+	db *gorm.DB
+}
+
+func NewGroundTruthStorage(db *gorm.DB) GroundTruthStorage {
+	return &groundTruthStorage{db: db}
+}
+
+// SavePredictions insert the logged predictions, or update them if they have already been logged
+func (s *groundTruthStorage) SavePredictions(predictions []*models.LoggedPrediction) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		for _, prediction := range predictions {
+			err := tx.Exec(`INSERT INTO logged_predictions (project_name, model_name, model_version, prediction_id, row_id, value, request_timestamp)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (prediction_id, row_id) DO UPDATE SET project_name = EXCLUDED.project_name, model_name = EXCLUDED.model_name,
+				model_version = EXCLUDED.model_version, value = EXCLUDED.value, request_timestamp = EXCLUDED.request_timestamp, updated_at = current_timestamp`,
+				prediction.ProjectName, prediction.ModelName, prediction.ModelVersion, prediction.PredictionID, prediction.RowID, prediction.Value, prediction.RequestTimestamp).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveLabels insert the labels of the predictions, or update them if they have already been submitted
+func (s *groundTruthStorage) SaveLabels(labels []*models.PredictionLabel) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		for _, label := range labels {
+			err := tx.Exec(`INSERT INTO prediction_labels (prediction_id, row_id, value, observed_at)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT (prediction_id, row_id) DO UPDATE SET value = EXCLUDED.value, observed_at = EXCLUDED.observed_at, updated_at = current_timestamp`,
+				label.PredictionID, label.RowID, label.Value, label.ObservedAt).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountPredictions count the predictions of the model version logged since the given time
+func (s *groundTruthStorage) CountPredictions(modelVersion models.PredictionModelVersion, since time.Time) (int, error) {
+	var count int
+	err := s.predictionsOf(modelVersion, since).Count(&count).Error
+	return count, err
+}
+
+// ListOutcomes list the predictions of the model version logged since the given time, joined with their labels
+func (s *groundTruthStorage) ListOutcomes(modelVersion models.PredictionModelVersion, since time.Time) ([]*models.PredictionOutcome, error) {
+	var outcomes []*models.PredictionOutcome
+	err := s.predictionsOf(modelVersion, since).
+		Select("logged_predictions.value AS prediction, prediction_labels.value AS label").
+		Joins("JOIN prediction_labels ON prediction_labels.prediction_id = logged_predictions.prediction_id AND prediction_labels.row_id = logged_predictions.row_id").
+		Scan(&outcomes).Error
+	return outcomes, err
+}
+
+// ListModelVersions list the model versions which logged predictions since the given time
+func (s *groundTruthStorage) ListModelVersions(since time.Time) ([]*models.PredictionModelVersion, error) {
+	var modelVersions []*models.PredictionModelVersion
+	err := s.db.Table("logged_predictions").
+		Select("DISTINCT project_name, model_name, model_version").
+		Where("request_timestamp >= ?", since).
+		Scan(&modelVersions).Error
+	return modelVersions, err
+}
+
+// DeleteBefore delete the predictions logged and the labels observed before the given time
+func (s *groundTruthStorage) DeleteBefore(before time.Time) error {
+	return inTransaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Where("request_timestamp < ?", before).Delete(&models.LoggedPrediction{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("observed_at < ?", before).Delete(&models.PredictionLabel{}).Error
+	})
+}
+
+func (s *groundTruthStorage) predictionsOf(modelVersion models.PredictionModelVersion, since time.Time) *gorm.DB {
+	return s.db.Table("logged_predictions").
+		Where("logged_predictions.project_name = ? AND logged_predictions.model_name = ? AND logged_predictions.model_version = ?",
+			modelVersion.ProjectName, modelVersion.ModelName, modelVersion.ModelVersion).
+		Where("logged_predictions.request_timestamp >= ?", since)
+}
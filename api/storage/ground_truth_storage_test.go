@@ -0,0 +1,87 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestGroundTruthStorage(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		groundTruthStorage := NewGroundTruthStorage(db)
+
+		now := time.Now().UTC()
+		version1 := models.PredictionModelVersion{ProjectName: "project", ModelName: "model", ModelVersion: "1"}
+		version2 := models.PredictionModelVersion{ProjectName: "project", ModelName: "model", ModelVersion: "2"}
+		newPrediction := func(version models.PredictionModelVersion, predictionID string, value float64, requestTimestamp time.Time) *models.LoggedPrediction {
+			return &models.LoggedPrediction{
+				ProjectName:      version.ProjectName,
+				ModelName:        version.ModelName,
+				ModelVersion:     version.ModelVersion,
+				PredictionID:     predictionID,
+				RowID:            "1",
+				Value:            value,
+				RequestTimestamp: requestTimestamp,
+			}
+		}
+
+		err := groundTruthStorage.SavePredictions([]*models.LoggedPrediction{
+			newPrediction(version1, "a", 0.9, now.Add(-time.Minute)),
+			newPrediction(version1, "b", 0.2, now.Add(-time.Minute)),
+			newPrediction(version1, "old", 0.5, now.Add(-48*time.Hour)),
+			newPrediction(version2, "c", 0.7, now.Add(-time.Minute)),
+		})
+		require.NoError(t, err)
+
+		// a label submitted twice is updated
+		err = groundTruthStorage.SaveLabels([]*models.PredictionLabel{
+			{PredictionID: "a", RowID: "1", Value: 0, ObservedAt: now},
+			{PredictionID: "old", RowID: "1", Value: 1, ObservedAt: now},
+		})
+		require.NoError(t, err)
+		err = groundTruthStorage.SaveLabels([]*models.PredictionLabel{{PredictionID: "a", RowID: "1", Value: 1, ObservedAt: now}})
+		require.NoError(t, err)
+
+		since := now.Add(-time.Hour)
+		count, err := groundTruthStorage.CountPredictions(version1, since)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		outcomes, err := groundTruthStorage.ListOutcomes(version1, since)
+		require.NoError(t, err)
+		assert.Equal(t, []*models.PredictionOutcome{{Prediction: 0.9, Label: 1}}, outcomes)
+
+		modelVersions, err := groundTruthStorage.ListModelVersions(since)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*models.PredictionModelVersion{&version1, &version2}, modelVersions)
+
+		err = groundTruthStorage.DeleteBefore(now.Add(-24 * time.Hour))
+		require.NoError(t, err)
+		count, err = groundTruthStorage.CountPredictions(version1, now.Add(-72*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
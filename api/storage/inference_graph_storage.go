@@ -0,0 +1,62 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type InferenceGraphStorage interface {
+	// List list all inference graphs of the given project
+	List(projectID models.ID) ([]*models.InferenceGraph, error)
+	// Get get inference graph given its ID
+	Get(projectID models.ID, id models.ID) (*models.InferenceGraph, error)
+	// Save save the inference graph to underlying storage
+	Save(graph *models.InferenceGraph) error
+}
+
+type inferenceGraphStorage struct {
+	db *gorm.DB
+}
+
+func NewInferenceGraphStorage(db *gorm.DB) InferenceGraphStorage {
+	return &inferenceGraphStorage{db: db}
+}
+
+// List list all inference graphs of the given project
+func (s *inferenceGraphStorage) List(projectID models.ID) (graphs []*models.InferenceGraph, err error) {
+	err = s.query().Where("project_id = ?", projectID).Order("id").Find(&graphs).Error
+	return
+}
+
+// Get get inference graph given its ID
+func (s *inferenceGraphStorage) Get(projectID models.ID, id models.ID) (*models.InferenceGraph, error) {
+	var graph models.InferenceGraph
+	if err := s.query().Where("project_id = ? AND id = ?", projectID, id).First(&graph).Error; err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
+// Save save the inference graph to underlying storage
+func (s *inferenceGraphStorage) Save(graph *models.InferenceGraph) error {
+	return s.db.Save(graph).Error
+}
+
+func (s *inferenceGraphStorage) query() *gorm.DB {
+	return s.db.Preload("Environment")
+}
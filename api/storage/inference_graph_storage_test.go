@@ -0,0 +1,71 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestInferenceGraphStorage_SaveListAndGet(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		env := models.Environment{Name: "env1", Cluster: "k8s"}
+		db.Create(&env)
+
+		graphStorage := NewInferenceGraphStorage(db)
+
+		endpointID := uuid.New()
+		graph := &models.InferenceGraph{
+			ProjectID: models.ID(1),
+			Name:      "my-graph",
+			Nodes: models.InferenceGraphNodes{
+				models.InferenceGraphRootNode: {
+					RouterType: models.InferenceGraphSequence,
+					Steps:      []*models.InferenceGraphStep{{VersionEndpointID: &endpointID}},
+				},
+			},
+			Status:          models.EndpointPending,
+			EnvironmentName: env.Name,
+		}
+		err := graphStorage.Save(graph)
+		assert.NoError(t, err)
+
+		graphs, err := graphStorage.List(models.ID(1))
+		assert.NoError(t, err)
+		assert.Len(t, graphs, 1)
+
+		graphs, err = graphStorage.List(models.ID(2))
+		assert.NoError(t, err)
+		assert.Len(t, graphs, 0)
+
+		saved, err := graphStorage.Get(models.ID(1), graph.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "my-graph", saved.Name)
+		assert.Equal(t, "env1", saved.Environment.Name)
+		assert.Equal(t, endpointID, *saved.Nodes[models.InferenceGraphRootNode].Steps[0].VersionEndpointID)
+
+		_, err = graphStorage.Get(models.ID(2), graph.ID)
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+	})
+}
@@ -12,6 +12,27 @@ type PredictionJobStorage struct {
 	mock.Mock
 }
 
+// CountActivePredictionJobs provides a mock function with given fields: projectID
+func (_m *PredictionJobStorage) CountActivePredictionJobs(projectID models.ID) (int, error) {
+	ret := _m.Called(projectID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(models.ID) int); ok {
+		r0 = rf(projectID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.ID) error); ok {
+		r1 = rf(projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: ID
 func (_m *PredictionJobStorage) Get(ID models.ID) (*models.PredictionJob, error) {
 	ret := _m.Called(ID)
@@ -81,6 +102,52 @@ func (_m *PredictionJobStorage) List(query *models.PredictionJob) ([]*models.Pre
 	return r0, r1
 }
 
+// ListAttempts provides a mock function with given fields: jobID
+func (_m *PredictionJobStorage) ListAttempts(jobID models.ID) ([]*models.PredictionJobAttempt, error) {
+	ret := _m.Called(jobID)
+
+	var r0 []*models.PredictionJobAttempt
+	if rf, ok := ret.Get(0).(func(models.ID) []*models.PredictionJobAttempt); ok {
+		r0 = rf(jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PredictionJobAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.ID) error); ok {
+		r1 = rf(jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPendingRetries provides a mock function with given fields: environmentName
+func (_m *PredictionJobStorage) ListPendingRetries(environmentName string) ([]*models.PredictionJob, error) {
+	ret := _m.Called(environmentName)
+
+	var r0 []*models.PredictionJob
+	if rf, ok := ret.Get(0).(func(string) []*models.PredictionJob); ok {
+		r0 = rf(environmentName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PredictionJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(environmentName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: predictionJob
 func (_m *PredictionJobStorage) Save(predictionJob *models.PredictionJob) error {
 	ret := _m.Called(predictionJob)
@@ -95,6 +162,34 @@ func (_m *PredictionJobStorage) Save(predictionJob *models.PredictionJob) error
 	return r0
 }
 
+// SaveAttempt provides a mock function with given fields: attempt
+func (_m *PredictionJobStorage) SaveAttempt(attempt *models.PredictionJobAttempt) error {
+	ret := _m.Called(attempt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.PredictionJobAttempt) error); ok {
+		r0 = rf(attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveProgress provides a mock function with given fields: jobID, progress
+func (_m *PredictionJobStorage) SaveProgress(jobID models.ID, progress *models.PredictionJobProgress) error {
+	ret := _m.Called(jobID, progress)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(models.ID, *models.PredictionJobProgress) error); ok {
+		r0 = rf(jobID, progress)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPredictionJobStorage interface {
 	mock.TestingT
 	Cleanup(func())
@@ -58,6 +58,29 @@ func (_m *VersionEndpointStorage) Get(_a0 uuid.UUID) (*models.VersionEndpoint, e
 	return r0, r1
 }
 
+// ListActiveEndpointsInProject provides a mock function with given fields: projectID
+func (_m *VersionEndpointStorage) ListActiveEndpointsInProject(projectID models.ID) ([]*models.VersionEndpoint, error) {
+	ret := _m.Called(projectID)
+
+	var r0 []*models.VersionEndpoint
+	if rf, ok := ret.Get(0).(func(models.ID) []*models.VersionEndpoint); ok {
+		r0 = rf(projectID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.VersionEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.ID) error); ok {
+		r1 = rf(projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListEndpoints provides a mock function with given fields: model, version
 func (_m *VersionEndpointStorage) ListEndpoints(model *models.Model, version *models.Version) ([]*models.VersionEndpoint, error) {
 	ret := _m.Called(model, version)
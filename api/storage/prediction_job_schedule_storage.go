@@ -0,0 +1,144 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type PredictionJobScheduleStorage interface {
+	// List list all schedules matching the given query
+	List(query *models.PredictionJobSchedule) ([]*models.PredictionJobSchedule, error)
+	// Get get schedule with given ID
+	Get(id models.ID) (*models.PredictionJobSchedule, error)
+	// Save save the schedule to underlying storage
+	Save(schedule *models.PredictionJobSchedule) error
+	// Delete delete the schedule and its runs
+	Delete(schedule *models.PredictionJobSchedule) error
+	// ListDue list unpaused schedules whose next run is at or before the given time
+	ListDue(now time.Time) ([]*models.PredictionJobSchedule, error)
+	// Advance moves the next run of the schedule, only if no one else has moved it since the schedule was read.
+	// It returns false if the schedule has been advanced by someone else.
+	Advance(schedule *models.PredictionJobSchedule, nextRunAt time.Time) (bool, error)
+	// AdvanceWatermark moves the last watermark of the schedule forward, it's never moved backward
+	AdvanceWatermark(schedule *models.PredictionJobSchedule, watermark time.Time) error
+	// ListRuns list the latest runs of a schedule, most recent first
+	ListRuns(scheduleID models.ID, limit int) ([]*models.PredictionJobScheduleRun, error)
+	// SaveRun save the schedule run to underlying storage
+	SaveRun(run *models.PredictionJobScheduleRun) error
+	// PruneRuns delete all but the latest runs of a schedule
+	PruneRuns(scheduleID models.ID, keep int) error
+}
+
+type predictionJobScheduleStorage struct {
+	db *gorm.DB
+}
+
+func NewPredictionJobScheduleStorage(db *gorm.DB) PredictionJobScheduleStorage {
+	return &predictionJobScheduleStorage{db: db}
+}
+
+// List list all schedules matching the given query
+func (s *predictionJobScheduleStorage) List(query *models.PredictionJobSchedule) (schedules []*models.PredictionJobSchedule, err error) {
+	err = s.db.Where(query).Order("id").Find(&schedules).Error
+	return
+}
+
+// Get get schedule with given ID
+func (s *predictionJobScheduleStorage) Get(id models.ID) (*models.PredictionJobSchedule, error) {
+	var schedule models.PredictionJobSchedule
+	if err := s.db.Where("id = ?", id).First(&schedule).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Save save the schedule to underlying storage
+func (s *predictionJobScheduleStorage) Save(schedule *models.PredictionJobSchedule) error {
+	return s.db.Save(schedule).Error
+}
+
+// Delete delete the schedule and its runs
+func (s *predictionJobScheduleStorage) Delete(schedule *models.PredictionJobSchedule) error {
+	return s.db.Delete(schedule).Error
+}
+
+// ListDue list unpaused schedules whose next run is at or before the given time
+func (s *predictionJobScheduleStorage) ListDue(now time.Time) (schedules []*models.PredictionJobSchedule, err error) {
+	err = s.db.Where("paused = ? AND next_run_at <= ?", false, now).Order("next_run_at").Find(&schedules).Error
+	return
+}
+
+// Advance moves the next run of the schedule, only if no one else has moved it since the schedule was read.
+// It returns false if the schedule has been advanced by someone else.
+func (s *predictionJobScheduleStorage) Advance(schedule *models.PredictionJobSchedule, nextRunAt time.Time) (bool, error) {
+	result := s.db.Model(&models.PredictionJobSchedule{}).
+		Where("id = ? AND next_run_at = ?", schedule.ID, schedule.NextRunAt).
+		Updates(map[string]interface{}{
+			"last_run_at": schedule.NextRunAt,
+			"next_run_at": nextRunAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	schedule.LastRunAt = schedule.NextRunAt
+	schedule.NextRunAt = &nextRunAt
+	return true, nil
+}
+
+// AdvanceWatermark moves the last watermark of the schedule forward, it's never moved backward
+func (s *predictionJobScheduleStorage) AdvanceWatermark(schedule *models.PredictionJobSchedule, watermark time.Time) error {
+	err := s.db.Model(&models.PredictionJobSchedule{}).
+		Where("id = ? AND (last_watermark IS NULL OR last_watermark < ?)", schedule.ID, watermark).
+		Update("last_watermark", watermark).Error
+	if err != nil {
+		return err
+	}
+
+	if schedule.LastWatermark == nil || schedule.LastWatermark.Before(watermark) {
+		schedule.LastWatermark = &watermark
+	}
+	return nil
+}
+
+// ListRuns list the latest runs of a schedule, most recent first
+func (s *predictionJobScheduleStorage) ListRuns(scheduleID models.ID, limit int) (runs []*models.PredictionJobScheduleRun, err error) {
+	err = s.db.Where("schedule_id = ?", scheduleID).Order("scheduled_time desc, id desc").Limit(limit).Find(&runs).Error
+	return
+}
+
+// SaveRun save the schedule run to underlying storage
+func (s *predictionJobScheduleStorage) SaveRun(run *models.PredictionJobScheduleRun) error {
+	return s.db.Save(run).Error
+}
+
+// PruneRuns delete all but the latest runs of a schedule
+func (s *predictionJobScheduleStorage) PruneRuns(scheduleID models.ID, keep int) error {
+	latest := s.db.Model(&models.PredictionJobScheduleRun{}).
+		Select("id").
+		Where("schedule_id = ?", scheduleID).
+		Order("scheduled_time desc, id desc").
+		Limit(keep).
+		SubQuery()
+	return s.db.Where("schedule_id = ? AND id NOT IN ?", scheduleID, latest).Delete(&models.PredictionJobScheduleRun{}).Error
+}
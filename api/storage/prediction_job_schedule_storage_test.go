@@ -0,0 +1,114 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/caraml-dev/merlin-pyspark-app/pkg/spec"
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/mlp"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestPredictionJobScheduleStorage(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		scheduleStorage := NewPredictionJobScheduleStorage(db)
+
+		env := models.Environment{Name: "env1", Cluster: "k8s", IsPredictionJobEnabled: true}
+		db.Create(&env)
+
+		p := mlp.Project{ID: 1, Name: "project", MLFlowTrackingURL: "http://mlflow:5000"}
+		db.Create(&p)
+
+		m := models.Model{ID: 1, ProjectID: models.ID(p.ID), ExperimentID: 1, Name: "model", Type: models.ModelTypePyFuncV2}
+		db.Create(&m)
+
+		v := models.Version{ModelID: m.ID, RunID: "1", ArtifactURI: "gcs:/mlp/1/1"}
+		db.Create(&v)
+
+		nextRunAt := time.Date(2023, 3, 1, 2, 0, 0, 0, time.UTC)
+		schedule := &models.PredictionJobSchedule{
+			Name:              "nightly",
+			ProjectID:         models.ID(p.ID),
+			ModelID:           m.ID,
+			VersionID:         v.ID,
+			EnvironmentName:   env.Name,
+			Schedule:          "0 2 * * *",
+			ConcurrencyPolicy: models.ConcurrencyPolicyForbid,
+			MaxHistory:        2,
+			Config: &models.Config{
+				JobConfig: &spec.PredictionJob{Version: "v1", Kind: "PredictionJob"},
+			},
+			NextRunAt: &nextRunAt,
+		}
+		require.NoError(t, scheduleStorage.Save(schedule))
+
+		schedules, err := scheduleStorage.List(&models.PredictionJobSchedule{ModelID: m.ID, VersionID: v.ID})
+		assert.NoError(t, err)
+		assert.Len(t, schedules, 1)
+		assert.Equal(t, "v1", schedules[0].Config.JobConfig.Version)
+
+		due, err := scheduleStorage.ListDue(nextRunAt.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.Len(t, due, 0)
+
+		due, err = scheduleStorage.ListDue(nextRunAt)
+		assert.NoError(t, err)
+		assert.Len(t, due, 1)
+
+		// only the first of two concurrent readers advances the schedule
+		stale := *due[0]
+		advanced, err := scheduleStorage.Advance(due[0], nextRunAt.Add(24*time.Hour))
+		assert.NoError(t, err)
+		assert.True(t, advanced)
+		advanced, err = scheduleStorage.Advance(&stale, nextRunAt.Add(24*time.Hour))
+		assert.NoError(t, err)
+		assert.False(t, advanced)
+
+		watermark := nextRunAt.Add(-time.Hour)
+		require.NoError(t, scheduleStorage.AdvanceWatermark(schedule, watermark))
+		require.NoError(t, scheduleStorage.AdvanceWatermark(&stale, watermark.Add(-time.Hour)))
+		saved, err := scheduleStorage.Get(schedule.ID)
+		assert.NoError(t, err)
+		assert.True(t, saved.LastWatermark.Equal(watermark))
+
+		for i := 0; i < 3; i++ {
+			err = scheduleStorage.SaveRun(&models.PredictionJobScheduleRun{
+				ScheduleID:    schedule.ID,
+				ScheduledTime: nextRunAt.Add(time.Duration(i) * time.Hour),
+			})
+			assert.NoError(t, err)
+		}
+		require.NoError(t, scheduleStorage.PruneRuns(schedule.ID, 2))
+
+		runs, err := scheduleStorage.ListRuns(schedule.ID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 2)
+		assert.True(t, runs[0].ScheduledTime.Equal(nextRunAt.Add(2*time.Hour)))
+
+		require.NoError(t, scheduleStorage.Delete(schedule))
+		_, err = scheduleStorage.Get(schedule.ID)
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+	})
+}
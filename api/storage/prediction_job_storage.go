@@ -27,8 +27,17 @@ type PredictionJobStorage interface {
 	// Save save the prediction job to underlying storage
 	Save(predictionJob *models.PredictionJob) error
 	// GetFirstSuccessModelVersionPerModel get first model version resulting in a successful batch prediction job
-	// GetFirstSuccessModelVersionPerModel get first model version resulting in a successful batch prediction job
 	GetFirstSuccessModelVersionPerModel() (map[models.ID]models.ID, error)
+	// CountActivePredictionJobs count pending and running prediction jobs in a project
+	CountActivePredictionJobs(projectID models.ID) (int, error)
+	// SaveProgress update the progress of a prediction job without touching its other fields
+	SaveProgress(jobID models.ID, progress *models.PredictionJobProgress) error
+	// ListAttempts list all attempts of a prediction job ordered by attempt number
+	ListAttempts(jobID models.ID) ([]*models.PredictionJobAttempt, error)
+	// SaveAttempt create or update the attempt of a prediction job
+	SaveAttempt(attempt *models.PredictionJobAttempt) error
+	// ListPendingRetries list the pending prediction jobs of an environment waiting to be retried
+	ListPendingRetries(environmentName string) ([]*models.PredictionJob, error)
 }
 
 type predictionJobStorage struct {
@@ -50,7 +59,7 @@ func (p *predictionJobStorage) Get(id models.ID) (*models.PredictionJob, error)
 
 // List list all prediction job matching the given query
 func (p *predictionJobStorage) List(query *models.PredictionJob) (predictionJobs []*models.PredictionJob, err error) {
-	err = p.query().Select("id, name, version_id, version_model_id, project_id, environment_name, status, error, created_at, updated_at").
+	err = p.query().Select("id, name, version_id, version_model_id, project_id, environment_name, config, status, error, attempt, progress, quality_status, quality, created_at, updated_at").
 		Where(query).Find(&predictionJobs).Error
 	return
 }
@@ -84,6 +93,51 @@ func (p *predictionJobStorage) GetFirstSuccessModelVersionPerModel() (map[models
 	return resultMap, nil
 }
 
+// CountActivePredictionJobs count pending and running prediction jobs in a project
+func (p *predictionJobStorage) CountActivePredictionJobs(projectID models.ID) (int, error) {
+	var count int
+	err := p.db.Model(&models.PredictionJob{}).
+		Where("project_id = ? AND status IN (?)", projectID, []models.State{models.JobPending, models.JobRunning}).
+		Count(&count).Error
+	return count, err
+}
+
+// SaveProgress update the progress of a prediction job without touching its other fields
+func (p *predictionJobStorage) SaveProgress(jobID models.ID, progress *models.PredictionJobProgress) error {
+	return p.db.Model(&models.PredictionJob{}).Where("id = ?", jobID).Update("progress", progress).Error
+}
+
+// ListAttempts list all attempts of a prediction job ordered by attempt number
+func (p *predictionJobStorage) ListAttempts(jobID models.ID) (attempts []*models.PredictionJobAttempt, err error) {
+	err = p.db.Where("job_id = ?", jobID).Order("attempt").Find(&attempts).Error
+	return
+}
+
+// SaveAttempt create or update the attempt of a prediction job
+func (p *predictionJobStorage) SaveAttempt(attempt *models.PredictionJobAttempt) error {
+	if attempt.ID == 0 {
+		var existing models.PredictionJobAttempt
+		err := p.db.Where("job_id = ? AND attempt = ?", attempt.JobID, attempt.Attempt).First(&existing).Error
+		if err == nil {
+			attempt.ID = existing.ID
+			attempt.CreatedAt = existing.CreatedAt
+		} else if !gorm.IsRecordNotFoundError(err) {
+			return err
+		}
+	}
+	return p.db.Save(attempt).Error
+}
+
+// ListPendingRetries list the pending prediction jobs of an environment waiting to be retried
+func (p *predictionJobStorage) ListPendingRetries(environmentName string) (jobs []*models.PredictionJob, err error) {
+	err = p.db.
+		Select("id, name, project_id, environment_name, status, attempt, next_retry_at").
+		Where("environment_name = ? AND status = ? AND next_retry_at IS NOT NULL", environmentName, models.JobPending).
+		Order("next_retry_at").
+		Find(&jobs).Error
+	return
+}
+
 func (p *predictionJobStorage) query() *gorm.DB {
 	return p.db.
 		Preload("Environment")
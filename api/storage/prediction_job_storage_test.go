@@ -330,3 +330,234 @@ func TestPredictionJobStorage_List(t *testing.T) {
 		assert.Len(t, jobs, 2)
 	})
 }
+
+func TestPredictionJobStorage_SaveAttempt(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		predJobStore := NewPredictionJobStorage(db)
+		isDefaultPredictionJob := true
+
+		env1 := models.Environment{
+			Name:                   "env1",
+			Cluster:                "k8s",
+			IsPredictionJobEnabled: true,
+			IsDefaultPredictionJob: &isDefaultPredictionJob,
+		}
+		db.Create(&env1)
+
+		p := mlp.Project{
+			Name:              "project",
+			MLFlowTrackingURL: "http://mlflow:5000",
+		}
+		db.Create(&p)
+
+		m := models.Model{
+			ID:           1,
+			ProjectID:    models.ID(p.ID),
+			ExperimentID: 1,
+			Name:         "model",
+			Type:         models.ModelTypeSkLearn,
+		}
+		db.Create(&m)
+
+		v := models.Version{
+			ModelID:     m.ID,
+			RunID:       "1",
+			ArtifactURI: "gcs:/mlp/1/1",
+		}
+		db.Create(&v)
+
+		job := &models.PredictionJob{
+			ID:              1,
+			Name:            "model-1-123",
+			VersionID:       v.ID,
+			VersionModelID:  m.ID,
+			ProjectID:       models.ID(p.ID),
+			EnvironmentName: env1.Name,
+			Status:          models.JobRunning,
+			Attempt:         2,
+		}
+		assert.NoError(t, predJobStore.Save(job))
+
+		first := &models.PredictionJobAttempt{
+			JobID:                job.ID,
+			Attempt:              1,
+			Status:               models.JobFailed,
+			Error:                "ExecutorLostFailure",
+			FailureReason:        models.FailureReasonPreemption,
+			SparkApplicationName: job.Name,
+			Namespace:            "project",
+		}
+		assert.NoError(t, predJobStore.SaveAttempt(first))
+
+		second := &models.PredictionJobAttempt{
+			JobID:                job.ID,
+			Attempt:              2,
+			Status:               models.JobPending,
+			SparkApplicationName: job.Name + "-2",
+			Namespace:            "project",
+		}
+		assert.NoError(t, predJobStore.SaveAttempt(second))
+
+		// saving the same attempt again updates the existing record
+		update := &models.PredictionJobAttempt{
+			JobID:                job.ID,
+			Attempt:              2,
+			Status:               models.JobRunning,
+			SparkApplicationName: job.Name + "-2",
+			Namespace:            "project",
+			DriverPodName:        job.Name + "-2-driver",
+		}
+		assert.NoError(t, predJobStore.SaveAttempt(update))
+		assert.Equal(t, second.ID, update.ID)
+
+		attempts, err := predJobStore.ListAttempts(job.ID)
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 2)
+		assert.Equal(t, 1, attempts[0].Attempt)
+		assert.Equal(t, models.FailureReasonPreemption, attempts[0].FailureReason)
+		assert.Equal(t, 2, attempts[1].Attempt)
+		assert.Equal(t, models.JobRunning, attempts[1].Status)
+		assert.Equal(t, job.Name+"-2-driver", attempts[1].DriverPodName)
+
+		loaded, err := predJobStore.Get(job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, loaded.Attempt)
+	})
+}
+
+func TestPredictionJobStorage_SaveProgress(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		predJobStore := NewPredictionJobStorage(db)
+		isDefaultPredictionJob := true
+
+		env1 := models.Environment{
+			Name:                   "env1",
+			Cluster:                "k8s",
+			IsPredictionJobEnabled: true,
+			IsDefaultPredictionJob: &isDefaultPredictionJob,
+		}
+		db.Create(&env1)
+
+		p := mlp.Project{
+			Name:              "project",
+			MLFlowTrackingURL: "http://mlflow:5000",
+		}
+		db.Create(&p)
+
+		m := models.Model{
+			ID:           1,
+			ProjectID:    models.ID(p.ID),
+			ExperimentID: 1,
+			Name:         "model",
+			Type:         models.ModelTypeSkLearn,
+		}
+		db.Create(&m)
+
+		v := models.Version{
+			ModelID:     m.ID,
+			RunID:       "1",
+			ArtifactURI: "gcs:/mlp/1/1",
+		}
+		db.Create(&v)
+
+		job := &models.PredictionJob{
+			ID:              1,
+			Name:            "model-1-123",
+			VersionID:       v.ID,
+			VersionModelID:  m.ID,
+			ProjectID:       models.ID(p.ID),
+			EnvironmentName: env1.Name,
+			Status:          models.JobRunning,
+			Attempt:         2,
+		}
+		assert.NoError(t, predJobStore.Save(job))
+
+		progress := &models.PredictionJobProgress{
+			StagesTotal:     3,
+			StagesCompleted: 1,
+			TasksTotal:      400,
+			TasksCompleted:  100,
+			Percentage:      25,
+			RowsRead:        1000,
+			ActiveExecutors: 3,
+		}
+		assert.NoError(t, predJobStore.SaveProgress(job.ID, progress))
+
+		loaded, err := predJobStore.Get(job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, progress, loaded.Progress)
+		assert.Equal(t, models.JobRunning, loaded.Status)
+	})
+}
+
+func TestPredictionJobStorage_ListPendingRetries(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		predJobStore := NewPredictionJobStorage(db)
+		isDefaultPredictionJob := true
+
+		env1 := models.Environment{
+			Name:                   "env1",
+			Cluster:                "k8s",
+			IsPredictionJobEnabled: true,
+			IsDefaultPredictionJob: &isDefaultPredictionJob,
+		}
+		db.Create(&env1)
+
+		env2 := models.Environment{
+			Name:                   "env2",
+			Cluster:                "k8s",
+			IsPredictionJobEnabled: true,
+		}
+		db.Create(&env2)
+
+		p := mlp.Project{
+			Name:              "project",
+			MLFlowTrackingURL: "http://mlflow:5000",
+		}
+		db.Create(&p)
+
+		m := models.Model{
+			ID:           1,
+			ProjectID:    models.ID(p.ID),
+			ExperimentID: 1,
+			Name:         "model",
+			Type:         models.ModelTypeSkLearn,
+		}
+		db.Create(&m)
+
+		v := models.Version{
+			ModelID:     m.ID,
+			RunID:       "1",
+			ArtifactURI: "gcs:/mlp/1/1",
+		}
+		db.Create(&v)
+
+		nextRetryAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+		newJob := func(id models.ID, environmentName string, status models.State, nextRetryAt *time.Time) *models.PredictionJob {
+			job := &models.PredictionJob{
+				ID:              id,
+				Name:            fmt.Sprintf("model-1-%d", id),
+				VersionID:       v.ID,
+				VersionModelID:  m.ID,
+				ProjectID:       models.ID(p.ID),
+				EnvironmentName: environmentName,
+				Status:          status,
+				Attempt:         1,
+				NextRetryAt:     nextRetryAt,
+			}
+			assert.NoError(t, predJobStore.Save(job))
+			return job
+		}
+		pending := newJob(1, env1.Name, models.JobPending, &nextRetryAt)
+		newJob(2, env1.Name, models.JobPending, nil)
+		newJob(3, env1.Name, models.JobTerminated, &nextRetryAt)
+		newJob(4, env2.Name, models.JobPending, &nextRetryAt)
+
+		jobs, err := predJobStore.ListPendingRetries(env1.Name)
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, pending.ID, jobs[0].ID)
+		assert.Equal(t, 1, jobs[0].Attempt)
+		assert.True(t, nextRetryAt.Equal(*jobs[0].NextRetryAt))
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type ProjectQuotaStorage interface {
+	// Get get the quota of the given project
+	Get(projectID models.ID) (*models.ProjectQuota, error)
+	// Save save the project quota to underlying storage
+	Save(quota *models.ProjectQuota) error
+	// Lock lock the quota of the given project while fn is running, fn is called with nil if the project has no quota
+	Lock(projectID models.ID, fn func(quota *models.ProjectQuota) error) error
+}
+
+type projectQuotaStorage struct {
+	db *gorm.DB
+}
+
+func NewProjectQuotaStorage(db *gorm.DB) ProjectQuotaStorage {
+	return &projectQuotaStorage{db: db}
+}
+
+// Get get the quota of the given project
+func (p *projectQuotaStorage) Get(projectID models.ID) (*models.ProjectQuota, error) {
+	var quota models.ProjectQuota
+	if err := p.db.Where("project_id = ?", projectID).First(&quota).Error; err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// Save save the project quota to underlying storage
+func (p *projectQuotaStorage) Save(quota *models.ProjectQuota) error {
+	return p.db.Save(quota).Error
+}
+
+// Lock lock the quota of the given project while fn is running, so that concurrent deployments in the project
+// are checked against the quota one at a time. fn is called with nil if the project has no quota.
+func (p *projectQuotaStorage) Lock(projectID models.ID, fn func(quota *models.ProjectQuota) error) error {
+	return inTransaction(p.db, func(tx *gorm.DB) error {
+		var quota models.ProjectQuota
+		err := tx.Set("gorm:query_option", "FOR UPDATE").Where("project_id = ?", projectID).First(&quota).Error
+		if err != nil {
+			if gorm.IsRecordNotFoundError(err) {
+				return fn(nil)
+			}
+			return err
+		}
+		return fn(&quota)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestProjectQuotaStorage_SaveAndGet(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		quotaStorage := NewProjectQuotaStorage(db)
+
+		_, err := quotaStorage.Get(models.ID(1))
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+
+		maxEndpoints := 5
+		quota := &models.ProjectQuota{
+			ProjectID:    models.ID(1),
+			MaxCPU:       "10",
+			MaxMemory:    "20Gi",
+			MaxEndpoints: &maxEndpoints,
+		}
+		err = quotaStorage.Save(quota)
+		assert.NoError(t, err)
+
+		saved, err := quotaStorage.Get(models.ID(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "10", saved.MaxCPU)
+		assert.Equal(t, "20Gi", saved.MaxMemory)
+		assert.Equal(t, 5, *saved.MaxEndpoints)
+		assert.Nil(t, saved.MaxReplicas)
+
+		saved.MaxCPU = "12"
+		err = quotaStorage.Save(saved)
+		assert.NoError(t, err)
+
+		updated, err := quotaStorage.Get(models.ID(1))
+		assert.NoError(t, err)
+		assert.Equal(t, saved.ID, updated.ID)
+		assert.Equal(t, "12", updated.MaxCPU)
+	})
+}
+
+func TestProjectQuotaStorage_Lock(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		quotaStorage := NewProjectQuotaStorage(db)
+
+		err := quotaStorage.Lock(models.ID(1), func(quota *models.ProjectQuota) error {
+			assert.Nil(t, quota)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		err = quotaStorage.Save(&models.ProjectQuota{ProjectID: models.ID(1), MaxCPU: "10"})
+		assert.NoError(t, err)
+
+		err = quotaStorage.Lock(models.ID(1), func(quota *models.ProjectQuota) error {
+			assert.Equal(t, "10", quota.MaxCPU)
+			return errors.New("quota exceeded")
+		})
+		assert.EqualError(t, err, "quota exceeded")
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type TeamStorage interface {
+	// List list all teams ordered by name
+	List() ([]*models.Team, error)
+	// Get get team given its ID
+	Get(id models.ID) (*models.Team, error)
+	// GetByName get team given its name
+	GetByName(name string) (*models.Team, error)
+	// Save save the team to underlying storage
+	Save(team *models.Team) error
+	// Delete delete the team and the ownership of its projects
+	Delete(team *models.Team) error
+	// GetProjectOwner get the owner of the given project, including its team
+	GetProjectOwner(projectID models.ID) (*models.ProjectOwner, error)
+	// SaveProjectOwner save the project owner to underlying storage
+	SaveProjectOwner(owner *models.ProjectOwner) error
+}
+
+type teamStorage struct {
+	db *gorm.DB
+}
+
+func NewTeamStorage(db *gorm.DB) TeamStorage {
+	return &teamStorage{db: db}
+}
+
+// List list all teams ordered by name
+func (s *teamStorage) List() (teams []*models.Team, err error) {
+	err = s.db.Order("name").Find(&teams).Error
+	return
+}
+
+// Get get team given its ID
+func (s *teamStorage) Get(id models.ID) (*models.Team, error) {
+	var team models.Team
+	if err := s.db.Where("id = ?", id).First(&team).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetByName get team given its name
+func (s *teamStorage) GetByName(name string) (*models.Team, error) {
+	var team models.Team
+	if err := s.db.Where("name = ?", name).First(&team).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// Save save the team to underlying storage
+func (s *teamStorage) Save(team *models.Team) error {
+	return s.db.Save(team).Error
+}
+
+// Delete delete the team and the ownership of its projects
+func (s *teamStorage) Delete(team *models.Team) error {
+	return s.db.Delete(team).Error
+}
+
+// GetProjectOwner get the owner of the given project, including its team
+func (s *teamStorage) GetProjectOwner(projectID models.ID) (*models.ProjectOwner, error) {
+	var owner models.ProjectOwner
+	if err := s.db.Preload("Team").Where("project_id = ?", projectID).First(&owner).Error; err != nil {
+		return nil, err
+	}
+	return &owner, nil
+}
+
+// SaveProjectOwner save the project owner to underlying storage
+func (s *teamStorage) SaveProjectOwner(owner *models.ProjectOwner) error {
+	return s.db.Set("gorm:save_associations", false).Save(owner).Error
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestTeamStorage(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		teamStorage := NewTeamStorage(db)
+
+		team := &models.Team{
+			Name:    "datascience",
+			Members: models.TeamMembers{"alice@example.com"},
+			NotificationChannels: models.NotificationChannels{
+				{Type: models.NotificationChannelTypeSlack, Target: "#ds-alerts"},
+			},
+			Escalation: models.TeamEscalation{Policy: "ds-primary"},
+			Source:     models.TeamSourceMerlin,
+		}
+		err := teamStorage.Save(team)
+		assert.NoError(t, err)
+
+		err = teamStorage.Save(&models.Team{Name: "analytics", Source: models.TeamSourceWarden})
+		assert.NoError(t, err)
+
+		teams, err := teamStorage.List()
+		assert.NoError(t, err)
+		assert.Len(t, teams, 2)
+		assert.Equal(t, "analytics", teams[0].Name)
+		assert.Equal(t, models.NotificationChannels{{Type: models.NotificationChannelTypeSlack, Target: "#ds-alerts"}}, teams[1].NotificationChannels)
+
+		found, err := teamStorage.GetByName("datascience")
+		assert.NoError(t, err)
+		assert.Equal(t, team.ID, found.ID)
+		assert.Equal(t, "ds-primary", found.Escalation.Policy)
+
+		err = teamStorage.SaveProjectOwner(&models.ProjectOwner{ProjectID: models.ID(1), TeamID: team.ID})
+		assert.NoError(t, err)
+
+		owner, err := teamStorage.GetProjectOwner(models.ID(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "datascience", owner.Team.Name)
+
+		err = teamStorage.Delete(team)
+		assert.NoError(t, err)
+
+		_, err = teamStorage.Get(team.ID)
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+
+		_, err = teamStorage.GetProjectOwner(models.ID(1))
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+	})
+}
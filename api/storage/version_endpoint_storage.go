@@ -28,6 +28,8 @@ type VersionEndpointStorage interface {
 	Get(uuid.UUID) (*models.VersionEndpoint, error)
 	Save(endpoint *models.VersionEndpoint) error
 	CountEndpoints(environment *models.Environment, model *models.Model) (int, error)
+	// ListActiveEndpointsInProject list all pending, running, and serving endpoints of all models in a project
+	ListActiveEndpointsInProject(projectID models.ID) ([]*models.VersionEndpoint, error)
 }
 
 type versionEndpointStorage struct {
@@ -73,6 +75,14 @@ func (v *versionEndpointStorage) CountEndpoints(environment *models.Environment,
 	return count, err
 }
 
+func (v *versionEndpointStorage) ListActiveEndpointsInProject(projectID models.ID) (endpoints []*models.VersionEndpoint, err error) {
+	err = v.query().
+		Joins("JOIN models on models.id = version_endpoints.version_model_id").
+		Where("models.project_id = ? AND version_endpoints.status IN ('pending', 'running', 'serving')", projectID).
+		Find(&endpoints).Error
+	return
+}
+
 func (v *versionEndpointStorage) query() *gorm.DB {
 	return v.db.
 		Preload("Environment").
@@ -0,0 +1,80 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/caraml-dev/merlin/models"
+)
+
+type WebhookStorage interface {
+	// List list all webhooks of the given project
+	List(projectID models.ID) ([]*models.Webhook, error)
+	// Get get webhook of a project given its ID
+	Get(projectID models.ID, id models.ID) (*models.Webhook, error)
+	// Save save the webhook to underlying storage
+	Save(webhook *models.Webhook) error
+	// Delete delete the webhook and its delivery logs
+	Delete(webhook *models.Webhook) error
+	// ListDeliveries list the latest deliveries of a webhook, most recent first
+	ListDeliveries(webhookID models.ID, limit int) ([]*models.WebhookDelivery, error)
+	// SaveDelivery save the webhook delivery to underlying storage
+	SaveDelivery(delivery *models.WebhookDelivery) error
+}
+
+type webhookStorage struct {
+	db *gorm.DB
+}
+
+func NewWebhookStorage(db *gorm.DB) WebhookStorage {
+	return &webhookStorage{db: db}
+}
+
+// List list all webhooks of the given project
+func (s *webhookStorage) List(projectID models.ID) (webhooks []*models.Webhook, err error) {
+	err = s.db.Where("project_id = ?", projectID).Order("id").Find(&webhooks).Error
+	return
+}
+
+// Get get webhook of a project given its ID
+func (s *webhookStorage) Get(projectID models.ID, id models.ID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := s.db.Where("project_id = ? AND id = ?", projectID, id).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Save save the webhook to underlying storage
+func (s *webhookStorage) Save(webhook *models.Webhook) error {
+	return s.db.Save(webhook).Error
+}
+
+// Delete delete the webhook and its delivery logs
+func (s *webhookStorage) Delete(webhook *models.Webhook) error {
+	return s.db.Delete(webhook).Error
+}
+
+// ListDeliveries list the latest deliveries of a webhook, most recent first
+func (s *webhookStorage) ListDeliveries(webhookID models.ID, limit int) (deliveries []*models.WebhookDelivery, err error) {
+	err = s.db.Where("webhook_id = ?", webhookID).Order("created_at desc, id desc").Limit(limit).Find(&deliveries).Error
+	return
+}
+
+// SaveDelivery save the webhook delivery to underlying storage
+func (s *webhookStorage) SaveDelivery(delivery *models.WebhookDelivery) error {
+	return s.db.Save(delivery).Error
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Merlin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration_local || integration
+// +build integration_local integration
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/caraml-dev/merlin/it/database"
+	"github.com/caraml-dev/merlin/models"
+)
+
+func TestWebhookStorage(t *testing.T) {
+	database.WithTestDatabase(t, func(t *testing.T, db *gorm.DB) {
+		webhookStorage := NewWebhookStorage(db)
+
+		webhook := &models.Webhook{
+			ProjectID:  models.ID(1),
+			Name:       "chat-ops",
+			URL:        "https://hooks.example.com/merlin",
+			Secret:     "secret",
+			EventTypes: models.WebhookEventTypes{models.WebhookEventPredictionJobStatusChanged},
+		}
+		err := webhookStorage.Save(webhook)
+		assert.NoError(t, err)
+
+		webhooks, err := webhookStorage.List(models.ID(1))
+		assert.NoError(t, err)
+		assert.Len(t, webhooks, 1)
+		assert.Equal(t, models.WebhookEventTypes{models.WebhookEventPredictionJobStatusChanged}, webhooks[0].EventTypes)
+
+		for i := 0; i < 3; i++ {
+			err = webhookStorage.SaveDelivery(&models.WebhookDelivery{
+				WebhookID: webhook.ID,
+				EventID:   "event",
+				EventType: models.WebhookEventPredictionJobStatusChanged,
+				Attempts:  i + 1,
+			})
+			assert.NoError(t, err)
+		}
+
+		deliveries, err := webhookStorage.ListDeliveries(webhook.ID, 2)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 2)
+		assert.Equal(t, 3, deliveries[0].Attempts)
+
+		err = webhookStorage.Delete(webhook)
+		assert.NoError(t, err)
+
+		_, err = webhookStorage.Get(models.ID(1), webhook.ID)
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+
+		deliveries, err = webhookStorage.ListDeliveries(webhook.ID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 0)
+	})
+}
@@ -0,0 +1 @@
+ALTER TABLE environments DROP COLUMN unit_cost;
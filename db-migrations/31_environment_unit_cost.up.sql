@@ -0,0 +1 @@
+ALTER TABLE environments ADD COLUMN unit_cost jsonb;
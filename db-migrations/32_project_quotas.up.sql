@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS project_quotas
+(
+    id                             serial PRIMARY KEY,
+    project_id                     integer      NOT NULL,
+    max_cpu                        varchar(32),
+    max_memory                     varchar(32),
+    max_replicas                   integer,
+    max_endpoints                  integer,
+    max_concurrent_prediction_jobs integer,
+    created_at                     timestamp    NOT NULL default current_timestamp,
+    updated_at                     timestamp    NOT NULL default current_timestamp,
+    UNIQUE (project_id)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS inference_graphs
+(
+    id               serial PRIMARY KEY,
+    project_id       integer         NOT NULL,
+    name             varchar(63)     NOT NULL,
+    nodes            jsonb           NOT NULL,
+    status           endpoint_status NOT NULL default 'pending',
+    url              varchar(128),
+    message          varchar(2048),
+    environment_name varchar(50) REFERENCES environments (name),
+    created_at       timestamp       NOT NULL default current_timestamp,
+    updated_at       timestamp       NOT NULL default current_timestamp,
+    UNIQUE (project_id, name, environment_name)
+);
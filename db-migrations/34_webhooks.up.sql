@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id          serial PRIMARY KEY,
+    project_id  integer      NOT NULL,
+    name        varchar(128) NOT NULL,
+    url         text         NOT NULL,
+    secret      text,
+    event_types jsonb,
+    created_at  timestamp    NOT NULL default current_timestamp,
+    updated_at  timestamp    NOT NULL default current_timestamp,
+    UNIQUE (project_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id          serial PRIMARY KEY,
+    webhook_id  integer      NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id    varchar(64)  NOT NULL,
+    event_type  varchar(128) NOT NULL,
+    payload     text,
+    attempts    integer      NOT NULL default 0,
+    status_code integer,
+    success     boolean      NOT NULL default false,
+    error       text,
+    created_at  timestamp    NOT NULL default current_timestamp,
+    updated_at  timestamp    NOT NULL default current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, created_at);
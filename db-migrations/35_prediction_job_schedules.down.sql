@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prediction_job_schedule_runs;
+DROP TABLE IF EXISTS prediction_job_schedules;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS prediction_job_schedules
+(
+    id                 serial PRIMARY KEY,
+    name               varchar(128) NOT NULL,
+    project_id         integer      NOT NULL,
+    model_id           integer      NOT NULL,
+    version_id         integer      NOT NULL,
+    environment_name   varchar(50)  NOT NULL REFERENCES environments (name),
+    schedule           varchar(128) NOT NULL,
+    timezone           varchar(64)  NOT NULL default '',
+    concurrency_policy varchar(16)  NOT NULL default 'forbid',
+    max_history        integer      NOT NULL default 10,
+    paused             boolean      NOT NULL default false,
+    config             jsonb,
+    last_run_at        timestamp,
+    next_run_at        timestamp,
+    created_at         timestamp    NOT NULL default current_timestamp,
+    updated_at         timestamp    NOT NULL default current_timestamp,
+    FOREIGN KEY (model_id, version_id) REFERENCES versions (model_id, id),
+    UNIQUE (project_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS prediction_job_schedules_next_run_at_idx ON prediction_job_schedules (next_run_at) WHERE paused = false;
+
+CREATE TABLE IF NOT EXISTS prediction_job_schedule_runs
+(
+    id             serial PRIMARY KEY,
+    schedule_id    integer   NOT NULL REFERENCES prediction_job_schedules (id) ON DELETE CASCADE,
+    job_id         integer REFERENCES prediction_jobs (id) ON DELETE SET NULL,
+    scheduled_time timestamp NOT NULL,
+    manual         boolean   NOT NULL default false,
+    message        text,
+    created_at     timestamp NOT NULL default current_timestamp,
+    updated_at     timestamp NOT NULL default current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS prediction_job_schedule_runs_schedule_id_idx ON prediction_job_schedule_runs (schedule_id, scheduled_time);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prediction_job_attempts;
+ALTER TABLE prediction_jobs DROP COLUMN IF EXISTS attempt;
@@ -0,0 +1,19 @@
+ALTER TABLE prediction_jobs ADD COLUMN IF NOT EXISTS attempt integer NOT NULL default 1;
+
+CREATE TABLE IF NOT EXISTS prediction_job_attempts
+(
+    id                     serial PRIMARY KEY,
+    job_id                 integer     NOT NULL REFERENCES prediction_jobs (id) ON DELETE CASCADE,
+    attempt                integer     NOT NULL,
+    status                 varchar(16) NOT NULL,
+    error                  text,
+    failure_reason         varchar(32),
+    spark_application_name varchar(128) NOT NULL,
+    namespace              varchar(128) NOT NULL,
+    driver_pod_name        varchar(256),
+    started_at             timestamp,
+    finished_at            timestamp,
+    created_at             timestamp   NOT NULL default current_timestamp,
+    updated_at             timestamp   NOT NULL default current_timestamp,
+    UNIQUE (job_id, attempt)
+);
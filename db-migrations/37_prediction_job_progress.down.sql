@@ -0,0 +1 @@
+ALTER TABLE prediction_jobs DROP COLUMN IF EXISTS progress;
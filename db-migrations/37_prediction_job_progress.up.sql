@@ -0,0 +1 @@
+ALTER TABLE prediction_jobs ADD COLUMN IF NOT EXISTS progress jsonb;
@@ -0,0 +1 @@
+ALTER TABLE environments DROP COLUMN lightweight_prediction_job_config;
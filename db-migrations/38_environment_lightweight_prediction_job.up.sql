@@ -0,0 +1 @@
+ALTER TABLE environments ADD COLUMN lightweight_prediction_job_config jsonb;
@@ -0,0 +1 @@
+ALTER TABLE prediction_job_schedules DROP COLUMN IF EXISTS last_watermark;
@@ -0,0 +1 @@
+ALTER TABLE prediction_job_schedules ADD COLUMN IF NOT EXISTS last_watermark timestamp;
@@ -0,0 +1,2 @@
+ALTER TABLE prediction_jobs DROP COLUMN IF EXISTS quality;
+ALTER TABLE prediction_jobs DROP COLUMN IF EXISTS quality_status;
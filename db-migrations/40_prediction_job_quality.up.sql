@@ -0,0 +1,2 @@
+ALTER TABLE prediction_jobs ADD COLUMN IF NOT EXISTS quality_status varchar(16);
+ALTER TABLE prediction_jobs ADD COLUMN IF NOT EXISTS quality jsonb;
@@ -0,0 +1,2 @@
+ALTER TABLE versions DROP COLUMN IF EXISTS build_config;
+ALTER TABLE versions DROP COLUMN IF EXISTS build_inputs;
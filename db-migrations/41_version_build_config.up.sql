@@ -0,0 +1,2 @@
+ALTER TABLE versions ADD COLUMN IF NOT EXISTS build_config jsonb;
+ALTER TABLE versions ADD COLUMN IF NOT EXISTS build_inputs jsonb;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prediction_labels;
+DROP TABLE IF EXISTS logged_predictions;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS logged_predictions
+(
+    id                bigserial PRIMARY KEY,
+    project_name      varchar(128) NOT NULL,
+    model_name        varchar(128) NOT NULL,
+    model_version     varchar(64)  NOT NULL,
+    prediction_id     varchar(256) NOT NULL,
+    row_id            varchar(256) NOT NULL default '',
+    value             double precision NOT NULL,
+    request_timestamp timestamp    NOT NULL,
+    created_at        timestamp    NOT NULL default current_timestamp,
+    updated_at        timestamp    NOT NULL default current_timestamp,
+    UNIQUE (prediction_id, row_id)
+);
+
+CREATE INDEX IF NOT EXISTS logged_predictions_model_version_idx ON logged_predictions (project_name, model_name, model_version, request_timestamp);
+
+CREATE TABLE IF NOT EXISTS prediction_labels
+(
+    id            bigserial PRIMARY KEY,
+    prediction_id varchar(256) NOT NULL,
+    row_id        varchar(256) NOT NULL default '',
+    value         double precision NOT NULL,
+    observed_at   timestamp    NOT NULL,
+    created_at    timestamp    NOT NULL default current_timestamp,
+    updated_at    timestamp    NOT NULL default current_timestamp,
+    UNIQUE (prediction_id, row_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS drift_results;
+DROP TABLE IF EXISTS drift_bin_counts;
+DROP TABLE IF EXISTS drift_references;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS drift_references
+(
+    id           serial PRIMARY KEY,
+    model_id     integer      NOT NULL,
+    version_id   integer      NOT NULL,
+    project_name varchar(128) NOT NULL,
+    model_name   varchar(128) NOT NULL,
+    dataset      text,
+    features     jsonb        NOT NULL,
+    created_at   timestamp    NOT NULL default current_timestamp,
+    updated_at   timestamp    NOT NULL default current_timestamp,
+    CONSTRAINT drift_references_model_version_fkey
+        FOREIGN KEY (model_id, version_id) REFERENCES versions (model_id, id),
+    UNIQUE (model_id, version_id)
+);
+
+CREATE TABLE IF NOT EXISTS drift_bin_counts
+(
+    reference_id integer      NOT NULL REFERENCES drift_references (id) ON DELETE CASCADE,
+    source       varchar(32)  NOT NULL,
+    feature      varchar(256) NOT NULL,
+    bin          varchar(256) NOT NULL,
+    bucket       timestamp    NOT NULL,
+    count        bigint       NOT NULL,
+    PRIMARY KEY (reference_id, source, feature, bin, bucket)
+);
+
+CREATE TABLE IF NOT EXISTS drift_results
+(
+    id             bigserial PRIMARY KEY,
+    reference_id   integer      NOT NULL REFERENCES drift_references (id) ON DELETE CASCADE,
+    source         varchar(32)  NOT NULL,
+    feature        varchar(256) NOT NULL,
+    observations   bigint       NOT NULL,
+    psi            double precision,
+    ks             double precision,
+    jensen_shannon double precision,
+    window_start   timestamp    NOT NULL,
+    window_end     timestamp    NOT NULL,
+    created_at     timestamp    NOT NULL default current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS drift_results_reference_idx ON drift_results (reference_id, window_end);
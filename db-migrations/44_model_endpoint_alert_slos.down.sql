@@ -0,0 +1,2 @@
+ALTER TABLE model_endpoint_alerts DROP COLUMN IF EXISTS slos;
+ALTER TABLE model_endpoint_alerts DROP COLUMN IF EXISTS routes;
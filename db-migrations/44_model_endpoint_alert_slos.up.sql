@@ -0,0 +1,2 @@
+ALTER TABLE model_endpoint_alerts ADD COLUMN IF NOT EXISTS slos jsonb NOT NULL DEFAULT '[]';
+ALTER TABLE model_endpoint_alerts ADD COLUMN IF NOT EXISTS routes jsonb NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_owners;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS teams
+(
+    id                    serial PRIMARY KEY,
+    name                  varchar(128) NOT NULL UNIQUE,
+    description           text,
+    members               jsonb        NOT NULL default '[]',
+    notification_channels jsonb        NOT NULL default '[]',
+    escalation            jsonb        NOT NULL default '{}',
+    source                varchar(16)  NOT NULL default 'merlin',
+    created_at            timestamp    NOT NULL default current_timestamp,
+    updated_at            timestamp    NOT NULL default current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS project_owners
+(
+    id         serial PRIMARY KEY,
+    project_id integer   NOT NULL UNIQUE,
+    team_id    integer   NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL default current_timestamp,
+    updated_at timestamp NOT NULL default current_timestamp
+);
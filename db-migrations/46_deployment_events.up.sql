@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS deployment_events
+(
+    id                  serial PRIMARY KEY,
+    version_endpoint_id uuid         NOT NULL REFERENCES version_endpoints (id) ON DELETE CASCADE,
+    source              varchar(32)  NOT NULL,
+    type                varchar(16)  NOT NULL default 'normal',
+    reason              varchar(128) NOT NULL,
+    message             text,
+    object              varchar(256),
+    failure_class       varchar(32),
+    timestamp           timestamp    NOT NULL default current_timestamp,
+    created_at          timestamp    NOT NULL default current_timestamp,
+    updated_at          timestamp    NOT NULL default current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS deployment_events_version_endpoint_id_timestamp_idx ON deployment_events (version_endpoint_id, timestamp, id);
@@ -0,0 +1,2 @@
+ALTER TABLE inference_graphs DROP COLUMN logger;
+ALTER TABLE inference_graphs DROP COLUMN alert;
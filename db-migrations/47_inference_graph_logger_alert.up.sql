@@ -0,0 +1,2 @@
+ALTER TABLE inference_graphs ADD COLUMN logger jsonb;
+ALTER TABLE inference_graphs ADD COLUMN alert jsonb;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS prediction_jobs_next_retry_at_idx;
+
+ALTER TABLE prediction_jobs DROP COLUMN IF EXISTS next_retry_at;
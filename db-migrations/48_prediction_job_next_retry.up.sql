@@ -0,0 +1,3 @@
+ALTER TABLE prediction_jobs ADD COLUMN IF NOT EXISTS next_retry_at timestamp;
+
+CREATE INDEX IF NOT EXISTS prediction_jobs_next_retry_at_idx ON prediction_jobs (environment_name, next_retry_at) WHERE next_retry_at IS NOT NULL;
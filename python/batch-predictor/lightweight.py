@@ -0,0 +1,59 @@
+# Copyright 2020 The Merlin Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import argparse
+
+from merlinpyspark.config import load
+from merlinpyspark.lightweight import create_predictor, run
+
+
+def main(spec_path, concurrency, batch_size, endpoint_url, endpoint_protocol):
+    print(f"loading prediction job spec from: {spec_path}")
+    job_spec = load(spec_path)
+
+    predictor = create_predictor(job_spec.model().model_uri(), endpoint_url,
+                                 endpoint_protocol)
+    rows = run(job_spec._config, predictor, concurrency, batch_size)
+
+    print(f"The prediction job completed successfully! {rows} rows predicted")
+
+
+if __name__ == '__main__':
+    parser = argparse.ArgumentParser(
+        description='Run a prediction job without spark')
+    parser.add_argument('--job-name', type=str, required=False, dest="job_name",
+                        help="The name of prediction job",
+                        default="merlin-prediction-job")
+    parser.add_argument('--spec-path', type=str, required=True,
+                        dest="spec_path",
+                        help="Path to prediction job yaml file")
+    parser.add_argument('--concurrency', type=int, required=False,
+                        dest="concurrency", default=4,
+                        help="Number of batches predicted concurrently")
+    parser.add_argument('--batch-size', type=int, required=False,
+                        dest="batch_size", default=1000,
+                        help="Number of rows per batch")
+    parser.add_argument('--endpoint-url', type=str, required=False,
+                        dest="endpoint_url", default=None,
+                        help="URL of the version endpoint to predict with, "
+                             "the model is loaded in the job if it's not set")
+    parser.add_argument('--endpoint-protocol', type=str, required=False,
+                        dest="endpoint_protocol", default="HTTP_JSON",
+                        help="Protocol of the version endpoint")
+
+    args = parser.parse_args()
+    print(f"Called with arguments: {args}")
+
+    main(args.spec_path, args.concurrency, args.batch_size, args.endpoint_url,
+         args.endpoint_protocol)
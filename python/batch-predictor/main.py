@@ -14,6 +14,7 @@
 # limitations under the License.
 
 import argparse
+import json
 import os
 
 from mlflow import pyfunc
@@ -22,6 +23,8 @@ from merlinpyspark.config import load
 from merlinpyspark.source import create_source
 from merlinpyspark.model import create_model_udf
 from merlinpyspark.sink import create_sink
+from merlinpyspark.quality import QualityCheckError, compute_quality_metrics, \
+    evaluate_quality_checks, should_fail, write_quality_metrics
 
 from pyspark import SparkConf, SparkContext
 from pyspark.sql import SparkSession
@@ -37,7 +40,7 @@ except ImportError:
 DEFAULT_PARALLELISM = 2
 
 
-def main(spec_path, spark):
+def main(spec_path, spark, quality_metrics_path=None, quality_checks=None):
     print(f"loading prediction job spec from: {spec_path}")
     job_spec = load(spec_path)
 
@@ -57,6 +60,12 @@ def main(spec_path, spark):
     if features is None:
         features = df.columns
 
+    source_rows = None
+    if quality_metrics_path is not None:
+        # the source is cached so that it's read once, to count it and to predict
+        df = df.cache()
+        source_rows = df.count()
+
     current_partition = df.rdd.getNumPartitions()
     if target_parallelism > current_partition:
         # Repartition the dataframe to have same number of partition as target_parallelism for better executor utilization
@@ -70,9 +79,35 @@ def main(spec_path, spark):
     model_udf = create_model_udf(spark, job_spec.model(), features)
     data_sink = create_sink(job_spec.sink())
 
-    df = df.withColumn(job_spec.sink().result_column(), model_udf(*features))
+    result_column = job_spec.sink().result_column()
+    df = df.withColumn(result_column, model_udf(*features))
+    if quality_metrics_path is None:
+        data_sink.save(df)
+        print(f"The prediction job completed successfully!")
+        return
+
+    # the predictions are cached so that the model isn't run again to write them
+    df = df.cache()
+    metrics = compute_quality_metrics(source_rows, df, result_column)
+    print(f"quality metrics: {metrics}")
+    write_quality_metrics(metrics, quality_metrics_path)
+
+    if quality_checks is not None:
+        failed = evaluate_quality_checks(quality_checks, metrics)
+        if failed:
+            print(f"data quality checks failed: {', '.join(failed)}")
+            if should_fail(quality_checks):
+                # the predictions are not written to the sink
+                raise QualityCheckError(", ".join(failed))
+
     data_sink.save(df)
 
+    written_rows = data_sink.count_written(spark)
+    if written_rows is not None:
+        metrics["output_rows"] = written_rows
+        print(f"rows written to the sink: {written_rows}")
+        write_quality_metrics(metrics, quality_metrics_path)
+
     print(f"The prediction job completed successfully!")
 
 
@@ -84,6 +119,10 @@ if __name__ == '__main__':
                         help="Path to prediction job yaml file")
     parser.add_argument('--dry-run-model', type=str, required=False, dest="dry_run_path",
                         help="Path to model for dry run", default=None)
+    parser.add_argument('--quality-metrics-path', type=str, required=False, dest="quality_metrics_path",
+                        help="Path to write the quality metrics of the predictions to", default=None)
+    parser.add_argument('--quality-checks', type=json.loads, required=False, dest="quality_checks",
+                        help="Data quality checks evaluated before writing the predictions, as JSON", default=None)
     parser.add_argument('--local', dest='local', action='store_true',
                         required=False, help="flag to run locally", default=False)
 
@@ -130,4 +169,4 @@ if __name__ == '__main__':
     else:
         if args.spec_path is None:
             raise ValueError("--spec-path must be specified if --dry-run-model is not specified")
-        main(args.spec_path, spark)
+        main(args.spec_path, spark, args.quality_metrics_path, args.quality_checks)
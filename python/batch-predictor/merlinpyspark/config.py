@@ -13,7 +13,7 @@
 # limitations under the License.
 
 from abc import ABC, abstractmethod
-from typing import Iterable, MutableMapping, Dict
+from typing import Iterable, MutableMapping, Dict, Optional
 
 import yaml
 from google.protobuf import json_format
@@ -21,7 +21,7 @@ from pyspark.sql.types import ArrayType, DataType, DoubleType, FloatType, \
     IntegerType, LongType, StringType
 
 from merlinpyspark.spec.prediction_job_pb2 import BigQuerySink, BigQuerySource, Model, \
-    PredictionJob, ResultType, ModelType, SaveMode
+    PredictionJob, ResultType, ModelType, SaveMode, SinkPartition
 
 
 def printJobConfig(spec_path):
@@ -113,6 +113,28 @@ class BigQuerySourceConfig(SourceConfig):
     def table(self) -> str:
         return self._proto.table
 
+    def watermark_filter(self) -> Optional[str]:
+        """
+        Filter of the rows after the start watermark, exclusive, up to the end
+        watermark, inclusive, if the source is incremental
+        :return: BigQuery standard SQL filter or None
+        """
+        if not self._proto.HasField("incremental"):
+            return None
+
+        incremental = self._proto.incremental
+        column = f"`{incremental.watermark_column}`"
+        conditions = []
+        if incremental.start_watermark:
+            conditions.append(
+                f"{column} > TIMESTAMP('{incremental.start_watermark}')")
+        if incremental.end_watermark:
+            conditions.append(
+                f"{column} <= TIMESTAMP('{incremental.end_watermark}')")
+        if not conditions:
+            return None
+        return " AND ".join(conditions)
+
 
 class ModelConfig:
     PRIMITIVE_TYPE_MAP = {
@@ -191,3 +213,12 @@ class BigQuerySinkConfig(SinkConfig):
 
     def staging_bucket(self) -> str:
         return self._proto.staging_bucket
+
+    def partition(self) -> Optional[SinkPartition]:
+        """
+        Date partition overwritten by the sink, None if the sink is not
+        partitioned
+        """
+        if not self._proto.HasField("partition"):
+            return None
+        return self._proto.partition
@@ -0,0 +1,282 @@
+# Copyright 2020 The Merlin Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+from abc import ABC, abstractmethod
+from concurrent.futures import ThreadPoolExecutor
+from typing import Iterable, Iterator, List, Optional, Tuple
+
+import pandas
+import requests
+
+from merlinpyspark.spec.prediction_job_pb2 import FileFormat, PredictionJob, \
+    SaveMode
+
+PROTOCOL_HTTP_JSON = "HTTP_JSON"
+PROTOCOL_UPI_V1 = "UPI_V1"
+
+
+def read_batches(source, batch_size: int) -> Iterator[pandas.DataFrame]:
+    """
+    Read the file source of a prediction job in batches of at most batch_size rows
+    :param source: GcsSource proto
+    :param batch_size: number of rows per batch
+    :return: iterator of pandas dataframe
+    """
+    features = list(source.features) or None
+    if source.format == FileFormat.CSV:
+        for batch in pandas.read_csv(source.uri, chunksize=batch_size,
+                                     usecols=features, **source.options):
+            yield batch
+    elif source.format == FileFormat.JSON:
+        for batch in pandas.read_json(source.uri, lines=True,
+                                      chunksize=batch_size, **source.options):
+            yield batch[features] if features else batch
+    elif source.format == FileFormat.PARQUET:
+        import fsspec
+        import pyarrow.parquet as pq
+
+        with fsspec.open(source.uri, "rb") as f:
+            for record_batch in pq.ParquetFile(f).iter_batches(
+                    batch_size=batch_size, columns=features):
+                yield record_batch.to_pandas()
+    else:
+        raise ValueError(
+            f"file format is not supported: {FileFormat.Name(source.format)}")
+
+
+class Predictor(ABC):
+    @abstractmethod
+    def predict(self, df: pandas.DataFrame) -> List:
+        pass
+
+
+class ModelPredictor(Predictor):
+    """
+    Predict by loading the model in the job
+    """
+
+    def __init__(self, model_uri: str):
+        from mlflow import pyfunc
+
+        self._model = pyfunc.load_model(model_uri)
+
+    def predict(self, df: pandas.DataFrame) -> List:
+        result = self._model.predict(df)
+        if isinstance(result, pandas.DataFrame):
+            result = result[result.columns[0]]
+        return list(result)
+
+
+class HttpPredictor(Predictor):
+    """
+    Predict by sending the batch to a running version endpoint with
+    {"instances": [...]} request and {"predictions": [...]} response
+    """
+
+    def __init__(self, endpoint_url: str, timeout: float = 60.0):
+        self._url = f"http://{endpoint_url}:predict"
+        self._session = requests.Session()
+        self._timeout = timeout
+
+    def predict(self, df: pandas.DataFrame) -> List:
+        payload = {"instances": json.loads(df.to_json(orient="values"))}
+        response = self._session.post(self._url, json=payload,
+                                      timeout=self._timeout)
+        response.raise_for_status()
+        return response.json()["predictions"]
+
+
+class UpiPredictor(Predictor):
+    """
+    Predict by sending the batch to a running UPI version endpoint
+    """
+
+    def __init__(self, endpoint_url: str, timeout: float = 60.0):
+        import grpc
+        from caraml.upi.v1 import upi_pb2_grpc
+
+        self._stub = upi_pb2_grpc.UniversalPredictionServiceStub(
+            grpc.insecure_channel(f"{endpoint_url}:80"))
+        self._timeout = timeout
+
+    def predict(self, df: pandas.DataFrame) -> List:
+        from caraml.upi.utils import df_to_table, table_to_df
+        from caraml.upi.v1 import upi_pb2
+
+        request = upi_pb2.PredictValuesRequest(
+            prediction_table=df_to_table(df, "prediction_table"))
+        response = self._stub.PredictValues(request, timeout=self._timeout)
+        result = table_to_df(response.prediction_result_table)
+        return list(result[result.columns[0]])
+
+
+def create_predictor(model_uri: str, endpoint_url: Optional[str],
+                     endpoint_protocol: Optional[str]) -> Predictor:
+    if not endpoint_url:
+        return ModelPredictor(model_uri)
+    if endpoint_protocol == PROTOCOL_UPI_V1:
+        return UpiPredictor(endpoint_url)
+    return HttpPredictor(endpoint_url)
+
+
+def predict_batches(predictor: Predictor,
+                    batches: Iterable[pandas.DataFrame],
+                    result_column: str,
+                    concurrency: int) -> Iterator[pandas.DataFrame]:
+    """
+    Predict the batches with at most concurrency batches in flight,
+    preserving the order of the batches
+    """
+
+    def predict(batch: pandas.DataFrame) -> pandas.DataFrame:
+        batch = batch.copy()
+        batch[result_column] = predictor.predict(batch)
+        return batch
+
+    with ThreadPoolExecutor(max_workers=concurrency) as executor:
+        in_flight = []
+        for batch in batches:
+            in_flight.append(executor.submit(predict, batch))
+            if len(in_flight) >= concurrency:
+                yield in_flight.pop(0).result()
+        for future in in_flight:
+            yield future.result()
+
+
+class Sink(ABC):
+    @abstractmethod
+    def write(self, df: pandas.DataFrame, part: int):
+        pass
+
+    def close(self):
+        pass
+
+
+class GcsSink(Sink):
+    """
+    Write each predicted batch as a part file under the sink uri, or under
+    the <column>=<date> directory of the sink uri if the sink is partitioned
+    """
+
+    EXTENSIONS = {
+        FileFormat.CSV: "csv",
+        FileFormat.PARQUET: "parquet",
+        FileFormat.JSON: "json",
+    }
+
+    def __init__(self, sink):
+        if sink.format not in self.EXTENSIONS:
+            raise ValueError(
+                f"file format is not supported: {FileFormat.Name(sink.format)}")
+
+        import fsspec
+
+        self._sink = sink
+        self._fs, self._path = fsspec.core.url_to_fs(sink.uri)
+        save_mode = sink.save_mode
+        if sink.HasField("partition"):
+            # only the partition is overwritten when the job is rerun
+            self._path = f"{self._path.rstrip('/')}/" \
+                         f"{sink.partition.column}={sink.partition.value}"
+            save_mode = SaveMode.OVERWRITE
+
+        if self._fs.exists(self._path) and self._fs.ls(self._path):
+            if save_mode == SaveMode.OVERWRITE:
+                self._fs.rm(self._path, recursive=True)
+            elif save_mode in (SaveMode.ERRORIFEXISTS, SaveMode.ERROR):
+                raise ValueError(f"{sink.uri} already exists")
+        self._fs.makedirs(self._path, exist_ok=True)
+
+    def write(self, df: pandas.DataFrame, part: int):
+        extension = self.EXTENSIONS[self._sink.format]
+        with self._fs.open(f"{self._path}/part-{part:05d}.{extension}",
+                           "wb") as f:
+            if self._sink.format == FileFormat.CSV:
+                f.write(df.to_csv(index=False).encode())
+            elif self._sink.format == FileFormat.JSON:
+                f.write(df.to_json(orient="records", lines=True).encode())
+            else:
+                df.to_parquet(f, index=False)
+
+
+class BigQuerySink(Sink):
+    """
+    Load each predicted batch into the sink table, or into the date partition
+    of the sink table if the sink is partitioned
+    """
+
+    def __init__(self, sink):
+        from google.cloud import bigquery
+
+        self._bigquery = bigquery
+        self._client = bigquery.Client(project=sink.options.get("project"))
+        self._table = sink.table
+        self._save_mode = sink.save_mode
+        self._partition = None
+        if sink.HasField("partition"):
+            # only the partition is overwritten when the job is rerun
+            self._partition = sink.partition
+            self._table = f"{sink.table}${sink.partition.value.replace('-', '')}"
+            self._save_mode = SaveMode.OVERWRITE
+
+    def write(self, df: pandas.DataFrame, part: int):
+        write_disposition = self._bigquery.WriteDisposition.WRITE_APPEND
+        if part == 0 and self._save_mode == SaveMode.OVERWRITE:
+            write_disposition = self._bigquery.WriteDisposition.WRITE_TRUNCATE
+        elif part == 0 and self._save_mode in (SaveMode.ERRORIFEXISTS,
+                                               SaveMode.ERROR):
+            write_disposition = self._bigquery.WriteDisposition.WRITE_EMPTY
+
+        job_config = self._bigquery.LoadJobConfig(
+            write_disposition=write_disposition)
+        if self._partition is not None:
+            df = df.assign(**{self._partition.column: pandas.to_datetime(
+                self._partition.value).date()})
+            job_config.time_partitioning = self._bigquery.TimePartitioning(
+                field=self._partition.column)
+        self._client.load_table_from_dataframe(
+            df, self._table, job_config=job_config).result()
+
+
+def create_sink(job_spec: PredictionJob) -> Tuple[Sink, str]:
+    sink = job_spec.WhichOneof("sink")
+    if sink == "gcs_sink":
+        return GcsSink(job_spec.gcs_sink), job_spec.gcs_sink.result_column
+    if sink == "bigquery_sink":
+        return BigQuerySink(job_spec.bigquery_sink), \
+               job_spec.bigquery_sink.result_column
+    raise ValueError(f"sink type is not implemented: {sink}")
+
+
+def run(job_spec: PredictionJob, predictor: Predictor, concurrency: int,
+        batch_size: int) -> int:
+    """
+    Stream the source of the prediction job through the predictor into the sink
+    :return: number of predicted rows
+    """
+    if job_spec.WhichOneof("source") != "gcs_source":
+        raise ValueError("lightweight prediction job only supports file source")
+
+    sink, result_column = create_sink(job_spec)
+    batches = read_batches(job_spec.gcs_source, batch_size)
+
+    rows = 0
+    for part, df in enumerate(
+            predict_batches(predictor, batches, result_column, concurrency)):
+        sink.write(df, part)
+        rows += len(df)
+        print(f"batch {part} predicted, {rows} rows written")
+    sink.close()
+    return rows
@@ -0,0 +1,190 @@
+# Copyright 2020 The Merlin Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+import math
+from typing import List
+
+from pyspark.sql import DataFrame
+from pyspark.sql import functions as F
+from pyspark.sql.types import NumericType
+
+# evenly spaced quantiles of the result column, from the minimum to the maximum
+QUANTILES = [i / 10 for i in range(11)]
+QUANTILE_RELATIVE_ERROR = 0.001
+# minimum share of a bin when computing the population stability index,
+# to avoid dividing by zero
+PSI_EPSILON = 0.0001
+
+ACTION_FAIL = "fail"
+
+
+class QualityCheckError(Exception):
+    """
+    Raised when the predictions fail the data quality checks, so that they're
+    not written to the sink
+    """
+
+
+def compute_quality_metrics(source_rows: int, df: DataFrame,
+                            result_column: str) -> dict:
+    """
+    Compute the metrics evaluated by the data quality checks of the
+    prediction job on the predictions, before they're written to the sink
+
+    :param source_rows: number of rows read from the source
+    :param df: dataframe of the predictions
+    :param result_column: column containing the predictions
+    :return: quality metrics
+    """
+    column = F.col(result_column)
+    numeric = isinstance(df.schema[result_column].dataType, NumericType)
+
+    # NaN predictions are counted as null and excluded from the distribution
+    missing = column.isNull()
+    if numeric:
+        missing = missing | F.isnan(column)
+        df = df.withColumn(result_column, F.when(~missing, column))
+
+    aggregations = [F.count(F.lit(1)).alias("output_rows"),
+                    F.sum(F.when(missing, 1).otherwise(0))
+                    .alias("null_count")]
+    if numeric:
+        aggregations += [F.min(column).alias("min"),
+                         F.max(column).alias("max")]
+    row = df.agg(*aggregations).collect()[0]
+
+    metrics = {
+        "source_rows": source_rows,
+        "output_rows": row["output_rows"],
+        "null_count": row["null_count"] or 0,
+    }
+    if numeric and row["min"] is not None:
+        metrics["min"] = float(row["min"])
+        metrics["max"] = float(row["max"])
+        metrics["quantiles"] = df.approxQuantile(result_column, QUANTILES,
+                                                 QUANTILE_RELATIVE_ERROR)
+    return metrics
+
+
+def write_quality_metrics(metrics: dict, path: str):
+    """
+    Write the quality metrics to the given path, which is the termination
+    message of the container so that merlin can read them once it terminated
+    """
+    with open(path, "w") as f:
+        json.dump(metrics, f)
+
+
+def evaluate_quality_checks(checks: dict, metrics: dict) -> List[str]:
+    """
+    Evaluate the data quality checks of the prediction job on the metrics of
+    its predictions, like merlin does once the prediction job completed.
+    The row ratio is only evaluated by merlin, once the rows are written.
+    The drift is only evaluated if the quantiles of the reference job were
+    passed with the checks.
+
+    :param checks: quality checks of the prediction job
+    :param metrics: quality metrics of the predictions
+    :return: description of the failed checks
+    """
+    failed = []
+
+    max_null_fraction = checks.get("max_null_fraction")
+    if max_null_fraction is not None:
+        fraction = 0.0
+        if metrics["output_rows"] > 0:
+            fraction = metrics["null_count"] / metrics["output_rows"]
+        if fraction > max_null_fraction:
+            failed.append(f"null_fraction: {fraction} "
+                          f"(threshold {max_null_fraction})")
+
+    min_value = checks.get("min_value")
+    if min_value is not None:
+        if metrics.get("min") is None:
+            failed.append("min_value: result column has no numeric value")
+        elif metrics["min"] < min_value:
+            failed.append(f"min_value: {metrics['min']} "
+                          f"(threshold {min_value})")
+
+    max_value = checks.get("max_value")
+    if max_value is not None:
+        if metrics.get("max") is None:
+            failed.append("max_value: result column has no numeric value")
+        elif metrics["max"] > max_value:
+            failed.append(f"max_value: {metrics['max']} "
+                          f"(threshold {max_value})")
+
+    drift = checks.get("drift")
+    reference = (drift or {}).get("reference_quantiles")
+    if drift is not None and reference is not None and len(reference) >= 2:
+        quantiles = metrics.get("quantiles")
+        if quantiles is None or len(quantiles) < 2:
+            failed.append("drift: result column has no numeric value")
+        else:
+            psi = population_stability_index(reference, quantiles)
+            if psi > drift["max_psi"]:
+                failed.append(f"drift: {psi} (threshold {drift['max_psi']})")
+
+    return failed
+
+
+def should_fail(checks: dict) -> bool:
+    """
+    Return true if the prediction job fails when any check fails, instead of
+    only warning
+    """
+    return checks.get("action") in (None, "", ACTION_FAIL)
+
+
+def population_stability_index(reference: List[float],
+                               current: List[float]) -> float:
+    """
+    Compare two distributions given by their evenly spaced quantiles, the
+    same way as merlin. The bins are delimited by the inner quantiles of the
+    reference distribution, and the share of the current distribution in
+    each bin is interpolated linearly between its quantiles.
+    """
+    edges = reference[1:-1]
+
+    psi = 0.0
+    lower = -math.inf
+    for i in range(len(edges) + 1):
+        upper = edges[i] if i < len(edges) else math.inf
+
+        expected = max(_quantile_cdf(reference, upper) -
+                       _quantile_cdf(reference, lower), PSI_EPSILON)
+        actual = max(_quantile_cdf(current, upper) -
+                     _quantile_cdf(current, lower), PSI_EPSILON)
+        psi += (actual - expected) * math.log(actual / expected)
+        lower = upper
+    return psi
+
+
+def _quantile_cdf(quantiles: List[float], x: float) -> float:
+    """
+    Share of a distribution given by its evenly spaced quantiles that is
+    lower or equal to x
+    """
+    last = len(quantiles) - 1
+    if x < quantiles[0]:
+        return 0.0
+    if x >= quantiles[last]:
+        return 1.0
+    for i in range(last - 1, 0, -1):
+        if x >= quantiles[i]:
+            return (i + (x - quantiles[i]) /
+                    (quantiles[i + 1] - quantiles[i])) / last
+    return (x - quantiles[0]) / (quantiles[1] - quantiles[0]) / last
+
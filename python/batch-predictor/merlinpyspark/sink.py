@@ -13,8 +13,10 @@
 # limitations under the License.
 
 from abc import ABC, abstractmethod
+from typing import Optional
 
-from pyspark.sql import DataFrame
+from pyspark.sql import DataFrame, SparkSession
+from pyspark.sql import functions as F
 
 from merlinpyspark.config import SinkConfig, BigQuerySinkConfig
 
@@ -34,20 +36,67 @@ class Sink(ABC):
     def save(self, df):
         pass
 
+    def count_written(self, spark: SparkSession) -> Optional[int]:
+        """
+        Count the rows written by the last save, None if they can't be told
+        apart from the rows already in the sink
+        """
+        return None
+
 
 class BigQuerySink(Sink):
     WRITE_FORMAT = "bigquery"
     OPTION_TABLE = "table"
     OPTION_STAGING_BUCKET = "temporaryGcsBucket"
+    OPTION_PARTITION_FIELD = "partitionField"
+    OPTION_PARTITION_TYPE = "partitionType"
+    OPTION_DATE_PARTITION = "datePartition"
+    SAVE_MODE_OVERWRITE = "overwrite"
+    SAVE_MODE_ERROR_IF_EXISTS = "errorifexists"
 
     def __init__(self, config: BigQuerySinkConfig):
         self._config = config
 
     def save(self, df: DataFrame):
+        save_mode = self._config.save_mode()
+        partition_options = {}
+
+        partition = self._config.partition()
+        if partition is not None:
+            # only the partition is overwritten, so rerunning the job
+            # replaces its own result without touching other partitions
+            df = df.withColumn(partition.column,
+                               F.to_date(F.lit(partition.value)))
+            save_mode = self.SAVE_MODE_OVERWRITE
+            partition_options = {
+                self.OPTION_PARTITION_FIELD: partition.column,
+                self.OPTION_PARTITION_TYPE: "DAY",
+                self.OPTION_DATE_PARTITION: partition.value.replace("-", ""),
+            }
+
         df.write \
-            .mode(self._config.save_mode()) \
+            .mode(save_mode) \
             .format(self.WRITE_FORMAT) \
             .option(self.OPTION_TABLE, self._config.table()) \
             .option(self.OPTION_STAGING_BUCKET, self._config.staging_bucket()) \
+            .options(**partition_options) \
             .options(**self._config.options()) \
             .save()
+
+    def count_written(self, spark: SparkSession) -> Optional[int]:
+        table = spark.read \
+            .format(self.WRITE_FORMAT) \
+            .option(self.OPTION_TABLE, self._config.table()) \
+            .options(**self._config.options()) \
+            .load()
+
+        partition = self._config.partition()
+        if partition is not None:
+            # the partition only contains the rows written by the last save
+            return table.where(F.col(partition.column) ==
+                               F.to_date(F.lit(partition.value))).count()
+
+        if self._config.save_mode() in (self.SAVE_MODE_OVERWRITE,
+                                        self.SAVE_MODE_ERROR_IF_EXISTS):
+            return table.count()
+        return None
@@ -55,6 +55,7 @@ class BigQuerySource(Source):
     READ_FORMAT = "bigquery"
     OPTION_TABLE = "table"
     OPTION_PARALLELISM = "maxParallelism"
+    OPTION_FILTER = "filter"
 
     def __init__(self, spark_session: SparkSession,
                  bq_source_config: BigQuerySourceConfig):
@@ -71,8 +72,15 @@ class BigQuerySource(Source):
         reader = self._spark.read.format(self.READ_FORMAT) \
             .option(self.OPTION_TABLE, cfg.table())
 
-        if cfg.options() is not None:
-            reader.options(**cfg.options())
+        options = dict(cfg.options()) if cfg.options() is not None else {}
+        watermark_filter = cfg.watermark_filter()
+        if watermark_filter is not None:
+            user_filter = options.get(self.OPTION_FILTER)
+            options[self.OPTION_FILTER] = watermark_filter if not user_filter \
+                else f"({user_filter}) AND ({watermark_filter})"
+
+        if options:
+            reader.options(**options)
 
         df = reader.load()
         if features is not None:
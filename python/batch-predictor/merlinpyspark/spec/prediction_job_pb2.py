@@ -13,7 +13,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x19spec/prediction_job.proto\x12\x11merlin.batch.spec\"\xd3\x02\n\rPredictionJob\x12\x0f\n\x07version\x18\x01 \x01(\t\x12\x0c\n\x04kind\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12<\n\x0f\x62igquery_source\x18\x0b \x01(\x0b\x32!.merlin.batch.spec.BigQuerySourceH\x00\x12\x32\n\ngcs_source\x18\x0c \x01(\x0b\x32\x1c.merlin.batch.spec.GcsSourceH\x00\x12\'\n\x05model\x18\x15 \x01(\x0b\x32\x18.merlin.batch.spec.Model\x12\x38\n\rbigquery_sink\x18\x1f \x01(\x0b\x32\x1f.merlin.batch.spec.BigQuerySinkH\x01\x12.\n\x08gcs_sink\x18  \x01(\x0b\x32\x1a.merlin.batch.spec.GcsSinkH\x01\x42\x08\n\x06sourceB\x06\n\x04sink\"\xa2\x01\n\x0e\x42igQuerySource\x12\r\n\x05table\x18\x01 \x01(\t\x12\x10\n\x08\x66\x65\x61tures\x18\x02 \x03(\t\x12?\n\x07options\x18\x03 \x03(\x0b\x32..merlin.batch.spec.BigQuerySource.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xc5\x01\n\tGcsSource\x12-\n\x06\x66ormat\x18\x01 \x01(\x0e\x32\x1d.merlin.batch.spec.FileFormat\x12\x0b\n\x03uri\x18\x02 \x01(\t\x12\x10\n\x08\x66\x65\x61tures\x18\x03 \x03(\t\x12:\n\x07options\x18\x04 \x03(\x0b\x32).merlin.batch.spec.GcsSource.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xcc\x02\n\x05Model\x12*\n\x04type\x18\x01 \x01(\x0e\x32\x1c.merlin.batch.spec.ModelType\x12\x0b\n\x03uri\x18\x02 \x01(\t\x12\x34\n\x06result\x18\x03 \x01(\x0b\x32$.merlin.batch.spec.Model.ModelResult\x12\x36\n\x07options\x18\x04 \x03(\x0b\x32%.merlin.batch.spec.Model.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1al\n\x0bModelResult\x12+\n\x04type\x18\x01 \x01(\x0e\x32\x1d.merlin.batch.spec.ResultType\x12\x30\n\titem_type\x18\x02 \x01(\x0e\x32\x1d.merlin.batch.spec.ResultType\"\xeb\x01\n\x0c\x42igQuerySink\x12\r\n\x05table\x18\x01 \x01(\t\x12\x16\n\x0estaging_bucket\x18\x02 \x01(\t\x12\x15\n\rresult_column\x18\x03 \x01(\t\x12.\n\tsave_mode\x18\x04 \x01(\x0e\x32\x1b.merlin.batch.spec.SaveMode\x12=\n\x07options\x18\x05 \x03(\x0b\x32,.merlin.batch.spec.BigQuerySink.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xf6\x01\n\x07GcsSink\x12-\n\x06\x66ormat\x18\x01 \x01(\x0e\x32\x1d.merlin.batch.spec.FileFormat\x12\x0b\n\x03uri\x18\x02 \x01(\t\x12\x15\n\rresult_column\x18\x03 \x01(\t\x12.\n\tsave_mode\x18\x04 \x01(\x0e\x32\x1b.merlin.batch.spec.SaveMode\x12\x38\n\x07options\x18\x05 \x03(\x0b\x32\'.merlin.batch.spec.GcsSink.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01*Q\n\nResultType\x12\n\n\x06\x44OUBLE\x10\x00\x12\t\n\x05\x46LOAT\x10\x01\x12\x0b\n\x07INTEGER\x10\x02\x12\x08\n\x04LONG\x10\x03\x12\n\n\x06STRING\x10\x04\x12\t\n\x05\x41RRAY\x10\n*\x7f\n\tModelType\x12\x16\n\x12INVALID_MODEL_TYPE\x10\x00\x12\x0b\n\x07XGBOOST\x10\x01\x12\x0e\n\nTENSORFLOW\x10\x02\x12\x0b\n\x07SKLEARN\x10\x03\x12\x0b\n\x07PYTORCH\x10\x04\x12\x08\n\x04ONNX\x10\x05\x12\n\n\x06PYFUNC\x10\x06\x12\r\n\tPYFUNC_V2\x10\x07*O\n\nFileFormat\x12\x17\n\x13INVALID_FILE_FORMAT\x10\x00\x12\x07\n\x03\x43SV\x10\x01\x12\x0b\n\x07PARQUET\x10\x02\x12\x08\n\x04\x41VRO\x10\x03\x12\x08\n\x04JSON\x10\x04*O\n\x08SaveMode\x12\x11\n\rERRORIFEXISTS\x10\x00\x12\r\n\tOVERWRITE\x10\x01\x12\n\n\x06\x41PPEND\x10\x02\x12\n\n\x06IGNORE\x10\x03\x12\t\n\x05\x45RROR\x10\x04\x42k\n com.caraml-dev.merlin.batch.specB\x12PredictionJobProtoP\x01Z1github.com/caraml-dev/merlin-pyspark-app/pkg/specb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x19spec/prediction_job.proto\x12\x11merlin.batch.spec\"\xd3\x02\n\rPredictionJob\x12\x0f\n\x07version\x18\x01 \x01(\t\x12\x0c\n\x04kind\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12<\n\x0f\x62igquery_source\x18\x0b \x01(\x0b\x32!.merlin.batch.spec.BigQuerySourceH\x00\x12\x32\n\ngcs_source\x18\x0c \x01(\x0b\x32\x1c.merlin.batch.spec.GcsSourceH\x00\x12\'\n\x05model\x18\x15 \x01(\x0b\x32\x18.merlin.batch.spec.Model\x12\x38\n\rbigquery_sink\x18\x1f \x01(\x0b\x32\x1f.merlin.batch.spec.BigQuerySinkH\x01\x12.\n\x08gcs_sink\x18  \x01(\x0b\x32\x1a.merlin.batch.spec.GcsSinkH\x01\x42\x08\n\x06sourceB\x06\n\x04sink\"\xdd\x01\n\x0e\x42igQuerySource\x12\r\n\x05table\x18\x01 \x01(\t\x12\x10\n\x08\x66\x65\x61tures\x18\x02 \x03(\t\x12?\n\x07options\x18\x03 \x03(\x0b\x32..merlin.batch.spec.BigQuerySource.OptionsEntry\x12\x39\n\x0bincremental\x18\x04 \x01(\x0b\x32$.merlin.batch.spec.IncrementalSource\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xc5\x01\n\tGcsSource\x12-\n\x06\x66ormat\x18\x01 \x01(\x0e\x32\x1d.merlin.batch.spec.FileFormat\x12\x0b\n\x03uri\x18\x02 \x01(\t\x12\x10\n\x08\x66\x65\x61tures\x18\x03 \x03(\t\x12:\n\x07options\x18\x04 \x03(\x0b\x32).merlin.batch.spec.GcsSource.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xcc\x02\n\x05Model\x12*\n\x04type\x18\x01 \x01(\x0e\x32\x1c.merlin.batch.spec.ModelType\x12\x0b\n\x03uri\x18\x02 \x01(\t\x12\x34\n\x06result\x18\x03 \x01(\x0b\x32$.merlin.batch.spec.Model.ModelResult\x12\x36\n\x07options\x18\x04 \x03(\x0b\x32%.merlin.batch.spec.Model.OptionsEntry\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1al\n\x0bModelResult\x12+\n\x04type\x18\x01 \x01(\x0e\x32\x1d.merlin.batch.spec.ResultType\x12\x30\n\titem_type\x18\x02 \x01(\x0e\x32\x1d.merlin.batch.spec.ResultType\"\xa0\x02\n\x0c\x42igQuerySink\x12\r\n\x05table\x18\x01 \x01(\t\x12\x16\n\x0estaging_bucket\x18\x02 \x01(\t\x12\x15\n\rresult_column\x18\x03 \x01(\t\x12.\n\tsave_mode\x18\x04 \x01(\x0e\x32\x1b.merlin.batch.spec.SaveMode\x12=\n\x07options\x18\x05 \x03(\x0b\x32,.merlin.batch.spec.BigQuerySink.OptionsEntry\x12\x33\n\tpartition\x18\x06 \x01(\x0b\x32 .merlin.batch.spec.SinkPartition\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xab\x02\n\x07GcsSink\x12-\n\x06\x66ormat\x18\x01 \x01(\x0e\x32\x1d.merlin.batch.spec.FileFormat\x12\x0b\n\x03uri\x18\x02 \x01(\t\x12\x15\n\rresult_column\x18\x03 \x01(\t\x12.\n\tsave_mode\x18\x04 \x01(\x0e\x32\x1b.merlin.batch.spec.SaveMode\x12\x38\n\x07options\x18\x05 \x03(\x0b\x32\'.merlin.batch.spec.GcsSink.OptionsEntry\x12\x33\n\tpartition\x18\x06 \x01(\x0b\x32 .merlin.batch.spec.SinkPartition\x1a.\n\x0cOptionsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"]\n\x11IncrementalSource\x12\x18\n\x10watermark_column\x18\x01 \x01(\t\x12\x17\n\x0fstart_watermark\x18\x02 \x01(\t\x12\x15\n\rend_watermark\x18\x03 \x01(\t\".\n\rSinkPartition\x12\x0e\n\x06\x63olumn\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t*Q\n\nResultType\x12\n\n\x06\x44OUBLE\x10\x00\x12\t\n\x05\x46LOAT\x10\x01\x12\x0b\n\x07INTEGER\x10\x02\x12\x08\n\x04LONG\x10\x03\x12\n\n\x06STRING\x10\x04\x12\t\n\x05\x41RRAY\x10\n*\x7f\n\tModelType\x12\x16\n\x12INVALID_MODEL_TYPE\x10\x00\x12\x0b\n\x07XGBOOST\x10\x01\x12\x0e\n\nTENSORFLOW\x10\x02\x12\x0b\n\x07SKLEARN\x10\x03\x12\x0b\n\x07PYTORCH\x10\x04\x12\x08\n\x04ONNX\x10\x05\x12\n\n\x06PYFUNC\x10\x06\x12\r\n\tPYFUNC_V2\x10\x07*O\n\nFileFormat\x12\x17\n\x13INVALID_FILE_FORMAT\x10\x00\x12\x07\n\x03\x43SV\x10\x01\x12\x0b\n\x07PARQUET\x10\x02\x12\x08\n\x04\x41VRO\x10\x03\x12\x08\n\x04JSON\x10\x04*O\n\x08SaveMode\x12\x11\n\rERRORIFEXISTS\x10\x00\x12\r\n\tOVERWRITE\x10\x01\x12\n\n\x06\x41PPEND\x10\x02\x12\n\n\x06IGNORE\x10\x03\x12\t\n\x05\x45RROR\x10\x04\x42k\n com.caraml-dev.merlin.batch.specB\x12PredictionJobProtoP\x01Z1github.com/caraml-dev/merlin-pyspark-app/pkg/specb\x06proto3')
 
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, globals())
 _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'spec.prediction_job_pb2', globals())
@@ -31,36 +31,40 @@ if _descriptor._USE_C_DESCRIPTORS == False:
   _BIGQUERYSINK_OPTIONSENTRY._serialized_options = b'8\001'
   _GCSSINK_OPTIONSENTRY._options = None
   _GCSSINK_OPTIONSENTRY._serialized_options = b'8\001'
-  _RESULTTYPE._serialized_start=1577
-  _RESULTTYPE._serialized_end=1658
-  _MODELTYPE._serialized_start=1660
-  _MODELTYPE._serialized_end=1787
-  _FILEFORMAT._serialized_start=1789
-  _FILEFORMAT._serialized_end=1868
-  _SAVEMODE._serialized_start=1870
-  _SAVEMODE._serialized_end=1949
+  _RESULTTYPE._serialized_start=1885
+  _RESULTTYPE._serialized_end=1966
+  _MODELTYPE._serialized_start=1968
+  _MODELTYPE._serialized_end=2095
+  _FILEFORMAT._serialized_start=2097
+  _FILEFORMAT._serialized_end=2176
+  _SAVEMODE._serialized_start=2178
+  _SAVEMODE._serialized_end=2257
   _PREDICTIONJOB._serialized_start=49
   _PREDICTIONJOB._serialized_end=388
   _BIGQUERYSOURCE._serialized_start=391
-  _BIGQUERYSOURCE._serialized_end=553
-  _BIGQUERYSOURCE_OPTIONSENTRY._serialized_start=507
-  _BIGQUERYSOURCE_OPTIONSENTRY._serialized_end=553
-  _GCSSOURCE._serialized_start=556
-  _GCSSOURCE._serialized_end=753
-  _GCSSOURCE_OPTIONSENTRY._serialized_start=507
-  _GCSSOURCE_OPTIONSENTRY._serialized_end=553
-  _MODEL._serialized_start=756
-  _MODEL._serialized_end=1088
-  _MODEL_OPTIONSENTRY._serialized_start=507
-  _MODEL_OPTIONSENTRY._serialized_end=553
-  _MODEL_MODELRESULT._serialized_start=980
-  _MODEL_MODELRESULT._serialized_end=1088
-  _BIGQUERYSINK._serialized_start=1091
-  _BIGQUERYSINK._serialized_end=1326
-  _BIGQUERYSINK_OPTIONSENTRY._serialized_start=507
-  _BIGQUERYSINK_OPTIONSENTRY._serialized_end=553
-  _GCSSINK._serialized_start=1329
-  _GCSSINK._serialized_end=1575
-  _GCSSINK_OPTIONSENTRY._serialized_start=507
-  _GCSSINK_OPTIONSENTRY._serialized_end=553
+  _BIGQUERYSOURCE._serialized_end=612
+  _BIGQUERYSOURCE_OPTIONSENTRY._serialized_start=566
+  _BIGQUERYSOURCE_OPTIONSENTRY._serialized_end=612
+  _GCSSOURCE._serialized_start=615
+  _GCSSOURCE._serialized_end=812
+  _GCSSOURCE_OPTIONSENTRY._serialized_start=566
+  _GCSSOURCE_OPTIONSENTRY._serialized_end=612
+  _MODEL._serialized_start=815
+  _MODEL._serialized_end=1147
+  _MODEL_OPTIONSENTRY._serialized_start=566
+  _MODEL_OPTIONSENTRY._serialized_end=612
+  _MODEL_MODELRESULT._serialized_start=1039
+  _MODEL_MODELRESULT._serialized_end=1147
+  _BIGQUERYSINK._serialized_start=1150
+  _BIGQUERYSINK._serialized_end=1438
+  _BIGQUERYSINK_OPTIONSENTRY._serialized_start=566
+  _BIGQUERYSINK_OPTIONSENTRY._serialized_end=612
+  _GCSSINK._serialized_start=1441
+  _GCSSINK._serialized_end=1740
+  _GCSSINK_OPTIONSENTRY._serialized_start=566
+  _GCSSINK_OPTIONSENTRY._serialized_end=612
+  _INCREMENTALSOURCE._serialized_start=1742
+  _INCREMENTALSOURCE._serialized_end=1835
+  _SINKPARTITION._serialized_start=1837
+  _SINKPARTITION._serialized_end=1883
 # @@protoc_insertion_point(module_scope)
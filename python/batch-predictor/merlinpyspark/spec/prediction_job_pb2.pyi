@@ -216,11 +216,15 @@ class BigQuerySource(google___protobuf___message___Message):
     @property
     def options(self) -> typing___MutableMapping[typing___Text, typing___Text]: ...
 
+    @property
+    def incremental(self) -> IncrementalSource: ...
+
     def __init__(self,
         *,
         table : typing___Optional[typing___Text] = None,
         features : typing___Optional[typing___Iterable[typing___Text]] = None,
         options : typing___Optional[typing___Mapping[typing___Text, typing___Text]] = None,
+        incremental : typing___Optional[IncrementalSource] = None,
         ) -> None: ...
     if sys.version_info >= (3,):
         @classmethod
@@ -230,7 +234,8 @@ class BigQuerySource(google___protobuf___message___Message):
         def FromString(cls, s: typing___Union[builtin___bytes, builtin___buffer, builtin___unicode]) -> BigQuerySource: ...
     def MergeFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
     def CopyFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"features",b"features",u"options",b"options",u"table",b"table"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"incremental",b"incremental"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"features",b"features",u"incremental",b"incremental",u"options",b"options",u"table",b"table"]) -> None: ...
 
 class GcsSource(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
@@ -377,6 +382,9 @@ class BigQuerySink(google___protobuf___message___Message):
     @property
     def options(self) -> typing___MutableMapping[typing___Text, typing___Text]: ...
 
+    @property
+    def partition(self) -> SinkPartition: ...
+
     def __init__(self,
         *,
         table : typing___Optional[typing___Text] = None,
@@ -384,6 +392,7 @@ class BigQuerySink(google___protobuf___message___Message):
         result_column : typing___Optional[typing___Text] = None,
         save_mode : typing___Optional[SaveMode] = None,
         options : typing___Optional[typing___Mapping[typing___Text, typing___Text]] = None,
+        partition : typing___Optional[SinkPartition] = None,
         ) -> None: ...
     if sys.version_info >= (3,):
         @classmethod
@@ -393,7 +402,8 @@ class BigQuerySink(google___protobuf___message___Message):
         def FromString(cls, s: typing___Union[builtin___bytes, builtin___buffer, builtin___unicode]) -> BigQuerySink: ...
     def MergeFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
     def CopyFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"options",b"options",u"result_column",b"result_column",u"save_mode",b"save_mode",u"staging_bucket",b"staging_bucket",u"table",b"table"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"partition",b"partition"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"options",b"options",u"partition",b"partition",u"result_column",b"result_column",u"save_mode",b"save_mode",u"staging_bucket",b"staging_bucket",u"table",b"table"]) -> None: ...
 
 class GcsSink(google___protobuf___message___Message):
     DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
@@ -425,6 +435,9 @@ class GcsSink(google___protobuf___message___Message):
     @property
     def options(self) -> typing___MutableMapping[typing___Text, typing___Text]: ...
 
+    @property
+    def partition(self) -> SinkPartition: ...
+
     def __init__(self,
         *,
         format : typing___Optional[FileFormat] = None,
@@ -432,6 +445,7 @@ class GcsSink(google___protobuf___message___Message):
         result_column : typing___Optional[typing___Text] = None,
         save_mode : typing___Optional[SaveMode] = None,
         options : typing___Optional[typing___Mapping[typing___Text, typing___Text]] = None,
+        partition : typing___Optional[SinkPartition] = None,
         ) -> None: ...
     if sys.version_info >= (3,):
         @classmethod
@@ -441,4 +455,47 @@ class GcsSink(google___protobuf___message___Message):
         def FromString(cls, s: typing___Union[builtin___bytes, builtin___buffer, builtin___unicode]) -> GcsSink: ...
     def MergeFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
     def CopyFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
-    def ClearField(self, field_name: typing_extensions___Literal[u"format",b"format",u"options",b"options",u"result_column",b"result_column",u"save_mode",b"save_mode",u"uri",b"uri"]) -> None: ...
+    def HasField(self, field_name: typing_extensions___Literal[u"partition",b"partition"]) -> builtin___bool: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"format",b"format",u"options",b"options",u"partition",b"partition",u"result_column",b"result_column",u"save_mode",b"save_mode",u"uri",b"uri"]) -> None: ...
+
+class IncrementalSource(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    watermark_column = ... # type: typing___Text
+    start_watermark = ... # type: typing___Text
+    end_watermark = ... # type: typing___Text
+
+    def __init__(self,
+        *,
+        watermark_column : typing___Optional[typing___Text] = None,
+        start_watermark : typing___Optional[typing___Text] = None,
+        end_watermark : typing___Optional[typing___Text] = None,
+        ) -> None: ...
+    if sys.version_info >= (3,):
+        @classmethod
+        def FromString(cls, s: builtin___bytes) -> IncrementalSource: ...
+    else:
+        @classmethod
+        def FromString(cls, s: typing___Union[builtin___bytes, builtin___buffer, builtin___unicode]) -> IncrementalSource: ...
+    def MergeFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
+    def CopyFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"end_watermark",b"end_watermark",u"start_watermark",b"start_watermark",u"watermark_column",b"watermark_column"]) -> None: ...
+
+class SinkPartition(google___protobuf___message___Message):
+    DESCRIPTOR: google___protobuf___descriptor___Descriptor = ...
+    column = ... # type: typing___Text
+    value = ... # type: typing___Text
+
+    def __init__(self,
+        *,
+        column : typing___Optional[typing___Text] = None,
+        value : typing___Optional[typing___Text] = None,
+        ) -> None: ...
+    if sys.version_info >= (3,):
+        @classmethod
+        def FromString(cls, s: builtin___bytes) -> SinkPartition: ...
+    else:
+        @classmethod
+        def FromString(cls, s: typing___Union[builtin___bytes, builtin___buffer, builtin___unicode]) -> SinkPartition: ...
+    def MergeFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
+    def CopyFrom(self, other_msg: google___protobuf___message___Message) -> None: ...
+    def ClearField(self, field_name: typing_extensions___Literal[u"column",b"column",u"value",b"value"]) -> None: ...
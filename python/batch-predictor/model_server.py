@@ -0,0 +1,100 @@
+# Copyright 2020 The Merlin Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import argparse
+import json
+import threading
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+
+import pandas
+
+from merlinpyspark.lightweight import ModelPredictor
+
+
+def create_handler(predictor: ModelPredictor, server_ref: list):
+    """
+    Create a request handler serving the model with the same
+    {"instances": [...]} request and {"predictions": [...]} response of
+    the version endpoint, so that the standard transformer of a prediction
+    job can call it in place of the predictor
+    """
+
+    class ModelHandler(BaseHTTPRequestHandler):
+        def do_GET(self):
+            self._respond(200, {"status": "ready"})
+
+        def do_POST(self):
+            if self.path == "/shutdown":
+                self._respond(200, {"status": "shutting down"})
+                threading.Thread(target=server_ref[0].shutdown).start()
+                return
+
+            if not self.path.endswith(":predict"):
+                self._respond(404, {"error": f"unknown path: {self.path}"})
+                return
+
+            try:
+                length = int(self.headers.get("Content-Length", 0))
+                payload = json.loads(self.rfile.read(length))
+                df = pandas.DataFrame(payload["instances"])
+                predictions = predictor.predict(df)
+            except Exception as e:
+                self._respond(500, {"error": str(e)})
+                return
+
+            self._respond(200, {"predictions": predictions}, default=str)
+
+        def _respond(self, status: int, body: dict, default=None):
+            data = json.dumps(body, default=default).encode("utf-8")
+            self.send_response(status)
+            self.send_header("Content-Type", "application/json")
+            self.send_header("Content-Length", str(len(data)))
+            self.end_headers()
+            self.wfile.write(data)
+
+        def log_message(self, format, *args):
+            # request payloads are logged by the standard transformer
+            pass
+
+    return ModelHandler
+
+
+def main(model_uri, port):
+    print(f"loading model from: {model_uri}")
+    predictor = ModelPredictor(model_uri)
+
+    server_ref = []
+    server = ThreadingHTTPServer(("", port),
+                                 create_handler(predictor, server_ref))
+    server_ref.append(server)
+
+    print(f"serving model on port {port}")
+    server.serve_forever()
+    server.server_close()
+    print("model server is shut down")
+
+
+if __name__ == '__main__':
+    parser = argparse.ArgumentParser(
+        description='Serve the model artifact for the standard transformer '
+                    'of a prediction job')
+    parser.add_argument('--model-uri', type=str, required=True,
+                        dest="model_uri", help="URI of the model artifact")
+    parser.add_argument('--port', type=int, required=False, dest="port",
+                        default=8080, help="Port to serve the model on")
+
+    args = parser.parse_args()
+    print(f"Called with arguments: {args}")
+
+    main(args.model_uri, args.port)
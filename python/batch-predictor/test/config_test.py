@@ -17,6 +17,8 @@ from pyspark.sql.types import ArrayType, DoubleType, FloatType, IntegerType, \
     LongType, StringType
 
 from merlinpyspark.config import BigQuerySinkConfig, BigQuerySourceConfig, load
+from merlinpyspark.spec.prediction_job_pb2 import BigQuerySource, \
+    IncrementalSource
 from test.util.test_utils import write_config
 
 sample_1_yaml = """
@@ -167,3 +169,18 @@ def test_model_result_conversion(return_type, item_type, expected):
     assert cfg.model().result_type() == expected
 
 
+
+
+def test_bq_source_watermark_filter():
+    src_cfg = BigQuerySourceConfig(BigQuerySource(
+        table="project.dataset.table",
+        incremental=IncrementalSource(
+            watermark_column="event_timestamp",
+            start_watermark="2023-03-01T02:00:00Z",
+            end_watermark="2023-03-02T02:00:00Z")))
+
+    assert src_cfg.watermark_filter() == \
+           "`event_timestamp` > TIMESTAMP('2023-03-01T02:00:00Z') AND " \
+           "`event_timestamp` <= TIMESTAMP('2023-03-02T02:00:00Z')"
+    assert BigQuerySourceConfig(
+        BigQuerySource(table="project.dataset.table")).watermark_filter() is None
@@ -0,0 +1,102 @@
+# Copyright 2020 The Merlin Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import os
+
+import pandas as pd
+
+from merlinpyspark.lightweight import GcsSink, Predictor, predict_batches, \
+    read_batches, run
+from merlinpyspark.spec.prediction_job_pb2 import FileFormat, GcsSink as \
+    GcsSinkProto, GcsSource, PredictionJob, SaveMode, SinkPartition
+
+
+class SumPredictor(Predictor):
+    def predict(self, df: pd.DataFrame):
+        return list(df.sum(axis=1))
+
+
+def test_read_batches(tmp_path):
+    path = os.path.join(tmp_path, "input.csv")
+    pd.DataFrame({"a": range(5), "b": range(5), "c": range(5)}) \
+        .to_csv(path, index=False)
+
+    source = GcsSource(format=FileFormat.CSV, uri=path, features=["a", "b"])
+    batches = list(read_batches(source, 2))
+
+    assert [len(b) for b in batches] == [2, 2, 1]
+    assert list(batches[0].columns) == ["a", "b"]
+
+
+def test_predict_batches_preserves_order():
+    batches = [pd.DataFrame({"a": [i], "b": [i]}) for i in range(10)]
+
+    results = list(predict_batches(SumPredictor(), batches, "prediction", 3))
+
+    assert [r["prediction"][0] for r in results] == [2 * i for i in range(10)]
+
+
+def test_run(tmp_path):
+    input_path = os.path.join(tmp_path, "input.csv")
+    output_path = os.path.join(tmp_path, "output")
+    pd.DataFrame({"a": range(5), "b": range(5)}).to_csv(input_path,
+                                                        index=False)
+
+    job_spec = PredictionJob(
+        gcs_source=GcsSource(format=FileFormat.CSV, uri=input_path),
+        gcs_sink=GcsSinkProto(format=FileFormat.CSV, uri=output_path,
+                              result_column="prediction",
+                              save_mode=SaveMode.OVERWRITE))
+
+    rows = run(job_spec, SumPredictor(), concurrency=2, batch_size=2)
+
+    assert rows == 5
+    parts = sorted(os.listdir(output_path))
+    assert parts == ["part-00000.csv", "part-00001.csv", "part-00002.csv"]
+    result = pd.concat(
+        [pd.read_csv(os.path.join(output_path, p)) for p in parts])
+    assert list(result["prediction"]) == [0, 2, 4, 6, 8]
+
+
+def test_gcs_sink_overwrite(tmp_path):
+    output_path = os.path.join(tmp_path, "output")
+    os.makedirs(output_path)
+    with open(os.path.join(output_path, "stale.csv"), "w") as f:
+        f.write("a\n1\n")
+
+    GcsSink(GcsSinkProto(format=FileFormat.CSV, uri=output_path,
+                         save_mode=SaveMode.OVERWRITE))
+
+    assert os.listdir(output_path) == []
+
+
+def test_gcs_sink_overwrite_partition(tmp_path):
+    output_path = os.path.join(tmp_path, "output")
+    for partition in ["prediction_date=2023-03-01", "prediction_date=2023-03-02"]:
+        os.makedirs(os.path.join(output_path, partition))
+        with open(os.path.join(output_path, partition, "stale.csv"), "w") as f:
+            f.write("a\n1\n")
+
+    sink = GcsSink(GcsSinkProto(format=FileFormat.CSV, uri=output_path,
+                                partition=SinkPartition(
+                                    column="prediction_date",
+                                    value="2023-03-02")))
+    sink.write(pd.DataFrame({"a": [1, 2]}), 0)
+
+    assert os.listdir(
+        os.path.join(output_path, "prediction_date=2023-03-01")) == [
+               "stale.csv"]
+    assert os.listdir(
+        os.path.join(output_path, "prediction_date=2023-03-02")) == [
+               "part-00000.csv"]
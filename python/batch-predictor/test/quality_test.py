@@ -0,0 +1,109 @@
+# Copyright 2020 The Merlin Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+
+import pytest
+
+from merlinpyspark.quality import compute_quality_metrics, \
+    evaluate_quality_checks, population_stability_index, should_fail, \
+    write_quality_metrics
+
+
+@pytest.mark.ci
+def test_compute_quality_metrics(spark_session):
+    rows = [(i, float(i)) for i in range(10)] + [(10, None),
+                                                  (11, float("nan"))]
+    df = spark_session.createDataFrame(rows, ["id", "prediction"])
+
+    metrics = compute_quality_metrics(13, df, "prediction")
+
+    assert metrics["source_rows"] == 13
+    assert metrics["output_rows"] == 12
+    assert metrics["null_count"] == 2
+    assert metrics["min"] == 0.0
+    assert metrics["max"] == 9.0
+    assert len(metrics["quantiles"]) == 11
+    assert metrics["quantiles"][0] == 0.0
+    assert metrics["quantiles"][-1] == 9.0
+
+
+@pytest.mark.ci
+def test_compute_quality_metrics_non_numeric(spark_session):
+    df = spark_session.createDataFrame([(1, "a"), (2, None), (3, None)],
+                                       ["id", "prediction"])
+
+    metrics = compute_quality_metrics(3, df, "prediction")
+
+    assert metrics == {"source_rows": 3, "output_rows": 3, "null_count": 2}
+
+
+def test_write_quality_metrics(tmp_path):
+    path = str(tmp_path / "termination-log")
+    write_quality_metrics({"source_rows": 1, "output_rows": 1}, path)
+
+    with open(path) as f:
+        assert json.load(f) == {"source_rows": 1, "output_rows": 1}
+
+
+def deciles(low, high):
+    return [low + (high - low) * i / 10 for i in range(11)]
+
+
+@pytest.mark.parametrize("checks,metrics,expected", [
+    (
+        {"max_null_fraction": 0.1, "min_value": 0, "max_value": 1,
+         "drift": {"reference_job_id": 1, "max_psi": 0.2,
+                   "reference_quantiles": deciles(0, 1)}},
+        {"source_rows": 100, "output_rows": 100, "null_count": 1,
+         "min": 0.0, "max": 1.0, "quantiles": deciles(0, 1)},
+        [],
+    ),
+    (
+        {"max_null_fraction": 0.1, "min_value": 0, "max_value": 1,
+         "drift": {"reference_job_id": 1, "max_psi": 0.2,
+                   "reference_quantiles": deciles(0, 1)}},
+        {"source_rows": 100, "output_rows": 100, "null_count": 100},
+        ["null_fraction: 1.0 (threshold 0.1)",
+         "min_value: result column has no numeric value",
+         "max_value: result column has no numeric value",
+         "drift: result column has no numeric value"],
+    ),
+    (
+        # the row ratio is only evaluated once the rows are written, and the
+        # drift only if the reference job had metrics on submission
+        {"min_row_ratio": 0.99, "max_value": 1,
+         "drift": {"reference_job_id": 1, "max_psi": 0.2}},
+        {"source_rows": 100, "output_rows": 50, "null_count": 0,
+         "min": 2.0, "max": 2.0, "quantiles": deciles(2, 2)},
+        ["max_value: 2.0 (threshold 1)"],
+    ),
+])
+def test_evaluate_quality_checks(checks, metrics, expected):
+    assert evaluate_quality_checks(checks, metrics) == expected
+
+
+def test_population_stability_index():
+    assert population_stability_index(deciles(0, 1), deciles(0, 1)) == \
+        pytest.approx(0)
+    assert population_stability_index(deciles(0, 1),
+                                      deciles(0.02, 1.02)) < 0.1
+    assert population_stability_index(deciles(0, 1),
+                                      deciles(0.5, 1.5)) > 0.2
+
+
+def test_should_fail():
+    assert should_fail({})
+    assert should_fail({"action": "fail"})
+    assert not should_fail({"action": "warn"})